@@ -11,6 +11,9 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/shipwright-io/cli/pkg/shp/cmd"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/plugin"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/params"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
@@ -18,6 +21,12 @@ import (
 // ApplicationName application name.
 const ApplicationName = "shp"
 
+// verbosityUsage usage of the klog verbosity flag, exposed as -v/--v, describing what each level
+// logs on stderr.
+const verbosityUsage = "log verbosity on stderr: 2 logs the state transitions of the pod watcher and log tail, " +
+	"4 the API calls retried or throttled, 6 each API call with its response code and latency, " +
+	"8 the request and response headers"
+
 var hiddenLogFlags = []string{
 	"add_dir_header",
 	"alsologtostderr",
@@ -30,7 +39,6 @@ var hiddenLogFlags = []string{
 	"skip_headers",
 	"skip_log_headers",
 	"stderrthreshold",
-	"v",
 	"vmodule",
 }
 
@@ -43,9 +51,21 @@ func main() {
 
 	streams := genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
 	rootCmd := cmd.NewCmdSHP(&streams)
+	if !plugin.IsBuiltin(rootCmd, os.Args[1:]) {
+		handler := plugin.NewDefaultHandler([]string{plugin.Prefix})
+		found, err := plugin.HandleCommand(handler, os.Args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(exitcode.Failure)
+		}
+		if found {
+			os.Exit(exitcode.Success)
+		}
+	}
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
-		os.Exit(1)
+		format, _ := rootCmd.PersistentFlags().GetString(params.ErrorFormatFlag)
+		exitcode.Print(os.Stderr, err, format)
+		os.Exit(exitcode.FromError(err))
 	}
 }
 
@@ -76,4 +96,7 @@ func initPFlags() {
 			panic(err)
 		}
 	}
+	if v := flags.Lookup("v"); v != nil {
+		v.Usage = verbosityUsage
+	}
 }
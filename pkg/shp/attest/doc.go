@@ -0,0 +1,4 @@
+// Package attest generates supply-chain attestations, like in-toto statements carrying SLSA
+// provenance, out of completed BuildRuns, and signs them, as well as the images produced, with
+// cosign.
+package attest
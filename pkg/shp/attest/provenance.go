@@ -0,0 +1,221 @@
+package attest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+const (
+	// ProvenanceType attestation type name for SLSA provenance, as informed on the command-line.
+	ProvenanceType = "provenance"
+
+	// StatementType in-toto statement type.
+	StatementType = "https://in-toto.io/Statement/v0.1"
+	// ProvenancePredicateType SLSA provenance predicate type.
+	ProvenancePredicateType = "https://slsa.dev/provenance/v0.2"
+	// BuildType identifies the BuildRun as the build recipe.
+	BuildType = "https://shipwright.io/BuildRun@v1alpha1"
+	// BuilderID identifies the Shipwright build controller as the builder.
+	BuilderID = "https://shipwright.io/build-controller"
+)
+
+// Statement in-toto statement, binding the predicate to the subjects it describes.
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Subject  `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Provenance `json:"predicate"`
+}
+
+// Subject software artifact described by the statement.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance SLSA provenance predicate.
+type Provenance struct {
+	Builder     Builder     `json:"builder"`
+	BuildType   string      `json:"buildType"`
+	Invocation  Invocation  `json:"invocation"`
+	Metadata    *Metadata   `json:"metadata,omitempty"`
+	Materials   []Material  `json:"materials,omitempty"`
+	BuildConfig interface{} `json:"buildConfig,omitempty"`
+}
+
+// Builder entity which executed the build.
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// Invocation describes the event which kicked off the build.
+type Invocation struct {
+	ConfigSource ConfigSource      `json:"configSource"`
+	Parameters   map[string]string `json:"parameters,omitempty"`
+	Environment  map[string]string `json:"environment,omitempty"`
+}
+
+// ConfigSource describes where the build configuration came from.
+type ConfigSource struct {
+	URI        string            `json:"uri,omitempty"`
+	Digest     map[string]string `json:"digest,omitempty"`
+	EntryPoint string            `json:"entryPoint,omitempty"`
+}
+
+// Metadata additional details about the build.
+type Metadata struct {
+	BuildInvocationID string `json:"buildInvocationId,omitempty"`
+	BuildStartedOn    string `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn   string `json:"buildFinishedOn,omitempty"`
+	Reproducible      bool   `json:"reproducible"`
+}
+
+// Material artifact which influenced the build.
+type Material struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// splitDigest splits a "algorithm:hex" digest into a map, as expected by in-toto.
+func splitDigest(digest string) (map[string]string, error) {
+	algorithm, hex, found := strings.Cut(digest, ":")
+	if !found || algorithm == "" || hex == "" {
+		return nil, fmt.Errorf("digest %q is not in algorithm:hex format", digest)
+	}
+	return map[string]string{algorithm: hex}, nil
+}
+
+// OutputImage returns the output image name for the BuildRun, taking into account the BuildRun's
+// own output override.
+func OutputImage(br *buildv1alpha1.BuildRun) string {
+	if br.Spec.Output != nil && br.Spec.Output.Image != "" {
+		return br.Spec.Output.Image
+	}
+	if br.Status.BuildSpec != nil {
+		return br.Status.BuildSpec.Output.Image
+	}
+	return ""
+}
+
+// ImageDigestReference returns the fully qualified "registry/repository@digest" reference for the
+// image produced by the informed BuildRun.
+func ImageDigestReference(br *buildv1alpha1.BuildRun) (name.Digest, error) {
+	if br.Status.Output == nil || br.Status.Output.Digest == "" {
+		return name.Digest{}, fmt.Errorf("BuildRun %q does not report the output image digest", br.Name)
+	}
+	image := OutputImage(br)
+	if image == "" {
+		return name.Digest{}, fmt.Errorf("BuildRun %q does not report the output image", br.Name)
+	}
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	return ref.Context().Digest(br.Status.Output.Digest), nil
+}
+
+// NewProvenance creates the in-toto statement carrying the SLSA provenance of the image produced by
+// the informed BuildRun, which must be successfully completed.
+func NewProvenance(br *buildv1alpha1.BuildRun) (*Statement, error) {
+	if !br.IsSuccessful() {
+		return nil, fmt.Errorf("BuildRun %q has not completed successfully", br.Name)
+	}
+	if br.Status.BuildSpec == nil {
+		return nil, fmt.Errorf("BuildRun %q does not report the Build specification", br.Name)
+	}
+	ref, err := ImageDigestReference(br)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := splitDigest(ref.DigestStr())
+	if err != nil {
+		return nil, err
+	}
+
+	spec := br.Status.BuildSpec
+	provenance := Provenance{
+		Builder:   Builder{ID: BuilderID},
+		BuildType: BuildType,
+		Invocation: Invocation{
+			Parameters: map[string]string{
+				"strategy": spec.Strategy.Name,
+			},
+			Environment: map[string]string{
+				"namespace": br.Namespace,
+				"buildrun":  br.Name,
+				"build":     br.Spec.BuildName(),
+			},
+		},
+		Metadata: &Metadata{
+			BuildInvocationID: string(br.UID),
+		},
+		BuildConfig: spec,
+	}
+
+	if spec.Strategy.Kind != nil {
+		provenance.Invocation.Parameters["strategyKind"] = string(*spec.Strategy.Kind)
+	}
+	if spec.Dockerfile != nil && *spec.Dockerfile != "" {
+		provenance.Invocation.Parameters["dockerfile"] = *spec.Dockerfile
+	}
+	for _, paramValues := range [][]buildv1alpha1.ParamValue{spec.ParamValues, br.Spec.ParamValues} {
+		for _, p := range paramValues {
+			if p.SingleValue != nil && p.SingleValue.Value != nil {
+				provenance.Invocation.Parameters[fmt.Sprintf("param.%s", p.Name)] = *p.SingleValue.Value
+			}
+		}
+	}
+
+	if br.Status.StartTime != nil {
+		provenance.Metadata.BuildStartedOn = br.Status.StartTime.UTC().Format(time.RFC3339)
+	}
+	if br.Status.CompletionTime != nil {
+		provenance.Metadata.BuildFinishedOn = br.Status.CompletionTime.UTC().Format(time.RFC3339)
+	}
+
+	// recording the source repository, or source bundle image, as the build configuration source and
+	// material, using the resolved revision reported on the BuildRun status
+	if spec.Source.ContextDir != nil {
+		provenance.Invocation.ConfigSource.EntryPoint = *spec.Source.ContextDir
+	}
+	for _, source := range br.Status.Sources {
+		switch {
+		case source.Git != nil && spec.Source.URL != nil:
+			material := Material{
+				URI:    fmt.Sprintf("git+%s", *spec.Source.URL),
+				Digest: map[string]string{"sha1": source.Git.CommitSha},
+			}
+			if source.Git.BranchName != "" {
+				material.URI = fmt.Sprintf("%s@refs/heads/%s", material.URI, source.Git.BranchName)
+			}
+			provenance.Materials = append(provenance.Materials, material)
+		case source.Bundle != nil && spec.Source.BundleContainer != nil:
+			bundleDigest, err := splitDigest(source.Bundle.Digest)
+			if err != nil {
+				return nil, err
+			}
+			provenance.Materials = append(provenance.Materials, Material{
+				URI:    spec.Source.BundleContainer.Image,
+				Digest: bundleDigest,
+			})
+		}
+	}
+	if len(provenance.Materials) > 0 {
+		provenance.Invocation.ConfigSource.URI = provenance.Materials[0].URI
+		provenance.Invocation.ConfigSource.Digest = provenance.Materials[0].Digest
+	}
+
+	return &Statement{
+		Type: StatementType,
+		Subject: []Subject{{
+			Name:   ref.Context().Name(),
+			Digest: digest,
+		}},
+		PredicateType: ProvenancePredicateType,
+		Predicate:     provenance,
+	}, nil
+}
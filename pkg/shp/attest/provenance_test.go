@@ -0,0 +1,95 @@
+package attest
+
+import (
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+const digest = "sha256:7d1a6ba3d2a6da6c9a5ddd8b5d6f5f8e4a4e9d3c2b1a0f9e8d7c6b5a4f3e2d1c"
+
+func succeededBuildRun() *buildv1alpha1.BuildRun {
+	return &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sample-abcde",
+			Namespace: "default",
+		},
+		Spec: buildv1alpha1.BuildRunSpec{
+			BuildRef: &buildv1alpha1.BuildRef{Name: "sample"},
+		},
+		Status: buildv1alpha1.BuildRunStatus{
+			Conditions: buildv1alpha1.Conditions{{
+				Type:   buildv1alpha1.Succeeded,
+				Status: corev1.ConditionTrue,
+			}},
+			BuildSpec: &buildv1alpha1.BuildSpec{
+				Source: buildv1alpha1.Source{
+					URL:        pointer.String("https://github.com/shipwright-io/sample-go"),
+					ContextDir: pointer.String("source-build"),
+				},
+				Strategy: buildv1alpha1.Strategy{Name: "buildpacks-v3"},
+				Output:   buildv1alpha1.Image{Image: "registry.example.com/org/sample:latest"},
+			},
+			Sources: []buildv1alpha1.SourceResult{{
+				Name: "default",
+				Git:  &buildv1alpha1.GitSourceResult{CommitSha: "a1b2c3", BranchName: "main"},
+			}},
+			Output: &buildv1alpha1.Output{Digest: digest},
+		},
+	}
+}
+
+func TestImageDigestReference(t *testing.T) {
+	g := o.NewWithT(t)
+
+	br := succeededBuildRun()
+	ref, err := ImageDigestReference(br)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(ref.String()).To(o.Equal("registry.example.com/org/sample@" + digest))
+
+	// BuildRun output override takes precedence over the Build's
+	br.Spec.Output = &buildv1alpha1.Image{Image: "registry.example.com/other/image"}
+	ref, err = ImageDigestReference(br)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(ref.String()).To(o.Equal("registry.example.com/other/image@" + digest))
+
+	br.Status.Output = nil
+	_, err = ImageDigestReference(br)
+	g.Expect(err).NotTo(o.BeNil())
+}
+
+func TestNewProvenance(t *testing.T) {
+	g := o.NewWithT(t)
+
+	statement, err := NewProvenance(succeededBuildRun())
+	g.Expect(err).To(o.BeNil())
+	g.Expect(statement.Type).To(o.Equal(StatementType))
+	g.Expect(statement.PredicateType).To(o.Equal(ProvenancePredicateType))
+	g.Expect(statement.Subject).To(o.Equal([]Subject{{
+		Name:   "registry.example.com/org/sample",
+		Digest: map[string]string{"sha256": digest[len("sha256:"):]},
+	}}))
+
+	predicate := statement.Predicate
+	g.Expect(predicate.Invocation.Parameters).To(o.HaveKeyWithValue("strategy", "buildpacks-v3"))
+	g.Expect(predicate.Invocation.Environment).To(o.HaveKeyWithValue("build", "sample"))
+	g.Expect(predicate.Invocation.ConfigSource.EntryPoint).To(o.Equal("source-build"))
+	g.Expect(predicate.Materials).To(o.Equal([]Material{{
+		URI:    "git+https://github.com/shipwright-io/sample-go@refs/heads/main",
+		Digest: map[string]string{"sha1": "a1b2c3"},
+	}}))
+}
+
+func TestNewProvenanceNotSucceeded(t *testing.T) {
+	g := o.NewWithT(t)
+
+	br := succeededBuildRun()
+	br.Status.Conditions[0].Status = corev1.ConditionFalse
+	_, err := NewProvenance(br)
+	g.Expect(err).NotTo(o.BeNil())
+}
@@ -0,0 +1,138 @@
+package attest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// CosignBinary name of the cosign executable looked up on $PATH.
+const CosignBinary = "cosign"
+
+// runCosign runs cosign, replaceable for testing purposes.
+var runCosign = defaultRunCosign
+
+// defaultRunCosign runs cosign with the informed arguments, wired to the informed streams.
+func defaultRunCosign(ctx context.Context, ioStreams *genericclioptions.IOStreams, args ...string) error {
+	cosign, err := exec.LookPath(CosignBinary)
+	if err != nil {
+		return fmt.Errorf("unable to find %q on PATH, it is required to sign and verify images: %w", CosignBinary, err)
+	}
+	// #nosec G204 the arguments are assembled by the callers, not a shell
+	cmd := exec.CommandContext(ctx, cosign, args...)
+	cmd.Stdin = ioStreams.In
+	cmd.Stdout = ioStreams.Out
+	cmd.Stderr = ioStreams.ErrOut
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("cosign %s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+// Attach signs the statement predicate and attaches it to the subject image using "cosign attest".
+// When key is empty, cosign keyless signing is employed.
+func Attach(ctx context.Context, ioStreams *genericclioptions.IOStreams, statement *Statement, key string) error {
+	if len(statement.Subject) == 0 {
+		return fmt.Errorf("attestation statement does not have a subject")
+	}
+
+	// cosign takes the predicate alone, and wraps it on a new statement for the informed image
+	predicate, err := os.CreateTemp("", "shp-predicate-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(predicate.Name())
+	if err = json.NewEncoder(predicate).Encode(statement.Predicate); err != nil {
+		predicate.Close()
+		return err
+	}
+	if err = predicate.Close(); err != nil {
+		return err
+	}
+
+	subject := statement.Subject[0]
+	image := subject.Name
+	for algorithm, hex := range subject.Digest {
+		image = fmt.Sprintf("%s@%s:%s", subject.Name, algorithm, hex)
+	}
+
+	args := []string{"attest", "--yes", "--type", "slsaprovenance", "--predicate", predicate.Name()}
+	if key != "" {
+		args = append(args, "--key", key)
+	}
+	args = append(args, image)
+
+	fmt.Fprintf(ioStreams.Out, "Signing and attaching provenance to %q ...\n", image)
+	return runCosign(ctx, ioStreams, args...)
+}
+
+// Sign signs the image digest and pushes the signature to the image repository using "cosign sign".
+// When key is empty, cosign keyless signing is employed.
+func Sign(ctx context.Context, ioStreams *genericclioptions.IOStreams, image name.Digest, key string) error {
+	args := []string{"sign", "--yes"}
+	if key != "" {
+		args = append(args, "--key", key)
+	}
+	args = append(args, image.String())
+
+	fmt.Fprintf(ioStreams.Out, "Signing image %q ...\n", image.String())
+	return runCosign(ctx, ioStreams, args...)
+}
+
+// VerifyOptions describes the trusted signers of an image, either the public key, or the identity
+// and OIDC issuer of the keyless signing certificate.
+type VerifyOptions struct {
+	Key                  string // public key reference
+	CertIdentity         string // certificate identity, e.g. the signer email
+	CertIdentityRegexp   string // regular expression matching the certificate identity
+	CertOIDCIssuer       string // OIDC issuer of the certificate identity
+	CertOIDCIssuerRegexp string // regular expression matching the certificate OIDC issuer
+}
+
+// Validate checks either the public key or the keyless certificate identity is informed.
+func (o *VerifyOptions) Validate() error {
+	keyless := o.CertIdentity != "" || o.CertIdentityRegexp != "" || o.CertOIDCIssuer != "" || o.CertOIDCIssuerRegexp != ""
+	switch {
+	case o.Key != "" && keyless:
+		return fmt.Errorf("the public key and the certificate identity are mutually exclusive")
+	case o.Key != "":
+		return nil
+	case o.CertIdentity == "" && o.CertIdentityRegexp == "":
+		return fmt.Errorf("either the public key or the certificate identity must be informed")
+	case o.CertOIDCIssuer == "" && o.CertOIDCIssuerRegexp == "":
+		return fmt.Errorf("the certificate OIDC issuer must be informed along with the certificate identity")
+	}
+	return nil
+}
+
+// Verify checks the image signatures against the trusted signers using "cosign verify", the
+// verified signatures payload is printed on the output.
+func Verify(ctx context.Context, ioStreams *genericclioptions.IOStreams, image string, opts VerifyOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	args := []string{"verify"}
+	for _, flag := range []struct{ name, value string }{
+		{"--key", opts.Key},
+		{"--certificate-identity", opts.CertIdentity},
+		{"--certificate-identity-regexp", opts.CertIdentityRegexp},
+		{"--certificate-oidc-issuer", opts.CertOIDCIssuer},
+		{"--certificate-oidc-issuer-regexp", opts.CertOIDCIssuerRegexp},
+	} {
+		if flag.value != "" {
+			args = append(args, flag.name, flag.value)
+		}
+	}
+	args = append(args, image)
+
+	if err := runCosign(ctx, ioStreams, args...); err != nil {
+		return fmt.Errorf("signature verification of image %q failed: %w", image, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package attest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	o "github.com/onsi/gomega"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	g := o.NewWithT(t)
+
+	var args []string
+	runCosign = func(_ context.Context, _ *genericclioptions.IOStreams, a ...string) error {
+		args = a
+		return nil
+	}
+	defer func() {
+		runCosign = defaultRunCosign
+	}()
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+	image, err := name.NewDigest("registry.example.com/org/sample@" + digest)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(Sign(context.TODO(), &ioStreams, image, "")).To(o.Succeed())
+	g.Expect(args).To(o.Equal([]string{"sign", "--yes", "registry.example.com/org/sample@" + digest}))
+	g.Expect(out.String()).To(o.ContainSubstring(`Signing image "registry.example.com/org/sample@` + digest))
+	g.Expect(Sign(context.TODO(), &ioStreams, image, "cosign.key")).To(o.Succeed())
+	g.Expect(args).To(o.Equal([]string{"sign", "--yes", "--key", "cosign.key", "registry.example.com/org/sample@" + digest}))
+
+	g.Expect(Verify(context.TODO(), &ioStreams, "registry.example.com/org/sample:latest", VerifyOptions{
+		CertIdentity:   "ci@example.com",
+		CertOIDCIssuer: "https://accounts.google.com",
+	})).To(o.Succeed())
+	g.Expect(args).To(o.Equal([]string{
+		"verify",
+		"--certificate-identity", "ci@example.com",
+		"--certificate-oidc-issuer", "https://accounts.google.com",
+		"registry.example.com/org/sample:latest",
+	}))
+
+	runCosign = func(_ context.Context, _ *genericclioptions.IOStreams, a ...string) error {
+		return context.DeadlineExceeded
+	}
+	err = Verify(context.TODO(), &ioStreams, "registry.example.com/org/sample:latest", VerifyOptions{Key: "cosign.pub"})
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`signature verification of image "registry.example.com/org/sample:latest" failed`)))
+}
+
+func TestVerifyOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts VerifyOptions
+		err  string
+	}{
+		{name: "key", opts: VerifyOptions{Key: "cosign.pub"}},
+		{name: "keyless", opts: VerifyOptions{CertIdentityRegexp: ".*@example.com", CertOIDCIssuer: "https://token.actions.githubusercontent.com"}},
+		{name: "nothing", err: "either the public key or the certificate identity must be informed"},
+		{name: "both", opts: VerifyOptions{Key: "cosign.pub", CertIdentity: "ci@example.com"},
+			err: "the public key and the certificate identity are mutually exclusive"},
+		{name: "identity without issuer", opts: VerifyOptions{CertIdentity: "ci@example.com"},
+			err: "the certificate OIDC issuer must be informed along with the certificate identity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			err := tt.opts.Validate()
+			if tt.err == "" {
+				g.Expect(err).To(o.BeNil())
+				return
+			}
+			g.Expect(err).To(o.MatchError(tt.err))
+		})
+	}
+}
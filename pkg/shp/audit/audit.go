@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	authenticationv1beta1 "k8s.io/api/authentication/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Annotations recorded on the BuildRuns triggered from the CLI.
+const (
+	TriggeredByAnnotation = "cli.shipwright.io/triggered-by"
+	HostAnnotation        = "cli.shipwright.io/host"
+	VersionAnnotation     = "cli.shipwright.io/version"
+)
+
+// EventReason reason of the Events recorded on the BuildRuns triggered from the CLI.
+const EventReason = "TriggeredFromCLI"
+
+// component the source of the Events recorded.
+const component = "shp"
+
+// unknown placeholder of the user or host which could not be found.
+const unknown = "unknown"
+
+// Recorder records who triggered the BuildRuns, from which host and CLI version.
+type Recorder struct {
+	User    string
+	Host    string
+	Version string
+
+	clientset kubernetes.Interface // creates the Events
+}
+
+// NewRecorder identifies the user by asking the API server, which tells the actual identity behind
+// the credentials, the fallback user is employed when not supported, i.e. the kubeconfig user.
+func NewRecorder(ctx context.Context, clientset kubernetes.Interface, fallbackUser, version string) *Recorder {
+	r := &Recorder{User: fallbackUser, Version: version, clientset: clientset}
+	review, err := clientset.AuthenticationV1beta1().SelfSubjectReviews().Create(ctx, &authenticationv1beta1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err == nil && review.Status.UserInfo.Username != "" {
+		r.User = review.Status.UserInfo.Username
+	}
+	if r.User == "" {
+		r.User = unknown
+	}
+	if r.Host, err = os.Hostname(); err != nil || r.Host == "" {
+		r.Host = unknown
+	}
+	return r
+}
+
+// Annotate records the user, host and version on the object annotations.
+func (r *Recorder) Annotate(meta *metav1.ObjectMeta) {
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[TriggeredByAnnotation] = r.User
+	meta.Annotations[HostAnnotation] = r.Host
+	meta.Annotations[VersionAnnotation] = r.Version
+}
+
+// Emit records a Normal Event on the BuildRun, shown by "kubectl describe" and "kubectl events".
+func (r *Recorder) Emit(ctx context.Context, br *buildv1alpha1.BuildRun) error {
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: br.GetName() + ".",
+			Namespace:    br.GetNamespace(),
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: buildv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "BuildRun",
+			Namespace:  br.GetNamespace(),
+			Name:       br.GetName(),
+			UID:        br.GetUID(),
+		},
+		Reason:              EventReason,
+		Message:             r.Message(),
+		Type:                corev1.EventTypeNormal,
+		Source:              corev1.EventSource{Component: component, Host: r.Host},
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+		ReportingController: component,
+		ReportingInstance:   r.Host,
+	}
+	_, err := r.clientset.CoreV1().Events(br.GetNamespace()).Create(ctx, event, metav1.CreateOptions{})
+	return err
+}
+
+// Message describes who triggered the BuildRun, as shown on the Event.
+func (r *Recorder) Message() string {
+	return fmt.Sprintf("Triggered by %q from host %q using shp %s", r.User, r.Host, r.Version)
+}
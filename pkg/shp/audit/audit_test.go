@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	authenticationv1beta1 "k8s.io/api/authentication/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestNewRecorder(t *testing.T) {
+	tests := []struct {
+		name         string
+		reviewUser   string
+		reviewErr    error
+		fallbackUser string
+		expectedUser string
+	}{{
+		name:         "user from the self subject review",
+		reviewUser:   "system:serviceaccount:ci:deployer",
+		fallbackUser: "kubeconfig-user",
+		expectedUser: "system:serviceaccount:ci:deployer",
+	}, {
+		name:         "review not supported falls back to the kubeconfig user",
+		reviewErr:    errors.New("the server could not find the requested resource"),
+		fallbackUser: "kubeconfig-user",
+		expectedUser: "kubeconfig-user",
+	}, {
+		name:         "user not found",
+		reviewErr:    errors.New("the server could not find the requested resource"),
+		expectedUser: "unknown",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			clientset := fake.NewSimpleClientset()
+			clientset.PrependReactor("create", "selfsubjectreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+				if tt.reviewErr != nil {
+					return true, nil, tt.reviewErr
+				}
+				review := &authenticationv1beta1.SelfSubjectReview{}
+				review.Status.UserInfo.Username = tt.reviewUser
+				return true, review, nil
+			})
+
+			r := NewRecorder(context.TODO(), clientset, tt.fallbackUser, "v0.13.0")
+			g.Expect(r.User).To(o.Equal(tt.expectedUser))
+			g.Expect(r.Host).ToNot(o.BeEmpty())
+			g.Expect(r.Version).To(o.Equal("v0.13.0"))
+		})
+	}
+}
+
+func TestRecorderAnnotateAndEmit(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+
+	clientset := fake.NewSimpleClientset()
+	r := &Recorder{User: "jane", Host: "laptop", Version: "v0.13.0", clientset: clientset}
+
+	br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{
+		Name:        "my-app-run",
+		Namespace:   ns,
+		UID:         types.UID("uid"),
+		Annotations: map[string]string{"team": "platform"},
+	}}
+	r.Annotate(&br.ObjectMeta)
+	g.Expect(br.Annotations).To(o.Equal(map[string]string{
+		"team":                "platform",
+		TriggeredByAnnotation: "jane",
+		HostAnnotation:        "laptop",
+		VersionAnnotation:     "v0.13.0",
+	}))
+
+	g.Expect(r.Emit(context.TODO(), br)).To(o.Succeed())
+	events, err := clientset.CoreV1().Events(ns).List(context.TODO(), metav1.ListOptions{})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(events.Items).To(o.HaveLen(1))
+	event := events.Items[0]
+	g.Expect(event.Type).To(o.Equal(corev1.EventTypeNormal))
+	g.Expect(event.Reason).To(o.Equal(EventReason))
+	g.Expect(event.Message).To(o.Equal(`Triggered by "jane" from host "laptop" using shp v0.13.0`))
+	g.Expect(event.InvolvedObject.Kind).To(o.Equal("BuildRun"))
+	g.Expect(event.InvolvedObject.Name).To(o.Equal("my-app-run"))
+	g.Expect(event.InvolvedObject.UID).To(o.Equal(types.UID("uid")))
+	g.Expect(event.Source.Component).To(o.Equal("shp"))
+}
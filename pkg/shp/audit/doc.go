@@ -0,0 +1,4 @@
+// Package audit records who triggered the BuildRuns from the CLI, from which host and CLI version,
+// as annotations on the BuildRun and as a Kubernetes Event, enabling the audit of manual build
+// triggers in shared clusters.
+package audit
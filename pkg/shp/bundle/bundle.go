@@ -3,18 +3,27 @@ package bundle
 import (
 	"context"
 	"fmt"
+	goio "io"
+	"time"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/google/go-containerregistry/pkg/authn"
+	ggcrcompression "github.com/google/go-containerregistry/pkg/compression"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
-	progressbar "github.com/schollz/progressbar/v3"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	buildbundle "github.com/shipwright-io/build/pkg/bundle"
 	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+	"github.com/shipwright-io/cli/pkg/shp/util"
 )
 
 // GetSourceBundleImage returns the source bundle image of the build that is
@@ -43,9 +52,18 @@ func GetSourceBundleImage(ctx context.Context, client buildclientset.Interface,
 
 // Push bundles the provided local directory into a container image and pushes
 // it to the given registry. For this to work, it relies on valid and working
-// container registry access credentials and tokens to be available in the
-// local system, for example logins done by `docker login` or similar.
-func Push(ctx context.Context, io *genericclioptions.IOStreams, localDirectory string, targetImage string) (name.Digest, error) {
+// container registry access credentials provided by the keychain, when nil,
+// the credentials available in the local system are used, for example logins
+// done by `docker login` or similar.
+func Push(
+	ctx context.Context,
+	io *genericclioptions.IOStreams,
+	localDirectory string,
+	targetImage string,
+	keychain authn.Keychain,
+	compression streamer.Compression,
+	level int,
+) (name.Digest, error) {
 	tag, err := name.NewTag(targetImage)
 	if err != nil {
 		return name.Digest{}, err
@@ -55,67 +73,87 @@ func Push(ctx context.Context, io *genericclioptions.IOStreams, localDirectory s
 	// checks it against the available login credentials in the system. The
 	// needs to have done a `docker login` or similar to the respective
 	// registry before using the Shipwright CLI.
-	auth, err := authn.DefaultKeychain.Resolve(tag.Context())
+	if keychain == nil {
+		keychain = authn.DefaultKeychain
+	}
+	auth, err := keychain.Resolve(tag.Context())
 	if err != nil {
 		return name.Digest{}, err
 	}
 
 	updates := make(chan v1.Update, 1)
-	done := make(chan struct{}, 1)
+	done := make(chan bool, 1) // tells whether the push succeeded
 	go func() {
-		var progress *progressbar.ProgressBar
+		progress := util.NewProgress(io.ErrOut, "Uploading local source...", 0)
 		for {
 			select {
 			case <-ctx.Done():
 				return
 
-			case <-done:
+			case succeeded := <-done:
+				if succeeded {
+					progress.Finish()
+				}
 				return
 
 			case update, ok := <-updates:
 				if !ok {
 					return
 				}
-
-				if progress == nil {
-					progress = progressbar.NewOptions(int(update.Total),
-						progressbar.OptionSetWriter(io.ErrOut),
-						progressbar.OptionEnableColorCodes(true),
-						progressbar.OptionShowBytes(true),
-						progressbar.OptionSetWidth(15),
-						progressbar.OptionSetPredictTime(false),
-						progressbar.OptionSetDescription("Uploading local source..."),
-						progressbar.OptionSetTheme(progressbar.Theme{
-							Saucer:        "[green]=[reset]",
-							SaucerHead:    "[green]>[reset]",
-							SaucerPadding: " ",
-							BarStart:      "[",
-							BarEnd:        "]"}),
-						progressbar.OptionOnCompletion(func() {
-							fmt.Fprintln(io.Out)
-						}),
-					)
-					defer progress.Close()
-				}
-
-				if update.Total != progress.GetMax64() {
-					progress.ChangeMax64(update.Total)
-				}
-
-				_ = progress.Set64(update.Complete)
+				progress.Set(update.Complete, update.Total)
 			}
 		}
 	}()
 
 	fmt.Fprintf(io.Out, "Bundling %q as %q ...\n", localDirectory, targetImage)
-	digest, err := buildbundle.PackAndPush(
+	digest, err := packAndPush(
 		tag,
 		localDirectory,
+		layerOptions(compression, level),
 		remote.WithContext(ctx),
 		remote.WithAuth(auth),
 		remote.WithProgress(updates),
 	)
 
-	done <- struct{}{}
+	done <- err == nil
 	return digest, err
 }
+
+// layerOptions returns the options to compress the bundle layer with the informed algorithm, gzip
+// is employed by default.
+func layerOptions(c streamer.Compression, level int) []tarball.LayerOption {
+	opts := []tarball.LayerOption{}
+	if c == streamer.CompressionZstd {
+		opts = append(opts, tarball.WithCompression(ggcrcompression.ZStd), tarball.WithMediaType(types.OCILayerZStd))
+	}
+	if level != 0 {
+		opts = append(opts, tarball.WithCompressionLevel(level))
+	}
+	return opts
+}
+
+// packAndPush bundles the local directory into a single layer image, like the upstream
+// bundle.PackAndPush, compressing the layer with the informed options.
+func packAndPush(ref name.Reference, directory string, layerOpts []tarball.LayerOption, options ...remote.Option) (name.Digest, error) {
+	layer, err := tarball.LayerFromOpener(func() (goio.ReadCloser, error) {
+		return buildbundle.Pack(directory)
+	}, layerOpts...)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	image, err := mutate.Time(empty.Image, time.Unix(0, 0))
+	if err != nil {
+		return name.Digest{}, err
+	}
+	if image, err = mutate.AppendLayers(image, layer); err != nil {
+		return name.Digest{}, err
+	}
+	hash, err := image.Digest()
+	if err != nil {
+		return name.Digest{}, err
+	}
+	if err = remote.Write(ref, image, options...); err != nil {
+		return name.Digest{}, err
+	}
+	return name.NewDigest(fmt.Sprintf("%s@%v", ref.Name(), hash.String()))
+}
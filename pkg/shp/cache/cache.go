@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// DefaultResync interval in which the handlers are informed of all the cached objects again, even
+// when not modified, thus views relying on the updates are refreshed periodically.
+const DefaultResync = 30 * time.Second
+
+// syncPollInterval interval between the checks of the informer being synced, the client-go helper
+// checks every 100ms which is noticeable on short lived commands.
+const syncPollInterval = 10 * time.Millisecond
+
+// BuildRunFn receives the BuildRun whenever it is added, modified or resynced, nil when deleted.
+type BuildRunFn func(br *buildv1alpha1.BuildRun)
+
+// Cache holds the BuildRun informers by namespace, started on demand and kept running until the
+// context is done.
+type Cache struct {
+	lock sync.Mutex
+
+	ctx       context.Context                           // lifetime of the informers
+	client    buildclientset.Interface                  // shipwright api-client
+	resync    time.Duration                             // interval between resyncs
+	buildRuns map[string]toolscache.SharedIndexInformer // BuildRun informers by namespace
+}
+
+// NewCache instantiate a Cache whose informers run until the context is done.
+func NewCache(ctx context.Context, client buildclientset.Interface, resync time.Duration) *Cache {
+	return &Cache{
+		ctx:       ctx,
+		client:    client,
+		resync:    resync,
+		buildRuns: map[string]toolscache.SharedIndexInformer{},
+	}
+}
+
+// BuildRunInformer returns the informer of the BuildRuns in the namespace, started on the first call.
+func (c *Cache) BuildRunInformer(ns string) toolscache.SharedIndexInformer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if informer, ok := c.buildRuns[ns]; ok {
+		return informer
+	}
+	client := c.client.ShipwrightV1alpha1().BuildRuns(ns)
+	informer := toolscache.NewSharedIndexInformer(
+		&toolscache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return client.List(c.ctx, opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return client.Watch(c.ctx, opts)
+			},
+		},
+		&buildv1alpha1.BuildRun{},
+		c.resync,
+		toolscache.Indexers{},
+	)
+	c.buildRuns[ns] = informer
+	go informer.Run(c.ctx.Done())
+	return informer
+}
+
+// synced waits for the informer to list the objects, or the context to be done.
+func synced(ctx context.Context, informer toolscache.SharedIndexInformer) error {
+	return wait.PollUntilContextCancel(ctx, syncPollInterval, true, func(context.Context) (bool, error) {
+		return informer.HasSynced(), nil
+	})
+}
+
+// GetBuildRun returns the cached BuildRun, once the informer is synced. The object returned is
+// shared, and must not be modified.
+func (c *Cache) GetBuildRun(ctx context.Context, ns, name string) (*buildv1alpha1.BuildRun, error) {
+	informer := c.BuildRunInformer(ns)
+	if err := synced(ctx, informer); err != nil {
+		return nil, err
+	}
+	obj, exists, err := informer.GetIndexer().GetByKey(ns + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, kerrors.NewNotFound(buildv1alpha1.Resource("buildruns"), name)
+	}
+	return obj.(*buildv1alpha1.BuildRun), nil
+}
+
+// WatchBuildRun calls the function on every update of the named BuildRun, including the periodic
+// resyncs. The returned function stops the updates.
+func (c *Cache) WatchBuildRun(ns, name string, fn BuildRunFn) (func(), error) {
+	informer := c.BuildRunInformer(ns)
+	matches := func(obj interface{}) (*buildv1alpha1.BuildRun, bool) {
+		if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		br, ok := obj.(*buildv1alpha1.BuildRun)
+		return br, ok && br.GetName() == name
+	}
+	registration, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if br, ok := matches(obj); ok {
+				fn(br)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if br, ok := matches(obj); ok {
+				fn(br)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if _, ok := matches(obj); ok {
+				fn(nil)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = informer.RemoveEventHandler(registration)
+	}, nil
+}
+
+// WaitForBuildRun blocks until the named BuildRun meets the condition, reacting on the informer
+// updates rather than polling the API server. When the context is done the last BuildRun observed
+// is returned along with the context error.
+func (c *Cache) WaitForBuildRun(
+	ctx context.Context,
+	ns, name string,
+	condition func(br *buildv1alpha1.BuildRun) bool,
+) (*buildv1alpha1.BuildRun, error) {
+	updated := make(chan struct{}, 1)
+	stop, err := c.WatchBuildRun(ns, name, func(*buildv1alpha1.BuildRun) {
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	var last *buildv1alpha1.BuildRun
+	for {
+		br, err := c.GetBuildRun(ctx, ns, name)
+		if err != nil {
+			return last, err
+		}
+		if last = br; condition(br) {
+			return br, nil
+		}
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-updated:
+		}
+	}
+}
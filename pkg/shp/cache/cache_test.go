@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCache(t *testing.T) {
+	g := o.NewWithT(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "my-app-1"}}
+	clientset := shpfake.NewSimpleClientset(br)
+	c := NewCache(ctx, clientset, time.Hour)
+
+	cached, err := c.GetBuildRun(ctx, "dev", "my-app-1")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(cached.GetName()).To(o.Equal("my-app-1"))
+
+	_, err = c.GetBuildRun(ctx, "dev", "missing")
+	g.Expect(kerrors.IsNotFound(err)).To(o.BeTrue())
+
+	// the informer is shared, the BuildRuns are listed and watched once per namespace
+	g.Expect(c.BuildRunInformer("dev")).To(o.BeIdenticalTo(c.BuildRunInformer("dev")))
+	_, err = c.GetBuildRun(ctx, "dev", "my-app-1")
+	g.Expect(err).To(o.BeNil())
+	verbs := map[string]int{}
+	for _, action := range clientset.Actions() {
+		verbs[action.GetVerb()]++
+	}
+	g.Expect(verbs).To(o.Equal(map[string]int{"list": 1, "watch": 1}))
+
+	t.Run("waits for the condition on the updates", func(_ *testing.T) {
+		done := make(chan *buildv1alpha1.BuildRun)
+		go func() {
+			br, err := c.WaitForBuildRun(ctx, "dev", "my-app-1", func(br *buildv1alpha1.BuildRun) bool {
+				return br.IsDone()
+			})
+			g.Expect(err).To(o.BeNil())
+			done <- br
+		}()
+		g.Consistently(done, 100*time.Millisecond).ShouldNot(o.Receive())
+
+		updated := br.DeepCopy()
+		updated.Status.Conditions = buildv1alpha1.Conditions{{Type: buildv1alpha1.Succeeded, Status: corev1.ConditionTrue}}
+		g.Expect(clientset.Tracker().Update(buildv1alpha1.SchemeGroupVersion.WithResource("buildruns"), updated, "dev")).To(o.Succeed())
+
+		var completed *buildv1alpha1.BuildRun
+		g.Eventually(done, 5*time.Second).Should(o.Receive(&completed))
+		g.Expect(completed.IsSuccessful()).To(o.BeTrue())
+	})
+
+	t.Run("reports the last BuildRun observed on timeout", func(_ *testing.T) {
+		g.Expect(clientset.Tracker().Add(&buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "my-app-2"}})).To(o.Succeed())
+
+		g.Eventually(func() error {
+			_, err := c.GetBuildRun(ctx, "dev", "my-app-2")
+			return err
+		}, 5*time.Second).Should(o.Succeed())
+
+		waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer waitCancel()
+		br, err := c.WaitForBuildRun(waitCtx, "dev", "my-app-2", func(*buildv1alpha1.BuildRun) bool { return false })
+		g.Expect(err).To(o.MatchError(context.DeadlineExceeded))
+		g.Expect(br.GetName()).To(o.Equal("my-app-2"))
+	})
+
+	t.Run("informs the deletion", func(_ *testing.T) {
+		deleted := make(chan bool, 1)
+		stop, err := c.WatchBuildRun("dev", "my-app-2", func(br *buildv1alpha1.BuildRun) {
+			if br == nil {
+				deleted <- true
+			}
+		})
+		g.Expect(err).To(o.BeNil())
+		defer stop()
+
+		g.Expect(clientset.Tracker().Delete(buildv1alpha1.SchemeGroupVersion.WithResource("buildruns"), "dev", "my-app-2")).To(o.Succeed())
+		g.Eventually(deleted, 5*time.Second).Should(o.Receive())
+	})
+}
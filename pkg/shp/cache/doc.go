@@ -0,0 +1,3 @@
+// Package cache shares informers across the commands, the objects are listed and watched once per
+// namespace, and read from memory afterwards instead of issuing a request on every check.
+package cache
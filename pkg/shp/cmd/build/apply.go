@@ -0,0 +1,189 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/manifest"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// defaultFieldManager the field manager owning the fields applied, unless informed otherwise.
+const defaultFieldManager = "shp"
+
+// Outcomes of applying an object.
+const (
+	applyCreated    = "created"
+	applyConfigured = "configured"
+	applyUnchanged  = "unchanged"
+)
+
+// ApplyCommand contains data input from user for the apply sub-command
+type ApplyCommand struct {
+	cmd *cobra.Command
+
+	filenames      []string // files, directories or URLs of the manifests, or "-" for stdin
+	fieldManager   string   // name of the manager owning the fields applied
+	forceConflicts bool     // take ownership of the fields managed by others
+}
+
+const buildApplyLongDesc = `
+Applies Build and BuildRun manifests with server-side apply, creating the objects which don't exist
+yet and updating the fields owned by the field manager on the existing ones. The manifests are
+read from files, directories, URLs, or the standard input with "-". For example:
+
+	$ shp build apply -f build.yaml
+	$ shp build apply -f ./manifests/ -f https://example.com/buildrun.yaml
+	$ cat build.yaml | shp build apply -f -
+
+Each object is reported as created, configured, or unchanged. When the fields applied are managed
+by another field manager, i.e. "kubectl", the apply fails unless --force-conflicts is informed.
+`
+
+func applyCmd() runner.SubCommand {
+	applyCommand := &ApplyCommand{
+		cmd: &cobra.Command{
+			Use:   "apply",
+			Short: "Apply Build and BuildRun manifests with server-side apply",
+			Long:  buildApplyLongDesc,
+			Args:  cobra.NoArgs,
+		},
+	}
+
+	flags.FilenamesFlags(applyCommand.cmd.Flags(), &applyCommand.filenames)
+	applyCommand.cmd.Flags().StringVar(&applyCommand.fieldManager, "field-manager", defaultFieldManager, "name of the manager owning the fields applied")
+	applyCommand.cmd.Flags().BoolVar(&applyCommand.forceConflicts, "force-conflicts", false, "take ownership of the fields managed by other field managers")
+	return applyCommand
+}
+
+// Cmd returns cobra command object of the apply subcommand
+func (c *ApplyCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills ApplyCommand structure with data obtained from cobra command
+func (c *ApplyCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	return nil
+}
+
+// Validate is used for validation of user input data
+func (c *ApplyCommand) Validate() error {
+	if len(c.filenames) == 0 {
+		return fmt.Errorf("flag --filename is required")
+	}
+	if err := manifest.ValidateFilenames(c.filenames); err != nil {
+		return err
+	}
+	if c.fieldManager == "" {
+		return fmt.Errorf("--field-manager must not be empty")
+	}
+	return nil
+}
+
+// Run reads all manifests before applying them, thus an invalid manifest doesn't leave the objects
+// partially applied.
+func (c *ApplyCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	ctx := c.cmd.Context()
+	objects, err := manifest.ReadAll(ctx, ioStreams.In, c.filenames)
+	if err != nil {
+		return err
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf("no objects found")
+	}
+	for _, obj := range objects {
+		if err := prepareManifest(obj, params.Namespace()); err != nil {
+			return err
+		}
+	}
+
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		outcome, err := c.apply(ctx, clientset, obj)
+		if err != nil {
+			if kerrors.IsConflict(err) {
+				return fmt.Errorf("%s %q: %w, use --force-conflicts to take ownership of the fields", obj.GetKind(), obj.GetName(), err)
+			}
+			return fmt.Errorf("%s %q: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		if params.Quiet() {
+			fmt.Fprintln(ioStreams.Out, obj.GetName())
+			continue
+		}
+		fmt.Fprintf(ioStreams.Out, "%s %q %s\n", obj.GetKind(), obj.GetName(), outcome)
+	}
+	return nil
+}
+
+// apply applies the object with server-side apply, the resource version before and after applying
+// tells whether the object has been created, modified or left unchanged.
+func (c *ApplyCommand) apply(ctx context.Context, clientset buildclientset.Interface, obj *unstructured.Unstructured) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	opts := metav1.PatchOptions{FieldManager: c.fieldManager, Force: &c.forceConflicts}
+
+	var existing, applied metav1.Object
+	switch obj.GetKind() {
+	case "Build":
+		client := clientset.ShipwrightV1alpha1().Builds(obj.GetNamespace())
+		if existing, err = client.Get(ctx, obj.GetName(), metav1.GetOptions{}); kerrors.IsNotFound(err) {
+			existing, err = nil, nil
+		}
+		if err == nil {
+			applied, err = client.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+		}
+	default:
+		client := clientset.ShipwrightV1alpha1().BuildRuns(obj.GetNamespace())
+		if existing, err = client.Get(ctx, obj.GetName(), metav1.GetOptions{}); kerrors.IsNotFound(err) {
+			existing, err = nil, nil
+		}
+		if err == nil {
+			applied, err = client.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+		}
+	}
+	switch {
+	case err != nil:
+		return "", err
+	case existing == nil:
+		return applyCreated, nil
+	case existing.GetResourceVersion() == applied.GetResourceVersion():
+		return applyUnchanged, nil
+	default:
+		return applyConfigured, nil
+	}
+}
+
+// prepareManifest places the object in the namespace, and drops the fields maintained by the API
+// server, which must not be part of the applied configuration.
+func prepareManifest(obj *unstructured.Unstructured, namespace string) error {
+	if obj.GetName() == "" {
+		return fmt.Errorf("%s without name, server-side apply requires the object name", obj.GetKind())
+	}
+	if err := manifest.SetNamespace(obj, namespace); err != nil {
+		return err
+	}
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetGeneration(0)
+	obj.SetManagedFields(nil)
+	unstructured.RemoveNestedField(obj.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(obj.Object, "status")
+	return nil
+}
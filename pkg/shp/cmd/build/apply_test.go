@@ -0,0 +1,203 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	fakekubetesting "k8s.io/client-go/testing"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+const buildManifest = `apiVersion: shipwright.io/v1alpha1
+kind: Build
+metadata:
+  name: my-app
+spec:
+  source:
+    url: https://github.com/org/my-app
+  strategy:
+    name: buildah
+  output:
+    image: %s
+`
+
+const buildRunManifest = `apiVersion: shipwright.io/v1alpha1
+kind: BuildRun
+metadata:
+  name: my-app-1
+spec:
+  buildRef:
+    name: my-app
+`
+
+// withServerSideApply emulates server-side apply on the fake clientset, which only patches existing
+// objects, the objects are replaced and their resource version bumped when modified.
+func withServerSideApply(clientset *shpfake.Clientset, conflicts bool) {
+	clientset.PrependReactor("patch", "*", func(action fakekubetesting.Action) (bool, runtime.Object, error) {
+		patch := action.(fakekubetesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		gvr := action.GetResource()
+		if conflicts {
+			return true, nil, kerrors.NewConflict(gvr.GroupResource(), patch.GetName(), fmt.Errorf("field managed by kubectl"))
+		}
+
+		var obj runtime.Object = &buildv1alpha1.Build{}
+		if gvr.Resource == "buildruns" {
+			obj = &buildv1alpha1.BuildRun{}
+		}
+		if err := yaml.Unmarshal(patch.GetPatch(), obj); err != nil {
+			return true, nil, err
+		}
+		applied := obj.(interface {
+			GetResourceVersion() string
+			SetResourceVersion(string)
+		})
+
+		tracker := clientset.Tracker()
+		existing, err := tracker.Get(gvr, patch.GetNamespace(), patch.GetName())
+		if kerrors.IsNotFound(err) {
+			applied.SetResourceVersion("1")
+			return true, obj, tracker.Create(gvr, obj, patch.GetNamespace())
+		}
+		if err != nil {
+			return true, nil, err
+		}
+		if specOf(existing) == specOf(obj) {
+			return true, existing, nil
+		}
+		version, _ := strconv.Atoi(existing.(interface{ GetResourceVersion() string }).GetResourceVersion())
+		applied.SetResourceVersion(strconv.Itoa(version + 1))
+		return true, obj, tracker.Update(gvr, obj, patch.GetNamespace())
+	})
+}
+
+// specOf renders the object spec for comparison.
+func specOf(obj runtime.Object) string {
+	switch o := obj.(type) {
+	case *buildv1alpha1.Build:
+		return fmt.Sprintf("%v", o.Spec)
+	case *buildv1alpha1.BuildRun:
+		return fmt.Sprintf("%v", o.Spec.BuildRef)
+	}
+	return ""
+}
+
+func TestApplyBuild(t *testing.T) {
+	g := o.NewWithT(t)
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "build.yaml"), []byte(fmt.Sprintf(buildManifest, "ghcr.io/org/my-app:v1")), 0o600)).To(o.Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0o600)).To(o.Succeed())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/buildrun.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, buildRunManifest)
+	}))
+	defer server.Close()
+
+	shpclientset := shpfake.NewSimpleClientset()
+	withServerSideApply(shpclientset, false)
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, "dev", nil, nil)
+
+	run := func(stdin string, args ...string) (string, error) {
+		cmd := applyCmd().(*ApplyCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		if err := cmd.Validate(); err != nil {
+			return "", err
+		}
+		ioStreams, in, out, _ := genericclioptions.NewTestIOStreams()
+		in.WriteString(stdin)
+		err := cmd.Run(p, &ioStreams)
+		return out.String(), err
+	}
+
+	t.Run("creates the objects", func(_ *testing.T) {
+		out, err := run("", "-f", dir, "-f", server.URL+"/buildrun.yaml")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).To(o.Equal("Build \"my-app\" created\nBuildRun \"my-app-1\" created\n"))
+
+		b, err := shpclientset.ShipwrightV1alpha1().Builds("dev").Get(context.TODO(), "my-app", metav1.GetOptions{})
+		g.Expect(err).To(o.BeNil())
+		g.Expect(b.Spec.Output.Image).To(o.Equal("ghcr.io/org/my-app:v1"))
+
+		patches := 0
+		for _, action := range shpclientset.Actions() {
+			if patch, ok := action.(fakekubetesting.PatchAction); ok {
+				g.Expect(patch.GetPatchType()).To(o.Equal(types.ApplyPatchType))
+				patches++
+			}
+		}
+		g.Expect(patches).To(o.Equal(2))
+	})
+
+	t.Run("reports unchanged and configured objects", func(_ *testing.T) {
+		manifests := fmt.Sprintf(buildManifest, "ghcr.io/org/my-app:v2") + "---\n" + buildRunManifest
+		out, err := run(manifests, "-f", "-")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).To(o.Equal("Build \"my-app\" configured\nBuildRun \"my-app-1\" unchanged\n"))
+	})
+
+	t.Run("invalid manifests", func(_ *testing.T) {
+		_, err := run("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n", "-f", "-")
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("unsupported object ConfigMap \"cm\"")))
+
+		_, err = run(strings.Replace(buildRunManifest, "name: my-app-1", "generateName: my-app-", 1), "-f", "-")
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("server-side apply requires the object name")))
+
+		_, err = run(strings.Replace(buildRunManifest, "metadata:\n", "metadata:\n  namespace: prod\n", 1), "-f", "-")
+		g.Expect(err).To(o.MatchError(o.ContainSubstring(`belongs to namespace "prod"`)))
+
+		_, err = run("", "-f", server.URL+"/missing.yaml")
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("404 Not Found")))
+
+		_, err = run("", "-f", "-")
+		g.Expect(err).To(o.MatchError("no objects found"))
+	})
+
+	t.Run("validation", func(_ *testing.T) {
+		_, err := run("")
+		g.Expect(err).To(o.MatchError("flag --filename is required"))
+
+		_, err = run("", "-f", "-", "-f", "-")
+		g.Expect(err).To(o.MatchError("the standard input can only be read once"))
+	})
+
+	t.Run("conflicts", func(_ *testing.T) {
+		conflicting := shpfake.NewSimpleClientset()
+		withServerSideApply(conflicting, true)
+		cmd := applyCmd().(*ApplyCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags([]string{"-f", "-"})).To(o.Succeed())
+		ioStreams, in, _, _ := genericclioptions.NewTestIOStreams()
+		in.WriteString(buildRunManifest)
+
+		err := cmd.Run(params.NewParamsForTest(fake.NewSimpleClientset(), conflicting, nil, "dev", nil, nil), &ioStreams)
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("use --force-conflicts")))
+		g.Expect(kerrors.IsConflict(err)).To(o.BeTrue())
+	})
+}
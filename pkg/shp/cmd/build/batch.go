@@ -0,0 +1,360 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/manifest"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// Status of each Build of the batch, shown on the summary table.
+const (
+	batchSkipped   = "Skipped"
+	batchRunning   = "Running"
+	batchSucceeded = "Succeeded"
+	batchFailed    = "Failed"
+	batchTimedOut  = "TimedOut"
+	batchCanceled  = "Canceled"
+)
+
+// batchRun the BuildRun created for a single Build of the batch, and its outcome.
+type batchRun struct {
+	build     string
+	name      string
+	ioStreams *genericclioptions.IOStreams // streams prefixed by the Build name
+	writers   []*tail.PrefixWriter         // flushed once the BuildRun is done
+	started   time.Time
+	finished  time.Time
+	status    string // summary status, empty while the BuildRun is waited
+	err       error  // outcome, nil when succeeded
+}
+
+// flush writes the partial lines left on the prefixed streams.
+func (b *batchRun) flush() {
+	for _, w := range b.writers {
+		_ = w.Flush()
+	}
+}
+
+// aborted tells whether the BuildRun was created, but waiting for it has been stopped.
+func (b *batchRun) aborted() bool {
+	return b.name != "" && b.status == ""
+}
+
+// validateBatch checks the batch flags are consistent with the others, the flags targeting a
+// single Build can't be used.
+func (r *RunCommand) validateBatch() error {
+	if r.batch.IsEmpty() {
+		if r.cmd.Flags().Changed(flags.ConcurrencyFlag) || r.batch.FailFast {
+			return fmt.Errorf("--%s or --%s must be informed when using the other batch flags", flags.FilenameFlag, flags.SelectorFlag)
+		}
+		return nil
+	}
+	batchFlags := fmt.Sprintf("--%s or --%s", flags.FilenameFlag, flags.SelectorFlag)
+	switch {
+	case len(r.batch.Filenames) > 0 && r.batch.Selector != "":
+		return fmt.Errorf("--%s and --%s are mutually exclusive", flags.FilenameFlag, flags.SelectorFlag)
+	case r.batch.Concurrency < 1:
+		return fmt.Errorf("--%s must be at least 1", flags.ConcurrencyFlag)
+	case r.follow:
+		return fmt.Errorf("--follow can't be used along with %s, the BuildRuns are waited", batchFlags)
+	case !r.multiPlatform.IsEmpty():
+		return fmt.Errorf("--%s can't be used along with %s", flags.PlatformsFlag, batchFlags)
+	case r.naming.Name != "":
+		return fmt.Errorf("--%s can't be used along with %s", flags.BuildRunNameFlag, batchFlags)
+	case r.cmd.Flags().Changed(flags.OutputImageFlag):
+		return fmt.Errorf("--%s can't be used along with %s", flags.OutputImageFlag, batchFlags)
+	case r.usesSourceBundle():
+		return fmt.Errorf("--%s can't be used along with %s", flags.SourceBundleImageFlag, batchFlags)
+	case !r.sourceOverride.IsEmpty():
+		return fmt.Errorf("--%s and --%s can't be used along with %s", flags.SourceRevisionFlag, flags.SourceContextDirFlag, batchFlags)
+	case r.pushCredentialsProvider != "":
+		return fmt.Errorf("--%s can't be used along with %s", flags.PushCredentialsProviderFlag, batchFlags)
+	case r.imageDigestFile != "":
+		return fmt.Errorf("--image-digest-file can't be used along with %s", batchFlags)
+	case len(r.additionalOutputs) > 0:
+		return fmt.Errorf("--%s can't be used along with %s", flags.AdditionalOutputImageFlag, batchFlags)
+	case r.attest != "":
+		return fmt.Errorf("--attest can't be used along with %s", batchFlags)
+	case r.sign:
+		return fmt.Errorf("--sign can't be used along with %s", batchFlags)
+	}
+	return manifest.ValidateFilenames(r.batch.Filenames)
+}
+
+// batchBuilds returns the names of the Builds to run, either selected by labels, ordered by name,
+// or read from the manifests, in order.
+func (r *RunCommand) batchBuilds(
+	ctx context.Context,
+	ioStreams *genericclioptions.IOStreams,
+	clientset buildclientset.Interface,
+) ([]string, error) {
+	names := []string{}
+	if r.batch.Selector != "" {
+		builds, err := clientset.ShipwrightV1alpha1().Builds(r.namespace).List(ctx, metav1.ListOptions{LabelSelector: r.batch.Selector})
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range builds.Items {
+			names = append(names, b.GetName())
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no Builds found matching %q in namespace %q", r.batch.Selector, r.namespace)
+		}
+		return names, nil
+	}
+
+	objects, err := manifest.ReadAll(ctx, ioStreams.In, r.batch.Filenames)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, obj := range objects {
+		if obj.GetKind() != "Build" {
+			return nil, fmt.Errorf("%s %q is not a Build, only Builds can be run", obj.GetKind(), obj.GetName())
+		}
+		if obj.GetName() == "" {
+			return nil, fmt.Errorf("the Builds to run must be informed by name")
+		}
+		if err = manifest.SetNamespace(obj, r.namespace); err != nil {
+			return nil, err
+		}
+		if !seen[obj.GetName()] {
+			seen[obj.GetName()] = true
+			names = append(names, obj.GetName())
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no Builds found on %s", strings.Join(r.batch.Filenames, ", "))
+	}
+	return names, nil
+}
+
+// runBatch creates one BuildRun per Build out of the informed BuildRun, running at most the
+// concurrency informed at once, and waits for them. The outcome is summarized on a table, and the
+// exit code is taken from the first failure.
+func (r *RunCommand) runBatch(
+	params *params.Params,
+	ioStreams *genericclioptions.IOStreams,
+	clientset buildclientset.Interface,
+	base *buildv1alpha1.BuildRun,
+) error {
+	builds, err := r.batchBuilds(r.cmd.Context(), ioStreams, clientset)
+	if err != nil {
+		return err
+	}
+
+	lock := &sync.Mutex{}
+	names := tail.NewPrefixWriter(ioStreams.Out, lock, "")
+	runs := make([]*batchRun, 0, len(builds))
+	for _, build := range builds {
+		prefix := styles.Prefix(fmt.Sprintf("[%s]", build)) + " "
+		out := tail.NewPrefixWriter(ioStreams.Out, lock, prefix)
+		errOut := tail.NewPrefixWriter(ioStreams.ErrOut, lock, prefix)
+		runs = append(runs, &batchRun{
+			build:     build,
+			ioStreams: params.QuietStreams(&genericclioptions.IOStreams{In: ioStreams.In, Out: out, ErrOut: errOut}),
+			writers:   []*tail.PrefixWriter{out, errOut},
+		})
+	}
+
+	ctx, cancel := context.WithCancel(r.cmd.Context())
+	defer cancel()
+	interrupt := r.notifyInterrupt(cancel)
+	defer interrupt.stop()
+
+	jobs := make(chan *batchRun)
+	var wg sync.WaitGroup
+	for i := 0; i < min(r.batch.Concurrency, len(runs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for run := range jobs {
+				// pending Builds are skipped once interrupted, or failing fast
+				if ctx.Err() != nil {
+					continue
+				}
+				r.runBatchBuild(ctx, params, clientset, base, run, names)
+				if run.err != nil && r.batch.FailFast {
+					cancel()
+				}
+			}
+		}()
+	}
+	for _, run := range runs {
+		jobs <- run
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, run := range runs {
+		run.flush()
+	}
+	var interruptErr error
+	for _, run := range runs {
+		switch {
+		case run.name == "":
+			if run.status == "" {
+				run.status = batchSkipped
+			}
+		case !run.aborted():
+		case interrupt.interrupted():
+			run.status = batchCanceled
+			if err := r.handleInterrupt(clientset, ioStreams, run.name); exitcode.FromError(err) != exitcode.Cancelled {
+				run.status = batchRunning
+				if interruptErr == nil {
+					interruptErr = err
+				}
+			}
+		default:
+			// failing fast, the BuildRuns still running are canceled
+			run.status = batchCanceled
+			if err := util.CancelBuildRun(r.cmd.Context(), clientset, r.namespace, run.name); err != nil {
+				fmt.Fprintf(ioStreams.ErrOut, "Warning: unable to cancel BuildRun %q: %v\n", run.name, err)
+				run.status = batchRunning
+			}
+		}
+	}
+
+	if !params.Quiet() {
+		printBatchSummary(ioStreams.Out, runs)
+	}
+	if interrupt.interrupted() {
+		if interruptErr != nil {
+			return interruptErr
+		}
+		return exitcode.Errorf(exitcode.Cancelled, "interrupted, the running BuildRuns have been canceled")
+	}
+	return batchOutcome(runs)
+}
+
+// runBatchBuild creates the BuildRun for a single Build of the batch and waits for it, recording
+// its outcome on the informed run.
+func (r *RunCommand) runBatchBuild(
+	ctx context.Context,
+	params *params.Params,
+	clientset buildclientset.Interface,
+	base *buildv1alpha1.BuildRun,
+	run *batchRun,
+	names io.Writer,
+) {
+	br := base.DeepCopy()
+	br.ObjectMeta = r.naming.ObjectMeta(run.build)
+	br.Spec.BuildRef = &buildv1alpha1.BuildRef{Name: run.build}
+	if base.Spec.BuildRef != nil {
+		br.Spec.BuildRef.APIVersion = base.Spec.BuildRef.APIVersion
+	}
+	var owner *buildv1alpha1.Build
+	if r.metadata.RequiresOwner() {
+		if owner, run.err = clientset.ShipwrightV1alpha1().Builds(r.namespace).Get(ctx, run.build, metav1.GetOptions{}); run.err != nil {
+			run.status = batchFailed
+			return
+		}
+	}
+	r.metadata.Apply(&br.ObjectMeta, owner)
+
+	run.started = time.Now()
+	if br, run.err = createNamedBuildRun(ctx, clientset, r.namespace, br, r.naming, r.recorder, run.ioStreams.ErrOut); run.err != nil {
+		run.status = batchFailed
+		return
+	}
+	run.name = br.GetName()
+	if params.Quiet() {
+		fmt.Fprintln(names, run.name)
+	} else {
+		fmt.Fprintf(run.ioStreams.Out, "BuildRun created %q for build %q\n", run.name, run.build)
+	}
+
+	err := r.waitForBuildRun(ctx, params, clientset, run.ioStreams, run.name)
+	run.finished = time.Now()
+	if err != nil && ctx.Err() != nil {
+		// stopped waiting, the BuildRun is still running
+		return
+	}
+	if err == nil {
+		err = r.completeBuildRun(params, clientset, run.ioStreams, run.name)
+	}
+	run.err = err
+	switch exitcode.FromError(err) {
+	case exitcode.Success:
+		run.status = batchSucceeded
+	case exitcode.Timeout:
+		run.status = batchTimedOut
+	case exitcode.Cancelled:
+		run.status = batchCanceled
+	default:
+		run.status = batchFailed
+	}
+}
+
+// printBatchSummary writes the table with the status of each Build of the batch.
+func printBatchSummary(w io.Writer, runs []*batchRun) {
+	writer := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "\nBUILD\tBUILDRUN\tSTATUS\tDURATION")
+	for _, run := range runs {
+		status := run.status
+		switch status {
+		case batchSucceeded:
+			status = styles.Success(status)
+		case batchSkipped, batchRunning:
+			status = styles.Faint(status)
+		case batchCanceled:
+			status = styles.Warning(status)
+		default:
+			status = styles.Failure(status)
+		}
+		var elapsed string
+		if !run.finished.IsZero() {
+			elapsed = duration.HumanDuration(run.finished.Sub(run.started))
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", styles.Bold(run.build), run.name, status, elapsed)
+	}
+	_ = writer.Flush()
+}
+
+// batchOutcome summarizes the failed Builds, the exit code is taken from the first failure.
+func batchOutcome(runs []*batchRun) error {
+	var first error
+	failures := []string{}
+	skipped := 0
+	for _, run := range runs {
+		if run.status == batchSkipped {
+			skipped++
+		}
+		if run.err == nil {
+			continue
+		}
+		if first == nil {
+			first = run.err
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s", run.build, run.err))
+	}
+	if first == nil {
+		return nil
+	}
+	summary := fmt.Sprintf("%d of %d builds failed", len(failures), len(runs))
+	if skipped > 0 {
+		summary = fmt.Sprintf("%s, %d skipped", summary, skipped)
+	}
+	return exitcode.Wrap(exitcode.FromError(first), fmt.Errorf("%s, %s", summary, strings.Join(failures, "; ")))
+}
@@ -28,6 +28,15 @@ func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Co
 		runner.NewRunner(p, ioStreams, deleteCmd()).Cmd(),
 		runner.NewRunner(p, ioStreams, runCmd()).Cmd(),
 		runner.NewRunner(p, ioStreams, uploadCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, exportCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, importCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, applyCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, editCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, updateCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, validateCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, diffCmd()).Cmd(),
+		triggerCmd(p, ioStreams),
+		webhookCmd(p, ioStreams),
 	)
 	return command
 }
@@ -0,0 +1,56 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/shipwright-io/cli/pkg/shp/audit"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+)
+
+// createNamedBuildRun creates the BuildRun named after the naming flags. When the explicit name is
+// already taken, and the collision strategy allows, the BuildRun is created again using the name as
+// prefix for an unique name. When the recorder is informed, the BuildRun is annotated with who
+// triggered it and an Event is recorded on it, failing to record the Event only warns.
+func createNamedBuildRun(
+	ctx context.Context,
+	clientset buildclientset.Interface,
+	ns string,
+	br *buildv1alpha1.BuildRun,
+	naming *flags.BuildRunNaming,
+	recorder *audit.Recorder,
+	w io.Writer,
+) (*buildv1alpha1.BuildRun, error) {
+	if recorder != nil {
+		recorder.Annotate(&br.ObjectMeta)
+	}
+	created, err := clientset.ShipwrightV1alpha1().BuildRuns(ns).Create(ctx, br, metav1.CreateOptions{})
+	if err != nil && kerrors.IsAlreadyExists(err) {
+		if !naming.GenerateOnCollision() {
+			return nil, fmt.Errorf("BuildRun %q already exists, use --%s=%s to generate an unique name instead",
+				br.GetName(), flags.OnNameCollisionFlag, flags.NameCollisionGenerate)
+		}
+
+		fmt.Fprintf(w, "BuildRun %q already exists, generating an unique name\n", br.GetName())
+		br = br.DeepCopy()
+		br.SetGenerateName(fmt.Sprintf("%s-", br.GetName()))
+		br.SetName("")
+		created, err = clientset.ShipwrightV1alpha1().BuildRuns(ns).Create(ctx, br, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if recorder != nil {
+		if err := recorder.Emit(ctx, created); err != nil {
+			fmt.Fprintf(w, "Warning: unable to record the audit Event on BuildRun %q: %v\n", created.GetName(), err)
+		}
+	}
+	return created, nil
+}
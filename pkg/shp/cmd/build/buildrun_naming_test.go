@@ -0,0 +1,50 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+)
+
+func TestCreateNamedBuildRun(t *testing.T) {
+	ns := metav1.NamespaceDefault
+
+	tests := []struct {
+		name        string
+		onCollision string
+		wantErr     bool
+		wantPrefix  string
+	}{
+		{name: "fail on collision", onCollision: flags.NameCollisionFail, wantErr: true},
+		{name: "generate on collision", onCollision: flags.NameCollisionGenerate, wantPrefix: "release-1-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			clientset := shpfake.NewSimpleClientset(
+				&buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Name: "release-1", Namespace: ns}},
+			)
+			naming := &flags.BuildRunNaming{Name: "release-1", OnCollision: tt.onCollision}
+			br := &buildv1alpha1.BuildRun{ObjectMeta: naming.ObjectMeta("my-app")}
+
+			var out bytes.Buffer
+			created, err := createNamedBuildRun(context.TODO(), clientset, ns, br, naming, nil, &out)
+			if tt.wantErr {
+				g.Expect(err).To(o.MatchError(o.ContainSubstring("already exists")))
+				return
+			}
+			g.Expect(err).To(o.BeNil())
+			// the fake clientset does not generate names, only the prefix is set
+			g.Expect(created.GetGenerateName()).To(o.Equal(tt.wantPrefix))
+			g.Expect(out.String()).To(o.ContainSubstring(`BuildRun "release-1" already exists`))
+		})
+	}
+}
@@ -2,32 +2,133 @@ package build
 
 import (
 	"fmt"
+	"strings"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/spf13/cobra"
 
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
 	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/manifest"
+	"github.com/shipwright-io/cli/pkg/shp/openshift"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+	"github.com/shipwright-io/cli/pkg/shp/suggestion"
+	"github.com/shipwright-io/cli/pkg/shp/util"
 )
 
 // CreateCommand contains data input from user
 type CreateCommand struct {
 	cmd *cobra.Command // cobra command instance
 
-	name      string                   // build resource's name
-	buildSpec *buildv1alpha1.BuildSpec // stores command-line flags
+	name              string                   // build resource's name
+	buildSpec         *buildv1alpha1.BuildSpec // stores command-line flags
+	sourceCredentials *flags.SourceCredentials // git credentials stored on a new secret
+	dockerfileSource  *flags.DockerfileSource  // local Dockerfile stored on a new ConfigMap
+	createNamespace   bool                     // create the namespace when absent
+	imageStream       string                   // OpenShift ImageStream receiving the output image
+	pinSourceImage    bool                     // pin the builder and source bundle images to digests
+	sourceOCIArtifact string                   // existing OCI artifact carrying the source
+	registryAuth      string                   // source of the registry credentials to resolve digests
+	registrySecret    string                   // docker-registry secret name to resolve digests
+	filenames         []string                 // manifests of the Builds, instead of the flags
+	podLabels         map[string]string        // Build labels propagated to the build pods
+	follow            bool                     // run the Build and follow the BuildRun logs
+	ephemeral         bool                     // delete the Build when the followed BuildRun fails
+	fromStrategyName  string                   // strategy whose parameters fill the Build skeleton
+	interactive       bool                     // ask for the strategy parameters, instead of the skeleton
 }
 
 const buildCreateLongDesc = `
 Creates a new Build instance using the first argument as its name. For example:
 
 	$ shp build create my-app --source-url="..." --output-image="..."
+
+The strategy must exist before the Build is created, either a ClusterBuildStrategy or, using
+--strategy-kind=BuildStrategy, a BuildStrategy on the Build's namespace. Similar strategy names
+are suggested when it is not found.
+
+The git credentials can be informed inline, either a SSH private key or a token, stored on a new
+Secret owned by the Build and referenced as its source credentials. The Secret is named after the
+Build, unless --source-credentials-secret is informed:
+
+	$ shp build create my-app --source-url=git@github.com:org/app.git --source-ssh-key ~/.ssh/id_ed25519 --output-image="..."
+	$ shp build create my-app --source-url=https://github.com/org/app --source-credentials-from=token.txt --output-image="..."
+
+When the strategy declares the "dockerfile" parameter, --dockerfile is set on it. A local Dockerfile,
+for repositories without one, is stored on a new ConfigMap owned by the Build and mounted on the
+strategy's overridable "dockerfile" volume (see --dockerfile-volume). Use "-" to read it from the
+standard input:
+
+	$ shp build create my-app --source-url="..." --output-image="..." --dockerfile-file=- <<EOF
+	FROM registry.access.redhat.com/ubi9/ubi-minimal
+	COPY . /app
+	EOF
+
+The strategy parameters are set with --param, arrays are informed in brackets, the items separated
+by commas, or item by item with "+=". Values referencing Secret or ConfigMap keys are informed with
+--params-from-json, as the JSON list of the Build's paramValues:
+
+	$ shp build create my-app --source-url="..." --output-image="..." --param build-args=[A=1,B=2] --param cache=disabled
+	$ shp build create my-app --source-url="..." --output-image="..." --param build-args+=A=1 --param build-args+=B=2
+	$ shp build create my-app --source-url="..." --output-image="..." --params-from-json='[{"name":"token","secretValue":{"name":"creds","key":"token"}}]'
+
+On OpenShift, the output image can be pushed to an ImageStream on the internal registry with
+--output-imagestream, instead of --output-image. The ImageStream is created when absent, and the
+push credentials of the "builder" ServiceAccount are employed, unless --output-credentials-secret is
+informed:
+
+	$ shp build create my-app --source-url="..." --output-imagestream=my-app:latest
+
+For repeatable builds, --pin-source-image resolves the tags of the builder and source bundle images
+to the digests they point to, stored on the Build instead. The registry credentials are selected
+with --registry-auth, the secret source defaults to the respective image credentials:
+
+	$ shp build create my-app --source-bundle-image=ghcr.io/org/app-source:v1 --output-image="..." --pin-source-image
+
+Existing OCI artifacts carrying the source are informed with --source-oci-artifact, instead of a
+git repository. The artifact is checked on the registry before the Build is created, using the
+--source-credentials-secret when informed, or the --registry-auth source otherwise:
+
+	$ shp build create my-app --source-oci-artifact=ghcr.io/org/app/source:v1 --source-credentials-secret=ghcr --output-image="..."
+
+The Builds can be created out of manifests instead, read from files, directories, URLs, or the
+standard input with "-", including multi-document YAML streams. The name argument is omitted then:
+
+	$ shp build create -f build.yaml
+	$ generate-manifests | shp build create -f -
+
+The build controller propagates the Build labels to the pods of all its BuildRuns, thus labels like
+"sidecar.istio.io/inject=false", keeping injected sidecars away from the builds, are informed with
+--pod-label. Pod annotations are only propagated from the build strategy annotations:
+
+	$ shp build create my-app --source-url="..." --output-image="..." --pod-label sidecar.istio.io/inject=false
+
+The parameters of an unfamiliar strategy are discovered with --from-strategy, printing a Build
+manifest with every parameter of the strategy set to its default, the ones without a default are
+marked as required. Once edited, the Build is created with --filename. With --interactive, the
+parameters are asked instead, and the Build is created with the answers, the empty ones keep the
+default:
+
+	$ shp build create my-app --from-strategy=buildah > my-app.yaml
+	$ shp build create my-app --from-strategy=buildah --interactive
+
+With --follow the Build is run right after it is created, following the logs of the BuildRun until
+it completes, the exit code is non-zero when it fails. The Build is deleted when the BuildRun fails
+with --ephemeral, thus it can be fixed and created again under the same name:
+
+	$ shp build create my-app --source-url="..." --output-image="..." --follow --ephemeral
 `
 
+// sourceCredentialsSuffix suffix of the source credentials secret name, created out of the Build name.
+const sourceCredentialsSuffix = "-source-credentials"
+
 // Cmd returns cobra.Command object of the create subcommand.
 func (c *CreateCommand) Cmd() *cobra.Command {
 	return c.cmd
@@ -35,8 +136,11 @@ func (c *CreateCommand) Cmd() *cobra.Command {
 
 // Complete fills internal subcommand structure for future work with user input
 func (c *CreateCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
-	switch len(args) {
-	case 1:
+	switch {
+	case len(c.filenames) > 0 && len(args) > 0:
+		return fmt.Errorf("the name argument can't be informed along with --%s", flags.FilenameFlag)
+	case len(c.filenames) > 0:
+	case len(args) == 1:
 		c.name = args[0]
 	default:
 		return fmt.Errorf("one argument is expected")
@@ -46,43 +150,488 @@ func (c *CreateCommand) Complete(_ *params.Params, _ *genericclioptions.IOStream
 
 // Validate is used for user input validation of flags and other data.
 func (c *CreateCommand) Validate() error {
+	if c.interactive && c.fromStrategyName == "" {
+		return fmt.Errorf("--interactive requires --from-strategy")
+	}
+	if c.fromStrategyName != "" {
+		switch {
+		case len(c.filenames) > 0:
+			return fmt.Errorf("--from-strategy can't be used along with --%s", flags.FilenameFlag)
+		case c.cmd.Flags().Changed(flags.StrategyNameFlag):
+			return fmt.Errorf("--from-strategy and --%s are mutually exclusive", flags.StrategyNameFlag)
+		case c.follow && !c.interactive:
+			return fmt.Errorf("--follow requires --interactive along with --from-strategy, the Build is not created otherwise")
+		case c.interactive && c.dockerfileSource.File == manifest.Stdin:
+			return fmt.Errorf("--interactive reads the answers from the standard input, which can't be used for the Dockerfile")
+		}
+	}
+	if c.ephemeral && !c.follow {
+		return fmt.Errorf("--ephemeral requires --follow")
+	}
+	if len(c.filenames) > 0 {
+		if c.follow {
+			return fmt.Errorf("--follow can't be used along with --%s", flags.FilenameFlag)
+		}
+		return manifest.ValidateFilenames(c.filenames)
+	}
 	if c.name == "" {
 		return fmt.Errorf("name must be provided")
 	}
-	return nil
+	if err := flags.ValidateLabels(c.podLabels); err != nil {
+		return err
+	}
+	if c.imageStream != "" {
+		if _, err := openshift.ParseImageStreamRef(c.imageStream); err != nil {
+			return err
+		}
+	}
+	if c.pinSourceImage {
+		if c.buildSpec.Source.BundleContainer.Image == "" && c.sourceOCIArtifact == "" &&
+			(c.buildSpec.Builder == nil || c.buildSpec.Builder.Image == "") {
+			return fmt.Errorf("--%s requires either --%s, --%s or --%s",
+				flags.PinSourceImageFlag, flags.SourceBundleImageFlag, flags.SourceOCIArtifactFlag, flags.BuilderImageFlag)
+		}
+	}
+	if c.pinSourceImage || c.sourceOCIArtifact != "" {
+		if _, err := registry.ParseAuthSource(c.registryAuth); err != nil {
+			return err
+		}
+	}
+	var sourceURL string
+	if c.buildSpec.Source.URL != nil {
+		sourceURL = *c.buildSpec.Source.URL
+	}
+	return c.sourceCredentials.Validate(sourceURL)
 }
 
 // Run executes the creation of a new Build instance using flags to fill up the details.
 func (c *CreateCommand) Run(params *params.Params, io *genericclioptions.IOStreams) error {
+	if params.Quiet() && c.follow {
+		return fmt.Errorf("--quiet can't be used along with --follow")
+	}
+	if len(c.filenames) > 0 {
+		return c.createFromManifests(params, io)
+	}
+	if c.fromStrategyName != "" {
+		if !c.interactive {
+			return c.printSkeleton(params, io)
+		}
+		if err := c.promptParamValues(params, io); err != nil {
+			return err
+		}
+	}
 	b := &buildv1alpha1.Build{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: c.name,
 		},
 		Spec: *c.buildSpec,
 	}
+	if len(c.podLabels) > 0 {
+		b.Labels = c.podLabels
+	}
+	out := io.Out
+	io = params.QuietStreams(io)
 
+	var secretName string
+	if c.sourceCredentials.Informed() {
+		secretName = b.Spec.Source.Credentials.Name
+		if secretName == "" {
+			secretName = c.name + sourceCredentialsSuffix
+			b.Spec.Source.Credentials.Name = secretName
+		}
+	}
+	if c.imageStream != "" {
+		if err := c.resolveImageStream(params, io, &b.Spec.Output); err != nil {
+			return err
+		}
+	}
+	if c.sourceOCIArtifact != "" {
+		if err := c.checkSourceOCIArtifact(params, io, &b.Spec); err != nil {
+			return err
+		}
+	}
 	flags.SanitizeBuildSpec(&b.Spec)
+	if c.pinSourceImage {
+		if err := c.pinImages(params, io, &b.Spec); err != nil {
+			return err
+		}
+	}
 
-	// print warning with regards to source bundle image being used
-	if b.Spec.Source.BundleContainer != nil && b.Spec.Source.BundleContainer.Image != "" {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	var dockerfile []byte
+	var configMapName string
+	if c.dockerfileSource.Informed() {
+		if dockerfile, err = c.dockerfileSource.Read(io.In); err != nil {
+			return err
+		}
+		configMapName = c.name + dockerfileConfigMapSuffix
+	}
+	strategy, err := c.checkStrategy(params, io, b.Spec.Strategy)
+	if err != nil {
+		return err
+	}
+	if b.Spec.Dockerfile != nil || configMapName != "" {
+		switch {
+		case strategy != nil:
+			if err = applyDockerfile(&b.Spec, strategy, c.dockerfileSource.Volume, configMapName); err != nil {
+				return err
+			}
+		case configMapName != "":
+			return fmt.Errorf("unable to inspect strategy %q", b.Spec.Strategy.Name)
+		}
+	}
+
+	if c.createNamespace {
+		if err = c.ensureNamespace(params, io); err != nil {
+			return err
+		}
+	}
+
+	// print warning with regards to source bundle image being used, the OCI artifacts are staged
+	// by other means
+	if b.Spec.Source.BundleContainer != nil && b.Spec.Source.BundleContainer.Image != "" && c.sourceOCIArtifact == "" {
 		fmt.Fprintf(io.Out, "Build %q uses a source bundle image, which means source code will be transferred to a container registry. It is advised to use private images to ensure the security of the source code being uploaded.\n", c.name)
 	}
 
+	builds := clientset.ShipwrightV1alpha1().Builds(params.Namespace())
+	if b, err = builds.Create(c.cmd.Context(), b, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(io.Out, "Created build %q\n", c.name)
+
+	// the Build would be invalid without its source credentials or Dockerfile
+	deleteBuild := func() {
+		if deleteErr := builds.Delete(c.cmd.Context(), c.name, metav1.DeleteOptions{}); deleteErr != nil {
+			fmt.Fprintf(io.ErrOut, "Warning: unable to delete Build %q: %v\n", c.name, deleteErr)
+		}
+	}
+	if secretName != "" {
+		if err = c.createSourceCredentials(params, b, secretName); err != nil {
+			deleteBuild()
+			return fmt.Errorf("unable to create the source credentials secret %q: %w", secretName, err)
+		}
+		fmt.Fprintf(io.Out, "Created secret %q with the source credentials\n", secretName)
+	}
+	if configMapName != "" {
+		if err = c.createDockerfile(params, b, configMapName, dockerfile); err != nil {
+			deleteBuild()
+			return fmt.Errorf("unable to create the Dockerfile ConfigMap %q: %w", configMapName, err)
+		}
+		fmt.Fprintf(io.Out, "Created configmap %q with the Dockerfile\n", configMapName)
+	}
+	if params.Quiet() {
+		fmt.Fprintln(out, c.name)
+	}
+	if c.follow {
+		return c.followBuild(params, &genericclioptions.IOStreams{In: io.In, Out: out, ErrOut: io.ErrOut})
+	}
+	return nil
+}
+
+// followBuild runs the Build just created following the logs of its BuildRun, the same way as
+// "build run --follow" does. With --ephemeral the Build is deleted when the BuildRun fails.
+func (c *CreateCommand) followBuild(params *params.Params, io *genericclioptions.IOStreams) error {
+	run := runCmd().(*RunCommand)
+	run.cmd.SetContext(c.cmd.Context())
+	run.follow = true
+	if err := run.Complete(params, io, []string{c.name}); err != nil {
+		return err
+	}
+	if err := run.Validate(); err != nil {
+		return err
+	}
+	err := run.Run(params, io)
+	if err == nil && !run.follower.PodSucceeded() {
+		err = exitcode.Errorf(exitcode.Failure, "Build %q has failed", c.name)
+	}
+	if err == nil || !c.ephemeral {
+		return err
+	}
+
+	clientset, clientErr := params.ShipwrightClientSet()
+	if clientErr != nil {
+		return clientErr
+	}
+	if deleteErr := clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Delete(c.cmd.Context(), c.name, metav1.DeleteOptions{}); deleteErr != nil {
+		fmt.Fprintf(io.ErrOut, "Warning: unable to delete Build %q: %v\n", c.name, deleteErr)
+		return err
+	}
+	fmt.Fprintf(io.Out, "Deleted ephemeral build %q\n", c.name)
+	return err
+}
+
+// createFromManifests creates the Builds read from the manifests, all of them are read before
+// creating the first Build, thus an invalid manifest doesn't leave the Builds partially created.
+func (c *CreateCommand) createFromManifests(params *params.Params, io *genericclioptions.IOStreams) error {
+	objects, err := manifest.ReadAll(c.cmd.Context(), io.In, c.filenames)
+	if err != nil {
+		return err
+	}
+	builds := []*buildv1alpha1.Build{}
+	for _, obj := range objects {
+		if err = manifest.SetNamespace(obj, params.Namespace()); err != nil {
+			return err
+		}
+		b := &buildv1alpha1.Build{}
+		if err = manifest.Convert(obj, "Build", b); err != nil {
+			return err
+		}
+		builds = append(builds, b)
+	}
+	if len(builds) == 0 {
+		return fmt.Errorf("no Builds found")
+	}
+
+	out := io.Out
+	io = params.QuietStreams(io)
+	if c.createNamespace {
+		if err = c.ensureNamespace(params, io); err != nil {
+			return err
+		}
+	}
 	clientset, err := params.ShipwrightClientSet()
 	if err != nil {
 		return err
 	}
-	if _, err := clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Create(c.cmd.Context(), b, metav1.CreateOptions{}); err != nil {
+	for _, b := range builds {
+		if _, err = c.checkStrategy(params, io, b.Spec.Strategy); err != nil {
+			return err
+		}
+		created, err := clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Create(c.cmd.Context(), b, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(io.Out, "Created build %q\n", created.GetName())
+		if params.Quiet() {
+			fmt.Fprintln(out, created.GetName())
+		}
+	}
+	return nil
+}
+
+// checkStrategy makes sure the strategy referenced by the Build exists, suggesting similar names
+// when it does not. When the strategy can't be retrieved for other reasons, i.e. lack of
+// permissions, a warning is printed and nil is returned.
+func (c *CreateCommand) checkStrategy(
+	params *params.Params,
+	io *genericclioptions.IOStreams,
+	ref buildv1alpha1.Strategy,
+) (buildv1alpha1.BuilderStrategy, error) {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return nil, err
+	}
+
+	kind := buildv1alpha1.ClusterBuildStrategyKind
+	if ref.Kind != nil {
+		kind = *ref.Kind
+	}
+	strategy, err := util.GetBuildStrategy(c.cmd.Context(), clientset, params.Namespace(), ref)
+	switch {
+	case err == nil:
+		return strategy, nil
+	case !kerrors.IsNotFound(err):
+		fmt.Fprintf(io.ErrOut, "Warning: unable to verify %s %q: %v\n", kind, ref.Name, err)
+		return nil, nil
+	}
+
+	msg := fmt.Sprintf("%s %q not found", kind, ref.Name)
+	if kind == buildv1alpha1.NamespacedBuildStrategyKind {
+		msg = fmt.Sprintf("%s on namespace %q", msg, params.Namespace())
+	}
+	if names, listErr := util.ListBuildStrategyNames(c.cmd.Context(), clientset, params.Namespace(), kind); listErr == nil {
+		if suggestions := suggestion.SuggestionsFor(ref.Name, names); len(suggestions) > 0 {
+			msg += "\n\nDid you mean this?\n"
+			for _, s := range suggestions {
+				msg += fmt.Sprintf("\t%s\n", s)
+			}
+		}
+	}
+
+	// the strategy may exist with the other kind, informing how to select it
+	other := buildv1alpha1.NamespacedBuildStrategyKind
+	if kind == buildv1alpha1.NamespacedBuildStrategyKind {
+		other = buildv1alpha1.ClusterBuildStrategyKind
+	}
+	if _, otherErr := util.GetBuildStrategy(c.cmd.Context(), clientset, params.Namespace(), buildv1alpha1.Strategy{
+		Name: ref.Name,
+		Kind: &other,
+	}); otherErr == nil {
+		msg += fmt.Sprintf("\nA %s named %q exists, use --%s=%s to select it\n", other, ref.Name, flags.StrategyKindFlag, other)
+	}
+	return nil, fmt.Errorf("%s", strings.TrimSuffix(msg, "\n"))
+}
+
+// resolveImageStream points the output image to the ImageStream on the internal registry, and
+// employs the push credentials of the "builder" ServiceAccount when none are informed.
+func (c *CreateCommand) resolveImageStream(
+	params *params.Params,
+	io *genericclioptions.IOStreams,
+	output *buildv1alpha1.Image,
+) error {
+	ref, err := openshift.ParseImageStreamRef(c.imageStream)
+	if err != nil {
 		return err
 	}
-	fmt.Fprintf(io.Out, "Created build %q\n", c.name)
+	dynamicClient, err := params.DynamicClient()
+	if err != nil {
+		return err
+	}
+	image, created, err := openshift.ResolveImageStream(c.cmd.Context(), dynamicClient, params.Namespace(), ref)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Fprintf(io.Out, "Created imagestream %q\n", ref.Name)
+	}
+	output.Image = image
+
+	if output.Credentials != nil && output.Credentials.Name != "" {
+		return nil
+	}
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	secretName, err := openshift.BuilderPushSecret(c.cmd.Context(), clientset, params.Namespace())
+	if err != nil {
+		return fmt.Errorf("unable to find the ImageStream push credentials: %w", err)
+	}
+	if secretName == "" {
+		fmt.Fprintf(io.ErrOut, "Warning: push credentials for ImageStream %q not found, the BuildRun's ServiceAccount must be allowed to push\n", ref.Name)
+		return nil
+	}
+	output.Credentials = &corev1.LocalObjectReference{Name: secretName}
+	return nil
+}
+
+// checkSourceOCIArtifact makes sure the OCI artifact exists before taking it as the Build source,
+// authenticating with the source credentials secret unless --registry-auth is informed.
+func (c *CreateCommand) checkSourceOCIArtifact(params *params.Params, io *genericclioptions.IOStreams, spec *buildv1alpha1.BuildSpec) error {
+	var fallbackSecret string
+	source := c.registryAuth
+	if spec.Source.Credentials != nil && spec.Source.Credentials.Name != "" {
+		fallbackSecret = spec.Source.Credentials.Name
+		if !c.cmd.Flags().Changed(flags.RegistryAuthFlag) {
+			source = string(registry.AuthSecret)
+		}
+	}
+	keychain, err := registryKeychain(c.cmd.Context(), params, source, c.registrySecret, fallbackSecret)
+	if err != nil {
+		return err
+	}
+	ref, err := registry.CheckArtifact(c.cmd.Context(), c.sourceOCIArtifact, keychain)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(io.Out, "Found source OCI artifact %s\n", ref.String())
+
+	if spec.Source.BundleContainer == nil {
+		spec.Source.BundleContainer = &buildv1alpha1.BundleContainer{}
+	}
+	spec.Source.BundleContainer.Image = c.sourceOCIArtifact
+	return nil
+}
+
+// pinImages replaces the tags of the builder and source bundle images by the digests they point to.
+func (c *CreateCommand) pinImages(params *params.Params, io *genericclioptions.IOStreams, spec *buildv1alpha1.BuildSpec) error {
+	pin := func(image *string, credentials *corev1.LocalObjectReference) error {
+		var fallbackSecret string
+		if credentials != nil {
+			fallbackSecret = credentials.Name
+		}
+		keychain, err := registryKeychain(c.cmd.Context(), params, c.registryAuth, c.registrySecret, fallbackSecret)
+		if err != nil {
+			return err
+		}
+		pinned, err := registry.PinImage(c.cmd.Context(), *image, keychain)
+		if err != nil {
+			return err
+		}
+		if pinned != *image {
+			fmt.Fprintf(io.Out, "Pinned image %q to %q\n", *image, pinned)
+			*image = pinned
+		}
+		return nil
+	}
+
+	if spec.Builder != nil && spec.Builder.Image != "" {
+		if err := pin(&spec.Builder.Image, spec.Builder.Credentials); err != nil {
+			return err
+		}
+	}
+	if spec.Source.BundleContainer != nil {
+		if err := pin(&spec.Source.BundleContainer.Image, spec.Source.Credentials); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureNamespace creates the target namespace, when it does not exist yet.
+func (c *CreateCommand) ensureNamespace(params *params.Params, io *genericclioptions.IOStreams) error {
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	created, err := util.EnsureNamespace(c.cmd.Context(), clientset, params.Namespace())
+	if err != nil {
+		return fmt.Errorf("unable to create namespace %q: %w", params.Namespace(), err)
+	}
+	if created {
+		fmt.Fprintf(io.Out, "Created namespace %q\n", params.Namespace())
+	}
 	return nil
 }
 
+// ownerReference the Build as owner, thus the objects are garbage collected along with it.
+func ownerReference(b *buildv1alpha1.Build) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: buildv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Build",
+		Name:       b.GetName(),
+		UID:        b.GetUID(),
+	}
+}
+
+// createSourceCredentials creates the secret with the git credentials, owned by the Build thus
+// garbage collected along with it.
+func (c *CreateCommand) createSourceCredentials(params *params.Params, b *buildv1alpha1.Build, name string) error {
+	secret, err := c.sourceCredentials.Secret(name, params.Namespace(), *b.Spec.Source.URL)
+	if err != nil {
+		return err
+	}
+	secret.OwnerReferences = []metav1.OwnerReference{ownerReference(b)}
+
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	_, err = clientset.CoreV1().Secrets(params.Namespace()).Create(c.cmd.Context(), secret, metav1.CreateOptions{})
+	return err
+}
+
+// createDockerfile creates the ConfigMap with the local Dockerfile, owned by the Build.
+func (c *CreateCommand) createDockerfile(params *params.Params, b *buildv1alpha1.Build, name string, content []byte) error {
+	configMap := c.dockerfileSource.ConfigMap(name, params.Namespace(), content)
+	configMap.OwnerReferences = []metav1.OwnerReference{ownerReference(b)}
+
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	_, err = clientset.CoreV1().ConfigMaps(params.Namespace()).Create(c.cmd.Context(), configMap, metav1.CreateOptions{})
+	return err
+}
+
 // createCmd instantiate the "build create" subcommand.
 func createCmd() runner.SubCommand {
 	cmd := &cobra.Command{
-		Use:   "create <name> [flags]",
+		Use:   "create [<name>] [flags]",
 		Short: "Create Build",
 		Long:  buildCreateLongDesc,
 	}
@@ -90,12 +639,37 @@ func createCmd() runner.SubCommand {
 	// instantiating command-line flags and the build-spec structure which receives the informed flag
 	// values, also marking certain flags as mandatory
 	buildSpecFlags := flags.BuildSpecFromFlags(cmd.Flags())
-	if err := cmd.MarkFlagRequired(flags.OutputImageFlag); err != nil {
-		panic(err)
-	}
+	sourceCredentials := &flags.SourceCredentials{}
+	flags.SourceCredentialsFlags(cmd.Flags(), sourceCredentials)
+
+	dockerfileSource := &flags.DockerfileSource{}
+	flags.DockerfileSourceFlags(cmd.Flags(), dockerfileSource)
 
-	return &CreateCommand{
-		cmd:       cmd,
-		buildSpec: buildSpecFlags,
+	c := &CreateCommand{
+		cmd:               cmd,
+		buildSpec:         buildSpecFlags,
+		sourceCredentials: sourceCredentials,
+		dockerfileSource:  dockerfileSource,
+		podLabels:         map[string]string{},
 	}
+	flags.CreateNamespaceFlags(cmd.Flags(), &c.createNamespace)
+	flags.OutputImageStreamFlags(cmd.Flags(), &c.imageStream)
+	flags.PinSourceImageFlags(cmd.Flags(), &c.pinSourceImage)
+	flags.SourceOCIArtifactFlags(cmd.Flags(), &c.sourceOCIArtifact)
+	flags.RegistryAuthFlags(cmd.Flags(), &c.registryAuth, &c.registrySecret)
+	flags.FilenamesFlags(cmd.Flags(), &c.filenames)
+	flags.PodLabelsFlags(cmd.Flags(), c.podLabels, "Build, on all its BuildRuns")
+	flags.FollowFlag(cmd.Flags(), &c.follow)
+	cmd.Flags().BoolVar(&c.ephemeral, "ephemeral", false, "delete the Build when the BuildRun followed with --follow fails")
+	cmd.Flags().StringVar(&c.fromStrategyName, "from-strategy", "", "print a Build manifest with every parameter of the strategy, instead of creating the Build")
+	cmd.Flags().BoolVar(&c.interactive, "interactive", false, "ask for the parameters of the --from-strategy strategy, and create the Build")
+	cmd.MarkFlagsOneRequired(flags.OutputImageFlag, flags.OutputImageStreamFlag, flags.FilenameFlag, "from-strategy")
+	cmd.MarkFlagsMutuallyExclusive(flags.OutputImageFlag, flags.OutputImageStreamFlag)
+	cmd.MarkFlagsMutuallyExclusive(flags.FilenameFlag, flags.OutputImageFlag)
+	cmd.MarkFlagsMutuallyExclusive(flags.FilenameFlag, flags.OutputImageStreamFlag)
+	cmd.MarkFlagsMutuallyExclusive(flags.FilenameFlag, flags.PodLabelFlag)
+	cmd.MarkFlagsMutuallyExclusive(flags.SourceOCIArtifactFlag, flags.SourceBundleImageFlag)
+	cmd.MarkFlagsMutuallyExclusive(flags.SourceOCIArtifactFlag, flags.SourceURLFlag)
+	cmd.MarkFlagsMutuallyExclusive(flags.SourceOCIArtifactFlag, flags.FilenameFlag)
+	return c
 }
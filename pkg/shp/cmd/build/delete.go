@@ -1,36 +1,68 @@
 package build
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
 	"github.com/spf13/cobra"
 
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
 )
 
 // DeleteCommand contains data provided by user to the delete subcommand
 type DeleteCommand struct {
 	name string
 
-	cmd        *cobra.Command
-	deleteRuns bool
+	cmd         *cobra.Command
+	deleteRuns  bool
+	dryRun      bool
+	wait        bool          // block until the objects are gone
+	waitTimeout time.Duration // maximum amount of time waiting, zero means no limit
 }
 
+const buildDeleteLongDesc = `
+Deletes the Build, and optionally its BuildRuns, found by the Build name label or owner reference.
+Use --dry-run to preview what would be removed. For example:
+
+	$ shp build delete my-app --with-runs --dry-run
+	$ shp build delete my-app --with-runs
+
+The deletion may take a while to complete, i.e. while finalizers are processed. Use --wait to block
+until the Build, and the BuildRuns deleted along, are fully gone, optionally up to --wait-timeout:
+
+	$ shp build delete my-app --with-runs --wait --wait-timeout=2m
+`
+
 func deleteCmd() runner.SubCommand {
 	deleteCommand := &DeleteCommand{
 		cmd: &cobra.Command{
 			Use:   "delete <name> [flags]",
 			Short: "Delete Build",
+			Long:  buildDeleteLongDesc,
 			Args:  cobra.ExactArgs(1),
 		},
 	}
 
-	deleteCommand.cmd.Flags().BoolVarP(&deleteCommand.deleteRuns, "delete-runs", "r", false, "Also delete all of the buildruns")
+	deleteCommand.cmd.Flags().BoolVarP(&deleteCommand.deleteRuns, "with-runs", "r", false, "Also delete all of the buildruns")
+	deleteCommand.cmd.Flags().BoolVar(&deleteCommand.deleteRuns, "delete-runs", false, "Also delete all of the buildruns")
+	if err := deleteCommand.cmd.Flags().MarkDeprecated("delete-runs", "use --with-runs instead"); err != nil {
+		panic(err)
+	}
+	deleteCommand.cmd.Flags().BoolVar(&deleteCommand.dryRun, "dry-run", false, "only print what would be deleted")
+	deleteCommand.cmd.Flags().BoolVar(&deleteCommand.wait, "wait", false, "wait until the deleted objects are fully gone")
+	deleteCommand.cmd.Flags().DurationVar(&deleteCommand.waitTimeout, "wait-timeout", 0, "maximum amount of time to wait for the deletion, zero means no limit")
 
 	return deleteCommand
 }
@@ -49,6 +81,15 @@ func (c *DeleteCommand) Complete(_ *params.Params, _ *genericclioptions.IOStream
 
 // Validate is used for validation of user input data
 func (c *DeleteCommand) Validate() error {
+	if c.wait && c.dryRun {
+		return fmt.Errorf("--wait and --dry-run are mutually exclusive")
+	}
+	if c.waitTimeout < 0 {
+		return fmt.Errorf("--wait-timeout must not be negative")
+	}
+	if c.waitTimeout > 0 && !c.wait {
+		return fmt.Errorf("--wait-timeout requires --wait")
+	}
 	return nil
 }
 
@@ -58,26 +99,151 @@ func (c *DeleteCommand) Run(params *params.Params, io *genericclioptions.IOStrea
 	if err != nil {
 		return err
 	}
-	if err := clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Delete(c.Cmd().Context(), c.name, v1.DeleteOptions{}); err != nil {
+	b, err := clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Get(c.cmd.Context(), c.name, v1.GetOptions{})
+	if err != nil {
 		return err
 	}
 
+	var buildRuns []buildv1alpha1.BuildRun
 	if c.deleteRuns {
 		var brList *buildv1alpha1.BuildRunList
-		if brList, err = clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).List(c.cmd.Context(), v1.ListOptions{
-			LabelSelector: fmt.Sprintf("%v/name=%v", buildv1alpha1.BuildDomain, c.name),
-		}); err != nil {
+		if brList, err = clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).List(c.cmd.Context(), v1.ListOptions{}); err != nil {
 			return err
 		}
-
-		for _, buildrun := range brList.Items {
-			if err := clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Delete(c.cmd.Context(), buildrun.Name, v1.DeleteOptions{}); err != nil {
-				fmt.Fprintf(io.ErrOut, "Error deleting BuildRun %q: %v\n", buildrun.Name, err)
+		for i := range brList.Items {
+			if ownedByBuild(&brList.Items[i], b) {
+				buildRuns = append(buildRuns, brList.Items[i])
 			}
 		}
 	}
 
+	if c.dryRun {
+		fmt.Fprintf(io.Out, "Build %q would be deleted\n", c.name)
+		for _, br := range buildRuns {
+			fmt.Fprintf(io.Out, "BuildRun %q would be deleted\n", br.Name)
+		}
+		return nil
+	}
+
+	if err := clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Delete(c.Cmd().Context(), c.name, v1.DeleteOptions{}); err != nil {
+		return err
+	}
+
+	deleted, failed := 0, 0
+	for _, buildrun := range buildRuns {
+		err := clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Delete(c.cmd.Context(), buildrun.Name, v1.DeleteOptions{})
+		switch {
+		// the build controller may have removed it already, when the Build owns its BuildRuns
+		case err == nil, kerrors.IsNotFound(err):
+			deleted++
+		default:
+			failed++
+			fmt.Fprintf(io.ErrOut, "Error deleting BuildRun %q: %v\n", buildrun.Name, err)
+		}
+	}
+
 	fmt.Fprintf(io.Out, "Build deleted %q\n", c.name)
+	if c.deleteRuns {
+		fmt.Fprintf(io.Out, "Deleted %d BuildRun(s) of Build %q", deleted, c.name)
+		if failed > 0 {
+			fmt.Fprintf(io.Out, ", %d failed", failed)
+		}
+		fmt.Fprintln(io.Out)
+	}
 
+	if c.wait {
+		names := []string{}
+		for _, br := range buildRuns {
+			names = append(names, br.Name)
+		}
+		return c.waitForDeletion(clientset, params.Namespace(), io, names)
+	}
+	return nil
+}
+
+// waitForDeletion blocks until the Build and the informed BuildRuns are gone, the timeout is
+// translated to an error carrying the respective exit code.
+func (c *DeleteCommand) waitForDeletion(
+	clientset buildclientset.Interface,
+	ns string,
+	io *genericclioptions.IOStreams,
+	buildRuns []string,
+) error {
+	ctx := c.cmd.Context()
+	if c.waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.waitTimeout)
+		defer cancel()
+	}
+
+	fmt.Fprintf(io.Out, "Waiting for Build %q to be gone...\n", c.name)
+	builds := clientset.ShipwrightV1alpha1().Builds(ns)
+	err := reactor.NewDeletionWatcher(
+		func(ctx context.Context) ([]string, string, error) {
+			list, err := builds.List(ctx, v1.ListOptions{})
+			if err != nil {
+				return nil, "", err
+			}
+			names := []string{}
+			for _, b := range list.Items {
+				names = append(names, b.Name)
+			}
+			return names, list.ResourceVersion, nil
+		},
+		func(ctx context.Context, listOpts v1.ListOptions) (watch.Interface, error) {
+			return builds.Watch(ctx, listOpts)
+		},
+	).WaitForDeletion(ctx, c.name)
+	if err != nil {
+		return deletionError(err, "Build")
+	}
+
+	if len(buildRuns) > 0 {
+		fmt.Fprintf(io.Out, "Waiting for %d BuildRun(s) of Build %q to be gone...\n", len(buildRuns), c.name)
+		runs := clientset.ShipwrightV1alpha1().BuildRuns(ns)
+		err = reactor.NewDeletionWatcher(
+			func(ctx context.Context) ([]string, string, error) {
+				list, err := runs.List(ctx, v1.ListOptions{})
+				if err != nil {
+					return nil, "", err
+				}
+				names := []string{}
+				for _, br := range list.Items {
+					names = append(names, br.Name)
+				}
+				return names, list.ResourceVersion, nil
+			},
+			func(ctx context.Context, listOpts v1.ListOptions) (watch.Interface, error) {
+				return runs.Watch(ctx, listOpts)
+			},
+		).WaitForDeletion(ctx, buildRuns...)
+		if err != nil {
+			return deletionError(err, "BuildRun")
+		}
+	}
+
+	fmt.Fprintf(io.Out, "Build %q is gone\n", c.name)
 	return nil
 }
+
+// deletionError translates the timeout waiting for the deletion to the respective exit code.
+func deletionError(err error, kind string) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return exitcode.Errorf(exitcode.Timeout, "timed out waiting for the %s deletion: %v", kind, err)
+	}
+	return err
+}
+
+// ownedByBuild checks whether the BuildRun belongs to the Build, either by the Build name label or
+// an owner reference.
+func ownedByBuild(br *buildv1alpha1.BuildRun, b *buildv1alpha1.Build) bool {
+	if br.GetLabels()[buildv1alpha1.LabelBuild] == b.GetName() {
+		return true
+	}
+	for _, ref := range br.GetOwnerReferences() {
+		if ref.Kind == "Build" && ref.Name == b.GetName() && (ref.UID == "" || b.GetUID() == "" || ref.UID == b.GetUID()) {
+			return true
+		}
+	}
+	return false
+}
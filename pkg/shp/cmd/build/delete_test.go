@@ -0,0 +1,112 @@
+package build
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	fakekubetesting "k8s.io/client-go/testing"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestDeleteBuildWithRuns(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	newBuildRun := func(name string, mutate func(br *buildv1alpha1.BuildRun)) *buildv1alpha1.BuildRun {
+		br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+		mutate(br)
+		return br
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		g := o.NewWithT(t)
+
+		shpclientset := shpfake.NewSimpleClientset(
+			&buildv1alpha1.Build{ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: ns, UID: types.UID("uid")}},
+			newBuildRun("labeled", func(br *buildv1alpha1.BuildRun) {
+				br.Labels = map[string]string{buildv1alpha1.LabelBuild: "my-app"}
+			}),
+			newBuildRun("owned", func(br *buildv1alpha1.BuildRun) {
+				br.OwnerReferences = []metav1.OwnerReference{{Kind: "Build", Name: "my-app", UID: types.UID("uid")}}
+			}),
+			newBuildRun("other", func(br *buildv1alpha1.BuildRun) {
+				br.Labels = map[string]string{buildv1alpha1.LabelBuild: "other-app"}
+			}),
+		)
+		p := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, ns, nil, nil)
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+		cmd := deleteCmd().(*DeleteCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags([]string{"--with-runs", "--dry-run=" + strconv.FormatBool(dryRun)})).To(o.Succeed())
+		g.Expect(cmd.Complete(p, &ioStreams, []string{"my-app"})).To(o.Succeed())
+		g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+
+		brs, err := shpclientset.ShipwrightV1alpha1().BuildRuns(ns).List(context.TODO(), metav1.ListOptions{})
+		g.Expect(err).To(o.BeNil())
+		_, err = shpclientset.ShipwrightV1alpha1().Builds(ns).Get(context.TODO(), "my-app", metav1.GetOptions{})
+
+		if dryRun {
+			g.Expect(err).To(o.BeNil())
+			g.Expect(brs.Items).To(o.HaveLen(3))
+			g.Expect(out.String()).To(o.ContainSubstring(`BuildRun "labeled" would be deleted`))
+			g.Expect(out.String()).To(o.ContainSubstring(`BuildRun "owned" would be deleted`))
+			g.Expect(strings.Contains(out.String(), `"other"`)).To(o.BeFalse())
+			continue
+		}
+		g.Expect(err).NotTo(o.BeNil())
+		g.Expect(brs.Items).To(o.HaveLen(1))
+		g.Expect(brs.Items[0].Name).To(o.Equal("other"))
+		g.Expect(out.String()).To(o.ContainSubstring(`Deleted 2 BuildRun(s) of Build "my-app"`))
+	}
+}
+
+func TestDeleteBuildWait(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+
+	shpclientset := shpfake.NewSimpleClientset(
+		&buildv1alpha1.Build{ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: ns}},
+	)
+	// emulating a finalizer, the Build lingers after the deletion until it's removed by the tracker
+	shpclientset.PrependReactor("delete", "builds", func(fakekubetesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	gvr := buildv1alpha1.SchemeGroupVersion.WithResource("builds")
+	go func() {
+		for {
+			for _, action := range shpclientset.Actions() {
+				if action.GetVerb() == "watch" {
+					_ = shpclientset.Tracker().Delete(gvr, ns, "my-app")
+					return
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, ns, nil, nil)
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+	cmd := deleteCmd().(*DeleteCommand)
+	cmd.cmd.SetContext(context.TODO())
+	g.Expect(cmd.cmd.ParseFlags([]string{"--wait", "--wait-timeout=10s"})).To(o.Succeed())
+	g.Expect(cmd.Complete(p, &ioStreams, []string{"my-app"})).To(o.Succeed())
+	g.Expect(cmd.Validate()).To(o.Succeed())
+	g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+	g.Expect(out.String()).To(o.HaveSuffix("Build \"my-app\" is gone\n"))
+
+	g.Expect(cmd.cmd.ParseFlags([]string{"--dry-run"})).To(o.Succeed())
+	g.Expect(cmd.Validate()).To(o.MatchError("--wait and --dry-run are mutually exclusive"))
+}
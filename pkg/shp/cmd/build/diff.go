@@ -0,0 +1,356 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/config"
+	"github.com/shipwright-io/cli/pkg/shp/diff"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/manifest"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+)
+
+// DiffCommand contains data input from user for the diff sub-command
+type DiffCommand struct {
+	cmd *cobra.Command
+
+	name      string                   // build name, when comparing the flags
+	filenames []string                 // manifests of the Builds, instead of the flags
+	exitCode  bool                     // exit with code 1 when differences are found
+	output    string                   // changes output format
+	buildSpec *buildv1alpha1.BuildSpec // stores command-line flags
+}
+
+const buildDiffLongDesc = `
+Shows the changes an update would make on the live Build, field by field, without modifying it.
+The desired Build is either described by the same flags as "shp build create", where only the
+flags informed are changed, or read from manifests, merged onto the live Build with strategic merge
+semantics. List elements carrying a name, like environment variables, are compared by name:
+
+	$ shp build diff my-app --source-revision=v2 --timeout=15m
+	$ shp build diff -f build.yaml
+
+Builds on the manifests which don't exist yet are shown as entirely added. Like "git diff", with
+--exit-code the command exits with 1 when differences are found, and 0 otherwise, useful for GitOps
+pre-checks. The changes are rendered for machines with "-o json".
+`
+
+// buildDiff changes found on a single Build.
+type buildDiff struct {
+	Name    string        `json:"name"`
+	Exists  bool          `json:"exists"`
+	Changes []diff.Change `json:"changes"`
+}
+
+func diffCmd() runner.SubCommand {
+	cmd := &cobra.Command{
+		Use:   "diff [<name>] [flags]",
+		Short: "Show the changes on the live Build, out of flags or manifests",
+		Long:  buildDiffLongDesc,
+		Args:  cobra.MaximumNArgs(1),
+	}
+
+	c := &DiffCommand{
+		cmd:       cmd,
+		buildSpec: flags.BuildSpecFromFlags(cmd.Flags()),
+	}
+	skipBuildSpecDefaults(cmd.Flags())
+	flags.FilenamesFlags(cmd.Flags(), &c.filenames)
+	cmd.Flags().BoolVar(&c.exitCode, "exit-code", false, "exit with code 1 when differences are found, 0 otherwise")
+	cmd.Flags().StringVarP(&c.output, "output", "o", "", "output format of the changes, either empty or \"json\"")
+	return c
+}
+
+// Cmd returns cobra command object of the diff subcommand
+func (c *DiffCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the Build name
+func (c *DiffCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	if len(args) == 1 {
+		c.name = args[0]
+	}
+	return nil
+}
+
+// Validate checks user input data
+func (c *DiffCommand) Validate() error {
+	if c.output != "" && c.output != "json" {
+		return fmt.Errorf("unsupported --output %q, only \"json\" is supported", c.output)
+	}
+	if len(c.filenames) == 0 {
+		if c.name == "" {
+			return fmt.Errorf("the Build name or --%s must be informed", flags.FilenameFlag)
+		}
+		return nil
+	}
+	if changed := c.buildFlagsChanged(); changed != "" {
+		return fmt.Errorf("--%s can't be informed along with --%s", changed, flags.FilenameFlag)
+	}
+	return manifest.ValidateFilenames(c.filenames)
+}
+
+// buildFlagsChanged returns the name of a Build flag informed, empty when none.
+func (c *DiffCommand) buildFlagsChanged() string {
+	changed := ""
+	c.cmd.Flags().Visit(func(f *pflag.Flag) {
+		switch f.Name {
+		case flags.FilenameFlag, "exit-code", "output":
+		default:
+			changed = f.Name
+		}
+	})
+	return changed
+}
+
+// Run compares the desired Builds with the live ones
+func (c *DiffCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	var diffs []buildDiff
+	var err error
+	if len(c.filenames) > 0 {
+		diffs, err = c.diffManifests(params, ioStreams)
+	} else {
+		var d *buildDiff
+		if d, err = c.diffFlags(params); err == nil {
+			diffs = []buildDiff{*d}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	different := 0
+	for _, d := range diffs {
+		if !d.Exists || len(d.Changes) > 0 {
+			different++
+		}
+	}
+	if c.output == "json" {
+		data, err := json.MarshalIndent(diffs, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(ioStreams.Out, string(data))
+	} else {
+		for _, d := range diffs {
+			switch {
+			case !d.Exists:
+				fmt.Fprintf(ioStreams.Out, "Build %q does not exist, it would be created:\n", styles.Bold(d.Name))
+			case len(d.Changes) == 0:
+				fmt.Fprintf(ioStreams.Out, "Build %q has no differences\n", styles.Bold(d.Name))
+				continue
+			default:
+				fmt.Fprintf(ioStreams.Out, "Build %q:\n", styles.Bold(d.Name))
+			}
+			diff.Print(ioStreams.Out, d.Changes)
+		}
+	}
+	if c.exitCode && different > 0 {
+		return exitcode.Errorf(exitcode.Failure, "differences found on %d Build(s)", different)
+	}
+	return nil
+}
+
+// diffFlags compares the live Build with the one resulting of applying the informed flags.
+func (c *DiffCommand) diffFlags(params *params.Params) (*buildDiff, error) {
+	patch, err := buildSpecPatch(c.cmd.Flags(), c.buildSpec)
+	if err != nil {
+		return nil, err
+	}
+	live, err := c.liveBuild(params, c.name)
+	if err != nil {
+		return nil, err
+	}
+	if live == nil {
+		return nil, exitcode.Errorf(exitcode.NotFound, "Build %q not found", c.name)
+	}
+	return compareBuild(c.name, live, map[string]interface{}{"spec": patch})
+}
+
+// diffManifests compares the live Builds with the ones on the manifests, when the name is informed
+// only the respective Build is compared.
+func (c *DiffCommand) diffManifests(params *params.Params, ioStreams *genericclioptions.IOStreams) ([]buildDiff, error) {
+	objects, err := manifest.ReadAll(c.cmd.Context(), ioStreams.In, c.filenames)
+	if err != nil {
+		return nil, err
+	}
+	diffs := []buildDiff{}
+	for _, obj := range objects {
+		if obj.GetKind() != "Build" || (c.name != "" && obj.GetName() != c.name) {
+			continue
+		}
+		if err = prepareManifest(obj, params.Namespace()); err != nil {
+			return nil, err
+		}
+		live, err := c.liveBuild(params, obj.GetName())
+		if err != nil {
+			return nil, err
+		}
+		d, err := compareBuild(obj.GetName(), live, obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, *d)
+	}
+	if len(diffs) == 0 {
+		if c.name != "" {
+			return nil, fmt.Errorf("no Build %q found on the manifests", c.name)
+		}
+		return nil, fmt.Errorf("no Builds found")
+	}
+	return diffs, nil
+}
+
+// liveBuild retrieves the Build, nil when it doesn't exist.
+func (c *DiffCommand) liveBuild(params *params.Params, name string) (*buildv1alpha1.Build, error) {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return nil, err
+	}
+	b, err := clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Get(c.cmd.Context(), name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// compareBuild merges the patch onto the live Build with strategic merge semantics, comparing the
+// labels, annotations and spec of both. Without a live Build the patch is the desired Build.
+func compareBuild(name string, live *buildv1alpha1.Build, patch map[string]interface{}) (*buildDiff, error) {
+	if live == nil {
+		return &buildDiff{Name: name, Changes: diff.Compare(nil, diffFields(patch))}, nil
+	}
+	liveData, err := json.Marshal(live)
+	if err != nil {
+		return nil, err
+	}
+	patchData, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	desiredData, err := strategicpatch.StrategicMergePatch(liveData, patchData, &buildv1alpha1.Build{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to merge Build %q: %w", name, err)
+	}
+	liveObj, desiredObj := map[string]interface{}{}, map[string]interface{}{}
+	if err = json.Unmarshal(liveData, &liveObj); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(desiredData, &desiredObj); err != nil {
+		return nil, err
+	}
+	return &buildDiff{
+		Name:    name,
+		Exists:  true,
+		Changes: diff.Compare(diffFields(liveObj), diffFields(desiredObj)),
+	}, nil
+}
+
+// diffFields selects the fields of the Build under the user control: labels, annotations and spec.
+func diffFields(obj map[string]interface{}) map[string]interface{} {
+	selected := map[string]interface{}{}
+	for _, field := range []string{"labels", "annotations"} {
+		if value, found, _ := unstructured.NestedFieldNoCopy(obj, "metadata", field); found && value != nil {
+			_ = unstructured.SetNestedField(selected, value, "metadata", field)
+		}
+	}
+	if spec, found := obj["spec"]; found && spec != nil {
+		selected["spec"] = spec
+	}
+	return selected
+}
+
+// buildSpecFlagPaths the Build spec fields set by each flag.
+var buildSpecFlagPaths = map[string][]string{
+	flags.SourceURLFlag:                  {"source", "url"},
+	flags.SourceRevisionFlag:             {"source", "revision"},
+	flags.SourceContextDirFlag:           {"source", "contextDir"},
+	flags.SourceCredentialsSecretFlag:    {"source", "credentials"},
+	flags.SourceBundleImageFlag:          {"source", "bundleContainer", "image"},
+	flags.SourceBundlePruneFlag:          {"source", "bundleContainer", "prune"},
+	flags.StrategyAPIVersionFlag:         {"strategy", "apiVersion"},
+	flags.StrategyKindFlag:               {"strategy", "kind"},
+	flags.StrategyNameFlag:               {"strategy", "name"},
+	flags.DockerfileFlag:                 {"dockerfile"},
+	flags.BuilderImageFlag:               {"builder", "image"},
+	flags.BuilderCredentialsSecretFlag:   {"builder", "credentials"},
+	flags.BuilderInsecureFlag:            {"builder", "insecure"},
+	flags.OutputImageFlag:                {"output", "image"},
+	flags.OutputCredentialsSecretFlag:    {"output", "credentials"},
+	flags.OutputInsecureFlag:             {"output", "insecure"},
+	flags.OutputImageLabelsFlag:          {"output", "labels"},
+	flags.OutputImageAnnotationsFlag:     {"output", "annotations"},
+	flags.TimeoutFlag:                    {"timeout"},
+	flags.EnvFlag:                        {"env"},
+	flags.EnvFileFlag:                    {"env"},
+	flags.EnvSecretFlag:                  {"env"},
+	flags.EnvConfigMapFlag:               {"env"},
+	flags.ParamFlag:                      {"paramValues"},
+	flags.ParamsFromJSONFlag:             {"paramValues"},
+	flags.RetentionFailedLimitFlag:       {"retention", "failedLimit"},
+	flags.RetentionSucceededLimitFlag:    {"retention", "succeededLimit"},
+	flags.RetentionTTLAfterFailedFlag:    {"retention", "ttlAfterFailed"},
+	flags.RetentionTTLAfterSucceededFlag: {"retention", "ttlAfterSucceeded"},
+}
+
+// skipBuildSpecDefaults opts the Build spec flags out of the configuration defaults, like the
+// strategy, since the flags describe changes on the live Build.
+func skipBuildSpecDefaults(flagSet *pflag.FlagSet) {
+	for name := range buildSpecFlagPaths {
+		if err := flagSet.SetAnnotation(name, config.SkipFlagDefaultAnnotation, []string{"true"}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// buildSpecPatch returns the fields set by the flags informed explicitly, regardless of whether the
+// value is the flag default. Fields cleared by the flags are set to null, thus removed by the
+// strategic merge.
+func buildSpecPatch(flagSet *pflag.FlagSet, spec *buildv1alpha1.BuildSpec) (map[string]interface{}, error) {
+	informed := spec.DeepCopy()
+	flags.SanitizeBuildSpec(informed)
+	informedObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(informed)
+	if err != nil {
+		return nil, err
+	}
+	// the bundle container is sanitized away without the image, while the prune option stands alone
+	rawObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := map[string]interface{}{}
+	var setErr error
+	flagSet.Visit(func(f *pflag.Flag) {
+		path, found := buildSpecFlagPaths[f.Name]
+		if !found || setErr != nil {
+			return
+		}
+		obj := informedObj
+		if f.Name == flags.SourceBundlePruneFlag {
+			obj = rawObj
+		}
+		value, found, _ := unstructured.NestedFieldCopy(obj, path...)
+		if !found {
+			value = nil
+		}
+		setErr = unstructured.SetNestedField(patch, value, path...)
+	})
+	return patch, setErr
+}
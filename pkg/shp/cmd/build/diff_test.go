@@ -0,0 +1,158 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	"github.com/shipwright-io/cli/pkg/shp/config"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestDiffCommand(t *testing.T) {
+	g := o.NewWithT(t)
+
+	build := &buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "app"},
+		Spec: buildv1alpha1.BuildSpec{
+			Source:     buildv1alpha1.Source{URL: pointer.String("https://github.com/org/app"), Revision: pointer.String("main")},
+			Strategy:   buildv1alpha1.Strategy{Name: "buildah"},
+			Dockerfile: pointer.String("Dockerfile"),
+			Output:     buildv1alpha1.Image{Image: "ghcr.io/org/app:latest"},
+			Env:        []corev1.EnvVar{{Name: "A", Value: "1"}},
+		},
+	}
+	p := params.NewParamsForTest(
+		fake.NewSimpleClientset(),
+		shpfake.NewSimpleClientset(build),
+		nil,
+		metav1.NamespaceDefault,
+		nil,
+		nil,
+	)
+
+	run := func(in string, args ...string) (string, error) {
+		cmd := diffCmd().(*DiffCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		if err := cmd.Complete(p, nil, cmd.cmd.Flags().Args()); err != nil {
+			return "", err
+		}
+		if err := cmd.Validate(); err != nil {
+			return "", err
+		}
+		ioStreams, stdin, out, _ := genericclioptions.NewTestIOStreams()
+		stdin.WriteString(in)
+		err := cmd.Run(p, &ioStreams)
+		return out.String(), err
+	}
+
+	// only the informed flags are compared, the strategy name keeps the live value
+	out, err := run("", "app", "--source-revision=v2", "--env=B=2")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).To(o.Equal(`Build "app":
+  - spec.env[name=A].name: "A"
+  - spec.env[name=A].value: "1"
+  + spec.env[name=B].name: "B"
+  + spec.env[name=B].value: "2"
+  ~ spec.source.revision: "main" -> "v2"
+`))
+
+	out, err = run("", "app", "--source-revision=main", "--exit-code")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).To(o.Equal("Build \"app\" has no differences\n"))
+
+	_, err = run("", "missing", "--source-revision=main")
+	g.Expect(exitcode.FromError(err)).To(o.Equal(exitcode.NotFound))
+
+	manifests := `apiVersion: shipwright.io/v1alpha1
+kind: Build
+metadata:
+  name: app
+  labels:
+    team: platform
+spec:
+  source:
+    revision: v3
+  env:
+  - name: A
+    value: "1"
+  - name: C
+    value: "3"
+---
+apiVersion: shipwright.io/v1alpha1
+kind: Build
+metadata:
+  name: new-app
+spec:
+  strategy:
+    name: buildah
+  output:
+    image: ghcr.io/org/new-app
+`
+	out, err = run(manifests, "-f", "-", "--exit-code")
+	var exitErr *exitcode.Error
+	g.Expect(errors.As(err, &exitErr)).To(o.BeTrue())
+	g.Expect(exitErr.Code).To(o.Equal(exitcode.Failure))
+	g.Expect(out).To(o.Equal(`Build "app":
+  + metadata.labels.team: "platform"
+  + spec.env[name=C].name: "C"
+  + spec.env[name=C].value: "3"
+  ~ spec.source.revision: "main" -> "v3"
+Build "new-app" does not exist, it would be created:
+  + spec.output.image: "ghcr.io/org/new-app"
+  + spec.strategy.name: "buildah"
+`))
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "build.yaml"), []byte(manifests), 0o600)).To(o.Succeed())
+	out, err = run("", "new-app", "-f", dir, "-o", "json")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(strings.Contains(out, `"exists": false`)).To(o.BeTrue())
+	g.Expect(out).NotTo(o.ContainSubstring(`"name": "app"`))
+
+	_, err = run("", "app", "-f", dir, "--source-revision=v2")
+	g.Expect(err).To(o.MatchError("--source-revision can't be informed along with --filename"))
+
+	_, err = run("")
+	g.Expect(err).To(o.MatchError("the Build name or --filename must be informed"))
+
+	// the configured strategy and registry prefix are not compared, only the informed flags
+	cmd := diffCmd().(*DiffCommand)
+	cmd.cmd.SetContext(context.TODO())
+	g.Expect(cmd.cmd.ParseFlags([]string{"--source-revision=main"})).To(o.Succeed())
+	cfg := &config.Config{Strategy: "kaniko", StrategyKind: "BuildStrategy", RegistryPrefix: "quay.io/org"}
+	g.Expect(cfg.ApplyDefaults(cmd.cmd, []string{"app"})).To(o.Succeed())
+	g.Expect(cmd.Complete(p, nil, []string{"app"})).To(o.Succeed())
+	g.Expect(cmd.Validate()).To(o.Succeed())
+	ioStreams, _, diffOut, _ := genericclioptions.NewTestIOStreams()
+	g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+	g.Expect(diffOut.String()).To(o.Equal("Build \"app\" has no differences\n"))
+}
+
+func TestBuildSpecFlagPaths(t *testing.T) {
+	g := o.NewWithT(t)
+
+	flagSet := pflag.NewFlagSet("build", pflag.ContinueOnError)
+	flags.BuildSpecFromFlags(flagSet)
+	flagSet.VisitAll(func(f *pflag.Flag) {
+		g.Expect(buildSpecFlagPaths).To(o.HaveKey(f.Name), "the Build spec field of --%s", f.Name)
+	})
+}
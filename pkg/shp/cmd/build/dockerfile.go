@@ -0,0 +1,108 @@
+package build
+
+import (
+	"fmt"
+	"path"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+)
+
+// dockerfileParam strategy parameter receiving the Dockerfile path, as in the sample strategies.
+const dockerfileParam = "dockerfile"
+
+// dockerfileConfigMapSuffix suffix of the Dockerfile ConfigMap name, created out of the Build name.
+const dockerfileConfigMapSuffix = "-dockerfile"
+
+// hasParameter tells whether the strategy declares the informed parameter.
+func hasParameter(strategy buildv1alpha1.BuilderStrategy, name string) bool {
+	for _, p := range strategy.GetParameters() {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasParamValue tells whether the Build already sets the informed parameter.
+func hasParamValue(spec *buildv1alpha1.BuildSpec, name string) bool {
+	for _, pv := range spec.ParamValues {
+		if pv.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// volumeMountPath looks for the path where the strategy steps mount the informed volume, the
+// volume must be overridable.
+func volumeMountPath(strategy buildv1alpha1.BuilderStrategy, volume string) (string, error) {
+	overridable := false
+	for _, v := range strategy.GetVolumes() {
+		if v.Name == volume {
+			overridable = v.Overridable != nil && *v.Overridable
+			break
+		}
+	}
+	if !overridable {
+		return "", fmt.Errorf("strategy %q does not declare the overridable volume %q", strategy.GetName(), volume)
+	}
+	for _, step := range strategy.GetBuildSteps() {
+		for _, mount := range step.VolumeMounts {
+			if mount.Name == volume {
+				return mount.MountPath, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("strategy %q does not mount the volume %q on its steps", strategy.GetName(), volume)
+}
+
+// applyDockerfile sets the Dockerfile path on the strategy parameter when the strategy declares
+// it, falling back to the deprecated Build field otherwise. When the ConfigMap is informed, it's
+// mounted on the strategy volume and the Dockerfile path points to its content, which requires
+// the strategy parameter.
+func applyDockerfile(
+	spec *buildv1alpha1.BuildSpec,
+	strategy buildv1alpha1.BuilderStrategy,
+	volume string,
+	configMap string,
+) error {
+	var dockerfile string
+	if spec.Dockerfile != nil {
+		dockerfile = *spec.Dockerfile
+	}
+
+	if configMap != "" {
+		if !hasParameter(strategy, dockerfileParam) {
+			return fmt.Errorf("strategy %q does not accept the %q parameter, unable to use a local Dockerfile",
+				strategy.GetName(), dockerfileParam)
+		}
+		mountPath, err := volumeMountPath(strategy, volume)
+		if err != nil {
+			return err
+		}
+		spec.Volumes = append(spec.Volumes, buildv1alpha1.BuildVolume{
+			Name: volume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMap},
+				},
+			},
+		})
+		dockerfile = path.Join(mountPath, flags.DockerfileKey)
+	}
+
+	if dockerfile == "" || !hasParameter(strategy, dockerfileParam) || hasParamValue(spec, dockerfileParam) {
+		return nil
+	}
+	spec.Dockerfile = nil
+	spec.ParamValues = append(spec.ParamValues, buildv1alpha1.ParamValue{
+		Name:        dockerfileParam,
+		SingleValue: &buildv1alpha1.SingleValue{Value: pointer.String(dockerfile)},
+	})
+	return nil
+}
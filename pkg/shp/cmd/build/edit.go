@@ -0,0 +1,310 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// editorEnvs environment variables naming the editor, in order of precedence.
+var editorEnvs = []string{"SHP_EDITOR", "EDITOR"}
+
+// defaultEditor editor launched when none is configured.
+const defaultEditor = "vi"
+
+// editHeader introduces the Build being edited.
+const editHeader = `# Please edit the Build below. Lines beginning with a '#' will be ignored,
+# and an empty file will abort the edit. If an error occurs while saving this file will be
+# reopened with the relevant failures.
+#
+`
+
+// EditorFn opens the file on the editor, returning once the editor exits.
+type EditorFn func(ioStreams *genericclioptions.IOStreams, path string) error
+
+// EditCommand contains data input from user for the edit sub-command
+type EditCommand struct {
+	cmd *cobra.Command
+
+	name           string   // build name
+	validateParams bool     // check the parameters against the build strategy
+	editor         EditorFn // launches the editor, replaceable for testing purposes
+}
+
+const buildEditLongDesc = `
+Opens the Build on the editor, and updates it when the edited Build is saved. The editor is taken
+from the SHP_EDITOR or EDITOR environment variables, falling back to "vi". For example:
+
+	$ EDITOR=nano shp build edit my-app
+
+The edited Build is validated before updating it, like "shp build validate" does, and with
+--validate-params its parameters are checked against the build strategy as well. Invalid edits are
+reopened on the editor, carrying the failures on the top of the file. Saving an empty file, or
+leaving the Build unchanged, aborts the edit.
+`
+
+func editCmd() runner.SubCommand {
+	editCommand := &EditCommand{
+		cmd: &cobra.Command{
+			Use:   "edit <name>",
+			Short: "Edit a Build on the editor",
+			Long:  buildEditLongDesc,
+			Args:  cobra.ExactArgs(1),
+		},
+		editor: launchEditor,
+	}
+
+	editCommand.cmd.Flags().BoolVar(&editCommand.validateParams, "validate-params", false, "check the parameters against the build strategy before updating")
+	return editCommand
+}
+
+// Cmd returns cobra command object of the edit subcommand
+func (c *EditCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the Build name
+func (c *EditCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+	return nil
+}
+
+// Validate is used for validation of user input data
+func (c *EditCommand) Validate() error {
+	if c.name == "" {
+		return fmt.Errorf("name is not informed")
+	}
+	return nil
+}
+
+// Run opens the Build on the editor until the edit is valid, and updated, or aborted
+func (c *EditCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	ctx := c.cmd.Context()
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	client := clientset.ShipwrightV1alpha1().Builds(params.Namespace())
+	b, err := client.Get(ctx, c.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	var original bytes.Buffer
+	if err = writeExportedBuild(&original, b); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp("", fmt.Sprintf("shp-edit-%s-*.yaml", c.name))
+	if err != nil {
+		return err
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	content := original.Bytes()
+	var failures []string
+	for {
+		if err = os.WriteFile(path, editContent(content, failures), 0o600); err != nil {
+			return err
+		}
+		if err = c.editor(ioStreams, path); err != nil {
+			return fmt.Errorf("unable to launch the editor: %w", err)
+		}
+		edited, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		content = stripComments(edited)
+
+		switch {
+		case len(bytes.TrimSpace(content)) == 0:
+			fmt.Fprintln(ioStreams.Out, "Edit cancelled, saved file was empty")
+			return nil
+		case bytes.Equal(content, original.Bytes()):
+			fmt.Fprintln(ioStreams.Out, "Edit cancelled, no changes made")
+			return nil
+		}
+
+		updated, warnings, failed := c.parseEdit(ctx, clientset, params.Namespace(), content)
+		if len(failed) == 0 {
+			updated, err = c.update(ctx, clientset, params.Namespace(), b, updated)
+			switch {
+			case err == nil:
+				for _, warning := range warnings {
+					fmt.Fprintf(ioStreams.ErrOut, "Warning: %s\n", warning)
+				}
+				fmt.Fprintf(ioStreams.Out, "Build %q edited\n", updated.GetName())
+				return nil
+			case kerrors.IsInvalid(err) || kerrors.IsBadRequest(err):
+				// the API server rejected the Build, the user gets to amend it
+				failed = []string{err.Error()}
+			default:
+				return err
+			}
+		}
+		failures = failed
+	}
+}
+
+// parseEdit decodes the edited Build strictly, and validates it. Returns the edited Build with
+// the warnings, or the failures making it invalid.
+func (c *EditCommand) parseEdit(
+	ctx context.Context,
+	clientset buildclientset.Interface,
+	ns string,
+	content []byte,
+) (*buildv1alpha1.Build, []string, []string) {
+	edited := &buildv1alpha1.Build{}
+	if err := yaml.UnmarshalStrict(content, edited); err != nil {
+		return nil, nil, []string{fmt.Sprintf("invalid document: %s", err)}
+	}
+	if edited.Kind != "Build" || edited.APIVersion != buildv1alpha1.SchemeGroupVersion.String() {
+		return nil, nil, []string{fmt.Sprintf("the object must remain a %s Build", buildv1alpha1.SchemeGroupVersion.String())}
+	}
+	if edited.GetName() != c.name {
+		return nil, nil, []string{fmt.Sprintf("metadata.name: the Build name can't be changed, it must remain %q", c.name)}
+	}
+
+	var warnings, failures []string
+	for _, f := range lintBuild(edited) {
+		message := f.Message
+		if f.Field != "" {
+			message = fmt.Sprintf("%s: %s", f.Field, f.Message)
+		}
+		if f.Severity == FindingError {
+			failures = append(failures, message)
+		} else {
+			warnings = append(warnings, message)
+		}
+	}
+	if len(failures) == 0 && c.validateParams {
+		failures = validateStrategyParams(ctx, clientset, ns, &edited.Spec)
+	}
+	return edited, warnings, failures
+}
+
+// update replaces the labels, annotations and specification of the Build with the edited ones.
+func (c *EditCommand) update(
+	ctx context.Context,
+	clientset buildclientset.Interface,
+	ns string,
+	b *buildv1alpha1.Build,
+	edited *buildv1alpha1.Build,
+) (*buildv1alpha1.Build, error) {
+	updated := b.DeepCopy()
+	updated.SetLabels(edited.GetLabels())
+	annotations := edited.GetAnnotations()
+	// the annotation is not shown on the editor, thus is kept
+	if last, ok := b.GetAnnotations()[lastAppliedConfigAnnotation]; ok {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[lastAppliedConfigAnnotation] = last
+	}
+	updated.SetAnnotations(annotations)
+	updated.Spec = edited.Spec
+	return clientset.ShipwrightV1alpha1().Builds(ns).Update(ctx, updated, metav1.UpdateOptions{})
+}
+
+// validateStrategyParams checks the parameter values are declared by the build strategy, and
+// the strategy parameters without defaults are informed.
+func validateStrategyParams(
+	ctx context.Context,
+	clientset buildclientset.Interface,
+	ns string,
+	spec *buildv1alpha1.BuildSpec,
+) []string {
+	strategy, err := util.GetBuildStrategy(ctx, clientset, ns, spec.Strategy)
+	if err != nil {
+		return []string{fmt.Sprintf("spec.strategy: unable to obtain strategy %q: %s", spec.Strategy.Name, err)}
+	}
+
+	failures := []string{}
+	for i, pv := range spec.ParamValues {
+		if !hasParameter(strategy, pv.Name) {
+			failures = append(failures, fmt.Sprintf("spec.paramValues[%d].name: strategy %q does not declare the parameter %q",
+				i, strategy.GetName(), pv.Name))
+		}
+	}
+	for _, p := range strategy.GetParameters() {
+		if p.Default == nil && p.Defaults == nil && !hasParamValue(spec, p.Name) {
+			failures = append(failures, fmt.Sprintf("spec.paramValues: strategy %q requires the parameter %q",
+				strategy.GetName(), p.Name))
+		}
+	}
+	return failures
+}
+
+// editContent renders the file opened on the editor, the header carries the failures of the
+// previous attempt.
+func editContent(content []byte, failures []string) []byte {
+	var b bytes.Buffer
+	b.WriteString(editHeader)
+	if len(failures) > 0 {
+		b.WriteString("# The edited Build is invalid:\n")
+		for _, failure := range failures {
+			for _, line := range strings.Split(failure, "\n") {
+				fmt.Fprintf(&b, "# * %s\n", line)
+			}
+		}
+		b.WriteString("#\n")
+	}
+	b.Write(content)
+	return b.Bytes()
+}
+
+// stripComments removes the lines beginning with a '#'.
+func stripComments(content []byte) []byte {
+	var b bytes.Buffer
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		b.WriteString(line)
+	}
+	return b.Bytes()
+}
+
+// launchEditor runs the configured editor attached to the terminal, the editor may carry
+// arguments, e.g. "code --wait".
+func launchEditor(ioStreams *genericclioptions.IOStreams, path string) error {
+	editor := defaultEditor
+	for _, env := range editorEnvs {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			editor = v
+			break
+		}
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...) // #nosec G204 the editor is chosen by the user
+	cmd.Stdin = ioStreams.In
+	cmd.Stdout = ioStreams.Out
+	cmd.Stderr = ioStreams.ErrOut
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("editor %q exited with code %d", editor, exitErr.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package build
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// scriptedEditor replaces the file content on each launch, recording the content opened.
+type scriptedEditor struct {
+	edits  []func(string) string
+	opened []string
+}
+
+func (s *scriptedEditor) edit(_ *genericclioptions.IOStreams, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	s.opened = append(s.opened, string(data))
+	edit := s.edits[0]
+	s.edits = s.edits[1:]
+	return os.WriteFile(path, []byte(edit(string(data))), 0o600)
+}
+
+func TestEditBuild(t *testing.T) {
+	newClientset := func() *shpfake.Clientset {
+		return shpfake.NewSimpleClientset(
+			&buildv1alpha1.Build{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "dev",
+					Name:        "my-app",
+					Annotations: map[string]string{lastAppliedConfigAnnotation: "{}"},
+				},
+				Spec: buildv1alpha1.BuildSpec{
+					Source:   buildv1alpha1.Source{URL: pointer.String("https://github.com/org/my-app")},
+					Strategy: buildv1alpha1.Strategy{Name: "buildah"},
+					Output:   buildv1alpha1.Image{Image: "registry.local/org/my-app:v1"},
+				},
+			},
+			&buildv1alpha1.ClusterBuildStrategy{
+				ObjectMeta: metav1.ObjectMeta{Name: "buildah"},
+				Spec: buildv1alpha1.BuildStrategySpec{
+					Parameters: []buildv1alpha1.Parameter{
+						{Name: "dockerfile", Default: pointer.String("Dockerfile")},
+						{Name: "target"},
+					},
+				},
+			},
+		)
+	}
+
+	run := func(t *testing.T, clientset *shpfake.Clientset, editor *scriptedEditor, args ...string) (string, error) {
+		g := o.NewWithT(t)
+		cmd := editCmd().(*EditCommand)
+		cmd.editor = editor.edit
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		g.Expect(cmd.Complete(nil, nil, []string{"my-app"})).To(o.Succeed())
+		g.Expect(cmd.Validate()).To(o.Succeed())
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		p := params.NewParamsForTest(fake.NewSimpleClientset(), clientset, nil, "dev", nil, nil)
+		err := cmd.Run(p, &ioStreams)
+		return out.String(), err
+	}
+
+	replace := func(old, new string) func(string) string {
+		return func(s string) string {
+			return strings.Replace(s, old, new, 1)
+		}
+	}
+
+	t.Run("reopens invalid edits with the failures", func(t *testing.T) {
+		g := o.NewWithT(t)
+		clientset := newClientset()
+		editor := &scriptedEditor{edits: []func(string) string{
+			replace("image: registry.local/org/my-app:v1", "image: \"\""),
+			replace("image: \"\"", "image: registry.local/org/my-app:v2"),
+		}}
+
+		out, err := run(t, clientset, editor)
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).To(o.Equal("Build \"my-app\" edited\n"))
+		g.Expect(editor.opened).To(o.HaveLen(2))
+		g.Expect(editor.opened[0]).To(o.HavePrefix(editHeader))
+		g.Expect(editor.opened[0]).NotTo(o.ContainSubstring("is invalid"))
+		g.Expect(editor.opened[0]).NotTo(o.ContainSubstring(lastAppliedConfigAnnotation))
+		g.Expect(editor.opened[1]).To(o.ContainSubstring("# The edited Build is invalid:\n# * spec.output.image: the output image is required\n"))
+		// the previous edit is kept
+		g.Expect(editor.opened[1]).To(o.ContainSubstring("image: \"\""))
+
+		b, err := clientset.ShipwrightV1alpha1().Builds("dev").Get(context.TODO(), "my-app", metav1.GetOptions{})
+		g.Expect(err).To(o.BeNil())
+		g.Expect(b.Spec.Output.Image).To(o.Equal("registry.local/org/my-app:v2"))
+		g.Expect(b.GetAnnotations()).To(o.HaveKey(lastAppliedConfigAnnotation))
+	})
+
+	t.Run("the name can't be changed", func(t *testing.T) {
+		g := o.NewWithT(t)
+		editor := &scriptedEditor{edits: []func(string) string{
+			replace("name: my-app", "name: other"),
+			func(string) string { return "" },
+		}}
+
+		out, err := run(t, newClientset(), editor)
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).To(o.Equal("Edit cancelled, saved file was empty\n"))
+		g.Expect(editor.opened[1]).To(o.ContainSubstring("the Build name can't be changed"))
+	})
+
+	t.Run("unchanged builds are not updated", func(t *testing.T) {
+		g := o.NewWithT(t)
+		clientset := newClientset()
+		editor := &scriptedEditor{edits: []func(string) string{
+			func(s string) string { return s + "# a comment\n" },
+		}}
+
+		out, err := run(t, clientset, editor)
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).To(o.Equal("Edit cancelled, no changes made\n"))
+		for _, action := range clientset.Actions() {
+			g.Expect(action.GetVerb()).NotTo(o.Equal("update"))
+		}
+	})
+
+	t.Run("strategy parameters", func(t *testing.T) {
+		g := o.NewWithT(t)
+		clientset := newClientset()
+		editor := &scriptedEditor{edits: []func(string) string{
+			replace("  strategy:", "  paramValues:\n  - name: unknown\n    value: x\n  strategy:"),
+			replace("name: unknown", "name: target"),
+		}}
+
+		out, err := run(t, clientset, editor, "--validate-params")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).To(o.Equal("Build \"my-app\" edited\n"))
+		g.Expect(editor.opened[1]).To(o.ContainSubstring(`strategy "buildah" does not declare the parameter "unknown"`))
+		g.Expect(editor.opened[1]).To(o.ContainSubstring(`strategy "buildah" requires the parameter "target"`))
+
+		b, err := clientset.ShipwrightV1alpha1().Builds("dev").Get(context.TODO(), "my-app", metav1.GetOptions{})
+		g.Expect(err).To(o.BeNil())
+		g.Expect(b.Spec.ParamValues).To(o.HaveLen(1))
+		g.Expect(b.Spec.ParamValues[0].Name).To(o.Equal("target"))
+	})
+}
+
+func TestStripComments(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(string(stripComments([]byte("# header\nkind: Build\n  # indented\nname: a # trailing\n")))).
+		To(o.Equal("kind: Build\nname: a # trailing\n"))
+}
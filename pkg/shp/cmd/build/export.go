@@ -0,0 +1,132 @@
+package build
+
+import (
+	"fmt"
+	"io"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// ExportCommand contains data input from user for the export sub-command
+type ExportCommand struct {
+	cmd *cobra.Command
+
+	names []string // build names to export
+}
+
+const buildExportLongDesc = `
+Exports one or more Builds as clean YAML, without the fields managed by the cluster, like status,
+managedFields, uid and resourceVersion. The output can be applied to another cluster or namespace
+using "shp build import". For example:
+
+	$ shp build export my-app > my-app.yaml
+	$ shp build import -f my-app.yaml --namespace=production
+`
+
+// lastAppliedConfigAnnotation annotation recorded by "kubectl apply".
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+func exportCmd() runner.SubCommand {
+	return &ExportCommand{
+		cmd: &cobra.Command{
+			Use:   "export <name> [<name>...]",
+			Short: "Export Builds as portable YAML",
+			Long:  buildExportLongDesc,
+			Args:  cobra.MinimumNArgs(1),
+		},
+	}
+}
+
+// Cmd returns cobra command object of the export subcommand
+func (c *ExportCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills ExportCommand structure with data obtained from cobra command
+func (c *ExportCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.names = args
+	return nil
+}
+
+// Validate is used for validation of user input data
+func (c *ExportCommand) Validate() error {
+	return nil
+}
+
+// Run retrieves the Builds and prints them as a stream of YAML documents
+func (c *ExportCommand) Run(params *params.Params, io *genericclioptions.IOStreams) error {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	for i, name := range c.names {
+		b, err := clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Get(c.cmd.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(io.Out, "---")
+		}
+		if err = writeExportedBuild(io.Out, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeExportedBuild strips the cluster-managed fields from the Build and writes it as YAML.
+func writeExportedBuild(w io.Writer, b *buildv1alpha1.Build) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(exportBuild(b))
+	if err != nil {
+		return err
+	}
+	// zero values still rendered by the serialization of the typed object
+	unstructured.RemoveNestedField(u, "status")
+	unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+
+	data, err := yaml.Marshal(u)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// exportBuild returns a copy of the informed Build carrying only the fields needed to recreate it
+// elsewhere.
+func exportBuild(b *buildv1alpha1.Build) *buildv1alpha1.Build {
+	annotations := map[string]string{}
+	for k, v := range b.GetAnnotations() {
+		if k == lastAppliedConfigAnnotation {
+			continue
+		}
+		annotations[k] = v
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	return &buildv1alpha1.Build{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: buildv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Build",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        b.GetName(),
+			Labels:      b.GetLabels(),
+			Annotations: annotations,
+		},
+		Spec: *b.Spec.DeepCopy(),
+	}
+}
@@ -0,0 +1,80 @@
+package build
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExportImportBuild(t *testing.T) {
+	g := o.NewWithT(t)
+
+	reason := buildv1alpha1.SucceedStatus
+	b := &buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "my-app",
+			Namespace:       "dev",
+			UID:             types.UID("8c5b6c5e"),
+			ResourceVersion: "42",
+			Generation:      3,
+			Labels:          map[string]string{"app": "my-app"},
+			Annotations: map[string]string{
+				lastAppliedConfigAnnotation: "{}",
+				"team":                      "a",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: buildv1alpha1.BuildSpec{
+			Output: buildv1alpha1.Image{Image: "quay.io/org/my-app:v1"},
+		},
+		Status: buildv1alpha1.BuildStatus{Reason: &reason},
+	}
+
+	var out bytes.Buffer
+	g.Expect(writeExportedBuild(&out, b)).To(o.Succeed())
+	exported := out.String()
+	for _, field := range []string{"status", "managedFields", "uid", "resourceVersion", "generation", "namespace", lastAppliedConfigAnnotation} {
+		g.Expect(exported).NotTo(o.ContainSubstring(field + ":"))
+	}
+	g.Expect(exported).To(o.ContainSubstring("kind: Build"))
+
+	builds, err := decodeBuilds(strings.NewReader(exported + "---\n" + exported))
+	g.Expect(err).To(o.BeNil())
+	g.Expect(builds).To(o.HaveLen(2))
+	g.Expect(builds[0].GetName()).To(o.Equal("my-app"))
+	g.Expect(builds[0].GetLabels()).To(o.Equal(b.GetLabels()))
+	g.Expect(builds[0].GetAnnotations()).To(o.Equal(map[string]string{"team": "a"}))
+	g.Expect(builds[0].Spec).To(o.Equal(b.Spec))
+
+	_, err = decodeBuilds(strings.NewReader("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"))
+	g.Expect(err).NotTo(o.BeNil())
+}
+
+func TestRewriteRegistry(t *testing.T) {
+	testCases := []struct {
+		image    string
+		expected string
+	}{
+		{"quay.io/org/app:v1", "registry.example.com/org/app:v1"},
+		{"quay.io/org/app", "registry.example.com/org/app"},
+		{"app:latest", "registry.example.com/library/app:latest"},
+		{
+			"localhost:5000/app@sha256:" + strings.Repeat("a", 64),
+			"registry.example.com/app@sha256:" + strings.Repeat("a", 64),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.image, func(t *testing.T) {
+			g := o.NewWithT(t)
+			image, err := rewriteRegistry(tt.image, "registry.example.com")
+			g.Expect(err).To(o.BeNil())
+			g.Expect(image).To(o.Equal(tt.expected))
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package build
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/utils/pointer"
+
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// Placeholders of the skeleton fields not informed by flags.
+const (
+	sourceURLPlaceholder   = "<git repository URL>"
+	outputImagePlaceholder = "<output image>"
+)
+
+// isRequired tells whether the strategy parameter has no default, thus the Build must set it.
+func isRequired(p buildv1alpha1.Parameter) bool {
+	if p.Type == buildv1alpha1.ParameterTypeArray {
+		return p.Defaults == nil
+	}
+	return p.Default == nil
+}
+
+// fromStrategy retrieves the strategy informed on --from-strategy, of the --strategy-kind kind.
+func (c *CreateCommand) fromStrategy(params *params.Params) (buildv1alpha1.BuilderStrategy, error) {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return nil, err
+	}
+	c.buildSpec.Strategy.Name = c.fromStrategyName
+	return util.GetBuildStrategy(c.cmd.Context(), clientset, params.Namespace(), c.buildSpec.Strategy)
+}
+
+// printSkeleton writes the Build manifest out of the flags, with every parameter of the strategy
+// set to its default, to be edited and created with --filename.
+func (c *CreateCommand) printSkeleton(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	strategy, err := c.fromStrategy(params)
+	if err != nil {
+		return err
+	}
+
+	spec := c.buildSpec.DeepCopy()
+	flags.SanitizeBuildSpec(spec)
+	if spec.Source.URL == nil && (spec.Source.BundleContainer == nil || spec.Source.BundleContainer.Image == "") {
+		spec.Source.URL = pointer.String(sourceURLPlaceholder)
+	}
+	if spec.Output.Image == "" {
+		spec.Output.Image = outputImagePlaceholder
+	}
+	b := &buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{Name: c.name, Labels: c.podLabels},
+		Spec:       *spec,
+	}
+	if len(b.Labels) == 0 {
+		b.Labels = nil
+	}
+	if err = writeExportedBuild(ioStreams.Out, b); err != nil {
+		return err
+	}
+	return writeParamValues(ioStreams.Out, strategy.GetParameters())
+}
+
+// writeParamValues appends the strategy parameters to the Build manifest as its paramValues, set
+// to their defaults and preceded by their descriptions, the ones without defaults are marked as
+// required.
+func writeParamValues(w io.Writer, parameters []buildv1alpha1.Parameter) error {
+	if len(parameters) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w, "  paramValues:")
+	for _, p := range parameters {
+		comment := strings.Join(strings.Fields(p.Description), " ")
+		if isRequired(p) {
+			comment = strings.TrimSpace("(required) " + comment)
+		}
+		if comment != "" {
+			fmt.Fprintf(w, "  # %s\n", comment)
+		}
+		fmt.Fprintf(w, "  - name: %s\n", quoteYAML(p.Name))
+		if p.Type != buildv1alpha1.ParameterTypeArray {
+			var value string
+			if p.Default != nil {
+				value = *p.Default
+			}
+			fmt.Fprintf(w, "    value: %s\n", quoteYAML(value))
+			continue
+		}
+		if p.Defaults == nil || len(*p.Defaults) == 0 {
+			fmt.Fprintln(w, "    values: []")
+			continue
+		}
+		fmt.Fprintln(w, "    values:")
+		for _, value := range *p.Defaults {
+			fmt.Fprintf(w, "    - value: %s\n", quoteYAML(value))
+		}
+	}
+	return nil
+}
+
+// quoteYAML renders the string as a double quoted YAML scalar.
+func quoteYAML(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// promptParamValues asks for the source URL and output image, when not informed by flags, and for
+// each parameter of the strategy. The parameters left empty keep their defaults, the required
+// ones are asked again until answered. Array values are separated by commas.
+func (c *CreateCommand) promptParamValues(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	strategy, err := c.fromStrategy(params)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(ioStreams.In)
+	ask := func(name string, label string, required bool) (string, error) {
+		for {
+			fmt.Fprintf(ioStreams.ErrOut, "%s%s: ", name, label)
+			answer, err := reader.ReadString('\n')
+			answer = strings.TrimSpace(answer)
+			if answer != "" || !required {
+				return answer, nil
+			}
+			if errors.Is(err, io.EOF) {
+				return "", fmt.Errorf("%s must be informed", name)
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if c.buildSpec.Source.URL == nil || *c.buildSpec.Source.URL == "" {
+		url, err := ask("source URL", " (git repository)", true)
+		if err != nil {
+			return err
+		}
+		c.buildSpec.Source.URL = &url
+	}
+	if c.buildSpec.Output.Image == "" {
+		if c.buildSpec.Output.Image, err = ask("output image", " (container image pushed)", true); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range strategy.GetParameters() {
+		// the parameters informed by flags are not asked
+		if hasParamValue(c.buildSpec, p.Name) {
+			continue
+		}
+		var label string
+		if description := strings.Join(strings.Fields(p.Description), " "); description != "" {
+			label = fmt.Sprintf(" (%s)", description)
+		}
+		switch {
+		case p.Type == buildv1alpha1.ParameterTypeArray && p.Defaults != nil:
+			label = fmt.Sprintf("%s [%s]", label, strings.Join(*p.Defaults, ","))
+		case p.Default != nil:
+			label = fmt.Sprintf("%s [%s]", label, *p.Default)
+		}
+		answer, err := ask(p.Name, label, isRequired(p))
+		if err != nil {
+			return err
+		}
+		if answer == "" {
+			continue
+		}
+
+		pv := buildv1alpha1.ParamValue{Name: p.Name}
+		if p.Type == buildv1alpha1.ParameterTypeArray {
+			for _, v := range strings.Split(answer, ",") {
+				value := strings.TrimSpace(v)
+				pv.Values = append(pv.Values, buildv1alpha1.SingleValue{Value: &value})
+			}
+		} else {
+			pv.SingleValue = &buildv1alpha1.SingleValue{Value: &answer}
+		}
+		c.buildSpec.ParamValues = append(c.buildSpec.ParamValues, pv)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// ImportCommand contains data input from user for the import sub-command
+type ImportCommand struct {
+	cmd *cobra.Command
+
+	filename       string // file containing the exported builds, or "-" for stdin
+	outputRegistry string // registry replacing the one in the output image
+	overwrite      bool   // flag to update builds already present
+}
+
+const buildImportLongDesc = `
+Imports Builds previously exported with "shp build export" into the current namespace, optionally
+rewriting the registry of the output image. For example:
+
+	$ shp build import -f my-app.yaml --output-registry=registry.example.com
+`
+
+func importCmd() runner.SubCommand {
+	importCommand := &ImportCommand{
+		cmd: &cobra.Command{
+			Use:   "import",
+			Short: "Import Builds from exported YAML",
+			Long:  buildImportLongDesc,
+			Args:  cobra.NoArgs,
+		},
+	}
+
+	importCommand.cmd.Flags().StringVarP(&importCommand.filename, "filename", "f", "", "file containing the exported Builds, use \"-\" to read from stdin")
+	importCommand.cmd.Flags().StringVar(&importCommand.outputRegistry, "output-registry", "", "registry replacing the one in the output image, e.g. registry.example.com")
+	importCommand.cmd.Flags().BoolVar(&importCommand.overwrite, "overwrite", false, "update Builds already present in the namespace")
+	return importCommand
+}
+
+// Cmd returns cobra command object of the import subcommand
+func (c *ImportCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills ImportCommand structure with data obtained from cobra command
+func (c *ImportCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	return nil
+}
+
+// Validate is used for validation of user input data
+func (c *ImportCommand) Validate() error {
+	if c.filename == "" {
+		return fmt.Errorf("flag --filename is required")
+	}
+	if c.outputRegistry != "" {
+		if _, err := name.NewRegistry(c.outputRegistry); err != nil {
+			return fmt.Errorf("invalid --output-registry: %w", err)
+		}
+	}
+	return nil
+}
+
+// Run reads the Builds from the informed file and creates, or updates, them in the namespace
+func (c *ImportCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	var r io.Reader = ioStreams.In
+	if c.filename != "-" {
+		f, err := os.Open(c.filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	builds, err := decodeBuilds(r)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	client := clientset.ShipwrightV1alpha1().Builds(params.Namespace())
+
+	for _, b := range builds {
+		b.SetNamespace(params.Namespace())
+		if c.outputRegistry != "" {
+			if b.Spec.Output.Image, err = rewriteRegistry(b.Spec.Output.Image, c.outputRegistry); err != nil {
+				return fmt.Errorf("build %q: %w", b.GetName(), err)
+			}
+		}
+
+		_, err = client.Create(c.cmd.Context(), b, metav1.CreateOptions{})
+		switch {
+		case err == nil:
+			fmt.Fprintf(ioStreams.Out, "Created build %q\n", b.GetName())
+		case kerrors.IsAlreadyExists(err) && c.overwrite:
+			existing, err := client.Get(c.cmd.Context(), b.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			b.SetResourceVersion(existing.GetResourceVersion())
+			if _, err = client.Update(c.cmd.Context(), b, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+			fmt.Fprintf(ioStreams.Out, "Updated build %q\n", b.GetName())
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeBuilds reads the stream of YAML (or JSON) documents, making sure all of them are Builds.
+func decodeBuilds(r io.Reader) ([]*buildv1alpha1.Build, error) {
+	builds := []*buildv1alpha1.Build{}
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		b := &buildv1alpha1.Build{}
+		if err := decoder.Decode(b); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		// skipping empty documents
+		if b.Kind == "" && b.GetName() == "" {
+			continue
+		}
+		if b.Kind != "Build" || b.APIVersion != buildv1alpha1.SchemeGroupVersion.String() {
+			return nil, fmt.Errorf("unsupported object %s %q, only %s Builds can be imported",
+				b.Kind, b.GetName(), buildv1alpha1.SchemeGroupVersion.String())
+		}
+		builds = append(builds, exportBuild(b))
+	}
+	if len(builds) == 0 {
+		return nil, fmt.Errorf("no Builds found")
+	}
+	return builds, nil
+}
+
+// rewriteRegistry replaces the registry of the image reference, keeping repository, tag and digest.
+func rewriteRegistry(image string, registry string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	suffix := ""
+	switch r := ref.(type) {
+	case name.Digest:
+		suffix = "@" + r.DigestStr()
+	case name.Tag:
+		// the parser defaults the tag when absent, the original reference is kept untagged then
+		if strings.HasSuffix(image, ":"+r.TagStr()) {
+			suffix = ":" + r.TagStr()
+		}
+	}
+	return fmt.Sprintf("%s/%s%s", registry, ref.Context().RepositoryStr(), suffix), nil
+}
@@ -0,0 +1,85 @@
+package build
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+	"golang.org/x/term"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// interruption intercepts the interrupt signal, i.e. Ctrl-C, while the BuildRun is followed or
+// waited, thus the command stops cleanly instead of leaving the BuildRun running silently.
+type interruption struct {
+	signalCh chan os.Signal
+	doneCh   chan struct{}
+	received atomic.Bool
+}
+
+// notifyInterrupt starts intercepting the interrupt signal, the informed function is called when
+// it's received. Only the first signal is intercepted, a second one terminates the process.
+func (r *RunCommand) notifyInterrupt(onInterrupt func()) *interruption {
+	i := &interruption{signalCh: r.signalCh, doneCh: make(chan struct{})}
+	if i.signalCh == nil {
+		i.signalCh = make(chan os.Signal, 1)
+		signal.Notify(i.signalCh, os.Interrupt)
+	}
+	go func() {
+		select {
+		case <-i.signalCh:
+			signal.Stop(i.signalCh)
+			i.received.Store(true)
+			onInterrupt()
+		case <-i.doneCh:
+		}
+	}()
+	return i
+}
+
+// interrupted tells whether the interrupt signal has been received.
+func (i *interruption) interrupted() bool {
+	return i.received.Load()
+}
+
+// stop stops intercepting the interrupt signal.
+func (i *interruption) stop() {
+	signal.Stop(i.signalCh)
+	close(i.doneCh)
+}
+
+// handleInterrupt decides the fate of the BuildRun after the command is interrupted, it's canceled
+// when --cancel-on-interrupt is set or the user confirms on the terminal, otherwise it's left
+// running. The returned error carries the respective exit code.
+func (r *RunCommand) handleInterrupt(
+	clientset buildclientset.Interface,
+	ioStreams *genericclioptions.IOStreams,
+	name string,
+) error {
+	cancel := r.cancelOnInterrupt
+	if !cancel {
+		if f, ok := ioStreams.In.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			fmt.Fprintf(ioStreams.ErrOut, "\nCancel BuildRun %q as well? [y/N] ", name)
+			answer, _ := bufio.NewReader(ioStreams.In).ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			cancel = answer == "y" || answer == "yes"
+		}
+	}
+	if !cancel {
+		return exitcode.Errorf(exitcode.Failure,
+			"interrupted, BuildRun %q is still running, cancel it with \"shp buildrun cancel %s\"", name, name)
+	}
+
+	if err := util.CancelBuildRun(r.cmd.Context(), clientset, r.namespace, name); err != nil {
+		return fmt.Errorf("interrupted, unable to cancel BuildRun %q: %w", name, err)
+	}
+	return exitcode.Errorf(exitcode.Cancelled, "interrupted, BuildRun %q has been canceled", name)
+}
@@ -0,0 +1,101 @@
+package build
+
+import (
+	"os"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	fakekubetesting "k8s.io/client-go/testing"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestStartBuildRunWaitInterrupted(t *testing.T) {
+	tests := []struct {
+		name              string
+		cancelOnInterrupt bool
+		exitCode          int
+		message           string
+	}{
+		{
+			name:              "canceled on interrupt",
+			cancelOnInterrupt: true,
+			exitCode:          exitcode.Cancelled,
+			message:           `interrupted, BuildRun "testbuild-abcde" has been canceled`,
+		},
+		{
+			name:     "left running without a terminal",
+			exitCode: exitcode.Failure,
+			message:  `interrupted, BuildRun "testbuild-abcde" is still running, cancel it with "shp buildrun cancel testbuild-abcde"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      "testbuild-abcde",
+			}}
+			shpclientset := shpfake.NewSimpleClientset()
+			shpclientset.PrependReactor("create", "buildruns", func(_ fakekubetesting.Action) (bool, kruntime.Object, error) {
+				return true, br, nil
+			})
+			signalCh := make(chan os.Signal, 1)
+			shpclientset.PrependReactor("get", "buildruns", func(_ fakekubetesting.Action) (bool, kruntime.Object, error) {
+				// the BuildRun is still running when the command is interrupted
+				select {
+				case signalCh <- os.Interrupt:
+				default:
+				}
+				return true, br, nil
+			})
+			shpclientset.PrependReactor("patch", "buildruns", func(_ fakekubetesting.Action) (bool, kruntime.Object, error) {
+				return true, br, nil
+			})
+
+			ccmd := &cobra.Command{}
+			cmd := &RunCommand{
+				cmd:               ccmd,
+				buildRunSpec:      flags.BuildRunSpecFromFlags(ccmd.Flags()),
+				wait:              true,
+				cancelOnInterrupt: test.cancelOnInterrupt,
+				signalCh:          signalCh,
+			}
+			cmd.Cmd().ExecuteC()
+			param := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, metav1.NamespaceDefault, nil, nil)
+			ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+
+			g.Expect(cmd.Complete(param, &ioStreams, []string{"testbuild"})).To(o.Succeed())
+			g.Expect(cmd.Validate()).To(o.Succeed())
+			err := cmd.Run(param, &ioStreams)
+			g.Expect(err).To(o.MatchError(test.message))
+			g.Expect(exitcode.FromError(err)).To(o.Equal(test.exitCode))
+
+			patched := false
+			for _, action := range shpclientset.Actions() {
+				patched = patched || action.GetVerb() == "patch"
+			}
+			g.Expect(patched).To(o.Equal(test.cancelOnInterrupt))
+		})
+	}
+
+	t.Run("requires following or waiting", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cmd := runCmd().(*RunCommand)
+		g.Expect(cmd.cmd.ParseFlags([]string{"--cancel-on-interrupt"})).To(o.Succeed())
+		cmd.buildName = "testbuild"
+		g.Expect(cmd.Validate()).To(o.MatchError("--cancel-on-interrupt requires --follow or --wait"))
+	})
+}
@@ -3,14 +3,21 @@ package build
 import (
 	"fmt"
 	"text/tabwriter"
+	"time"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/printer"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+	"github.com/shipwright-io/cli/pkg/shp/util"
 	"github.com/spf13/cobra"
 
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors" // Import the k8serrors package
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -18,18 +25,51 @@ import (
 type ListCommand struct {
 	cmd *cobra.Command
 
-	noHeader bool
+	noHeader    bool
+	output      printer.Flags
+	failedOnly  bool          // only the Builds whose latest BuildRun failed
+	notRunSince time.Duration // only the Builds without BuildRuns created within the duration
+	registered  bool          // only the Builds with the registered status, when informed
+
+	now func() time.Time // current time, replaceable for testing purposes
 }
 
+const listLongDesc = `
+Lists the Builds of the namespace. For triage, the health filters narrow the list down, combined
+when more than one is informed:
+
+	--failed-only      only the Builds whose latest BuildRun failed
+	--not-run-since    only the Builds without BuildRuns created within the duration, e.g. 7d
+	--registered       only the Builds registered, or with validation errors when false
+
+The latest BuildRun of each Build is shown along with the filters --failed-only and --not-run-since,
+and the reason of the validation errors is shown inline. For example:
+
+	$ shp build list --failed-only
+	$ shp build list --not-run-since=7d
+	$ shp build list --registered=false
+`
+
 func listCmd() runner.SubCommand {
 	listCommand := &ListCommand{
 		cmd: &cobra.Command{
 			Use:   "list [flags]",
 			Short: "List Builds",
+			Long:  listLongDesc,
 		},
+		now: time.Now,
 	}
 
 	listCommand.cmd.Flags().BoolVar(&listCommand.noHeader, "no-header", false, "Do not show columns header in list output")
+	listCommand.output.AddFlags(listCommand.cmd.Flags())
+	listCommand.cmd.Flags().BoolVar(&listCommand.failedOnly, "failed-only", false, "Only list the Builds whose latest BuildRun failed")
+	listCommand.cmd.Flags().Var(
+		flags.NewDaysDurationValue(&listCommand.notRunSince),
+		"not-run-since",
+		"Only list the Builds without BuildRuns created within the duration, e.g. 7d or 72h",
+	)
+	// the filter only applies when informed, thus the default is never in effect
+	listCommand.cmd.Flags().BoolVar(&listCommand.registered, "registered", false, "Only list the Builds registered, or with validation errors when false, all of them when not informed")
 
 	return listCommand
 }
@@ -46,14 +86,69 @@ func (c *ListCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams,
 
 // Validate checks user input data
 func (c *ListCommand) Validate() error {
-	return nil
+	if c.notRunSince < 0 {
+		return fmt.Errorf("--not-run-since must not be negative")
+	}
+	return c.output.Validate()
+}
+
+// runFilters tells whether the filters relying on the BuildRuns are informed.
+func (c *ListCommand) runFilters() bool {
+	return c.failedOnly || c.notRunSince > 0
+}
+
+// latestBuildRuns returns the most recently created BuildRun of each Build, by Build name.
+func (c *ListCommand) latestBuildRuns(params *params.Params) (map[string]*buildv1alpha1.BuildRun, error) {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return nil, err
+	}
+	latest := map[string]*buildv1alpha1.BuildRun{}
+	_, err = util.ListBuildRunPages(c.cmd.Context(), clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).List,
+		metav1.ListOptions{}, 0, util.DefaultChunkSize, func(page *buildv1alpha1.BuildRunList) error {
+			for i := range page.Items {
+				br := &page.Items[i]
+				name := br.GetLabels()[buildv1alpha1.LabelBuild]
+				if name == "" {
+					continue
+				}
+				if l, ok := latest[name]; !ok || l.CreationTimestamp.Before(&br.CreationTimestamp) {
+					latest[name] = br
+				}
+			}
+			return nil
+		})
+	return latest, err
+}
+
+// matches tells whether the Build matches the health filters informed.
+func (c *ListCommand) matches(b *buildv1alpha1.Build, latest *buildv1alpha1.BuildRun) bool {
+	if c.cmd.Flags().Changed("registered") {
+		registered := b.Status.Registered != nil && *b.Status.Registered == corev1.ConditionTrue
+		if registered != c.registered {
+			return false
+		}
+	}
+	if c.failedOnly && (latest == nil || util.PhaseOf(latest) != util.PhaseFailed) {
+		return false
+	}
+	if c.notRunSince > 0 && latest != nil && latest.CreationTimestamp.Time.After(c.now().Add(-c.notRunSince)) {
+		return false
+	}
+	return true
+}
+
+// lastRun describes the BuildRun for the "LAST RUN" column.
+func (c *ListCommand) lastRun(br *buildv1alpha1.BuildRun) string {
+	if br == nil {
+		return "never"
+	}
+	age := duration.HumanDuration(c.now().Sub(br.CreationTimestamp.Time))
+	return fmt.Sprintf("%s (%s, %s ago)", br.GetName(), util.PhaseOf(br), age)
 }
 
 // Run contains main logic of List subcommand of Build
 func (c *ListCommand) Run(params *params.Params, io *genericclioptions.IOStreams) error {
-	// TODO: Support multiple output formats here, not only tabwriter
-	//       find out more in kubectl libraries and use them
-
 	// Initialize tabwriter for command output
 	writer := tabwriter.NewWriter(io.Out, 0, 8, 2, '\t', 0)
 	columnNames := "NAME\tOUTPUT\tSTATUS"
@@ -81,10 +176,43 @@ func (c *ListCommand) Run(params *params.Params, io *genericclioptions.IOStreams
 	if buildList, err = clientset.ShipwrightV1alpha1().Builds(params.Namespace()).List(c.cmd.Context(), metav1.ListOptions{}); err != nil {
 		return err
 	}
+	latest := map[string]*buildv1alpha1.BuildRun{}
+	if c.runFilters() {
+		if latest, err = c.latestBuildRuns(params); err != nil {
+			return err
+		}
+	}
+	filtered := c.cmd.Flags().Changed("registered") || c.runFilters()
+	if filtered {
+		items := []buildv1alpha1.Build{}
+		for i := range buildList.Items {
+			if c.matches(&buildList.Items[i], latest[buildList.Items[i].GetName()]) {
+				items = append(items, buildList.Items[i])
+			}
+		}
+		buildList.Items = items
+	}
+	if params.Quiet() {
+		for _, b := range buildList.Items {
+			fmt.Fprintln(io.Out, b.Name)
+		}
+		return nil
+	}
+	if !c.output.Table() {
+		return c.output.PrintList(buildList, c.noHeader, io.Out)
+	}
 	if len(buildList.Items) == 0 {
+		if filtered {
+			fmt.Fprintf(io.Out, "No builds matching the filters found in namespace '%s'.\n", params.Namespace())
+			return nil
+		}
 		fmt.Fprintf(io.Out, "No builds found in namespace '%s'. Please create a build or verify the namespace.\n", params.Namespace())
 		return nil
 	}
+	if c.runFilters() {
+		columnNames += "\tLAST RUN"
+		columnTemplate = "%s\t%s\t%s\t%s\n"
+	}
 
 	if !c.noHeader {
 		fmt.Fprintln(writer, columnNames)
@@ -95,7 +223,18 @@ func (c *ListCommand) Run(params *params.Params, io *genericclioptions.IOStreams
 		if b.Status.Message != nil {
 			message = *b.Status.Message
 		}
-		fmt.Fprintf(writer, columnTemplate, b.Name, b.Spec.Output.Image, message)
+		// the reason tells the validation error apart at a glance
+		if b.Status.Reason != nil && *b.Status.Reason != "" && *b.Status.Reason != buildv1alpha1.SucceedStatus {
+			message = fmt.Sprintf("%s: %s", *b.Status.Reason, message)
+		}
+		if b.Status.Registered != nil {
+			message = styles.Condition(*b.Status.Registered, message)
+		}
+		if c.runFilters() {
+			fmt.Fprintf(writer, columnTemplate, styles.Bold(b.Name), b.Spec.Output.Image, message, c.lastRun(latest[b.Name]))
+			continue
+		}
+		fmt.Fprintf(writer, columnTemplate, styles.Bold(b.Name), b.Spec.Output.Image, message)
 	}
 
 	return writer.Flush()
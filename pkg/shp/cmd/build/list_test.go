@@ -0,0 +1,136 @@
+package build
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestListBuildsHealthFilters(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	now := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+
+	build := func(name string, registered corev1.ConditionStatus, reason buildv1alpha1.BuildReason, message string) *buildv1alpha1.Build {
+		return &buildv1alpha1.Build{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Status: buildv1alpha1.BuildStatus{
+				Registered: &registered,
+				Reason:     buildv1alpha1.BuildReasonPtr(reason),
+				Message:    pointer.String(message),
+			},
+		}
+	}
+	buildRun := func(name, build string, age time.Duration, succeeded corev1.ConditionStatus) *buildv1alpha1.BuildRun {
+		br := &buildv1alpha1.BuildRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         ns,
+				Name:              name,
+				Labels:            map[string]string{buildv1alpha1.LabelBuild: build},
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+		}
+		br.Status.Conditions = buildv1alpha1.Conditions{{Type: buildv1alpha1.Succeeded, Status: succeeded}}
+		return br
+	}
+	objects := []runtime.Object{
+		build("healthy", corev1.ConditionTrue, buildv1alpha1.SucceedStatus, "all validations succeeded"),
+		build("broken", corev1.ConditionFalse, buildv1alpha1.BuildStrategyNotFound, "strategy not found"),
+		build("failing", corev1.ConditionTrue, buildv1alpha1.SucceedStatus, "all validations succeeded"),
+		build("stale", corev1.ConditionTrue, buildv1alpha1.SucceedStatus, "all validations succeeded"),
+		buildRun("healthy-1", "healthy", time.Hour, corev1.ConditionTrue),
+		buildRun("failing-1", "failing", 2*time.Hour, corev1.ConditionTrue),
+		buildRun("failing-2", "failing", time.Hour, corev1.ConditionFalse),
+		buildRun("stale-1", "stale", 10*24*time.Hour, corev1.ConditionFalse),
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		builds   []string
+		contains []string
+	}{{
+		name:   "failed only",
+		args:   []string{"--failed-only"},
+		builds: []string{"failing", "stale"},
+		contains: []string{
+			"LAST RUN",
+			"failing-2 (Failed, 60m ago)",
+			"stale-1 (Failed, 10d ago)",
+		},
+	}, {
+		name:     "not run since",
+		args:     []string{"--not-run-since=7d"},
+		builds:   []string{"broken", "stale"},
+		contains: []string{"never"},
+	}, {
+		name:     "not registered",
+		args:     []string{"--registered=false"},
+		builds:   []string{"broken"},
+		contains: []string{"BuildStrategyNotFound: strategy not found"},
+	}, {
+		name:   "registered",
+		args:   []string{"--registered"},
+		builds: []string{"healthy", "failing", "stale"},
+	}, {
+		name:   "combined",
+		args:   []string{"--failed-only", "--not-run-since=168h"},
+		builds: []string{"stale"},
+	}, {
+		name:     "none matching",
+		args:     []string{"--registered=false", "--failed-only"},
+		contains: []string{"No builds matching the filters found in namespace 'default'."},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			clientset := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+			p := params.NewParamsForTest(clientset, shpfake.NewSimpleClientset(objects...), nil, ns, nil, nil)
+			ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+			cmd := listCmd().(*ListCommand)
+			cmd.now = func() time.Time { return now }
+			cmd.cmd.SetContext(context.TODO())
+			g.Expect(cmd.cmd.ParseFlags(tt.args)).To(o.Succeed())
+			g.Expect(cmd.Complete(p, &ioStreams, nil)).To(o.Succeed())
+			g.Expect(cmd.Validate()).To(o.Succeed())
+			g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+
+			for _, name := range []string{"healthy", "broken", "failing", "stale"} {
+				matcher := o.MatchRegexp(`(?m)^` + name + `\s`)
+				if contains(tt.builds, name) {
+					g.Expect(out.String()).To(matcher)
+				} else {
+					g.Expect(out.String()).NotTo(matcher)
+				}
+			}
+			for _, s := range tt.contains {
+				g.Expect(out.String()).To(o.ContainSubstring(s))
+			}
+		})
+	}
+}
+
+// contains tells whether the slice carries the informed string.
+func contains(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
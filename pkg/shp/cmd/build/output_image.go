@@ -0,0 +1,116 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// outputImageTimestampFormat format of the {{.Timestamp}} token, valid on image tags.
+const outputImageTimestampFormat = "20060102150405"
+
+// shortGitSHALength amount of characters of the {{.ShortGitSHA}} token.
+const shortGitSHALength = 7
+
+// gitSHARegexp matches a full git commit SHA.
+var gitSHARegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// outputImageData values of the tokens expanded on the --output-image template, the git commit SHA
+// is only resolved when referenced.
+type outputImageData struct {
+	BuildName string // name of the Build being run
+	Namespace string // namespace of the BuildRun
+	Timestamp string // moment of the run, UTC, formatted as "20060102150405"
+
+	gitSHA func() (string, error) // resolves the git commit SHA of the source
+}
+
+// GitSHA returns the git commit SHA of the source.
+func (d *outputImageData) GitSHA() (string, error) {
+	return d.gitSHA()
+}
+
+// ShortGitSHA returns the abbreviated git commit SHA of the source.
+func (d *outputImageData) ShortGitSHA() (string, error) {
+	sha, err := d.gitSHA()
+	if err != nil || len(sha) <= shortGitSHALength {
+		return sha, err
+	}
+	return sha[:shortGitSHALength], nil
+}
+
+// isOutputImageTemplate tells whether the output image carries template tokens.
+func isOutputImageTemplate(image string) bool {
+	return strings.Contains(image, "{{")
+}
+
+// expandOutputImage expands the template tokens of the output image, making sure the outcome is a
+// valid image reference.
+func expandOutputImage(image string, data *outputImageData) (string, error) {
+	tmpl, err := template.New("output-image").Parse(image)
+	if err != nil {
+		return "", fmt.Errorf("invalid output image template %q: %w", image, err)
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("unable to expand output image template %q: %w", image, err)
+	}
+	expanded := buf.String()
+	if _, err = name.ParseReference(expanded); err != nil {
+		return "", fmt.Errorf("output image template %q expands to an invalid image %q: %w", image, expanded, err)
+	}
+	return expanded, nil
+}
+
+// validateOutputImageTemplate expands the template with placeholder values, thus unknown tokens and
+// invalid references are reported before creating the BuildRun.
+func validateOutputImageTemplate(image string) error {
+	_, err := expandOutputImage(image, &outputImageData{
+		BuildName: "build",
+		Namespace: "namespace",
+		Timestamp: time.Time{}.Format(outputImageTimestampFormat),
+		gitSHA: func() (string, error) {
+			return strings.Repeat("0", 40), nil
+		},
+	})
+	return err
+}
+
+// resolveGitSHA returns the git commit SHA of the source, either the revision informed when it's a
+// commit SHA already, or the HEAD of the local git repository on the informed directory.
+func resolveGitSHA(ctx context.Context, revision, dir string) (string, error) {
+	if gitSHARegexp.MatchString(revision) {
+		return revision, nil
+	}
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve the git commit SHA on %q, a local git repository is required: %w", dir, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// expandOutputImage expands the template tokens of the output image informed for this run, the git
+// commit SHA is resolved on the local source directory, or on the current directory otherwise.
+func (r *RunCommand) expandOutputImage(ctx context.Context, image string) (string, error) {
+	dir := "."
+	if r.usesSourceBundle() {
+		dir = r.sourceBundleDir
+	}
+	return expandOutputImage(image, &outputImageData{
+		BuildName: r.buildName,
+		Namespace: r.namespace,
+		Timestamp: time.Now().UTC().Format(outputImageTimestampFormat),
+		gitSHA: func() (string, error) {
+			return resolveGitSHA(ctx, r.sourceOverride.Revision, dir)
+		},
+	})
+}
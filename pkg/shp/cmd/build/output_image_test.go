@@ -0,0 +1,82 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestExpandOutputImage(t *testing.T) {
+	g := o.NewWithT(t)
+
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	data := &outputImageData{
+		BuildName: "my-app",
+		Namespace: "dev",
+		Timestamp: "20240102030405",
+		gitSHA: func() (string, error) {
+			return sha, nil
+		},
+	}
+
+	image, err := expandOutputImage("ghcr.io/org/{{.BuildName}}:{{.ShortGitSHA}}-{{.Timestamp}}", data)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(image).To(o.Equal("ghcr.io/org/my-app:0123456-20240102030405"))
+
+	image, err = expandOutputImage("registry.local/{{.Namespace}}/app:{{.GitSHA}}", data)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(image).To(o.Equal("registry.local/dev/app:" + sha))
+
+	_, err = expandOutputImage("ghcr.io/org/app:{{.Branch}}", data)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring("can't evaluate field Branch")))
+
+	_, err = expandOutputImage("ghcr.io/org/app:{{.BuildName", data)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring("invalid output image template")))
+
+	_, err = expandOutputImage("ghcr.io/org/app:{{.BuildName}}:{{.Timestamp}}", data)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`expands to an invalid image "ghcr.io/org/app:my-app:20240102030405"`)))
+
+	data.gitSHA = func() (string, error) {
+		return "", errors.New("not a git repository")
+	}
+	_, err = expandOutputImage("ghcr.io/org/app:{{.GitSHA}}", data)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring("not a git repository")))
+
+	g.Expect(validateOutputImageTemplate("ghcr.io/org/app:{{.ShortGitSHA}}")).To(o.Succeed())
+	g.Expect(validateOutputImageTemplate("ghcr.io/org/app:{{.Unknown}}")).ToNot(o.Succeed())
+}
+
+func TestResolveGitSHA(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.TODO()
+
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	resolved, err := resolveGitSHA(ctx, sha, t.TempDir())
+	g.Expect(err).To(o.BeNil())
+	g.Expect(resolved).To(o.Equal(sha))
+
+	dir := t.TempDir()
+	_, err = resolveGitSHA(ctx, "main", dir)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring("a local git repository is required")))
+
+	if _, err = exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=shp", "-c", "user.email=shp@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		g.Expect(err).To(o.BeNil(), string(out))
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "--quiet")
+	git("commit", "--quiet", "--allow-empty", "-m", "initial")
+
+	resolved, err = resolveGitSHA(ctx, "main", dir)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(resolved).To(o.Equal(git("rev-parse", "HEAD")))
+}
@@ -0,0 +1,372 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/attest"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/follower"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// platformRun the BuildRun created for a single platform, and its outcome.
+type platformRun struct {
+	platform  v1.Platform
+	name      string
+	created   time.Time                    // BuildRun creation, the timeout is counted from it
+	ioStreams *genericclioptions.IOStreams // streams prefixed by the platform
+	writers   []*tail.PrefixWriter         // flushed once the BuildRun is done
+	image     name.Digest                  // image produced, when succeeded
+	err       error                        // outcome, nil when succeeded
+}
+
+// flush writes the partial lines left on the prefixed streams.
+func (p *platformRun) flush() {
+	for _, w := range p.writers {
+		_ = w.Flush()
+	}
+}
+
+// validatePlatforms checks the multi-platform flags are consistent with the others.
+func (r *RunCommand) validatePlatforms() error {
+	if r.multiPlatform.IsEmpty() {
+		if r.multiPlatform.ManifestList || r.cmd.Flags().Changed(flags.PlatformParamFlag) {
+			return fmt.Errorf("--%s must be informed when using the other platform flags", flags.PlatformsFlag)
+		}
+		return nil
+	}
+	if _, err := registry.ParsePlatforms(r.multiPlatform.Platforms); err != nil {
+		return err
+	}
+	if r.multiPlatform.Param == "" {
+		return fmt.Errorf("--%s must not be empty", flags.PlatformParamFlag)
+	}
+	for _, pv := range r.buildRunSpec.ParamValues {
+		if pv.Name == r.multiPlatform.Param {
+			return fmt.Errorf("parameter %q is set by --%s, it can't be informed along with --%s",
+				pv.Name, flags.PlatformParamFlag, flags.PlatformsFlag)
+		}
+	}
+	switch {
+	case r.ui:
+		return fmt.Errorf("--ui can't be used along with --%s", flags.PlatformsFlag)
+	case r.showMetrics:
+		return fmt.Errorf("--show-metrics can't be used along with --%s", flags.PlatformsFlag)
+	case r.attest != "":
+		return fmt.Errorf("--attest can't be used along with --%s", flags.PlatformsFlag)
+	case r.sign:
+		return fmt.Errorf("--sign can't be used along with --%s", flags.PlatformsFlag)
+	case len(r.localPorts) > 0:
+		return fmt.Errorf("--%s can't be used along with --%s", flags.LocalPortForwardFlag, flags.PlatformsFlag)
+	case len(r.additionalOutputs) > 0 && !r.multiPlatform.ManifestList:
+		return fmt.Errorf("--%s requires --%s along with --%s", flags.AdditionalOutputImageFlag, flags.ManifestListFlag, flags.PlatformsFlag)
+	case r.imageDigestFile != "" && !r.multiPlatform.ManifestList:
+		return fmt.Errorf("--image-digest-file requires --%s along with --%s", flags.ManifestListFlag, flags.PlatformsFlag)
+	case r.multiPlatform.ManifestList && !r.follow && !r.wait:
+		return fmt.Errorf("--%s requires --follow or --wait", flags.ManifestListFlag)
+	}
+	return nil
+}
+
+// runPlatforms creates one BuildRun per platform out of the informed BuildRun, passing the platform
+// on the strategy parameter, and follows or waits for them concurrently. With the manifest list
+// requested, each platform pushes to its own tag, and the images are stitched afterwards.
+func (r *RunCommand) runPlatforms(
+	params *params.Params,
+	ioStreams *genericclioptions.IOStreams,
+	clientset buildclientset.Interface,
+	base *buildv1alpha1.BuildRun,
+) error {
+	ctx := r.cmd.Context()
+	platforms, err := registry.ParsePlatforms(r.multiPlatform.Platforms)
+	if err != nil {
+		return err
+	}
+	var output *buildv1alpha1.Image
+	if r.multiPlatform.ManifestList {
+		if output, err = r.outputOf(ctx, clientset, base); err != nil {
+			return err
+		}
+	}
+
+	lock := &sync.Mutex{}
+	runs := make([]*platformRun, 0, len(platforms))
+	for _, p := range platforms {
+		br := base.DeepCopy()
+		suffix := registry.PlatformSuffix(p)
+		if br.GetName() != "" {
+			br.SetName(fmt.Sprintf("%s-%s", br.GetName(), suffix))
+		} else {
+			br.SetGenerateName(fmt.Sprintf("%s%s-", br.GetGenerateName(), suffix))
+		}
+		value := p.String()
+		br.Spec.ParamValues = append(br.Spec.ParamValues, buildv1alpha1.ParamValue{
+			Name:        r.multiPlatform.Param,
+			SingleValue: &buildv1alpha1.SingleValue{Value: &value},
+		})
+		if output != nil {
+			br.Spec.Output = output.DeepCopy()
+			if br.Spec.Output.Image, err = registry.PlatformImageTag(output.Image, p); err != nil {
+				return err
+			}
+		}
+		if br, err = createNamedBuildRun(ctx, clientset, r.namespace, br, r.naming, r.recorder, ioStreams.ErrOut); err != nil {
+			return err
+		}
+
+		prefix := styles.Prefix(fmt.Sprintf("[%s]", p.String())) + " "
+		out := tail.NewPrefixWriter(ioStreams.Out, lock, prefix)
+		errOut := tail.NewPrefixWriter(ioStreams.ErrOut, lock, prefix)
+		runs = append(runs, &platformRun{
+			platform:  p,
+			name:      br.GetName(),
+			created:   br.GetCreationTimestamp().Time,
+			ioStreams: &genericclioptions.IOStreams{In: ioStreams.In, Out: out, ErrOut: errOut},
+			writers:   []*tail.PrefixWriter{out, errOut},
+		})
+	}
+
+	for _, run := range runs {
+		if params.Quiet() {
+			fmt.Fprintln(ioStreams.Out, run.name)
+		} else {
+			fmt.Fprintf(ioStreams.Out, "BuildRun created %q for build %q on platform %q\n", run.name, r.buildName, run.platform.String())
+		}
+	}
+	if params.Quiet() {
+		for _, run := range runs {
+			run.ioStreams = params.QuietStreams(run.ioStreams)
+		}
+	}
+
+	var interrupted bool
+	switch {
+	case r.follow:
+		interrupted, err = r.followPlatforms(params, runs)
+	case r.wait:
+		interrupted = r.waitPlatforms(params, clientset, runs)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if interrupted {
+		return r.handlePlatformsInterrupt(clientset, ioStreams, runs)
+	}
+
+	for _, run := range runs {
+		if run.err == nil {
+			run.image, run.err = r.completePlatformRun(clientset, run)
+		}
+		run.flush()
+	}
+	if err = platformsOutcome(runs); err != nil {
+		return err
+	}
+	if output == nil {
+		return nil
+	}
+	return r.pushManifestList(params, ioStreams, output, runs)
+}
+
+// outputOf returns the output image of the BuildRun, either informed by flags or the Build's.
+func (r *RunCommand) outputOf(
+	ctx context.Context,
+	clientset buildclientset.Interface,
+	br *buildv1alpha1.BuildRun,
+) (*buildv1alpha1.Image, error) {
+	switch {
+	case br.Spec.Output != nil && br.Spec.Output.Image != "":
+		return br.Spec.Output, nil
+	case br.Spec.BuildSpec != nil:
+		return &br.Spec.BuildSpec.Output, nil
+	}
+	b, err := clientset.ShipwrightV1alpha1().Builds(r.namespace).Get(ctx, r.buildName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &b.Spec.Output, nil
+}
+
+// followPlatforms follows the logs of every BuildRun concurrently, each line prefixed by the
+// platform. Returns whether the command has been interrupted.
+func (r *RunCommand) followPlatforms(params *params.Params, runs []*platformRun) (bool, error) {
+	followers := make([]*follower.Follower, 0, len(runs))
+	for _, run := range runs {
+		brName := types.NamespacedName{Namespace: r.namespace, Name: run.name}
+		f, err := params.NewDedicatedFollower(r.cmd.Context(), brName, run.ioStreams)
+		if err != nil {
+			return false, err
+		}
+		f.SetMaxLogRate(r.maxLogRate)
+		f.SetLineFilter(r.logFilter.LineFilter())
+		f.SetHeartbeat(r.heartbeat)
+		if timeout := r.buildRunSpec.Timeout; timeout != nil && timeout.Duration > 0 {
+			f.SetTimeout(run.created, timeout.Duration)
+		}
+		listOpts := reactor.BuildRunPodListOptions(run.name)
+		if err = f.Connect(listOpts); err != nil {
+			return false, err
+		}
+		followers = append(followers, f)
+	}
+	r.markFollowerReady()
+
+	interrupt := r.notifyInterrupt(func() {
+		for _, f := range followers {
+			f.Stop()
+		}
+	})
+	defer interrupt.stop()
+
+	var wg sync.WaitGroup
+	for i := range runs {
+		wg.Add(1)
+		go func(run *platformRun, f *follower.Follower) {
+			defer wg.Done()
+			if _, err := f.WaitForCompletion(); err != nil {
+				run.err = err
+				return
+			}
+			if !f.PodSucceeded() {
+				run.err = exitcode.Errorf(exitcode.Failure, "BuildRun %q has failed", run.name)
+			}
+		}(runs[i], followers[i])
+	}
+	wg.Wait()
+	return interrupt.interrupted(), nil
+}
+
+// waitPlatforms waits for every BuildRun concurrently. Returns whether the command has been
+// interrupted.
+func (r *RunCommand) waitPlatforms(params *params.Params, clientset buildclientset.Interface, runs []*platformRun) bool {
+	ctx, cancel := context.WithCancel(r.cmd.Context())
+	defer cancel()
+	interrupt := r.notifyInterrupt(cancel)
+	defer interrupt.stop()
+
+	var wg sync.WaitGroup
+	for _, run := range runs {
+		wg.Add(1)
+		go func(run *platformRun) {
+			defer wg.Done()
+			run.err = r.waitForBuildRun(ctx, params, clientset, run.ioStreams, run.name)
+		}(run)
+	}
+	wg.Wait()
+	return interrupt.interrupted()
+}
+
+// completePlatformRun obtains the image produced by a successful BuildRun, and reports its
+// vulnerabilities.
+func (r *RunCommand) completePlatformRun(clientset buildclientset.Interface, run *platformRun) (name.Digest, error) {
+	// the BuildRun status may lag behind the completion of the build pod
+	br, err := util.WaitForBuildRunDone(r.cmd.Context(), clientset, r.namespace, run.name, buildRunDonePollInterval, buildRunDonePollTimeout)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("unable to obtain the final state of BuildRun %q: %w", run.name, err)
+	}
+	image, err := attest.ImageDigestReference(br)
+	if err != nil {
+		if r.multiPlatform.ManifestList {
+			return name.Digest{}, err
+		}
+		fmt.Fprintf(run.ioStreams.ErrOut, "Warning: %s\n", err)
+	} else {
+		fmt.Fprintf(run.ioStreams.Out, "BuildRun %q produced image %s\n", run.name, image.String())
+	}
+	return image, r.reportVulnerabilities(clientset, run.ioStreams, run.name)
+}
+
+// handlePlatformsInterrupt decides the fate of every BuildRun after the command is interrupted.
+func (r *RunCommand) handlePlatformsInterrupt(
+	clientset buildclientset.Interface,
+	ioStreams *genericclioptions.IOStreams,
+	runs []*platformRun,
+) error {
+	var err error
+	for _, run := range runs {
+		run.flush()
+		if runErr := r.handleInterrupt(clientset, ioStreams, run.name); err == nil {
+			err = runErr
+		}
+	}
+	return err
+}
+
+// platformsOutcome summarizes the failed platforms, the exit code is taken from the first failure.
+func platformsOutcome(runs []*platformRun) error {
+	var first error
+	failures := []string{}
+	for _, run := range runs {
+		if run.err == nil {
+			continue
+		}
+		if first == nil {
+			first = run.err
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s", run.platform.String(), run.err))
+	}
+	if first == nil {
+		return nil
+	}
+	return exitcode.Wrap(exitcode.FromError(first), fmt.Errorf("%d of %d platforms failed, %s",
+		len(failures), len(runs), strings.Join(failures, "; ")))
+}
+
+// pushManifestList stitches the images produced for each platform into the manifest list tagged as
+// the output image, copied to the additional output images afterwards.
+func (r *RunCommand) pushManifestList(
+	params *params.Params,
+	ioStreams *genericclioptions.IOStreams,
+	output *buildv1alpha1.Image,
+	runs []*platformRun,
+) error {
+	var outputSecret string
+	if output.Credentials != nil {
+		outputSecret = output.Credentials.Name
+	}
+	keychain, err := registryKeychain(r.cmd.Context(), params, r.registryAuth, r.registrySecret, outputSecret)
+	if err != nil {
+		return err
+	}
+	images := make([]registry.PlatformImage, 0, len(runs))
+	for _, run := range runs {
+		images = append(images, registry.PlatformImage{Platform: run.platform, Image: run.image})
+	}
+	digest, err := registry.PushManifestList(r.cmd.Context(), output.Image, images, keychain)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ioStreams.Out, "Manifest list %s pushed for platforms %s\n", digest.String(), strings.Join(r.multiPlatform.Platforms, ", "))
+	if r.imageDigestFile != "" {
+		if err = os.WriteFile(r.imageDigestFile, []byte(digest.String()+"\n"), 0o600); err != nil {
+			return err
+		}
+	}
+	if len(r.additionalOutputs) == 0 {
+		return nil
+	}
+	return r.copyOutputImage(params, ioStreams, digest, outputSecret)
+}
@@ -0,0 +1,90 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+)
+
+// defaultPushSecret name of the push secret created when neither the Build output credentials nor
+// --push-secret are informed.
+func defaultPushSecret(buildName string) string {
+	return fmt.Sprintf("%s-push", buildName)
+}
+
+// refreshPushSecret mints the credentials for the output image registry and creates, or updates,
+// the push secret with them. When the secret is not already the output credentials of the Build,
+// the BuildRun overrides the output to employ it.
+func (r *RunCommand) refreshPushSecret(
+	ctx context.Context,
+	p *params.Params,
+	ioStreams *genericclioptions.IOStreams,
+	clientset buildclientset.Interface,
+	br *buildv1alpha1.BuildRun,
+) error {
+	output := br.Spec.Output
+	if output == nil {
+		b, err := clientset.ShipwrightV1alpha1().Builds(r.namespace).Get(ctx, r.buildName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		output = b.Spec.Output.DeepCopy()
+	}
+	ref, err := name.ParseReference(output.Image)
+	if err != nil {
+		return fmt.Errorf("unable to parse the output image %q: %w", output.Image, err)
+	}
+	host := ref.Context().RegistryStr()
+
+	secretName := r.pushSecret
+	if secretName == "" && output.Credentials != nil {
+		secretName = output.Credentials.Name
+	}
+	if secretName == "" {
+		secretName = defaultPushSecret(r.buildName)
+	}
+
+	provider, err := registry.ProviderFor(r.pushCredentialsProvider, host)
+	if err != nil {
+		return err
+	}
+	credentials, err := provider.Mint(ctx, host)
+	if err != nil {
+		return fmt.Errorf("unable to mint %s credentials for %q: %w", provider.Name(), host, err)
+	}
+	kubeClientset, err := p.ClientSet()
+	if err != nil {
+		return err
+	}
+	created, err := registry.ApplySecret(ctx, kubeClientset, r.namespace, secretName, host, credentials)
+	if err != nil {
+		return err
+	}
+	action := "Refreshed"
+	if created {
+		action = "Created"
+	}
+	expiration := ""
+	if !credentials.ExpiresAt.IsZero() {
+		expiration = fmt.Sprintf(", valid until %s", credentials.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(ioStreams.Out, "%s push secret %q with %s credentials for %q%s\n",
+		action, secretName, provider.Name(), host, expiration)
+
+	if output.Credentials == nil || output.Credentials.Name != secretName {
+		output.Credentials = &corev1.LocalObjectReference{Name: secretName}
+		br.Spec.Output = output
+	}
+	return nil
+}
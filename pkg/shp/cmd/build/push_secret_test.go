@@ -0,0 +1,94 @@
+package build
+
+import (
+	"context"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+)
+
+// fakeProvider credential provider issuing a fixed token for "registry.example.com".
+type fakeProvider struct{}
+
+func (fakeProvider) Name() registry.AuthSource {
+	return "example"
+}
+
+func (fakeProvider) Matches(host string) bool {
+	return host == "registry.example.com"
+}
+
+func (fakeProvider) Mint(context.Context, string) (*registry.Credentials, error) {
+	return &registry.Credentials{Username: "user", Password: "token"}, nil
+}
+
+func TestRefreshPushSecret(t *testing.T) {
+	g := o.NewWithT(t)
+
+	registry.RegisterProvider(fakeProvider{})
+
+	ctx := context.TODO()
+	build := &buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "app"},
+		Spec: buildv1alpha1.BuildSpec{
+			Output: buildv1alpha1.Image{
+				Image:       "registry.example.com/org/app:latest",
+				Credentials: &corev1.LocalObjectReference{Name: "app-credentials"},
+			},
+		},
+	}
+	clientset := fake.NewSimpleClientset()
+	shpClientset := shpfake.NewSimpleClientset(build)
+	p := params.NewParamsForTest(clientset, shpClientset, nil, metav1.NamespaceDefault, nil, nil)
+
+	refresh := func(provider, secret string, br *buildv1alpha1.BuildRun) (string, error) {
+		r := &RunCommand{
+			buildName:               "app",
+			namespace:               metav1.NamespaceDefault,
+			pushCredentialsProvider: provider,
+			pushSecret:              secret,
+		}
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		err := r.refreshPushSecret(ctx, p, &ioStreams, shpClientset, br)
+		return out.String(), err
+	}
+
+	// the Build output credentials are refreshed, thus the BuildRun doesn't override the output
+	br := &buildv1alpha1.BuildRun{}
+	out, err := refresh(registry.ProviderAuto, "", br)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).To(o.Equal("Created push secret \"app-credentials\" with example credentials for \"registry.example.com\"\n"))
+	g.Expect(br.Spec.Output).To(o.BeNil())
+
+	secret, err := clientset.CoreV1().Secrets(metav1.NamespaceDefault).Get(ctx, "app-credentials", metav1.GetOptions{})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(string(secret.Data[corev1.DockerConfigJsonKey])).To(o.ContainSubstring(`"registry.example.com":{"username":"user","password":"token"`))
+
+	out, err = refresh(registry.ProviderAuto, "", br)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).To(o.HavePrefix("Refreshed push secret \"app-credentials\""))
+
+	// a different secret is wired as the BuildRun output credentials
+	out, err = refresh("example", "ci-push", br)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).To(o.HavePrefix("Created push secret \"ci-push\""))
+	g.Expect(br.Spec.Output).NotTo(o.BeNil())
+	g.Expect(br.Spec.Output.Image).To(o.Equal("registry.example.com/org/app:latest"))
+	g.Expect(br.Spec.Output.Credentials.Name).To(o.Equal("ci-push"))
+
+	// the output image override decides the registry
+	br = &buildv1alpha1.BuildRun{Spec: buildv1alpha1.BuildRunSpec{Output: &buildv1alpha1.Image{Image: "ghcr.io/org/app:v1"}}}
+	_, err = refresh(registry.ProviderAuto, "", br)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`no credential provider matches registry "ghcr.io"`)))
+}
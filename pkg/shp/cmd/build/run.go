@@ -1,19 +1,45 @@
 package build
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+	"github.com/shipwright-io/cli/pkg/shp/attest"
+	"github.com/shipwright-io/cli/pkg/shp/audit"
+	"github.com/shipwright-io/cli/pkg/shp/bundle"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/follower"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/version"
+	"github.com/shipwright-io/cli/pkg/shp/debug"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
 	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/metrics"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+	"github.com/shipwright-io/cli/pkg/shp/trace"
+	"github.com/shipwright-io/cli/pkg/shp/tui"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+	"github.com/shipwright-io/cli/pkg/shp/vulnerability"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -25,9 +51,65 @@ type RunCommand struct {
 	buildName     string
 	namespace     string
 	buildRunSpec  *buildv1alpha1.BuildRunSpec // stores command-line flags
+	naming        *flags.BuildRunNaming       // controls the BuildRun name
+	metadata      *flags.ObjectMetadata       // BuildRun labels, annotations and ownership
 	follow        bool                        // flag to tail pod logs
 	follower      *follower.Follower
 	followerReady chan bool
+	readyOnce     sync.Once     // closes followerReady once, the retries follow more than one BuildRun
+	wait          bool          // flag to wait for the BuildRun to finish
+	waitTimeout   time.Duration // maximum amount of time to wait for the BuildRun
+
+	after                    string // BuildRun which must succeed before the build is started
+	abortOnDependencyFailure bool   // the build is not started when the --after BuildRun fails
+
+	retries      int           // fresh BuildRuns created when the run fails, zero disables it
+	retryOn      []string      // failures retried, either "failure" or "timeout"
+	retryBackoff time.Duration // wait before the first retry, doubled every retry
+
+	imageDigestFile   string    // file path to write the produced image digest reference
+	additionalOutputs []string  // tagged images the output image is copied to after succeeding
+	envOut            io.Writer // receives the shell variables of the BuildRun outcome, "-o env"
+	failureLogLines   int       // amount of log lines of the failed step printed after waiting
+
+	showMetrics   bool                      // flag to print the metrics summary after following
+	metricsOutput string                    // metrics summary format
+	tracker       *reactor.ContainerTracker // records the build pod container transitions
+	ui            bool                      // flag to follow the logs on a full-screen terminal view
+	maxLogRate    int64                     // log bytes per second printed while following
+	logFilter     flags.LogFilter           // selects the log lines printed while following
+	heartbeat     time.Duration             // quiet period before the running step is reported
+	localPorts    []string                  // local ports forwarded to the build pod while following
+
+	sourceBundle    *buildv1alpha1.BundleContainer // source bundle image packed from a local directory
+	sourceBundleDir string                         // local directory packed into the source bundle
+	registryAuth    string                         // source of the registry credentials to push the bundle
+	registrySecret  string                         // docker-registry secret name to push the bundle
+	compression     string                         // compression of the source bundle layer
+	compressLevel   int                            // compression level, zero means the algorithm default
+	sourceOverride  flags.SourceOverride           // git revision and context directory overrides
+
+	pushCredentialsProvider string // provider minting the output registry credentials
+	pushSecret              string // push secret refreshed with the minted credentials
+
+	attest     string // attestation type generated after a successful run
+	attestFile string // file path to write the attestation statement
+	attestSign bool   // sign and attach the attestation with cosign
+	attestKey  string // cosign key reference
+
+	sign    bool   // sign the output image digest with cosign
+	signKey string // cosign key reference to sign the image
+
+	failOn string // vulnerability severity failing the run
+
+	multiPlatform flags.MultiPlatform // platforms to build for, one BuildRun per platform
+	batch         flags.Batch         // Builds run at once, instead of the informed Build
+
+	cancelOnInterrupt bool           // cancel the BuildRun when interrupted, instead of asking
+	signalCh          chan os.Signal // interrupt signals, intercepted from the process when nil
+
+	audit    bool            // record who triggered the BuildRuns
+	recorder *audit.Recorder // annotates the BuildRuns and records the Events, when auditing
 }
 
 const buildRunLongDesc = `
@@ -35,8 +117,184 @@ Creates a unique BuildRun instance for the given Build, which starts the build
 process orchestrated by the Shipwright build controller. For example:
 
 	$ shp build run my-app
+
+To block until the BuildRun is finished without streaming the logs, use --wait. The exit code
+tells the outcome: 0 when succeeded, 1 when failed, 2 on timeout, and 3 when cancelled.
+
+	$ shp build run my-app --wait --wait-timeout=30m
+
+When the waited BuildRun fails, the last lines of the failed step are printed, the amount of lines
+is controlled by --failure-log-lines, zero disables it.
+
+After a successful run, followed or waited, the fully qualified reference of the produced image,
+"registry/repository@sha256:...", is printed and can be written to a file for downstream steps:
+
+	$ shp build run my-app --follow --image-digest-file=image-ref.txt
+
+The output image can be overridden for a single run with --output-image, expanding the template
+tokens {{.BuildName}}, {{.Namespace}}, {{.Timestamp}} (UTC, "20060102150405"), {{.GitSHA}} and
+{{.ShortGitSHA}}, thus each run pushes an uniquely tagged image without modifying the Build. The git
+commit SHA is taken from --source-revision when it's a commit SHA, otherwise from the local git
+repository, either the --source-bundle-dir or the current directory:
+
+	$ shp build run my-app --output-image='ghcr.io/org/app:{{.ShortGitSHA}}-{{.Timestamp}}'
+
+Cloud registries issue short-lived tokens, --push-credentials-provider mints a fresh token for the
+output image registry and creates, or updates, the push secret before the BuildRun is created. The
+token is exchanged by the cloud provider CLI (aws, gcloud or az), or by the instance metadata
+service when the gcloud or az CLIs are not installed. With "auto" the provider is chosen by the
+registry hostname:
+
+	$ shp build run my-app --push-credentials-provider=auto --push-secret=ecr-push
+
+The BuildRun name is generated out of the Build name by default, a different prefix or an explicit
+name can be informed. When the explicit name is taken, an unique name is generated out of it with
+--on-name-collision=generate:
+
+	$ shp build run my-app --generate-name-prefix=nightly-
+	$ shp build run my-app --buildrun-name=my-app-v1.2.0 --on-name-collision=generate
+
+Labels and annotations can be set on the BuildRun, and the Build set as its owner, thus the
+BuildRun is garbage collected when the Build is deleted:
+
+	$ shp build run my-app --label=team=platform --annotation=ci.example.com/job=42 --owned-by-build
+
+When following the logs, --show-metrics prints the queue time, the duration and resource limits of
+each step, and the total duration at the end of the run, "-o json" renders them for machines:
+
+	$ shp build run my-app --follow --show-metrics -o json
+
+The local source code can be used instead of the Build's repository, it is packed into a source
+bundle image pushed to a container registry, and the BuildRun carries the Build's specification
+modified to pull the bundle. Prune the bundle image after it is pulled with --source-bundle-prune:
+
+	$ shp build run my-app --source-bundle-image=ghcr.io/org/app/source:latest --source-bundle-prune=AfterPull
+
+The git revision and the context directory of the Build's source can be overridden for a single
+run, without modifying the Build. The BuildRun carries the Build's specification with the
+overrides applied:
+
+	$ shp build run my-app --source-revision=feature-branch --source-context-dir=services/api
+
+When following the logs, or waiting, a SLSA provenance attestation can be generated for the image
+produced by a successful BuildRun, and optionally signed and attached to the image with cosign:
+
+	$ shp build run my-app --follow --attest=provenance --attest-sign
+
+The produced image digest can be signed with cosign once the BuildRun succeeds, keyless by default,
+or with the key informed on --sign-key, for strategies which don't sign the images themselves. The
+signatures are checked with "shp image verify":
+
+	$ shp build run my-app --wait --sign --sign-key=cosign.key
+
+The produced image can be pushed to other registries as well, --additional-output-image copies the
+image digest, registry to registry, once the BuildRun succeeds. The outcome of each copy is
+reported, and the command fails when any of them fails. The credentials are taken from
+--registry-auth, defaulting to the Build output credentials secret:
+
+	$ shp build run my-app --wait --additional-output-image=quay.io/org/app:v1 \
+		--additional-output-image=registry.example.com/mirror/app:v1
+
+Simple pipelines chain the builds with --after, e.g. the application image built on top of the base
+image, waiting for the informed BuildRun to succeed before starting the build. When the BuildRun
+fails the build is not started, unless --abort-on-dependency-failure=false:
+
+	$ shp build run base --wait --buildrun-name=base-42
+	$ shp build run app --follow --after=base-42
+
+Flaky builds are retried with --retries, creating a fresh BuildRun whenever the followed or waited
+run fails, up to the informed amount of times. The failures retried are selected with --retry-on,
+either "failure", any failure but the timeout, or "timeout". The wait between the attempts starts
+at --retry-backoff and doubles every time, and the outcome of each attempt is listed at the end:
+
+	$ shp build run my-app --wait --retries=2 --retry-on=failure,timeout --retry-backoff=30s
+
+Scripts read the outcome of the BuildRun with "-o env", printing shell variables like
+SHP_BUILDRUN_NAME, SHP_IMAGE_DIGEST and SHP_GIT_SHA once it succeeds, the messages and logs are
+written on the standard error instead:
+
+	$ eval "$(shp build run my-app --wait -o env)"
+
+When the build strategy scans the output image, a summary of the vulnerabilities found is printed
+after a successful run. With --fail-on the exit code is 4 when vulnerabilities as severe as the
+informed severity, or more, are found:
+
+	$ shp build run my-app --wait --fail-on=critical
+
+When interrupted (Ctrl-C) while following or waiting, the command stops and asks whether the
+BuildRun should be canceled as well, --cancel-on-interrupt cancels it without asking. Without a
+terminal to ask, the BuildRun is left running.
+
+When following the logs of a BuildRun with --timeout, the time left before it times out is shown
+periodically, and the exit code is 2 once the timeout is reached:
+
+	$ shp build run my-app --follow --timeout=15m
+
+When following the logs on a terminal, --ui shows a full-screen view with the state of each step
+on the upper pane and the scrolling logs on the lower pane. Without a terminal the logs are
+streamed as usual:
+
+	$ shp build run my-app --follow --ui
+
+Builds printing a lot of logs can flood the terminal, --max-log-rate limits the log bytes printed
+per second while following, skipping the lines exceeding it:
+
+	$ shp build run my-app --follow --max-log-rate=256Ki
+
+CI systems kill the jobs which stop producing output, when following the logs without a terminal
+the step still running is reported whenever no logs are printed for --heartbeat-interval, one
+minute by default, zero disables it:
+
+	$ shp build run my-app --follow --heartbeat-interval=30s
+
+Strategies exposing debug servers on the build pod are reached with --local-port-forward, forwarding
+the local port to the pod once it's running, for as long as the logs are followed. Use
+"shp buildrun exec" to run a shell on a step container:
+
+	$ shp build run my-app --follow --local-port-forward=5005:5005
+
+To build for multiple platforms, --platforms creates one BuildRun per platform, passing the platform
+on the build strategy parameter named by --platform-param. The BuildRuns are followed, or waited,
+concurrently, and the log lines are prefixed by the platform. With --manifest-list each platform
+pushes to the output image tag suffixed by the platform, e.g. "v1-linux-arm64", and a manifest list
+stitching them is pushed to the output image afterwards:
+
+	$ shp build run my-app --platforms=linux/amd64,linux/arm64 --follow --manifest-list
+
+The build strategy is responsible for building for the platform informed, either by cross-compiling
+or by emulation, as scheduling the build pod on a node of the platform is not supported by the
+BuildRun API served.
+
+Several Builds can be run at once, read from the manifests informed on --filename, e.g. the output
+of "shp build export", or selected by labels with --selector. One BuildRun is created per
+Build, at most --concurrency of them running at once, and they are waited with the log lines
+prefixed by the Build name. A table summarizes the outcome of each Build, and the exit code is
+taken from the first failure. With --fail-fast the pending Builds are skipped, and the running
+BuildRuns canceled, once a Build fails:
+
+	$ shp build run -f builds.yaml --concurrency=4
+	$ shp build run -l app.kubernetes.io/part-of=monorepo --fail-fast
+
+With --audit, or the "audit" configuration key, the BuildRuns are annotated with the user, host
+and CLI version triggering them, and a "TriggeredFromCLI" Event is recorded on each of them:
+
+	$ shp build run my-app --audit
 `
 
+// buildRunReasonCanceled the "Succeeded" condition reason set by the build controller when the
+// BuildRun is canceled, the timeout one is follower.BuildRunReasonTimeout.
+const buildRunReasonCanceled = "BuildRunCanceled"
+
+// buildRunDonePollInterval and buildRunDonePollTimeout control how long to wait for the BuildRun
+// status to reflect the completion of the build pod.
+const (
+	buildRunDonePollInterval = 1 * time.Second
+	buildRunDonePollTimeout  = 30 * time.Second
+)
+
+// topVulnerabilities amount of the most severe vulnerabilities listed after a successful run.
+const topVulnerabilities = 5
+
 // Cmd returns cobra.Command object of the create sub-command.
 func (r *RunCommand) Cmd() *cobra.Command {
 	return r.cmd
@@ -44,20 +302,29 @@ func (r *RunCommand) Cmd() *cobra.Command {
 
 // Complete picks the build resource name from arguments, and instantiate additional components.
 func (r *RunCommand) Complete(params *params.Params, ioStreams *genericclioptions.IOStreams, args []string) error {
-	switch len(args) {
-	case 1:
+	switch {
+	case !r.batch.IsEmpty():
+		if len(args) > 0 {
+			return fmt.Errorf("build name can't be informed along with --%s or --%s", flags.FilenameFlag, flags.SelectorFlag)
+		}
+		// the BuildRuns of a batch are always waited
+		r.wait = true
+	case len(args) == 1:
 		r.buildName = args[0]
 	default:
 		return errors.New("build name is not informed")
 	}
 
 	r.namespace = params.Namespace()
+	if !r.batch.IsEmpty() {
+		return nil
+	}
 
+	if r.metricsOutput == envOutputFormat {
+		ioStreams = envStreams(ioStreams)
+	}
 	if r.follow {
-		var err error
-		// provide empty build run name; will be set in Run()
-		r.follower, err = params.NewFollower(r.cmd.Context(), types.NamespacedName{}, ioStreams)
-		if err != nil {
+		if err := r.newFollower(params, ioStreams, false); err != nil {
 			return err
 		}
 		r.followerReady = make(chan bool, 1)
@@ -68,12 +335,223 @@ func (r *RunCommand) Complete(params *params.Params, ioStreams *genericclioption
 
 // Validate the user must inform the build resource name.
 func (r *RunCommand) Validate() error {
-	if r.buildName == "" {
+	if err := r.validateBatch(); err != nil {
+		return err
+	}
+	if r.buildName == "" && r.batch.IsEmpty() {
 		return fmt.Errorf("name is not informed")
 	}
+	if r.follow && r.wait {
+		return fmt.Errorf("--follow and --wait are mutually exclusive")
+	}
+	if err := r.naming.Validate(); err != nil {
+		return err
+	}
+	if err := r.metadata.Validate(); err != nil {
+		return err
+	}
+	if r.waitTimeout < 0 {
+		return fmt.Errorf("--wait-timeout must not be negative")
+	}
+	if r.waitTimeout > 0 && !r.wait {
+		return fmt.Errorf("--wait-timeout requires --wait")
+	}
+	if output := r.buildRunSpec.Output; output != nil && isOutputImageTemplate(output.Image) {
+		if err := validateOutputImageTemplate(output.Image); err != nil {
+			return err
+		}
+	}
+	if r.cmd.Flags().Changed("abort-on-dependency-failure") && r.after == "" {
+		return fmt.Errorf("--abort-on-dependency-failure requires --after")
+	}
+	if err := r.validateRetries(); err != nil {
+		return err
+	}
+	if r.failureLogLines < 0 {
+		return fmt.Errorf("--failure-log-lines must not be negative")
+	}
+	if r.showMetrics && !r.follow {
+		return fmt.Errorf("--show-metrics requires --follow")
+	}
+	if r.metricsOutput == envOutputFormat {
+		if err := r.validateEnvOutput(); err != nil {
+			return err
+		}
+	} else if r.metricsOutput != "" {
+		if !r.showMetrics {
+			return fmt.Errorf("--output requires --show-metrics")
+		}
+		if r.metricsOutput != metrics.FormatTable && r.metricsOutput != metrics.FormatJSON {
+			return fmt.Errorf("unsupported --output %q, expected one of %v", r.metricsOutput, metrics.Formats)
+		}
+	}
+	if r.ui && !r.follow {
+		return fmt.Errorf("--ui requires --follow")
+	}
+	if r.maxLogRate > 0 && !r.follow {
+		return fmt.Errorf("--%s requires --follow", flags.MaxLogRateFlag)
+	}
+	if err := validateHeartbeat(r.cmd, r.heartbeat, r.follow); err != nil {
+		return err
+	}
+	if !r.logFilter.IsEmpty() && !r.follow {
+		return fmt.Errorf("--%s and --%s require --follow", flags.GrepFlag, flags.StepFlag)
+	}
+	if len(r.localPorts) > 0 {
+		if !r.follow {
+			return fmt.Errorf("--%s requires --follow", flags.LocalPortForwardFlag)
+		}
+		if err := debug.ValidatePorts(r.localPorts); err != nil {
+			return err
+		}
+	}
+	if r.cancelOnInterrupt && !r.follow && !r.wait {
+		return fmt.Errorf("--cancel-on-interrupt requires --follow or --wait")
+	}
+	if r.imageDigestFile != "" && !r.follow && !r.wait {
+		return fmt.Errorf("--image-digest-file requires --follow or --wait")
+	}
+	if len(r.additionalOutputs) > 0 {
+		if !r.follow && !r.wait {
+			return fmt.Errorf("--%s requires --follow or --wait, the image is copied after the run succeeds",
+				flags.AdditionalOutputImageFlag)
+		}
+		for _, image := range r.additionalOutputs {
+			if _, err := registry.ParseCopyTarget(image); err != nil {
+				return err
+			}
+		}
+	}
+	if r.pushCredentialsProvider != "" {
+		if err := registry.ValidateProvider(r.pushCredentialsProvider); err != nil {
+			return err
+		}
+	} else if r.pushSecret != "" {
+		return fmt.Errorf("--%s requires --%s", flags.PushSecretFlag, flags.PushCredentialsProviderFlag)
+	}
+	if r.sourceOverride.Revision != "" && r.usesSourceBundle() {
+		return fmt.Errorf("--%s can't be used along with --%s", flags.SourceRevisionFlag, flags.SourceBundleImageFlag)
+	}
+	if !r.usesSourceBundle() {
+		if r.cmd.Flags().Changed(flags.SourceBundleDirFlag) || r.cmd.Flags().Changed(flags.SourceBundlePruneFlag) ||
+			r.cmd.Flags().Changed(flags.CompressFlag) || r.cmd.Flags().Changed(flags.CompressLevelFlag) {
+			return fmt.Errorf("--%s must be informed when using the other source bundle flags", flags.SourceBundleImageFlag)
+		}
+		// the registry credentials are employed to push the source bundle, the manifest list, or the
+		// additional output images
+		if (r.cmd.Flags().Changed(flags.RegistryAuthFlag) || r.registrySecret != "") &&
+			!r.multiPlatform.ManifestList && len(r.additionalOutputs) == 0 {
+			return fmt.Errorf("--%s, --%s or --%s must be informed when using the registry authentication flags",
+				flags.SourceBundleImageFlag, flags.ManifestListFlag, flags.AdditionalOutputImageFlag)
+		}
+		if _, err := registry.ParseAuthSource(r.registryAuth); err != nil {
+			return err
+		}
+	} else {
+		stat, err := os.Stat(r.sourceBundleDir)
+		if err != nil {
+			return err
+		}
+		if !stat.IsDir() {
+			return fmt.Errorf("informed path is not a directory: '%s'", r.sourceBundleDir)
+		}
+		if _, err = registry.ParseAuthSource(r.registryAuth); err != nil {
+			return err
+		}
+		if err = streamer.ValidateCompression(streamer.Compression(r.compression), r.compressLevel); err != nil {
+			return err
+		}
+	}
+	switch r.attest {
+	case "":
+		if r.attestFile != "" || r.attestSign || r.attestKey != "" {
+			return fmt.Errorf("--attest must be informed when using the other attestation flags")
+		}
+	case attest.ProvenanceType:
+		if !r.follow && !r.wait {
+			return fmt.Errorf("--attest requires --follow or --wait, the attestation is generated after the run succeeds")
+		}
+		if r.attestKey != "" && !r.attestSign {
+			return fmt.Errorf("--attest-key requires --attest-sign")
+		}
+	default:
+		return fmt.Errorf("unsupported attestation type %q, only %q is supported", r.attest, attest.ProvenanceType)
+	}
+	switch {
+	case r.sign && !r.follow && !r.wait:
+		return fmt.Errorf("--sign requires --follow or --wait, the image is signed after the run succeeds")
+	case r.signKey != "" && !r.sign:
+		return fmt.Errorf("--sign-key requires --sign")
+	}
+	if r.failOn != "" {
+		if !r.follow && !r.wait {
+			return fmt.Errorf("--fail-on requires --follow or --wait")
+		}
+		if _, err := vulnerability.ParseSeverity(r.failOn); err != nil {
+			return err
+		}
+	}
+	return r.validatePlatforms()
+}
+
+// validateEnvOutput checks the shell variables are only printed for a single BuildRun, once it's
+// finished.
+func (r *RunCommand) validateEnvOutput() error {
+	switch {
+	case !r.batch.IsEmpty():
+		return fmt.Errorf("--output %s can't be used along with --%s or --%s", envOutputFormat, flags.FilenameFlag, flags.SelectorFlag)
+	case !r.follow && !r.wait:
+		return fmt.Errorf("--output %s requires --follow or --wait", envOutputFormat)
+	case r.showMetrics:
+		return fmt.Errorf("--output %s can't be used along with --show-metrics", envOutputFormat)
+	case !r.multiPlatform.IsEmpty():
+		return fmt.Errorf("--output %s can't be used along with --platforms", envOutputFormat)
+	}
 	return nil
 }
 
+// validateHeartbeat checks the heartbeat interval is not negative, and only informed when following.
+func validateHeartbeat(cmd *cobra.Command, interval time.Duration, follow bool) error {
+	if interval < 0 {
+		return fmt.Errorf("--%s must not be negative", flags.HeartbeatIntervalFlag)
+	}
+	if cmd.Flags().Changed(flags.HeartbeatIntervalFlag) && !follow {
+		return fmt.Errorf("--%s requires --follow", flags.HeartbeatIntervalFlag)
+	}
+	return nil
+}
+
+// usesSourceBundle tells whether the local source directory is packed as the source bundle image.
+func (r *RunCommand) usesSourceBundle() bool {
+	return r.sourceBundle != nil && r.sourceBundle.Image != ""
+}
+
+// newFollower instantiates the follower of the BuildRun logs, once per BuildRun followed. The
+// dedicated followers watch the pods on their own, instead of the shared pod watcher.
+func (r *RunCommand) newFollower(params *params.Params, ioStreams *genericclioptions.IOStreams, dedicated bool) error {
+	newFollower := params.NewFollower
+	if dedicated {
+		newFollower = params.NewDedicatedFollower
+	}
+	var err error
+	// provide empty build run name; will be set in Run()
+	r.follower, err = newFollower(r.cmd.Context(), types.NamespacedName{}, ioStreams)
+	if err != nil {
+		return err
+	}
+	r.follower.SetMaxLogRate(r.maxLogRate)
+	r.follower.SetLineFilter(r.logFilter.LineFilter())
+	r.follower.SetHeartbeat(r.heartbeat)
+	return nil
+}
+
+// markFollowerReady unblocks FollowerReady, once the first log following connection is established.
+func (r *RunCommand) markFollowerReady() {
+	r.readyOnce.Do(func() {
+		close(r.followerReady)
+	})
+}
+
 // FollowerReady blocks until the any log following connections are established in the Run call.
 // Useful if you have code that calls Run on a separate thread and coordination is needed.
 func (r *RunCommand) FollowerReady() bool {
@@ -84,14 +562,30 @@ func (r *RunCommand) FollowerReady() bool {
 	return !closed
 }
 
+// newRecorder instantiates the audit recorder, identifying the user behind the credentials.
+func newRecorder(ctx context.Context, params *params.Params) (*audit.Recorder, error) {
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return nil, err
+	}
+	return audit.NewRecorder(ctx, clientset, params.KubeconfigUser(), version.Current()), nil
+}
+
 // Run creates a BuildRun resource based on Build's name informed on arguments.
 func (r *RunCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
-	// resource using GenerateName, which will provide a unique instance
+	if params.Quiet() && r.follow {
+		return fmt.Errorf("--quiet can't be used along with --follow")
+	}
+	if r.metricsOutput == envOutputFormat {
+		// the shell variables are the only output, the messages and logs are written on stderr
+		r.envOut = ioStreams.Out
+		ioStreams = envStreams(ioStreams)
+	}
+
+	// resource using GenerateName by default, which will provide a unique instance
 	br := &buildv1alpha1.BuildRun{
-		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: fmt.Sprintf("%s-", r.buildName),
-		},
-		Spec: *r.buildRunSpec,
+		ObjectMeta: r.naming.ObjectMeta(r.buildName),
+		Spec:       *r.buildRunSpec,
 	}
 	flags.SanitizeBuildRunSpec(&br.Spec)
 
@@ -100,47 +594,583 @@ func (r *RunCommand) Run(params *params.Params, ioStreams *genericclioptions.IOS
 	if err != nil {
 		return err
 	}
-	br, err = clientset.ShipwrightV1alpha1().BuildRuns(r.namespace).Create(ctx, br, metav1.CreateOptions{})
+	if r.audit {
+		if r.recorder, err = newRecorder(ctx, params); err != nil {
+			return err
+		}
+	}
+	if r.after != "" {
+		if err = r.waitForDependency(clientset, ioStreams); err != nil {
+			return err
+		}
+	}
+	if !r.batch.IsEmpty() {
+		return r.runBatch(params, ioStreams, clientset, br)
+	}
+	if output := br.Spec.Output; output != nil && isOutputImageTemplate(output.Image) {
+		if output.Image, err = r.expandOutputImage(ctx, output.Image); err != nil {
+			return err
+		}
+		fmt.Fprintf(params.QuietStreams(ioStreams).Out, "Output image %q\n", output.Image)
+	}
+	if r.pushCredentialsProvider != "" {
+		if err = r.refreshPushSecret(ctx, params, params.QuietStreams(ioStreams), clientset, br); err != nil {
+			return err
+		}
+	}
+	var owner *buildv1alpha1.Build
+	if r.metadata.RequiresOwner() {
+		if owner, err = clientset.ShipwrightV1alpha1().Builds(r.namespace).Get(ctx, r.buildName, metav1.GetOptions{}); err != nil {
+			return err
+		}
+	}
+	r.metadata.Apply(&br.ObjectMeta, owner)
+	if r.usesSourceBundle() || !r.sourceOverride.IsEmpty() {
+		if br.Spec.BuildSpec, err = r.embeddedBuildSpec(params, ioStreams); err != nil {
+			return err
+		}
+		// the build specification is embedded, thus both can't be informed at once
+		br.Spec.BuildRef = nil
+	}
+	if !r.multiPlatform.IsEmpty() {
+		return r.runPlatforms(params, ioStreams, clientset, br)
+	}
+	if r.retries > 0 {
+		return r.runWithRetries(params, ioStreams, clientset, br)
+	}
+	_, err = r.runBuildRun(params, ioStreams, clientset, br)
+	return err
+}
+
+// runBuildRun creates the BuildRun out of the informed one, following or waiting for it when
+// requested. Returns the name of the BuildRun created, empty when it could not be created.
+func (r *RunCommand) runBuildRun(
+	params *params.Params,
+	ioStreams *genericclioptions.IOStreams,
+	clientset buildclientset.Interface,
+	br *buildv1alpha1.BuildRun,
+) (string, error) {
+	ctx := r.cmd.Context()
+	_, span := trace.Start(ctx, "create BuildRun")
+	span.SetAttribute("build", r.buildName)
+	br, err := createNamedBuildRun(ctx, clientset, r.namespace, br, r.naming, r.recorder, ioStreams.ErrOut)
+	if err == nil {
+		span.SetAttribute("buildrun", br.GetName())
+	}
+	span.RecordError(err)
+	span.End()
 	if err != nil {
-		return err
+		return "", err
 	}
+	name := br.GetName()
 
 	if !r.follow {
-		fmt.Fprintf(ioStreams.Out, "BuildRun created %q for build %q\n", br.GetName(), r.buildName)
-		return nil
+		if params.Quiet() {
+			fmt.Fprintln(ioStreams.Out, name)
+			// only the name is printed, the outcome of waiting is carried by the exit code
+			ioStreams = params.QuietStreams(ioStreams)
+		} else {
+			fmt.Fprintf(ioStreams.Out, "BuildRun created %q for build %q\n", name, r.buildName)
+		}
+		if !r.wait {
+			return name, nil
+		}
+		waitCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		interrupt := r.notifyInterrupt(cancel)
+		defer interrupt.stop()
+		waitCtx, span := trace.Start(waitCtx, "wait for BuildRun")
+		err = r.waitForBuildRun(waitCtx, params, clientset, ioStreams, name)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			if interrupt.interrupted() {
+				return name, r.handleInterrupt(clientset, ioStreams, name)
+			}
+			return name, err
+		}
+		return name, r.completeBuildRun(params, clientset, ioStreams, name)
 	}
 
-	buildRun := types.NamespacedName{Namespace: r.namespace, Name: br.GetName()}
+	buildRun := types.NamespacedName{Namespace: r.namespace, Name: name}
 	r.follower.SetBuildRunName(buildRun)
+	if timeout := br.Spec.Timeout; timeout != nil && timeout.Duration > 0 {
+		r.follower.SetTimeout(br.GetCreationTimestamp().Time, timeout.Duration)
+	}
 
 	// instantiating a pod watcher with a specific label-selector to find the indented pod where the
 	// actual build started by this subcommand is being executed, including the randomized buildrun
 	// name
-	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf(
-		"build.shipwright.io/name=%s,buildrun.shipwright.io/name=%s",
-		r.buildName,
-		br.GetName(),
-	)}
-	err = r.follower.Connect(listOpts)
+	listOpts := reactor.BuildRunPodListOptions(name)
+	if r.showMetrics || r.ui {
+		r.tracker = reactor.NewContainerTracker()
+		r.follower.WithContainerTracker(r.tracker)
+	}
+	if err = r.follower.Connect(listOpts); err != nil {
+		return name, err
+	}
+	r.markFollowerReady()
+	if len(r.localPorts) > 0 {
+		stop, err := r.startPortForward(params, ioStreams, listOpts)
+		if err != nil {
+			return name, err
+		}
+		defer stop()
+	}
+	screen := r.startScreen(params, ioStreams, name)
+	interrupt := r.notifyInterrupt(r.follower.Stop)
+	defer interrupt.stop()
+	_, err = r.follower.WaitForCompletion()
+	if screen != nil {
+		screen.Stop()
+	}
+	if err != nil {
+		return name, err
+	}
+	if interrupt.interrupted() {
+		return name, r.handleInterrupt(clientset, ioStreams, name)
+	}
+	if r.follower.PodSucceeded() {
+		err = r.completeBuildRun(params, clientset, ioStreams, name)
+	}
+	if r.showMetrics {
+		if metricsErr := r.printMetrics(clientset, ioStreams, name); metricsErr != nil && err == nil {
+			err = metricsErr
+		}
+	}
+	return name, err
+}
+
+// startPortForward forwards the local ports to the build pod in the background, once it's running.
+// Returns the function stopping the forwarding.
+func (r *RunCommand) startPortForward(
+	params *params.Params,
+	ioStreams *genericclioptions.IOStreams,
+	listOpts metav1.ListOptions,
+) (func(), error) {
+	restConfig, err := params.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClientset, err := params.ClientSet()
+	if err != nil {
+		return nil, err
+	}
+	forwarder := debug.NewPortForwarder(restConfig, kubeClientset)
+	return forwarder.Start(r.cmd.Context(), r.namespace, listOpts, r.localPorts, ioStreams.ErrOut), nil
+}
+
+// startScreen switches to the full-screen view when requested and the output is a terminal, the
+// follower messages and logs are redirected to it. Returns nil when the logs are streamed as usual.
+func (r *RunCommand) startScreen(params *params.Params, ioStreams *genericclioptions.IOStreams, name string) *tui.Screen {
+	if !r.ui {
+		return nil
+	}
+	f, ok := ioStreams.Out.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil
+	}
+	size := func() (int, int, error) {
+		return term.GetSize(int(f.Fd()))
+	}
+	screen := tui.NewScreen(f, size, fmt.Sprintf("BuildRun %q", name), r.tracker)
+	r.follower.SetOutput(screen, screen)
+	r.watchStatus(params, screen, name)
+	screen.Start()
+	return screen
+}
+
+// watchStatus keeps the screen headline showing the BuildRun status, updated by the shared informer
+// cache. The status is best effort, the screen works without it.
+func (r *RunCommand) watchStatus(params *params.Params, screen *tui.Screen, name string) {
+	brCache, err := params.Cache(r.cmd.Context())
+	if err != nil {
+		return
+	}
+	// the informer lives as long as the command, thus the handler is not removed
+	_, _ = brCache.WatchBuildRun(r.namespace, name, func(br *buildv1alpha1.BuildRun) {
+		if br == nil {
+			screen.SetStatus("deleted")
+			return
+		}
+		screen.SetStatus(string(util.PhaseOf(br)))
+	})
+}
+
+// printMetrics prints the summary of the durations recorded while following the BuildRun.
+func (r *RunCommand) printMetrics(clientset buildclientset.Interface, ioStreams *genericclioptions.IOStreams, name string) error {
+	br, err := clientset.ShipwrightV1alpha1().BuildRuns(r.namespace).Get(r.cmd.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
-	close(r.followerReady)
-	_, err = r.follower.WaitForCompletion()
-	return err
+	return metrics.NewSummary(br, r.tracker).Print(ioStreams.Out, r.metricsOutput)
+}
+
+// waitForBuildRun blocks until the BuildRun reaches a terminal state, the outcome is translated to
+// an error carrying the respective exit code.
+func (r *RunCommand) waitForBuildRun(
+	ctx context.Context,
+	params *params.Params,
+	clientset buildclientset.Interface,
+	ioStreams *genericclioptions.IOStreams,
+	name string,
+) error {
+	fmt.Fprintf(ioStreams.Out, "Waiting for BuildRun %q to finish...\n", name)
+	br, err := util.WaitForBuildRunDone(ctx, clientset, r.namespace, name, buildRunDonePollInterval, r.waitTimeout)
+	if err != nil {
+		if wait.Interrupted(err) {
+			return exitcode.Errorf(exitcode.Timeout, "timed out waiting for BuildRun %q to finish", name)
+		}
+		return err
+	}
+
+	c := br.Status.GetCondition(buildv1alpha1.Succeeded)
+	switch {
+	case br.IsSuccessful():
+		fmt.Fprintf(ioStreams.Out, "BuildRun %q has succeeded\n", name)
+		return nil
+	case br.IsCanceled() || c.GetReason() == buildRunReasonCanceled:
+		return exitcode.Errorf(exitcode.Cancelled, "BuildRun %q has been canceled", name)
+	case c.GetReason() == follower.BuildRunReasonTimeout:
+		return exitcode.Errorf(exitcode.Timeout, "BuildRun %q has timed out: %s", name, c.GetMessage())
+	default:
+		if r.failureLogLines > 0 {
+			if err = r.printFailureLogs(params, ioStreams, br); err != nil {
+				fmt.Fprintf(ioStreams.ErrOut, "Warning: unable to show the logs of the failed step: %v\n", err)
+			}
+		}
+		return exitcode.Errorf(exitcode.Failure, "BuildRun %q has failed because of %s: %s", name, c.GetReason(), c.GetMessage())
+	}
+}
+
+// printFailureLogs prints the last lines of the failed step of the BuildRun, found by the failure
+// details reported by the build controller, or the build pod container statuses.
+func (r *RunCommand) printFailureLogs(params *params.Params, ioStreams *genericclioptions.IOStreams, br *buildv1alpha1.BuildRun) error {
+	kclientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+
+	var podName, container string
+	if details := br.Status.FailureDetails; details != nil && details.Location != nil {
+		podName, container = details.Location.Pod, details.Location.Container
+	}
+
+	var pod *corev1.Pod
+	if podName != "" {
+		if pod, err = kclientset.CoreV1().Pods(r.namespace).Get(r.cmd.Context(), podName, metav1.GetOptions{}); err != nil {
+			return err
+		}
+	} else {
+		pods, err := kclientset.CoreV1().Pods(r.namespace).List(r.cmd.Context(), reactor.BuildRunPodListOptions(br.GetName()))
+		if err != nil {
+			return err
+		}
+		if len(pods.Items) == 0 {
+			return fmt.Errorf("build pod of BuildRun %q not found", br.GetName())
+		}
+		pod = &pods.Items[0]
+	}
+	if container == "" {
+		container = util.FailedContainer(pod)
+	}
+	if container == "" {
+		return fmt.Errorf("no failed container found in pod %q", pod.GetName())
+	}
+
+	logs, err := util.GetPodLogsTail(r.cmd.Context(), kclientset, *pod, container, int64(r.failureLogLines))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "Last %d lines of the failed step %q:\n\n%s\n",
+		r.failureLogLines, strings.TrimPrefix(container, "step-"), strings.TrimRight(logs, "\n"))
+	return nil
+}
+
+// embeddedBuildSpec returns the Build's specification modified by the source overrides, and to
+// pull the source bundle when the local source directory is used.
+func (r *RunCommand) embeddedBuildSpec(params *params.Params, ioStreams *genericclioptions.IOStreams) (*buildv1alpha1.BuildSpec, error) {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return nil, err
+	}
+	b, err := clientset.ShipwrightV1alpha1().Builds(r.namespace).Get(r.cmd.Context(), r.buildName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	spec := b.Spec.DeepCopy()
+	if r.usesSourceBundle() {
+		if err = r.pushSourceBundle(params, ioStreams, b, spec); err != nil {
+			return nil, err
+		}
+	}
+	if err = r.sourceOverride.Apply(&spec.Source); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// pushSourceBundle packs and pushes the local source directory as the source bundle image, the
+// specification is modified to pull the bundle, pinned by digest.
+func (r *RunCommand) pushSourceBundle(
+	params *params.Params,
+	ioStreams *genericclioptions.IOStreams,
+	b *buildv1alpha1.Build,
+	spec *buildv1alpha1.BuildSpec,
+) error {
+
+	var sourceCredentials string
+	if b.Spec.Source.Credentials != nil {
+		sourceCredentials = b.Spec.Source.Credentials.Name
+	}
+	keychain, err := registryKeychain(r.cmd.Context(), params, r.registryAuth, r.registrySecret, sourceCredentials)
+	if err != nil {
+		return err
+	}
+	digest, err := bundle.Push(r.cmd.Context(), ioStreams, r.sourceBundleDir, r.sourceBundle.Image, keychain,
+		streamer.Compression(r.compression), r.compressLevel)
+	if err != nil {
+		return err
+	}
+
+	spec.Source.URL = nil
+	spec.Source.Revision = nil
+	spec.Source.BundleContainer = &buildv1alpha1.BundleContainer{Image: digest.String()}
+	if r.sourceBundle.Prune != nil && *r.sourceBundle.Prune != "" {
+		spec.Source.BundleContainer.Prune = r.sourceBundle.Prune
+	}
+	return nil
+}
+
+// completeBuildRun obtains the final state of a successful BuildRun in order to report the image
+// digest reference, and copy and sign the image and generate the attestation when requested.
+func (r *RunCommand) completeBuildRun(
+	params *params.Params,
+	clientset buildclientset.Interface,
+	ioStreams *genericclioptions.IOStreams,
+	name string,
+) (err error) {
+	ctx, span := trace.Start(r.cmd.Context(), "complete BuildRun")
+	span.SetAttribute("buildrun", name)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// the BuildRun status may lag behind the completion of the build pod
+	br, err := util.WaitForBuildRunDone(ctx, clientset, r.namespace, name, buildRunDonePollInterval, buildRunDonePollTimeout)
+	if err != nil {
+		return fmt.Errorf("unable to obtain the final state of BuildRun %q: %w", name, err)
+	}
+	if !br.IsSuccessful() {
+		return nil
+	}
+
+	if err = r.reportImageDigest(ioStreams, br); err != nil {
+		return err
+	}
+	if len(r.additionalOutputs) > 0 {
+		image, err := attest.ImageDigestReference(br)
+		if err != nil {
+			return fmt.Errorf("unable to copy the image: %w", err)
+		}
+		if err = r.copyOutputImage(params, ioStreams, image, outputCredentialsOf(br)); err != nil {
+			return err
+		}
+	}
+	if r.sign {
+		image, err := attest.ImageDigestReference(br)
+		if err != nil {
+			return fmt.Errorf("unable to sign the image: %w", err)
+		}
+		if err = attest.Sign(r.cmd.Context(), ioStreams, image, r.signKey); err != nil {
+			return err
+		}
+	}
+	if r.attest != "" {
+		if err = r.attestBuildRun(ioStreams, br); err != nil {
+			return err
+		}
+	}
+	if r.envOut != nil {
+		if err = printBuildRunEnv(r.envOut, br); err != nil {
+			return err
+		}
+	}
+	return r.reportVulnerabilities(clientset, ioStreams, name)
+}
+
+// reportVulnerabilities prints the summary of the vulnerabilities found on the output image, when
+// reported by the build strategy, and fails when they reach the --fail-on severity.
+func (r *RunCommand) reportVulnerabilities(clientset buildclientset.Interface, ioStreams *genericclioptions.IOStreams, name string) error {
+	vulns, err := vulnerability.Fetch(r.cmd.Context(), vulnerability.RESTClient(clientset), r.namespace, name)
+	if err != nil {
+		if r.failOn != "" {
+			return fmt.Errorf("unable to obtain the vulnerabilities of BuildRun %q: %w", name, err)
+		}
+		fmt.Fprintf(ioStreams.ErrOut, "Warning: unable to obtain the vulnerabilities of BuildRun %q: %v\n", name, err)
+		return nil
+	}
+	if len(vulns) == 0 {
+		return nil
+	}
+	vulnerability.PrintSummary(ioStreams.Out, vulns, topVulnerabilities)
+
+	if r.failOn == "" {
+		return nil
+	}
+	threshold, err := vulnerability.ParseSeverity(r.failOn)
+	if err != nil {
+		return err
+	}
+	if found := vulnerability.AtLeast(vulns, threshold); len(found) > 0 {
+		return exitcode.Errorf(exitcode.Vulnerable, "BuildRun %q output image has %d vulnerabilities of severity %s or higher",
+			name, len(found), threshold)
+	}
+	return nil
+}
+
+// reportImageDigest prints the fully qualified reference of the produced image, and writes it to the
+// informed file. Build strategies which do not report the digest only produce a warning, unless the
+// file is requested.
+func (r *RunCommand) reportImageDigest(ioStreams *genericclioptions.IOStreams, br *buildv1alpha1.BuildRun) error {
+	ref, err := attest.ImageDigestReference(br)
+	if err != nil {
+		if r.imageDigestFile != "" {
+			return err
+		}
+		fmt.Fprintf(ioStreams.ErrOut, "Warning: %s\n", err)
+		return nil
+	}
+
+	fmt.Fprintf(ioStreams.Out, "BuildRun %q produced image %s\n", br.GetName(), ref.String())
+	if r.imageDigestFile == "" {
+		return nil
+	}
+	return os.WriteFile(r.imageDigestFile, []byte(ref.String()+"\n"), 0o600)
+}
+
+// outputCredentialsOf returns the name of the output credentials secret of the BuildRun, empty when
+// not informed.
+func outputCredentialsOf(br *buildv1alpha1.BuildRun) string {
+	switch {
+	case br.Spec.Output != nil && br.Spec.Output.Credentials != nil:
+		return br.Spec.Output.Credentials.Name
+	case br.Status.BuildSpec != nil && br.Status.BuildSpec.Output.Credentials != nil:
+		return br.Status.BuildSpec.Output.Credentials.Name
+	}
+	return ""
+}
+
+// copyOutputImage copies the image to each additional output image, reporting the outcome of each
+// one. The copies continue after a failure, the error tells the ones which failed.
+func (r *RunCommand) copyOutputImage(
+	params *params.Params,
+	ioStreams *genericclioptions.IOStreams,
+	image name.Digest,
+	outputSecret string,
+) error {
+	keychain, err := registryKeychain(r.cmd.Context(), params, r.registryAuth, r.registrySecret, outputSecret)
+	if err != nil {
+		return err
+	}
+	failures := []string{}
+	for _, target := range r.additionalOutputs {
+		copied, err := registry.CopyImage(r.cmd.Context(), image, target, keychain)
+		if err != nil {
+			fmt.Fprintf(ioStreams.ErrOut, "Error: %s\n", err)
+			failures = append(failures, target)
+			continue
+		}
+		fmt.Fprintf(ioStreams.Out, "Image copied to %s\n", copied.String())
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d additional output images failed: %s",
+			len(failures), len(r.additionalOutputs), strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+// attestBuildRun generates the attestation for the informed successful BuildRun, writing it to the
+// informed file or output stream, and signing it when requested.
+func (r *RunCommand) attestBuildRun(ioStreams *genericclioptions.IOStreams, br *buildv1alpha1.BuildRun) error {
+	name := br.GetName()
+	statement, err := attest.NewProvenance(br)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return err
+	}
+	if r.attestFile == "" {
+		fmt.Fprintln(ioStreams.Out, string(data))
+	} else {
+		if err = os.WriteFile(r.attestFile, data, 0o600); err != nil {
+			return err
+		}
+		fmt.Fprintf(ioStreams.Out, "Provenance for BuildRun %q written to %q\n", name, r.attestFile)
+	}
+
+	if !r.attestSign {
+		return nil
+	}
+	return attest.Attach(r.cmd.Context(), ioStreams, statement, r.attestKey)
 }
 
 // runCmd instantiate the "build run" sub-command using common BuildRun flags.
 func runCmd() runner.SubCommand {
 	cmd := &cobra.Command{
-		Use:   "run <name>",
+		Use:   "run [<name>] [flags]",
 		Short: "Start a build specified by 'name'",
 		Long:  buildRunLongDesc,
 	}
 	runCommand := &RunCommand{
 		cmd:          cmd,
 		buildRunSpec: flags.BuildRunSpecFromFlags(cmd.Flags()),
+		naming:       &flags.BuildRunNaming{},
+		metadata:     &flags.ObjectMetadata{},
+		sourceBundle: &buildv1alpha1.BundleContainer{},
 	}
 	flags.FollowFlag(cmd.Flags(), &runCommand.follow)
+	flags.MaxLogRateFlags(cmd.Flags(), &runCommand.maxLogRate)
+	flags.HeartbeatIntervalFlags(cmd.Flags(), &runCommand.heartbeat)
+	flags.LogFilterFlags(cmd.Flags(), &runCommand.logFilter)
+	flags.LocalPortForwardFlags(cmd.Flags(), &runCommand.localPorts)
+	flags.BuildRunNamingFlags(cmd.Flags(), runCommand.naming)
+	flags.ObjectMetadataFlags(cmd.Flags(), runCommand.metadata)
+	cmd.Flags().BoolVar(&runCommand.wait, "wait", false, "wait for the BuildRun to finish, the exit code reflects the outcome")
+	cmd.Flags().DurationVar(&runCommand.waitTimeout, "wait-timeout", 0, "maximum amount of time to wait for the BuildRun, zero means no limit")
+	cmd.Flags().StringVar(&runCommand.after, "after", "", "BuildRun which must succeed before the build is started, the command waits for it")
+	cmd.Flags().BoolVar(&runCommand.abortOnDependencyFailure, "abort-on-dependency-failure", true,
+		"do not start the build when the --after BuildRun fails, otherwise it's started once the BuildRun finishes")
+	cmd.Flags().IntVar(&runCommand.retries, "retries", 0, "amount of fresh BuildRuns created when the followed or waited run fails, zero disables it")
+	cmd.Flags().StringSliceVar(&runCommand.retryOn, "retry-on", []string{retryOnFailure},
+		fmt.Sprintf("comma separated failures retried, one or more of %v", retryOnReasons))
+	cmd.Flags().DurationVar(&runCommand.retryBackoff, "retry-backoff", 10*time.Second, "wait before the first retry, doubled on every retry")
+	cmd.Flags().IntVar(&runCommand.failureLogLines, "failure-log-lines", 20, "amount of log lines of the failed step printed when the waited BuildRun fails, zero disables it")
+	cmd.Flags().BoolVar(&runCommand.showMetrics, "show-metrics", false, "print the queue time, step durations and resource limits after following the run")
+	cmd.Flags().BoolVar(&runCommand.ui, "ui", false, "follow the logs on a full-screen terminal view with the state of each step")
+	cmd.Flags().StringVarP(&runCommand.metricsOutput, "output", "o", "", fmt.Sprintf("output format, %q prints the BuildRun outcome as shell variables, or the metrics summary format, one of %v", envOutputFormat, metrics.Formats))
+	cmd.Flags().StringVar(&runCommand.imageDigestFile, "image-digest-file", "", "path to write the produced image digest reference after a successful run")
+	flags.AdditionalOutputImageFlags(cmd.Flags(), &runCommand.additionalOutputs)
+	flags.SourceOverrideFlags(cmd.Flags(), &runCommand.sourceOverride)
+	flags.SourceBundleFlags(cmd.Flags(), runCommand.sourceBundle, &runCommand.sourceBundleDir)
+	flags.RegistryAuthFlags(cmd.Flags(), &runCommand.registryAuth, &runCommand.registrySecret)
+	flags.PushCredentialsFlags(cmd.Flags(), &runCommand.pushCredentialsProvider, &runCommand.pushSecret)
+	flags.CompressionFlags(cmd.Flags(), &runCommand.compression, &runCommand.compressLevel)
+	cmd.Flags().StringVar(&runCommand.attest, "attest", "", fmt.Sprintf("generate an attestation after a successful run, supported: %q", attest.ProvenanceType))
+	cmd.Flags().StringVar(&runCommand.attestFile, "attest-file", "", "path to write the attestation statement, printed on the output when empty")
+	cmd.Flags().BoolVar(&runCommand.attestSign, "attest-sign", false, "sign and attach the attestation to the output image using cosign")
+	cmd.Flags().StringVar(&runCommand.attestKey, "attest-key", "", "cosign key reference to sign the attestation, keyless signing is used when empty")
+	cmd.Flags().BoolVar(&runCommand.sign, "sign", false, "sign the output image digest using cosign after a successful run")
+	cmd.Flags().StringVar(&runCommand.signKey, "sign-key", "", "cosign key reference to sign the image, keyless signing is used when empty")
+	cmd.Flags().StringVar(&runCommand.failOn, "fail-on", "",
+		fmt.Sprintf("exit non-zero when the output image has vulnerabilities of the severity, or more severe, one of %v", vulnerability.Severities))
+	flags.MultiPlatformFlags(cmd.Flags(), &runCommand.multiPlatform)
+	flags.BatchFlags(cmd.Flags(), &runCommand.batch)
+	flags.AuditFlags(cmd.Flags(), &runCommand.audit)
+	cmd.Flags().BoolVar(&runCommand.cancelOnInterrupt, "cancel-on-interrupt", false, "cancel the BuildRun when the command is interrupted while following or waiting, instead of asking")
 	return runCommand
 }
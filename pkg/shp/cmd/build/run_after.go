@@ -0,0 +1,47 @@
+package build
+
+import (
+	"fmt"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// waitForDependency blocks until the BuildRun informed on --after finishes, the build is only
+// started when it has succeeded, unless --abort-on-dependency-failure=false.
+func (r *RunCommand) waitForDependency(clientset buildclientset.Interface, ioStreams *genericclioptions.IOStreams) error {
+	ctx := r.cmd.Context()
+	_, err := clientset.ShipwrightV1alpha1().BuildRuns(r.namespace).Get(ctx, r.after, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return exitcode.Errorf(exitcode.NotFound, "BuildRun %q informed on --after not found", r.after)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ioStreams.Out, "Waiting for BuildRun %q to succeed before starting the build...\n", r.after)
+	br, err := util.WaitForBuildRunDone(ctx, clientset, r.namespace, r.after, buildRunDonePollInterval, 0)
+	if err != nil {
+		return fmt.Errorf("unable to wait for BuildRun %q: %w", r.after, err)
+	}
+	if br.IsSuccessful() {
+		fmt.Fprintf(ioStreams.Out, "BuildRun %q has succeeded, starting the build\n", r.after)
+		return nil
+	}
+
+	c := br.Status.GetCondition(buildv1alpha1.Succeeded)
+	if r.abortOnDependencyFailure {
+		return exitcode.Errorf(exitcode.Failure, "BuildRun %q has not succeeded because of %s: %s, the build is not started",
+			r.after, c.GetReason(), c.GetMessage())
+	}
+	fmt.Fprintf(ioStreams.ErrOut, "Warning: BuildRun %q has not succeeded because of %s: %s, starting the build anyway\n",
+		r.after, c.GetReason(), c.GetMessage())
+	return nil
+}
@@ -0,0 +1,75 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/attest"
+)
+
+// envOutputFormat output format printing the outcome of the BuildRun as shell variables.
+const envOutputFormat = "env"
+
+// envStreams returns the streams with the standard output redirected to the standard error, thus
+// only the shell variables are written on the standard output.
+func envStreams(ioStreams *genericclioptions.IOStreams) *genericclioptions.IOStreams {
+	return &genericclioptions.IOStreams{In: ioStreams.In, Out: ioStreams.ErrOut, ErrOut: ioStreams.ErrOut}
+}
+
+// buildRunEnv returns the shell variables describing the outcome of the BuildRun, in a stable
+// order. The variables without a value are omitted.
+func buildRunEnv(br *buildv1alpha1.BuildRun) [][2]string {
+	env := [][2]string{
+		{"SHP_BUILDRUN_NAME", br.GetName()},
+		{"SHP_NAMESPACE", br.GetNamespace()},
+		{"SHP_BUILD_NAME", br.GetLabels()[buildv1alpha1.LabelBuild]},
+		{"SHP_IMAGE", attest.OutputImage(br)},
+	}
+	if br.Status.Output != nil && br.Status.Output.Digest != "" {
+		env = append(env, [2]string{"SHP_IMAGE_DIGEST", br.Status.Output.Digest})
+		if ref, err := attest.ImageDigestReference(br); err == nil {
+			env = append(env, [2]string{"SHP_IMAGE_REF", ref.String()})
+		}
+	}
+	for _, source := range br.Status.Sources {
+		if source.Git != nil {
+			env = append(env,
+				[2]string{"SHP_GIT_SHA", source.Git.CommitSha},
+				[2]string{"SHP_GIT_AUTHOR", source.Git.CommitAuthor},
+				[2]string{"SHP_GIT_BRANCH", source.Git.BranchName},
+			)
+		}
+		if source.Bundle != nil {
+			env = append(env, [2]string{"SHP_SOURCE_BUNDLE_DIGEST", source.Bundle.Digest})
+		}
+	}
+
+	vars := make([][2]string, 0, len(env))
+	for _, v := range env {
+		if v[1] != "" {
+			vars = append(vars, v)
+		}
+	}
+	return vars
+}
+
+// printBuildRunEnv writes the shell variables of the BuildRun, one "NAME=value" per line, with the
+// values quoted to be evaluated by the shell.
+func printBuildRunEnv(out io.Writer, br *buildv1alpha1.BuildRun) error {
+	for _, v := range buildRunEnv(br) {
+		if _, err := fmt.Fprintf(out, "%s=%s\n", v[0], shellQuote(v[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shellQuote quotes the value with single quotes, escaping the ones it carries.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,163 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/follower"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// Failures retried, informed on --retry-on.
+const (
+	retryOnFailure = "failure" // the BuildRun failed for any reason other than the timeout
+	retryOnTimeout = "timeout" // the BuildRun timed out
+)
+
+// retryOnReasons failures supported by --retry-on.
+var retryOnReasons = []string{retryOnFailure, retryOnTimeout}
+
+// retryAttempt a BuildRun created by the retries, and its outcome.
+type retryAttempt struct {
+	name    string // BuildRun name
+	outcome string // either succeeded, or the reason it failed
+}
+
+// validateRetries checks the retry flags are consistent with the others.
+func (r *RunCommand) validateRetries() error {
+	if r.retries < 0 {
+		return fmt.Errorf("--retries must not be negative")
+	}
+	if r.retries == 0 {
+		if r.cmd.Flags().Changed("retry-on") || r.cmd.Flags().Changed("retry-backoff") {
+			return fmt.Errorf("--retry-on and --retry-backoff require --retries")
+		}
+		return nil
+	}
+	switch {
+	case !r.batch.IsEmpty():
+		return fmt.Errorf("--retries can't be used along with --%s or --%s", flags.FilenameFlag, flags.SelectorFlag)
+	case !r.multiPlatform.IsEmpty():
+		return fmt.Errorf("--retries can't be used along with --%s", flags.PlatformsFlag)
+	case !r.follow && !r.wait:
+		return fmt.Errorf("--retries requires --follow or --wait")
+	case r.retryBackoff < 0:
+		return fmt.Errorf("--retry-backoff must not be negative")
+	}
+	for _, reason := range r.retryOn {
+		if reason != retryOnFailure && reason != retryOnTimeout {
+			return fmt.Errorf("unsupported --retry-on %q, expected one of %v", reason, retryOnReasons)
+		}
+	}
+	return nil
+}
+
+// retryReason returns the reason the BuildRun has failed, and whether it matches --retry-on. The
+// BuildRuns succeeded, still running, like when interrupted, or canceled are not retried.
+func (r *RunCommand) retryReason(clientset buildclientset.Interface, name string) (string, bool) {
+	br, err := clientset.ShipwrightV1alpha1().BuildRuns(r.namespace).Get(r.cmd.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", false
+	}
+	c := br.Status.GetCondition(buildv1alpha1.Succeeded)
+	if c.GetStatus() != corev1.ConditionFalse || br.IsCanceled() || c.GetReason() == buildRunReasonCanceled {
+		return c.GetReason(), false
+	}
+
+	failure := retryOnFailure
+	if c.GetReason() == follower.BuildRunReasonTimeout {
+		failure = retryOnTimeout
+	}
+	for _, reason := range r.retryOn {
+		if reason == failure {
+			return c.GetReason(), true
+		}
+	}
+	return c.GetReason(), false
+}
+
+// runWithRetries runs the BuildRun, creating a fresh one whenever it fails with a reason informed on
+// --retry-on, up to --retries times. The backoff between the attempts doubles every time. The
+// outcome of each attempt is summarized at the end, the error is the one of the last attempt.
+func (r *RunCommand) runWithRetries(
+	params *params.Params,
+	ioStreams *genericclioptions.IOStreams,
+	clientset buildclientset.Interface,
+	br *buildv1alpha1.BuildRun,
+) error {
+	ctx := r.cmd.Context()
+	attempts := []retryAttempt{}
+	backoff := r.retryBackoff
+	for attempt := 1; ; attempt++ {
+		next := br.DeepCopy()
+		if attempt > 1 {
+			// the explicit name is taken by the first attempt
+			if next.GetName() != "" {
+				next.SetName(fmt.Sprintf("%s-retry-%d", br.GetName(), attempt-1))
+			}
+			// the shared pod watcher is stopped once the previous BuildRun completes
+			if r.follow {
+				if err := r.newFollower(params, ioStreams, true); err != nil {
+					return err
+				}
+			}
+		}
+
+		name, err := r.runBuildRun(params, ioStreams, clientset, next)
+		if name == "" {
+			r.reportAttempts(params.QuietStreams(ioStreams).Out, attempts)
+			return err
+		}
+		// following the logs doesn't fail on the failed BuildRuns, the pod outcome tells instead
+		if err == nil && r.follow && !r.follower.PodSucceeded() {
+			err = exitcode.Errorf(exitcode.Failure, "BuildRun %q has failed", name)
+		}
+		if err == nil {
+			attempts = append(attempts, retryAttempt{name: name, outcome: "succeeded"})
+			r.reportAttempts(params.QuietStreams(ioStreams).Out, attempts)
+			return nil
+		}
+
+		reason, retry := r.retryReason(clientset, name)
+		outcome := "failed"
+		if reason != "" {
+			outcome = fmt.Sprintf("failed because of %s", reason)
+		}
+		attempts = append(attempts, retryAttempt{name: name, outcome: outcome})
+		if !retry || attempt > r.retries {
+			r.reportAttempts(params.QuietStreams(ioStreams).Out, attempts)
+			return err
+		}
+
+		fmt.Fprintf(ioStreams.ErrOut, "BuildRun %q %s, retrying in %s (attempt %d of %d)\n",
+			name, outcome, backoff, attempt+1, r.retries+1)
+		select {
+		case <-ctx.Done():
+			r.reportAttempts(params.QuietStreams(ioStreams).Out, attempts)
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// reportAttempts prints the outcome of each attempt, when the BuildRun has been retried.
+func (r *RunCommand) reportAttempts(w io.Writer, attempts []retryAttempt) {
+	if len(attempts) < 2 {
+		return
+	}
+	fmt.Fprintf(w, "Attempts of Build %q:\n", r.buildName)
+	for i, a := range attempts {
+		fmt.Fprintf(w, "  %d. BuildRun %q %s\n", i+1, a.name, a.outcome)
+	}
+}
@@ -2,12 +2,19 @@ package build
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/follower"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
 	"github.com/shipwright-io/cli/pkg/shp/flags"
 	"github.com/shipwright-io/cli/pkg/shp/params"
 	"github.com/shipwright-io/cli/pkg/shp/reactor"
@@ -19,6 +26,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes/fake"
 	fakekubetesting "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
 )
 
 func TestStartBuildRunFollowLog(t *testing.T) {
@@ -176,6 +184,13 @@ func TestStartBuildRunFollowLog(t *testing.T) {
 					Status: corev1.ConditionFalse,
 				},
 			}
+		case test.phase == corev1.PodSucceeded:
+			br.Status.Conditions = []buildv1alpha1.Condition{
+				{
+					Type:   buildv1alpha1.Succeeded,
+					Status: corev1.ConditionTrue,
+				},
+			}
 		case test.phase == corev1.PodRunning:
 			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
 				State: corev1.ContainerState{
@@ -225,3 +240,972 @@ func checkLog(name, text string, cmd *RunCommand, out *bytes.Buffer, t *testing.
 		t.Errorf("test %s: unexpected output: %s", name, out.String())
 	}
 }
+
+func TestStartBuildRunWait(t *testing.T) {
+	imageDigest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		name     string
+		status   corev1.ConditionStatus
+		reason   string
+		canceled bool
+		exitCode int
+	}{
+		{name: "succeeded", status: corev1.ConditionTrue, reason: "Succeeded", exitCode: exitcode.Success},
+		{name: "failed", status: corev1.ConditionFalse, reason: "Failed", exitCode: exitcode.Failure},
+		{name: "timeout", status: corev1.ConditionFalse, reason: "BuildRunTimeout", exitCode: exitcode.Timeout},
+		{name: "canceled", status: corev1.ConditionFalse, reason: "BuildRunCanceled", canceled: true, exitCode: exitcode.Cancelled},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			br := &buildv1alpha1.BuildRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: metav1.NamespaceDefault,
+					Name:      "testbuild-abcde",
+				},
+				Status: buildv1alpha1.BuildRunStatus{
+					Conditions: buildv1alpha1.Conditions{{
+						Type:   buildv1alpha1.Succeeded,
+						Status: test.status,
+						Reason: test.reason,
+					}},
+					BuildSpec: &buildv1alpha1.BuildSpec{
+						Output: buildv1alpha1.Image{Image: "registry.example.com/org/app:latest"},
+					},
+					Output: &buildv1alpha1.Output{Digest: imageDigest},
+				},
+			}
+			if test.canceled {
+				br.Spec.State = buildv1alpha1.BuildRunRequestedStatePtr(buildv1alpha1.BuildRunStateCancel)
+			}
+
+			shpclientset := shpfake.NewSimpleClientset()
+			shpclientset.PrependReactor("create", "buildruns", func(_ fakekubetesting.Action) (bool, kruntime.Object, error) {
+				return true, br, nil
+			})
+			shpclientset.PrependReactor("get", "buildruns", func(_ fakekubetesting.Action) (bool, kruntime.Object, error) {
+				return true, br, nil
+			})
+
+			ccmd := &cobra.Command{}
+			cmd := &RunCommand{
+				cmd:          ccmd,
+				buildRunSpec: flags.BuildRunSpecFromFlags(ccmd.Flags()),
+				wait:         true,
+
+				imageDigestFile: filepath.Join(t.TempDir(), "image-ref.txt"),
+			}
+			cmd.Cmd().ExecuteC()
+			param := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, metav1.NamespaceDefault, nil, nil)
+			ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+
+			if err := cmd.Complete(param, &ioStreams, []string{"testbuild"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := cmd.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			err := cmd.Run(param, &ioStreams)
+			if code := exitcode.FromError(err); code != test.exitCode {
+				t.Errorf("expected exit code %d, got %d (error: %v)", test.exitCode, code, err)
+			}
+
+			data, err := os.ReadFile(cmd.imageDigestFile)
+			switch {
+			case test.exitCode != exitcode.Success:
+				if err == nil {
+					t.Errorf("unexpected image digest file for a unsuccessful BuildRun")
+				}
+			case err != nil:
+				t.Errorf("unable to read image digest file: %v", err)
+			case string(data) != "registry.example.com/org/app@"+imageDigest+"\n":
+				t.Errorf("unexpected image digest reference %q", string(data))
+			}
+		})
+	}
+}
+
+func TestRunEnvOutput(t *testing.T) {
+	imageDigest := "sha256:" + strings.Repeat("a", 64)
+	br := &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "testbuild-abcde",
+			Labels:    map[string]string{buildv1alpha1.LabelBuild: "testbuild"},
+		},
+		Status: buildv1alpha1.BuildRunStatus{
+			Conditions: buildv1alpha1.Conditions{{
+				Type:   buildv1alpha1.Succeeded,
+				Status: corev1.ConditionTrue,
+				Reason: "Succeeded",
+			}},
+			BuildSpec: &buildv1alpha1.BuildSpec{
+				Output: buildv1alpha1.Image{Image: "registry.example.com/org/app:latest"},
+			},
+			Output: &buildv1alpha1.Output{Digest: imageDigest},
+			Sources: []buildv1alpha1.SourceResult{{
+				Name: "default",
+				Git:  &buildv1alpha1.GitSourceResult{CommitSha: "0123abc", CommitAuthor: "Jane O'Hara"},
+			}},
+		},
+	}
+
+	shpclientset := shpfake.NewSimpleClientset()
+	shpclientset.PrependReactor("create", "buildruns", func(_ fakekubetesting.Action) (bool, kruntime.Object, error) {
+		return true, br, nil
+	})
+	shpclientset.PrependReactor("get", "buildruns", func(_ fakekubetesting.Action) (bool, kruntime.Object, error) {
+		return true, br, nil
+	})
+
+	cmd := runCmd().(*RunCommand)
+	cmd.cmd.SetContext(context.TODO())
+	if err := cmd.Cmd().ParseFlags([]string{"--wait", "-o", "env"}); err != nil {
+		t.Fatal(err)
+	}
+	param := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, metav1.NamespaceDefault, nil, nil)
+	ioStreams, _, out, errOut := genericclioptions.NewTestIOStreams()
+	if err := cmd.Complete(param, &ioStreams, []string{"testbuild"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Run(param, &ioStreams); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "SHP_BUILDRUN_NAME='testbuild-abcde'\n" +
+		"SHP_NAMESPACE='default'\n" +
+		"SHP_BUILD_NAME='testbuild'\n" +
+		"SHP_IMAGE='registry.example.com/org/app:latest'\n" +
+		"SHP_IMAGE_DIGEST='" + imageDigest + "'\n" +
+		"SHP_IMAGE_REF='registry.example.com/org/app@" + imageDigest + "'\n" +
+		"SHP_GIT_SHA='0123abc'\n" +
+		"SHP_GIT_AUTHOR='Jane O'\\''Hara'\n"
+	if out.String() != expected {
+		t.Errorf("unexpected shell variables:\n%s", out.String())
+	}
+	if !strings.Contains(errOut.String(), `BuildRun "testbuild-abcde" has succeeded`) {
+		t.Errorf("expected the messages on the standard error, got %q", errOut.String())
+	}
+}
+
+func TestRunAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		status   corev1.ConditionStatus
+		missing  bool
+		created  bool
+		exitCode int
+		errOut   string
+	}{
+		{name: "succeeded", status: corev1.ConditionTrue, created: true},
+		{name: "failed", status: corev1.ConditionFalse, exitCode: exitcode.Failure},
+		{name: "failed without aborting", args: []string{"--abort-on-dependency-failure=false"}, status: corev1.ConditionFalse,
+			created: true, errOut: `Warning: BuildRun "base-42" has not succeeded because of Failed: step failed, starting the build anyway`},
+		{name: "not found", missing: true, exitCode: exitcode.NotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dependency := &buildv1alpha1.BuildRun{
+				ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "base-42"},
+				Status: buildv1alpha1.BuildRunStatus{
+					Conditions: buildv1alpha1.Conditions{{
+						Type:    buildv1alpha1.Succeeded,
+						Status:  test.status,
+						Reason:  "Failed",
+						Message: "step failed",
+					}},
+				},
+			}
+			shpclientset := shpfake.NewSimpleClientset()
+			if !test.missing {
+				shpclientset = shpfake.NewSimpleClientset(dependency)
+			}
+			created := false
+			shpclientset.PrependReactor("create", "buildruns", func(action fakekubetesting.Action) (bool, kruntime.Object, error) {
+				created = true
+				br := action.(fakekubetesting.CreateAction).GetObject().(*buildv1alpha1.BuildRun)
+				br.Name = "app-abcde"
+				return true, br, nil
+			})
+
+			cmd := runCmd().(*RunCommand)
+			cmd.cmd.SetContext(context.TODO())
+			if err := cmd.Cmd().ParseFlags(append([]string{"--after=base-42"}, test.args...)); err != nil {
+				t.Fatal(err)
+			}
+			param := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, metav1.NamespaceDefault, nil, nil)
+			ioStreams, _, out, errOut := genericclioptions.NewTestIOStreams()
+			if err := cmd.Complete(param, &ioStreams, []string{"app"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := cmd.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			err := cmd.Run(param, &ioStreams)
+			if code := exitcode.FromError(err); code != test.exitCode {
+				t.Errorf("expected exit code %d, got %d (error: %v)", test.exitCode, code, err)
+			}
+			if created != test.created {
+				t.Errorf("expected BuildRun created to be %v, output: %s", test.created, out.String())
+			}
+			if !strings.Contains(errOut.String(), test.errOut) {
+				t.Errorf("unexpected standard error: %q", errOut.String())
+			}
+		})
+	}
+
+	cmd := runCmd().(*RunCommand)
+	if err := cmd.Cmd().ParseFlags([]string{"--abort-on-dependency-failure=false"}); err != nil {
+		t.Fatal(err)
+	}
+	cmd.buildName = "app"
+	if err := cmd.Validate(); err == nil || err.Error() != "--abort-on-dependency-failure requires --after" {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+}
+
+func TestRunEnvOutputValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "without waiting", args: []string{"-o", "env"}, err: "--output env requires --follow or --wait"},
+		{name: "with metrics", args: []string{"--follow", "--show-metrics", "-o", "env"},
+			err: "--output env can't be used along with --show-metrics"},
+		{name: "batch", args: []string{"--selector=app=web", "-o", "env"},
+			err: "--output env can't be used along with --filename or --selector"},
+		{name: "platforms", args: []string{"--platforms=linux/amd64", "--wait", "-o", "env"},
+			err: "--output env can't be used along with --platforms"},
+		{name: "follow", args: []string{"--follow", "-o", "env"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := runCmd().(*RunCommand)
+			if err := cmd.cmd.ParseFlags(test.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+			if cmd.batch.IsEmpty() {
+				cmd.buildName = "testbuild"
+			}
+			err := cmd.Validate()
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.err != "" && (err == nil || err.Error() != test.err):
+				t.Errorf("expected error %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestRunSourceBundleValidate(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "no source bundle", args: []string{}},
+		{name: "source bundle", args: []string{"--source-bundle-image=ghcr.io/org/source", "--source-bundle-dir=" + dir}},
+		{name: "prune without image", args: []string{"--source-bundle-prune=AfterPull"}, wantErr: true},
+		{name: "registry auth without image", args: []string{"--registry-auth=ecr"}, wantErr: true},
+		{name: "missing directory", args: []string{"--source-bundle-image=ghcr.io/org/source", "--source-bundle-dir=" + filepath.Join(dir, "missing")}, wantErr: true},
+		{name: "unsupported registry auth", args: []string{"--source-bundle-image=ghcr.io/org/source", "--source-bundle-dir=" + dir, "--registry-auth=quay"}, wantErr: true},
+		{name: "revision override with source bundle", args: []string{"--source-bundle-image=ghcr.io/org/source", "--source-bundle-dir=" + dir, "--source-revision=main"}, wantErr: true},
+		{name: "context dir override with source bundle", args: []string{"--source-bundle-image=ghcr.io/org/source", "--source-bundle-dir=" + dir, "--source-context-dir=app"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := runCmd().(*RunCommand)
+			if err := cmd.Cmd().ParseFlags(test.args); err != nil {
+				t.Fatal(err)
+			}
+			cmd.buildName = "testbuild"
+			err := cmd.Validate()
+			if test.wantErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !test.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunWaitFailureLogs(t *testing.T) {
+	br := &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "testbuild-abcde",
+		},
+		Status: buildv1alpha1.BuildRunStatus{
+			Conditions: buildv1alpha1.Conditions{{
+				Type:   buildv1alpha1.Succeeded,
+				Status: corev1.ConditionFalse,
+				Reason: "Failed",
+			}},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "testbuild-abcde-pod",
+			Labels:    map[string]string{buildv1alpha1.LabelBuildRun: br.Name},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "step-source-default",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+			}, {
+				Name:  "step-build",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2}},
+			}},
+		},
+	}
+
+	shpclientset := shpfake.NewSimpleClientset()
+	shpclientset.PrependReactor("create", "buildruns", func(_ fakekubetesting.Action) (bool, kruntime.Object, error) {
+		return true, br, nil
+	})
+	shpclientset.PrependReactor("get", "buildruns", func(_ fakekubetesting.Action) (bool, kruntime.Object, error) {
+		return true, br, nil
+	})
+
+	cmd := runCmd().(*RunCommand)
+	cmd.Cmd().SetContext(context.TODO())
+	if err := cmd.Cmd().ParseFlags([]string{"--wait"}); err != nil {
+		t.Fatal(err)
+	}
+	param := params.NewParamsForTest(fake.NewSimpleClientset(pod), shpclientset, nil, metav1.NamespaceDefault, nil, nil)
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+	if err := cmd.Complete(param, &ioStreams, []string{"testbuild"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if code := exitcode.FromError(cmd.Run(param, &ioStreams)); code != exitcode.Failure {
+		t.Errorf("expected exit code %d, got %d", exitcode.Failure, code)
+	}
+	if !strings.Contains(out.String(), "Last 20 lines of the failed step \"build\":\n\nfake logs") {
+		t.Errorf("expected the logs of the failed step, got %q", out.String())
+	}
+}
+
+func TestRunCommandUIRequiresFollow(t *testing.T) {
+	cmd := runCmd().(*RunCommand)
+	if err := cmd.cmd.ParseFlags([]string{"--ui"}); err != nil {
+		t.Fatalf("unexpected error parsing flags: %v", err)
+	}
+	cmd.buildName = "testbuild"
+	if err := cmd.Validate(); err == nil || err.Error() != "--ui requires --follow" {
+		t.Errorf("expected --ui to require --follow, got %v", err)
+	}
+}
+
+func TestRunHeartbeatValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "without following", args: []string{"--heartbeat-interval=30s"},
+			err: "--heartbeat-interval requires --follow"},
+		{name: "negative", args: []string{"--follow", "--heartbeat-interval=-1s"},
+			err: "--heartbeat-interval must not be negative"},
+		{name: "following", args: []string{"--follow", "--heartbeat-interval=30s"}},
+		{name: "disabled", args: []string{"--follow", "--heartbeat-interval=0"}},
+		{name: "default without following", args: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := runCmd().(*RunCommand)
+			if err := cmd.cmd.ParseFlags(test.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+			cmd.buildName = "testbuild"
+			err := cmd.Validate()
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.err != "" && (err == nil || err.Error() != test.err):
+				t.Errorf("expected error %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestRunSourceOverride(t *testing.T) {
+	shpclientset := shpfake.NewSimpleClientset(&buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "testbuild"},
+		Spec: buildv1alpha1.BuildSpec{
+			Source: buildv1alpha1.Source{
+				URL:        pointer.String("https://github.com/org/repo"),
+				Revision:   pointer.String("main"),
+				ContextDir: pointer.String("."),
+			},
+			Strategy: buildv1alpha1.Strategy{Name: "buildah"},
+		},
+	})
+	var created *buildv1alpha1.BuildRun
+	shpclientset.PrependReactor("create", "buildruns", func(action fakekubetesting.Action) (bool, kruntime.Object, error) {
+		created = action.(fakekubetesting.CreateAction).GetObject().(*buildv1alpha1.BuildRun)
+		created.Name = "testbuild-abcde"
+		return true, created, nil
+	})
+
+	cmd := runCmd().(*RunCommand)
+	cmd.Cmd().SetContext(context.TODO())
+	if err := cmd.Cmd().ParseFlags([]string{"--source-revision=feature", "--source-context-dir=services/api"}); err != nil {
+		t.Fatal(err)
+	}
+	param := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, metav1.NamespaceDefault, nil, nil)
+	ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+	if err := cmd.Complete(param, &ioStreams, []string{"testbuild"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Run(param, &ioStreams); err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case created == nil:
+		t.Fatal("expected BuildRun to be created")
+	case created.Spec.BuildRef != nil:
+		t.Errorf("expected the Build's specification to be embedded, got reference %v", created.Spec.BuildRef)
+	case created.Spec.BuildSpec == nil:
+		t.Fatal("expected the Build's specification to be embedded")
+	}
+	source := created.Spec.BuildSpec.Source
+	if *source.URL != "https://github.com/org/repo" || *source.Revision != "feature" || *source.ContextDir != "services/api" {
+		t.Errorf("unexpected source %q, revision %q and context dir %q", *source.URL, *source.Revision, *source.ContextDir)
+	}
+	if created.Spec.BuildSpec.Strategy.Name != "buildah" {
+		t.Errorf("expected the Build's strategy to be preserved, got %q", created.Spec.BuildSpec.Strategy.Name)
+	}
+}
+
+func TestRunPlatforms(t *testing.T) {
+	imageDigest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		name     string
+		failArch string
+		exitCode int
+	}{
+		{name: "succeeded", exitCode: exitcode.Success},
+		{name: "one platform failed", failArch: "arm64", exitCode: exitcode.Failure},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			created := map[string]*buildv1alpha1.BuildRun{}
+			shpclientset := shpfake.NewSimpleClientset()
+			shpclientset.PrependReactor("create", "buildruns", func(action fakekubetesting.Action) (bool, kruntime.Object, error) {
+				br := action.(fakekubetesting.CreateAction).GetObject().(*buildv1alpha1.BuildRun)
+				br.Name = br.GenerateName + "abcde"
+				created[br.Name] = br
+				return true, br, nil
+			})
+			shpclientset.PrependReactor("get", "buildruns", func(action fakekubetesting.Action) (bool, kruntime.Object, error) {
+				br := created[action.(fakekubetesting.GetAction).GetName()].DeepCopy()
+				status, reason := corev1.ConditionTrue, "Succeeded"
+				if test.failArch != "" && strings.Contains(br.Name, test.failArch) {
+					status, reason = corev1.ConditionFalse, "Failed"
+				}
+				br.Status = buildv1alpha1.BuildRunStatus{
+					Conditions: buildv1alpha1.Conditions{{Type: buildv1alpha1.Succeeded, Status: status, Reason: reason}},
+					BuildSpec: &buildv1alpha1.BuildSpec{
+						Output: buildv1alpha1.Image{Image: "registry.example.com/org/app:latest"},
+					},
+					Output: &buildv1alpha1.Output{Digest: imageDigest},
+				}
+				return true, br, nil
+			})
+
+			cmd := runCmd().(*RunCommand)
+			cmd.Cmd().SetContext(context.TODO())
+			if err := cmd.Cmd().ParseFlags([]string{"--platforms=linux/amd64,linux/arm64", "--wait", "--failure-log-lines=0"}); err != nil {
+				t.Fatal(err)
+			}
+			param := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, metav1.NamespaceDefault, nil, nil)
+			ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+			if err := cmd.Complete(param, &ioStreams, []string{"testbuild"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := cmd.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			err := cmd.Run(param, &ioStreams)
+			if code := exitcode.FromError(err); code != test.exitCode {
+				t.Errorf("expected exit code %d, got %d (error: %v)", test.exitCode, code, err)
+			}
+			if test.failArch != "" && (err == nil || !strings.Contains(err.Error(), "1 of 2 platforms failed, linux/arm64: ")) {
+				t.Errorf("expected the failed platform to be reported, got %v", err)
+			}
+
+			for _, platform := range []string{"linux/amd64", "linux/arm64"} {
+				name := "testbuild-" + strings.ReplaceAll(platform, "/", "-") + "-abcde"
+				br, ok := created[name]
+				if !ok {
+					t.Fatalf("expected BuildRun %q to be created, got %v", name, created)
+				}
+				params := br.Spec.ParamValues
+				if len(params) != 1 || params[0].Name != "platform" || *params[0].Value != platform {
+					t.Errorf("expected the platform parameter %q on BuildRun %q, got %v", platform, name, params)
+				}
+				if !strings.Contains(out.String(), "["+platform+"] Waiting for BuildRun \""+name+"\" to finish...\n") {
+					t.Errorf("expected the output prefixed by the platform %q, got %q", platform, out.String())
+				}
+			}
+			if !strings.Contains(out.String(), "[linux/amd64] BuildRun \"testbuild-linux-amd64-abcde\" produced image registry.example.com/org/app@"+imageDigest) {
+				t.Errorf("expected the image produced to be reported, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestRunPlatformsValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "manifest list without platforms", args: []string{"--manifest-list", "--wait"},
+			err: "--platforms must be informed when using the other platform flags"},
+		{name: "invalid platform", args: []string{"--platforms=linux"},
+			err: `invalid platform "linux", expected the "os/arch[/variant]" format`},
+		{name: "manifest list without waiting", args: []string{"--platforms=linux/amd64", "--manifest-list"},
+			err: "--manifest-list requires --follow or --wait"},
+		{name: "image digest file without manifest list", args: []string{"--platforms=linux/amd64", "--wait", "--image-digest-file=ref.txt"},
+			err: "--image-digest-file requires --manifest-list along with --platforms"},
+		{name: "registry auth without manifest list", args: []string{"--platforms=linux/amd64", "--registry-auth=secret"},
+			err: "--source-bundle-image, --manifest-list or --additional-output-image must be informed when using the registry authentication flags"},
+		{name: "manifest list with registry auth", args: []string{"--platforms=linux/amd64", "--follow", "--manifest-list", "--registry-auth=secret"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := runCmd().(*RunCommand)
+			if err := cmd.cmd.ParseFlags(test.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+			cmd.buildName = "testbuild"
+			err := cmd.Validate()
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.err != "" && (err == nil || err.Error() != test.err):
+				t.Errorf("expected error %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestRunSignValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "sign without waiting", args: []string{"--sign"},
+			err: "--sign requires --follow or --wait, the image is signed after the run succeeds"},
+		{name: "sign key without sign", args: []string{"--wait", "--sign-key=cosign.key"},
+			err: "--sign-key requires --sign"},
+		{name: "sign with platforms", args: []string{"--platforms=linux/amd64", "--wait", "--sign"},
+			err: "--sign can't be used along with --platforms"},
+		{name: "keyless", args: []string{"--follow", "--sign"}},
+		{name: "key based", args: []string{"--wait", "--sign", "--sign-key=cosign.key"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := runCmd().(*RunCommand)
+			if err := cmd.cmd.ParseFlags(test.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+			cmd.buildName = "testbuild"
+			err := cmd.Validate()
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.err != "" && (err == nil || err.Error() != test.err):
+				t.Errorf("expected error %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestRunAdditionalOutputImageValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "without waiting", args: []string{"--additional-output-image=quay.io/org/app:v1"},
+			err: "--additional-output-image requires --follow or --wait, the image is copied after the run succeeds"},
+		{name: "by digest", args: []string{"--wait", "--additional-output-image=quay.io/org/app@sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb"},
+			err: `invalid image reference "quay.io/org/app@sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb", expected a tagged image: ` +
+				`repository can only contain the characters ` + "`abcdefghijklmnopqrstuvwxyz0123456789_-./`" + `: org/app@sha256`},
+		{name: "platforms without manifest list", args: []string{"--platforms=linux/amd64", "--wait", "--additional-output-image=quay.io/org/app:v1"},
+			err: "--additional-output-image requires --manifest-list along with --platforms"},
+		{name: "batch", args: []string{"--selector=app=web", "--additional-output-image=quay.io/org/app:v1"},
+			err: "--additional-output-image can't be used along with --filename or --selector"},
+		{name: "multiple with registry auth", args: []string{"--follow", "--registry-auth=secret",
+			"--additional-output-image=quay.io/org/app:v1", "--additional-output-image=registry.example.com/mirror/app:v1"}},
+		{name: "manifest list", args: []string{"--platforms=linux/amd64", "--wait", "--manifest-list", "--additional-output-image=quay.io/org/app:v1"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := runCmd().(*RunCommand)
+			if err := cmd.cmd.ParseFlags(test.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+			if cmd.batch.IsEmpty() {
+				cmd.buildName = "testbuild"
+			}
+			err := cmd.Validate()
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.err != "" && (err == nil || err.Error() != test.err):
+				t.Errorf("expected error %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		manifest string
+		exitCode int
+		summary  []string
+		err      string
+	}{
+		{name: "selector", args: []string{"--selector=team=platform", "--failure-log-lines=0"},
+			exitCode: exitcode.Failure,
+			summary:  []string{"a a-abcde Succeeded", "b b-abcde Failed", "c c-abcde Succeeded"},
+			err:      "1 of 3 builds failed, b: BuildRun \"b-abcde\" has failed because of Failed: "},
+		{name: "fail fast", args: []string{"--selector=team=platform", "--failure-log-lines=0", "--concurrency=1", "--fail-fast"},
+			exitCode: exitcode.Failure,
+			summary:  []string{"a a-abcde Succeeded", "b b-abcde Failed", "c Skipped"},
+			err:      "1 of 3 builds failed, 1 skipped, b: "},
+		{name: "manifests", args: []string{"--filename=-"},
+			manifest: "apiVersion: shipwright.io/v1alpha1\nkind: Build\nmetadata:\n  name: c\n" +
+				"---\napiVersion: shipwright.io/v1alpha1\nkind: Build\nmetadata:\n  name: a\n",
+			exitCode: exitcode.Success,
+			summary:  []string{"BUILD BUILDRUN STATUS DURATION c c-abcde Succeeded 0s a a-abcde Succeeded 0s"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			builds := []kruntime.Object{}
+			for _, name := range []string{"c", "a", "b"} {
+				builds = append(builds, &buildv1alpha1.Build{ObjectMeta: metav1.ObjectMeta{
+					Namespace: metav1.NamespaceDefault,
+					Name:      name,
+					Labels:    map[string]string{"team": "platform"},
+				}})
+			}
+			lock := sync.Mutex{}
+			created := map[string]*buildv1alpha1.BuildRun{}
+			shpclientset := shpfake.NewSimpleClientset(builds...)
+			shpclientset.PrependReactor("create", "buildruns", func(action fakekubetesting.Action) (bool, kruntime.Object, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				br := action.(fakekubetesting.CreateAction).GetObject().(*buildv1alpha1.BuildRun)
+				br.Name = br.GenerateName + "abcde"
+				created[br.Name] = br
+				return true, br, nil
+			})
+			shpclientset.PrependReactor("get", "buildruns", func(action fakekubetesting.Action) (bool, kruntime.Object, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				br := created[action.(fakekubetesting.GetAction).GetName()].DeepCopy()
+				status, reason := corev1.ConditionTrue, "Succeeded"
+				if br.Spec.BuildRef.Name == "b" {
+					status, reason = corev1.ConditionFalse, "Failed"
+				}
+				br.Status.Conditions = buildv1alpha1.Conditions{{Type: buildv1alpha1.Succeeded, Status: status, Reason: reason}}
+				return true, br, nil
+			})
+
+			cmd := runCmd().(*RunCommand)
+			cmd.Cmd().SetContext(context.TODO())
+			if err := cmd.Cmd().ParseFlags(test.args); err != nil {
+				t.Fatal(err)
+			}
+			param := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, metav1.NamespaceDefault, nil, nil)
+			ioStreams, in, out, _ := genericclioptions.NewTestIOStreams()
+			in.WriteString(test.manifest)
+			if err := cmd.Complete(param, &ioStreams, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := cmd.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			err := cmd.Run(param, &ioStreams)
+			if code := exitcode.FromError(err); code != test.exitCode {
+				t.Errorf("expected exit code %d, got %d (error: %v)", test.exitCode, code, err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("expected error containing %q, got %v", test.err, err)
+			}
+			if !strings.Contains(out.String(), "[a] Waiting for BuildRun \"a-abcde\" to finish...\n") {
+				t.Errorf("expected the output prefixed by the Build name, got %q", out.String())
+			}
+			// the summary table columns are compared regardless of their padding
+			summary := strings.Join(strings.Fields(out.String()), " ")
+			for _, row := range test.summary {
+				if !strings.Contains(summary, row) {
+					t.Errorf("expected the summary row %q, got %q", row, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunBatchValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "fail fast without builds", args: []string{"--fail-fast"},
+			err: "--filename or --selector must be informed when using the other batch flags"},
+		{name: "filename and selector", args: []string{"--filename=builds.yaml", "--selector=team=platform"},
+			err: "--filename and --selector are mutually exclusive"},
+		{name: "no concurrency", args: []string{"--selector=team=platform", "--concurrency=0"},
+			err: "--concurrency must be at least 1"},
+		{name: "follow", args: []string{"--selector=team=platform", "--follow"},
+			err: "--follow can't be used along with --filename or --selector, the BuildRuns are waited"},
+		{name: "buildrun name", args: []string{"--selector=team=platform", "--buildrun-name=nightly"},
+			err: "--buildrun-name can't be used along with --filename or --selector"},
+		{name: "output image", args: []string{"--selector=team=platform", "--output-image=ghcr.io/org/app"},
+			err: "--output-image can't be used along with --filename or --selector"},
+		{name: "selector with wait timeout", args: []string{"--selector=team=platform", "--concurrency=2", "--wait-timeout=10m"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := runCmd().(*RunCommand)
+			if err := cmd.cmd.ParseFlags(test.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+			param := params.NewParamsForTest(nil, nil, nil, metav1.NamespaceDefault, nil, nil)
+			ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+			if cmd.batch.IsEmpty() {
+				cmd.buildName = "testbuild"
+			} else if err := cmd.Complete(param, &ioStreams, nil); err != nil {
+				t.Fatal(err)
+			}
+			err := cmd.Validate()
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.err != "" && (err == nil || err.Error() != test.err):
+				t.Errorf("expected error %q, got %v", test.err, err)
+			}
+		})
+	}
+
+	cmd := runCmd().(*RunCommand)
+	if err := cmd.cmd.ParseFlags([]string{"--selector=team=platform"}); err != nil {
+		t.Fatal(err)
+	}
+	err := cmd.Complete(params.NewParamsForTest(nil, nil, nil, metav1.NamespaceDefault, nil, nil), nil, []string{"testbuild"})
+	if err == nil || err.Error() != "build name can't be informed along with --filename or --selector" {
+		t.Errorf("expected the build name to be rejected, got %v", err)
+	}
+}
+
+func TestRunLocalPortForwardValidate(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{args: []string{"--local-port-forward=5005"}, err: "--local-port-forward requires --follow"},
+		{args: []string{"--follow", "--local-port-forward=debug"}, err: `invalid remote port in "debug", expected [LOCAL:]REMOTE`},
+		{args: []string{"--follow", "--local-port-forward=5005", "--platforms=linux/amd64"}, err: "--local-port-forward can't be used along with --platforms"},
+		{args: []string{"--follow", "--local-port-forward=5005", "--local-port-forward=8080:80"}},
+	}
+	for _, tt := range tests {
+		cmd := runCmd().(*RunCommand)
+		if err := cmd.Cmd().ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		cmd.buildName = "app"
+		err := cmd.Validate()
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%v: unexpected validation error: %v", tt.args, err)
+		case tt.err != "" && (err == nil || err.Error() != tt.err):
+			t.Errorf("%v: expected validation error %q, got: %v", tt.args, tt.err, err)
+		}
+	}
+}
+
+func TestRunRetries(t *testing.T) {
+	const (
+		failed    = "Failed"
+		succeeded = "Succeeded"
+	)
+	tests := []struct {
+		name     string
+		args     []string
+		follow   bool     // follow the logs instead of waiting
+		outcomes []string // Succeeded condition reason of each BuildRun created
+		exitCode int
+		out      string
+		errOut   string
+	}{{
+		name:     "succeeding on the last attempt",
+		args:     []string{"--retries=2"},
+		outcomes: []string{failed, failed, succeeded},
+		out: "Attempts of Build \"app\":\n" +
+			"  1. BuildRun \"app-1\" failed because of Failed\n" +
+			"  2. BuildRun \"app-2\" failed because of Failed\n" +
+			"  3. BuildRun \"app-3\" succeeded\n",
+		errOut: `BuildRun "app-2" failed because of Failed, retrying in 2ms (attempt 3 of 3)`,
+	}, {
+		name:     "failing every attempt",
+		args:     []string{"--retries=1"},
+		outcomes: []string{failed, failed},
+		exitCode: exitcode.Failure,
+		out:      "  2. BuildRun \"app-2\" failed because of Failed\n",
+	}, {
+		name:     "timeout not retried by default",
+		args:     []string{"--retries=1"},
+		outcomes: []string{follower.BuildRunReasonTimeout},
+		exitCode: exitcode.Timeout,
+	}, {
+		name:     "timeout retried",
+		args:     []string{"--retries=1", "--retry-on=timeout"},
+		outcomes: []string{follower.BuildRunReasonTimeout, succeeded},
+		out:      "  1. BuildRun \"app-1\" failed because of BuildRunTimeout\n",
+	}, {
+		name:     "canceled not retried",
+		args:     []string{"--retries=1"},
+		outcomes: []string{buildRunReasonCanceled},
+		exitCode: exitcode.Cancelled,
+	}, {
+		name:     "following every attempt",
+		args:     []string{"--retries=1"},
+		follow:   true,
+		outcomes: []string{failed, failed},
+		exitCode: exitcode.Failure,
+		out:      "  2. BuildRun \"app-2\" failed because of Failed\n",
+	}, {
+		name:     "following the attempt succeeding",
+		args:     []string{"--retries=1"},
+		follow:   true,
+		outcomes: []string{failed, succeeded},
+		out:      "  2. BuildRun \"app-2\" succeeded\n",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kclientset := fake.NewSimpleClientset()
+			shpclientset := shpfake.NewSimpleClientset()
+			created := 0
+			shpclientset.PrependReactor("create", "buildruns", func(action fakekubetesting.Action) (bool, kruntime.Object, error) {
+				br := action.(fakekubetesting.CreateAction).GetObject().(*buildv1alpha1.BuildRun)
+				status := corev1.ConditionFalse
+				if test.outcomes[created] == succeeded {
+					status = corev1.ConditionTrue
+				}
+				created++
+				br.Name = fmt.Sprintf("app-%d", created)
+				br.Status.Conditions = buildv1alpha1.Conditions{{
+					Type:   buildv1alpha1.Succeeded,
+					Status: status,
+					Reason: test.outcomes[created-1],
+				}}
+				// the pod completes before the logs are followed, it's found by listing the pods
+				phase := corev1.PodFailed
+				if status == corev1.ConditionTrue {
+					phase = corev1.PodSucceeded
+				}
+				_, err := kclientset.CoreV1().Pods(metav1.NamespaceDefault).Create(context.TODO(), &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: metav1.NamespaceDefault,
+						Name:      br.Name + "-pod",
+						Labels:    map[string]string{buildv1alpha1.LabelBuildRun: br.Name},
+					},
+					Status: corev1.PodStatus{Phase: phase},
+				}, metav1.CreateOptions{})
+				// the default reactor stores the BuildRun
+				return false, nil, err
+			})
+
+			cmd := runCmd().(*RunCommand)
+			cmd.cmd.SetContext(context.TODO())
+			mode := "--wait"
+			if test.follow {
+				mode = "--follow"
+			}
+			args := append([]string{mode, "--failure-log-lines=0", "--retry-backoff=1ms"}, test.args...)
+			if err := cmd.Cmd().ParseFlags(args); err != nil {
+				t.Fatal(err)
+			}
+			failureDuration := time.Millisecond
+			param := params.NewParamsForTest(kclientset, shpclientset, nil, metav1.NamespaceDefault, &failureDuration, &failureDuration)
+			ioStreams, _, out, errOut := genericclioptions.NewTestIOStreams()
+			if err := cmd.Complete(param, &ioStreams, []string{"app"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := cmd.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			err := cmd.Run(param, &ioStreams)
+			if code := exitcode.FromError(err); code != test.exitCode {
+				t.Errorf("expected exit code %d, got %d (error: %v)", test.exitCode, code, err)
+			}
+			if created != len(test.outcomes) {
+				t.Errorf("expected %d BuildRuns created, got %d", len(test.outcomes), created)
+			}
+			if !strings.Contains(out.String(), test.out) {
+				t.Errorf("unexpected standard output: %q", out.String())
+			}
+			if !strings.Contains(errOut.String(), test.errOut) {
+				t.Errorf("unexpected standard error: %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestRunRetriesValidate(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{args: []string{"--retries=-1"}, err: "--retries must not be negative"},
+		{args: []string{"--retry-on=timeout"}, err: "--retry-on and --retry-backoff require --retries"},
+		{args: []string{"--retries=1"}, err: "--retries requires --follow or --wait"},
+		{args: []string{"--retries=1", "--wait", "--retry-on=oom"}, err: `unsupported --retry-on "oom", expected one of [failure timeout]`},
+		{args: []string{"--retries=1", "--wait", "--retry-backoff=-1s"}, err: "--retry-backoff must not be negative"},
+		{args: []string{"--retries=1", "--wait", "--platforms=linux/amd64"}, err: "--retries can't be used along with --platforms"},
+		{args: []string{"--retries=1", "--follow", "--retry-on=failure,timeout"}},
+	}
+	for _, tt := range tests {
+		cmd := runCmd().(*RunCommand)
+		if err := cmd.Cmd().ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		cmd.buildName = "app"
+		err := cmd.Validate()
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%v: unexpected validation error: %v", tt.args, err)
+		case tt.err != "" && (err == nil || err.Error() != tt.err):
+			t.Errorf("%v: expected validation error %q, got: %v", tt.args, tt.err, err)
+		}
+	}
+}
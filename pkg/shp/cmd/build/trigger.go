@@ -0,0 +1,141 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// triggerTypes the trigger types supported by the Build API.
+var triggerTypes = []buildv1alpha1.TriggerType{
+	buildv1alpha1.GitHubWebHookTrigger,
+	buildv1alpha1.ImageTrigger,
+	buildv1alpha1.PipelineTrigger,
+}
+
+// triggerCmd instantiate the "trigger" command, grouping the subcommands managing the Build's
+// ".spec.trigger" stanza.
+func triggerCmd(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "trigger",
+		Short: "Manage Build triggers",
+	}
+
+	command.AddCommand(
+		runner.NewRunner(p, ioStreams, triggerAddCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, triggerRemoveCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, triggerListCmd()).Cmd(),
+	)
+	return command
+}
+
+// parseTriggerType finds the trigger type informed, case insensitive.
+func parseTriggerType(s string) (buildv1alpha1.TriggerType, error) {
+	for _, t := range triggerTypes {
+		if strings.EqualFold(string(t), s) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown trigger type %q, expected one of %v", s, triggerTypes)
+}
+
+// parseGitHubEvent finds the GitHub event name informed, case insensitive.
+func parseGitHubEvent(s string) (buildv1alpha1.GitHubEventName, error) {
+	for _, e := range []buildv1alpha1.GitHubEventName{
+		buildv1alpha1.GitHubPushEvent,
+		buildv1alpha1.GitHubPullRequestEvent,
+	} {
+		if strings.EqualFold(string(e), s) {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("unknown GitHub event %q, expected either %s or %s",
+		s, buildv1alpha1.GitHubPushEvent, buildv1alpha1.GitHubPullRequestEvent)
+}
+
+// findTrigger returns the index of the named trigger condition, or -1 when not found.
+func findTrigger(trigger *buildv1alpha1.Trigger, name string) int {
+	if trigger == nil {
+		return -1
+	}
+	for i, when := range trigger.When {
+		if when.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// describeTrigger renders the details of the trigger condition in a single line.
+func describeTrigger(when buildv1alpha1.TriggerWhen) string {
+	details := []string{}
+	if when.GitHub != nil {
+		events := make([]string, 0, len(when.GitHub.Events))
+		for _, e := range when.GitHub.Events {
+			events = append(events, string(e))
+		}
+		details = append(details, fmt.Sprintf("events=%s", strings.Join(events, ",")))
+		if len(when.GitHub.Branches) > 0 {
+			details = append(details, fmt.Sprintf("branches=%s", strings.Join(when.GitHub.Branches, ",")))
+		}
+	}
+	if when.Image != nil && len(when.Image.Names) > 0 {
+		details = append(details, fmt.Sprintf("images=%s", strings.Join(when.Image.Names, ",")))
+	}
+	if when.ObjectRef != nil {
+		if when.ObjectRef.Name != "" {
+			details = append(details, fmt.Sprintf("name=%s", when.ObjectRef.Name))
+		}
+		if len(when.ObjectRef.Selector) > 0 {
+			selector := make([]string, 0, len(when.ObjectRef.Selector))
+			for k, v := range when.ObjectRef.Selector {
+				selector = append(selector, fmt.Sprintf("%s=%s", k, v))
+			}
+			sort.Strings(selector)
+			details = append(details, fmt.Sprintf("selector=%s", strings.Join(selector, ",")))
+		}
+		if len(when.ObjectRef.Status) > 0 {
+			details = append(details, fmt.Sprintf("status=%s", strings.Join(when.ObjectRef.Status, ",")))
+		}
+	}
+	if len(details) == 0 {
+		return "-"
+	}
+	return strings.Join(details, " ")
+}
+
+// updateBuildTrigger retrieves the named Build, applies the trigger modification and updates it.
+func updateBuildTrigger(
+	ctx context.Context,
+	p *params.Params,
+	name string,
+	modify func(b *buildv1alpha1.Build) error,
+) error {
+	clientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	b, err := clientset.ShipwrightV1alpha1().Builds(p.Namespace()).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err = modify(b); err != nil {
+		return err
+	}
+	// a trigger stanza without conditions nor secret is meaningless, removing it altogether
+	if b.Spec.Trigger != nil && len(b.Spec.Trigger.When) == 0 && b.Spec.Trigger.SecretRef == nil {
+		b.Spec.Trigger = nil
+	}
+	_, err = clientset.ShipwrightV1alpha1().Builds(p.Namespace()).Update(ctx, b, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,172 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// TriggerAddCommand contains data provided by user to the trigger add subcommand
+type TriggerAddCommand struct {
+	cmd *cobra.Command
+
+	buildName   string
+	triggerName string
+	triggerType string
+	events      []string
+	branches    []string
+	images      []string
+	objectName  string
+	objectState []string
+	selector    map[string]string
+	secretName  string
+	overwrite   bool
+
+	when buildv1alpha1.TriggerWhen
+}
+
+const triggerAddLongDesc = `
+Adds a trigger condition to the Build, describing when a new BuildRun should take place. The
+condition type is either GitHub, Image or Pipeline. For example:
+
+	$ shp build trigger add my-app --name push --type GitHub --github-event Push --branch main --secret webhook
+	$ shp build trigger add my-app --name base --type Image --image ghcr.io/org/base:latest
+	$ shp build trigger add my-app --name pipeline --type Pipeline --object-name my-pipeline --object-status Succeeded
+`
+
+func triggerAddCmd() runner.SubCommand {
+	c := &TriggerAddCommand{
+		cmd: &cobra.Command{
+			Use:   "add <build> [flags]",
+			Short: "Add a trigger condition to the Build",
+			Long:  triggerAddLongDesc,
+			Args:  cobra.ExactArgs(1),
+		},
+		selector: map[string]string{},
+	}
+
+	f := c.cmd.Flags()
+	f.StringVar(&c.triggerName, "name", "", "name of the trigger condition")
+	f.StringVar(&c.triggerType, "type", "", fmt.Sprintf("type of the trigger condition, one of %v", triggerTypes))
+	f.StringSliceVar(&c.events, "github-event", []string{}, "GitHub events triggering the Build, either Push or PullRequest")
+	f.StringSliceVar(&c.branches, "branch", []string{}, "branches the GitHub events apply to")
+	f.StringSliceVar(&c.images, "image", []string{}, "fully qualified image names triggering the Build")
+	f.StringVar(&c.objectName, "object-name", "", "name of the Pipeline object triggering the Build")
+	f.StringSliceVar(&c.objectState, "object-status", []string{}, "Pipeline object status triggering the Build")
+	f.Var(flags.NewMapValue(c.selector), "object-selector", "label selector of the Pipeline objects triggering the Build")
+	f.StringVar(&c.secretName, "secret", "", "name of the secret carrying the token to validate webhook requests")
+	f.BoolVar(&c.overwrite, "overwrite", false, "replace the trigger condition when it already exists")
+
+	return c
+}
+
+// Cmd returns cobra command object of the trigger add subcommand
+func (c *TriggerAddCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the trigger condition with the data informed on the command-line
+func (c *TriggerAddCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.buildName = args[0]
+	c.when = buildv1alpha1.TriggerWhen{Name: c.triggerName}
+
+	if c.triggerType == "" {
+		return nil
+	}
+	triggerType, err := parseTriggerType(c.triggerType)
+	if err != nil {
+		return err
+	}
+	c.when.Type = triggerType
+
+	switch triggerType {
+	case buildv1alpha1.GitHubWebHookTrigger:
+		c.when.GitHub = &buildv1alpha1.WhenGitHub{Branches: c.branches}
+		for _, e := range c.events {
+			event, err := parseGitHubEvent(e)
+			if err != nil {
+				return err
+			}
+			c.when.GitHub.Events = append(c.when.GitHub.Events, event)
+		}
+	case buildv1alpha1.ImageTrigger:
+		c.when.Image = &buildv1alpha1.WhenImage{Names: c.images}
+	case buildv1alpha1.PipelineTrigger:
+		c.when.ObjectRef = &buildv1alpha1.WhenObjectRef{Name: c.objectName, Status: c.objectState}
+		if len(c.selector) > 0 {
+			c.when.ObjectRef.Selector = c.selector
+		}
+	}
+	return nil
+}
+
+// Validate checks the trigger condition is complete and the flags match its type
+func (c *TriggerAddCommand) Validate() error {
+	if c.when.Name == "" {
+		return errors.New("flag --name is required")
+	}
+	switch c.when.Type {
+	case "":
+		return errors.New("flag --type is required")
+	case buildv1alpha1.GitHubWebHookTrigger:
+		if len(c.when.GitHub.Events) == 0 {
+			return errors.New("flag --github-event is required for GitHub triggers")
+		}
+	case buildv1alpha1.PipelineTrigger:
+		if c.objectName == "" && len(c.selector) == 0 {
+			return errors.New("either --object-name or --object-selector is required for Pipeline triggers")
+		}
+		if len(c.objectState) == 0 {
+			return errors.New("flag --object-status is required for Pipeline triggers")
+		}
+	}
+
+	if c.when.Type != buildv1alpha1.GitHubWebHookTrigger && (len(c.events) > 0 || len(c.branches) > 0) {
+		return errors.New("flags --github-event and --branch only apply to GitHub triggers")
+	}
+	if c.when.Type != buildv1alpha1.ImageTrigger && len(c.images) > 0 {
+		return errors.New("flag --image only applies to Image triggers")
+	}
+	if c.when.Type != buildv1alpha1.PipelineTrigger && (c.objectName != "" || len(c.objectState) > 0 || len(c.selector) > 0) {
+		return errors.New("flags --object-name, --object-status and --object-selector only apply to Pipeline triggers")
+	}
+	return nil
+}
+
+// Run adds the trigger condition to the Build
+func (c *TriggerAddCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	err := updateBuildTrigger(c.cmd.Context(), p, c.buildName, func(b *buildv1alpha1.Build) error {
+		if b.Spec.Trigger == nil {
+			b.Spec.Trigger = &buildv1alpha1.Trigger{}
+		}
+		if c.secretName != "" {
+			b.Spec.Trigger.SecretRef = &corev1.LocalObjectReference{Name: c.secretName}
+		}
+
+		i := findTrigger(b.Spec.Trigger, c.when.Name)
+		switch {
+		case i < 0:
+			b.Spec.Trigger.When = append(b.Spec.Trigger.When, c.when)
+		case c.overwrite:
+			b.Spec.Trigger.When[i] = c.when
+		default:
+			return fmt.Errorf("trigger %q already exists on Build %q, use --overwrite to replace it", c.when.Name, c.buildName)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ioStreams.Out, "Trigger %q added to Build %q\n", c.when.Name, c.buildName)
+	return nil
+}
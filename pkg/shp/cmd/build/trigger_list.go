@@ -0,0 +1,86 @@
+package build
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+)
+
+// TriggerListCommand contains data provided by user to the trigger list subcommand
+type TriggerListCommand struct {
+	cmd *cobra.Command
+
+	buildName string
+	noHeader  bool
+}
+
+func triggerListCmd() runner.SubCommand {
+	c := &TriggerListCommand{
+		cmd: &cobra.Command{
+			Use:   "list <build> [flags]",
+			Short: "List the trigger conditions of the Build",
+			Args:  cobra.ExactArgs(1),
+		},
+	}
+
+	c.cmd.Flags().BoolVar(&c.noHeader, "no-header", false, "Do not show columns header in list output")
+
+	return c
+}
+
+// Cmd returns cobra command object of the trigger list subcommand
+func (c *TriggerListCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the Build name
+func (c *TriggerListCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.buildName = args[0]
+	return nil
+}
+
+// Validate checks user input data
+func (c *TriggerListCommand) Validate() error {
+	return nil
+}
+
+// Run prints the trigger conditions of the Build
+func (c *TriggerListCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	b, err := clientset.ShipwrightV1alpha1().Builds(p.Namespace()).Get(c.cmd.Context(), c.buildName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if b.Spec.Trigger == nil || len(b.Spec.Trigger.When) == 0 {
+		fmt.Fprintf(ioStreams.Out, "No triggers found on Build %q\n", c.buildName)
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(ioStreams.Out, 0, 8, 2, '\t', 0)
+	if !c.noHeader {
+		fmt.Fprintln(writer, "NAME\tTYPE\tDETAILS")
+	}
+	for _, when := range b.Spec.Trigger.When {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", styles.Bold(when.Name), when.Type, describeTrigger(when))
+	}
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+
+	if b.Spec.Trigger.SecretRef != nil {
+		fmt.Fprintf(ioStreams.Out, "\nWebhook secret: %s\n", b.Spec.Trigger.SecretRef.Name)
+	}
+	return nil
+}
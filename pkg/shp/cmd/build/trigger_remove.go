@@ -0,0 +1,90 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// TriggerRemoveCommand contains data provided by user to the trigger remove subcommand
+type TriggerRemoveCommand struct {
+	cmd *cobra.Command
+
+	buildName    string
+	triggerNames []string
+	all          bool
+}
+
+func triggerRemoveCmd() runner.SubCommand {
+	c := &TriggerRemoveCommand{
+		cmd: &cobra.Command{
+			Use:   "remove <build> [trigger...] [flags]",
+			Short: "Remove trigger conditions from the Build",
+			Args:  cobra.MinimumNArgs(1),
+		},
+	}
+
+	c.cmd.Flags().BoolVar(&c.all, "all", false, "remove the whole trigger configuration, including the secret")
+
+	return c
+}
+
+// Cmd returns cobra command object of the trigger remove subcommand
+func (c *TriggerRemoveCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the Build and trigger names
+func (c *TriggerRemoveCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.buildName = args[0]
+	c.triggerNames = args[1:]
+	return nil
+}
+
+// Validate checks either trigger names or --all are informed
+func (c *TriggerRemoveCommand) Validate() error {
+	if c.all && len(c.triggerNames) > 0 {
+		return errors.New("trigger names can't be informed together with --all")
+	}
+	if !c.all && len(c.triggerNames) == 0 {
+		return errors.New("either trigger names or --all must be informed")
+	}
+	return nil
+}
+
+// Run removes the trigger conditions from the Build
+func (c *TriggerRemoveCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	err := updateBuildTrigger(c.cmd.Context(), p, c.buildName, func(b *buildv1alpha1.Build) error {
+		if c.all {
+			b.Spec.Trigger = nil
+			return nil
+		}
+		for _, name := range c.triggerNames {
+			i := findTrigger(b.Spec.Trigger, name)
+			if i < 0 {
+				return fmt.Errorf("trigger %q not found on Build %q", name, c.buildName)
+			}
+			b.Spec.Trigger.When = append(b.Spec.Trigger.When[:i], b.Spec.Trigger.When[i+1:]...)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.all {
+		fmt.Fprintf(ioStreams.Out, "Triggers removed from Build %q\n", c.buildName)
+		return nil
+	}
+	for _, name := range c.triggerNames {
+		fmt.Fprintf(ioStreams.Out, "Trigger %q removed from Build %q\n", name, c.buildName)
+	}
+	return nil
+}
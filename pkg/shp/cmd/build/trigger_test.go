@@ -0,0 +1,108 @@
+package build
+
+import (
+	"context"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestBuildTrigger(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+
+	shpclientset := shpfake.NewSimpleClientset(
+		&buildv1alpha1.Build{ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: ns}},
+	)
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, ns, nil, nil)
+
+	run := func(sub runner.SubCommand, args ...string) (string, error) {
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		cmd := sub.Cmd()
+		cmd.SetContext(context.TODO())
+		if err := cmd.ParseFlags(args); err != nil {
+			return "", err
+		}
+		if err := sub.Complete(p, &ioStreams, cmd.Flags().Args()); err != nil {
+			return "", err
+		}
+		if err := sub.Validate(); err != nil {
+			return "", err
+		}
+		err := sub.Run(p, &ioStreams)
+		return out.String(), err
+	}
+	getTrigger := func() *buildv1alpha1.Trigger {
+		b, err := shpclientset.ShipwrightV1alpha1().Builds(ns).Get(context.TODO(), "my-app", metav1.GetOptions{})
+		g.Expect(err).To(o.BeNil())
+		return b.Spec.Trigger
+	}
+
+	_, err := run(triggerAddCmd(), "my-app", "--name", "push", "--type", "github",
+		"--github-event", "push,pullrequest", "--branch", "main", "--secret", "webhook")
+	g.Expect(err).To(o.BeNil())
+	_, err = run(triggerAddCmd(), "my-app", "--name", "base", "--type", "Image", "--image", "ghcr.io/org/base:latest")
+	g.Expect(err).To(o.BeNil())
+
+	trigger := getTrigger()
+	g.Expect(trigger).NotTo(o.BeNil())
+	g.Expect(trigger.SecretRef.Name).To(o.Equal("webhook"))
+	g.Expect(trigger.When).To(o.HaveLen(2))
+	g.Expect(trigger.When[0].Type).To(o.Equal(buildv1alpha1.GitHubWebHookTrigger))
+	g.Expect(trigger.When[0].GitHub.Events).To(o.Equal([]buildv1alpha1.GitHubEventName{
+		buildv1alpha1.GitHubPushEvent, buildv1alpha1.GitHubPullRequestEvent,
+	}))
+	g.Expect(trigger.When[1].Image.Names).To(o.Equal([]string{"ghcr.io/org/base:latest"}))
+
+	t.Run("duplicated trigger requires overwrite", func(_ *testing.T) {
+		_, err := run(triggerAddCmd(), "my-app", "--name", "base", "--type", "Image", "--image", "other")
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("already exists")))
+
+		_, err = run(triggerAddCmd(), "my-app", "--name", "base", "--type", "Image", "--image", "other", "--overwrite")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(getTrigger().When[1].Image.Names).To(o.Equal([]string{"other"}))
+	})
+
+	t.Run("flags must match the trigger type", func(_ *testing.T) {
+		_, err := run(triggerAddCmd(), "my-app", "--name", "x", "--type", "Image", "--branch", "main")
+		g.Expect(err).NotTo(o.BeNil())
+
+		_, err = run(triggerAddCmd(), "my-app", "--name", "x", "--type", "Pipeline", "--object-name", "p")
+		g.Expect(err).NotTo(o.BeNil())
+
+		_, err = run(triggerAddCmd(), "my-app", "--name", "x", "--type", "Unknown")
+		g.Expect(err).NotTo(o.BeNil())
+	})
+
+	t.Run("list", func(_ *testing.T) {
+		out, err := run(triggerListCmd(), "my-app")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).To(o.ContainSubstring("events=Push,PullRequest branches=main"))
+		g.Expect(out).To(o.ContainSubstring("images=other"))
+		g.Expect(out).To(o.ContainSubstring("Webhook secret: webhook"))
+	})
+
+	t.Run("remove", func(_ *testing.T) {
+		_, err := run(triggerRemoveCmd(), "my-app", "missing")
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("not found")))
+
+		_, err = run(triggerRemoveCmd(), "my-app", "push", "base")
+		g.Expect(err).To(o.BeNil())
+		trigger := getTrigger()
+		g.Expect(trigger.When).To(o.BeEmpty())
+		g.Expect(trigger.SecretRef).NotTo(o.BeNil())
+
+		_, err = run(triggerRemoveCmd(), "my-app", "--all")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(getTrigger()).To(o.BeNil())
+	})
+}
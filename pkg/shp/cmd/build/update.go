@@ -0,0 +1,280 @@
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// Types of the patch informed on --patch.
+const (
+	patchTypeMerge = "merge" // JSON merge patch, RFC 7386
+	patchTypeJSON  = "json"  // JSON patch, RFC 6902
+)
+
+// UpdateCommand contains data input from user for the update sub-command
+type UpdateCommand struct {
+	cmd *cobra.Command
+
+	name      string                   // build name
+	buildSpec *buildv1alpha1.BuildSpec // stores command-line flags
+	patch     string                   // patch applied after the flags, or @file with it
+	patchType string                   // either merge or json
+}
+
+const buildUpdateLongDesc = `
+Updates an existing Build using the same flags as "shp build create", only the fields of the flags
+informed are modified, the rest of the Build is kept as is. List elements carrying a name, like
+environment variables and parameter values, are merged by name. For example:
+
+	$ shp build update my-app --source-revision=v2 --timeout=15m
+	$ shp build update my-app --env LOG_LEVEL=debug
+
+For changes the flags don't cover, like removing a field, the Build is patched with --patch, either
+a JSON merge patch (RFC 7386), the default, or a JSON patch (RFC 6902) with --patch-type=json. Use
+"@" followed by a path to read the patch from a file. The patch is applied after the flags:
+
+	$ shp build update my-app --patch='{"spec":{"timeout":null}}'
+	$ shp build update my-app --patch-type=json --patch='[{"op":"remove","path":"/spec/env/0"}]'
+
+The updated Build is validated like "shp build validate" does, and it's not updated when invalid.
+`
+
+func updateCmd() runner.SubCommand {
+	cmd := &cobra.Command{
+		Use:   "update <name> [flags]",
+		Short: "Update the informed fields of a Build",
+		Long:  buildUpdateLongDesc,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	c := &UpdateCommand{
+		cmd:       cmd,
+		buildSpec: flags.BuildSpecFromFlags(cmd.Flags()),
+	}
+	skipBuildSpecDefaults(cmd.Flags())
+	cmd.Flags().StringVar(&c.patch, "patch", "", "patch applied to the Build after the flags, or @file with it")
+	cmd.Flags().StringVar(&c.patchType, "patch-type", patchTypeMerge,
+		fmt.Sprintf("type of the --patch, either %q or %q", patchTypeMerge, patchTypeJSON))
+	return c
+}
+
+// Cmd returns cobra command object of the update subcommand
+func (c *UpdateCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the Build name
+func (c *UpdateCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+	return nil
+}
+
+// Validate checks user input data
+func (c *UpdateCommand) Validate() error {
+	if c.patchType != patchTypeMerge && c.patchType != patchTypeJSON {
+		return fmt.Errorf("unsupported --patch-type %q, expected either %q or %q", c.patchType, patchTypeMerge, patchTypeJSON)
+	}
+	if c.cmd.Flags().Changed("patch-type") && c.patch == "" {
+		return fmt.Errorf("--patch-type requires --patch")
+	}
+	if c.patch == "" && !c.buildFlagsChanged() {
+		return fmt.Errorf("nothing to update, inform the Build flags or --patch")
+	}
+	return nil
+}
+
+// buildFlagsChanged returns whether any Build flag has been informed.
+func (c *UpdateCommand) buildFlagsChanged() bool {
+	changed := false
+	c.cmd.Flags().Visit(func(f *pflag.Flag) {
+		switch f.Name {
+		case "patch", "patch-type":
+		default:
+			changed = true
+		}
+	})
+	return changed
+}
+
+// Run merges the flags and the patch onto the live Build, updating it when changed
+func (c *UpdateCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	ctx := c.cmd.Context()
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	client := clientset.ShipwrightV1alpha1().Builds(params.Namespace())
+	b, err := client.Get(ctx, c.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	updated, err := c.merge(b)
+	if err != nil {
+		return err
+	}
+	if updated.GetName() != b.GetName() || updated.GetNamespace() != b.GetNamespace() {
+		return fmt.Errorf("the Build name and namespace can't be changed")
+	}
+	if equality.Semantic.DeepEqual(b, updated) {
+		fmt.Fprintf(ioStreams.Out, "Build %q unchanged\n", c.name)
+		return nil
+	}
+
+	var warnings, failures []string
+	for _, f := range lintBuild(updated) {
+		message := f.Message
+		if f.Field != "" {
+			message = fmt.Sprintf("%s: %s", f.Field, f.Message)
+		}
+		if f.Severity == FindingError {
+			failures = append(failures, message)
+		} else {
+			warnings = append(warnings, message)
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("the updated Build %q is invalid:\n  %s", c.name, strings.Join(failures, "\n  "))
+	}
+
+	if _, err = client.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(ioStreams.ErrOut, "Warning: %s\n", warning)
+	}
+	fmt.Fprintf(ioStreams.Out, "Build %q updated\n", c.name)
+	return nil
+}
+
+// merge returns the Build with the fields of the informed flags merged onto it, with strategic merge
+// semantics, followed by the patch.
+func (c *UpdateCommand) merge(b *buildv1alpha1.Build) (*buildv1alpha1.Build, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.buildFlagsChanged() {
+		spec, err := buildSpecPatch(c.cmd.Flags(), c.buildSpec)
+		if err != nil {
+			return nil, err
+		}
+		if err = mergeNamedLists(b, spec); err != nil {
+			return nil, err
+		}
+		patchData, err := json.Marshal(map[string]interface{}{"spec": spec})
+		if err != nil {
+			return nil, err
+		}
+		if data, err = strategicpatch.StrategicMergePatch(data, patchData, &buildv1alpha1.Build{}); err != nil {
+			return nil, fmt.Errorf("unable to merge the flags onto Build %q: %w", c.name, err)
+		}
+	}
+
+	if c.patch != "" {
+		if data, err = c.applyPatch(data); err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	updated := &buildv1alpha1.Build{}
+	if err = decoder.Decode(updated); err != nil {
+		return nil, fmt.Errorf("invalid Build after the patch: %w", err)
+	}
+	return updated, nil
+}
+
+// namedLists fields of the Build spec holding elements identified by name, the Build API doesn't
+// declare them as merged by name.
+var namedLists = []string{"env", "paramValues"}
+
+// mergeNamedLists merges the named lists on the spec patch with the ones of the live Build, thus the
+// elements informed replace the ones with the same name, in place, and the others are kept.
+func mergeNamedLists(b *buildv1alpha1.Build, spec map[string]interface{}) error {
+	live, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&b.Spec)
+	if err != nil {
+		return err
+	}
+	for _, field := range namedLists {
+		informed, ok := spec[field].([]interface{})
+		if !ok {
+			continue
+		}
+		existing, _ := live[field].([]interface{})
+		merged := make([]interface{}, 0, len(existing)+len(informed))
+		for _, e := range existing {
+			if i := indexOfName(informed, e); i >= 0 {
+				e = informed[i]
+			}
+			merged = append(merged, e)
+		}
+		for _, e := range informed {
+			if indexOfName(existing, e) < 0 {
+				merged = append(merged, e)
+			}
+		}
+		spec[field] = merged
+	}
+	return nil
+}
+
+// indexOfName returns the index of the list element with the same name as the element, -1 when
+// there is none.
+func indexOfName(list []interface{}, element interface{}) int {
+	name, _, _ := unstructured.NestedString(element.(map[string]interface{}), "name")
+	for i, e := range list {
+		if n, _, _ := unstructured.NestedString(e.(map[string]interface{}), "name"); n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyPatch applies --patch on the Build data, according to --patch-type.
+func (c *UpdateCommand) applyPatch(data []byte) ([]byte, error) {
+	patch := []byte(c.patch)
+	if path, found := strings.CutPrefix(c.patch, "@"); found {
+		var err error
+		if patch, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.patchType == patchTypeJSON {
+		p, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON patch: %w", err)
+		}
+		if data, err = p.Apply(data); err != nil {
+			return nil, fmt.Errorf("unable to apply the JSON patch: %w", err)
+		}
+		return data, nil
+	}
+	data, err := jsonpatch.MergePatch(data, patch)
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply the merge patch: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,228 @@
+package build
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	"github.com/shipwright-io/cli/pkg/shp/config"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestUpdateBuild(t *testing.T) {
+	newBuild := func() *buildv1alpha1.Build {
+		kind := buildv1alpha1.ClusterBuildStrategyKind
+		return &buildv1alpha1.Build{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "my-app", Labels: map[string]string{"team": "a"}},
+			Spec: buildv1alpha1.BuildSpec{
+				Source: buildv1alpha1.Source{
+					URL:      pointer.String("https://github.com/org/my-app"),
+					Revision: pointer.String("v1"),
+				},
+				Strategy: buildv1alpha1.Strategy{Name: "buildah", Kind: &kind},
+				Output:   buildv1alpha1.Image{Image: "registry.local/org/my-app:v1"},
+				Env:      []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+				Timeout:  &metav1.Duration{Duration: 600_000_000_000},
+			},
+		}
+	}
+
+	patchFile := filepath.Join(t.TempDir(), "patch.json")
+	if err := os.WriteFile(patchFile, []byte(`{"metadata":{"labels":{"team":"b"}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(b *buildv1alpha1.Build)
+		args    []string
+		out     string
+		err     string
+		verify  func(g *o.WithT, b *buildv1alpha1.Build)
+	}{{
+		name: "only the informed flags",
+		args: []string{"--source-revision=v2", "--env", "B=3", "--env", "C=4"},
+		out:  "Build \"my-app\" updated\n",
+		verify: func(g *o.WithT, b *buildv1alpha1.Build) {
+			g.Expect(*b.Spec.Source.Revision).To(o.Equal("v2"))
+			g.Expect(*b.Spec.Source.URL).To(o.Equal("https://github.com/org/my-app"))
+			g.Expect(b.Spec.Output.Image).To(o.Equal("registry.local/org/my-app:v1"))
+			g.Expect(b.Spec.Timeout.Duration.String()).To(o.Equal("10m0s"))
+			g.Expect(b.Spec.Env).To(o.Equal([]corev1.EnvVar{
+				{Name: "A", Value: "1"},
+				{Name: "B", Value: "3"},
+				{Name: "C", Value: "4"},
+			}))
+			g.Expect(b.GetLabels()).To(o.Equal(map[string]string{"team": "a"}))
+		},
+	}, {
+		name: "merge patch",
+		args: []string{"--output-image=registry.local/org/my-app:v2", "--patch", `{"spec":{"timeout":null}}`},
+		out:  "Build \"my-app\" updated\n",
+		verify: func(g *o.WithT, b *buildv1alpha1.Build) {
+			g.Expect(b.Spec.Output.Image).To(o.Equal("registry.local/org/my-app:v2"))
+			g.Expect(b.Spec.Timeout).To(o.BeNil())
+		},
+	}, {
+		name: "merge patch from file",
+		args: []string{"--patch", "@" + patchFile},
+		out:  "Build \"my-app\" updated\n",
+		verify: func(g *o.WithT, b *buildv1alpha1.Build) {
+			g.Expect(b.GetLabels()).To(o.Equal(map[string]string{"team": "b"}))
+		},
+	}, {
+		name: "JSON patch",
+		args: []string{"--patch-type=json", "--patch", `[{"op":"remove","path":"/spec/env/0"}]`},
+		out:  "Build \"my-app\" updated\n",
+		verify: func(g *o.WithT, b *buildv1alpha1.Build) {
+			g.Expect(b.Spec.Env).To(o.Equal([]corev1.EnvVar{{Name: "B", Value: "2"}}))
+		},
+	}, {
+		name: "informed with the flag defaults",
+		args: []string{"--strategy-name=buildpacks-v3", "--timeout=0s", "--source-revision="},
+		out:  "Build \"my-app\" updated\n",
+		verify: func(g *o.WithT, b *buildv1alpha1.Build) {
+			g.Expect(b.Spec.Strategy.Name).To(o.Equal("buildpacks-v3"))
+			g.Expect(*b.Spec.Strategy.Kind).To(o.Equal(buildv1alpha1.ClusterBuildStrategyKind))
+			g.Expect(b.Spec.Timeout).To(o.BeNil())
+			g.Expect(b.Spec.Source.Revision).To(o.BeNil())
+			g.Expect(*b.Spec.Source.URL).To(o.Equal("https://github.com/org/my-app"))
+		},
+	}, {
+		name: "strategy kind informed with the flag default",
+		prepare: func(b *buildv1alpha1.Build) {
+			kind := buildv1alpha1.NamespacedBuildStrategyKind
+			b.Spec.Strategy.Kind = &kind
+		},
+		args: []string{"--strategy-kind=ClusterBuildStrategy"},
+		out:  "Build \"my-app\" updated\n",
+		verify: func(g *o.WithT, b *buildv1alpha1.Build) {
+			g.Expect(*b.Spec.Strategy.Kind).To(o.Equal(buildv1alpha1.ClusterBuildStrategyKind))
+			g.Expect(b.Spec.Strategy.Name).To(o.Equal("buildah"))
+		},
+	}, {
+		name: "unchanged",
+		args: []string{"--source-revision=v1"},
+		out:  "Build \"my-app\" unchanged\n",
+	}, {
+		name: "renamed by the patch",
+		args: []string{"--patch", `{"metadata":{"name":"other"}}`},
+		err:  "the Build name and namespace can't be changed",
+	}, {
+		name: "unknown field on the patch",
+		args: []string{"--patch", `{"spec":{"sourceURL":"https://github.com/org/other"}}`},
+		err:  `invalid Build after the patch: json: unknown field "sourceURL"`,
+	}, {
+		name: "invalid JSON patch",
+		args: []string{"--patch-type=json", "--patch", `[{"op":"remove","path":"/spec/env/5"}]`},
+		err:  "unable to apply the JSON patch",
+	}, {
+		name: "invalid updated Build",
+		args: []string{"--patch", `{"spec":{"strategy":{"name":""}}}`},
+		err:  `the updated Build "my-app" is invalid`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			b := newBuild()
+			if tt.prepare != nil {
+				tt.prepare(b)
+			}
+			clientset := shpfake.NewSimpleClientset(b)
+
+			cmd := updateCmd().(*UpdateCommand)
+			cmd.cmd.SetContext(context.TODO())
+			g.Expect(cmd.cmd.ParseFlags(tt.args)).To(o.Succeed())
+			g.Expect(cmd.Complete(nil, nil, []string{"my-app"})).To(o.Succeed())
+			g.Expect(cmd.Validate()).To(o.Succeed())
+
+			ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+			p := params.NewParamsForTest(fake.NewSimpleClientset(), clientset, nil, "dev", nil, nil)
+			err := cmd.Run(p, &ioStreams)
+
+			b, getErr := clientset.ShipwrightV1alpha1().Builds("dev").Get(context.TODO(), "my-app", metav1.GetOptions{})
+			g.Expect(getErr).To(o.BeNil())
+			if tt.err != "" {
+				g.Expect(err).To(o.HaveOccurred())
+				g.Expect(err.Error()).To(o.ContainSubstring(tt.err))
+				g.Expect(b).To(o.Equal(newBuild()))
+				return
+			}
+			g.Expect(err).To(o.BeNil())
+			g.Expect(out.String()).To(o.Equal(tt.out))
+			if tt.verify != nil {
+				tt.verify(g, b)
+			}
+		})
+	}
+}
+
+func TestUpdateValidate(t *testing.T) {
+	g := o.NewWithT(t)
+
+	for args, err := range map[string]string{
+		"":                             "nothing to update, inform the Build flags or --patch",
+		"--patch-type=json":            "--patch-type requires --patch",
+		"--patch-type=strategic":       `unsupported --patch-type "strategic", expected either "merge" or "json"`,
+		"--patch={}":                   "",
+		"--source-url=https://git.dev": "",
+	} {
+		cmd := updateCmd().(*UpdateCommand)
+		if args != "" {
+			g.Expect(cmd.cmd.ParseFlags([]string{args})).To(o.Succeed())
+		}
+		if err == "" {
+			g.Expect(cmd.Validate()).To(o.Succeed(), args)
+		} else {
+			g.Expect(cmd.Validate()).To(o.MatchError(err), args)
+		}
+	}
+}
+
+func TestUpdateConfigDefaults(t *testing.T) {
+	g := o.NewWithT(t)
+	cfg := &config.Config{Strategy: "kaniko", StrategyKind: "BuildStrategy", RegistryPrefix: "quay.io/org"}
+
+	cmd := updateCmd().(*UpdateCommand)
+	g.Expect(cfg.ApplyDefaults(cmd.cmd, []string{"my-app"})).To(o.Succeed())
+	g.Expect(cmd.Validate()).To(o.MatchError("nothing to update, inform the Build flags or --patch"))
+
+	kind := buildv1alpha1.ClusterBuildStrategyKind
+	clientset := shpfake.NewSimpleClientset(&buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "my-app"},
+		Spec: buildv1alpha1.BuildSpec{
+			Source:   buildv1alpha1.Source{URL: pointer.String("https://github.com/org/my-app")},
+			Strategy: buildv1alpha1.Strategy{Name: "buildah", Kind: &kind},
+			Output:   buildv1alpha1.Image{Image: "registry.local/org/my-app"},
+		},
+	})
+	cmd = updateCmd().(*UpdateCommand)
+	cmd.cmd.SetContext(context.TODO())
+	g.Expect(cmd.cmd.ParseFlags([]string{"--timeout=15m"})).To(o.Succeed())
+	g.Expect(cfg.ApplyDefaults(cmd.cmd, []string{"my-app"})).To(o.Succeed())
+	g.Expect(cmd.Complete(nil, nil, []string{"my-app"})).To(o.Succeed())
+	g.Expect(cmd.Validate()).To(o.Succeed())
+
+	ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), clientset, nil, "dev", nil, nil)
+	g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+
+	b, err := clientset.ShipwrightV1alpha1().Builds("dev").Get(context.TODO(), "my-app", metav1.GetOptions{})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(b.Spec.Strategy.Name).To(o.Equal("buildah"))
+	g.Expect(*b.Spec.Strategy.Kind).To(o.Equal(buildv1alpha1.ClusterBuildStrategyKind))
+	g.Expect(b.Spec.Output.Image).To(o.Equal("registry.local/org/my-app"))
+	g.Expect(b.Spec.Timeout.Duration.String()).To(o.Equal("15m0s"))
+}
@@ -1,21 +1,25 @@
 package build
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"path"
 	"time"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources/sources"
 
+	"github.com/shipwright-io/cli/pkg/shp/audit"
 	"github.com/shipwright-io/cli/pkg/shp/bundle"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/follower"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
 	"github.com/shipwright-io/cli/pkg/shp/flags"
 	"github.com/shipwright-io/cli/pkg/shp/params"
 	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
 	"github.com/shipwright-io/cli/pkg/shp/streamer"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
@@ -28,7 +32,12 @@ import (
 type UploadCommand struct {
 	cmd          *cobra.Command              // cobra command instance
 	buildRunSpec *buildv1alpha1.BuildRunSpec // command-line flags stored directly on the BuildRun
+	naming       *flags.BuildRunNaming       // controls the BuildRun name
+	metadata     *flags.ObjectMetadata       // BuildRun labels, annotations and ownership
 	follow       bool                        // flag to tail pod logs
+	maxLogRate   int64                       // log bytes per second printed while following
+	heartbeat    time.Duration               // quiet period before the running step is reported
+	logFilter    flags.LogFilter             // selects the log lines printed while following
 
 	buildRefName string // build name
 	sourceDir    string // local directory to be streamed
@@ -37,9 +46,17 @@ type UploadCommand struct {
 	streamingIsDone bool               // marks the streaming is completed
 
 	sourceBundleImage string // image to be used as the source bundle
+	sourceCredentials string // secret name with the source bundle registry credentials
+	registryAuth      string // source of the registry credentials to push the bundle
+	registrySecret    string // docker-registry secret name to push the bundle
+	compression       string // compression of the uploaded data
+	compressionLevel  int    // compression level, zero means the algorithm default
 
 	pw       *reactor.PodWatcher // pod-watcher instance
 	follower *follower.Follower  // follower instance
+
+	audit    bool            // record who triggered the BuildRun
+	recorder *audit.Recorder // annotates the BuildRun and records the Event, when auditing
 }
 
 const (
@@ -56,16 +73,23 @@ In case a source bundle image is defined, the bundling feature is used, which wi
 source code into a bundle container and upload it to the specified container registry. Instead of
 executing using Git in the source step, it will use the container registry to obtain the source code.
 
+The registry credentials to push the source bundle are taken from the local Docker configuration
+by default, the --registry-auth flag allows exchanging cloud provider credentials for a registry
+token instead ("ecr", "gcr" or "acr"), or using the Build's source credentials secret ("secret").
+
+Large directories may be compressed with --compress, either "gzip" or "zstd", and --compress-level.
+When streaming, the data is extracted by "tar" on the build pod, which must support the algorithm.
+The upload progress, transfer rate and estimated time left are shown as a bar on a terminal, and
+as periodic lines otherwise.
+
 	$ shp buildrun upload <build-name>
 	$ shp buildrun upload <build-name> /path/to/repository
+	$ shp buildrun upload <build-name> --registry-auth=secret
+	$ shp buildrun upload <build-name> --compress=zstd --compress-level=9
 `
 
 	// targetBaseDir directory where data will be uploaded.
 	targetBaseDir = "/workspace/source"
-	// buildNameAnnotation label to identify the Build name.
-	buildNameAnnotation = "build.shipwright.io/name"
-	// buildRunNameAnnotation label to identify the BuildRun name.
-	buildRunNameAnnotation = "buildrun.shipwright.io/name"
 )
 
 // Cmd exposes the Cobra command instance.
@@ -130,9 +154,13 @@ func (u *UploadCommand) Complete(p *params.Params, _ *genericclioptions.IOStream
 	// is assumed that the source bundle upload via registry is used
 	if build.Spec.Source.BundleContainer != nil && build.Spec.Source.BundleContainer.Image != "" {
 		u.sourceBundleImage = build.Spec.Source.BundleContainer.Image
+		if build.Spec.Source.Credentials != nil {
+			u.sourceCredentials = build.Spec.Source.Credentials.Name
+		}
 
 	} else {
 		u.dataStreamer = streamer.NewStreamer(restConfig, clientset)
+		u.dataStreamer.SetCompression(streamer.Compression(u.compression), u.compressionLevel)
 	}
 
 	u.pw, err = p.NewPodWatcher(u.Cmd().Context())
@@ -148,7 +176,53 @@ func (u *UploadCommand) Validate() error {
 	if !stat.IsDir() {
 		return fmt.Errorf("informed path is not a directory: '%s'", u.sourceDir)
 	}
-	return nil
+	if err = u.naming.Validate(); err != nil {
+		return err
+	}
+	if err = u.metadata.Validate(); err != nil {
+		return err
+	}
+	if u.maxLogRate > 0 && !u.follow {
+		return fmt.Errorf("--%s requires --follow", flags.MaxLogRateFlag)
+	}
+	if err = validateHeartbeat(u.cmd, u.heartbeat, u.follow); err != nil {
+		return err
+	}
+	if !u.logFilter.IsEmpty() && !u.follow {
+		return fmt.Errorf("--%s and --%s require --follow", flags.GrepFlag, flags.StepFlag)
+	}
+	if err = streamer.ValidateCompression(streamer.Compression(u.compression), u.compressionLevel); err != nil {
+		return err
+	}
+	_, err = registry.ParseAuthSource(u.registryAuth)
+	return err
+}
+
+// keychain returns the registry credentials keychain to push the source bundle, the secret source
+// defaults to the Build's source credentials.
+func (u *UploadCommand) keychain(p *params.Params) (authn.Keychain, error) {
+	return registryKeychain(u.cmd.Context(), p, u.registryAuth, u.registrySecret, u.sourceCredentials)
+}
+
+// registryKeychain returns the keychain for the informed registry credentials source, the secret
+// source uses the informed secret name, or the fallback secret when empty.
+func registryKeychain(ctx context.Context, p *params.Params, authSource, secretName, fallbackSecret string) (authn.Keychain, error) {
+	source, err := registry.ParseAuthSource(authSource)
+	if err != nil {
+		return nil, err
+	}
+	opts := registry.AuthOptions{Source: source}
+	if source == registry.AuthSecret {
+		if opts.Clientset, err = p.ClientSet(); err != nil {
+			return nil, err
+		}
+		opts.Namespace = p.Namespace()
+		opts.SecretName = secretName
+		if opts.SecretName == "" {
+			opts.SecretName = fallbackSecret
+		}
+	}
+	return registry.Keychain(ctx, opts)
 }
 
 // createBuildRun creates the BuildRun instance to receive the data upload afterwards, it returns the
@@ -159,10 +233,8 @@ func (u *UploadCommand) createBuildRun(p *params.Params) (*buildv1alpha1.BuildRu
 	// Use bundle feature for source upload and build
 	case u.sourceBundleImage != "":
 		br = &buildv1alpha1.BuildRun{
-			ObjectMeta: metav1.ObjectMeta{
-				GenerateName: fmt.Sprintf("%s-", u.buildRefName),
-			},
-			Spec: *u.buildRunSpec,
+			ObjectMeta: u.naming.ObjectMeta(u.buildRefName),
+			Spec:       *u.buildRunSpec,
 		}
 
 	// Use local copy streaming feature for source upload and build
@@ -172,10 +244,8 @@ func (u *UploadCommand) createBuildRun(p *params.Params) (*buildv1alpha1.BuildRu
 			Type: buildv1alpha1.LocalCopy,
 		}}
 		br = &buildv1alpha1.BuildRun{
-			ObjectMeta: metav1.ObjectMeta{
-				GenerateName: fmt.Sprintf("%s-", u.buildRefName),
-			},
-			Spec: *u.buildRunSpec,
+			ObjectMeta: u.naming.ObjectMeta(u.buildRefName),
+			Spec:       *u.buildRunSpec,
 		}
 	}
 
@@ -187,9 +257,19 @@ func (u *UploadCommand) createBuildRun(p *params.Params) (*buildv1alpha1.BuildRu
 	if err != nil {
 		return nil, err
 	}
-	br, err = clientset.ShipwrightV1alpha1().
-		BuildRuns(ns).
-		Create(u.cmd.Context(), br, metav1.CreateOptions{})
+	var owner *buildv1alpha1.Build
+	if u.metadata.RequiresOwner() {
+		if owner, err = clientset.ShipwrightV1alpha1().Builds(ns).Get(u.cmd.Context(), u.buildRefName, metav1.GetOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	u.metadata.Apply(&br.ObjectMeta, owner)
+	if u.audit {
+		if u.recorder, err = newRecorder(u.cmd.Context(), p); err != nil {
+			return nil, err
+		}
+	}
+	br, err = createNamedBuildRun(u.cmd.Context(), clientset, ns, br, u.naming, u.recorder, log.Writer())
 	if err != nil {
 		return nil, err
 	}
@@ -243,33 +323,26 @@ func (u *UploadCommand) stop() {
 // onPodModifiedEventStreaming is invoked everytime the pod running the actual build process changes, thus it
 // can react upon the state changes in order to orchestrate the data upload.
 func (u *UploadCommand) onPodModifiedEventStreaming(pod *corev1.Pod) error {
-	switch pod.Status.Phase {
-	case corev1.PodRunning:
-		return u.performDataStreaming(&streamer.Target{
-			Namespace: pod.GetNamespace(),
-			Pod:       pod.GetName(),
-			Container: fmt.Sprintf("step-%s", sources.WaiterContainerName),
-			BaseDir:   targetBaseDir,
-		})
-	case corev1.PodFailed:
-		u.stop()
-		return fmt.Errorf("build pod '%s' has failed", pod.GetName())
-	case corev1.PodSucceeded:
-		u.stop()
+	if pod.Status.Phase != corev1.PodRunning {
+		return nil
 	}
-	return nil
+	return u.performDataStreaming(&streamer.Target{
+		Namespace: pod.GetNamespace(),
+		Pod:       pod.GetName(),
+		Container: fmt.Sprintf("step-%s", sources.WaiterContainerName),
+		BaseDir:   targetBaseDir,
+	})
 }
 
-func (u *UploadCommand) onPodModifiedEventBundling(pod *corev1.Pod) error {
-	switch pod.Status.Phase {
-	case corev1.PodFailed:
-		u.stop()
-		return fmt.Errorf("build pod '%s' has failed", pod.GetName())
-
-	case corev1.PodSucceeded:
-		u.stop()
-	}
+// onPodFailed stops watching the build pod, reporting its failure.
+func (u *UploadCommand) onPodFailed(pod *corev1.Pod) error {
+	u.stop()
+	return fmt.Errorf("build pod '%s' has failed", pod.GetName())
+}
 
+// onPodCompleted stops watching the build pod once it succeeds.
+func (u *UploadCommand) onPodCompleted(_ *corev1.Pod) error {
+	u.stop()
 	return nil
 }
 
@@ -287,32 +360,34 @@ func (u *UploadCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStr
 		if u.follower, err = p.NewFollower(u.Cmd().Context(), types.NamespacedName{Namespace: br.Namespace, Name: br.Name}, ioStreams); err != nil {
 			return err
 		}
+		u.follower.SetMaxLogRate(u.maxLogRate)
+		u.follower.SetLineFilter(u.logFilter.LineFilter())
+		u.follower.SetHeartbeat(u.heartbeat)
 	}
 
 	switch {
 	// Using bundling to upload local source code
 	case u.sourceBundleImage != "":
-		_, err = bundle.Push(u.cmd.Context(), ioStreams, u.sourceDir, u.sourceBundleImage)
+		keychain, err := u.keychain(p)
+		if err != nil {
+			return err
+		}
+		_, err = bundle.Push(u.cmd.Context(), ioStreams, u.sourceDir, u.sourceBundleImage, keychain,
+			streamer.Compression(u.compression), u.compressionLevel)
 		if err != nil {
 			return err
 		}
-
-		u.pw.WithOnPodModifiedFn(u.onPodModifiedEventBundling)
 
 	// Using streaming to upload local source code
 	default:
 		// registering the routine that will react upon build pod state changes
 		u.pw.WithOnPodModifiedFn(u.onPodModifiedEventStreaming)
 	}
+	u.pw.WithOnPodFailedFn(u.onPodFailed).WithOnPodCompletedFn(u.onPodCompleted)
 
-	// preparing a label-selector with annotations that can pinpoint the exact pod created for the
-	// BuildRun we've just issued
-	labelSelector := fmt.Sprintf(
-		"%s=%s,%s=%s",
-		buildNameAnnotation, u.buildRunSpec.BuildRef.Name,
-		buildRunNameAnnotation, br.Name,
-	)
-	listOpts := metav1.ListOptions{LabelSelector: labelSelector}
+	// preparing a label-selector that can pinpoint the exact pod created for the BuildRun we've
+	// just issued
+	listOpts := reactor.BuildRunPodListOptions(br.Name)
 
 	// starting the event reactor with the ListOptions instance to find the desired pod, as the pod
 	// status changes, different routines are issued
@@ -331,8 +406,18 @@ func uploadCmd() runner.SubCommand {
 	u := &UploadCommand{
 		cmd:          cmd,
 		buildRunSpec: flags.BuildRunSpecFromFlags(cmd.Flags()),
+		naming:       &flags.BuildRunNaming{},
+		metadata:     &flags.ObjectMetadata{},
 		follow:       false,
 	}
 	flags.FollowFlag(cmd.Flags(), &u.follow)
+	flags.MaxLogRateFlags(cmd.Flags(), &u.maxLogRate)
+	flags.HeartbeatIntervalFlags(cmd.Flags(), &u.heartbeat)
+	flags.LogFilterFlags(cmd.Flags(), &u.logFilter)
+	flags.BuildRunNamingFlags(cmd.Flags(), u.naming)
+	flags.ObjectMetadataFlags(cmd.Flags(), u.metadata)
+	flags.RegistryAuthFlags(cmd.Flags(), &u.registryAuth, &u.registrySecret)
+	flags.CompressionFlags(cmd.Flags(), &u.compression, &u.compressionLevel)
+	flags.AuditFlags(cmd.Flags(), &u.audit)
 	return u
 }
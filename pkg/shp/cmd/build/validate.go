@@ -0,0 +1,374 @@
+package build
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// ValidateCommand contains data input from user for the validate sub-command
+type ValidateCommand struct {
+	cmd *cobra.Command
+
+	name      string                   // build name, when validating the flags
+	filename  string                   // file containing the Builds, or "-" for stdin
+	output    string                   // findings output format
+	buildSpec *buildv1alpha1.BuildSpec // stores command-line flags
+}
+
+const buildValidateLongDesc = `
+Validates Builds without contacting the cluster, either the Builds in a YAML (or JSON) file, or the
+Build described by the same flags as "shp build create". The findings are reported as errors, which
+would make the Build invalid, or warnings about common misconfigurations. For example:
+
+	$ shp build validate -f my-app.yaml
+	$ shp build validate my-app --source-url="..." --output-image="..." -o json
+
+The command exits with a non-zero code when errors are found.
+`
+
+// FindingSeverity tells whether the finding makes the Build invalid.
+type FindingSeverity string
+
+const (
+	// FindingError the Build is invalid.
+	FindingError FindingSeverity = "error"
+	// FindingWarning the Build is valid, but likely misconfigured.
+	FindingWarning FindingSeverity = "warning"
+)
+
+// Finding describes a problem found on a Build.
+type Finding struct {
+	Build    string          `json:"build"`
+	Severity FindingSeverity `json:"severity"`
+	Field    string          `json:"field,omitempty"`
+	Message  string          `json:"message"`
+}
+
+// validationReport machine readable validation outcome.
+type validationReport struct {
+	Valid    bool      `json:"valid"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+	Findings []Finding `json:"findings"`
+}
+
+// scpLikeURL git URL in the SSH short form, e.g. "git@github.com:org/app.git".
+var scpLikeURL = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[^/].*$`)
+
+// clusterRegistrySuffixes hosts of registries usually reachable without credentials.
+var clusterRegistrySuffixes = []string{".svc", ".svc.cluster.local", ".local", "localhost"}
+
+func validateCmd() runner.SubCommand {
+	cmd := &cobra.Command{
+		Use:   "validate [name] [flags]",
+		Short: "Validate Builds without contacting the cluster",
+		Long:  buildValidateLongDesc,
+		Args:  cobra.MaximumNArgs(1),
+	}
+
+	c := &ValidateCommand{
+		cmd:       cmd,
+		buildSpec: flags.BuildSpecFromFlags(cmd.Flags()),
+	}
+	cmd.Flags().StringVarP(&c.filename, "filename", "f", "", "file containing the Builds, use \"-\" to read from stdin")
+	cmd.Flags().StringVarP(&c.output, "output", "o", "", "output format of the findings, either empty or \"json\"")
+	return c
+}
+
+// Cmd returns cobra command object of the validate subcommand
+func (c *ValidateCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the Build name
+func (c *ValidateCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	if len(args) == 1 {
+		c.name = args[0]
+	}
+	return nil
+}
+
+// Validate checks user input data
+func (c *ValidateCommand) Validate() error {
+	if c.output != "" && c.output != "json" {
+		return fmt.Errorf("unsupported --output %q, only \"json\" is supported", c.output)
+	}
+	if c.filename != "" && c.name != "" {
+		return fmt.Errorf("either the Build name, along with its flags, or --filename must be informed")
+	}
+	if c.filename == "" && c.name == "" {
+		return fmt.Errorf("the Build name or --filename must be informed")
+	}
+	return nil
+}
+
+// Run lints the Builds, and reports the findings
+func (c *ValidateCommand) Run(_ *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	var findings []Finding
+	if c.filename != "" {
+		var r io.Reader = ioStreams.In
+		if c.filename != "-" {
+			f, err := os.Open(c.filename)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		var err error
+		if findings, err = lintBuildDocuments(r); err != nil {
+			return err
+		}
+	} else {
+		b := &buildv1alpha1.Build{Spec: *c.buildSpec}
+		b.SetName(c.name)
+		flags.SanitizeBuildSpec(&b.Spec)
+		findings = lintBuild(b)
+	}
+
+	report := validationReport{Findings: findings}
+	for _, f := range findings {
+		if f.Severity == FindingError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	report.Valid = report.Errors == 0
+
+	if c.output == "json" {
+		if report.Findings == nil {
+			report.Findings = []Finding{}
+		}
+		enc := json.NewEncoder(ioStreams.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range findings {
+			field := ""
+			if f.Field != "" {
+				field = f.Field + ": "
+			}
+			fmt.Fprintf(ioStreams.Out, "build/%s: %s: %s%s\n", f.Build, f.Severity, field, f.Message)
+		}
+		if len(findings) == 0 {
+			fmt.Fprintln(ioStreams.Out, "No issues found")
+		}
+	}
+
+	if !report.Valid {
+		return exitcode.Errorf(exitcode.Failure, "found %d error(s) and %d warning(s)", report.Errors, report.Warnings)
+	}
+	return nil
+}
+
+// lintBuildDocuments reads the stream of YAML (or JSON) documents, decoding them strictly, thus
+// unknown fields are reported, and lints each Build.
+func lintBuildDocuments(r io.Reader) ([]Finding, error) {
+	findings := []Finding{}
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	documents := 0
+	for {
+		doc, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		documents++
+
+		b := &buildv1alpha1.Build{}
+		if err := yaml.UnmarshalStrict(doc, b); err != nil {
+			// decoding leniently to learn the Build name, which identifies the finding
+			_ = yaml.Unmarshal(doc, b)
+			findings = append(findings, Finding{
+				Build:    nameOrIndex(b, documents),
+				Severity: FindingError,
+				Message:  fmt.Sprintf("invalid document: %s", err),
+			})
+			continue
+		}
+		if b.Kind != "Build" || b.APIVersion != buildv1alpha1.SchemeGroupVersion.String() {
+			findings = append(findings, Finding{
+				Build:    nameOrIndex(b, documents),
+				Severity: FindingError,
+				Message: fmt.Sprintf("unsupported object %s %s, only %s Builds are validated",
+					b.APIVersion, b.Kind, buildv1alpha1.SchemeGroupVersion.String()),
+			})
+			continue
+		}
+		findings = append(findings, lintBuild(b)...)
+	}
+	if documents == 0 {
+		return nil, fmt.Errorf("no Builds found")
+	}
+	return findings, nil
+}
+
+// nameOrIndex identifies the document by the object name, or by its position when unnamed.
+func nameOrIndex(b *buildv1alpha1.Build, index int) string {
+	if b.GetName() != "" {
+		return b.GetName()
+	}
+	return fmt.Sprintf("#%d", index)
+}
+
+// lintBuild checks the Build for invalid, or likely misconfigured, fields.
+func lintBuild(b *buildv1alpha1.Build) []Finding {
+	findings := []Finding{}
+	add := func(severity FindingSeverity, field, format string, a ...interface{}) {
+		findings = append(findings, Finding{
+			Build:    b.GetName(),
+			Severity: severity,
+			Field:    field,
+			Message:  fmt.Sprintf(format, a...),
+		})
+	}
+
+	if b.GetName() == "" {
+		add(FindingError, "metadata.name", "the Build name is required")
+	} else if errs := validation.IsDNS1123Subdomain(b.GetName()); len(errs) > 0 {
+		add(FindingError, "metadata.name", "invalid name: %s", strings.Join(errs, ", "))
+	}
+
+	spec := &b.Spec
+	lintSource(spec, add)
+
+	switch {
+	case spec.Strategy.Name == "":
+		add(FindingError, "spec.strategy.name", "the build strategy name is required")
+	default:
+		if errs := validation.IsDNS1123Subdomain(spec.Strategy.Name); len(errs) > 0 {
+			add(FindingError, "spec.strategy.name", "invalid strategy name: %s", strings.Join(errs, ", "))
+		}
+	}
+	if kind := spec.Strategy.Kind; kind != nil &&
+		*kind != buildv1alpha1.NamespacedBuildStrategyKind && *kind != buildv1alpha1.ClusterBuildStrategyKind {
+		add(FindingError, "spec.strategy.kind", "unknown strategy kind %q, either %q or %q", *kind,
+			buildv1alpha1.NamespacedBuildStrategyKind, buildv1alpha1.ClusterBuildStrategyKind)
+	}
+
+	if spec.Output.Image == "" {
+		add(FindingError, "spec.output.image", "the output image is required")
+	} else if ref, err := name.ParseReference(spec.Output.Image); err != nil {
+		add(FindingError, "spec.output.image", "invalid image reference: %s", err)
+	} else if spec.Output.Credentials == nil && !isClusterRegistry(ref.Context().RegistryStr()) {
+		add(FindingWarning, "spec.output.credentials",
+			"no output credentials, pushing to %q relies on the service account image pull secrets",
+			ref.Context().RegistryStr())
+	}
+
+	if spec.Timeout != nil && spec.Timeout.Duration < 0 {
+		add(FindingError, "spec.timeout", "the timeout must be positive")
+	}
+	for i, env := range spec.Env {
+		if errs := validation.IsEnvVarName(env.Name); len(errs) > 0 {
+			add(FindingError, fmt.Sprintf("spec.env[%d].name", i), "invalid environment variable %q: %s",
+				env.Name, strings.Join(errs, ", "))
+		}
+	}
+	seen := map[string]bool{}
+	for i, pv := range spec.ParamValues {
+		if seen[pv.Name] {
+			add(FindingError, fmt.Sprintf("spec.paramValues[%d].name", i), "parameter %q is informed more than once", pv.Name)
+		}
+		seen[pv.Name] = true
+	}
+
+	if spec.Dockerfile != nil {
+		add(FindingWarning, "spec.dockerfile", "deprecated, use the %q strategy parameter instead", dockerfileParam)
+	}
+	if spec.Builder != nil {
+		add(FindingWarning, "spec.builder", "deprecated, use the equivalent strategy parameter instead")
+	}
+	return findings
+}
+
+// lintSource checks the Build source, either a git repository or a source bundle image.
+func lintSource(spec *buildv1alpha1.BuildSpec, add func(FindingSeverity, string, string, ...interface{})) {
+	source := spec.Source
+	bundle := source.BundleContainer != nil && source.BundleContainer.Image != ""
+	switch {
+	case bundle:
+		if _, err := name.ParseReference(source.BundleContainer.Image); err != nil {
+			add(FindingError, "spec.source.bundleContainer.image", "invalid image reference: %s", err)
+		}
+	case source.URL == nil || *source.URL == "":
+		add(FindingError, "spec.source.url", "either the source URL or the source bundle image is required")
+	default:
+		lintSourceURL(*source.URL, source.Credentials != nil, add)
+	}
+
+	if source.ContextDir != nil {
+		contextDir := *source.ContextDir
+		if path.IsAbs(contextDir) || strings.HasPrefix(path.Clean(contextDir), "..") {
+			add(FindingError, "spec.source.contextDir", "must be a path relative to the source root, %q is not", contextDir)
+		}
+	}
+}
+
+// lintSourceURL checks the git repository URL, and whether SSH URLs carry credentials.
+func lintSourceURL(sourceURL string, credentials bool, add func(FindingSeverity, string, string, ...interface{})) {
+	if scpLikeURL.MatchString(sourceURL) {
+		if !credentials {
+			add(FindingWarning, "spec.source.credentials", "SSH repositories require credentials")
+		}
+		return
+	}
+	u, err := url.Parse(sourceURL)
+	if err != nil || u.Host == "" {
+		add(FindingError, "spec.source.url", "invalid git URL %q", sourceURL)
+		return
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		add(FindingWarning, "spec.source.url", "the repository is cloned over plain HTTP")
+	case "ssh":
+		if !credentials {
+			add(FindingWarning, "spec.source.credentials", "SSH repositories require credentials")
+		}
+	default:
+		add(FindingError, "spec.source.url", "unsupported URL scheme %q, either https or ssh", u.Scheme)
+	}
+}
+
+// isClusterRegistry tells whether the registry is likely served within the cluster, or locally.
+func isClusterRegistry(registry string) bool {
+	host := strings.Split(registry, ":")[0]
+	for _, suffix := range clusterRegistrySuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return host == "127.0.0.1"
+}
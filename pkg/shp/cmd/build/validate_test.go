@@ -0,0 +1,107 @@
+package build
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+const validateBuilds = `
+apiVersion: shipwright.io/v1alpha1
+kind: Build
+metadata:
+  name: app
+spec:
+  source:
+    url: https://github.com/org/app
+  strategy:
+    name: buildpacks-v3
+    kind: ClusterBuildStrategy
+  output:
+    image: image-registry.openshift-image-registry.svc:5000/org/app
+---
+apiVersion: shipwright.io/v1alpha1
+kind: Build
+metadata:
+  name: api
+spec:
+  source:
+    url: git@github.com:org/api.git
+    contextDir: ../api
+  strategy:
+    name: buildah
+    kind: Strategy
+  dockerfile: Dockerfile
+  output:
+    image: ghcr.io/org/api
+---
+apiVersion: shipwright.io/v1alpha1
+kind: Build
+metadata:
+  name: typo
+spec:
+  source:
+    uri: https://github.com/org/typo
+`
+
+func TestValidateCommand(t *testing.T) {
+	g := o.NewWithT(t)
+	p := params.NewParamsForTest(nil, nil, nil, metav1.NamespaceDefault, nil, nil)
+
+	run := func(stdin string, args ...string) (string, error) {
+		cmd := validateCmd().(*ValidateCommand)
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		ioStreams, in, out, _ := genericclioptions.NewTestIOStreams()
+		in.WriteString(stdin)
+		g.Expect(cmd.Complete(p, &ioStreams, cmd.cmd.Flags().Args())).To(o.Succeed())
+		if err := cmd.Validate(); err != nil {
+			return "", err
+		}
+		err := cmd.Run(p, &ioStreams)
+		return out.String(), err
+	}
+
+	t.Run("file", func(t *testing.T) {
+		out, err := run(validateBuilds, "-f", "-")
+		g.Expect(exitcode.FromError(err)).To(o.Equal(exitcode.Failure))
+		g.Expect(strings.Split(strings.TrimSpace(out), "\n")).To(o.Equal([]string{
+			`build/api: warning: spec.source.credentials: SSH repositories require credentials`,
+			`build/api: error: spec.source.contextDir: must be a path relative to the source root, "../api" is not`,
+			`build/api: error: spec.strategy.kind: unknown strategy kind "Strategy", either "BuildStrategy" or "ClusterBuildStrategy"`,
+			`build/api: warning: spec.output.credentials: no output credentials, pushing to "ghcr.io" relies on the service account image pull secrets`,
+			`build/api: warning: spec.dockerfile: deprecated, use the "dockerfile" strategy parameter instead`,
+			`build/typo: error: invalid document: error unmarshaling JSON: while decoding JSON: json: unknown field "uri"`,
+		}))
+	})
+
+	t.Run("flags as json", func(t *testing.T) {
+		out, err := run("", "my-app", "--source-url=https://github.com/org/app", "--output-image=ghcr.io/org/app",
+			"--output-credentials-secret=push", "-o", "json")
+		g.Expect(err).NotTo(o.HaveOccurred())
+
+		report := validationReport{}
+		g.Expect(json.Unmarshal([]byte(out), &report)).To(o.Succeed())
+		g.Expect(report.Valid).To(o.BeTrue())
+		g.Expect(report.Findings).To(o.BeEmpty())
+	})
+
+	t.Run("flags without output image", func(t *testing.T) {
+		out, err := run("", "my-app", "--source-url=ftp://example.com/app")
+		g.Expect(err).To(o.MatchError("found 2 error(s) and 0 warning(s)"))
+		g.Expect(out).To(o.ContainSubstring(`spec.source.url: unsupported URL scheme "ftp"`))
+		g.Expect(out).To(o.ContainSubstring("spec.output.image: the output image is required"))
+	})
+
+	t.Run("name and file", func(t *testing.T) {
+		_, err := run("", "my-app", "-f", "-")
+		g.Expect(err).To(o.HaveOccurred())
+	})
+}
@@ -0,0 +1,24 @@
+package build
+
+import (
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// webhookCmd instantiate the "webhook" command, grouping the subcommands exercising the webhook of
+// the Build's GitHub triggers.
+func webhookCmd(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "webhook",
+		Short: "Exercise the Build trigger webhooks",
+	}
+
+	command.AddCommand(
+		runner.NewRunner(p, ioStreams, webhookInvokeCmd()).Cmd(),
+	)
+	return command
+}
@@ -0,0 +1,260 @@
+package build
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/webhook"
+)
+
+// webhookTokenKey key of the trigger secret carrying the webhook token, when the secret has more
+// than one entry.
+const webhookTokenKey = "token"
+
+// webhookTimeout maximum amount of time to deliver the webhook request.
+const webhookTimeout = 30 * time.Second
+
+// WebhookInvokeCommand contains data provided by user to the webhook invoke subcommand
+type WebhookInvokeCommand struct {
+	cmd *cobra.Command
+
+	buildName  string
+	endpoint   string
+	event      string
+	branch     string
+	headBranch string
+	sha        string
+	token      string
+	dryRun     bool
+
+	githubEvent buildv1alpha1.GitHubEventName
+}
+
+const webhookInvokeLongDesc = `
+Sends a simulated GitHub event for the Build's repository to the trigger webhook endpoint, thus the
+trigger wiring can be tested without pushing commits. The request is signed with the token of the
+Build's trigger secret, its single entry or the "token" key, unless --token is informed.
+
+The branch defaults to the first one of the GitHub trigger conditions for the event, and then to the
+Build's source revision. Pull request events target the branch, coming from --head-branch. The
+commit SHA is random unless informed. For example:
+
+	$ shp build webhook invoke my-app --url=https://triggers.example.com/ --branch=main
+	$ shp build webhook invoke my-app --url=https://triggers.example.com/ --github-event=PullRequest --dry-run
+`
+
+func webhookInvokeCmd() runner.SubCommand {
+	c := &WebhookInvokeCommand{
+		cmd: &cobra.Command{
+			Use:   "invoke <build> [flags]",
+			Short: "Send a simulated GitHub event to the trigger webhook endpoint",
+			Long:  webhookInvokeLongDesc,
+			Args:  cobra.ExactArgs(1),
+		},
+	}
+
+	f := c.cmd.Flags()
+	f.StringVar(&c.endpoint, "url", "", "URL of the trigger webhook endpoint")
+	f.StringVar(&c.event, "github-event", string(buildv1alpha1.GitHubPushEvent), "GitHub event sent, either Push or PullRequest")
+	f.StringVar(&c.branch, "branch", "", "branch pushed, or targeted by the pull request")
+	f.StringVar(&c.headBranch, "head-branch", "shp-webhook-invoke", "branch the pull request comes from")
+	f.StringVar(&c.sha, "sha", "", "commit SHA pushed, or the pull request head commit, random when empty")
+	f.StringVar(&c.token, "token", "", "webhook token signing the request, by default taken from the Build trigger secret")
+	f.BoolVar(&c.dryRun, "dry-run", false, "print the request instead of sending it")
+
+	return c
+}
+
+// Cmd returns cobra command object of the webhook invoke subcommand
+func (c *WebhookInvokeCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *WebhookInvokeCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.buildName = args[0]
+	return nil
+}
+
+// Validate checks the endpoint and GitHub event informed
+func (c *WebhookInvokeCommand) Validate() error {
+	if c.endpoint == "" && !c.dryRun {
+		return fmt.Errorf("--url must be informed, unless using --dry-run")
+	}
+	var err error
+	c.githubEvent, err = parseGitHubEvent(c.event)
+	return err
+}
+
+// Run crafts the GitHub event for the Build's repository and sends it to the endpoint
+func (c *WebhookInvokeCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	ctx := c.cmd.Context()
+	b, err := clientset.ShipwrightV1alpha1().Builds(p.Namespace()).Get(ctx, c.buildName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if b.Spec.Source.URL == nil || *b.Spec.Source.URL == "" {
+		return fmt.Errorf("Build %q does not have a git repository URL", c.buildName)
+	}
+
+	event := &webhook.GitHubEvent{
+		Name:       webhook.GitHubPushEvent,
+		Repository: *b.Spec.Source.URL,
+		Branch:     c.branch,
+		HeadBranch: c.headBranch,
+		SHA:        c.sha,
+	}
+	if c.githubEvent == buildv1alpha1.GitHubPullRequestEvent {
+		event.Name = webhook.GitHubPullRequestEvent
+	}
+	if event.Branch == "" {
+		event.Branch = c.defaultBranch(b)
+	}
+	if event.SHA == "" {
+		if event.SHA, err = webhook.RandomSHA(); err != nil {
+			return err
+		}
+	}
+	if !c.matchesTrigger(b.Spec.Trigger, event.Branch) {
+		fmt.Fprintf(ioStreams.ErrOut, "Warning: no GitHub trigger condition of Build %q matches the %s event on branch %q\n",
+			c.buildName, c.githubEvent, event.Branch)
+	}
+
+	token := c.token
+	if token == "" && b.Spec.Trigger != nil && b.Spec.Trigger.SecretRef != nil {
+		if token, err = c.secretToken(p, b.Spec.Trigger.SecretRef.Name); err != nil {
+			return err
+		}
+	}
+
+	payload, err := event.Payload()
+	if err != nil {
+		return err
+	}
+	req, err := webhook.NewGitHubRequest(ctx, c.endpoint, event.Name, payload, token)
+	if err != nil {
+		return err
+	}
+
+	if c.dryRun {
+		fmt.Fprintf(ioStreams.Out, "POST %s\n", c.endpoint)
+		keys := make([]string, 0, len(req.Header))
+		for key := range req.Header {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(ioStreams.Out, "%s: %s\n", key, req.Header.Get(key))
+		}
+		fmt.Fprintf(ioStreams.Out, "\n%s\n", payload)
+		return nil
+	}
+
+	fmt.Fprintf(ioStreams.Out, "Sending GitHub %s event for branch %q at %s to %q ...\n",
+		event.Name, event.Branch, event.SHA, req.URL.Redacted())
+	status, err := webhook.Deliver(&http.Client{Timeout: webhookTimeout}, req)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "Delivered, the endpoint answered %q\n", status)
+	return nil
+}
+
+// defaultBranch returns the first branch of the GitHub trigger conditions for the event, and then
+// the Build's source revision, or "main" when none is set.
+func (c *WebhookInvokeCommand) defaultBranch(b *buildv1alpha1.Build) string {
+	if b.Spec.Trigger != nil {
+		for _, when := range b.Spec.Trigger.When {
+			if c.watchesEvent(when) && len(when.GitHub.Branches) > 0 {
+				return when.GitHub.Branches[0]
+			}
+		}
+	}
+	if b.Spec.Source.Revision != nil && *b.Spec.Source.Revision != "" {
+		return *b.Spec.Source.Revision
+	}
+	return "main"
+}
+
+// watchesEvent tells whether the trigger condition is a GitHub one watching the informed event.
+func (c *WebhookInvokeCommand) watchesEvent(when buildv1alpha1.TriggerWhen) bool {
+	if when.Type != buildv1alpha1.GitHubWebHookTrigger || when.GitHub == nil {
+		return false
+	}
+	for _, e := range when.GitHub.Events {
+		if e == c.githubEvent {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTrigger tells whether a GitHub trigger condition applies to the event on the branch, the
+// conditions without branches apply to all of them.
+func (c *WebhookInvokeCommand) matchesTrigger(trigger *buildv1alpha1.Trigger, branch string) bool {
+	if trigger == nil {
+		return false
+	}
+	for _, when := range trigger.When {
+		if !c.watchesEvent(when) {
+			continue
+		}
+		if len(when.GitHub.Branches) == 0 {
+			return true
+		}
+		for _, b := range when.GitHub.Branches {
+			if b == branch {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// secretToken reads the webhook token out of the trigger secret.
+func (c *WebhookInvokeCommand) secretToken(p *params.Params, name string) (string, error) {
+	clientset, err := p.ClientSet()
+	if err != nil {
+		return "", err
+	}
+	secret, err := clientset.CoreV1().Secrets(p.Namespace()).Get(c.cmd.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to read the trigger secret %q: %w", name, err)
+	}
+	return tokenOf(secret)
+}
+
+// tokenOf returns the single entry of the secret, or the one on the token key.
+func tokenOf(secret *corev1.Secret) (string, error) {
+	if len(secret.Data) == 1 {
+		for _, v := range secret.Data {
+			return string(v), nil
+		}
+	}
+	if v, ok := secret.Data[webhookTokenKey]; ok {
+		return string(v), nil
+	}
+	keys := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return "", fmt.Errorf("trigger secret %q does not have the %q key, found: %s",
+		secret.GetName(), webhookTokenKey, strings.Join(keys, ", "))
+}
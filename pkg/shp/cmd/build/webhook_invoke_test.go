@@ -0,0 +1,93 @@
+package build
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/webhook"
+)
+
+func TestBuildWebhookInvoke(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	build := &buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: ns},
+		Spec: buildv1alpha1.BuildSpec{
+			Source: buildv1alpha1.Source{URL: pointer.String("https://github.com/org/app")},
+			Trigger: &buildv1alpha1.Trigger{
+				When: []buildv1alpha1.TriggerWhen{{
+					Name:   "push",
+					Type:   buildv1alpha1.GitHubWebHookTrigger,
+					GitHub: &buildv1alpha1.WhenGitHub{Events: []buildv1alpha1.GitHubEventName{buildv1alpha1.GitHubPushEvent}, Branches: []string{"release"}},
+				}},
+				SecretRef: &corev1.LocalObjectReference{Name: "webhook"},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: ns},
+		Data:       map[string][]byte{"token": []byte("s3cr3t"), "other": []byte("value")},
+	}
+	p := params.NewParamsForTest(fake.NewSimpleClientset(secret), shpfake.NewSimpleClientset(build), nil, ns, nil, nil)
+
+	run := func(args ...string) (string, string, error) {
+		cmd := webhookInvokeCmd()
+		cmd.Cmd().SetContext(context.TODO())
+		g.Expect(cmd.Cmd().ParseFlags(args)).To(o.Succeed())
+		if err := cmd.Complete(p, nil, cmd.Cmd().Flags().Args()); err != nil {
+			return "", "", err
+		}
+		if err := cmd.Validate(); err != nil {
+			return "", "", err
+		}
+		ioStreams, _, out, errOut := genericclioptions.NewTestIOStreams()
+		err := cmd.Run(p, &ioStreams)
+		return out.String(), errOut.String(), err
+	}
+
+	// the branch comes from the trigger condition, and the request is signed with the secret token
+	out, errOut, err := run("my-app", "--url", server.URL, "--sha", "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(errOut).To(o.BeEmpty())
+	g.Expect(out).To(o.ContainSubstring(`Sending GitHub push event for branch "release" at a1b2c3d4e5f60718293a4b5c6d7e8f9012345678`))
+	g.Expect(out).To(o.ContainSubstring(`Delivered, the endpoint answered "202 Accepted"`))
+	g.Expect(received.Header.Get("X-GitHub-Event")).To(o.Equal("push"))
+	g.Expect(received.Header.Get("X-Hub-Signature-256")).To(o.Equal(webhook.Signature(body, "s3cr3t")))
+	g.Expect(string(body)).To(o.ContainSubstring(`"ref": "refs/heads/release"`))
+
+	// events not matching any condition are sent anyway, with a warning
+	out, errOut, err = run("my-app", "--github-event", "pullrequest", "--dry-run", "--token", "other")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(errOut).To(o.Equal("Warning: no GitHub trigger condition of Build \"my-app\" matches the PullRequest event on branch \"main\"\n"))
+	g.Expect(out).To(o.ContainSubstring("X-Github-Event: pull_request\n"))
+	g.Expect(out).To(o.ContainSubstring(`"title": "Simulated pull request sent by shp"`))
+
+	_, _, err = run("my-app")
+	g.Expect(err).To(o.MatchError("--url must be informed, unless using --dry-run"))
+	_, _, err = run("my-app", "--dry-run", "--github-event", "issues")
+	g.Expect(err).To(o.MatchError(o.HavePrefix(`unknown GitHub event "issues"`)))
+}
@@ -27,6 +27,16 @@ func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Co
 		runner.NewRunner(p, ioStreams, createCmd()).Cmd(),
 		runner.NewRunner(p, ioStreams, cancelCmd()).Cmd(),
 		runner.NewRunner(p, ioStreams, deleteCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, pruneCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, statsCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, vulnerabilitiesCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, waitCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, eventsCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, resultsCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, describeCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, topCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, sbomCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, execCmd()).Cmd(),
 	)
 	return command
 }
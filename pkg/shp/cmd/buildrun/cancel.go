@@ -1,18 +1,17 @@
 package buildrun
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cobra"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/util"
 )
 
 // CancelCommand contains data input from user for delete sub-command
@@ -64,21 +63,7 @@ func (c *CancelCommand) Run(params *params.Params, ioStreams *genericclioptions.
 		return fmt.Errorf("failed to cancel BuildRun %s: execution has already finished", c.name)
 	}
 
-	type patchStringValue struct {
-		Op    string `json:"op"`
-		Path  string `json:"path"`
-		Value string `json:"value"`
-	}
-	payload := []patchStringValue{{
-		Op:    "replace",
-		Path:  "/spec/state",
-		Value: buildv1alpha1.BuildRunStateCancel,
-	}}
-	var data []byte
-	if data, err = json.Marshal(payload); err != nil {
-		return err
-	}
-	if _, err = clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Patch(c.Cmd().Context(), c.name, types.JSONPatchType, data, metav1.PatchOptions{}); err != nil {
+	if err = util.CancelBuildRun(c.Cmd().Context(), clientset, params.Namespace(), c.name); err != nil {
 		return err
 	}
 
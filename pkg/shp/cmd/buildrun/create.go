@@ -2,16 +2,22 @@ package buildrun
 
 import (
 	"fmt"
+	"io"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
 	"github.com/spf13/cobra"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/shipwright-io/cli/pkg/shp/audit"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/version"
 	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/manifest"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/util"
 )
 
 // CreateCommand reprents the build's create subcommand.
@@ -20,6 +26,11 @@ type CreateCommand struct {
 
 	name         string                      // buildrun name
 	buildRunSpec *buildv1alpha1.BuildRunSpec // stores command-line flags
+	metadata     *flags.ObjectMetadata       // BuildRun labels, annotations and ownership
+
+	createNamespace bool     // create the namespace when absent
+	filenames       []string // manifests of the BuildRuns, instead of the flags
+	audit           bool     // record who triggered the BuildRuns
 }
 
 const buildRunCreateLongDesc = `
@@ -27,6 +38,20 @@ Creates a new BuildRun instance using the given name, and requires --buildref-na
 find the Build object. Example:
 
 	$ shp buildrun create my-app-build --buildref-name="..."
+
+Labels and annotations can be set on the BuildRun, and the Build set as its owner:
+
+	$ shp buildrun create my-app-build --buildref-name="..." --label=team=platform --owned-by-build
+
+The BuildRuns can be created out of manifests instead, read from files, directories, URLs, or the
+standard input with "-", including multi-document YAML streams. The name argument is omitted then,
+and manifests relying on "generateName" are supported:
+
+	$ shp buildrun create -f buildrun.yaml
+	$ generate-manifests | shp buildrun create -f -
+
+With --audit, or the "audit" configuration key, the BuildRuns are annotated with the user, host
+and CLI version creating them, and a "TriggeredFromCLI" Event is recorded on each of them.
 `
 
 // Cmd returns cobra.Command object of the create sub-command.
@@ -36,8 +61,11 @@ func (c *CreateCommand) Cmd() *cobra.Command {
 
 // Complete checks if the arguments is informing the BuildRun name.
 func (c *CreateCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
-	switch len(args) {
-	case 1:
+	switch {
+	case len(c.filenames) > 0 && len(args) > 0:
+		return fmt.Errorf("the name argument can't be informed along with --%s", flags.FilenameFlag)
+	case len(c.filenames) > 0:
+	case len(args) == 1:
 		c.name = args[0]
 	default:
 		return fmt.Errorf("wrong amount of arguments, expected only one")
@@ -47,14 +75,23 @@ func (c *CreateCommand) Complete(_ *params.Params, _ *genericclioptions.IOStream
 
 // Validate makes sure a name is informed.
 func (c *CreateCommand) Validate() error {
+	if len(c.filenames) > 0 {
+		if err := manifest.ValidateFilenames(c.filenames); err != nil {
+			return err
+		}
+		return c.metadata.Validate()
+	}
 	if c.name == "" {
 		return fmt.Errorf("name is not informed")
 	}
-	return nil
+	return c.metadata.Validate()
 }
 
 // Run executes the creation of BuildRun object.
 func (c *CreateCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	if len(c.filenames) > 0 {
+		return c.createFromManifests(params, ioStreams)
+	}
 	br := &buildv1alpha1.BuildRun{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: c.name,
@@ -64,22 +101,160 @@ func (c *CreateCommand) Run(params *params.Params, ioStreams *genericclioptions.
 
 	flags.SanitizeBuildRunSpec(&br.Spec)
 
+	out := ioStreams.Out
+	ioStreams = params.QuietStreams(ioStreams)
+	if c.createNamespace {
+		if err := c.ensureNamespace(params, ioStreams); err != nil {
+			return err
+		}
+	}
+
 	clientset, err := params.ShipwrightClientSet()
 	if err != nil {
 		return err
 	}
-	if _, err = clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Create(c.cmd.Context(), br, metav1.CreateOptions{}); err != nil {
+	var owner *buildv1alpha1.Build
+	if c.metadata.RequiresOwner() {
+		if owner, err = clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Get(c.cmd.Context(), br.Spec.BuildRef.Name, metav1.GetOptions{}); err != nil {
+			return err
+		}
+	}
+	c.metadata.Apply(&br.ObjectMeta, owner)
+	recorder, err := c.newRecorder(params)
+	if err != nil {
+		return err
+	}
+	if _, err = c.create(clientset, params.Namespace(), br, recorder, ioStreams.ErrOut); err != nil {
 		return err
 	}
+	if params.Quiet() {
+		fmt.Fprintln(out, c.name)
+		return nil
+	}
 	fmt.Fprintf(ioStreams.Out, "BuildRun created %q for Build %q\n", c.name, br.Spec.BuildRef.Name)
 	return nil
 }
 
+// newRecorder instantiates the audit recorder when --audit is informed, nil otherwise.
+func (c *CreateCommand) newRecorder(params *params.Params) (*audit.Recorder, error) {
+	if !c.audit {
+		return nil, nil
+	}
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return nil, err
+	}
+	return audit.NewRecorder(c.cmd.Context(), clientset, params.KubeconfigUser(), version.Current()), nil
+}
+
+// create creates the BuildRun, annotated and with the Event recorded when the recorder is
+// informed, failing to record the Event only warns.
+func (c *CreateCommand) create(
+	clientset buildclientset.Interface,
+	ns string,
+	br *buildv1alpha1.BuildRun,
+	recorder *audit.Recorder,
+	w io.Writer,
+) (*buildv1alpha1.BuildRun, error) {
+	ctx := c.cmd.Context()
+	if recorder != nil {
+		recorder.Annotate(&br.ObjectMeta)
+	}
+	created, err := clientset.ShipwrightV1alpha1().BuildRuns(ns).Create(ctx, br, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if recorder != nil {
+		if err := recorder.Emit(ctx, created); err != nil {
+			fmt.Fprintf(w, "Warning: unable to record the audit Event on BuildRun %q: %v\n", created.GetName(), err)
+		}
+	}
+	return created, nil
+}
+
+// createFromManifests creates the BuildRuns read from the manifests, all of them are read before
+// creating the first BuildRun, thus an invalid manifest doesn't leave the BuildRuns partially
+// created. The labels, annotations and owner informed on the flags are applied on each BuildRun.
+func (c *CreateCommand) createFromManifests(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	objects, err := manifest.ReadAll(c.cmd.Context(), ioStreams.In, c.filenames)
+	if err != nil {
+		return err
+	}
+	buildRuns := []*buildv1alpha1.BuildRun{}
+	for _, obj := range objects {
+		if err = manifest.SetNamespace(obj, params.Namespace()); err != nil {
+			return err
+		}
+		br := &buildv1alpha1.BuildRun{}
+		if err = manifest.Convert(obj, "BuildRun", br); err != nil {
+			return err
+		}
+		buildRuns = append(buildRuns, br)
+	}
+	if len(buildRuns) == 0 {
+		return fmt.Errorf("no BuildRuns found")
+	}
+
+	out := ioStreams.Out
+	ioStreams = params.QuietStreams(ioStreams)
+	if c.createNamespace {
+		if err = c.ensureNamespace(params, ioStreams); err != nil {
+			return err
+		}
+	}
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	recorder, err := c.newRecorder(params)
+	if err != nil {
+		return err
+	}
+	for _, br := range buildRuns {
+		var owner *buildv1alpha1.Build
+		if c.metadata.RequiresOwner() {
+			if br.Spec.BuildRef == nil {
+				return fmt.Errorf("BuildRun %q has no Build reference to own it", br.GetName())
+			}
+			if owner, err = clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Get(c.cmd.Context(), br.Spec.BuildRef.Name, metav1.GetOptions{}); err != nil {
+				return err
+			}
+		}
+		c.metadata.Apply(&br.ObjectMeta, owner)
+		created, err := c.create(clientset, params.Namespace(), br, recorder, ioStreams.ErrOut)
+		if err != nil {
+			return err
+		}
+		if params.Quiet() {
+			fmt.Fprintln(out, created.GetName())
+			continue
+		}
+		fmt.Fprintf(ioStreams.Out, "BuildRun created %q for Build %q\n", created.GetName(), created.Spec.BuildName())
+	}
+	return nil
+}
+
+// ensureNamespace creates the target namespace, when it does not exist yet.
+func (c *CreateCommand) ensureNamespace(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	created, err := util.EnsureNamespace(c.cmd.Context(), clientset, params.Namespace())
+	if err != nil {
+		return fmt.Errorf("unable to create namespace %q: %w", params.Namespace(), err)
+	}
+	if created {
+		fmt.Fprintf(ioStreams.Out, "Created namespace %q\n", params.Namespace())
+	}
+	return nil
+}
+
 // createCmd instantiate a new CreateCommand, by wiring it as a cobra.Command and registering the
 // flags and marking flags required.
 func createCmd() runner.SubCommand {
 	cmd := &cobra.Command{
-		Use:   "create <name> [flags]",
+		Use:   "create [<name>] [flags]",
 		Short: "Creates a BuildRun instance.",
 		Long:  buildRunCreateLongDesc,
 	}
@@ -87,12 +262,19 @@ func createCmd() runner.SubCommand {
 	// instantiating command-line flags, using an actual BuildRunSpec object to receive the flags
 	// issued on command-line, also marking flags as required
 	buildRunSpecFlags := flags.BuildRunSpecFromFlags(cmd.Flags())
-	if err := cmd.MarkFlagRequired(flags.BuildrefNameFlag); err != nil {
-		panic(err)
-	}
 
-	return &CreateCommand{
+	metadata := &flags.ObjectMetadata{}
+	flags.ObjectMetadataFlags(cmd.Flags(), metadata)
+
+	c := &CreateCommand{
 		cmd:          cmd,
 		buildRunSpec: buildRunSpecFlags,
+		metadata:     metadata,
 	}
+	flags.CreateNamespaceFlags(cmd.Flags(), &c.createNamespace)
+	flags.FilenamesFlags(cmd.Flags(), &c.filenames)
+	flags.AuditFlags(cmd.Flags(), &c.audit)
+	cmd.MarkFlagsOneRequired(flags.BuildrefNameFlag, flags.FilenameFlag)
+	cmd.MarkFlagsMutuallyExclusive(flags.BuildrefNameFlag, flags.FilenameFlag)
+	return c
 }
@@ -0,0 +1,179 @@
+package buildrun
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/audit"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestCreateBuildRunMetadata(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+
+	shpclientset := shpfake.NewSimpleClientset(
+		&buildv1alpha1.Build{ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: ns, UID: types.UID("uid")}},
+	)
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, ns, nil, nil)
+	ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+
+	cmd := createCmd().(*CreateCommand)
+	cmd.cmd.SetContext(context.TODO())
+	g.Expect(cmd.cmd.ParseFlags([]string{
+		"--buildref-name", "my-app",
+		"--label", "team=platform",
+		"--annotation", "ci.example.com/job=42",
+		"--owned-by-build",
+	})).To(o.Succeed())
+	g.Expect(cmd.Complete(p, &ioStreams, []string{"my-app-run"})).To(o.Succeed())
+	g.Expect(cmd.Validate()).To(o.Succeed())
+	g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+
+	br, err := shpclientset.ShipwrightV1alpha1().BuildRuns(ns).Get(context.TODO(), "my-app-run", metav1.GetOptions{})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(br.Labels).To(o.HaveKeyWithValue("team", "platform"))
+	g.Expect(br.Annotations).To(o.HaveKeyWithValue("ci.example.com/job", "42"))
+	g.Expect(br.OwnerReferences).To(o.HaveLen(1))
+	g.Expect(br.OwnerReferences[0].Name).To(o.Equal("my-app"))
+	g.Expect(br.OwnerReferences[0].UID).To(o.Equal(types.UID("uid")))
+}
+
+func TestCreateBuildRunCreateNamespace(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := "ephemeral"
+
+	clientset := fake.NewSimpleClientset()
+	shpclientset := shpfake.NewSimpleClientset()
+	p := params.NewParamsForTest(clientset, shpclientset, nil, ns, nil, nil)
+
+	for i, expected := range []string{
+		"Created namespace \"ephemeral\"\nBuildRun created \"my-app-run-1\" for Build \"my-app\"\n",
+		"BuildRun created \"my-app-run-2\" for Build \"my-app\"\n",
+	} {
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		cmd := createCmd().(*CreateCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags([]string{"--buildref-name", "my-app", "--create-namespace"})).To(o.Succeed())
+		g.Expect(cmd.Complete(p, &ioStreams, []string{fmt.Sprintf("my-app-run-%d", i+1)})).To(o.Succeed())
+		g.Expect(cmd.Validate()).To(o.Succeed())
+		g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+		g.Expect(out.String()).To(o.Equal(expected))
+	}
+
+	_, err := clientset.CoreV1().Namespaces().Get(context.TODO(), ns, metav1.GetOptions{})
+	g.Expect(err).NotTo(o.HaveOccurred())
+}
+
+func TestCreateBuildRunQuiet(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := "ephemeral"
+
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpfake.NewSimpleClientset(), nil, ns, nil, nil)
+	p.SetQuiet(true)
+
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := createCmd().(*CreateCommand)
+	cmd.cmd.SetContext(context.TODO())
+	g.Expect(cmd.cmd.ParseFlags([]string{"--buildref-name", "my-app", "--create-namespace"})).To(o.Succeed())
+	g.Expect(cmd.Complete(p, &ioStreams, []string{"my-app-run-1"})).To(o.Succeed())
+	g.Expect(cmd.Validate()).To(o.Succeed())
+	g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+	g.Expect(out.String()).To(o.Equal("my-app-run-1\n"))
+}
+
+func TestCreateBuildRunFromManifests(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+
+	manifests := `apiVersion: shipwright.io/v1alpha1
+kind: BuildRun
+metadata:
+  name: my-app-1
+spec:
+  buildRef:
+    name: my-app
+---
+apiVersion: shipwright.io/v1alpha1
+kind: BuildRun
+metadata:
+  name: my-app-2
+spec:
+  buildRef:
+    name: my-app
+`
+	shpclientset := shpfake.NewSimpleClientset()
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, ns, nil, nil)
+
+	run := func(stdin string, args ...string) (string, error) {
+		cmd := createCmd().(*CreateCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		ioStreams, in, out, _ := genericclioptions.NewTestIOStreams()
+		in.WriteString(stdin)
+		if err := cmd.Complete(p, &ioStreams, cmd.cmd.Flags().Args()); err != nil {
+			return "", err
+		}
+		if err := cmd.Validate(); err != nil {
+			return "", err
+		}
+		err := cmd.Run(p, &ioStreams)
+		return out.String(), err
+	}
+
+	out, err := run(manifests, "-f", "-", "--label", "team=platform")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).To(o.Equal("BuildRun created \"my-app-1\" for Build \"my-app\"\n" +
+		"BuildRun created \"my-app-2\" for Build \"my-app\"\n"))
+	br, err := shpclientset.ShipwrightV1alpha1().BuildRuns(ns).Get(context.TODO(), "my-app-2", metav1.GetOptions{})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(br.Labels).To(o.HaveKeyWithValue("team", "platform"))
+
+	_, err = run(manifests, "-f", "-", "my-app-3")
+	g.Expect(err).To(o.MatchError("the name argument can't be informed along with --filename"))
+
+	_, err = run("apiVersion: shipwright.io/v1alpha1\nkind: Build\nmetadata:\n  name: my-app\n", "-f", "-")
+	g.Expect(err).To(o.MatchError(`Build "my-app" is not a BuildRun`))
+
+	_, err = run("", "-f", "-")
+	g.Expect(err).To(o.MatchError("no BuildRuns found"))
+}
+
+func TestCreateBuildRunAudit(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+
+	clientset := fake.NewSimpleClientset()
+	shpclientset := shpfake.NewSimpleClientset()
+	p := params.NewParamsForTest(clientset, shpclientset, nil, ns, nil, nil)
+	ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+
+	cmd := createCmd().(*CreateCommand)
+	cmd.cmd.SetContext(context.TODO())
+	g.Expect(cmd.cmd.ParseFlags([]string{"--buildref-name", "my-app", "--audit"})).To(o.Succeed())
+	g.Expect(cmd.Complete(p, &ioStreams, []string{"my-app-run"})).To(o.Succeed())
+	g.Expect(cmd.Validate()).To(o.Succeed())
+	g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+
+	br, err := shpclientset.ShipwrightV1alpha1().BuildRuns(ns).Get(context.TODO(), "my-app-run", metav1.GetOptions{})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(br.Annotations).To(o.HaveKey(audit.TriggeredByAnnotation))
+	g.Expect(br.Annotations).To(o.HaveKey(audit.HostAnnotation))
+	g.Expect(br.Annotations).To(o.HaveKey(audit.VersionAnnotation))
+
+	events, err := clientset.CoreV1().Events(ns).List(context.TODO(), metav1.ListOptions{})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(events.Items).To(o.HaveLen(1))
+	g.Expect(events.Items[0].Reason).To(o.Equal(audit.EventReason))
+	g.Expect(events.Items[0].InvolvedObject.Name).To(o.Equal("my-app-run"))
+}
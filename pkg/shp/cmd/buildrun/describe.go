@@ -0,0 +1,292 @@
+package buildrun
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/printer"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+)
+
+// DescribeCommand contains data input from user for the describe sub-command
+type DescribeCommand struct {
+	cmd *cobra.Command
+
+	name   string
+	output printer.Flags
+}
+
+const describeLongDesc = `
+Shows the BuildRun in one view: its conditions, start and completion times, the source revision
+resolved by the build, the resulting image digest, and the state of each build strategy step with
+its exit code, taken from the build pod. With "-o yaml" the full BuildRun object is printed instead,
+for example:
+
+	$ shp buildrun describe my-app-xyz12 -o yaml
+`
+
+// describeTimeFormat format of the timestamps shown by describe.
+const describeTimeFormat = time.RFC3339
+
+func describeCmd() runner.SubCommand {
+	c := &DescribeCommand{
+		cmd: &cobra.Command{
+			Use:   "describe <name> [flags]",
+			Short: "Show the details of a BuildRun, including the state of each step",
+			Long:  describeLongDesc,
+			Args:  cobra.ExactArgs(1),
+		},
+	}
+	c.output.AddFlags(c.cmd.Flags())
+	return c
+}
+
+// Cmd returns cobra command object of the describe sub-command
+func (c *DescribeCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in the BuildRun name
+func (c *DescribeCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+	return nil
+}
+
+// Validate checks the output format
+func (c *DescribeCommand) Validate() error {
+	return c.output.Validate()
+}
+
+// stepState state of a build strategy step, taken from its container status.
+type stepState struct {
+	name     string
+	state    string
+	exitCode string
+	reason   string
+	started  time.Time
+	finished time.Time
+}
+
+// stepStatesOf returns the state of the build strategy steps of the pod, in execution order.
+func stepStatesOf(pod *corev1.Pod) []stepState {
+	steps := tail.StepsOf(pod)
+	statuses := map[string]corev1.ContainerStatus{}
+	for _, status := range pod.Status.ContainerStatuses {
+		statuses[status.Name] = status
+	}
+
+	states := make([]stepState, len(steps))
+	for container, step := range steps {
+		s := stepState{name: step.Name, state: "Waiting"}
+		status := statuses[container]
+		switch {
+		case status.State.Terminated != nil:
+			terminated := status.State.Terminated
+			s.state = "Terminated"
+			s.exitCode = strconv.Itoa(int(terminated.ExitCode))
+			s.reason = terminated.Reason
+			s.started = terminated.StartedAt.Time
+			s.finished = terminated.FinishedAt.Time
+		case status.State.Running != nil:
+			s.state = "Running"
+			s.started = status.State.Running.StartedAt.Time
+		case status.State.Waiting != nil:
+			s.reason = status.State.Waiting.Reason
+		}
+		states[step.Index-1] = s
+	}
+	return states
+}
+
+// buildPodOf returns the most recent pod created for the BuildRun, nil when there is none.
+func buildPodOf(pods []corev1.Pod) *corev1.Pod {
+	if len(pods) == 0 {
+		return nil
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.After(pods[j].CreationTimestamp.Time)
+	})
+	return &pods[0]
+}
+
+// Run prints the BuildRun details
+func (c *DescribeCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	shpClientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	ctx := c.cmd.Context()
+	br, err := shpClientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Get(ctx, c.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if !c.output.Table() {
+		p, err := c.output.ToPrinter(false)
+		if err != nil {
+			return err
+		}
+		return p.PrintObj(br, ioStreams.Out)
+	}
+
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	pods, err := clientset.CoreV1().Pods(params.Namespace()).List(ctx, reactor.BuildRunPodListOptions(c.name))
+	if err != nil {
+		return err
+	}
+	return describe(ioStreams.Out, br, buildPodOf(pods.Items))
+}
+
+// describe renders the BuildRun details, the steps section is based on the build pod, when any.
+func describe(out io.Writer, br *buildv1alpha1.BuildRun, pod *corev1.Pod) error {
+	writer := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(writer, "%s:\t%s\n", name, value)
+		}
+	}
+
+	field("Name", styles.Bold(br.Name))
+	field("Namespace", br.Namespace)
+	build := br.Spec.BuildName()
+	if build == "" {
+		build = "(embedded)"
+	}
+	field("Build", build)
+	if spec := br.Status.BuildSpec; spec != nil {
+		kind := string(buildv1alpha1.NamespacedBuildStrategyKind)
+		if spec.Strategy.Kind != nil {
+			kind = string(*spec.Strategy.Kind)
+		}
+		field("Strategy", fmt.Sprintf("%s/%s", kind, spec.Strategy.Name))
+	}
+	if c := br.Status.GetCondition(buildv1alpha1.Succeeded); c != nil {
+		field("Status", styles.Condition(c.Status, c.Reason))
+	} else {
+		field("Status", styles.Faint("Pending"))
+	}
+	field("Created", timestamp(br.CreationTimestamp.Time))
+	if br.Status.StartTime != nil {
+		field("Started", timestamp(br.Status.StartTime.Time))
+	}
+	if br.Status.CompletionTime != nil {
+		field("Completed", timestamp(br.Status.CompletionTime.Time))
+		if br.Status.StartTime != nil {
+			field("Duration", duration.HumanDuration(br.Status.CompletionTime.Sub(br.Status.StartTime.Time)))
+		}
+	}
+	if pod != nil {
+		field("Pod", pod.Name)
+	}
+
+	if spec := br.Status.BuildSpec; spec != nil {
+		if spec.Source.URL != nil {
+			field("Source", *spec.Source.URL)
+		}
+		if spec.Source.Revision != nil {
+			field("Revision", *spec.Source.Revision)
+		}
+		if spec.Source.ContextDir != nil {
+			field("Context Dir", *spec.Source.ContextDir)
+		}
+	}
+	results := resultsOf(br)
+	for _, source := range results.Sources {
+		commit := source.CommitSha
+		if source.CommitAuthor != "" {
+			commit = fmt.Sprintf("%s by %s", commit, source.CommitAuthor)
+		}
+		if source.BranchName != "" {
+			commit = fmt.Sprintf("%s on %s", commit, source.BranchName)
+		}
+		field("Commit", strings.TrimSpace(commit))
+		field("Bundle Digest", source.BundleDigest)
+	}
+	field("Image", results.Image)
+	if results.ImageSize > 0 {
+		field("Image Size", strconv.FormatInt(results.ImageSize, 10))
+	}
+	if details := br.Status.FailureDetails; details != nil {
+		field("Failure", details.Message)
+		if location := details.Location; location != nil {
+			field("Failed At", fmt.Sprintf("%s/%s", location.Pod, location.Container))
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if len(br.Status.Conditions) > 0 {
+		fmt.Fprintln(out, "\nConditions:")
+		writer = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "  TYPE\tSTATUS\tREASON\tLAST TRANSITION\tMESSAGE")
+		for _, condition := range br.Status.Conditions {
+			fmt.Fprintf(writer, "  %s\t%s\t%s\t%s\t%s\n",
+				condition.Type,
+				styles.Condition(condition.Status, string(condition.Status)),
+				condition.Reason,
+				timestamp(condition.LastTransitionTime.Time),
+				condition.Message,
+			)
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(out, "\nSteps:")
+	if pod == nil {
+		fmt.Fprintln(out, "  No build pod found, it may not have been created yet or has been removed")
+		return nil
+	}
+	writer = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "  STEP\tSTATE\tEXIT CODE\tREASON\tSTARTED\tDURATION")
+	for _, s := range stepStatesOf(pod) {
+		state := s.state
+		switch {
+		case s.exitCode == "0":
+			state = styles.Success(state)
+		case s.exitCode != "":
+			state = styles.Failure(state)
+		case s.state == "Running":
+			state = styles.Warning(state)
+		default:
+			state = styles.Faint(state)
+		}
+		elapsed := ""
+		if !s.started.IsZero() && !s.finished.IsZero() {
+			elapsed = duration.HumanDuration(s.finished.Sub(s.started))
+		}
+		fmt.Fprintf(writer, "  %s\t%s\t%s\t%s\t%s\t%s\n",
+			s.name, state, s.exitCode, s.reason, timestamp(s.started), elapsed)
+	}
+	return writer.Flush()
+}
+
+// timestamp formats the informed time, empty when zero.
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(describeTimeFormat)
+}
@@ -0,0 +1,114 @@
+package buildrun
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestDescribeCommand(t *testing.T) {
+	g := o.NewWithT(t)
+
+	start := metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(90 * time.Second))
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	url := "https://github.com/org/app"
+	br := &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"},
+		Spec:       buildv1alpha1.BuildRunSpec{BuildRef: &buildv1alpha1.BuildRef{Name: "app"}},
+		Status: buildv1alpha1.BuildRunStatus{
+			StartTime:      &start,
+			CompletionTime: &end,
+			BuildSpec: &buildv1alpha1.BuildSpec{
+				Source:   buildv1alpha1.Source{URL: &url},
+				Strategy: buildv1alpha1.Strategy{Name: "buildah"},
+				Output:   buildv1alpha1.Image{Image: "ghcr.io/org/app:latest"},
+			},
+			Output: &buildv1alpha1.Output{Digest: digest},
+			Sources: []buildv1alpha1.SourceResult{{
+				Name: "default",
+				Git:  &buildv1alpha1.GitSourceResult{CommitSha: "abc123", BranchName: "main"},
+			}},
+			Conditions: buildv1alpha1.Conditions{{
+				Type:               buildv1alpha1.Succeeded,
+				Status:             corev1.ConditionFalse,
+				Reason:             "Failed",
+				Message:            "step push failed",
+				LastTransitionTime: end,
+			}},
+		},
+	}
+	pending := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pending"}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "br-pod",
+			Labels:    map[string]string{buildv1alpha1.LabelBuildRun: "br"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "step-source-default"},
+			{Name: "step-build"},
+			{Name: "step-push"},
+		}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "step-push", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 1, Reason: "Error", StartedAt: start, FinishedAt: end,
+			}}},
+			{Name: "step-source-default", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 0, Reason: "Completed", StartedAt: start, FinishedAt: start,
+			}}},
+			{Name: "step-build", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: start}}},
+		}},
+	}
+	p := params.NewParamsForTest(
+		fake.NewSimpleClientset(pod),
+		shpfake.NewSimpleClientset(br, pending),
+		nil,
+		metav1.NamespaceDefault,
+		nil,
+		nil,
+	)
+
+	run := func(name string, args ...string) string {
+		cmd := describeCmd().(*DescribeCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		g.Expect(cmd.Complete(p, nil, []string{name})).To(o.Succeed())
+		g.Expect(cmd.Validate()).To(o.Succeed())
+
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+		return out.String()
+	}
+
+	out := run("br")
+	g.Expect(out).To(o.MatchRegexp(`Build:\s+app\n`))
+	g.Expect(out).To(o.MatchRegexp(`Strategy:\s+BuildStrategy/buildah\n`))
+	g.Expect(out).To(o.MatchRegexp(`Duration:\s+90s\n`))
+	g.Expect(out).To(o.MatchRegexp(`Commit:\s+abc123 on main\n`))
+	g.Expect(out).To(o.MatchRegexp(`Image:\s+ghcr.io/org/app@` + digest + `\n`))
+	g.Expect(out).To(o.MatchRegexp(`Succeeded\s+False\s+Failed\s+2024-01-02T03:05:30Z\s+step push failed`))
+	g.Expect(out).To(o.MatchRegexp(`source-default\s+Terminated\s+0\s+Completed`))
+	g.Expect(out).To(o.MatchRegexp(`build\s+Running\s+2024-01-02T03:04:00Z\s*\n`))
+	g.Expect(out).To(o.MatchRegexp(`push\s+Terminated\s+1\s+Error\s+2024-01-02T03:04:00Z\s+90s`))
+
+	out = run("pending")
+	g.Expect(out).To(o.MatchRegexp(`Build:\s+\(embedded\)\n`))
+	g.Expect(out).To(o.ContainSubstring("No build pod found"))
+
+	out = run("br", "-o", "yaml")
+	g.Expect(out).To(o.ContainSubstring("kind: BuildRun\n"))
+	g.Expect(out).To(o.ContainSubstring("digest: " + digest))
+}
@@ -0,0 +1,252 @@
+package buildrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+)
+
+// EventsCommand contains data input from user for the events sub-command
+type EventsCommand struct {
+	cmd *cobra.Command
+
+	name     string
+	follow   bool
+	noHeader bool
+
+	now func() time.Time
+}
+
+const eventsLongDesc = `
+Shows the Kubernetes Events related to the BuildRun, its TaskRun and build pods, in chronological
+order. Events surface issues the BuildRun status may not explain, like scheduling failures, image
+pull errors and OOM kills.
+
+With "--follow" new events are printed as they happen, until interrupted.
+`
+
+func eventsCmd() runner.SubCommand {
+	c := &EventsCommand{
+		cmd: &cobra.Command{
+			Use:   "events <name> [flags]",
+			Short: "Show the Kubernetes Events related to a BuildRun",
+			Long:  eventsLongDesc,
+			Args:  cobra.ExactArgs(1),
+		},
+		now: time.Now,
+	}
+	c.cmd.Flags().BoolVarP(&c.follow, "follow", "F", false, "Follow the events of the BuildRun until interrupted")
+	c.cmd.Flags().BoolVar(&c.noHeader, "no-header", false, "Do not show columns header in the output")
+	return c
+}
+
+// Cmd returns cobra command object of the events sub-command
+func (c *EventsCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in the BuildRun name
+func (c *EventsCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+	return nil
+}
+
+// Validate checks user input data
+func (c *EventsCommand) Validate() error {
+	return nil
+}
+
+// involvedObjects keeps track of the objects a BuildRun is made of, the TaskRun and pods are only
+// known after the build controller creates them.
+type involvedObjects struct {
+	ctx          context.Context
+	clientset    kubernetes.Interface
+	shpClientset buildclientset.Interface
+	ns           string
+	buildRun     string
+
+	taskRun string
+	pods    map[string]bool
+}
+
+// refresh looks up the BuildRun TaskRun and the pods labeled for the BuildRun.
+func (i *involvedObjects) refresh() error {
+	br, err := i.shpClientset.ShipwrightV1alpha1().BuildRuns(i.ns).Get(i.ctx, i.buildRun, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if br.Status.LatestTaskRunRef != nil {
+		i.taskRun = *br.Status.LatestTaskRunRef
+	}
+
+	pods, err := i.clientset.CoreV1().Pods(i.ns).List(i.ctx, reactor.BuildRunPodListOptions(i.buildRun))
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		i.pods[pod.GetName()] = true
+	}
+	return nil
+}
+
+// matches tells whether the event is about one of the involved objects. Objects named after the
+// BuildRun which aren't known yet trigger a refresh, since the TaskRun and pod names are generated
+// from the BuildRun name.
+func (i *involvedObjects) matches(event *corev1.Event) (bool, error) {
+	obj := event.InvolvedObject
+	if obj.Namespace != "" && obj.Namespace != i.ns {
+		return false, nil
+	}
+	known := func() bool {
+		switch obj.Kind {
+		case "BuildRun":
+			return obj.Name == i.buildRun
+		case "TaskRun":
+			return obj.Name == i.taskRun
+		case "Pod":
+			return i.pods[obj.Name]
+		}
+		return false
+	}
+	if known() {
+		return true, nil
+	}
+	if obj.Kind == "BuildRun" || !strings.HasPrefix(obj.Name, i.buildRun+"-") {
+		return false, nil
+	}
+	if err := i.refresh(); err != nil {
+		return false, err
+	}
+	return known(), nil
+}
+
+// eventTime returns the most recent timestamp recorded on the event.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// printEvent writes the event as a table row, warnings are highlighted.
+func (c *EventsCommand) printEvent(w io.Writer, event *corev1.Event) {
+	eventType := styles.Faint(event.Type)
+	if event.Type == corev1.EventTypeWarning {
+		eventType = styles.Warning(event.Type)
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		duration.ShortHumanDuration(c.now().Sub(eventTime(event))),
+		eventType,
+		event.Reason,
+		fmt.Sprintf("%s/%s", strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name),
+		strings.TrimSpace(event.Message),
+	)
+}
+
+// Run prints the events of the BuildRun, and when following, keeps printing new events until
+// interrupted.
+func (c *EventsCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := p.ClientSet()
+	if err != nil {
+		return err
+	}
+	shpClientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	ctx := c.cmd.Context()
+	involved := &involvedObjects{
+		ctx:          ctx,
+		clientset:    clientset,
+		shpClientset: shpClientset,
+		ns:           p.Namespace(),
+		buildRun:     c.name,
+		pods:         map[string]bool{},
+	}
+	if err = involved.refresh(); err != nil {
+		return err
+	}
+
+	list, err := clientset.CoreV1().Events(p.Namespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	events := []*corev1.Event{}
+	for i := range list.Items {
+		ok, err := involved.matches(&list.Items[i])
+		if err != nil {
+			return err
+		}
+		if ok {
+			events = append(events, &list.Items[i])
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+
+	writer := tabwriter.NewWriter(ioStreams.Out, 0, 8, 2, '\t', 0)
+	if len(events) == 0 && !c.follow {
+		fmt.Fprintf(ioStreams.Out, "No events found for BuildRun %q\n", c.name)
+		return nil
+	}
+	if !c.noHeader {
+		fmt.Fprintln(writer, "LAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE")
+	}
+	seen := map[types.UID]string{}
+	for _, event := range events {
+		seen[event.GetUID()] = event.GetResourceVersion()
+		c.printEvent(writer, event)
+	}
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+	if !c.follow {
+		return nil
+	}
+
+	ew := reactor.NewEventWatcher(ctx, clientset, p.Namespace())
+	ew.WithFilterFn(func(event *corev1.Event) bool {
+		// the initial listing may already contain the event in the same version
+		if rv, ok := seen[event.GetUID()]; ok && rv == event.GetResourceVersion() {
+			return false
+		}
+		ok, err := involved.matches(event)
+		if err != nil {
+			fmt.Fprintf(ioStreams.ErrOut, "unable to inspect BuildRun %q: %s\n", c.name, err)
+		}
+		return ok
+	}).WithOnEventFn(func(event *corev1.Event) error {
+		seen[event.GetUID()] = event.GetResourceVersion()
+		c.printEvent(writer, event)
+		return writer.Flush()
+	})
+	return ew.Start(metav1.ListOptions{ResourceVersion: list.GetResourceVersion()})
+}
@@ -0,0 +1,147 @@
+package buildrun
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// syncBuffer buffer safe for concurrent writes and reads.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.buf.String()
+}
+
+func newEvent(name, kind, object, eventType, reason string, lastSeen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      name,
+			UID:       types.UID(name),
+		},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: object},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        reason + " message",
+		LastTimestamp:  metav1.Time{Time: lastSeen},
+	}
+}
+
+func TestEventsCommand(t *testing.T) {
+	g := o.NewWithT(t)
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	taskRun := "br-abcde"
+	br := &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"},
+		Status:     buildv1alpha1.BuildRunStatus{LatestTaskRunRef: &taskRun},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: metav1.NamespaceDefault,
+		Name:      "br-abcde-pod",
+		Labels:    map[string]string{buildv1alpha1.LabelBuildRun: "br"},
+	}}
+
+	clientset := fake.NewSimpleClientset(
+		pod,
+		newEvent("pulled", "Pod", "br-abcde-pod", corev1.EventTypeNormal, "Pulled", now.Add(-time.Minute)),
+		newEvent("scheduled", "Pod", "br-abcde-pod", corev1.EventTypeWarning, "FailedScheduling", now.Add(-3*time.Minute)),
+		newEvent("taskrun", "TaskRun", "br-abcde", corev1.EventTypeNormal, "Started", now.Add(-2*time.Minute)),
+		newEvent("other", "Pod", "br-2-abcde-pod", corev1.EventTypeWarning, "OOMKilled", now),
+	)
+	p := params.NewParamsForTest(clientset, shpfake.NewSimpleClientset(br), nil, metav1.NamespaceDefault, nil, nil)
+
+	t.Run("list", func(t *testing.T) {
+		cmd := eventsCmd().(*EventsCommand)
+		cmd.now = func() time.Time { return now }
+		cmd.Cmd().SetContext(context.TODO())
+		g.Expect(cmd.Complete(p, nil, []string{"br"})).To(o.Succeed())
+
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		g.Expect(lines).To(o.HaveLen(4))
+		g.Expect(lines[0]).To(o.HavePrefix("LAST SEEN"))
+		g.Expect(lines[1]).To(o.ContainSubstring("FailedScheduling"))
+		g.Expect(lines[1]).To(o.ContainSubstring("pod/br-abcde-pod"))
+		g.Expect(lines[2]).To(o.ContainSubstring("taskrun/br-abcde"))
+		g.Expect(lines[3]).To(o.ContainSubstring("Pulled"))
+		g.Expect(out.String()).NotTo(o.ContainSubstring("OOMKilled"))
+	})
+
+	t.Run("no events", func(t *testing.T) {
+		empty := params.NewParamsForTest(fake.NewSimpleClientset(), shpfake.NewSimpleClientset(br), nil, metav1.NamespaceDefault, nil, nil)
+		cmd := eventsCmd().(*EventsCommand)
+		cmd.Cmd().SetContext(context.TODO())
+		g.Expect(cmd.Complete(empty, nil, []string{"br"})).To(o.Succeed())
+
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		g.Expect(cmd.Run(empty, &ioStreams)).To(o.Succeed())
+		g.Expect(out.String()).To(o.Equal("No events found for BuildRun \"br\"\n"))
+	})
+
+	t.Run("follow", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		cmd := eventsCmd().(*EventsCommand)
+		cmd.now = func() time.Time { return now }
+		cmd.Cmd().SetContext(ctx)
+		g.Expect(cmd.Cmd().ParseFlags([]string{"--follow", "--no-header"})).To(o.Succeed())
+		g.Expect(cmd.Complete(p, nil, []string{"br"})).To(o.Succeed())
+
+		out := &syncBuffer{}
+		ioStreams := genericclioptions.IOStreams{Out: out, ErrOut: out}
+		done := make(chan error)
+		go func() {
+			done <- cmd.Run(p, &ioStreams)
+		}()
+
+		g.Eventually(out.String).Should(o.ContainSubstring("Pulled"))
+		g.Eventually(func() bool {
+			for _, action := range clientset.Actions() {
+				if action.GetVerb() == "watch" && action.GetResource().Resource == "events" {
+					return true
+				}
+			}
+			return false
+		}).Should(o.BeTrue())
+		_, err := clientset.CoreV1().Events(metav1.NamespaceDefault).Create(ctx,
+			newEvent("oom", "Pod", "br-abcde-pod", corev1.EventTypeWarning, "OOMKilled", now), metav1.CreateOptions{})
+		g.Expect(err).NotTo(o.HaveOccurred())
+		g.Eventually(out.String).Should(o.ContainSubstring("OOMKilled"))
+
+		cancel()
+		g.Eventually(done).Should(o.Receive(o.BeNil()))
+		g.Expect(out.String()).NotTo(o.ContainSubstring("LAST SEEN"))
+	})
+}
@@ -0,0 +1,173 @@
+package buildrun
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/debug"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+)
+
+// stepContainerPrefix the prefix of the build pod containers running the build strategy steps.
+const stepContainerPrefix = "step-"
+
+// execer runs commands on the build pod containers, replaced during testing.
+type execer interface {
+	Exec(namespace, pod, container string, command []string, stdin, tty bool, ioStreams genericclioptions.IOStreams) error
+}
+
+// ExecCommand contains data input from user for the exec sub-command
+type ExecCommand struct {
+	cmd *cobra.Command
+
+	name    string
+	command []string // command run on the step container
+	step    string   // step name, the current step when empty
+	stdin   bool     // pass the standard input to the command
+	tty     bool     // allocate a terminal for the command
+
+	executor execer
+}
+
+const execLongDesc = `
+Runs a command on a step container of the running BuildRun pod, by default an interactive shell on
+the current step, the first one not finished yet. Strategies holding the steps on debugging
+breakpoints, or simply sleeping on failures, are inspected this way. For example:
+
+	$ shp buildrun exec my-app-xyz12 -it
+	$ shp buildrun exec my-app-xyz12 --step build-and-push -- ls -la /workspace/source
+
+The step container image must provide the command, "sh" by default.
+`
+
+// defaultExecCommand command run when none is informed.
+var defaultExecCommand = []string{"sh"}
+
+func execCmd() runner.SubCommand {
+	c := &ExecCommand{
+		cmd: &cobra.Command{
+			Use:   "exec <name> [flags] [-- <command> [args...]]",
+			Short: "Run a command on a step container of the running BuildRun",
+			Long:  execLongDesc,
+			Args:  cobra.MinimumNArgs(1),
+		},
+	}
+
+	c.cmd.Flags().StringVar(&c.step, "step", "", "build strategy step to run the command on, the current step by default")
+	c.cmd.Flags().BoolVarP(&c.stdin, "stdin", "i", false, "pass the standard input to the command")
+	c.cmd.Flags().BoolVarP(&c.tty, "tty", "t", false, "allocate a terminal for the command, requires --stdin")
+
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *ExecCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *ExecCommand) Complete(params *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+	c.command = args[1:]
+	if len(c.command) == 0 {
+		c.command = defaultExecCommand
+	}
+	if dash := c.cmd.ArgsLenAtDash(); len(args) > 1 && dash != 1 {
+		return fmt.Errorf("the command must be informed after \"--\", e.g. \"shp buildrun exec %s -- ls\"", c.name)
+	}
+
+	if c.executor != nil {
+		return nil
+	}
+	restConfig, err := params.RESTConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	c.executor = debug.NewExecutor(restConfig, clientset)
+	return nil
+}
+
+// Validate validates data input by user
+func (c *ExecCommand) Validate() error {
+	if c.tty && !c.stdin {
+		return fmt.Errorf("--tty requires --stdin")
+	}
+	return nil
+}
+
+// Run executes the command on the build pod step container
+func (c *ExecCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	shpClientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+
+	ctx := c.cmd.Context()
+	br, err := shpClientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Get(ctx, c.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if br.IsDone() {
+		return fmt.Errorf("BuildRun %q has already finished, there is no step to run the command on", c.name)
+	}
+	pods, err := clientset.CoreV1().Pods(params.Namespace()).List(ctx, reactor.BuildRunPodListOptions(c.name))
+	if err != nil {
+		return err
+	}
+	pod := buildPodOf(pods.Items)
+	if pod == nil || pod.Status.Phase != corev1.PodRunning {
+		return fmt.Errorf("BuildRun %q pod is not running yet", c.name)
+	}
+
+	container, err := c.stepContainer(pod)
+	if err != nil {
+		return err
+	}
+	return c.executor.Exec(params.Namespace(), pod.GetName(), container, c.command, c.stdin, c.tty, *ioStreams)
+}
+
+// stepContainer returns the container of the informed step, or the current step, the first one not
+// terminated, since all step containers are started at once and wait for the previous steps.
+func (c *ExecCommand) stepContainer(pod *corev1.Pod) (string, error) {
+	terminated := map[string]bool{}
+	for _, s := range pod.Status.ContainerStatuses {
+		terminated[s.Name] = s.State.Terminated != nil
+	}
+
+	steps := []string{}
+	for _, container := range pod.Spec.Containers {
+		if !strings.HasPrefix(container.Name, stepContainerPrefix) {
+			continue
+		}
+		step := strings.TrimPrefix(container.Name, stepContainerPrefix)
+		steps = append(steps, step)
+		switch {
+		case c.step == "" && !terminated[container.Name]:
+			return container.Name, nil
+		case c.step == step && terminated[container.Name]:
+			return "", fmt.Errorf("step %q has already finished", step)
+		case c.step == step:
+			return container.Name, nil
+		}
+	}
+	if c.step == "" {
+		return "", fmt.Errorf("all the steps of pod %q have finished", pod.GetName())
+	}
+	return "", fmt.Errorf("step %q not found, expected one of: %s", c.step, strings.Join(steps, ", "))
+}
@@ -0,0 +1,145 @@
+package buildrun
+
+import (
+	"context"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// fakeExecer records the command executed.
+type fakeExecer struct {
+	pod       string
+	container string
+	command   []string
+	tty       bool
+}
+
+func (f *fakeExecer) Exec(_, pod, container string, command []string, _, tty bool, _ genericclioptions.IOStreams) error {
+	f.pod, f.container, f.command, f.tty = pod, container, command, tty
+	return nil
+}
+
+func TestExecCommand(t *testing.T) {
+	br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"}}
+	terminated := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	pod := func(phase corev1.PodPhase, states ...corev1.ContainerState) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      "br-pod",
+				Labels:    map[string]string{buildv1alpha1.LabelBuildRun: "br"},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "step-source-default"},
+				{Name: "step-build"},
+				{Name: "step-push"},
+			}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+		for i, state := range states {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+				Name:  pod.Spec.Containers[i].Name,
+				State: state,
+			})
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		pod       *corev1.Pod
+		container string
+		command   []string
+		err       string
+	}{{
+		name:      "current step",
+		args:      []string{"br"},
+		pod:       pod(corev1.PodRunning, terminated, running, running),
+		container: "step-build",
+		command:   []string{"sh"},
+	}, {
+		name:      "informed step and command",
+		args:      []string{"br", "--step", "push", "--", "ls", "-la"},
+		pod:       pod(corev1.PodRunning, terminated, running, running),
+		container: "step-push",
+		command:   []string{"ls", "-la"},
+	}, {
+		name: "finished step",
+		args: []string{"br", "--step", "source-default"},
+		pod:  pod(corev1.PodRunning, terminated, running, running),
+		err:  `step "source-default" has already finished`,
+	}, {
+		name: "unknown step",
+		args: []string{"br", "--step", "test"},
+		pod:  pod(corev1.PodRunning, terminated, running, running),
+		err:  `step "test" not found, expected one of: source-default, build, push`,
+	}, {
+		name: "pending pod",
+		args: []string{"br"},
+		pod:  pod(corev1.PodPending),
+		err:  `BuildRun "br" pod is not running yet`,
+	}, {
+		name: "no pod yet",
+		args: []string{"br"},
+		err:  `BuildRun "br" pod is not running yet`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			clientset := fake.NewSimpleClientset()
+			if tt.pod != nil {
+				clientset = fake.NewSimpleClientset(tt.pod)
+			}
+			p := params.NewParamsForTest(clientset, shpfake.NewSimpleClientset(br), nil, metav1.NamespaceDefault, nil, nil)
+
+			executor := &fakeExecer{}
+			cmd := execCmd().(*ExecCommand)
+			cmd.cmd.SetContext(context.TODO())
+			cmd.executor = executor
+			g.Expect(cmd.cmd.Flags().Parse(tt.args)).To(o.Succeed())
+			g.Expect(cmd.Complete(p, nil, cmd.cmd.Flags().Args())).To(o.Succeed())
+			g.Expect(cmd.Validate()).To(o.Succeed())
+
+			ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+			err := cmd.Run(p, &ioStreams)
+			if tt.err != "" {
+				g.Expect(err).To(o.MatchError(tt.err))
+				return
+			}
+			g.Expect(err).To(o.Succeed())
+			g.Expect(executor.pod).To(o.Equal("br-pod"))
+			g.Expect(executor.container).To(o.Equal(tt.container))
+			g.Expect(executor.command).To(o.Equal(tt.command))
+		})
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		g := o.NewWithT(t)
+		p := params.NewParamsForTest(fake.NewSimpleClientset(), shpfake.NewSimpleClientset(br), nil, metav1.NamespaceDefault, nil, nil)
+
+		cmd := execCmd().(*ExecCommand)
+		cmd.executor = &fakeExecer{}
+		g.Expect(cmd.cmd.Flags().Parse([]string{"br", "ls"})).To(o.Succeed())
+		g.Expect(cmd.Complete(p, nil, cmd.cmd.Flags().Args())).To(o.MatchError(
+			`the command must be informed after "--", e.g. "shp buildrun exec br -- ls"`))
+
+		cmd = execCmd().(*ExecCommand)
+		g.Expect(cmd.cmd.Flags().Parse([]string{"br", "-t"})).To(o.Succeed())
+		g.Expect(cmd.Validate()).To(o.MatchError("--tty requires --stdin"))
+	})
+}
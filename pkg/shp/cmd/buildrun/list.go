@@ -2,7 +2,8 @@ package buildrun
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"sort"
 	"text/tabwriter"
 	"time"
 
@@ -16,25 +17,49 @@ import (
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/printer"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+	"github.com/shipwright-io/cli/pkg/shp/util"
 )
 
 // ListCommand contains data input from user for list sub-command
 type ListCommand struct {
 	cmd *cobra.Command
 
-	noHeader bool
+	noHeader      bool
+	groupBy       string
+	output        printer.Flags
+	limit         int64
+	continueToken string
+	chunkSize     int64
 }
 
+// groupByBuild groups the BuildRuns by their Build.
+const groupByBuild = "build"
+
+// noBuild groups the BuildRuns with an embedded Build specification.
+const noBuild = "<embedded>"
+
 func listCmd() runner.SubCommand {
 	listCmd := &ListCommand{
 		cmd: &cobra.Command{
 			Use:   "list [flags]",
 			Short: "List Builds",
+			Long: `List the BuildRuns of the namespace.
+
+The BuildRuns are requested in pages of --chunk-size items, and the table rows are shown as each page
+arrives. The --limit flag stops the listing after the informed amount of BuildRuns, showing the token
+to list the remaining ones with --continue.`,
 		},
+		chunkSize: util.DefaultChunkSize,
 	}
 
 	listCmd.cmd.Flags().BoolVar(&listCmd.noHeader, "no-header", false, "Do not show columns header in list output")
+	listCmd.cmd.Flags().StringVar(&listCmd.groupBy, "group-by", "", "Group the BuildRuns, only \"build\" is supported, showing each Build with its runs")
+	listCmd.output.AddFlags(listCmd.cmd.Flags())
+	flags.PaginationFlags(listCmd.cmd.Flags(), &listCmd.limit, &listCmd.continueToken, &listCmd.chunkSize)
 
 	return listCmd
 }
@@ -51,15 +76,24 @@ func (c *ListCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams,
 
 // Validate validates data input by user
 func (c *ListCommand) Validate() error {
-	return nil
+	if c.groupBy != "" && c.groupBy != groupByBuild {
+		return fmt.Errorf("unsupported --group-by %q, only %q is supported", c.groupBy, groupByBuild)
+	}
+	if c.groupBy != "" && !c.output.Table() {
+		return fmt.Errorf("--group-by is only supported by the table output")
+	}
+	if c.limit < 0 {
+		return fmt.Errorf("--%s must not be negative", flags.LimitFlag)
+	}
+	if c.chunkSize < 0 {
+		return fmt.Errorf("--%s must not be negative", flags.ChunkSizeFlag)
+	}
+	return c.output.Validate()
 }
 
 // Run executes list sub-command logic
 func (c *ListCommand) Run(params *params.Params, io *genericclioptions.IOStreams) error {
-	// TODO: Support multiple output formats here, not only tabwriter
-	//       find out more in kubectl libraries and use them
-
-	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, '\t', 0)
+	writer := tabwriter.NewWriter(io.Out, 0, 8, 2, '\t', 0)
 	columnNames := "NAME\tSTATUS\tAGE"
 	columnTemplate := "%s\t%s\t%s\n"
 
@@ -81,32 +115,145 @@ func (c *ListCommand) Run(params *params.Params, io *genericclioptions.IOStreams
 		return err
 	}
 
-	var brs *buildv1alpha1.BuildRunList
-	if brs, err = clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).List(c.cmd.Context(), metav1.ListOptions{}); err != nil {
+	// the table and names are streamed page by page, the other formats and the grouping need all
+	// BuildRuns at once
+	stream := params.Quiet() || c.output.Output == "name" || (c.output.Table() && c.groupBy == "")
+	brs := &buildv1alpha1.BuildRunList{}
+	headerShown := false
+	page := func(list *buildv1alpha1.BuildRunList) error {
+		if !stream {
+			brs.Items = append(brs.Items, list.Items...)
+			return nil
+		}
+		switch {
+		case params.Quiet():
+			for _, br := range list.Items {
+				fmt.Fprintln(io.Out, br.Name)
+			}
+			return nil
+		case !c.output.Table():
+			return c.output.PrintList(list, c.noHeader, io.Out)
+		}
+		if len(list.Items) == 0 {
+			return nil
+		}
+		if !c.noHeader && !headerShown {
+			fmt.Fprintln(writer, columnNames)
+			headerShown = true
+		}
+		for _, br := range list.Items {
+			age := duration.ShortHumanDuration(time.Since((br.ObjectMeta.CreationTimestamp).Time))
+			fmt.Fprintf(writer, columnTemplate, styles.Bold(br.Name), statusOf(&br), age)
+		}
+		return writer.Flush()
+	}
+
+	listed := 0
+	continueToken, err := util.ListBuildRunPages(
+		c.cmd.Context(),
+		clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).List,
+		metav1.ListOptions{Continue: c.continueToken},
+		c.limit,
+		c.chunkSize,
+		func(list *buildv1alpha1.BuildRunList) error {
+			listed += len(list.Items)
+			return page(list)
+		},
+	)
+	if err != nil {
 		return err
 	}
-	if len(brs.Items) == 0 {
+	defer c.showContinueToken(io, continueToken)
+
+	switch {
+	case params.Quiet() || c.output.Output == "name":
+		return nil
+	case !c.output.Table():
+		brs.Continue = continueToken
+		return c.output.PrintList(brs, c.noHeader, io.Out)
+	case listed == 0:
 		fmt.Fprintf(io.Out, "No buildruns found in namespace '%s'. Please create a buildrun or verify the namespace.\n", params.Namespace())
 		return nil
+	case c.groupBy == groupByBuild:
+		c.renderGroupedByBuild(writer, brs.Items)
+		return writer.Flush()
+	}
+	return nil
+}
+
+// showContinueToken tells how to list the remaining BuildRuns, when the listing stopped on the limit.
+func (c *ListCommand) showContinueToken(io *genericclioptions.IOStreams, continueToken string) {
+	if continueToken == "" {
+		return
 	}
+	fmt.Fprintf(io.ErrOut, "There are more BuildRuns, list them with: --%s %s\n", flags.ContinueFlag, continueToken)
+}
+
+// statusOf describes the BuildRun "Succeeded" condition reason, decorated by its status.
+func statusOf(br *buildv1alpha1.BuildRun) string {
+	for _, condition := range br.Status.Conditions {
+		if condition.Type == buildv1alpha1.Succeeded {
+			return styles.Condition(condition.Status, condition.Reason)
+		}
+	}
+	return styles.Faint(string(metav1.ConditionUnknown))
+}
+
+// durationOf describes how long the BuildRun took, or has been running for, empty when not started.
+func durationOf(br *buildv1alpha1.BuildRun) string {
+	if br.Status.StartTime == nil {
+		return ""
+	}
+	end := time.Now()
+	if br.Status.CompletionTime != nil {
+		end = br.Status.CompletionTime.Time
+	}
+	return duration.HumanDuration(end.Sub(br.Status.StartTime.Time))
+}
+
+// renderGroupedByBuild writes each Build, ordered by name, with the roll-up of its runs and the
+// last run status, followed by its runs from the most recent.
+func (c *ListCommand) renderGroupedByBuild(writer io.Writer, buildRuns []buildv1alpha1.BuildRun) {
+	groups := map[string][]buildv1alpha1.BuildRun{}
+	for _, br := range buildRuns {
+		build := buildNameOf(&br)
+		groups[build] = append(groups[build], br)
+	}
+	builds := make([]string, 0, len(groups))
+	for build := range groups {
+		builds = append(builds, build)
+	}
+	sort.Strings(builds)
 
 	if !c.noHeader {
-		fmt.Fprintln(writer, columnNames)
+		fmt.Fprintln(writer, "NAME\tRUNS\tSTATUS\tDURATION\tAGE")
 	}
+	for _, build := range builds {
+		runs := groups[build]
+		sort.SliceStable(runs, func(i, j int) bool {
+			return runs[j].CreationTimestamp.Before(&runs[i].CreationTimestamp)
+		})
 
-	for _, br := range brs.Items {
-		name := br.Name
-		status := string(metav1.ConditionUnknown)
-		for _, condition := range br.Status.Conditions {
-			if condition.Type == buildv1alpha1.Succeeded {
-				status = condition.Reason
-				break
+		succeeded, failed := 0, 0
+		for i := range runs {
+			switch util.PhaseOf(&runs[i]) {
+			case util.PhaseSucceeded:
+				succeeded++
+			case util.PhaseFailed:
+				failed++
 			}
 		}
-		age := duration.ShortHumanDuration(time.Since((br.ObjectMeta.CreationTimestamp).Time))
+		rollUp := fmt.Sprintf("%d (%d succeeded, %d failed)", len(runs), succeeded, failed)
+		if succeeded > 0 && failed > 0 {
+			rollUp = styles.Warning(rollUp)
+		}
 
-		fmt.Fprintf(writer, columnTemplate, name, status, age)
+		last := &runs[0]
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", styles.Bold(build), rollUp, statusOf(last), durationOf(last),
+			duration.ShortHumanDuration(time.Since(last.CreationTimestamp.Time)))
+		for i := range runs {
+			fmt.Fprintf(writer, "  %s\t\t%s\t%s\t%s\n", runs[i].Name, statusOf(&runs[i]), durationOf(&runs[i]),
+				duration.ShortHumanDuration(time.Since(runs[i].CreationTimestamp.Time)))
+		}
 	}
-
-	return writer.Flush()
 }
@@ -0,0 +1,133 @@
+package buildrun
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestListCommandGroupByBuild(t *testing.T) {
+	g := o.NewWithT(t)
+
+	now := time.Now()
+	newBuildRun := func(name, build string, age time.Duration, status corev1.ConditionStatus) *buildv1alpha1.BuildRun {
+		br := &buildv1alpha1.BuildRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         metav1.NamespaceDefault,
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Spec: buildv1alpha1.BuildRunSpec{BuildRef: &buildv1alpha1.BuildRef{Name: build}},
+			Status: buildv1alpha1.BuildRunStatus{
+				StartTime:      &metav1.Time{Time: now.Add(-age)},
+				CompletionTime: &metav1.Time{Time: now.Add(-age + time.Minute)},
+				Conditions: buildv1alpha1.Conditions{{
+					Type:   buildv1alpha1.Succeeded,
+					Status: status,
+					Reason: map[corev1.ConditionStatus]string{corev1.ConditionTrue: "Succeeded", corev1.ConditionFalse: "Failed"}[status],
+				}},
+			},
+		}
+		if build == "" {
+			br.Spec.BuildRef = nil
+		}
+		return br
+	}
+	objects := []runtime.Object{
+		newBuildRun("app-1", "app", 3*time.Hour, corev1.ConditionTrue),
+		newBuildRun("app-2", "app", 2*time.Hour, corev1.ConditionFalse),
+		newBuildRun("api-1", "api", time.Hour, corev1.ConditionTrue),
+		newBuildRun("inline", "", time.Hour, corev1.ConditionTrue),
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: metav1.NamespaceDefault}}
+	p := params.NewParamsForTest(fake.NewSimpleClientset(ns), shpfake.NewSimpleClientset(objects...), nil, metav1.NamespaceDefault, nil, nil)
+
+	cmd := listCmd().(*ListCommand)
+	cmd.cmd.SetContext(context.TODO())
+	g.Expect(cmd.cmd.ParseFlags([]string{"--group-by", "build"})).To(o.Succeed())
+	g.Expect(cmd.Validate()).To(o.Succeed())
+
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+	g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	g.Expect(lines).To(o.HaveLen(8))
+	g.Expect(strings.Fields(lines[0])).To(o.Equal([]string{"NAME", "RUNS", "STATUS", "DURATION", "AGE"}))
+	g.Expect(strings.Fields(lines[1])).To(o.Equal([]string{"<embedded>", "1", "(1", "succeeded,", "0", "failed)", "Succeeded", "60s", "1h"}))
+	g.Expect(lines[3]).To(o.HavePrefix("api\t"))
+	g.Expect(strings.Fields(lines[5])).To(o.Equal([]string{"app", "2", "(1", "succeeded,", "1", "failed)", "Failed", "60s", "2h"}))
+	g.Expect(lines[6]).To(o.HavePrefix("  app-2\t"))
+	g.Expect(lines[7]).To(o.HavePrefix("  app-1\t"))
+
+	invalid := listCmd().(*ListCommand)
+	g.Expect(invalid.cmd.ParseFlags([]string{"--group-by", "strategy"})).To(o.Succeed())
+	g.Expect(invalid.Validate()).To(o.MatchError(`unsupported --group-by "strategy", only "build" is supported`))
+}
+
+func TestListCommandQuiet(t *testing.T) {
+	g := o.NewWithT(t)
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: metav1.NamespaceDefault}}
+	p := params.NewParamsForTest(fake.NewSimpleClientset(ns), shpfake.NewSimpleClientset(
+		&buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "app-1"}},
+		&buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "app-2"}},
+	), nil, metav1.NamespaceDefault, nil, nil)
+	p.SetQuiet(true)
+
+	cmd := listCmd().(*ListCommand)
+	cmd.cmd.SetContext(context.TODO())
+
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+	g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+	g.Expect(out.String()).To(o.Equal("app-1\napp-2\n"))
+}
+
+func TestListCommandPagination(t *testing.T) {
+	g := o.NewWithT(t)
+
+	objects := []runtime.Object{}
+	for _, name := range []string{"br-1", "br-2", "br-3"} {
+		objects = append(objects, &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name}})
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: metav1.NamespaceDefault}}
+	p := params.NewParamsForTest(fake.NewSimpleClientset(ns), shpfake.NewSimpleClientset(objects...), nil, metav1.NamespaceDefault, nil, nil)
+
+	run := func(args ...string) (string, error) {
+		cmd := listCmd().(*ListCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		if err := cmd.Validate(); err != nil {
+			return "", err
+		}
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		err := cmd.Run(p, &ioStreams)
+		return out.String(), err
+	}
+
+	out, err := run("--chunk-size", "2", "--no-header")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(strings.Split(strings.TrimSpace(out), "\n")).To(o.HaveLen(3))
+
+	out, err = run("-o", "name", "--limit", "5")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).To(o.Equal("buildrun.shipwright.io/br-1\nbuildrun.shipwright.io/br-2\nbuildrun.shipwright.io/br-3\n"))
+
+	_, err = run("--limit", "-1")
+	g.Expect(err).To(o.MatchError("--limit must not be negative"))
+	_, err = run("--chunk-size", "-1")
+	g.Expect(err).To(o.MatchError("--chunk-size must not be negative"))
+}
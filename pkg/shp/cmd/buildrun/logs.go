@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -17,7 +19,11 @@ import (
 
 	"github.com/shipwright-io/cli/pkg/shp/cmd/follower"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/debug"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
 	"github.com/shipwright-io/cli/pkg/shp/util"
 )
 
@@ -27,20 +33,64 @@ type LogsCommand struct {
 
 	name string
 
-	follow   bool
-	follower *follower.Follower
+	follow     bool
+	fromStart  bool // follows the logs from the start, otherwise only the ones written from now on
+	follower   *follower.Follower
+	maxLogRate int64           // log bytes per second printed while following
+	heartbeat  time.Duration   // quiet period before the running step is reported
+	logFilter  flags.LogFilter // selects the log lines printed
+	logBackend string          // log backend URL template, queried when the pod is gone
+	localPorts []string        // local ports forwarded to the build pod while following
 }
 
+const buildRunLogsLongDesc = `
+Prints the logs of the BuildRun pod containers, and with --follow streams them until the BuildRun
+completes or fails. Long logs can be narrowed down to the lines matching a regular expression, or
+to the lines of selected build strategy steps. For example:
+
+	$ shp buildrun logs my-app-xyz --follow --grep "(?i)error"
+	$ shp buildrun logs my-app-xyz --step build-and-push
+
+When following, the logs written so far are printed first, attaching to a BuildRun already running
+mid-flight skips them with --from-start=false, only streaming the logs written from now on. The
+steps started afterwards are streamed in full:
+
+	$ shp buildrun logs my-app-xyz --follow --from-start=false
+
+While following, --local-port-forward forwards the local port to the build pod, reaching the debug
+servers exposed by the build strategy:
+
+	$ shp buildrun logs my-app-xyz --follow --local-port-forward=5005:5005
+
+Once the BuildRun pod is garbage collected, the logs are obtained from the log backend informed by
+--log-backend, or the "log-backend" configuration key, when available. The URL placeholders
+"{namespace}", "{pod}" and "{buildrun}" are replaced by the respective names, the backend must reply
+the pod logs as plain text, and the SHP_LOG_BACKEND_TOKEN environment variable is sent as bearer
+token. For example:
+
+	$ shp config set log-backend "https://logs.example.com/api/v1/logs?namespace={namespace}&pod={pod}"
+
+Without a log backend, or when it fails, the details recorded on the BuildRun are shown instead:
+the outcome, the failed step, the timing and the results.
+`
+
 func logsCmd() runner.SubCommand {
 	cmd := &cobra.Command{
 		Use:   "logs <name>",
 		Short: "See BuildRun log output",
+		Long:  buildRunLogsLongDesc,
 		Args:  cobra.ExactArgs(1),
 	}
 	logCommand := &LogsCommand{
 		cmd: cmd,
 	}
 	cmd.Flags().BoolVarP(&logCommand.follow, "follow", "F", logCommand.follow, "Follow the log of a buildrun until it completes or fails.")
+	cmd.Flags().BoolVar(&logCommand.fromStart, "from-start", true, "Print the logs from the start when following, otherwise only the ones written from now on")
+	flags.MaxLogRateFlags(cmd.Flags(), &logCommand.maxLogRate)
+	flags.HeartbeatIntervalFlags(cmd.Flags(), &logCommand.heartbeat)
+	flags.LogFilterFlags(cmd.Flags(), &logCommand.logFilter)
+	flags.LocalPortForwardFlags(cmd.Flags(), &logCommand.localPorts)
+	cmd.Flags().StringVar(&logCommand.logBackend, "log-backend", "", "log backend URL serving the logs once the BuildRun pod is gone, e.g. \"https://logs.example.com/?pod={pod}\"")
 	return logCommand
 }
 
@@ -61,12 +111,40 @@ func (c *LogsCommand) Complete(params *params.Params, ioStreams *genericclioptio
 		Name:      c.name,
 	}
 	var err error
-	c.follower, err = params.NewFollower(c.Cmd().Context(), br, ioStreams)
-	return err
+	if c.follower, err = params.NewFollower(c.Cmd().Context(), br, ioStreams); err != nil {
+		return err
+	}
+	c.follower.SetMaxLogRate(c.maxLogRate)
+	c.follower.SetLineFilter(c.logFilter.LineFilter())
+	c.follower.SetHeartbeat(c.heartbeat)
+	if !c.fromStart {
+		c.follower.SetSince(time.Now())
+	}
+	return nil
 }
 
 // Validate validates data input by user
 func (c *LogsCommand) Validate() error {
+	if c.maxLogRate > 0 && !c.follow {
+		return fmt.Errorf("--%s requires --follow", flags.MaxLogRateFlag)
+	}
+	if c.heartbeat < 0 {
+		return fmt.Errorf("--%s must not be negative", flags.HeartbeatIntervalFlag)
+	}
+	if c.cmd.Flags().Changed(flags.HeartbeatIntervalFlag) && !c.follow {
+		return fmt.Errorf("--%s requires --follow", flags.HeartbeatIntervalFlag)
+	}
+	if !c.fromStart && !c.follow {
+		return fmt.Errorf("--from-start=false requires --follow")
+	}
+	if len(c.localPorts) > 0 {
+		if !c.follow {
+			return fmt.Errorf("--%s requires --follow", flags.LocalPortForwardFlag)
+		}
+		if err := debug.ValidatePorts(c.localPorts); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -77,21 +155,24 @@ func (c *LogsCommand) Run(params *params.Params, ioStreams *genericclioptions.IO
 		return err
 	}
 
-	lo := v1.ListOptions{
-		LabelSelector: fmt.Sprintf("%v=%v", buildv1alpha1.LabelBuildRun, c.name),
-	}
+	lo := reactor.BuildRunPodListOptions(c.name)
 
 	// first see if pod is already done; if so, even if we have follow == true, just do the normal path;
 	// we don't employ a pod watch here since the buildrun may already be complete before 'shp buildrun logs -F'
 	// is invoked.
 	justGetLogs := false
 	var pods *corev1.PodList
+	var completed *buildv1alpha1.BuildRun
 	err = wait.PollUntilContextTimeout(c.cmd.Context(), 1*time.Second, 10*time.Second, true, func(ctx context.Context) (done bool, err error) {
 		if pods, err = clientset.CoreV1().Pods(params.Namespace()).List(ctx, lo); err != nil {
 			fmt.Fprintf(ioStreams.ErrOut, "error listing Pods for BuildRun %q: %s\n", c.name, err.Error())
 			return false, nil
 		}
 		if len(pods.Items) == 0 {
+			// the pod of a completed BuildRun is not coming back, i.e. garbage collected
+			if completed, err = c.completedBuildRun(ctx, params); err != nil || completed != nil {
+				return true, err
+			}
 			fmt.Fprintf(ioStreams.ErrOut, "no builder pod found for BuildRun %q\n", c.name)
 			return false, nil
 		}
@@ -100,22 +181,33 @@ func (c *LogsCommand) Run(params *params.Params, ioStreams *genericclioptions.IO
 	if err != nil {
 		return err
 	}
+	if completed != nil {
+		return c.printWithoutPod(params, ioStreams, completed)
+	}
 	pod := pods.Items[0]
 	phase := pod.Status.Phase
 	if phase == corev1.PodFailed || phase == corev1.PodSucceeded {
 		justGetLogs = true
 	}
 
+	if justGetLogs && !c.fromStart {
+		fmt.Fprintf(ioStreams.Out, "BuildRun %q pod %q has already finished, there are no logs to follow\n", c.name, pod.GetName())
+		return nil
+	}
 	if !c.follow || justGetLogs {
 		fmt.Fprintf(ioStreams.Out, "Obtaining logs for BuildRun %q\n\n", c.name)
 
 		var b strings.Builder
+		filter := c.logFilter.LineFilter()
 		containers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
 		for _, container := range containers {
 			logs, err := util.GetPodLogs(c.cmd.Context(), clientset, pod, container.Name)
 			if err != nil {
 				return err
 			}
+			if logs = tail.FilterLines(filter, container.Name, logs); filter != nil && logs == "" {
+				continue
+			}
 
 			fmt.Fprintf(&b, "*** Pod %q, container %q: ***\n\n", pod.Name, container.Name)
 			fmt.Fprintln(&b, logs)
@@ -126,6 +218,113 @@ func (c *LogsCommand) Run(params *params.Params, ioStreams *genericclioptions.IO
 		return nil
 
 	}
+	if len(c.localPorts) > 0 {
+		restConfig, err := params.RESTConfig()
+		if err != nil {
+			return err
+		}
+		forwarder := debug.NewPortForwarder(restConfig, clientset)
+		stop := forwarder.Start(c.cmd.Context(), params.Namespace(), lo, c.localPorts, ioStreams.ErrOut)
+		defer stop()
+	}
 	_, err = c.follower.Start(lo)
 	return err
 }
+
+// completedBuildRun returns the BuildRun when it has completed, nil otherwise. A BuildRun which
+// does not exist is reported as such.
+func (c *LogsCommand) completedBuildRun(ctx context.Context, params *params.Params) (*buildv1alpha1.BuildRun, error) {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return nil, err
+	}
+	br, err := clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Get(ctx, c.name, v1.GetOptions{})
+	switch {
+	case kerrors.IsNotFound(err):
+		return nil, fmt.Errorf("BuildRun %q not found", c.name)
+	case err != nil:
+		// the pod may still show up, the BuildRun is inspected again on the next attempt
+		return nil, nil
+	}
+	if cond := br.Status.GetCondition(buildv1alpha1.Succeeded); cond == nil || cond.Status == corev1.ConditionUnknown {
+		return nil, nil
+	}
+	return br, nil
+}
+
+// podNameOf returns the name of the BuildRun pod, either recorded on the failure details or named
+// after the TaskRun, empty when unknown.
+func podNameOf(br *buildv1alpha1.BuildRun) string {
+	switch {
+	case br.Status.FailureDetails != nil && br.Status.FailureDetails.Location != nil && br.Status.FailureDetails.Location.Pod != "":
+		return br.Status.FailureDetails.Location.Pod
+	case br.Status.FailedAt != nil && br.Status.FailedAt.Pod != "":
+		return br.Status.FailedAt.Pod
+	case br.Status.LatestTaskRunRef != nil:
+		return *br.Status.LatestTaskRunRef + "-pod"
+	}
+	return ""
+}
+
+// printWithoutPod prints the logs of the completed BuildRun from the log backend, when configured,
+// falling back to the details recorded on the BuildRun.
+func (c *LogsCommand) printWithoutPod(params *params.Params, ioStreams *genericclioptions.IOStreams, br *buildv1alpha1.BuildRun) error {
+	podName := podNameOf(br)
+	if c.logBackend != "" && podName != "" {
+		url := util.LogBackendURL(c.logBackend, params.Namespace(), podName, c.name)
+		logs, err := util.GetBackendLogs(c.cmd.Context(), url)
+		if err == nil {
+			if len(c.logFilter.Steps) > 0 {
+				fmt.Fprintf(ioStreams.ErrOut, "Warning: --%s does not apply to the logs of the log backend\n", flags.StepFlag)
+			}
+			if c.logFilter.Grep != nil {
+				logs = tail.FilterLines(tail.MatchFilter(c.logFilter.Grep), "", logs)
+			}
+			fmt.Fprintf(ioStreams.Out, "Obtaining logs for BuildRun %q from the log backend\n\n", c.name)
+			fmt.Fprintf(ioStreams.Out, "*** Pod %q: ***\n\n", podName)
+			fmt.Fprintln(ioStreams.Out, logs)
+			return nil
+		}
+		fmt.Fprintf(ioStreams.ErrOut, "Warning: unable to obtain the logs from the log backend: %v\n", err)
+	}
+
+	fmt.Fprintf(ioStreams.Out, "The pod of BuildRun %q is gone, showing the details recorded on the BuildRun instead\n\n", c.name)
+	writer := tabwriter.NewWriter(ioStreams.Out, 0, 8, 2, ' ', 0)
+	add := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(writer, "%s:\t%s\n", name, value)
+		}
+	}
+
+	cond := br.Status.GetCondition(buildv1alpha1.Succeeded)
+	add("Status", string(cond.GetStatus()))
+	add("Reason", cond.GetReason())
+	add("Message", cond.GetMessage())
+	add("Pod", podName)
+	if details := br.Status.FailureDetails; details != nil {
+		if details.Location != nil && details.Location.Container != "" {
+			add("Failed step", strings.TrimPrefix(details.Location.Container, "step-"))
+		}
+		add("Failure reason", details.Reason)
+		add("Failure message", details.Message)
+	}
+	if br.Status.StartTime != nil {
+		add("Started", br.Status.StartTime.Format(time.RFC3339))
+	}
+	if br.Status.CompletionTime != nil {
+		add("Completed", br.Status.CompletionTime.Format(time.RFC3339))
+		if br.Status.StartTime != nil {
+			add("Duration", br.Status.CompletionTime.Sub(br.Status.StartTime.Time).Round(time.Second).String())
+		}
+	}
+
+	results := resultsOf(br)
+	add("Image", results.Image)
+	for _, source := range results.Sources {
+		add(source.Name+" commit", source.CommitSha)
+		add(source.Name+" author", source.CommitAuthor)
+		add(source.Name+" branch", source.BranchName)
+		add(source.Name+" bundle digest", source.BundleDigest)
+	}
+	return writer.Flush()
+}
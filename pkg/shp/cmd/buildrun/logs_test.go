@@ -2,8 +2,12 @@ package buildrun
 
 import (
 	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
 	"github.com/shipwright-io/cli/pkg/shp/reactor"
@@ -55,6 +59,57 @@ func TestStreamBuildLogs(t *testing.T) {
 
 }
 
+func TestStreamBuildLogsFiltered(t *testing.T) {
+	name := "test-obj"
+	pod := &corev1.Pod{}
+	pod.Name = name
+	pod.Namespace = metav1.NamespaceDefault
+	pod.Labels = map[string]string{
+		v1alpha1.LabelBuildRun: name,
+	}
+	pod.Spec.Containers = []corev1.Container{{Name: "step-source-default"}, {Name: "step-build-and-push"}}
+
+	for _, test := range []struct {
+		args     []string
+		contains []string
+		excludes []string
+	}{{
+		args:     []string{"--step", "build-and-push"},
+		contains: []string{`container "step-build-and-push"`, "fake logs"},
+		excludes: []string{`container "step-source-default"`},
+	}, {
+		args:     []string{"--grep", "^fake"},
+		contains: []string{`container "step-source-default"`, `container "step-build-and-push"`},
+	}, {
+		args:     []string{"--grep", "error"},
+		excludes: []string{"fake logs", `container "step-`},
+	}} {
+		cmd := logsCmd().(*LogsCommand)
+		if err := cmd.Cmd().ParseFlags(test.args); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+		cmd.name = name
+		// set up context
+		cmd.Cmd().ExecuteC()
+
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		param := params.NewParamsForTest(fake.NewSimpleClientset(pod), nil, nil, metav1.NamespaceDefault, nil, nil)
+		if err := cmd.Run(param, &ioStreams); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+		for _, s := range test.contains {
+			if !strings.Contains(out.String(), s) {
+				t.Errorf("%v: expected %q on the output: %s", test.args, s, out.String())
+			}
+		}
+		for _, s := range test.excludes {
+			if strings.Contains(out.String(), s) {
+				t.Errorf("%v: unexpected %q on the output: %s", test.args, s, out.String())
+			}
+		}
+	}
+}
+
 func TestStreamBuildRunFollowLogs(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -246,3 +301,154 @@ func checkLog(name, text string, cmd *LogsCommand, out *bytes.Buffer, t *testing
 		t.Errorf("test %s: unexpected output: %s", name, out.String())
 	}
 }
+
+func TestBuildRunLogsWithoutPod(t *testing.T) {
+	name := "my-app-xyz"
+	start := metav1.NewTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	completion := metav1.NewTime(start.Add(90 * time.Second))
+	taskRun := name + "-tr"
+	br := &v1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+		Status: v1alpha1.BuildRunStatus{
+			Conditions: v1alpha1.Conditions{{
+				Type:    v1alpha1.Succeeded,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Failed",
+				Message: "buildrun step step-build-and-push failed",
+			}},
+			LatestTaskRunRef: &taskRun,
+			StartTime:        &start,
+			CompletionTime:   &completion,
+			FailureDetails: &v1alpha1.FailureDetails{
+				Reason:   "PushFailed",
+				Message:  "unauthorized",
+				Location: &v1alpha1.FailedAt{Container: "step-build-and-push"},
+			},
+		},
+	}
+
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.RequestURI()
+		if r.URL.Query().Get("pod") != taskRun+"-pod" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "STEP 1/2: FROM ubi\nerror: unauthorized\n")
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, string, error) {
+		cmd := logsCmd().(*LogsCommand)
+		if err := cmd.Cmd().ParseFlags(args); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+		cmd.name = name
+		// set up context
+		cmd.Cmd().ExecuteC()
+
+		ioStreams, _, out, errOut := genericclioptions.NewTestIOStreams()
+		param := params.NewParamsForTest(fake.NewSimpleClientset(), shpfake.NewSimpleClientset(br), nil, metav1.NamespaceDefault, nil, nil)
+		err := cmd.Run(param, &ioStreams)
+		return out.String(), errOut.String(), err
+	}
+
+	out, _, err := run()
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	for _, s := range []string{
+		`The pod of BuildRun "my-app-xyz" is gone`,
+		"Status:           False",
+		"Failed step:      build-and-push",
+		"Failure message:  unauthorized",
+		"Duration:         1m30s",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q on the output: %s", s, out)
+		}
+	}
+
+	out, _, err = run("--log-backend", server.URL+"/logs?ns={namespace}&pod={pod}", "--grep", "error")
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if requested != "/logs?ns=default&pod=my-app-xyz-tr-pod" {
+		t.Errorf("unexpected log backend request %q", requested)
+	}
+	if !strings.Contains(out, "error: unauthorized\n") || strings.Contains(out, "STEP 1/2") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	out, errOut, err := run("--log-backend", server.URL+"/logs?pod=other")
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if !strings.Contains(errOut, "unable to obtain the logs from the log backend: log backend replied 404 Not Found") ||
+		!strings.Contains(out, "Failed step:") {
+		t.Errorf("unexpected output: %s %s", out, errOut)
+	}
+
+	cmd := logsCmd().(*LogsCommand)
+	cmd.name = "missing"
+	cmd.Cmd().ExecuteC()
+	ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+	param := params.NewParamsForTest(fake.NewSimpleClientset(), shpfake.NewSimpleClientset(), nil, metav1.NamespaceDefault, nil, nil)
+	if err = cmd.Run(param, &ioStreams); err == nil || err.Error() != `BuildRun "missing" not found` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBuildRunLogsFromStart(t *testing.T) {
+	name := "test-obj"
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      name,
+			Labels:    map[string]string{v1alpha1.LabelBuildRun: name},
+		},
+		Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: name}}},
+		Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+	}
+
+	cmd := logsCmd().(*LogsCommand)
+	if err := cmd.cmd.ParseFlags([]string{"--from-start=false"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Validate(); err == nil || err.Error() != "--from-start=false requires --follow" {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	cmd = logsCmd().(*LogsCommand)
+	if err := cmd.cmd.ParseFlags([]string{"--local-port-forward=5005"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Validate(); err == nil || err.Error() != "--local-port-forward requires --follow" {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	cmd = logsCmd().(*LogsCommand)
+	if err := cmd.cmd.ParseFlags([]string{"--follow", "--from-start=false"}); err != nil {
+		t.Fatal(err)
+	}
+	cmd.Cmd().ExecuteC()
+	clientset := fake.NewSimpleClientset(pod)
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+	param := params.NewParamsForTest(clientset, shpfake.NewSimpleClientset(), nil, metav1.NamespaceDefault, nil, nil)
+	if err := cmd.Complete(param, &ioStreams, []string{name}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Run(param, &ioStreams); err != nil {
+		t.Fatal(err)
+	}
+	// the logs of the finished pod are not replayed
+	if strings.Contains(out.String(), "fake logs") {
+		t.Errorf("unexpected logs: %s", out.String())
+	}
+	if !strings.Contains(out.String(), `BuildRun "test-obj" pod "test-obj" has already finished, there are no logs to follow`) {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
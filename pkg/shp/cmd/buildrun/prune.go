@@ -0,0 +1,185 @@
+package buildrun
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// PruneCommand contains data input from user for the prune sub-command
+type PruneCommand struct {
+	cmd *cobra.Command
+
+	keepLast   int           // completed BuildRuns kept per Build, zero disables it
+	olderThan  time.Duration // minimum age of the completed BuildRuns pruned, zero disables it
+	onlyFailed bool          // prune the failed BuildRuns only
+	build      string        // prune the BuildRuns of this Build only
+	dryRun     bool          // show the BuildRuns pruned without deleting them
+}
+
+const pruneLongDesc = `
+Deletes the completed BuildRuns selected by the retention policy flags, for clusters where the
+BuildRun retention is not configured. The BuildRuns still pending or running are never pruned.
+The policies are combined, a BuildRun is pruned when it matches all of them:
+
+	--keep-last    the most recent completed BuildRuns of each Build are kept
+	--older-than   only the BuildRuns completed before the informed duration are pruned
+	--only-failed  only the failed BuildRuns are pruned
+
+At least one policy must be informed. Use --dry-run to preview what would be pruned, and --build to
+prune the BuildRuns of a single Build. For example:
+
+	$ shp buildrun prune --keep-last=5
+	$ shp buildrun prune --keep-last=5 --older-than=72h --only-failed --dry-run
+	$ shp buildrun prune --build=my-app --older-than=168h
+`
+
+func pruneCmd() runner.SubCommand {
+	c := &PruneCommand{
+		cmd: &cobra.Command{
+			Use:   "prune [flags]",
+			Short: "Delete completed BuildRuns by age, amount per Build, or status",
+			Long:  pruneLongDesc,
+			Args:  cobra.NoArgs,
+		},
+	}
+	c.cmd.Flags().IntVar(&c.keepLast, "keep-last", 0, "amount of the most recent completed BuildRuns kept per Build, zero keeps none")
+	c.cmd.Flags().DurationVar(&c.olderThan, "older-than", 0, "prune only the BuildRuns completed longer than the duration ago, e.g. 72h")
+	c.cmd.Flags().BoolVar(&c.onlyFailed, "only-failed", false, "prune only the failed BuildRuns")
+	c.cmd.Flags().StringVar(&c.build, "build", "", "prune only the BuildRuns of the Build")
+	c.cmd.Flags().BoolVar(&c.dryRun, "dry-run", false, "show the BuildRuns pruned without deleting them")
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *PruneCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *PruneCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	return nil
+}
+
+// Validate makes sure at least one retention policy is informed, thus the completed BuildRuns are
+// not pruned all at once by mistake.
+func (c *PruneCommand) Validate() error {
+	if c.keepLast < 0 {
+		return fmt.Errorf("--keep-last must not be negative")
+	}
+	if c.olderThan < 0 {
+		return fmt.Errorf("--older-than must not be negative")
+	}
+	if c.keepLast == 0 && c.olderThan == 0 && !c.onlyFailed {
+		return errors.New("at least one of --keep-last, --older-than or --only-failed must be informed")
+	}
+	return nil
+}
+
+// Run lists the BuildRuns of the namespace and deletes the ones selected by the policies.
+func (c *PruneCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	ctx := c.cmd.Context()
+	ns := params.Namespace()
+	buildRuns := []buildv1alpha1.BuildRun{}
+	_, err = util.ListBuildRunPages(ctx, clientset.ShipwrightV1alpha1().BuildRuns(ns).List, metav1.ListOptions{}, 0,
+		util.DefaultChunkSize, func(page *buildv1alpha1.BuildRunList) error {
+			buildRuns = append(buildRuns, page.Items...)
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	pruned := c.selectPruned(buildRuns, time.Now())
+	if len(pruned) == 0 {
+		fmt.Fprintf(ioStreams.Out, "No BuildRuns to prune in namespace %q\n", ns)
+		return nil
+	}
+	for i := range pruned {
+		br := &pruned[i]
+		if c.dryRun {
+			fmt.Fprintf(ioStreams.Out, "BuildRun %q of Build %q would be deleted\n", br.GetName(), buildNameOf(br))
+			continue
+		}
+		uid := br.GetUID()
+		err = clientset.ShipwrightV1alpha1().BuildRuns(ns).Delete(ctx, br.GetName(), metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &uid},
+		})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete BuildRun %q: %w", br.GetName(), err)
+		}
+		fmt.Fprintf(ioStreams.Out, "BuildRun %q of Build %q deleted\n", br.GetName(), buildNameOf(br))
+	}
+	if c.dryRun {
+		fmt.Fprintf(ioStreams.Out, "%d BuildRuns would be pruned\n", len(pruned))
+		return nil
+	}
+	fmt.Fprintf(ioStreams.Out, "Pruned %d BuildRuns\n", len(pruned))
+	return nil
+}
+
+// selectPruned returns the completed BuildRuns matching the policies, grouped by Build name and
+// from the most recent within each Build.
+func (c *PruneCommand) selectPruned(buildRuns []buildv1alpha1.BuildRun, now time.Time) []buildv1alpha1.BuildRun {
+	groups := map[string][]buildv1alpha1.BuildRun{}
+	for _, br := range buildRuns {
+		if !br.IsDone() {
+			continue
+		}
+		build := buildNameOf(&br)
+		if c.build != "" && build != c.build {
+			continue
+		}
+		groups[build] = append(groups[build], br)
+	}
+	builds := make([]string, 0, len(groups))
+	for build := range groups {
+		builds = append(builds, build)
+	}
+	sort.Strings(builds)
+
+	pruned := []buildv1alpha1.BuildRun{}
+	for _, build := range builds {
+		runs := groups[build]
+		sort.SliceStable(runs, func(i, j int) bool {
+			return util.CompletionTimeOf(&runs[j]).Before(util.CompletionTimeOf(&runs[i]))
+		})
+		for i := range runs {
+			br := &runs[i]
+			switch {
+			case i < c.keepLast:
+			case c.olderThan > 0 && now.Sub(util.CompletionTimeOf(br)) < c.olderThan:
+			case c.onlyFailed && util.PhaseOf(br) != util.PhaseFailed:
+			default:
+				pruned = append(pruned, *br)
+			}
+		}
+	}
+	return pruned
+}
+
+// buildNameOf returns the name of the Build referenced by the BuildRun, the embedded Build
+// specifications are grouped together.
+func buildNameOf(br *buildv1alpha1.BuildRun) string {
+	if br.Spec.BuildRef != nil && br.Spec.BuildRef.Name != "" {
+		return br.Spec.BuildRef.Name
+	}
+	return noBuild
+}
@@ -0,0 +1,98 @@
+package buildrun
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestPruneCommand(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+	now := time.Now()
+
+	buildRun := func(name, build string, status corev1.ConditionStatus, completedAgo time.Duration) *buildv1alpha1.BuildRun {
+		br := &buildv1alpha1.BuildRun{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Spec:       buildv1alpha1.BuildRunSpec{BuildRef: &buildv1alpha1.BuildRef{Name: build}},
+		}
+		if status != "" {
+			completion := metav1.NewTime(now.Add(-completedAgo))
+			br.Status.CompletionTime = &completion
+			br.Status.Conditions = buildv1alpha1.Conditions{{Type: buildv1alpha1.Succeeded, Status: status}}
+		}
+		return br
+	}
+	shpClientset := shpfake.NewSimpleClientset(
+		buildRun("app-1", "app", corev1.ConditionTrue, 100*time.Hour),
+		buildRun("app-2", "app", corev1.ConditionFalse, 90*time.Hour),
+		buildRun("app-3", "app", corev1.ConditionTrue, 2*time.Hour),
+		buildRun("app-4", "app", corev1.ConditionFalse, time.Hour),
+		buildRun("app-5", "app", "", 0),
+		buildRun("lib-1", "lib", corev1.ConditionFalse, 80*time.Hour),
+	)
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpClientset, nil, ns, nil, nil)
+
+	run := func(args ...string) string {
+		cmd := pruneCmd().(*PruneCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		g.Expect(cmd.Validate()).To(o.Succeed())
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+		return out.String()
+	}
+	names := func() []string {
+		brs, err := shpClientset.ShipwrightV1alpha1().BuildRuns(ns).List(context.TODO(), metav1.ListOptions{})
+		g.Expect(err).To(o.BeNil())
+		names := []string{}
+		for _, br := range brs.Items {
+			names = append(names, br.Name)
+		}
+		return names
+	}
+
+	g.Expect(run("--keep-last=1", "--dry-run")).To(o.Equal(`BuildRun "app-3" of Build "app" would be deleted
+BuildRun "app-2" of Build "app" would be deleted
+BuildRun "app-1" of Build "app" would be deleted
+3 BuildRuns would be pruned
+`))
+	g.Expect(names()).To(o.HaveLen(6))
+
+	g.Expect(run("--older-than=72h", "--only-failed")).To(o.Equal(`BuildRun "app-2" of Build "app" deleted
+BuildRun "lib-1" of Build "lib" deleted
+Pruned 2 BuildRuns
+`))
+	g.Expect(names()).To(o.ConsistOf("app-1", "app-3", "app-4", "app-5"))
+
+	g.Expect(run("--build=app", "--keep-last=2")).To(o.Equal(`BuildRun "app-1" of Build "app" deleted
+Pruned 1 BuildRuns
+`))
+	g.Expect(run("--build=app", "--keep-last=2")).To(o.Equal("No BuildRuns to prune in namespace \"default\"\n"))
+	g.Expect(names()).To(o.ConsistOf("app-3", "app-4", "app-5"))
+}
+
+func TestPruneCommandValidate(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"--dry-run"},
+		{"--keep-last=-1"},
+		{"--older-than=-1h"},
+	} {
+		g := o.NewWithT(t)
+		cmd := pruneCmd().(*PruneCommand)
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		g.Expect(cmd.Validate()).ToNot(o.Succeed(), "%v", args)
+	}
+}
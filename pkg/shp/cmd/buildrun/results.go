@@ -0,0 +1,178 @@
+package buildrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// ResultsCommand contains data input from user for the results sub-command
+type ResultsCommand struct {
+	cmd *cobra.Command
+
+	name     string
+	output   string
+	noHeader bool
+}
+
+const resultsLongDesc = `
+Shows the results recorded by the build steps on the BuildRun status: the output image digest and
+size, and for each source the git commit SHA, author and branch, or the source bundle digest. With
+"-o json" the results are rendered for pipeline consumption, for example:
+
+	$ shp buildrun results my-app-xyz12 -o json | jq -r .image
+`
+
+// sourceResults results of a single source.
+type sourceResults struct {
+	Name         string `json:"name"`
+	CommitSha    string `json:"commitSha,omitempty"`
+	CommitAuthor string `json:"commitAuthor,omitempty"`
+	BranchName   string `json:"branchName,omitempty"`
+	BundleDigest string `json:"bundleDigest,omitempty"`
+}
+
+// buildRunResults results recorded on the BuildRun status.
+type buildRunResults struct {
+	Image       string          `json:"image,omitempty"`
+	ImageDigest string          `json:"imageDigest,omitempty"`
+	ImageSize   int64           `json:"imageSize,omitempty"`
+	Sources     []sourceResults `json:"sources,omitempty"`
+}
+
+func resultsCmd() runner.SubCommand {
+	c := &ResultsCommand{
+		cmd: &cobra.Command{
+			Use:   "results <name> [flags]",
+			Short: "Show the results recorded on the BuildRun",
+			Long:  resultsLongDesc,
+			Args:  cobra.ExactArgs(1),
+		},
+	}
+	c.cmd.Flags().StringVarP(&c.output, "output", "o", "", "output format of the results, either empty or \"json\"")
+	c.cmd.Flags().BoolVar(&c.noHeader, "no-header", false, "Do not show columns header in list output")
+	return c
+}
+
+// Cmd returns cobra command object of the results sub-command
+func (c *ResultsCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in the BuildRun name
+func (c *ResultsCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+	return nil
+}
+
+// Validate checks the output format
+func (c *ResultsCommand) Validate() error {
+	if c.output != "" && c.output != "json" {
+		return fmt.Errorf("unsupported --output %q, only \"json\" is supported", c.output)
+	}
+	return nil
+}
+
+// resultsOf collects the results recorded on the BuildRun status, the output image is qualified by
+// its digest.
+func resultsOf(br *buildv1alpha1.BuildRun) *buildRunResults {
+	results := &buildRunResults{}
+	if output := br.Status.Output; output != nil {
+		results.ImageDigest = output.Digest
+		results.ImageSize = output.Size
+
+		image := ""
+		switch {
+		case br.Spec.Output != nil:
+			image = br.Spec.Output.Image
+		case br.Status.BuildSpec != nil:
+			image = br.Status.BuildSpec.Output.Image
+		}
+		if image != "" && output.Digest != "" {
+			results.Image = fmt.Sprintf("%s@%s", imageRepository(image), output.Digest)
+		}
+	}
+	for _, source := range br.Status.Sources {
+		sr := sourceResults{Name: source.Name}
+		if source.Git != nil {
+			sr.CommitSha = source.Git.CommitSha
+			sr.CommitAuthor = source.Git.CommitAuthor
+			sr.BranchName = source.Git.BranchName
+		}
+		if source.Bundle != nil {
+			sr.BundleDigest = source.Bundle.Digest
+		}
+		results.Sources = append(results.Sources, sr)
+	}
+	return results
+}
+
+// imageRepository strips the tag, or digest, from the image reference.
+func imageRepository(image string) string {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return image
+	}
+	return ref.Context().Name()
+}
+
+// Run prints the results recorded on the BuildRun status
+func (c *ResultsCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	br, err := clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Get(c.cmd.Context(), c.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	results := resultsOf(br)
+
+	if c.output == "json" {
+		enc := json.NewEncoder(ioStreams.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	rows := [][2]string{}
+	add := func(name, value string) {
+		if value != "" {
+			rows = append(rows, [2]string{name, value})
+		}
+	}
+	add("image", results.Image)
+	add("image-digest", results.ImageDigest)
+	if results.ImageSize > 0 {
+		add("image-size", strconv.FormatInt(results.ImageSize, 10))
+	}
+	for _, source := range results.Sources {
+		add(source.Name+".commit-sha", source.CommitSha)
+		add(source.Name+".commit-author", source.CommitAuthor)
+		add(source.Name+".branch-name", source.BranchName)
+		add(source.Name+".bundle-digest", source.BundleDigest)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintf(ioStreams.Out, "No results recorded on BuildRun %q\n", c.name)
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(ioStreams.Out, 0, 8, 2, '\t', 0)
+	if !c.noHeader {
+		fmt.Fprintln(writer, "NAME\tVALUE")
+	}
+	for _, row := range rows {
+		fmt.Fprintf(writer, "%s\t%s\n", row[0], row[1])
+	}
+	return writer.Flush()
+}
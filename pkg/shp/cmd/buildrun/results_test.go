@@ -0,0 +1,70 @@
+package buildrun
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestResultsCommand(t *testing.T) {
+	g := o.NewWithT(t)
+
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	br := &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"},
+		Status: buildv1alpha1.BuildRunStatus{
+			BuildSpec: &buildv1alpha1.BuildSpec{Output: buildv1alpha1.Image{Image: "ghcr.io/org/app:latest"}},
+			Output:    &buildv1alpha1.Output{Digest: digest, Size: 1024},
+			Sources: []buildv1alpha1.SourceResult{{
+				Name: "default",
+				Git:  &buildv1alpha1.GitSourceResult{CommitSha: "abc123", CommitAuthor: "Jane Doe"},
+			}},
+		},
+	}
+	empty := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "empty"}}
+	p := params.NewParamsForTest(nil, shpfake.NewSimpleClientset(br, empty), nil, metav1.NamespaceDefault, nil, nil)
+
+	run := func(name string, args ...string) string {
+		cmd := resultsCmd().(*ResultsCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		g.Expect(cmd.Complete(p, nil, []string{name})).To(o.Succeed())
+		g.Expect(cmd.Validate()).To(o.Succeed())
+
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+		return out.String()
+	}
+
+	rows := [][]string{}
+	for _, line := range strings.Split(strings.TrimSpace(run("br", "--no-header")), "\n") {
+		rows = append(rows, strings.Fields(line))
+	}
+	g.Expect(rows).To(o.Equal([][]string{
+		{"image", "ghcr.io/org/app@" + digest},
+		{"image-digest", digest},
+		{"image-size", "1024"},
+		{"default.commit-sha", "abc123"},
+		{"default.commit-author", "Jane", "Doe"},
+	}))
+
+	g.Expect(run("br", "-o", "json")).To(o.MatchJSON(`{
+		"image": "ghcr.io/org/app@` + digest + `",
+		"imageDigest": "` + digest + `",
+		"imageSize": 1024,
+		"sources": [{"name": "default", "commitSha": "abc123", "commitAuthor": "Jane Doe"}]
+	}`))
+
+	g.Expect(run("empty")).To(o.Equal("No results recorded on BuildRun \"empty\"\n"))
+	g.Expect(run("empty", "-o", "json")).To(o.MatchJSON(`{}`))
+}
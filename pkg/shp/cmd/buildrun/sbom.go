@@ -0,0 +1,171 @@
+package buildrun
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/spf13/cobra"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/attest"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+	"github.com/shipwright-io/cli/pkg/shp/sbom"
+)
+
+// SbomCommand contains data input from user for the sbom sub-command
+type SbomCommand struct {
+	cmd *cobra.Command
+
+	name           string
+	format         string
+	registryAuth   string
+	registrySecret string
+
+	to sbom.Format // format the document is converted to, empty to keep it as published
+}
+
+const sbomLongDesc = `
+Retrieves the software bill of materials of the BuildRun output image, looking it up on the "sbom"
+result reported by the build strategy, which carries either the document or the reference of the
+artifact holding it, then among the OCI referrers of the image, and at last on the tag employed by
+"cosign attach sbom". The document is printed as published, or converted with --format, keeping the
+name, version, package URL and license of the packages. For example:
+
+	$ shp buildrun sbom my-app-xyz12 --format=cyclonedx > sbom.cdx.json
+`
+
+func sbomCmd() runner.SubCommand {
+	c := &SbomCommand{
+		cmd: &cobra.Command{
+			Use:   "sbom <name> [flags]",
+			Short: "Retrieve the SBOM of the BuildRun output image",
+			Long:  sbomLongDesc,
+			Args:  cobra.ExactArgs(1),
+		},
+	}
+
+	c.cmd.Flags().StringVar(&c.format, "format", "",
+		fmt.Sprintf("convert the SBOM to the format, one of %v, by default it's printed as published", sbom.Formats))
+	flags.RegistryAuthFlags(c.cmd.Flags(), &c.registryAuth, &c.registrySecret)
+
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *SbomCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *SbomCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+	return nil
+}
+
+// Validate checks the format and the registry credentials source
+func (c *SbomCommand) Validate() error {
+	if c.format != "" {
+		var err error
+		if c.to, err = sbom.ParseFormat(c.format); err != nil {
+			return err
+		}
+	}
+	_, err := registry.ParseAuthSource(c.registryAuth)
+	return err
+}
+
+// Run looks up the SBOM of the BuildRun output image and prints it
+func (c *SbomCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	shpClientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	ctx := c.cmd.Context()
+	br, err := shpClientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Get(ctx, c.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	listOpts := reactor.BuildRunPodListOptions(c.name)
+	pods, err := clientset.CoreV1().Pods(params.Namespace()).List(ctx, listOpts)
+	if err != nil {
+		return err
+	}
+	keychain, err := c.keychain(params, br)
+	if err != nil {
+		return err
+	}
+
+	var doc *sbom.SBOM
+	if value := sbom.ResultValue(buildPodOf(pods.Items)); value != "" {
+		if doc, err = sbom.FromResult(ctx, value, keychain); err != nil {
+			return err
+		}
+	} else {
+		image, err := attest.ImageDigestReference(br)
+		if err != nil {
+			return exitcode.Errorf(exitcode.NotFound, "no SBOM found for BuildRun %q: %v", c.name, err)
+		}
+		if doc, err = sbom.FromImage(ctx, image, keychain); err != nil {
+			if errors.Is(err, sbom.ErrNotFound) {
+				return exitcode.Errorf(exitcode.NotFound, "no SBOM found for BuildRun %q image %q", c.name, image)
+			}
+			return err
+		}
+	}
+
+	location := doc.Location
+	if location == "" {
+		location = fmt.Sprintf("the %q result", sbom.ResultName)
+	}
+	fmt.Fprintf(ioStreams.ErrOut, "Found %s SBOM (%s) on %s\n", doc.Format, doc.Source, location)
+
+	data := doc.Data
+	if c.to != "" {
+		if data, err = sbom.Convert(doc.Data, c.to); err != nil {
+			return err
+		}
+	}
+	if _, err = ioStreams.Out.Write(data); err != nil {
+		return err
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		fmt.Fprintln(ioStreams.Out)
+	}
+	return nil
+}
+
+// keychain returns the registry credentials keychain, the secret source defaults to the Build
+// output credentials.
+func (c *SbomCommand) keychain(params *params.Params, br *buildv1alpha1.BuildRun) (authn.Keychain, error) {
+	source, err := registry.ParseAuthSource(c.registryAuth)
+	if err != nil {
+		return nil, err
+	}
+	opts := registry.AuthOptions{Source: source}
+	if source == registry.AuthSecret {
+		if opts.Clientset, err = params.ClientSet(); err != nil {
+			return nil, err
+		}
+		opts.Namespace = params.Namespace()
+		opts.SecretName = c.registrySecret
+		if opts.SecretName == "" && br.Status.BuildSpec != nil && br.Status.BuildSpec.Output.Credentials != nil {
+			opts.SecretName = br.Status.BuildSpec.Output.Credentials.Name
+		}
+	}
+	return registry.Keychain(c.cmd.Context(), opts)
+}
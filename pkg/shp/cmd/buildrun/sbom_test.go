@@ -0,0 +1,98 @@
+package buildrun
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestSbomCommand(t *testing.T) {
+	const spdx = `{"spdxVersion": "SPDX-2.3", "name": "app", "creationInfo": {"created": "2024-05-02T10:00:00Z"},` +
+		`"packages": [{"name": "openssl", "SPDXID": "SPDXRef-openssl", "versionInfo": "3.1.4"}]}`
+
+	br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "br-pod",
+			Labels:    map[string]string{buildv1alpha1.LabelBuildRun: "br"},
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name: "step-build-and-push",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				Message: `[{"key":"sbom","value":` + quote(spdx) + `,"type":1}]`,
+			}},
+		}}},
+	}
+
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		format   string
+		code     int
+		expected string
+	}{{
+		name:     "as published",
+		pod:      pod,
+		expected: spdx + "\n",
+	}, {
+		name:     "converted",
+		pod:      pod,
+		format:   "cyclonedx",
+		expected: `"bomFormat": "CycloneDX"`,
+	}, {
+		name: "without result nor output image digest",
+		code: exitcode.NotFound,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			clientset := fake.NewSimpleClientset()
+			if tt.pod != nil {
+				clientset = fake.NewSimpleClientset(tt.pod)
+			}
+			p := params.NewParamsForTest(clientset, shpfake.NewSimpleClientset(br), nil, metav1.NamespaceDefault, nil, nil)
+
+			cmd := sbomCmd().(*SbomCommand)
+			cmd.cmd.SetContext(context.TODO())
+			cmd.format = tt.format
+			g.Expect(cmd.Complete(p, nil, []string{"br"})).To(o.Succeed())
+			g.Expect(cmd.Validate()).To(o.Succeed())
+
+			ioStreams, _, out, errOut := genericclioptions.NewTestIOStreams()
+			err := cmd.Run(p, &ioStreams)
+			g.Expect(exitcode.FromError(err)).To(o.Equal(tt.code))
+			if tt.code != 0 {
+				return
+			}
+			g.Expect(out.String()).To(o.ContainSubstring(tt.expected))
+			g.Expect(errOut.String()).To(o.Equal("Found spdx SBOM (result) on the \"sbom\" result\n"))
+		})
+	}
+
+	cmd := sbomCmd().(*SbomCommand)
+	cmd.format = "syft-json"
+	g := o.NewWithT(t)
+	g.Expect(cmd.Validate()).To(o.MatchError(o.ContainSubstring(`unsupported SBOM format "syft-json"`)))
+}
+
+// quote renders the string as a JSON string.
+func quote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
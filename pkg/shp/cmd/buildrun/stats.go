@@ -0,0 +1,266 @@
+package buildrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientsetv1alpha1 "github.com/shipwright-io/build/pkg/client/clientset/versioned/typed/build/v1alpha1"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// StatsCommand contains data input from user for stats sub-command
+type StatsCommand struct {
+	cmd *cobra.Command
+
+	watch    bool // keep refreshing the overview from a BuildRun watch
+	failures int  // amount of recent failures to show
+}
+
+const buildRunStatsLongDesc = `
+Shows a one-screen overview of the BuildRuns in the namespace, with the amount of running,
+pending, succeeded and failed BuildRuns, the oldest pending BuildRun and the most recent failures.
+With --watch the overview is refreshed every time a BuildRun changes. For example:
+
+	$ shp buildrun stats --watch
+`
+
+// clearScreen ANSI sequence to move the cursor home and clear the terminal.
+const clearScreen = "\033[H\033[2J"
+
+func statsCmd() runner.SubCommand {
+	statsCommand := &StatsCommand{
+		cmd: &cobra.Command{
+			Use:   "stats [flags]",
+			Short: "Show an overview of the BuildRuns in the namespace",
+			Long:  buildRunStatsLongDesc,
+			Args:  cobra.NoArgs,
+		},
+	}
+
+	statsCommand.cmd.Flags().BoolVarP(&statsCommand.watch, "watch", "w", false, "Keep refreshing the overview as BuildRuns change")
+	statsCommand.cmd.Flags().IntVar(&statsCommand.failures, "failures", 5, "Amount of recent failures to show")
+
+	return statsCommand
+}
+
+// Cmd returns cobra command object
+func (c *StatsCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *StatsCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	return nil
+}
+
+// Validate validates data input by user
+func (c *StatsCommand) Validate() error {
+	if c.failures < 0 {
+		return fmt.Errorf("--failures must not be negative")
+	}
+	return nil
+}
+
+// Run executes stats sub-command logic
+func (c *StatsCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	ctx := c.cmd.Context()
+	brClient := clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace())
+	buildRuns := map[string]buildv1alpha1.BuildRun{}
+	resourceVersion, err := c.list(ctx, brClient, buildRuns)
+	if err != nil {
+		return err
+	}
+
+	if !c.watch {
+		return c.render(ioStreams.Out, params.Namespace(), buildRuns)
+	}
+
+	for {
+		fmt.Fprint(ioStreams.Out, clearScreen)
+		if err := c.render(ioStreams.Out, params.Namespace(), buildRuns); err != nil {
+			return err
+		}
+
+		w, err := brClient.Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion})
+		if err != nil {
+			return err
+		}
+
+		expired := false
+		for event := range w.ResultChan() {
+			if event.Type == watch.Error {
+				err := kerrors.FromObject(event.Object)
+				if !kerrors.IsResourceExpired(err) && !kerrors.IsGone(err) {
+					w.Stop()
+					return err
+				}
+				// the resource version is too old, i.e. compacted, thus the BuildRuns are listed again
+				klog.V(2).Infof("BuildRun watch resource version %q expired: %v", resourceVersion, err)
+				expired = true
+				break
+			}
+			br, ok := event.Object.(*buildv1alpha1.BuildRun)
+			if !ok {
+				continue
+			}
+			resourceVersion = br.ResourceVersion
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				buildRuns[br.Name] = *br
+			case watch.Deleted:
+				delete(buildRuns, br.Name)
+			}
+
+			fmt.Fprint(ioStreams.Out, clearScreen)
+			if err := c.render(ioStreams.Out, params.Namespace(), buildRuns); err != nil {
+				w.Stop()
+				return err
+			}
+		}
+		w.Stop()
+
+		// the watch channel is closed either because the context is done, or because the API server
+		// expired the watch, in the later case a new watch is started from the last resource version,
+		// or from a fresh list when the resource version itself has expired
+		if ctx.Err() != nil {
+			return nil
+		}
+		if expired {
+			if resourceVersion, err = c.list(ctx, brClient, buildRuns); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// list replaces the BuildRuns with the ones listed, returning the resource version of the list.
+func (c *StatsCommand) list(
+	ctx context.Context,
+	brClient buildclientsetv1alpha1.BuildRunInterface,
+	buildRuns map[string]buildv1alpha1.BuildRun,
+) (string, error) {
+	brList, err := brClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for name := range buildRuns {
+		delete(buildRuns, name)
+	}
+	for _, br := range brList.Items {
+		buildRuns[br.Name] = br
+	}
+	return brList.ResourceVersion, nil
+}
+
+// render prints the overview for the informed BuildRuns.
+func (c *StatsCommand) render(out io.Writer, namespace string, buildRuns map[string]buildv1alpha1.BuildRun) error {
+	s := summarizeBuildRuns(buildRuns, c.failures)
+
+	fmt.Fprintf(out, "BuildRuns in namespace %q (updated %s)\n\n", namespace, time.Now().Format(time.TimeOnly))
+
+	writer := tabwriter.NewWriter(out, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(writer, "RUNNING\tPENDING\tSUCCEEDED\tFAILED")
+	fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+		styles.Warning(strconv.Itoa(s.running)),
+		styles.Faint(strconv.Itoa(s.pending)),
+		styles.Success(strconv.Itoa(s.succeeded)),
+		styles.Failure(strconv.Itoa(s.failed)),
+	)
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if s.oldestPending != nil {
+		fmt.Fprintf(out, "\nOldest pending: %s (waiting %s)\n",
+			s.oldestPending.Name,
+			duration.ShortHumanDuration(time.Since(s.oldestPending.CreationTimestamp.Time)),
+		)
+	}
+
+	if len(s.recentFailures) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out, "\nRecent failures:")
+	writer = tabwriter.NewWriter(out, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(writer, "NAME\tBUILD\tREASON\tAGE")
+	for _, br := range s.recentFailures {
+		reason := ""
+		if c := br.Status.GetCondition(buildv1alpha1.Succeeded); c != nil {
+			reason = c.Reason
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+			styles.Bold(br.Name),
+			br.Spec.BuildName(),
+			styles.Failure(reason),
+			duration.ShortHumanDuration(time.Since(br.CreationTimestamp.Time)),
+		)
+	}
+	return writer.Flush()
+}
+
+// buildRunSummary aggregated view on a set of BuildRuns.
+type buildRunSummary struct {
+	running   int
+	pending   int
+	succeeded int
+	failed    int
+
+	oldestPending  *buildv1alpha1.BuildRun  // pending BuildRun created first
+	recentFailures []buildv1alpha1.BuildRun // failed BuildRuns, most recent first
+}
+
+// summarizeBuildRuns counts the BuildRuns per phase, and collects the oldest pending instance and
+// up to maxFailures recent failures.
+func summarizeBuildRuns(buildRuns map[string]buildv1alpha1.BuildRun, maxFailures int) *buildRunSummary {
+	s := &buildRunSummary{}
+	for name := range buildRuns {
+		br := buildRuns[name]
+		switch util.PhaseOf(&br) {
+		case util.PhasePending:
+			s.pending++
+			if s.oldestPending == nil || br.CreationTimestamp.Before(&s.oldestPending.CreationTimestamp) {
+				s.oldestPending = &br
+			}
+		case util.PhaseRunning:
+			s.running++
+		case util.PhaseSucceeded:
+			s.succeeded++
+		case util.PhaseFailed:
+			s.failed++
+			s.recentFailures = append(s.recentFailures, br)
+		}
+	}
+
+	sort.Slice(s.recentFailures, func(i, j int) bool {
+		return util.CompletionTimeOf(&s.recentFailures[i]).After(util.CompletionTimeOf(&s.recentFailures[j]))
+	})
+	if len(s.recentFailures) > maxFailures {
+		s.recentFailures = s.recentFailures[:maxFailures]
+	}
+	return s
+}
@@ -0,0 +1,126 @@
+package buildrun
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakekubetesting "k8s.io/client-go/testing"
+
+	"github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func buildRunWithCondition(name string, created time.Time, status corev1.ConditionStatus, reason string) v1alpha1.BuildRun {
+	br := v1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+	if status != "" {
+		br.Status.Conditions = v1alpha1.Conditions{{
+			Type:   v1alpha1.Succeeded,
+			Status: status,
+			Reason: reason,
+		}}
+	}
+	if status == corev1.ConditionUnknown && reason == "Running" {
+		br.Status.StartTime = &br.CreationTimestamp
+	}
+	return br
+}
+
+func TestSummarizeBuildRuns(t *testing.T) {
+	g := o.NewWithT(t)
+
+	now := time.Now()
+	buildRuns := map[string]v1alpha1.BuildRun{}
+	for _, br := range []v1alpha1.BuildRun{
+		buildRunWithCondition("no-condition", now.Add(-1*time.Minute), "", ""),
+		buildRunWithCondition("pending", now.Add(-10*time.Minute), corev1.ConditionUnknown, "Pending"),
+		buildRunWithCondition("running", now, corev1.ConditionUnknown, "Running"),
+		buildRunWithCondition("succeeded", now, corev1.ConditionTrue, "Succeeded"),
+		buildRunWithCondition("failed-old", now.Add(-2*time.Hour), corev1.ConditionFalse, "Failed"),
+		buildRunWithCondition("failed-new", now.Add(-1*time.Hour), corev1.ConditionFalse, "Failed"),
+		buildRunWithCondition("failed-newest", now, corev1.ConditionFalse, "BuildRunTimeout"),
+	} {
+		buildRuns[br.Name] = br
+	}
+
+	s := summarizeBuildRuns(buildRuns, 2)
+	g.Expect(s.pending).To(o.Equal(2))
+	g.Expect(s.running).To(o.Equal(1))
+	g.Expect(s.succeeded).To(o.Equal(1))
+	g.Expect(s.failed).To(o.Equal(3))
+
+	g.Expect(s.oldestPending).NotTo(o.BeNil())
+	g.Expect(s.oldestPending.Name).To(o.Equal("pending"))
+
+	g.Expect(s.recentFailures).To(o.HaveLen(2))
+	g.Expect(s.recentFailures[0].Name).To(o.Equal("failed-newest"))
+	g.Expect(s.recentFailures[1].Name).To(o.Equal("failed-new"))
+}
+
+func TestStatsWatchError(t *testing.T) {
+	expired := &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusGone, Reason: metav1.StatusReasonExpired}
+	internal := &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusInternalServerError, Reason: metav1.StatusReasonInternalError, Message: "etcd unavailable"}
+
+	tests := []struct {
+		name   string
+		status *metav1.Status
+		lists  int
+		err    string
+	}{
+		{name: "expired resource version listed again", status: expired, lists: 2},
+		{name: "other errors returned", status: internal, lists: 1, err: "etcd unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			clientset := shpfake.NewSimpleClientset()
+			lists, watches := 0, 0
+			clientset.PrependReactor("list", "buildruns", func(fakekubetesting.Action) (bool, kruntime.Object, error) {
+				lists++
+				return false, nil, nil
+			})
+			clientset.PrependWatchReactor("buildruns", func(fakekubetesting.Action) (bool, watch.Interface, error) {
+				watches++
+				w := watch.NewFakeWithChanSize(1, false)
+				if watches == 1 {
+					w.Error(tt.status)
+				} else {
+					// the second watch is only started after listing again
+					cancel()
+				}
+				w.Stop()
+				return true, w, nil
+			})
+
+			cmd := statsCmd().(*StatsCommand)
+			cmd.cmd.SetContext(ctx)
+			g.Expect(cmd.cmd.Flags().Parse([]string{"--watch"})).To(o.Succeed())
+			ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+			err := cmd.Run(params.NewParamsForTest(nil, clientset, nil, metav1.NamespaceDefault, nil, nil), &ioStreams)
+			if tt.err != "" {
+				g.Expect(err).To(o.MatchError(o.ContainSubstring(tt.err)))
+			} else {
+				g.Expect(err).To(o.BeNil())
+			}
+			g.Expect(lists).To(o.Equal(tt.lists))
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package buildrun
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/metrics"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+)
+
+// TopCommand contains data input from user for the top sub-command
+type TopCommand struct {
+	cmd *cobra.Command
+
+	name     string
+	watch    bool          // keep refreshing the usage until the build pod is done
+	interval time.Duration // interval between refreshes
+
+	client rest.Interface // raw client querying the resource metrics API, the Kubernetes clientset's by default
+}
+
+const topLongDesc = `
+Shows the live CPU and memory usage of each step of the BuildRun pod, as reported by metrics-server,
+along with the share of the step limits, helping to tune the resources of heavy builds. With --watch
+the usage is refreshed until the build pod is done. For example:
+
+	$ shp buildrun top my-app-xyz12 --watch --interval=10s
+`
+
+func topCmd() runner.SubCommand {
+	c := &TopCommand{
+		cmd: &cobra.Command{
+			Use:   "top <name> [flags]",
+			Short: "Show the live resource usage of the BuildRun steps",
+			Long:  topLongDesc,
+			Args:  cobra.ExactArgs(1),
+		},
+	}
+
+	c.cmd.Flags().BoolVarP(&c.watch, "watch", "w", false, "Keep refreshing the usage until the build pod is done")
+	c.cmd.Flags().DurationVar(&c.interval, "interval", 5*time.Second, "Interval between refreshes while watching")
+
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *TopCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *TopCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+	return nil
+}
+
+// Validate validates data input by user
+func (c *TopCommand) Validate() error {
+	if c.interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	return nil
+}
+
+// Run prints the usage of the BuildRun pod steps, once or until the pod is done
+func (c *TopCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	if c.client == nil {
+		c.client = metrics.CoreRESTClient(clientset)
+	}
+	shpClientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	ctx := c.cmd.Context()
+	if _, err = shpClientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Get(ctx, c.name, metav1.GetOptions{}); err != nil {
+		return err
+	}
+	listOpts := reactor.BuildRunPodListOptions(c.name)
+
+	for {
+		pods, err := clientset.CoreV1().Pods(params.Namespace()).List(ctx, listOpts)
+		if err != nil {
+			return err
+		}
+		pod := buildPodOf(pods.Items)
+
+		if !c.watch {
+			return c.render(ioStreams.Out, params, pod)
+		}
+		fmt.Fprint(ioStreams.Out, clearScreen)
+		if err = c.render(ioStreams.Out, params, pod); err != nil {
+			if !errors.Is(err, metrics.ErrNoMetrics) {
+				return err
+			}
+			fmt.Fprintln(ioStreams.Out, err)
+		}
+		if pod != nil && isPodDone(pod) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.interval):
+		}
+	}
+}
+
+// render prints the usage of the pod, reporting when there is no running pod instead.
+func (c *TopCommand) render(out io.Writer, params *params.Params, pod *corev1.Pod) error {
+	switch {
+	case pod == nil:
+		fmt.Fprintf(out, "BuildRun %q has no pod yet\n", c.name)
+		return nil
+	case isPodDone(pod):
+		fmt.Fprintf(out, "BuildRun %q pod %q is %s, there is no live usage\n", c.name, pod.GetName(), pod.Status.Phase)
+		return nil
+	}
+	usage, err := metrics.FetchPodUsage(c.cmd.Context(), c.client, pod)
+	if err != nil {
+		if errors.Is(err, metrics.ErrNoMetrics) && !c.watch {
+			return exitcode.Wrap(exitcode.Unavailable, err)
+		}
+		return err
+	}
+	return usage.Print(out)
+}
+
+// isPodDone tells whether the pod has succeeded or failed.
+func isPodDone(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
@@ -0,0 +1,116 @@
+package buildrun
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestTopCommand(t *testing.T) {
+	const body = `{
+		"timestamp": "2024-01-01T10:00:00Z",
+		"window": "15s",
+		"containers": [
+			{"name": "step-source-default", "usage": {"cpu": "10m", "memory": "20Mi"}},
+			{"name": "step-build-and-push", "usage": {"cpu": "500m", "memory": "512Mi"}}
+		]
+	}`
+
+	br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"}}
+	pod := func(phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      "br-pod",
+				Labels:    map[string]string{buildv1alpha1.LabelBuildRun: "br"},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "step-source-default"},
+				{Name: "step-build-and-push", Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				}}},
+			}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		status   int
+		code     int
+		expected []string
+	}{{
+		name:   "running pod",
+		pod:    pod(corev1.PodRunning),
+		status: http.StatusOK,
+		expected: []string{
+			`Pod "br-pod" usage over 15s`,
+			"source-default\t10m\t-\t\t20Mi\t-\n",
+			"build-and-push\t500m\t2 (25%)\t\t512Mi\t1Gi (50%)\n",
+		},
+	}, {
+		name:     "completed pod",
+		pod:      pod(corev1.PodSucceeded),
+		expected: []string{`BuildRun "br" pod "br-pod" is Succeeded, there is no live usage`},
+	}, {
+		name:     "no pod yet",
+		expected: []string{`BuildRun "br" has no pod yet`},
+	}, {
+		name:   "without metrics",
+		pod:    pod(corev1.PodRunning),
+		status: http.StatusNotFound,
+		code:   exitcode.Unavailable,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			clientset := fake.NewSimpleClientset()
+			if tt.pod != nil {
+				clientset = fake.NewSimpleClientset(tt.pod)
+			}
+			p := params.NewParamsForTest(clientset, shpfake.NewSimpleClientset(br), nil, metav1.NamespaceDefault, nil, nil)
+
+			cmd := topCmd().(*TopCommand)
+			cmd.cmd.SetContext(context.TODO())
+			cmd.client = &restfake.RESTClient{
+				NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+				Resp: &http.Response{
+					StatusCode: tt.status,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(body)),
+				},
+			}
+			g.Expect(cmd.Complete(p, nil, []string{"br"})).To(o.Succeed())
+			g.Expect(cmd.Validate()).To(o.Succeed())
+
+			ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+			err := cmd.Run(p, &ioStreams)
+			g.Expect(exitcode.FromError(err)).To(o.Equal(tt.code))
+			for _, expected := range tt.expected {
+				g.Expect(out.String()).To(o.ContainSubstring(expected))
+			}
+		})
+	}
+}
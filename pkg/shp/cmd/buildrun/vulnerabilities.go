@@ -0,0 +1,118 @@
+package buildrun
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/vulnerability"
+)
+
+// VulnerabilitiesCommand contains data input from user for the vulnerabilities sub-command
+type VulnerabilitiesCommand struct {
+	cmd *cobra.Command
+
+	name     string
+	failOn   string
+	noHeader bool
+
+	threshold vulnerability.Severity
+	client    rest.Interface // raw client retrieving the BuildRun, the Shipwright clientset's by default
+}
+
+const vulnerabilitiesLongDesc = `
+Shows the vulnerabilities found on the output image, as reported on the BuildRun status by build
+strategies scanning the image. With --fail-on the command exits with code 4 when vulnerabilities
+as severe as the informed severity, or more, are found. For example:
+
+	$ shp buildrun vulnerabilities my-app-xyz12 --fail-on=high
+`
+
+func vulnerabilitiesCmd() runner.SubCommand {
+	c := &VulnerabilitiesCommand{
+		cmd: &cobra.Command{
+			Use:     "vulnerabilities <name> [flags]",
+			Aliases: []string{"vulns"},
+			Short:   "Show the vulnerabilities found on the BuildRun output image",
+			Long:    vulnerabilitiesLongDesc,
+			Args:    cobra.ExactArgs(1),
+		},
+	}
+
+	c.cmd.Flags().StringVar(&c.failOn, "fail-on", "",
+		fmt.Sprintf("exit non-zero when vulnerabilities of the severity, or more severe, are found, one of %v", vulnerability.Severities))
+	c.cmd.Flags().BoolVar(&c.noHeader, "no-header", false, "Do not show columns header in list output")
+
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *VulnerabilitiesCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *VulnerabilitiesCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+	return nil
+}
+
+// Validate checks the severity informed
+func (c *VulnerabilitiesCommand) Validate() error {
+	if c.failOn == "" {
+		return nil
+	}
+	var err error
+	c.threshold, err = vulnerability.ParseSeverity(c.failOn)
+	return err
+}
+
+// Run prints the vulnerabilities reported on the BuildRun status
+func (c *VulnerabilitiesCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	if c.client == nil {
+		clientset, err := params.ShipwrightClientSet()
+		if err != nil {
+			return err
+		}
+		c.client = vulnerability.RESTClient(clientset)
+	}
+
+	vulns, err := vulnerability.Fetch(c.cmd.Context(), c.client, params.Namespace(), c.name)
+	if err != nil {
+		return err
+	}
+	if len(vulns) == 0 {
+		fmt.Fprintf(ioStreams.Out, "No vulnerabilities reported on BuildRun %q\n", c.name)
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(ioStreams.Out, 0, 8, 2, '\t', 0)
+	if !c.noHeader {
+		fmt.Fprintln(writer, "ID\tSEVERITY")
+	}
+	for _, v := range vulns {
+		fmt.Fprintf(writer, "%s\t%s\n", v.ID, strings.ToUpper(string(v.Severity)))
+	}
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(ioStreams.Out)
+	vulnerability.PrintSummary(ioStreams.Out, vulns, 0)
+
+	if c.threshold == "" {
+		return nil
+	}
+	if found := vulnerability.AtLeast(vulns, c.threshold); len(found) > 0 {
+		return exitcode.Errorf(exitcode.Vulnerable, "BuildRun %q output image has %d vulnerabilities of severity %s or higher",
+			c.name, len(found), c.threshold)
+	}
+	return nil
+}
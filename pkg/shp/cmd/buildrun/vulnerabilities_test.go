@@ -0,0 +1,81 @@
+package buildrun
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestVulnerabilitiesCommand(t *testing.T) {
+	const body = `{"status": {"output": {"vulnerabilities": [
+		{"id": "CVE-2023-0002", "severity": "medium"},
+		{"id": "CVE-2023-0001", "severity": "high"}
+	]}}}`
+
+	tests := []struct {
+		name     string
+		body     string
+		failOn   string
+		code     int
+		expected string
+	}{{
+		name:     "lists vulnerabilities",
+		body:     body,
+		expected: "CVE-2023-0001\tHIGH",
+	}, {
+		name:     "fails on severity",
+		body:     body,
+		failOn:   "high",
+		code:     exitcode.Vulnerable,
+		expected: "Vulnerabilities: 0 critical, 1 high, 1 medium, 0 low, 0 unknown",
+	}, {
+		name:   "below severity threshold",
+		body:   body,
+		failOn: "critical",
+	}, {
+		name:     "no vulnerabilities",
+		body:     `{"status": {}}`,
+		failOn:   "low",
+		expected: `No vulnerabilities reported on BuildRun "br"`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			cmd := vulnerabilitiesCmd().(*VulnerabilitiesCommand)
+			cmd.client = &restfake.RESTClient{
+				NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+				Resp: &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(tt.body)),
+				},
+			}
+			cmd.failOn = tt.failOn
+			cmd.Cmd().SetContext(context.TODO())
+
+			out := &bytes.Buffer{}
+			ioStreams := &genericclioptions.IOStreams{Out: out, ErrOut: out}
+			p := params.NewParamsForTest(nil, nil, nil, "default", nil, nil)
+
+			g.Expect(cmd.Complete(p, ioStreams, []string{"br"})).To(o.Succeed())
+			g.Expect(cmd.Validate()).To(o.Succeed())
+			err := cmd.Run(p, ioStreams)
+			g.Expect(exitcode.FromError(err)).To(o.Equal(tt.code))
+			g.Expect(out.String()).To(o.ContainSubstring(tt.expected))
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package buildrun
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cache"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// waitForDone waits for the BuildRuns to reach a terminal state, regardless of the outcome.
+const waitForDone = "done"
+
+// WaitCommand contains data input from user for the wait sub-command
+type WaitCommand struct {
+	cmd *cobra.Command
+
+	names    []string
+	selector string
+	forCond  string
+	timeout  time.Duration
+	output   string
+
+	expected corev1.ConditionStatus // expected status of the "Succeeded" condition, empty for "done"
+}
+
+// waitResult the outcome of waiting for a single BuildRun.
+type waitResult struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Met      bool   `json:"conditionMet"`
+	TimedOut bool   `json:"timedOut,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+const waitLongDesc = `
+Blocks until the informed BuildRuns, by name or label selector, reach a terminal state, waiting for
+all of them in parallel. By default any outcome is accepted, with --for=condition=Succeeded the
+BuildRuns must succeed, and with --for=condition=Succeeded=False they must fail.
+
+The exit code is 0 when all BuildRuns meet the condition, 2 when the wait times out, and 1 otherwise.
+For example:
+
+	$ shp buildrun wait my-app-xyz12 my-app-abc34 --for=condition=Succeeded --timeout=30m
+	$ shp buildrun wait --selector=build.shipwright.io/name=my-app -o json
+`
+
+func waitCmd() runner.SubCommand {
+	c := &WaitCommand{
+		cmd: &cobra.Command{
+			Use:   "wait [name...] [flags]",
+			Short: "Wait for BuildRuns to finish",
+			Long:  waitLongDesc,
+		},
+	}
+
+	c.cmd.Flags().StringVarP(&c.selector, "selector", "l", "", "label selector of the BuildRuns to wait for")
+	c.cmd.Flags().StringVar(&c.forCond, "for", waitForDone,
+		"condition to wait for, either \"done\", \"condition=Succeeded\" or \"condition=Succeeded=False\"")
+	c.cmd.Flags().DurationVar(&c.timeout, "timeout", 0, "maximum amount of time to wait, zero means no limit")
+	c.cmd.Flags().StringVarP(&c.output, "output", "o", "", "output format of the results, either empty or \"json\"")
+
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *WaitCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *WaitCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.names = args
+	return nil
+}
+
+// Validate validates data input by user
+func (c *WaitCommand) Validate() error {
+	if len(c.names) == 0 && c.selector == "" {
+		return errors.New("either BuildRun names or --selector must be informed")
+	}
+	if len(c.names) > 0 && c.selector != "" {
+		return errors.New("BuildRun names can't be informed together with --selector")
+	}
+	if c.timeout < 0 {
+		return errors.New("--timeout must not be negative")
+	}
+	if c.output != "" && c.output != "json" {
+		return fmt.Errorf("unsupported --output %q, only \"json\" is supported", c.output)
+	}
+	var err error
+	c.expected, err = parseWaitFor(c.forCond)
+	return err
+}
+
+// parseWaitFor parses the --for flag into the expected status of the "Succeeded" condition.
+func parseWaitFor(s string) (corev1.ConditionStatus, error) {
+	if s == waitForDone {
+		return "", nil
+	}
+	condition, found := strings.CutPrefix(s, "condition=")
+	if !found {
+		return "", fmt.Errorf("invalid --for %q, expected either %q or \"condition=<name>[=<status>]\"", s, waitForDone)
+	}
+	name, status, found := strings.Cut(condition, "=")
+	if !strings.EqualFold(name, string(buildv1alpha1.Succeeded)) {
+		return "", fmt.Errorf("invalid --for %q, only the %q condition is supported", s, buildv1alpha1.Succeeded)
+	}
+	if !found {
+		return corev1.ConditionTrue, nil
+	}
+	switch {
+	case strings.EqualFold(status, string(corev1.ConditionTrue)):
+		return corev1.ConditionTrue, nil
+	case strings.EqualFold(status, string(corev1.ConditionFalse)):
+		return corev1.ConditionFalse, nil
+	}
+	return "", fmt.Errorf("invalid --for %q, the condition status must be either True or False", s)
+}
+
+// Run waits for the BuildRuns in parallel, reporting each outcome as it's known
+func (c *WaitCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	ctx := c.cmd.Context()
+	// the BuildRuns are watched by a single informer, rather than polled one by one
+	brCache, err := params.Cache(ctx)
+	if err != nil {
+		return err
+	}
+	names := c.names
+	if c.selector != "" {
+		brs, err := clientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).List(ctx, metav1.ListOptions{
+			LabelSelector: c.selector,
+		})
+		if err != nil {
+			return err
+		}
+		if len(brs.Items) == 0 {
+			return fmt.Errorf("no BuildRuns found matching selector %q", c.selector)
+		}
+		for _, br := range brs.Items {
+			names = append(names, br.GetName())
+		}
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	results := make([]waitResult, len(names))
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i] = c.waitFor(ctx, brCache, params.Namespace(), name)
+			if c.output == "" {
+				lock.Lock()
+				defer lock.Unlock()
+				fmt.Fprintln(ioStreams.Out, describeWaitResult(results[i]))
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	if c.output == "json" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(ioStreams.Out, string(data))
+	}
+
+	unmet, timedOut := 0, 0
+	for _, r := range results {
+		if r.TimedOut {
+			timedOut++
+		} else if !r.Met {
+			unmet++
+		}
+	}
+	switch {
+	case timedOut > 0:
+		return exitcode.Errorf(exitcode.Timeout, "timed out waiting for %d of %d BuildRuns", timedOut, len(results))
+	case unmet > 0:
+		return exitcode.Errorf(exitcode.Failure, "%d of %d BuildRuns did not meet the condition %q", unmet, len(results), c.forCond)
+	}
+	return nil
+}
+
+// waitFor waits for a single BuildRun to reach a terminal state, and checks the expected condition.
+func (c *WaitCommand) waitFor(ctx context.Context, brCache *cache.Cache, ns, name string) waitResult {
+	result := waitResult{Name: name}
+	br, err := brCache.WaitForBuildRun(ctx, ns, name, func(br *buildv1alpha1.BuildRun) bool {
+		return br.IsDone()
+	})
+	if br != nil {
+		result.Phase = string(util.PhaseOf(br))
+		if cond := br.Status.GetCondition(buildv1alpha1.Succeeded); cond != nil {
+			result.Reason, result.Message = cond.GetReason(), cond.GetMessage()
+		}
+	}
+	if err != nil {
+		if wait.Interrupted(err) {
+			result.TimedOut = true
+		} else {
+			result.Error = err.Error()
+		}
+		return result
+	}
+
+	cond := br.Status.GetCondition(buildv1alpha1.Succeeded)
+	result.Met = c.expected == "" || (cond != nil && cond.GetStatus() == c.expected)
+	return result
+}
+
+// describeWaitResult renders the outcome of waiting for a single BuildRun in a single line.
+func describeWaitResult(r waitResult) string {
+	var b strings.Builder
+	switch {
+	case r.Error != "":
+		fmt.Fprintf(&b, "BuildRun %q could not be waited for: %s", r.Name, r.Error)
+		return b.String()
+	case r.TimedOut && r.Phase == "":
+		fmt.Fprintf(&b, "BuildRun %q timed out", r.Name)
+		return b.String()
+	case r.TimedOut:
+		fmt.Fprintf(&b, "BuildRun %q timed out while %s", r.Name, strings.ToLower(r.Phase))
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "BuildRun %q has %s", r.Name, strings.ToLower(r.Phase))
+	if r.Phase != string(util.PhaseSucceeded) && r.Reason != "" {
+		fmt.Fprintf(&b, " because of %s: %s", r.Reason, r.Message)
+	}
+	if !r.Met {
+		b.WriteString(", the condition is not met")
+	}
+	return b.String()
+}
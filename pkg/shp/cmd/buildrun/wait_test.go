@@ -0,0 +1,148 @@
+package buildrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestParseWaitFor(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(parseWaitFor("done")).To(o.BeEmpty())
+	g.Expect(parseWaitFor("condition=Succeeded")).To(o.Equal(corev1.ConditionTrue))
+	g.Expect(parseWaitFor("condition=succeeded=false")).To(o.Equal(corev1.ConditionFalse))
+	for _, invalid := range []string{"delete", "condition=Ready", "condition=Succeeded=Unknown"} {
+		_, err := parseWaitFor(invalid)
+		g.Expect(err).To(o.HaveOccurred(), invalid)
+	}
+}
+
+func TestWaitCommand(t *testing.T) {
+	newBuildRun := func(name string, status corev1.ConditionStatus, reason string) *buildv1alpha1.BuildRun {
+		br := &buildv1alpha1.BuildRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+				Labels:    map[string]string{buildv1alpha1.LabelBuild: "app"},
+			},
+		}
+		if status != "" {
+			br.Status.StartTime = &metav1.Time{Time: time.Now()}
+			br.Status.Conditions = buildv1alpha1.Conditions{{
+				Type:    buildv1alpha1.Succeeded,
+				Status:  status,
+				Reason:  reason,
+				Message: "message",
+			}}
+		}
+		return br
+	}
+	objects := []runtime.Object{
+		newBuildRun("succeeded", corev1.ConditionTrue, "Succeeded"),
+		newBuildRun("failed", corev1.ConditionFalse, "Failed"),
+		newBuildRun("running", corev1.ConditionUnknown, "Running"),
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		selector string
+		forCond  string
+		timeout  time.Duration
+		code     int
+		expected []string
+	}{{
+		name:     "any outcome",
+		args:     []string{"succeeded", "failed"},
+		forCond:  "done",
+		expected: []string{`BuildRun "succeeded" has succeeded`, `BuildRun "failed" has failed because of Failed: message`},
+	}, {
+		name:     "must succeed",
+		args:     []string{"succeeded", "failed"},
+		forCond:  "condition=Succeeded",
+		code:     exitcode.Failure,
+		expected: []string{`BuildRun "failed" has failed because of Failed: message, the condition is not met`},
+	}, {
+		name:     "must fail",
+		args:     []string{"failed"},
+		forCond:  "condition=Succeeded=False",
+		expected: []string{`BuildRun "failed" has failed`},
+	}, {
+		name:     "timeout",
+		selector: buildv1alpha1.LabelBuild + "=app",
+		forCond:  "done",
+		timeout:  100 * time.Millisecond,
+		code:     exitcode.Timeout,
+		expected: []string{`BuildRun "running" timed out while running`, `BuildRun "succeeded" has succeeded`},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			cmd := waitCmd().(*WaitCommand)
+			cmd.Cmd().SetContext(context.TODO())
+			cmd.selector, cmd.forCond, cmd.timeout = tt.selector, tt.forCond, tt.timeout
+
+			out := &bytes.Buffer{}
+			ioStreams := &genericclioptions.IOStreams{Out: out, ErrOut: out}
+			p := params.NewParamsForTest(nil, fake.NewSimpleClientset(objects...), nil, metav1.NamespaceDefault, nil, nil)
+
+			g.Expect(cmd.Complete(p, ioStreams, tt.args)).To(o.Succeed())
+			g.Expect(cmd.Validate()).To(o.Succeed())
+			err := cmd.Run(p, ioStreams)
+			g.Expect(exitcode.FromError(err)).To(o.Equal(tt.code))
+			for _, line := range tt.expected {
+				g.Expect(out.String()).To(o.ContainSubstring(line))
+			}
+		})
+	}
+
+	t.Run("json output", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		cmd := waitCmd().(*WaitCommand)
+		cmd.Cmd().SetContext(context.TODO())
+		cmd.output = "json"
+
+		out := &bytes.Buffer{}
+		ioStreams := &genericclioptions.IOStreams{Out: out, ErrOut: out}
+		p := params.NewParamsForTest(nil, fake.NewSimpleClientset(objects...), nil, metav1.NamespaceDefault, nil, nil)
+
+		g.Expect(cmd.Complete(p, ioStreams, []string{"failed", "succeeded"})).To(o.Succeed())
+		g.Expect(cmd.Validate()).To(o.Succeed())
+		g.Expect(cmd.Run(p, ioStreams)).To(o.Succeed())
+
+		results := []waitResult{}
+		g.Expect(json.Unmarshal(out.Bytes(), &results)).To(o.Succeed())
+		g.Expect(results).To(o.Equal([]waitResult{
+			{Name: "failed", Phase: "Failed", Reason: "Failed", Message: "message", Met: true},
+			{Name: "succeeded", Phase: "Succeeded", Reason: "Succeeded", Message: "message", Met: true},
+		}))
+	})
+
+	t.Run("names and selector are exclusive", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		cmd := waitCmd().(*WaitCommand)
+		cmd.selector = "app=x"
+		g.Expect(cmd.Complete(nil, nil, []string{"br"})).To(o.Succeed())
+		g.Expect(cmd.Validate()).ToNot(o.Succeed())
+	})
+}
@@ -0,0 +1,443 @@
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+	"github.com/shipwright-io/cli/pkg/shp/suggestion"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// Status the outcome of a single check.
+type Status string
+
+const (
+	// StatusPass the check succeeded.
+	StatusPass Status = "pass"
+	// StatusWarn the check found a problem which may not prevent the build.
+	StatusWarn Status = "warn"
+	// StatusFail the check found a problem which prevents the build.
+	StatusFail Status = "fail"
+	// StatusSkip the check was not executed.
+	StatusSkip Status = "skip"
+)
+
+// defaultServiceAccounts the service accounts employed by the build controller, in order, when the
+// BuildRun does not inform one.
+var defaultServiceAccounts = []string{"pipeline", "default"}
+
+// pingTimeout maximum amount of time to check the push permission on the registry.
+const pingTimeout = 30 * time.Second
+
+// Result the outcome of a single check.
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// CheckCommand contains data input from user for the check command
+type CheckCommand struct {
+	cmd *cobra.Command
+
+	buildName      string                                                        // Build whose settings are checked, optional
+	strategy       buildv1alpha1.Strategy                                        // strategy to check
+	outputImage    string                                                        // output image to check
+	outputSecret   string                                                        // output credentials secret to check
+	serviceAccount string                                                        // service account to check
+	pingRegistry   bool                                                          // check the push permission on the registry
+	output         string                                                        // output format, either empty or "json"
+	pushCheck      func(name.Reference, authn.Keychain, http.RoundTripper) error // replaceable for testing purposes
+}
+
+const checkLongDesc = `
+Diagnoses the common setup problems before running a build: whether the cluster is reachable, the
+Shipwright custom resources are installed, the build strategy exists, the output registry secret is
+present and holds credentials for the output image registry, and the service account exists.
+
+The settings are taken from the informed Build, the flags take precedence. With --ping-registry the
+push permission is checked against the output image registry, using the secret credentials.
+
+Each check passes, warns, fails or is skipped, and the exit code is 1 when any check fails. For
+example:
+
+	$ shp check my-app
+	$ shp check --strategy-name=buildah --output-image=ghcr.io/org/app --output-credentials-secret=push
+	$ shp check my-app --ping-registry -o json
+`
+
+// Command returns the "check" command of Shipwright CLI.
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	cmd := runner.NewRunner(p, ioStreams, checkCmd()).Cmd()
+	cmd.Annotations = map[string]string{
+		"commandType": "main",
+	}
+	return cmd
+}
+
+func checkCmd() runner.SubCommand {
+	c := &CheckCommand{
+		cmd: &cobra.Command{
+			Use:     "check [build-name] [flags]",
+			Aliases: []string{"doctor"},
+			Short:   "Diagnose the setup before running a build",
+			Long:    checkLongDesc,
+			Args:    cobra.MaximumNArgs(1),
+		},
+		strategy:  buildv1alpha1.Strategy{Kind: new(buildv1alpha1.BuildStrategyKind)},
+		pushCheck: remote.CheckPushPermission,
+	}
+
+	f := c.cmd.Flags()
+	f.StringVar(&c.strategy.Name, flags.StrategyNameFlag, "", "name of the build strategy to check")
+	f.Var(flags.NewStrategyKindValue(c.strategy.Kind), flags.StrategyKindFlag,
+		"kind of the build strategy, either ClusterBuildStrategy (default) or BuildStrategy")
+	f.StringVar(&c.outputImage, flags.OutputImageFlag, "", "output image whose registry credentials are checked")
+	f.StringVar(&c.outputSecret, flags.OutputCredentialsSecretFlag, "", "name of the secret with the output registry credentials")
+	f.StringVar(&c.serviceAccount, flags.ServiceAccountNameFlag, "", "name of the service account running the build")
+	f.BoolVar(&c.pingRegistry, "ping-registry", false, "check the push permission on the output image registry")
+	f.StringVarP(&c.output, "output", "o", "", "output format, either empty or \"json\"")
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *CheckCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *CheckCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	if len(args) > 0 {
+		c.buildName = args[0]
+	}
+	return nil
+}
+
+// Validate validates data input by user
+func (c *CheckCommand) Validate() error {
+	if c.output != "" && c.output != "json" {
+		return fmt.Errorf("unsupported --output %q, only \"json\" is supported", c.output)
+	}
+	return nil
+}
+
+// Run executes the checks in order, the checks depending on a failed one are skipped.
+func (c *CheckCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	results := c.check(p)
+
+	if c.output == "json" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(ioStreams.Out, string(data))
+	} else {
+		printResults(ioStreams.Out, results)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Status == StatusFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return exitcode.Errorf(exitcode.Failure, "%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// check executes all checks, returning their results.
+func (c *CheckCommand) check(p *params.Params) []Result {
+	ctx := c.cmd.Context()
+	ns := p.Namespace()
+	results := []Result{}
+
+	kclientset, err := p.ClientSet()
+	if err == nil {
+		results = append(results, checkCluster(kclientset))
+	} else {
+		results = append(results, Result{Name: "cluster", Status: StatusFail, Message: err.Error()})
+	}
+	if results[0].Status == StatusFail {
+		return append(results, Result{Name: "crds", Status: StatusSkip, Message: "the cluster is not reachable"})
+	}
+	results = append(results, checkCRDs(kclientset))
+	if results[1].Status == StatusFail {
+		return results
+	}
+
+	clientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return append(results, Result{Name: "build", Status: StatusFail, Message: err.Error()})
+	}
+	if c.buildName != "" {
+		result := c.checkBuild(ctx, clientset, ns)
+		results = append(results, result)
+		if result.Status == StatusFail {
+			return results
+		}
+	}
+
+	results = append(results, c.checkStrategy(ctx, clientset, ns))
+	secret, keychain := c.checkOutputSecret(ctx, kclientset, ns)
+	results = append(results, secret)
+	if secret.Status != StatusFail {
+		results = append(results, c.checkRegistry(ctx, keychain))
+	} else {
+		results = append(results, Result{Name: "registry", Status: StatusSkip, Message: "the output secret is not usable"})
+	}
+	return append(results, c.checkServiceAccount(ctx, kclientset, ns))
+}
+
+// checkCluster checks the cluster is reachable.
+func checkCluster(clientset kubernetes.Interface) Result {
+	info, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return Result{Name: "cluster", Status: StatusFail, Message: fmt.Sprintf("unable to reach the cluster: %s", err)}
+	}
+	return Result{Name: "cluster", Status: StatusPass, Message: fmt.Sprintf("reachable, Kubernetes %s", info.GitVersion)}
+}
+
+// checkCRDs checks the Shipwright resources employed by the CLI are served.
+func checkCRDs(clientset kubernetes.Interface) Result {
+	gv := buildv1alpha1.SchemeGroupVersion.String()
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion(gv)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return Result{Name: "crds", Status: StatusFail, Message: fmt.Sprintf("%s is not served, is Shipwright Build installed?", gv)}
+		}
+		return Result{Name: "crds", Status: StatusFail, Message: err.Error()}
+	}
+	served := map[string]bool{}
+	for _, r := range resources.APIResources {
+		served[r.Name] = true
+	}
+	missing := []string{}
+	for _, r := range []string{"builds", "buildruns", "buildstrategies", "clusterbuildstrategies"} {
+		if !served[r] {
+			missing = append(missing, r)
+		}
+	}
+	if len(missing) > 0 {
+		return Result{Name: "crds", Status: StatusFail, Message: fmt.Sprintf("%s does not serve %s", gv, strings.Join(missing, ", "))}
+	}
+	return Result{Name: "crds", Status: StatusPass, Message: fmt.Sprintf("%s is served", gv)}
+}
+
+// checkBuild checks the Build exists and is registered, its settings are employed by the next
+// checks, unless informed by flags.
+func (c *CheckCommand) checkBuild(ctx context.Context, clientset buildclientset.Interface, ns string) Result {
+	b, err := clientset.ShipwrightV1alpha1().Builds(ns).Get(ctx, c.buildName, metav1.GetOptions{})
+	if err != nil {
+		return Result{Name: "build", Status: StatusFail, Message: err.Error()}
+	}
+	flagSet := c.cmd.Flags()
+	if !flagSet.Changed(flags.StrategyNameFlag) {
+		c.strategy.Name = b.Spec.Strategy.Name
+	}
+	if !flagSet.Changed(flags.StrategyKindFlag) && b.Spec.Strategy.Kind != nil {
+		*c.strategy.Kind = *b.Spec.Strategy.Kind
+	}
+	if !flagSet.Changed(flags.OutputImageFlag) {
+		c.outputImage = b.Spec.Output.Image
+	}
+	if !flagSet.Changed(flags.OutputCredentialsSecretFlag) && b.Spec.Output.Credentials != nil {
+		c.outputSecret = b.Spec.Output.Credentials.Name
+	}
+
+	if b.Status.Registered != nil && *b.Status.Registered == corev1.ConditionFalse {
+		return Result{Name: "build", Status: StatusWarn, Message: fmt.Sprintf("Build %q is not registered because of %s",
+			b.GetName(), reason(b.Status.Reason, b.Status.Message))}
+	}
+	return Result{Name: "build", Status: StatusPass, Message: fmt.Sprintf("Build %q found", b.GetName())}
+}
+
+// checkStrategy checks the build strategy exists, suggesting similar names otherwise.
+func (c *CheckCommand) checkStrategy(ctx context.Context, clientset buildclientset.Interface, ns string) Result {
+	if c.strategy.Name == "" {
+		return Result{Name: "strategy", Status: StatusSkip, Message: "no build strategy informed"}
+	}
+	kind := buildv1alpha1.ClusterBuildStrategyKind
+	if *c.strategy.Kind != "" {
+		kind = *c.strategy.Kind
+	}
+	_, err := util.GetBuildStrategy(ctx, clientset, ns, buildv1alpha1.Strategy{Name: c.strategy.Name, Kind: &kind})
+	if err == nil {
+		return Result{Name: "strategy", Status: StatusPass, Message: fmt.Sprintf("%s %q found", kind, c.strategy.Name)}
+	}
+	if !kerrors.IsNotFound(err) {
+		return Result{Name: "strategy", Status: StatusFail, Message: err.Error()}
+	}
+	msg := fmt.Sprintf("%s %q not found", kind, c.strategy.Name)
+	if names, listErr := util.ListBuildStrategyNames(ctx, clientset, ns, kind); listErr == nil {
+		if suggestions := suggestion.SuggestionsFor(c.strategy.Name, names); len(suggestions) > 0 {
+			msg = fmt.Sprintf("%s, did you mean %q?", msg, suggestions[0])
+		}
+	}
+	return Result{Name: "strategy", Status: StatusFail, Message: msg}
+}
+
+// checkOutputSecret checks the output secret exists, is a docker-registry secret, and holds
+// credentials for the output image registry. Returns the keychain of the secret, anonymous when
+// not informed.
+func (c *CheckCommand) checkOutputSecret(ctx context.Context, clientset kubernetes.Interface, ns string) (Result, authn.Keychain) {
+	if c.outputSecret == "" {
+		return Result{Name: "output-secret", Status: StatusSkip, Message: "no output credentials secret informed"}, authn.NewMultiKeychain()
+	}
+	secret, err := clientset.CoreV1().Secrets(ns).Get(ctx, c.outputSecret, metav1.GetOptions{})
+	if err != nil {
+		return Result{Name: "output-secret", Status: StatusFail, Message: err.Error()}, nil
+	}
+	if secret.Type != corev1.SecretTypeDockerConfigJson {
+		return Result{Name: "output-secret", Status: StatusFail, Message: fmt.Sprintf("secret %q is of type %q, expected %q",
+			c.outputSecret, secret.Type, corev1.SecretTypeDockerConfigJson)}, nil
+	}
+	keychain, err := registry.Keychain(ctx, registry.AuthOptions{
+		Source:     registry.AuthSecret,
+		Clientset:  clientset,
+		Namespace:  ns,
+		SecretName: c.outputSecret,
+	})
+	if err != nil {
+		return Result{Name: "output-secret", Status: StatusFail, Message: err.Error()}, nil
+	}
+	if c.outputImage == "" {
+		return Result{Name: "output-secret", Status: StatusPass, Message: fmt.Sprintf("secret %q found", c.outputSecret)}, keychain
+	}
+	ref, err := name.ParseReference(c.outputImage)
+	if err != nil {
+		return Result{Name: "output-secret", Status: StatusFail, Message: fmt.Sprintf("invalid output image: %s", err)}, nil
+	}
+	auth, err := keychain.Resolve(ref.Context())
+	if err != nil {
+		return Result{Name: "output-secret", Status: StatusFail, Message: err.Error()}, nil
+	}
+	if auth == authn.Anonymous {
+		return Result{Name: "output-secret", Status: StatusWarn, Message: fmt.Sprintf("secret %q has no credentials for registry %q",
+			c.outputSecret, ref.Context().RegistryStr())}, keychain
+	}
+	return Result{Name: "output-secret", Status: StatusPass, Message: fmt.Sprintf("secret %q holds credentials for registry %q",
+		c.outputSecret, ref.Context().RegistryStr())}, keychain
+}
+
+// checkRegistry checks the push permission on the output image registry, when requested.
+func (c *CheckCommand) checkRegistry(ctx context.Context, keychain authn.Keychain) Result {
+	switch {
+	case !c.pingRegistry:
+		return Result{Name: "registry", Status: StatusSkip, Message: "--ping-registry not informed"}
+	case c.outputImage == "":
+		return Result{Name: "registry", Status: StatusSkip, Message: "no output image informed"}
+	}
+	ref, err := name.ParseReference(c.outputImage)
+	if err != nil {
+		return Result{Name: "registry", Status: StatusFail, Message: fmt.Sprintf("invalid output image: %s", err)}
+	}
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	if err = c.pushCheck(ref, keychain, &contextTransport{ctx: ctx, next: http.DefaultTransport}); err != nil {
+		return Result{Name: "registry", Status: StatusFail, Message: fmt.Sprintf("unable to push to %q: %s", ref.Context().Name(), err)}
+	}
+	return Result{Name: "registry", Status: StatusPass, Message: fmt.Sprintf("allowed to push to %q", ref.Context().Name())}
+}
+
+// checkServiceAccount checks the informed service account exists, or one of the defaults employed
+// by the build controller.
+func (c *CheckCommand) checkServiceAccount(ctx context.Context, clientset kubernetes.Interface, ns string) Result {
+	candidates := defaultServiceAccounts
+	if c.serviceAccount != "" {
+		candidates = []string{c.serviceAccount}
+	}
+	for _, sa := range candidates {
+		_, err := clientset.CoreV1().ServiceAccounts(ns).Get(ctx, sa, metav1.GetOptions{})
+		if err == nil {
+			return Result{Name: "service-account", Status: StatusPass, Message: fmt.Sprintf("service account %q found", sa)}
+		}
+		if !kerrors.IsNotFound(err) {
+			return Result{Name: "service-account", Status: StatusFail, Message: err.Error()}
+		}
+	}
+	return Result{Name: "service-account", Status: StatusFail, Message: fmt.Sprintf("service account %s not found in namespace %q",
+		strings.Join(quote(candidates), " or "), ns)}
+}
+
+// quote renders each string quoted.
+func quote(values []string) []string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", v))
+	}
+	return quoted
+}
+
+// contextTransport attaches the context to the requests, bounding the registry calls.
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+// RoundTrip executes the request with the context.
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
+
+// printResults renders a line per check, followed by the summary.
+func printResults(out io.Writer, results []Result) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	counts := map[Status]int{}
+	for _, r := range results {
+		counts[r.Status]++
+		var icon string
+		switch r.Status {
+		case StatusPass:
+			icon = styles.Success("✔")
+		case StatusWarn:
+			icon = styles.Warning("!")
+		case StatusFail:
+			icon = styles.Failure("✖")
+		default:
+			icon = styles.Faint("-")
+		}
+		fmt.Fprintf(w, "%s %s\t%s\n", icon, r.Name, r.Message)
+	}
+	w.Flush()
+	fmt.Fprintf(out, "\n%d passed, %d warnings, %d failed, %d skipped\n",
+		counts[StatusPass], counts[StatusWarn], counts[StatusFail], counts[StatusSkip])
+}
+
+// reason renders the Build status reason and message, which are optional.
+func reason(r *buildv1alpha1.BuildReason, msg *string) string {
+	s := "unknown reason"
+	if r != nil {
+		s = string(*r)
+	}
+	if msg != nil && *msg != "" {
+		s = fmt.Sprintf("%s: %s", s, *msg)
+	}
+	return s
+}
@@ -0,0 +1,148 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestCheckCommand(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	clusterKind := buildv1alpha1.ClusterBuildStrategyKind
+
+	build := &buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "app"},
+		Spec: buildv1alpha1.BuildSpec{
+			Strategy: buildv1alpha1.Strategy{Name: "buildah", Kind: &clusterKind},
+			Output: buildv1alpha1.Image{
+				Image:       "registry.local/org/app:latest",
+				Credentials: &corev1.LocalObjectReference{Name: "push"},
+			},
+		},
+	}
+	strategy := &buildv1alpha1.ClusterBuildStrategy{ObjectMeta: metav1.ObjectMeta{Name: "buildah"}}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "push"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"registry.local":{"username":"u","password":"p"}}}`),
+		},
+	}
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "pipeline"}}
+
+	newClientset := func(crds bool, objects ...runtime.Object) *fake.Clientset {
+		clientset := fake.NewSimpleClientset(objects...)
+		if crds {
+			clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+				GroupVersion: buildv1alpha1.SchemeGroupVersion.String(),
+				APIResources: []metav1.APIResource{
+					{Name: "builds"}, {Name: "buildruns"}, {Name: "buildstrategies"}, {Name: "clusterbuildstrategies"},
+				},
+			}}
+		}
+		return clientset
+	}
+
+	run := func(t *testing.T, p *params.Params, pushErr error, args ...string) (string, []string, error) {
+		g := o.NewWithT(t)
+		cmd := checkCmd().(*CheckCommand)
+		cmd.cmd.SetContext(context.TODO())
+		pushed := []string{}
+		cmd.pushCheck = func(ref name.Reference, _ authn.Keychain, _ http.RoundTripper) error {
+			pushed = append(pushed, ref.Context().Name())
+			return pushErr
+		}
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		g.Expect(cmd.Complete(p, nil, cmd.cmd.Flags().Args())).To(o.Succeed())
+		g.Expect(cmd.Validate()).To(o.Succeed())
+		out := &bytes.Buffer{}
+		err := cmd.Run(p, &genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}})
+		return out.String(), pushed, err
+	}
+
+	statuses := func(g *o.WithT, out string) map[string]Status {
+		results := []Result{}
+		g.Expect(json.Unmarshal([]byte(out), &results)).To(o.Succeed())
+		m := map[string]Status{}
+		for _, r := range results {
+			m[r.Name] = r.Status
+		}
+		return m
+	}
+
+	t.Run("settings taken from the Build", func(t *testing.T) {
+		g := o.NewWithT(t)
+		p := params.NewParamsForTest(newClientset(true, secret, sa), shpfake.NewSimpleClientset(build, strategy), nil, ns, nil, nil)
+		out, pushed, err := run(t, p, nil, "app", "--ping-registry", "-o", "json")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(pushed).To(o.Equal([]string{"registry.local/org/app"}))
+		g.Expect(statuses(g, out)).To(o.Equal(map[string]Status{
+			"cluster":         StatusPass,
+			"crds":            StatusPass,
+			"build":           StatusPass,
+			"strategy":        StatusPass,
+			"output-secret":   StatusPass,
+			"registry":        StatusPass,
+			"service-account": StatusPass,
+		}))
+	})
+
+	t.Run("flags take precedence over the Build", func(t *testing.T) {
+		g := o.NewWithT(t)
+		p := params.NewParamsForTest(newClientset(true, secret, sa), shpfake.NewSimpleClientset(build, strategy), nil, ns, nil, nil)
+		out, pushed, err := run(t, p, errors.New("denied"), "app", "--strategy-name=bildah",
+			"--output-image=ghcr.io/org/app", "--sa-name=builder", "--ping-registry")
+		g.Expect(err).NotTo(o.BeNil())
+		g.Expect(exitcode.FromError(err)).To(o.Equal(exitcode.Failure))
+		g.Expect(pushed).To(o.Equal([]string{"ghcr.io/org/app"}))
+		g.Expect(out).To(o.ContainSubstring(`ClusterBuildStrategy "bildah" not found, did you mean "buildah"?`))
+		g.Expect(out).To(o.ContainSubstring(`secret "push" has no credentials for registry "ghcr.io"`))
+		g.Expect(out).To(o.ContainSubstring(`unable to push to "ghcr.io/org/app": denied`))
+		g.Expect(out).To(o.ContainSubstring(`service account "builder" not found in namespace "default"`))
+		g.Expect(out).To(o.HaveSuffix("\n3 passed, 1 warnings, 3 failed, 0 skipped\n"))
+	})
+
+	t.Run("without a Build", func(t *testing.T) {
+		g := o.NewWithT(t)
+		p := params.NewParamsForTest(newClientset(true), shpfake.NewSimpleClientset(), nil, ns, nil, nil)
+		out, pushed, err := run(t, p, nil, "-o", "json", "--output-credentials-secret=push")
+		g.Expect(err).NotTo(o.BeNil())
+		g.Expect(pushed).To(o.BeEmpty())
+		g.Expect(statuses(g, out)).To(o.Equal(map[string]Status{
+			"cluster":         StatusPass,
+			"crds":            StatusPass,
+			"strategy":        StatusSkip,
+			"output-secret":   StatusFail,
+			"registry":        StatusSkip,
+			"service-account": StatusFail,
+		}))
+	})
+
+	t.Run("Shipwright not installed", func(t *testing.T) {
+		g := o.NewWithT(t)
+		p := params.NewParamsForTest(newClientset(false), shpfake.NewSimpleClientset(), nil, ns, nil, nil)
+		out, _, err := run(t, p, nil, "app")
+		g.Expect(err).NotTo(o.BeNil())
+		g.Expect(out).To(o.ContainSubstring("shipwright.io/v1alpha1 is not served, is Shipwright Build installed?"))
+		g.Expect(out).To(o.HaveSuffix("\n1 passed, 0 warnings, 1 failed, 0 skipped\n"))
+	})
+}
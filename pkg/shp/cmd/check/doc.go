@@ -0,0 +1,3 @@
+// Package check contains types and functions for the check cobra command, diagnosing the common
+// setup problems before running a build.
+package check
@@ -0,0 +1,26 @@
+package cleanup
+
+import (
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// Command represents "shp cleanup" sub-command.
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Remove the leftovers of deleted BuildRuns",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	command.AddCommand(
+		runner.NewRunner(p, ioStreams, serviceAccountsCmd()).Cmd(),
+	)
+	return command
+}
@@ -0,0 +1,3 @@
+// Package cleanup contains types and functions for the cleanup cobra command, removing the leftovers
+// of deleted BuildRuns.
+package cleanup
@@ -0,0 +1,141 @@
+package cleanup
+
+import (
+	"fmt"
+	"sort"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// ServiceAccountsCommand contains data input from user for the cleanup serviceaccounts sub-command
+type ServiceAccountsCommand struct {
+	cmd *cobra.Command
+
+	dryRun bool
+}
+
+const serviceAccountsLongDesc = `
+Removes the ServiceAccounts generated for BuildRuns created with --sa-generate which outlived their
+BuildRun. The build controller labels the generated ServiceAccounts with the BuildRun name, and
+removes them once the BuildRun is done, but they are left behind when the BuildRun is deleted
+before, or the controller is interrupted. A ServiceAccount is orphaned when its BuildRun no longer
+exists, or was recreated under the same name. For example:
+
+	$ shp cleanup serviceaccounts --dry-run
+`
+
+func serviceAccountsCmd() runner.SubCommand {
+	c := &ServiceAccountsCommand{
+		cmd: &cobra.Command{
+			Use:     "serviceaccounts [flags]",
+			Aliases: []string{"serviceaccount", "sa"},
+			Short:   "Remove the generated ServiceAccounts of deleted BuildRuns",
+			Long:    serviceAccountsLongDesc,
+			Args:    cobra.NoArgs,
+		},
+	}
+	c.cmd.Flags().BoolVar(&c.dryRun, "dry-run", false, "show the orphaned ServiceAccounts without removing them")
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *ServiceAccountsCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *ServiceAccountsCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	return nil
+}
+
+// Validate validates data input by user
+func (c *ServiceAccountsCommand) Validate() error {
+	return nil
+}
+
+// Run finds the generated ServiceAccounts whose BuildRun is gone, and removes them
+func (c *ServiceAccountsCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	shpClientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	ctx := c.cmd.Context()
+	ns := params.Namespace()
+	sas, err := clientset.CoreV1().ServiceAccounts(ns).List(ctx, metav1.ListOptions{LabelSelector: buildv1alpha1.LabelBuildRun})
+	if err != nil {
+		return err
+	}
+	brs, err := shpClientset.ShipwrightV1alpha1().BuildRuns(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	existing := map[string]types.UID{}
+	for _, br := range brs.Items {
+		existing[br.GetName()] = br.GetUID()
+	}
+
+	orphans := orphanedServiceAccounts(sas.Items, existing)
+	if len(orphans) == 0 {
+		fmt.Fprintf(ioStreams.Out, "No orphaned generated ServiceAccounts found in namespace %q\n", ns)
+		return nil
+	}
+	for i := range orphans {
+		sa := &orphans[i]
+		buildRun := sa.GetLabels()[buildv1alpha1.LabelBuildRun]
+		if c.dryRun {
+			fmt.Fprintf(ioStreams.Out, "ServiceAccount %q of BuildRun %q would be removed\n", sa.GetName(), buildRun)
+			continue
+		}
+		uid := sa.GetUID()
+		err = clientset.CoreV1().ServiceAccounts(ns).Delete(ctx, sa.GetName(), metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &uid},
+		})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("unable to remove ServiceAccount %q: %w", sa.GetName(), err)
+		}
+		fmt.Fprintf(ioStreams.Out, "ServiceAccount %q of BuildRun %q removed\n", sa.GetName(), buildRun)
+	}
+	return nil
+}
+
+// orphanedServiceAccounts returns the generated ServiceAccounts, sorted by name, whose BuildRun
+// doesn't exist, or is not the one owning them.
+func orphanedServiceAccounts(sas []corev1.ServiceAccount, existing map[string]types.UID) []corev1.ServiceAccount {
+	orphans := []corev1.ServiceAccount{}
+	for _, sa := range sas {
+		buildRun := sa.GetLabels()[buildv1alpha1.LabelBuildRun]
+		if buildRun == "" {
+			continue
+		}
+		uid, found := existing[buildRun]
+		if !found {
+			orphans = append(orphans, sa)
+			continue
+		}
+		for _, owner := range sa.GetOwnerReferences() {
+			if owner.Kind == "BuildRun" && owner.Name == buildRun && owner.UID != uid {
+				orphans = append(orphans, sa)
+				break
+			}
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].GetName() < orphans[j].GetName()
+	})
+	return orphans
+}
@@ -0,0 +1,74 @@
+package cleanup
+
+import (
+	"context"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestServiceAccountsCommand(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+
+	sa := func(name, buildRun string, owner types.UID) *corev1.ServiceAccount {
+		sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+		if buildRun != "" {
+			sa.Labels = map[string]string{buildv1alpha1.LabelBuildRun: buildRun}
+		}
+		if owner != "" {
+			sa.OwnerReferences = []metav1.OwnerReference{{Kind: "BuildRun", Name: buildRun, UID: owner}}
+		}
+		return sa
+	}
+	clientset := fake.NewSimpleClientset(
+		sa("running-sa", "running", "uid-running"),
+		sa("deleted-sa", "deleted", "uid-deleted"),
+		sa("recreated-sa", "recreated", "uid-old"),
+		sa("pipeline", "", ""),
+	)
+	shpClientset := shpfake.NewSimpleClientset(
+		&buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "running", UID: "uid-running"}},
+		&buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "recreated", UID: "uid-new"}},
+	)
+	p := params.NewParamsForTest(clientset, shpClientset, nil, ns, nil, nil)
+
+	run := func(args ...string) string {
+		cmd := serviceAccountsCmd().(*ServiceAccountsCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+		return out.String()
+	}
+	names := func() []string {
+		sas, err := clientset.CoreV1().ServiceAccounts(ns).List(context.TODO(), metav1.ListOptions{})
+		g.Expect(err).To(o.BeNil())
+		names := []string{}
+		for _, sa := range sas.Items {
+			names = append(names, sa.Name)
+		}
+		return names
+	}
+
+	g.Expect(run("--dry-run")).To(o.Equal(`ServiceAccount "deleted-sa" of BuildRun "deleted" would be removed
+ServiceAccount "recreated-sa" of BuildRun "recreated" would be removed
+`))
+	g.Expect(names()).To(o.HaveLen(4))
+
+	g.Expect(run()).To(o.ContainSubstring(`ServiceAccount "deleted-sa" of BuildRun "deleted" removed`))
+	g.Expect(names()).To(o.ConsistOf("running-sa", "pipeline"))
+
+	g.Expect(run()).To(o.Equal("No orphaned generated ServiceAccounts found in namespace \"default\"\n"))
+}
@@ -0,0 +1,112 @@
+package completion
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// Shells supported by the completion scripts.
+const (
+	Bash       = "bash"
+	Zsh        = "zsh"
+	Fish       = "fish"
+	PowerShell = "powershell"
+)
+
+// Shells the shells supported, in the order shown to the user.
+var Shells = []string{Bash, Zsh, Fish, PowerShell}
+
+// noDescriptionsFlag command-line flag.
+const noDescriptionsFlag = "no-descriptions"
+
+// Command returns the "completion" command of Shipwright CLI, replacing the cobra default command
+// with the same script printing subcommands plus the installation one.
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "completion",
+		Short: "Generate or install the shell completion scripts",
+		Long: `
+Generates the shp completion script for bash, zsh, fish or powershell, printing it for manual
+installation, or installs it on the user shell profile with "shp completion install".
+`,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	for _, shell := range Shells {
+		command.AddCommand(runner.NewRunner(p, ioStreams, scriptCmd(shell)).Cmd())
+	}
+	command.AddCommand(runner.NewRunner(p, ioStreams, installCmd()).Cmd())
+	return command
+}
+
+// ScriptCommand contains data input from user for the completion script subcommands
+type ScriptCommand struct {
+	cmd *cobra.Command
+
+	shell          string
+	noDescriptions bool
+}
+
+func scriptCmd(shell string) runner.SubCommand {
+	c := &ScriptCommand{
+		cmd: &cobra.Command{
+			Use:   shell,
+			Short: fmt.Sprintf("Print the completion script for %s", shell),
+			Args:  cobra.NoArgs,
+		},
+		shell: shell,
+	}
+	c.cmd.Flags().BoolVar(&c.noDescriptions, noDescriptionsFlag, false, "disable the completion descriptions")
+	return c
+}
+
+// Cmd returns cobra command object of the completion script subcommand
+func (c *ScriptCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *ScriptCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	return nil
+}
+
+// Validate validates data input by user
+func (c *ScriptCommand) Validate() error {
+	return nil
+}
+
+// Run prints the completion script
+func (c *ScriptCommand) Run(_ *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	return writeScript(c.cmd.Root(), c.shell, !c.noDescriptions, ioStreams.Out)
+}
+
+// writeScript generates the completion script of the root command for the informed shell.
+func writeScript(root *cobra.Command, shell string, descriptions bool, w io.Writer) error {
+	switch shell {
+	case Bash:
+		return root.GenBashCompletionV2(w, descriptions)
+	case Zsh:
+		if descriptions {
+			return root.GenZshCompletion(w)
+		}
+		return root.GenZshCompletionNoDesc(w)
+	case Fish:
+		return root.GenFishCompletion(w, descriptions)
+	case PowerShell:
+		if descriptions {
+			return root.GenPowerShellCompletionWithDesc(w)
+		}
+		return root.GenPowerShellCompletion(w)
+	default:
+		return fmt.Errorf("unsupported shell %q, one of: %s", shell, strings.Join(Shells, "|"))
+	}
+}
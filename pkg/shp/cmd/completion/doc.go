@@ -0,0 +1,3 @@
+// Package completion contains types and functions for the completion cobra command, printing the
+// shell completion scripts and installing them on the user shell profile.
+package completion
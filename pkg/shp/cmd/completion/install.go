@@ -0,0 +1,246 @@
+package completion
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// profileMarker comment preceding the lines registering the completion on the shell profile, thus
+// the registration is done only once.
+const profileMarker = "# shp shell completion"
+
+// environment lookups, replaced on testing.
+var (
+	getenv  = os.Getenv
+	homeDir = os.UserHomeDir
+	goos    = runtime.GOOS
+)
+
+// installPlan where the completion script is written, and how the shell profile loads it.
+type installPlan struct {
+	shell        string
+	scriptPath   string   // location of the completion script
+	profilePath  string   // shell profile loading the script, empty when the shell does it by itself
+	profileLines []string // lines appended to the shell profile
+}
+
+// InstallCommand contains data input from user for the completion install subcommand
+type InstallCommand struct {
+	cmd *cobra.Command
+
+	shell          string
+	dryRun         bool
+	noDescriptions bool
+}
+
+func installCmd() runner.SubCommand {
+	c := &InstallCommand{
+		cmd: &cobra.Command{
+			Use:       "install [bash|zsh|fish|powershell] [flags]",
+			Short:     "Install the completion script on the user shell profile",
+			Args:      cobra.MaximumNArgs(1),
+			ValidArgs: Shells,
+			Long: `
+Writes the completion script for the informed shell, by default the one on the SHELL environment
+variable, and registers it on the user shell profile when the shell doesn't load it by itself:
+
+	bash        "$XDG_DATA_HOME/bash-completion/completions/shp", sourced by "~/.bashrc"
+	zsh         "$XDG_DATA_HOME/zsh/site-functions/_shp", added to the "fpath" on "~/.zshrc"
+	fish        "$XDG_CONFIG_HOME/fish/completions/shp.fish", loaded by fish
+	powershell  "shp-completion.ps1" next to the PowerShell profile, dot-sourced by it
+
+The profile is changed only once, installing again refreshes the script. Use --dry-run to show
+what would be done.
+`,
+		},
+	}
+	c.cmd.Flags().BoolVar(&c.dryRun, "dry-run", false, "show what would be done without changing any file")
+	c.cmd.Flags().BoolVar(&c.noDescriptions, noDescriptionsFlag, false, "disable the completion descriptions")
+	return c
+}
+
+// Cmd returns cobra command object of the completion install subcommand
+func (c *InstallCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete takes the informed shell, or detects it
+func (c *InstallCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	if len(args) > 0 {
+		c.shell = args[0]
+		return nil
+	}
+	var err error
+	c.shell, err = detectShell()
+	return err
+}
+
+// Validate checks the shell is supported
+func (c *InstallCommand) Validate() error {
+	for _, shell := range Shells {
+		if c.shell == shell {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported shell %q, one of: %s", c.shell, strings.Join(Shells, "|"))
+}
+
+// Run writes the completion script and registers it on the shell profile
+func (c *InstallCommand) Run(_ *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	plan, err := planInstall(c.shell)
+	if err != nil {
+		return err
+	}
+	registered, err := isRegistered(plan.profilePath)
+	if err != nil {
+		return err
+	}
+
+	if c.dryRun {
+		fmt.Fprintf(ioStreams.Out, "Would write the %s completion script to %q\n", plan.shell, plan.scriptPath)
+		switch {
+		case plan.profilePath == "":
+		case registered:
+			fmt.Fprintf(ioStreams.Out, "Already registered on %q\n", plan.profilePath)
+		default:
+			fmt.Fprintf(ioStreams.Out, "Would register it on %q, appending:\n", plan.profilePath)
+			for _, line := range append([]string{profileMarker}, plan.profileLines...) {
+				fmt.Fprintf(ioStreams.Out, "\t%s\n", line)
+			}
+		}
+		return nil
+	}
+
+	var script bytes.Buffer
+	if err = writeScript(c.cmd.Root(), plan.shell, !c.noDescriptions, &script); err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(plan.scriptPath), 0o755); err != nil {
+		return err
+	}
+	if err = os.WriteFile(plan.scriptPath, script.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "Wrote the %s completion script to %q\n", plan.shell, plan.scriptPath)
+
+	switch {
+	case plan.profilePath == "":
+	case registered:
+		fmt.Fprintf(ioStreams.Out, "Already registered on %q\n", plan.profilePath)
+	default:
+		if err = register(plan); err != nil {
+			return err
+		}
+		fmt.Fprintf(ioStreams.Out, "Registered it on %q\n", plan.profilePath)
+	}
+	fmt.Fprintln(ioStreams.Out, "Start a new shell to enable the completion")
+	return nil
+}
+
+// detectShell identifies the user shell from the SHELL environment variable, PowerShell is assumed
+// on Windows.
+func detectShell() (string, error) {
+	shell := strings.TrimSuffix(filepath.Base(getenv("SHELL")), ".exe")
+	switch shell {
+	case Bash, Zsh, Fish, PowerShell:
+		return shell, nil
+	case "pwsh":
+		return PowerShell, nil
+	}
+	if goos == "windows" {
+		return PowerShell, nil
+	}
+	return "", fmt.Errorf("unable to detect the shell, inform one of: %s", strings.Join(Shells, "|"))
+}
+
+// planInstall works out the script and profile locations for the shell, honoring the XDG base
+// directories and the zsh ZDOTDIR.
+func planInstall(shell string) (*installPlan, error) {
+	home, err := homeDir()
+	if err != nil {
+		return nil, err
+	}
+	dataHome := envOr("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	configHome := envOr("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	plan := &installPlan{shell: shell}
+	switch shell {
+	case Bash:
+		plan.scriptPath = filepath.Join(dataHome, "bash-completion", "completions", "shp")
+		plan.profilePath = filepath.Join(home, ".bashrc")
+		plan.profileLines = []string{fmt.Sprintf("[ -f '%s' ] && source '%s'", plan.scriptPath, plan.scriptPath)}
+	case Zsh:
+		plan.scriptPath = filepath.Join(dataHome, "zsh", "site-functions", "_shp")
+		plan.profilePath = filepath.Join(envOr("ZDOTDIR", home), ".zshrc")
+		plan.profileLines = []string{
+			fmt.Sprintf("fpath=('%s' $fpath)", filepath.Dir(plan.scriptPath)),
+			"autoload -Uz compinit && compinit",
+		}
+	case Fish:
+		plan.scriptPath = filepath.Join(configHome, "fish", "completions", "shp.fish")
+	case PowerShell:
+		profileDir := filepath.Join(configHome, "powershell")
+		if goos == "windows" {
+			profileDir = filepath.Join(home, "Documents", "PowerShell")
+		}
+		plan.scriptPath = filepath.Join(profileDir, "shp-completion.ps1")
+		plan.profilePath = filepath.Join(profileDir, "Microsoft.PowerShell_profile.ps1")
+		plan.profileLines = []string{fmt.Sprintf(". '%s'", plan.scriptPath)}
+	default:
+		return nil, fmt.Errorf("unsupported shell %q, one of: %s", shell, strings.Join(Shells, "|"))
+	}
+	return plan, nil
+}
+
+// envOr returns the environment variable value, or the fallback when it's empty.
+func envOr(name, fallback string) string {
+	if value := getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// isRegistered tells whether the profile already carries the completion registration, a missing
+// profile is not registered.
+func isRegistered(profilePath string) (bool, error) {
+	if profilePath == "" {
+		return false, nil
+	}
+	data, err := os.ReadFile(profilePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Contains(data, []byte(profileMarker)), nil
+}
+
+// register appends the registration lines to the profile, creating it when needed.
+func register(plan *installPlan) error {
+	if err := os.MkdirAll(filepath.Dir(plan.profilePath), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(plan.profilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	block := "\n" + profileMarker + "\n" + strings.Join(plan.profileLines, "\n") + "\n"
+	_, err = f.WriteString(block)
+	return err
+}
@@ -0,0 +1,85 @@
+package completion
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestInstallCommand(t *testing.T) {
+	g := o.NewWithT(t)
+
+	home := t.TempDir()
+	env := map[string]string{"SHELL": "/usr/bin/zsh"}
+	getenv = func(name string) string { return env[name] }
+	homeDir = func() (string, error) { return home, nil }
+	defer func() {
+		getenv, homeDir = os.Getenv, os.UserHomeDir
+	}()
+
+	p := params.NewParamsForTest(nil, nil, nil, "default", nil, nil)
+	run := func(args ...string) (string, error) {
+		cmd := installCmd().(*InstallCommand)
+		root := &cobra.Command{Use: "shp"}
+		root.AddCommand(cmd.cmd)
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		if err := cmd.Complete(p, nil, cmd.cmd.Flags().Args()); err != nil {
+			return "", err
+		}
+		if err := cmd.Validate(); err != nil {
+			return "", err
+		}
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		err := cmd.Run(p, &ioStreams)
+		return out.String(), err
+	}
+
+	script := filepath.Join(home, ".local", "share", "zsh", "site-functions", "_shp")
+	zshrc := filepath.Join(home, ".zshrc")
+
+	// the shell is detected, and nothing is written on dry-run
+	out, err := run("--dry-run")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).To(o.ContainSubstring("Would write the zsh completion script to %q", script))
+	g.Expect(out).To(o.ContainSubstring("fpath=('%s' $fpath)", filepath.Dir(script)))
+	g.Expect(script).NotTo(o.BeAnExistingFile())
+	g.Expect(zshrc).NotTo(o.BeAnExistingFile())
+
+	g.Expect(os.WriteFile(zshrc, []byte("export EDITOR=vi\n"), 0o600)).To(o.Succeed())
+	out, err = run()
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).To(o.ContainSubstring("Registered it on %q", zshrc))
+	data, err := os.ReadFile(script)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(string(data)).To(o.HavePrefix("#compdef shp"))
+
+	// the profile is changed only once
+	out, err = run()
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).To(o.ContainSubstring("Already registered on %q", zshrc))
+	data, err = os.ReadFile(zshrc)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(strings.HasPrefix(string(data), "export EDITOR=vi\n")).To(o.BeTrue())
+	g.Expect(strings.Count(string(data), profileMarker)).To(o.Equal(1))
+
+	// fish loads the completions by itself, honoring the XDG configuration directory
+	env["XDG_CONFIG_HOME"] = filepath.Join(home, "config")
+	out, err = run("fish")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out).NotTo(o.ContainSubstring("Registered"))
+	g.Expect(filepath.Join(home, "config", "fish", "completions", "shp.fish")).To(o.BeAnExistingFile())
+
+	env["SHELL"] = "/bin/tcsh"
+	_, err = run()
+	g.Expect(err).To(o.MatchError("unable to detect the shell, inform one of: bash|zsh|fish|powershell"))
+	_, err = run("tcsh")
+	g.Expect(err).To(o.MatchError(`unsupported shell "tcsh", one of: bash|zsh|fish|powershell`))
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	shpconfig "github.com/shipwright-io/cli/pkg/shp/config"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// Command returns the "config" command of Shipwright CLI, grouping the configuration subcommands.
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the shp persistent defaults",
+		Long:  configLongDesc(),
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	for _, sub := range []runner.SubCommand{getCmd(), setCmd(), unsetCmd(), viewCmd()} {
+		cmd := runner.NewRunner(p, ioStreams, sub).Cmd()
+		cmd.Annotations = map[string]string{shpconfig.SkipDefaultsAnnotation: ""}
+		command.AddCommand(cmd)
+	}
+	return command
+}
+
+// configLongDesc describes the configuration file and the keys supported.
+func configLongDesc() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `
+Manages the configuration file, by default "~/.config/shp/config.yaml" or the location informed by
+the %s environment variable, storing defaults for the flags not informed explicitly.
+
+The configuration keys are:
+
+`, shpconfig.EnvVar)
+	for _, k := range shpconfig.Keys {
+		fmt.Fprintf(&b, "\t%-16s %s\n", k.Name, k.Description)
+	}
+	return b.String()
+}
+
+// completeKeys completes the key names for shell completion.
+func completeKeys(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(shpconfig.Keys))
+	for _, k := range shpconfig.Keys {
+		names = append(names, k.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	shpconfig "github.com/shipwright-io/cli/pkg/shp/config"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestConfigCommand(t *testing.T) {
+	g := o.NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(shpconfig.EnvVar, path)
+
+	out := &bytes.Buffer{}
+	ioStreams := &genericclioptions.IOStreams{Out: out, ErrOut: out}
+	p := params.NewParamsForTest(nil, nil, nil, "default", nil, nil)
+
+	cmd := Command(p, ioStreams)
+	cmd.SetArgs([]string{"set", "registry-prefix", "quay.io/org"})
+	g.Expect(cmd.Execute()).To(o.Succeed())
+	g.Expect(out.String()).To(o.ContainSubstring(`Configuration "registry-prefix" set to "quay.io/org"`))
+
+	out.Reset()
+	cmd.SetArgs([]string{"get", "registry-prefix"})
+	g.Expect(cmd.Execute()).To(o.Succeed())
+	g.Expect(out.String()).To(o.Equal("quay.io/org\n"))
+
+	out.Reset()
+	cmd.SetArgs([]string{"view"})
+	g.Expect(cmd.Execute()).To(o.Succeed())
+	g.Expect(out.String()).To(o.ContainSubstring(path))
+	g.Expect(out.String()).To(o.ContainSubstring("quay.io/org"))
+	g.Expect(out.String()).ToNot(o.ContainSubstring("namespace"))
+
+	out.Reset()
+	cmd.SetArgs([]string{"unset", "registry-prefix"})
+	g.Expect(cmd.Execute()).To(o.Succeed())
+
+	cfg, err := shpconfig.Load(path)
+	g.Expect(err).ToNot(o.HaveOccurred())
+	g.Expect(cfg.RegistryPrefix).To(o.BeEmpty())
+
+	cmd.SetArgs([]string{"set", "colour", "blue"})
+	g.Expect(cmd.Execute()).ToNot(o.Succeed())
+}
+
+func TestConfigViewInvalidFile(t *testing.T) {
+	g := o.NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	g.Expect(os.WriteFile(path, []byte("colour: blue\n"), 0o600)).To(o.Succeed())
+	t.Setenv(shpconfig.EnvVar, path)
+
+	ioStreams, _, out, errOut := genericclioptions.NewTestIOStreams()
+	p := params.NewParamsForTest(nil, nil, nil, "default", nil, nil)
+
+	cmd := Command(p, &ioStreams)
+	cmd.SetArgs([]string{"view"})
+	g.Expect(cmd.Execute()).To(o.Succeed())
+	g.Expect(out.String()).To(o.ContainSubstring(path))
+	g.Expect(errOut.String()).To(o.ContainSubstring("Warning: invalid configuration file"))
+}
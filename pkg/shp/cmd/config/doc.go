@@ -0,0 +1,3 @@
+// Package config contains types and functions for the config cobra command, managing the
+// persistent defaults stored on the shp configuration file.
+package config
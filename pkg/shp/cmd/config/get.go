@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	shpconfig "github.com/shipwright-io/cli/pkg/shp/config"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// GetCommand contains data input from user for the config get subcommand
+type GetCommand struct {
+	cmd *cobra.Command
+
+	key  string
+	path string
+}
+
+func getCmd() runner.SubCommand {
+	return &GetCommand{
+		cmd: &cobra.Command{
+			Use:               "get <key>",
+			Short:             "Print the value of a configuration key",
+			Args:              cobra.ExactArgs(1),
+			ValidArgsFunction: completeKeys,
+		},
+	}
+}
+
+// Cmd returns cobra command object of the config get subcommand
+func (c *GetCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the key name and the configuration file location
+func (c *GetCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.key = args[0]
+	var err error
+	c.path, err = shpconfig.Path()
+	return err
+}
+
+// Validate checks the key is supported
+func (c *GetCommand) Validate() error {
+	_, err := shpconfig.LookupKey(c.key)
+	return err
+}
+
+// Run prints the key value, an empty line when not set
+func (c *GetCommand) Run(_ *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	cfg, err := shpconfig.Load(c.path)
+	if err != nil {
+		return err
+	}
+	value, err := cfg.Get(c.key)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(ioStreams.Out, value)
+	return nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	shpconfig "github.com/shipwright-io/cli/pkg/shp/config"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// SetCommand contains data input from user for the config set subcommand
+type SetCommand struct {
+	cmd *cobra.Command
+
+	key   string
+	value string
+	path  string
+}
+
+func setCmd() runner.SubCommand {
+	return &SetCommand{
+		cmd: &cobra.Command{
+			Use:               "set <key> <value>",
+			Short:             "Store the default value of a configuration key",
+			Args:              cobra.ExactArgs(2),
+			ValidArgsFunction: completeKeys,
+		},
+	}
+}
+
+// Cmd returns cobra command object of the config set subcommand
+func (c *SetCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the key, value and the configuration file location
+func (c *SetCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.key, c.value = args[0], args[1]
+	var err error
+	c.path, err = shpconfig.Path()
+	return err
+}
+
+// Validate checks the key is supported and the value informed
+func (c *SetCommand) Validate() error {
+	if _, err := shpconfig.LookupKey(c.key); err != nil {
+		return err
+	}
+	if c.value == "" {
+		return fmt.Errorf("value for %q is empty, use \"shp config unset\" instead", c.key)
+	}
+	return nil
+}
+
+// Run stores the key value on the configuration file
+func (c *SetCommand) Run(_ *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	cfg, err := shpconfig.Load(c.path)
+	if err != nil {
+		return err
+	}
+	if err = cfg.Set(c.key, c.value); err != nil {
+		return err
+	}
+	if err = cfg.Save(c.path); err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "Configuration %q set to %q\n", c.key, c.value)
+	return nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	shpconfig "github.com/shipwright-io/cli/pkg/shp/config"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// UnsetCommand contains data input from user for the config unset subcommand
+type UnsetCommand struct {
+	cmd *cobra.Command
+
+	key  string
+	path string
+}
+
+func unsetCmd() runner.SubCommand {
+	return &UnsetCommand{
+		cmd: &cobra.Command{
+			Use:               "unset <key>",
+			Short:             "Remove the default value of a configuration key",
+			Args:              cobra.ExactArgs(1),
+			ValidArgsFunction: completeKeys,
+		},
+	}
+}
+
+// Cmd returns cobra command object of the config unset subcommand
+func (c *UnsetCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the key name and the configuration file location
+func (c *UnsetCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.key = args[0]
+	var err error
+	c.path, err = shpconfig.Path()
+	return err
+}
+
+// Validate checks the key is supported
+func (c *UnsetCommand) Validate() error {
+	_, err := shpconfig.LookupKey(c.key)
+	return err
+}
+
+// Run removes the key from the configuration file
+func (c *UnsetCommand) Run(_ *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	cfg, err := shpconfig.Load(c.path)
+	if err != nil {
+		return err
+	}
+	if err = cfg.Set(c.key, ""); err != nil {
+		return err
+	}
+	if err = cfg.Save(c.path); err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "Configuration %q unset\n", c.key)
+	return nil
+}
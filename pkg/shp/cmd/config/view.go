@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	shpconfig "github.com/shipwright-io/cli/pkg/shp/config"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// ViewCommand contains data input from user for the config view subcommand
+type ViewCommand struct {
+	cmd *cobra.Command
+
+	all  bool
+	path string
+}
+
+func viewCmd() runner.SubCommand {
+	c := &ViewCommand{
+		cmd: &cobra.Command{
+			Use:   "view [flags]",
+			Short: "Print the configuration file location and the keys set",
+			Args:  cobra.NoArgs,
+		},
+	}
+
+	c.cmd.Flags().BoolVar(&c.all, "all", false, "show the keys not set as well")
+
+	return c
+}
+
+// Cmd returns cobra command object of the config view subcommand
+func (c *ViewCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills the configuration file location
+func (c *ViewCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	var err error
+	c.path, err = shpconfig.Path()
+	return err
+}
+
+// Validate validates data input by user
+func (c *ViewCommand) Validate() error {
+	return nil
+}
+
+// Run prints the configuration keys and values
+func (c *ViewCommand) Run(_ *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	fmt.Fprintf(ioStreams.Out, "Configuration file: %s\n\n", c.path)
+	cfg, err := shpconfig.Load(c.path)
+	if err != nil {
+		// the location is shown regardless, thus the invalid file can be repaired
+		fmt.Fprintf(ioStreams.ErrOut, "Warning: %v\n", err)
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(ioStreams.Out, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(writer, "KEY\tVALUE\tFLAG")
+	for _, k := range shpconfig.Keys {
+		value, _ := cfg.Get(k.Name)
+		if value == "" {
+			if !c.all {
+				continue
+			}
+			value = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t--%s\n", k.Name, value, k.Flag)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// exitCodesHelpTopic instantiate the "exit-codes" help topic, shown by "shp help exit-codes",
+// describing the exit code taxonomy.
+func exitCodesHelpTopic() *cobra.Command {
+	var b strings.Builder
+	b.WriteString(`
+The exit code of shp tells the outcome of the command, the codes are stable across releases and
+can be relied upon by scripts:
+
+`)
+	for _, c := range exitcode.Classes {
+		fmt.Fprintf(&b, "\t%d\t%-13s %s\n", c.Code, c.Name, c.Description)
+	}
+	fmt.Fprintf(&b, `
+With --%s=json the error is printed on the standard error as a single line of JSON, carrying
+the class above, the specific reason, the message, a remediation hint and the exit code:
+
+	$ shp buildrun logs missing --%s=json
+	{"class":"NotFound","reason":"NotFound","message":"...","remediation":"...","exitCode":6}
+`, params.ErrorFormatFlag, params.ErrorFormatFlag)
+
+	return &cobra.Command{
+		Use:   "exit-codes",
+		Short: "Exit codes and machine-readable errors",
+		Long:  b.String(),
+	}
+}
@@ -5,15 +5,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
 	"github.com/shipwright-io/cli/pkg/shp/reactor"
 	"github.com/shipwright-io/cli/pkg/shp/tail"
+	"github.com/shipwright-io/cli/pkg/shp/trace"
 	"github.com/shipwright-io/cli/pkg/shp/util"
+	"golang.org/x/term"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -24,7 +31,24 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
-// Follower encapsulate the function of tailing the logs for Pods derived from BuildRuns
+// noEventHeartbeat how long to wait without pod events before letting the user know the log
+// following is still waiting.
+const noEventHeartbeat = 30 * time.Second
+
+// minRemainingInterval and maxRemainingInterval bound the interval between the reports of the time
+// left before the BuildRun times out.
+const (
+	minRemainingInterval = 10 * time.Second
+	maxRemainingInterval = time.Minute
+)
+
+// BuildRunReasonTimeout the "Succeeded" condition reason set by the build controller when the
+// BuildRun times out.
+const BuildRunReasonTimeout = "BuildRunTimeout"
+
+// Follower encapsulate the function of tailing the logs for Pods derived from BuildRuns. Strategies
+// and retries may spawn more than one pod per BuildRun, every pod matching the BuildRun label is
+// followed, and the following ends when all of them are completed.
 type Follower struct {
 	ctx            context.Context              // global context instance
 	buildRun       types.NamespacedName         // qualified object name
@@ -34,10 +58,25 @@ type Follower struct {
 	buildClientset buildclientset.Interface     // shipwright api-client
 
 	logTail         *tail.Tail      // follow container logs
-	tailLogsStarted map[string]bool // controls tail instance per container
-
-	logLock             sync.Mutex // avoiding race condition to print logs
-	enteredRunningState bool       // target pod is running
+	logWriter       *tail.LogWriter // buffers, and rate limits, the container logs
+	maxLogRate      int64           // log bytes per second printed, zero means no limit
+	lineFilter      tail.LineFilter // selects the log lines printed, nil prints all of them
+	heartbeat       *tail.Heartbeat // reports the running step during quiet periods, nil when disabled
+	since           time.Time       // logs written before it are skipped, zero follows from the start
+	started         time.Time       // moment the following started
+	reportOnce      sync.Once       // reports the amount of logs once stopped
+	tailLogsStarted map[string]bool // controls tail instance per pod container
+
+	spanLock sync.Mutex  // guards the phase spans, ended by the watcher and on stop
+	podSpan  *trace.Span // waiting for the first pod to run, nil when tracing is disabled
+	logSpan  *trace.Span // streaming the logs, from the first running pod on
+
+	logLock       sync.Mutex      // avoiding race condition to print logs
+	seenPods      map[string]bool // pods observed for the BuildRun
+	runningPods   map[string]bool // pods which entered the running state
+	completedPods map[string]bool // pods already succeeded or failed, further events are ignored
+	podSucceeded  atomic.Bool     // target pod has succeeded
+	timeout       time.Duration   // BuildRun timeout, zero when not set
 
 	failPollInterval time.Duration // for use in the PollInterval call when processing failed pods
 	failPollTimeout  time.Duration // for use in the PollInterval call when processing failed pods
@@ -52,6 +91,9 @@ func NewFollower(
 	clientset kubernetes.Interface,
 	buildClientset buildclientset.Interface,
 ) *Follower {
+	logWriter := tail.NewLogWriter(ioStreams.Out)
+	logTail := tail.NewTail(ctx, clientset)
+	logTail.SetStdout(logWriter)
 	f := &Follower{
 		ctx:            ctx,
 		buildRun:       buildRun,
@@ -60,9 +102,13 @@ func NewFollower(
 		clientset:      clientset,
 		buildClientset: buildClientset,
 
-		logTail:          tail.NewTail(ctx, clientset),
+		logTail:          logTail,
+		logWriter:        logWriter,
 		logLock:          sync.Mutex{},
 		tailLogsStarted:  map[string]bool{},
+		seenPods:         map[string]bool{},
+		runningPods:      map[string]bool{},
+		completedPods:    map[string]bool{},
 		failPollInterval: 1 * time.Second,
 		failPollTimeout:  15 * time.Second,
 	}
@@ -70,6 +116,7 @@ func NewFollower(
 	f.pw.WithOnPodModifiedFn(f.OnEvent)
 	f.pw.WithTimeoutPodFn(f.OnTimeout)
 	f.pw.WithNoPodEventsYetFn(f.OnNoPodEventsYet)
+	f.pw.WithTimeout(noEventHeartbeat).WithOnNoEventFn(f.OnNoEvent)
 
 	return f
 }
@@ -91,6 +138,106 @@ func (f *Follower) SetFailPollTimeout(t time.Duration) {
 	f.failPollTimeout = t
 }
 
+// SetOutput redirects the messages and the container logs to the informed writers, for instance
+// a full-screen terminal view.
+func (f *Follower) SetOutput(out, errOut io.Writer) {
+	f.logLock.Lock()
+	defer f.logLock.Unlock()
+	f.ioStreams = &genericclioptions.IOStreams{In: f.ioStreams.In, Out: out, ErrOut: errOut}
+	f.logWriter.SetOutput(out)
+	if f.heartbeat != nil {
+		// the full-screen view is always on a terminal
+		f.heartbeat.Stop()
+		f.heartbeat = nil
+	}
+	f.logTail.SetStderr(errOut)
+}
+
+// SetMaxLogRate limits the amount of log bytes per second printed, the lines exceeding it are
+// skipped, zero means no limit. The amount of logs printed and skipped is reported once stopped.
+func (f *Follower) SetMaxLogRate(bytesPerSecond int64) {
+	f.maxLogRate = bytesPerSecond
+	f.logWriter.SetMaxRate(bytesPerSecond)
+}
+
+// SetLineFilter selects the log lines printed, nil prints all of them.
+func (f *Follower) SetLineFilter(filter tail.LineFilter) {
+	f.lineFilter = filter
+	f.logTail.SetFilter(filter)
+}
+
+// SetSince skips the container logs written before the informed moment, attaching to a BuildRun
+// already running mid-flight, zero follows the logs from the start.
+func (f *Follower) SetSince(since time.Time) {
+	f.since = since
+	f.logTail.SetSince(since)
+}
+
+// SetHeartbeat reports the step still running when nothing has been printed for the informed
+// interval, thus CI systems don't kill the jobs during quiet steps. It's only enabled when the
+// output is not a terminal, zero disables it.
+func (f *Follower) SetHeartbeat(interval time.Duration) {
+	if interval <= 0 || isTerminal(f.ioStreams.Out) {
+		return
+	}
+	f.logLock.Lock()
+	defer f.logLock.Unlock()
+	f.heartbeat = tail.NewHeartbeat(f.ioStreams.Out, interval, f.heartbeatMessage)
+	f.ioStreams = &genericclioptions.IOStreams{In: f.ioStreams.In, Out: f.heartbeat, ErrOut: f.ioStreams.ErrOut}
+	f.logWriter.SetOutput(f.heartbeat)
+}
+
+// heartbeatMessage describes the step still running, or the BuildRun when no step is logging yet.
+func (f *Follower) heartbeatMessage() string {
+	if step, started, ok := f.logTail.RunningStep(); ok {
+		return fmt.Sprintf("Still running %s (%s elapsed)\n", step, time.Since(started).Round(time.Second))
+	}
+	return fmt.Sprintf("BuildRun %q is still running (%s elapsed)\n", f.buildRun.Name, time.Since(f.started).Round(time.Second))
+}
+
+// isTerminal tells whether the writer is a terminal, looking through the writers wrapping it, i.e.
+// the prefix writers.
+func isTerminal(w io.Writer) bool {
+	for {
+		switch writer := w.(type) {
+		case *os.File:
+			return term.IsTerminal(int(writer.Fd()))
+		case interface{ Unwrap() io.Writer }:
+			w = writer.Unwrap()
+		default:
+			return false
+		}
+	}
+}
+
+// WithContainerTracker records the container state transitions of the followed pod on the tracker.
+func (f *Follower) WithContainerTracker(t *reactor.ContainerTracker) {
+	f.pw.WithContainerTracker(t)
+}
+
+// SetTimeout reports periodically the time left before the BuildRun times out, counted from the
+// informed start, zero means now. Once the deadline is reached the following stops with a timeout
+// error.
+func (f *Follower) SetTimeout(start time.Time, timeout time.Duration) {
+	if start.IsZero() {
+		start = time.Now()
+	}
+	f.timeout = timeout
+	interval := timeout / 10
+	switch {
+	case interval < minRemainingInterval:
+		interval = minRemainingInterval
+	case interval > maxRemainingInterval:
+		interval = maxRemainingInterval
+	}
+	f.pw.WithDeadline(start.Add(timeout), interval).WithOnRemainingFn(f.OnRemaining).WithOnDeadlineFn(f.OnDeadline)
+}
+
+// PodSucceeded tells whether the BuildRun's pod has been observed succeeding.
+func (f *Follower) PodSucceeded() bool {
+	return f.podSucceeded.Load()
+}
+
 // GetLogLock returns the mutex used for coordinating access to log buffers.
 func (f *Follower) GetLogLock() *sync.Mutex {
 	return &f.logLock
@@ -101,45 +248,117 @@ func (f *Follower) Log(msg string) {
 	// concurrent fmt.Fprintf(r.ioStream.Out...) calls need locking to avoid data races, as we 'write' to the stream
 	f.logLock.Lock()
 	defer f.logLock.Unlock()
+	// the buffered container logs are printed first, keeping the order
+	f.logWriter.Flush()
 	fmt.Fprint(f.ioStreams.Out, msg)
 }
 
 // tailLogs start tailing logs for each container name in init-containers and containers, if not
 // started already.
 func (f *Follower) tailLogs(pod *corev1.Pod) {
+	f.logTail.SetSteps(pod.GetName(), tail.StepsOf(pod))
+	f.logWriter.Start()
 	containers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
 	for _, container := range containers {
-		if _, exists := f.tailLogsStarted[container.Name]; exists {
+		key := fmt.Sprintf("%s/%s", pod.GetName(), container.Name)
+		if _, exists := f.tailLogsStarted[key]; exists {
 			continue
 		}
-		f.tailLogsStarted[container.Name] = true
+		f.tailLogsStarted[key] = true
 		f.logTail.Start(pod.GetNamespace(), pod.GetName(), container.Name)
 	}
 }
 
-// Stop stop log tail instance.
+// activePods returns the pods observed which are not completed yet, besides the informed pod.
+func (f *Follower) activePods(except string) []string {
+	active := []string{}
+	for name := range f.seenPods {
+		if name != except && !f.completedPods[name] {
+			active = append(active, name)
+		}
+	}
+	sort.Strings(active)
+	return active
+}
+
+// completePod records the pod as completed, and tells whether other pods of the BuildRun are
+// still being followed, in which case the following must go on.
+func (f *Follower) completePod(pod *corev1.Pod) bool {
+	f.completedPods[pod.GetName()] = true
+	active := f.activePods(pod.GetName())
+	if len(active) == 0 {
+		return false
+	}
+	f.Log(fmt.Sprintf("Pod %q is %s, still following the logs of %s\n",
+		pod.GetName(), strings.ToLower(string(pod.Status.Phase)), strings.Join(active, ", ")))
+	return true
+}
+
+// Stop stop log tail instance, flushing the buffered logs.
 func (f *Follower) Stop() {
 	f.logTail.Stop()
+	f.logWriter.Stop()
+	if f.heartbeat != nil {
+		f.heartbeat.Stop()
+	}
 	f.pw.Stop()
+	f.endSpans()
+	if f.maxLogRate > 0 {
+		f.reportOnce.Do(func() {
+			f.Log(fmt.Sprintf("Printed %s of logs, skipped %s exceeding the rate of %s/s\n",
+				tail.FormatBytes(f.logWriter.Written()), tail.FormatBytes(f.logWriter.Skipped()), tail.FormatBytes(f.maxLogRate)))
+		})
+	}
+}
+
+// startLogSpan ends the wait for the pod phase, and starts the log streaming phase, when the first
+// pod starts running.
+func (f *Follower) startLogSpan(pod *corev1.Pod) {
+	f.spanLock.Lock()
+	defer f.spanLock.Unlock()
+	if f.podSpan == nil || f.logSpan != nil {
+		return
+	}
+	f.podSpan.SetAttribute("pod", pod.GetName())
+	f.podSpan.End()
+	_, f.logSpan = trace.Start(f.ctx, "stream logs")
+	f.logSpan.SetAttribute("pod", pod.GetName())
+}
+
+// endSpans ends the phases in progress.
+func (f *Follower) endSpans() {
+	f.spanLock.Lock()
+	defer f.spanLock.Unlock()
+	f.podSpan.End()
+	f.logSpan.End()
 }
 
 // OnEvent reacts on pod state changes, to start and stop tailing container logs.
 func (f *Follower) OnEvent(pod *corev1.Pod) error {
+	// completed streams are not followed again, late modifications are ignored
+	if f.completedPods[pod.GetName()] {
+		return nil
+	}
+	f.seenPods[pod.GetName()] = true
 	switch pod.Status.Phase {
 	case corev1.PodRunning:
-		if !f.enteredRunningState {
+		if !f.runningPods[pod.GetName()] {
 			f.Log(fmt.Sprintf("Pod %q in %q state, starting up log tail\n", pod.GetName(), corev1.PodRunning))
 			for _, c := range pod.Status.ContainerStatuses {
 				if c.State.Running != nil && !c.State.Running.StartedAt.IsZero() {
-					f.enteredRunningState = true
+					f.runningPods[pod.GetName()] = true
 					break
 				}
 			}
-			if f.enteredRunningState {
+			if f.runningPods[pod.GetName()] {
+				f.startLogSpan(pod)
 				f.tailLogs(pod)
 			}
 		}
 	case corev1.PodFailed:
+		if f.completePod(pod) {
+			return nil
+		}
 		msg := ""
 		var br *buildv1alpha1.BuildRun
 		err := wait.PollUntilContextTimeout(f.ctx, f.failPollInterval, f.failPollTimeout, true, func(ctx context.Context) (done bool, err error) {
@@ -169,6 +388,10 @@ func (f *Follower) OnEvent(pod *corev1.Pod) error {
 			msg = fmt.Sprintf("BuildRun %q has been deleted.\n", br.Name)
 		case pod.DeletionTimestamp != nil:
 			msg = fmt.Sprintf("Pod %q has been deleted.\n", pod.GetName())
+		case isTimedOut(br):
+			msg = buildErrorMessage(br, pod)
+			err = exitcode.Errorf(exitcode.Timeout, "BuildRun %q has timed out: %s", br.Name,
+				br.Status.GetCondition(buildv1alpha1.Succeeded).GetMessage()).WithReason(BuildRunReasonTimeout)
 		default:
 			msg = buildErrorMessage(br, pod)
 			err = fmt.Errorf("buildrun pod %q has failed", pod.GetName())
@@ -179,8 +402,9 @@ func (f *Follower) OnEvent(pod *corev1.Pod) error {
 		return err
 	case corev1.PodSucceeded:
 		// encountered scenarios where the build run quickly enough that the pod effectively skips the running state,
-		// or the events come in reverse order, and we never enter the tail
-		if !f.enteredRunningState {
+		// or the events come in reverse order, and we never enter the tail. The logs are dumped from
+		// the start, thus not when attaching mid-flight
+		if !f.runningPods[pod.GetName()] && f.since.IsZero() {
 			f.Log(fmt.Sprintf("succeeded event for pod %q arrived before or in place of running event so dumping logs now\n", pod.GetName()))
 			var b strings.Builder
 			for _, c := range pod.Spec.Containers {
@@ -189,15 +413,26 @@ func (f *Follower) OnEvent(pod *corev1.Pod) error {
 					f.Log(fmt.Sprintf("could not get logs for container %q: %s\n", c.Name, err.Error()))
 					continue
 				}
+				if logs = tail.FilterLines(f.lineFilter, c.Name, logs); f.lineFilter != nil && logs == "" {
+					continue
+				}
 				fmt.Fprintf(&b, "*** Pod %q, container %q: ***\n\n", pod.Name, c.Name)
 				fmt.Fprintln(&b, logs)
 			}
 			f.Log(b.String())
 		}
 		f.Log(fmt.Sprintf("Pod %q has succeeded!\n", pod.GetName()))
+		f.podSucceeded.Store(true)
+		if f.completePod(pod) {
+			return nil
+		}
 		f.Stop()
 	default:
-		f.Log(fmt.Sprintf("Pod %q is in state %q...\n", pod.GetName(), string(pod.Status.Phase)))
+		state := fmt.Sprintf("%q", string(pod.Status.Phase))
+		if reason, pending := reactor.PendingReasonOf(pod); pending && reason.Reason != string(pod.Status.Phase) {
+			state = fmt.Sprintf("%s (%s)", state, reason)
+		}
+		f.Log(fmt.Sprintf("Pod %q is in state %s...\n", pod.GetName(), state))
 		// handle any issues with pulling images that may fail
 		for _, c := range pod.Status.Conditions {
 			if c.Type == corev1.PodInitialized || c.Type == corev1.ContainersReady {
@@ -216,12 +451,53 @@ func (f *Follower) OnTimeout(msg string) {
 	f.Log(fmt.Sprintf("BuildRun %q log following has stopped because: %q\n", f.buildRun.Name, msg))
 }
 
+// OnRemaining lets the user know the time left before the BuildRun times out.
+func (f *Follower) OnRemaining(remaining time.Duration) {
+	f.Log(fmt.Sprintf("BuildRun %q has %s left before timing out\n", f.buildRun.Name, remaining.Round(time.Second)))
+}
+
+// OnDeadline stops following once the BuildRun timeout is reached, with a timeout error. When the
+// BuildRun is done for other reasons, the pod events tell the outcome instead.
+func (f *Follower) OnDeadline() error {
+	br, err := f.buildClientset.ShipwrightV1alpha1().BuildRuns(f.buildRun.Namespace).Get(f.ctx, f.buildRun.Name, metav1.GetOptions{})
+	if err == nil && br.IsDone() && !isTimedOut(br) {
+		return nil
+	}
+	f.Log(fmt.Sprintf("BuildRun %q has reached its timeout of %s\n", f.buildRun.Name, f.timeout))
+	f.Stop()
+	return exitcode.Errorf(exitcode.Timeout, "BuildRun %q has exceeded its timeout of %s", f.buildRun.Name, f.timeout).
+		WithReason(BuildRunReasonTimeout)
+}
+
+// isTimedOut tells whether the BuildRun has been marked as timed out by the build controller.
+func isTimedOut(br *buildv1alpha1.BuildRun) bool {
+	return br.Status.GetCondition(buildv1alpha1.Succeeded).GetReason() == BuildRunReasonTimeout
+}
+
+// OnNoEvent reacts to the pod watcher not receiving pod events within the heartbeat window, letting
+// the user know the log following is still waiting.
+func (f *Follower) OnNoEvent(elapsed time.Duration) error {
+	if len(f.runningPods) > 0 {
+		return nil
+	}
+	f.Log(fmt.Sprintf("BuildRun %q is still waiting for the build pod to be scheduled and started (%s)...\n",
+		f.buildRun.Name, elapsed.Round(time.Second)))
+	return nil
+}
+
 // OnNoPodEventsYet reacts to the pod watcher telling us it has not received any pod events for our build run
 func (f *Follower) OnNoPodEventsYet(podList *corev1.PodList) {
 	f.Log(fmt.Sprintf("BuildRun %q log following has not observed any pod events yet.\n", f.buildRun.Name))
 	if podList != nil && len(podList.Items) > 0 {
 		f.Log(fmt.Sprintf("BuildRun %q's Pod completed before the log following's watch was established.\n", f.buildRun.Name))
-		f.OnEvent(&podList.Items[0]) // #nosec G104 there is nothing we must handle here, the error is logged in the function already
+		// the most recent pod carries the outcome of the BuildRun, when it has been retried
+		latest := &podList.Items[0]
+		for i := range podList.Items {
+			if latest.CreationTimestamp.Before(&podList.Items[i].CreationTimestamp) {
+				latest = &podList.Items[i]
+			}
+		}
+		f.OnEvent(latest) // #nosec G104 there is nothing we must handle here, the error is logged in the function already
 		return
 	}
 	brClient := f.buildClientset.ShipwrightV1alpha1().BuildRuns(f.buildRun.Namespace)
@@ -258,8 +534,19 @@ func (f *Follower) OnNoPodEventsYet(podList *corev1.PodList) {
 	}
 }
 
+// Connect starts watching the pods selected, and the heartbeat when enabled.
 func (f *Follower) Connect(lo metav1.ListOptions) error {
-	return f.pw.Connect(lo)
+	f.started = time.Now()
+	f.spanLock.Lock()
+	_, f.podSpan = trace.Start(f.ctx, "wait for pod")
+	f.spanLock.Unlock()
+	if err := f.pw.Connect(lo); err != nil {
+		return err
+	}
+	if f.heartbeat != nil {
+		f.heartbeat.Start()
+	}
+	return nil
 }
 
 // WaitForCompletion initiates the log following for the referenced BuildRun's Pod
@@ -0,0 +1,177 @@
+package follower
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+)
+
+func TestFollowerMultiplePods(t *testing.T) {
+	g := o.NewWithT(t)
+
+	newPod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+				Labels:    map[string]string{buildv1alpha1.LabelBuildRun: "br"},
+			},
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "step-build"}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(newPod("pod-1", corev1.PodPending), newPod("pod-2", corev1.PodPending))
+	pw, err := reactor.NewPodWatcher(context.TODO(), time.Minute, clientset, metav1.NamespaceDefault)
+	g.Expect(err).NotTo(o.HaveOccurred())
+
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+	br := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "br"}
+	f := NewFollower(context.TODO(), br, &ioStreams, pw, clientset, shpfake.NewSimpleClientset())
+
+	g.Expect(f.OnEvent(newPod("pod-1", corev1.PodPending))).To(o.Succeed())
+	g.Expect(f.OnEvent(newPod("pod-2", corev1.PodPending))).To(o.Succeed())
+
+	g.Expect(f.OnEvent(newPod("pod-1", corev1.PodSucceeded))).To(o.Succeed())
+	g.Expect(out.String()).To(o.ContainSubstring(`Pod "pod-1" has succeeded!`))
+	g.Expect(out.String()).To(o.ContainSubstring(`Pod "pod-1" is succeeded, still following the logs of pod-2`))
+
+	// completed pods are not followed again
+	out.Reset()
+	g.Expect(f.OnEvent(newPod("pod-1", corev1.PodSucceeded))).To(o.Succeed())
+	g.Expect(out.String()).To(o.BeEmpty())
+
+	g.Expect(f.OnEvent(newPod("pod-2", corev1.PodSucceeded))).To(o.Succeed())
+	g.Expect(out.String()).To(o.ContainSubstring(`Pod "pod-2" has succeeded!`))
+	g.Expect(strings.Count(out.String(), "still following")).To(o.BeZero())
+	g.Expect(f.PodSucceeded()).To(o.BeTrue())
+}
+
+func TestFollowerTimeout(t *testing.T) {
+	newBuildRun := func(reason string, status corev1.ConditionStatus) *buildv1alpha1.BuildRun {
+		br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"}}
+		if reason != "" {
+			br.Status.Conditions = buildv1alpha1.Conditions{{
+				Type: buildv1alpha1.Succeeded, Status: status, Reason: reason, Message: "exceeded 10m0s",
+			}}
+		}
+		return br
+	}
+	newFollower := func(g *o.WithT, br *buildv1alpha1.BuildRun) (*Follower, *bytes.Buffer) {
+		clientset := fake.NewSimpleClientset()
+		pw, err := reactor.NewPodWatcher(context.TODO(), time.Minute, clientset, metav1.NamespaceDefault)
+		g.Expect(err).NotTo(o.HaveOccurred())
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		name := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "br"}
+		f := NewFollower(context.TODO(), name, &ioStreams, pw, clientset, shpfake.NewSimpleClientset(br))
+		f.SetFailPollInterval(time.Millisecond)
+		f.SetTimeout(time.Now(), 10*time.Minute)
+		return f, out
+	}
+
+	t.Run("remaining time", func(t *testing.T) {
+		g := o.NewWithT(t)
+		f, out := newFollower(g, newBuildRun("", ""))
+		f.OnRemaining(4*time.Minute + 29*time.Second + 600*time.Millisecond)
+		g.Expect(out.String()).To(o.Equal("BuildRun \"br\" has 4m30s left before timing out\n"))
+	})
+
+	t.Run("deadline reached while running", func(t *testing.T) {
+		g := o.NewWithT(t)
+		f, out := newFollower(g, newBuildRun("", ""))
+		err := f.OnDeadline()
+		g.Expect(exitcode.Describe(err)).To(o.Equal(exitcode.Details{
+			Class:       "Timeout",
+			Reason:      "BuildRunTimeout",
+			Message:     `BuildRun "br" has exceeded its timeout of 10m0s`,
+			Remediation: exitcode.Describe(exitcode.Errorf(exitcode.Timeout, "")).Remediation,
+			ExitCode:    exitcode.Timeout,
+		}))
+		g.Expect(out.String()).To(o.ContainSubstring(`BuildRun "br" has reached its timeout of 10m0s`))
+	})
+
+	t.Run("deadline reached once done", func(t *testing.T) {
+		g := o.NewWithT(t)
+		f, _ := newFollower(g, newBuildRun("Succeeded", corev1.ConditionTrue))
+		g.Expect(f.OnDeadline()).To(o.Succeed())
+	})
+
+	t.Run("pod failed because of the timeout", func(t *testing.T) {
+		g := o.NewWithT(t)
+		f, _ := newFollower(g, newBuildRun("BuildRunTimeout", corev1.ConditionFalse))
+		err := f.OnEvent(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"},
+			Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+		})
+		g.Expect(exitcode.FromError(err)).To(o.Equal(exitcode.Timeout))
+		g.Expect(err).To(o.MatchError(`BuildRun "br" has timed out: exceeded 10m0s`))
+	})
+}
+
+func TestFollowerPendingReason(t *testing.T) {
+	g := o.NewWithT(t)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type:    corev1.PodScheduled,
+				Status:  corev1.ConditionFalse,
+				Reason:  corev1.PodReasonUnschedulable,
+				Message: "0/3 nodes are available",
+			}},
+		},
+	}
+	clientset := fake.NewSimpleClientset()
+	pw, err := reactor.NewPodWatcher(context.TODO(), time.Minute, clientset, metav1.NamespaceDefault)
+	g.Expect(err).NotTo(o.HaveOccurred())
+
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+	br := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "br"}
+	f := NewFollower(context.TODO(), br, &ioStreams, pw, clientset, shpfake.NewSimpleClientset())
+
+	g.Expect(f.OnEvent(pod)).To(o.Succeed())
+	g.Expect(out.String()).To(o.Equal("Pod \"pod\" is in state \"Pending\" (Unschedulable: 0/3 nodes are available)...\n"))
+}
+
+func TestFollowerHeartbeat(t *testing.T) {
+	g := o.NewWithT(t)
+
+	clientset := fake.NewSimpleClientset()
+	pw, err := reactor.NewPodWatcher(context.TODO(), time.Minute, clientset, metav1.NamespaceDefault)
+	g.Expect(err).NotTo(o.HaveOccurred())
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+	br := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "br"}
+	f := NewFollower(context.TODO(), br, &ioStreams, pw, clientset, shpfake.NewSimpleClientset())
+
+	f.SetHeartbeat(0)
+	g.Expect(f.heartbeat).To(o.BeNil())
+
+	// the messages are written through the heartbeat, postponing it
+	f.SetHeartbeat(time.Minute)
+	g.Expect(f.heartbeat).NotTo(o.BeNil())
+	f.Log("Pod \"pod\" is in state \"Pending\"...\n")
+	g.Expect(out.String()).To(o.Equal("Pod \"pod\" is in state \"Pending\"...\n"))
+
+	// without steps logging yet, the BuildRun is reported
+	f.started = time.Now().Add(-3*time.Minute - 12*time.Second)
+	g.Expect(f.heartbeatMessage()).To(o.Equal("BuildRun \"br\" is still running (3m12s elapsed)\n"))
+	f.Stop()
+}
@@ -0,0 +1,2 @@
+// Package image contains types and functions for image cobra sub-command
+package image
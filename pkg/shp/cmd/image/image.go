@@ -0,0 +1,27 @@
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// Command represents "shp image" sub-command.
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "image",
+		Short: "Inspect the container images produced by BuildRuns",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	command.AddCommand(
+		runner.NewRunner(p, ioStreams, inspectCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, verifyCmd()).Cmd(),
+	)
+	return command
+}
@@ -0,0 +1,249 @@
+package image
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/attest"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+)
+
+// InspectCommand contains data input from user for the image inspect sub-command
+type InspectCommand struct {
+	cmd *cobra.Command
+
+	image      string
+	buildRun   string // BuildRun whose output image is inspected, instead of the informed image
+	platform   string // platform resolved on multi-platform images
+	authSource string // registry credentials source
+	secretName string // docker-registry secret, when the credentials source is "secret"
+	output     string // output format, either empty or "json"
+
+	parsedPlatform *v1.Platform
+}
+
+const inspectLongDesc = `
+Inspects the image, or the image produced by the BuildRun informed on --buildrun, by reading its
+manifest and config from the registry without pulling the layers: digest, platform, created time,
+size, labels, entrypoint and layers. Multi-platform images are resolved to --platform, linux/amd64
+by default, and their platforms are listed.
+
+When --buildrun is informed, and --registry-auth is not, the Build output credentials secret is
+employed to authenticate against the registry. For example:
+
+	$ shp image inspect ghcr.io/org/app:latest
+	$ shp image inspect --buildrun=my-app-xyz12 -o json
+	$ shp image inspect ghcr.io/org/app:latest --platform=linux/arm64
+`
+
+func inspectCmd() runner.SubCommand {
+	c := &InspectCommand{
+		cmd: &cobra.Command{
+			Use:   "inspect [image] [flags]",
+			Short: "Inspect the config, layers and size of an image",
+			Long:  inspectLongDesc,
+			Args:  cobra.MaximumNArgs(1),
+		},
+	}
+
+	c.cmd.Flags().StringVar(&c.buildRun, "buildrun", "", "inspect the output image of the BuildRun, instead of the informed image")
+	c.cmd.Flags().StringVar(&c.platform, "platform", "", "platform resolved on multi-platform images, in the \"os/arch[/variant]\" format")
+	c.cmd.Flags().StringVarP(&c.output, "output", "o", "", "output format, either empty or \"json\"")
+	flags.RegistryAuthFlags(c.cmd.Flags(), &c.authSource, &c.secretName)
+
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *InspectCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *InspectCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	if len(args) > 0 {
+		c.image = args[0]
+	}
+	return nil
+}
+
+// Validate checks either the image or the BuildRun is informed, along with the platform and output
+// format
+func (c *InspectCommand) Validate() error {
+	switch {
+	case c.image == "" && c.buildRun == "":
+		return fmt.Errorf("either the image or --buildrun must be informed")
+	case c.image != "" && c.buildRun != "":
+		return fmt.Errorf("the image and --buildrun are mutually exclusive")
+	}
+	if c.output != "" && c.output != "json" {
+		return fmt.Errorf("unsupported --output %q, only \"json\" is supported", c.output)
+	}
+	if _, err := registry.ParseAuthSource(c.authSource); err != nil {
+		return err
+	}
+	if c.platform != "" {
+		platforms, err := registry.ParsePlatforms([]string{c.platform})
+		if err != nil {
+			return err
+		}
+		c.parsedPlatform = &platforms[0]
+	}
+	return nil
+}
+
+// outputSecretOf returns the name of the output credentials secret of the BuildRun, looking up the
+// Build when the BuildRun does not carry its specification.
+func (c *InspectCommand) outputSecretOf(params *params.Params, br *buildv1alpha1.BuildRun) (string, error) {
+	switch {
+	case br.Spec.Output != nil && br.Spec.Output.Credentials != nil:
+		return br.Spec.Output.Credentials.Name, nil
+	case br.Status.BuildSpec != nil:
+		if br.Status.BuildSpec.Output.Credentials != nil {
+			return br.Status.BuildSpec.Output.Credentials.Name, nil
+		}
+		return "", nil
+	case br.Spec.BuildName() == "":
+		return "", nil
+	}
+	clientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return "", err
+	}
+	b, err := clientset.ShipwrightV1alpha1().Builds(params.Namespace()).Get(c.cmd.Context(), br.Spec.BuildName(), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if b.Spec.Output.Credentials != nil {
+		return b.Spec.Output.Credentials.Name, nil
+	}
+	return "", nil
+}
+
+// keychain returns the registry credentials, the output secret is employed when the credentials
+// source is not informed.
+func (c *InspectCommand) keychain(params *params.Params, outputSecret string) (authn.Keychain, error) {
+	source, err := registry.ParseAuthSource(c.authSource)
+	if err != nil {
+		return nil, err
+	}
+	if !c.cmd.Flags().Changed(flags.RegistryAuthFlag) && outputSecret != "" {
+		source = registry.AuthSecret
+	}
+	opts := registry.AuthOptions{Source: source}
+	if source == registry.AuthSecret {
+		if opts.Clientset, err = params.ClientSet(); err != nil {
+			return nil, err
+		}
+		opts.Namespace = params.Namespace()
+		opts.SecretName = c.secretName
+		if opts.SecretName == "" {
+			opts.SecretName = outputSecret
+		}
+	}
+	return registry.Keychain(c.cmd.Context(), opts)
+}
+
+// Run reads the image details from the registry and prints them
+func (c *InspectCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	image := c.image
+	var outputSecret string
+	if c.buildRun != "" {
+		shpClientset, err := params.ShipwrightClientSet()
+		if err != nil {
+			return err
+		}
+		br, err := shpClientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Get(c.cmd.Context(), c.buildRun, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		ref, err := attest.ImageDigestReference(br)
+		if err != nil {
+			return err
+		}
+		image = ref.String()
+		if outputSecret, err = c.outputSecretOf(params, br); err != nil {
+			return err
+		}
+	}
+
+	keychain, err := c.keychain(params, outputSecret)
+	if err != nil {
+		return err
+	}
+	details, err := registry.InspectImage(c.cmd.Context(), image, keychain, c.parsedPlatform)
+	if err != nil {
+		return err
+	}
+
+	if c.output == "json" {
+		enc := json.NewEncoder(ioStreams.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(details)
+	}
+	return printImageDetails(ioStreams.Out, details)
+}
+
+// printImageDetails renders the image details as a summary followed by its layers.
+func printImageDetails(out io.Writer, details *registry.ImageDetails) error {
+	writer := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(writer, "%s:\t%s\n", name, value)
+		}
+	}
+
+	field("Image", details.Reference)
+	field("Media Type", details.MediaType)
+	field("Platform", details.Platform)
+	field("Platforms", strings.Join(details.Platforms, ", "))
+	if !details.Created.IsZero() {
+		field("Created", details.Created.UTC().Format("2006-01-02 15:04:05 MST"))
+	}
+	field("Size", tail.FormatBytes(details.Size))
+	field("User", details.User)
+	field("Working Dir", details.WorkingDir)
+	field("Entrypoint", strings.Join(details.Entrypoint, " "))
+	field("Cmd", strings.Join(details.Cmd, " "))
+
+	if len(details.Env) > 0 {
+		fmt.Fprintln(writer, "Env:")
+		for _, env := range details.Env {
+			fmt.Fprintf(writer, "  %s\n", env)
+		}
+	}
+	if len(details.Labels) > 0 {
+		keys := make([]string, 0, len(details.Labels))
+		for k := range details.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Fprintln(writer, "Labels:")
+		for _, k := range keys {
+			fmt.Fprintf(writer, "  %s=%s\n", k, details.Labels[k])
+		}
+	}
+
+	fmt.Fprintf(writer, "Layers:\n")
+	fmt.Fprintf(writer, "  DIGEST\tSIZE\tMEDIA TYPE\n")
+	for _, l := range details.Layers {
+		fmt.Fprintf(writer, "  %s\t%s\t%s\n", l.Digest, tail.FormatBytes(l.Size), l.MediaType)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,114 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+)
+
+func TestInspectCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "image", args: []string{"ghcr.io/org/app:v1"}},
+		{name: "buildrun", args: []string{"--buildrun=br", "-o", "json", "--platform=linux/arm64"}},
+		{name: "neither image nor buildrun", args: []string{},
+			err: "either the image or --buildrun must be informed"},
+		{name: "image and buildrun", args: []string{"ghcr.io/org/app:v1", "--buildrun=br"},
+			err: "the image and --buildrun are mutually exclusive"},
+		{name: "unsupported output", args: []string{"ghcr.io/org/app:v1", "-o", "yaml"},
+			err: `unsupported --output "yaml", only "json" is supported`},
+		{name: "invalid platform", args: []string{"ghcr.io/org/app:v1", "--platform=linux"},
+			err: `invalid platform "linux", expected the "os/arch[/variant]" format`},
+		{name: "unsupported registry auth", args: []string{"ghcr.io/org/app:v1", "--registry-auth=other"},
+			err: `unsupported registry authentication "other", expected one of [default ecr gcr acr secret]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			cmd := inspectCmd().(*InspectCommand)
+			g.Expect(cmd.cmd.ParseFlags(tt.args)).To(o.Succeed())
+			g.Expect(cmd.Complete(nil, nil, cmd.cmd.Flags().Args())).To(o.Succeed())
+			err := cmd.Validate()
+			if tt.err == "" {
+				g.Expect(err).To(o.BeNil())
+				return
+			}
+			g.Expect(err).To(o.MatchError(tt.err))
+		})
+	}
+}
+
+func TestInspectCommandOutputSecret(t *testing.T) {
+	g := o.NewWithT(t)
+
+	build := &buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "app"},
+		Spec: buildv1alpha1.BuildSpec{
+			Output: buildv1alpha1.Image{Credentials: &corev1.LocalObjectReference{Name: "push-secret"}},
+		},
+	}
+	p := params.NewParamsForTest(nil, shpfake.NewSimpleClientset(build), nil, metav1.NamespaceDefault, nil, nil)
+	cmd := inspectCmd().(*InspectCommand)
+	cmd.cmd.SetContext(context.TODO())
+
+	// taken from the Build, when the BuildRun does not carry its specification
+	br := &buildv1alpha1.BuildRun{Spec: buildv1alpha1.BuildRunSpec{BuildRef: &buildv1alpha1.BuildRef{Name: "app"}}}
+	secret, err := cmd.outputSecretOf(p, br)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(secret).To(o.Equal("push-secret"))
+
+	br.Status.BuildSpec = &buildv1alpha1.BuildSpec{
+		Output: buildv1alpha1.Image{Credentials: &corev1.LocalObjectReference{Name: "status-secret"}},
+	}
+	secret, err = cmd.outputSecretOf(p, br)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(secret).To(o.Equal("status-secret"))
+
+	br.Spec.Output = &buildv1alpha1.Image{Credentials: &corev1.LocalObjectReference{Name: "override-secret"}}
+	secret, err = cmd.outputSecretOf(p, br)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(secret).To(o.Equal("override-secret"))
+}
+
+func TestPrintImageDetails(t *testing.T) {
+	g := o.NewWithT(t)
+
+	out := &bytes.Buffer{}
+	g.Expect(printImageDetails(out, &registry.ImageDetails{
+		Reference:  "ghcr.io/org/app@sha256:abc",
+		MediaType:  "application/vnd.oci.image.manifest.v1+json",
+		Platform:   "linux/amd64",
+		Platforms:  []string{"linux/amd64", "linux/arm64"},
+		Created:    time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		Size:       2048,
+		Entrypoint: []string{"/app", "serve"},
+		Labels:     map[string]string{"b": "2", "a": "1"},
+		Layers:     []registry.LayerDetails{{Digest: "sha256:def", MediaType: "application/vnd.oci.image.layer.v1.tar+gzip", Size: 1024}},
+	})).To(o.Succeed())
+
+	text := out.String()
+	g.Expect(text).To(o.ContainSubstring("Image:       ghcr.io/org/app@sha256:abc\n"))
+	g.Expect(text).To(o.ContainSubstring("Platforms:   linux/amd64, linux/arm64\n"))
+	g.Expect(text).To(o.ContainSubstring("Created:     2024-03-01 10:00:00 UTC\n"))
+	g.Expect(text).To(o.ContainSubstring("Size:        2.0Ki\n"))
+	g.Expect(text).To(o.ContainSubstring("Entrypoint:  /app serve\n"))
+	g.Expect(text).To(o.ContainSubstring("Labels:\n  a=1\n  b=2\n"))
+	g.Expect(text).To(o.MatchRegexp(`sha256:def\s+1.0Ki\s+application/vnd.oci.image.layer.v1.tar\+gzip`))
+	g.Expect(text).NotTo(o.ContainSubstring("User:"))
+}
@@ -0,0 +1,99 @@
+package image
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/attest"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// VerifyCommand contains data input from user for the image verify sub-command
+type VerifyCommand struct {
+	cmd *cobra.Command
+
+	image    string
+	buildRun string // BuildRun whose output image is verified, instead of the informed image
+	opts     attest.VerifyOptions
+}
+
+const verifyLongDesc = `
+Verifies the cosign signatures of the image, or of the image produced by the BuildRun informed on
+--buildrun, against the trusted signers: the public key on --key, or the identity and OIDC issuer
+of the keyless signing certificate. The exit code is non-zero when no valid signature is found.
+For example:
+
+	$ shp image verify ghcr.io/org/app@sha256:9b2a28eb... --key=cosign.pub
+	$ shp image verify --buildrun=my-app-xyz12 \
+		--certificate-identity=ci@example.com --certificate-oidc-issuer=https://accounts.google.com
+`
+
+func verifyCmd() runner.SubCommand {
+	c := &VerifyCommand{
+		cmd: &cobra.Command{
+			Use:   "verify [image] [flags]",
+			Short: "Verify the signatures of an image",
+			Long:  verifyLongDesc,
+			Args:  cobra.MaximumNArgs(1),
+		},
+	}
+
+	c.cmd.Flags().StringVar(&c.buildRun, "buildrun", "", "verify the output image of the BuildRun, instead of the informed image")
+	c.cmd.Flags().StringVar(&c.opts.Key, "key", "", "public key reference of the trusted signer")
+	c.cmd.Flags().StringVar(&c.opts.CertIdentity, "certificate-identity", "", "identity of the keyless signing certificate, e.g. the signer email")
+	c.cmd.Flags().StringVar(&c.opts.CertIdentityRegexp, "certificate-identity-regexp", "", "regular expression matching the identity of the keyless signing certificate")
+	c.cmd.Flags().StringVar(&c.opts.CertOIDCIssuer, "certificate-oidc-issuer", "", "OIDC issuer of the keyless signing certificate identity")
+	c.cmd.Flags().StringVar(&c.opts.CertOIDCIssuerRegexp, "certificate-oidc-issuer-regexp", "", "regular expression matching the OIDC issuer of the keyless signing certificate")
+
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *VerifyCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *VerifyCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	if len(args) > 0 {
+		c.image = args[0]
+	}
+	return nil
+}
+
+// Validate checks either the image or the BuildRun is informed, along with the trusted signers
+func (c *VerifyCommand) Validate() error {
+	switch {
+	case c.image == "" && c.buildRun == "":
+		return fmt.Errorf("either the image or --buildrun must be informed")
+	case c.image != "" && c.buildRun != "":
+		return fmt.Errorf("the image and --buildrun are mutually exclusive")
+	}
+	return c.opts.Validate()
+}
+
+// Run verifies the image signatures with cosign
+func (c *VerifyCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	image := c.image
+	if c.buildRun != "" {
+		shpClientset, err := params.ShipwrightClientSet()
+		if err != nil {
+			return err
+		}
+		br, err := shpClientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Get(c.cmd.Context(), c.buildRun, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		ref, err := attest.ImageDigestReference(br)
+		if err != nil {
+			return err
+		}
+		image = ref.String()
+	}
+	return attest.Verify(c.cmd.Context(), ioStreams, image, c.opts)
+}
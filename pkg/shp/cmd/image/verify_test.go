@@ -0,0 +1,64 @@
+package image
+
+import (
+	"context"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestVerifyCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "image", args: []string{"ghcr.io/org/app:v1", "--key=cosign.pub"}},
+		{name: "buildrun", args: []string{"--buildrun=br", "--certificate-identity=ci@example.com", "--certificate-oidc-issuer=https://accounts.google.com"}},
+		{name: "neither image nor buildrun", args: []string{"--key=cosign.pub"},
+			err: "either the image or --buildrun must be informed"},
+		{name: "image and buildrun", args: []string{"ghcr.io/org/app:v1", "--buildrun=br", "--key=cosign.pub"},
+			err: "the image and --buildrun are mutually exclusive"},
+		{name: "without signers", args: []string{"ghcr.io/org/app:v1"},
+			err: "either the public key or the certificate identity must be informed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			cmd := verifyCmd().(*VerifyCommand)
+			g.Expect(cmd.cmd.ParseFlags(tt.args)).To(o.Succeed())
+			g.Expect(cmd.Complete(nil, nil, cmd.cmd.Flags().Args())).To(o.Succeed())
+			err := cmd.Validate()
+			if tt.err == "" {
+				g.Expect(err).To(o.BeNil())
+				return
+			}
+			g.Expect(err).To(o.MatchError(tt.err))
+		})
+	}
+}
+
+func TestVerifyCommandBuildRunWithoutDigest(t *testing.T) {
+	g := o.NewWithT(t)
+
+	br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"}}
+	p := params.NewParamsForTest(nil, shpfake.NewSimpleClientset(br), nil, metav1.NamespaceDefault, nil, nil)
+
+	cmd := verifyCmd().(*VerifyCommand)
+	cmd.cmd.SetContext(context.TODO())
+	g.Expect(cmd.cmd.ParseFlags([]string{"--buildrun=br", "--key=cosign.pub"})).To(o.Succeed())
+	g.Expect(cmd.Complete(p, nil, nil)).To(o.Succeed())
+	g.Expect(cmd.Validate()).To(o.Succeed())
+
+	ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+	g.Expect(cmd.Run(p, &ioStreams)).To(o.MatchError(`BuildRun "br" does not report the output image digest`))
+}
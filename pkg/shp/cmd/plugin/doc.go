@@ -0,0 +1,3 @@
+// Package plugin contains the discovery and execution of shp plugins, executables on PATH named
+// after the "shp-" prefix, invoked when the command-line does not match any built-in command.
+package plugin
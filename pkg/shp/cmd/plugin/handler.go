@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// Prefix the executable name prefix identifying shp plugins.
+const Prefix = "shp"
+
+// reservedCommands commands added by cobra upon execution, therefore not known to the root command
+// in advance, which must not be handled as plugins.
+var reservedCommands = []string{
+	"help",
+	"completion",
+	cobra.ShellCompRequestCmd,
+	cobra.ShellCompNoDescRequestCmd,
+}
+
+// Handler finds and executes plugins.
+type Handler interface {
+	// Lookup searches for the plugin executable by name, returning its full path.
+	Lookup(name string) (string, bool)
+	// Execute runs the plugin executable with the informed arguments and environment.
+	Execute(executablePath string, args, environment []string) error
+}
+
+// DefaultHandler finds plugins on PATH, named after one of the valid prefixes.
+type DefaultHandler struct {
+	ValidPrefixes []string
+}
+
+var _ Handler = &DefaultHandler{}
+
+// Lookup searches PATH for the executable named "<prefix>-<name>".
+func (h *DefaultHandler) Lookup(name string) (string, bool) {
+	for _, prefix := range h.ValidPrefixes {
+		path, err := exec.LookPath(prefix + "-" + name)
+		if err != nil || path == "" {
+			continue
+		}
+		return path, true
+	}
+	return "", false
+}
+
+// Execute replaces the current process with the plugin executable, on Windows the plugin runs as
+// a child process instead, and shp exits with the same exit code.
+func (h *DefaultHandler) Execute(executablePath string, args, environment []string) error {
+	if runtime.GOOS != "windows" {
+		return syscall.Exec(executablePath, append([]string{executablePath}, args...), environment)
+	}
+
+	cmd := exec.Command(executablePath, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = environment
+	err := cmd.Run()
+	if err == nil {
+		os.Exit(0)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	return err
+}
+
+// NewDefaultHandler instantiates the DefaultHandler with the informed prefixes.
+func NewDefaultHandler(validPrefixes []string) *DefaultHandler {
+	return &DefaultHandler{ValidPrefixes: validPrefixes}
+}
+
+// IsBuiltin checks whether the arguments match a built-in command, flags and the reserved
+// commands included, in which case plugins are not searched.
+func IsBuiltin(root *cobra.Command, args []string) bool {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return true
+	}
+	for _, name := range reservedCommands {
+		if args[0] == name {
+			return true
+		}
+	}
+	cmd, _, err := root.Find(args)
+	return err == nil && cmd != root
+}
+
+// HandleCommand searches for the plugin matching the leading arguments, preferring the longest
+// name, so "shp foo bar" runs "shp-foo-bar" when present and otherwise "shp-foo". Dashes in the
+// arguments are replaced by underscores, the remaining arguments are passed on to the plugin.
+// Returns false when no plugin is found, when found the plugin is executed and on most platforms
+// this function does not return.
+func HandleCommand(handler Handler, args []string) (bool, error) {
+	parts := []string{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		parts = append(parts, strings.ReplaceAll(arg, "-", "_"))
+	}
+
+	for len(parts) > 0 {
+		path, found := handler.Lookup(strings.Join(parts, "-"))
+		if !found {
+			parts = parts[:len(parts)-1]
+			continue
+		}
+		return true, handler.Execute(path, args[len(parts):], os.Environ())
+	}
+	return false, nil
+}
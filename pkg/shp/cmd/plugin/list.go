@@ -0,0 +1,134 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// ListCommand contains data input from user for the plugin list subcommand
+type ListCommand struct {
+	cmd *cobra.Command
+
+	nameOnly bool
+}
+
+func listCmd() runner.SubCommand {
+	c := &ListCommand{
+		cmd: &cobra.Command{
+			Use:   "list [flags]",
+			Short: "List the plugins found on PATH",
+			Args:  cobra.NoArgs,
+		},
+	}
+
+	c.cmd.Flags().BoolVar(&c.nameOnly, "name-only", false, "show only the plugin executable names")
+
+	return c
+}
+
+// Cmd returns cobra command object of the plugin list subcommand
+func (c *ListCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *ListCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	return nil
+}
+
+// Validate validates data input by user
+func (c *ListCommand) Validate() error {
+	return nil
+}
+
+// Run prints the plugins found on PATH, warning about the ones that can't be invoked
+func (c *ListCommand) Run(_ *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	plugins := FindPlugins(filepath.SplitList(os.Getenv("PATH")))
+	if len(plugins) == 0 {
+		return fmt.Errorf("unable to find any shp plugins in PATH")
+	}
+
+	fmt.Fprintln(ioStreams.Out, "The following compatible plugins are available:")
+	fmt.Fprintln(ioStreams.Out)
+
+	seen := map[string]string{}
+	for _, path := range plugins {
+		if c.nameOnly {
+			fmt.Fprintln(ioStreams.Out, filepath.Base(path))
+		} else {
+			fmt.Fprintln(ioStreams.Out, path)
+		}
+
+		name := pluginName(path)
+		if previous, exists := seen[name]; exists {
+			fmt.Fprintf(ioStreams.ErrOut, "  - warning: %s is overshadowed by a similarly named plugin: %s\n",
+				path, previous)
+			continue
+		}
+		seen[name] = path
+		if IsBuiltin(c.cmd.Root(), strings.Split(name, "-")) {
+			fmt.Fprintf(ioStreams.ErrOut, "  - warning: %s overwrites an existing command: %q\n",
+				path, "shp "+strings.ReplaceAll(name, "-", " "))
+		}
+	}
+	return nil
+}
+
+// FindPlugins returns the plugin executables found in the informed directories, in order.
+func FindPlugins(dirs []string) []string {
+	plugins := []string{}
+	for _, dir := range dirs {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), Prefix+"-") {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if isExecutable(path) {
+				plugins = append(plugins, path)
+			}
+		}
+	}
+	return plugins
+}
+
+// pluginName returns the plugin name out of the executable path, without prefix and extension.
+func pluginName(path string) string {
+	name := strings.TrimPrefix(filepath.Base(path), Prefix+"-")
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+// isExecutable checks whether the file is executable, on Windows based on the file extension.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".bat", ".cmd", ".com", ".exe", ".ps1":
+			return true
+		}
+		return false
+	}
+	return info.Mode()&0111 != 0
+}
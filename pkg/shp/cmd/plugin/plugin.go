@@ -0,0 +1,29 @@
+package plugin
+
+import (
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// Command returns the "plugin" command of Shipwright CLI, grouping the plugin subcommands.
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "plugin",
+		Short: "Inspect shp plugins",
+		Long: `
+Plugins are executables on PATH named "shp-<name>", invoked as "shp <name>" whenever the name does
+not match a built-in command. Dashes in the plugin name map to subcommands, so "shp-foo-bar" is
+invoked as "shp foo bar".
+`,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	command.AddCommand(runner.NewRunner(p, ioStreams, listCmd()).Cmd())
+	return command
+}
@@ -0,0 +1,99 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+)
+
+type fakeHandler struct {
+	plugins  map[string]string
+	executed string
+	args     []string
+}
+
+func (h *fakeHandler) Lookup(name string) (string, bool) {
+	path, found := h.plugins[name]
+	return path, found
+}
+
+func (h *fakeHandler) Execute(executablePath string, args, _ []string) error {
+	h.executed = executablePath
+	h.args = args
+	return nil
+}
+
+func TestIsBuiltin(t *testing.T) {
+	g := o.NewWithT(t)
+
+	root := &cobra.Command{Use: "shp", Args: cobra.ArbitraryArgs}
+	build := &cobra.Command{Use: "build"}
+	build.AddCommand(&cobra.Command{Use: "create"})
+	root.AddCommand(build)
+
+	g.Expect(IsBuiltin(root, []string{})).To(o.BeTrue())
+	g.Expect(IsBuiltin(root, []string{"--help"})).To(o.BeTrue())
+	g.Expect(IsBuiltin(root, []string{"help", "promote"})).To(o.BeTrue())
+	g.Expect(IsBuiltin(root, []string{"completion", "bash"})).To(o.BeTrue())
+	g.Expect(IsBuiltin(root, []string{"build", "create", "name"})).To(o.BeTrue())
+	g.Expect(IsBuiltin(root, []string{"promote", "name"})).To(o.BeFalse())
+}
+
+func TestHandleCommand(t *testing.T) {
+	g := o.NewWithT(t)
+
+	handler := &fakeHandler{plugins: map[string]string{
+		"promote":         "/bin/shp-promote",
+		"promote-to_prod": "/bin/shp-promote-to_prod",
+	}}
+
+	t.Run("longest plugin name is preferred", func(t *testing.T) {
+		found, err := HandleCommand(handler, []string{"promote", "to-prod", "app", "--force"})
+		g.Expect(err).ToNot(o.HaveOccurred())
+		g.Expect(found).To(o.BeTrue())
+		g.Expect(handler.executed).To(o.Equal("/bin/shp-promote-to_prod"))
+		g.Expect(handler.args).To(o.Equal([]string{"app", "--force"}))
+	})
+
+	t.Run("falls back to shorter plugin names", func(t *testing.T) {
+		found, err := HandleCommand(handler, []string{"promote", "app", "--force"})
+		g.Expect(err).ToNot(o.HaveOccurred())
+		g.Expect(found).To(o.BeTrue())
+		g.Expect(handler.executed).To(o.Equal("/bin/shp-promote"))
+		g.Expect(handler.args).To(o.Equal([]string{"app", "--force"}))
+	})
+
+	t.Run("unknown plugin", func(t *testing.T) {
+		found, err := HandleCommand(handler, []string{"deploy", "app"})
+		g.Expect(err).ToNot(o.HaveOccurred())
+		g.Expect(found).To(o.BeFalse())
+	})
+}
+
+func TestFindPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits are not available on Windows")
+	}
+	g := o.NewWithT(t)
+
+	first, second := t.TempDir(), t.TempDir()
+	for path, mode := range map[string]os.FileMode{
+		filepath.Join(first, "shp-promote"):  0755,
+		filepath.Join(first, "shp-readme"):   0644,
+		filepath.Join(first, "kubectl-foo"):  0755,
+		filepath.Join(second, "shp-promote"): 0755,
+	} {
+		g.Expect(os.WriteFile(path, []byte("#!/bin/sh\n"), mode)).To(o.Succeed())
+	}
+
+	plugins := FindPlugins([]string{first, "", filepath.Join(first, "missing"), second})
+	g.Expect(plugins).To(o.Equal([]string{
+		filepath.Join(first, "shp-promote"),
+		filepath.Join(second, "shp-promote"),
+	}))
+	g.Expect(pluginName(plugins[0])).To(o.Equal("promote"))
+}
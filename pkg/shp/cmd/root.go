@@ -7,8 +7,20 @@ import (
 
 	"github.com/shipwright-io/cli/pkg/shp/cmd/build"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/buildrun"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/check"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/cleanup"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/completion"
+	configcmd "github.com/shipwright-io/cli/pkg/shp/cmd/config"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/image"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/plugin"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/secret"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/stats"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/status"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/version"
+	"github.com/shipwright-io/cli/pkg/shp/config"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
 	"github.com/shipwright-io/cli/pkg/shp/suggestion"
 )
 
@@ -22,16 +34,56 @@ var rootCmd = &cobra.Command{
 // NewCmdSHP create a new SHP root command, linking together all sub-commands organized by groups.
 func NewCmdSHP(ioStreams *genericclioptions.IOStreams) *cobra.Command {
 	p := params.NewParams()
+	p.SetVersion(version.Current())
 	p.AddFlags(rootCmd.PersistentFlags())
-	rootCmd.AddCommand(version.Command())
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		styles.Configure(ioStreams.Out, p.NoColor())
+		if format := p.ErrorFormat(); format != exitcode.FormatText && format != exitcode.FormatJSON {
+			return exitcode.Errorf(exitcode.Usage, "unsupported --%s %q, expected one of %v",
+				params.ErrorFormatFlag, format, exitcode.Formats)
+		}
+		return applyConfigDefaults(cmd, args)
+	}
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return exitcode.Wrap(exitcode.Usage, err)
+	})
+	rootCmd.AddCommand(version.Command(p, ioStreams))
 	rootCmd.AddCommand(build.Command(p, ioStreams))
 	rootCmd.AddCommand(buildrun.Command(p, ioStreams))
+	rootCmd.AddCommand(secret.Command(p, ioStreams))
+	rootCmd.AddCommand(image.Command(p, ioStreams))
+	rootCmd.AddCommand(status.Command(p, ioStreams))
+	rootCmd.AddCommand(check.Command(p, ioStreams))
+	rootCmd.AddCommand(cleanup.Command(p, ioStreams))
+	rootCmd.AddCommand(stats.Command(p, ioStreams))
+	rootCmd.AddCommand(plugin.Command(p, ioStreams))
+	rootCmd.AddCommand(configcmd.Command(p, ioStreams))
+	rootCmd.AddCommand(completion.Command(p, ioStreams))
+	rootCmd.AddCommand(exitCodesHelpTopic())
 
 	visitCommands(rootCmd, reconfigureCommandWithSubcommand)
+	visitCommands(rootCmd, reconfigureValidationWithUsageExitCode)
 
 	return rootCmd
 }
 
+// applyConfigDefaults loads the configuration file and sets the flags not informed explicitly. The
+// commands opting out don't load the file, thus an invalid file can still be repaired with them.
+func applyConfigDefaults(cmd *cobra.Command, args []string) error {
+	if _, skip := cmd.Annotations[config.SkipDefaultsAnnotation]; skip {
+		return nil
+	}
+	path, err := config.Path()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	return cfg.ApplyDefaults(cmd, args)
+}
+
 func reconfigureCommandWithSubcommand(cmd *cobra.Command) {
 	if len(cmd.Commands()) == 0 {
 		return
@@ -42,7 +94,28 @@ func reconfigureCommandWithSubcommand(cmd *cobra.Command) {
 	}
 
 	if cmd.RunE == nil {
-		cmd.RunE = suggestion.SubcommandsRequiredWithSuggestions
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			return exitcode.Wrap(exitcode.Usage, suggestion.SubcommandsRequiredWithSuggestions(cmd, args))
+		}
+	}
+}
+
+// reconfigureValidationWithUsageExitCode marks the errors of the positional arguments and required
+// flags validation with the usage exit code. The required flags are validated ahead of cobra, after
+// the configuration defaults are applied.
+func reconfigureValidationWithUsageExitCode(cmd *cobra.Command) {
+	if validateArgs := cmd.Args; validateArgs != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			return exitcode.Wrap(exitcode.Usage, validateArgs(cmd, args))
+		}
+	}
+	if cmd.PreRunE == nil {
+		cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+			if err := cmd.ValidateRequiredFlags(); err != nil {
+				return exitcode.Wrap(exitcode.Usage, err)
+			}
+			return exitcode.Wrap(exitcode.Usage, cmd.ValidateFlagGroups())
+		}
 	}
 }
 
@@ -3,12 +3,16 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/onsi/gomega"
 	"github.com/shipwright-io/cli/test/stub"
+	"github.com/spf13/cobra"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/config"
 )
 
 func TestCMD_NewCmdSHP(t *testing.T) {
@@ -27,3 +31,18 @@ func TestCMD_NewCmdSHP(t *testing.T) {
 
 	g.Expect(err.Error()).To(gomega.Equal(expected))
 }
+
+func TestCMD_ApplyConfigDefaultsInvalidFile(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	g.Expect(os.WriteFile(path, []byte("colour: blue\n"), 0o600)).To(gomega.Succeed())
+	t.Setenv(config.EnvVar, path)
+
+	cmd := &cobra.Command{Use: "list"}
+	g.Expect(applyConfigDefaults(cmd, nil)).To(gomega.MatchError(gomega.ContainSubstring("invalid configuration file")))
+
+	// the config subcommands must work to repair the file
+	cmd.Annotations = map[string]string{config.SkipDefaultsAnnotation: ""}
+	g.Expect(applyConfigDefaults(cmd, nil)).To(gomega.Succeed())
+}
@@ -1,11 +1,19 @@
 package runner
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/spf13/cobra"
 
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/trace"
 )
 
 // Runner execute the sub-command lifecycle, wrapper around sub-commands.
@@ -24,15 +32,69 @@ func (r *Runner) Cmd() *cobra.Command {
 }
 
 // RunE cobra.Command's RunE implementation focusing on sub-commands lifecycle. To achieve it, a
-// dynamic client and configured namespace are informed.
-func (r *Runner) RunE(_ *cobra.Command, args []string) error {
+// dynamic client and configured namespace are informed. The command context is bound to the
+// --request-timeout deadline, when informed. The command is recorded as a span when tracing is
+// enabled, exported once it's done.
+func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
+	tracer := r.p.NewTracer()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if tracer != nil {
+		ctx = trace.WithTracer(ctx, tracer)
+	}
+	ctx, span := trace.Start(ctx, cmd.CommandPath())
+	cmd.SetContext(ctx)
+
+	err := r.run(cmd, args)
+	span.RecordError(err)
+	span.End()
+	if exportErr := r.p.ExportTrace(tracer); exportErr != nil {
+		fmt.Fprintf(r.ioStreams.ErrOut, "Warning: %v\n", exportErr)
+	}
+	return err
+}
+
+// run completes, validates and runs the sub-command.
+func (r *Runner) run(cmd *cobra.Command, args []string) error {
+	ctx, cancel, err := r.p.RequestContext(cmd.Context())
+	if err != nil {
+		return usageError(err)
+	}
+	defer cancel()
+	cmd.SetContext(ctx)
+
 	if err := r.subCmd.Complete(r.p, r.ioStreams, args); err != nil {
-		return err
+		return usageError(err)
 	}
 	if err := r.subCmd.Validate(); err != nil {
+		return usageError(err)
+	}
+	return timeoutError(ctx, r.subCmd.Run(r.p, r.ioStreams))
+}
+
+// timeoutError marks the errors of a command interrupted by the --request-timeout deadline as a
+// timeout, instead of the errors of the calls interrupted, with the timeout exit code.
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	var timeoutErr *reactor.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		err = &reactor.TimeoutError{Message: reactor.RequestTimeoutMessage, Err: err}
+	}
+	return exitcode.Wrap(exitcode.Timeout, err)
+}
+
+// usageError marks errors completing and validating the user input with the usage exit code,
+// errors returned by the cluster keep their own classification.
+func usageError(err error) error {
+	var status kerrors.APIStatus
+	if errors.As(err, &status) {
 		return err
 	}
-	return r.subCmd.Run(r.p, r.ioStreams)
+	return exitcode.Wrap(exitcode.Usage, err)
 }
 
 // NewRunner instantiate a Runner.
@@ -1,15 +1,19 @@
 package runner
 
 import (
+	"errors"
 	"os"
 	"testing"
 
 	"github.com/onsi/gomega"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
 )
 
 type mockedSubCommand struct{}
@@ -52,3 +56,37 @@ func TestCMD_Runner(t *testing.T) {
 		g.Expect(err).To(gomega.BeNil())
 	})
 }
+
+// blockingSubCommand runs until its context is done.
+type blockingSubCommand struct {
+	mockedSubCommand
+	cmd *cobra.Command
+}
+
+func (b *blockingSubCommand) Cmd() *cobra.Command {
+	return b.cmd
+}
+
+func (b *blockingSubCommand) Run(_ *params.Params, _ *genericclioptions.IOStreams) error {
+	<-b.cmd.Context().Done()
+	return b.cmd.Context().Err()
+}
+
+func TestCMD_RunnerRequestTimeout(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	p := params.NewParams()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	p.AddFlags(flags)
+	g.Expect(flags.Parse([]string{"--request-timeout=10ms"})).To(gomega.Succeed())
+
+	genericStreams := &genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	sub := &blockingSubCommand{cmd: &cobra.Command{}}
+	r := NewRunner(p, genericStreams, sub)
+
+	err := r.RunE(sub.Cmd(), []string{})
+	var timeoutErr *reactor.TimeoutError
+	g.Expect(errors.As(err, &timeoutErr)).To(gomega.BeTrue())
+	g.Expect(timeoutErr.Message).To(gomega.Equal(reactor.RequestTimeoutMessage))
+	g.Expect(exitcode.FromError(err)).To(gomega.Equal(exitcode.Timeout))
+}
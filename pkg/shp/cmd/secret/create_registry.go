@@ -0,0 +1,260 @@
+package secret
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	dockerconfig "github.com/docker/cli/cli/config"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+)
+
+// CreateRegistryCommand contains data input from user for the create-registry sub-command
+type CreateRegistryCommand struct {
+	cmd *cobra.Command
+
+	name             string // secret name
+	server           string // registry server
+	username         string // registry username
+	password         string // registry password
+	email            string // registry email, optional
+	fromDockerConfig bool   // flag to read credentials from the local docker configuration
+	dockerConfigDir  string // directory of the local docker configuration
+	buildName        string // build to wire the secret as output credentials
+	provider         string // credential provider minting a short-lived registry token
+}
+
+const createRegistryLongDesc = `
+Creates, or updates, a docker-registry Secret annotated for Shipwright, using either the informed
+credentials or the ones found on the local Docker configuration (~/.docker/config.json), including
+its credential helpers. For example:
+
+	$ shp secret create-registry push-secret --server=quay.io --username=user --password=pass
+	$ shp secret create-registry push-secret --from-docker-config --server=quay.io
+
+The Secret can be used as the Build's output credentials in the same step:
+
+	$ shp secret create-registry push-secret --from-docker-config --build=my-app
+
+For cloud registries, --credentials-provider mints a short-lived token using the cloud provider CLI
+(aws, gcloud or az), or the instance metadata service, running the command again refreshes it:
+
+	$ shp secret create-registry push-secret --server=123456789012.dkr.ecr.eu-west-1.amazonaws.com --credentials-provider=auto
+`
+
+// dockerConfigJSON represents the ".dockerconfigjson" payload of docker-registry secrets.
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+// dockerConfigEntry credentials for a single registry.
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+func createRegistryCmd() runner.SubCommand {
+	c := &CreateRegistryCommand{
+		cmd: &cobra.Command{
+			Use:   "create-registry <name>",
+			Short: "Create or update a docker-registry Secret",
+			Long:  createRegistryLongDesc,
+			Args:  cobra.ExactArgs(1),
+		},
+	}
+
+	c.cmd.Flags().StringVar(&c.server, "server", "", "registry server, e.g. quay.io")
+	c.cmd.Flags().StringVar(&c.username, "username", "", "registry username")
+	c.cmd.Flags().StringVar(&c.password, "password", "", "registry password or token")
+	c.cmd.Flags().StringVar(&c.email, "email", "", "registry email, optional")
+	c.cmd.Flags().BoolVar(&c.fromDockerConfig, "from-docker-config", false, "read the credentials from the local Docker configuration, only for --server when informed")
+	c.cmd.Flags().StringVar(&c.dockerConfigDir, "docker-config-dir", "", "directory of the local Docker configuration, defaults to $DOCKER_CONFIG or ~/.docker")
+	c.cmd.Flags().StringVar(&c.buildName, "build", "", "Build to use the Secret as output credentials")
+	c.cmd.Flags().StringVar(&c.provider, "credentials-provider", "", fmt.Sprintf(
+		"mint a short-lived token for --server, %q or one of %v", registry.ProviderAuto, registry.ProviderNames()))
+	return c
+}
+
+// Cmd returns cobra command object of the create-registry subcommand
+func (c *CreateRegistryCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills CreateRegistryCommand structure with data obtained from cobra command
+func (c *CreateRegistryCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+	return nil
+}
+
+// Validate is used for validation of user input data
+func (c *CreateRegistryCommand) Validate() error {
+	if c.provider != "" {
+		if c.fromDockerConfig || c.username != "" || c.password != "" || c.email != "" {
+			return fmt.Errorf("--credentials-provider can not be combined with --from-docker-config, --username, --password or --email")
+		}
+		if c.server == "" {
+			return fmt.Errorf("--server is required by --credentials-provider")
+		}
+		return registry.ValidateProvider(c.provider)
+	}
+	if c.fromDockerConfig {
+		if c.username != "" || c.password != "" || c.email != "" {
+			return fmt.Errorf("--from-docker-config can not be combined with --username, --password or --email")
+		}
+		return nil
+	}
+	if c.dockerConfigDir != "" {
+		return fmt.Errorf("--docker-config-dir requires --from-docker-config")
+	}
+	if c.server == "" || c.username == "" || c.password == "" {
+		return fmt.Errorf("--server, --username and --password are required, unless --from-docker-config or --credentials-provider are used")
+	}
+	return nil
+}
+
+// Run creates, or updates, the secret and wires it into the Build when requested
+func (c *CreateRegistryCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	ctx := c.cmd.Context()
+	data, err := c.dockerConfigJSON(ctx)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.name,
+			Namespace: params.Namespace(),
+			Annotations: map[string]string{
+				buildv1alpha1.AnnotationBuildRefSecret: "true",
+			},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: data},
+	}
+
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+	secrets := clientset.CoreV1().Secrets(params.Namespace())
+	if _, err = secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return err
+		}
+		existing, err := secrets.Get(ctx, c.name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if existing.Type != corev1.SecretTypeDockerConfigJson {
+			return fmt.Errorf("secret %q already exists with type %q", c.name, existing.Type)
+		}
+		if existing.Annotations == nil {
+			existing.Annotations = map[string]string{}
+		}
+		existing.Annotations[buildv1alpha1.AnnotationBuildRefSecret] = "true"
+		existing.Data = secret.Data
+		if _, err = secrets.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(ioStreams.Out, "Updated secret %q\n", c.name)
+	} else {
+		fmt.Fprintf(ioStreams.Out, "Created secret %q\n", c.name)
+	}
+
+	if c.buildName == "" {
+		return nil
+	}
+	shpClientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	builds := shpClientset.ShipwrightV1alpha1().Builds(params.Namespace())
+	b, err := builds.Get(ctx, c.buildName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	b.Spec.Output.Credentials = &corev1.LocalObjectReference{Name: c.name}
+	if _, err = builds.Update(ctx, b, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "Build %q uses secret %q as output credentials\n", c.buildName, c.name)
+	return nil
+}
+
+// dockerConfigJSON renders the secret payload, either from the informed credentials, the ones
+// minted by the credential provider or the local Docker configuration.
+func (c *CreateRegistryCommand) dockerConfigJSON(ctx context.Context) ([]byte, error) {
+	config := dockerConfigJSON{Auths: map[string]dockerConfigEntry{}}
+
+	if c.provider != "" {
+		provider, err := registry.ProviderFor(c.provider, c.server)
+		if err != nil {
+			return nil, err
+		}
+		credentials, err := provider.Mint(ctx, c.server)
+		if err != nil {
+			return nil, fmt.Errorf("unable to mint %s credentials for %q: %w", provider.Name(), c.server, err)
+		}
+		config.Auths[c.server] = newDockerConfigEntry(credentials.Username, credentials.Password, "")
+		return json.Marshal(config)
+	}
+
+	if !c.fromDockerConfig {
+		config.Auths[c.server] = newDockerConfigEntry(c.username, c.password, c.email)
+		return json.Marshal(config)
+	}
+
+	dir := c.dockerConfigDir
+	if dir == "" {
+		dir = dockerconfig.Dir()
+	}
+	configFile, err := dockerconfig.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	// resolving the entries stored on credential helpers as well
+	credentials, err := configFile.GetAllCredentials()
+	if err != nil {
+		return nil, err
+	}
+	for server, auth := range credentials {
+		if c.server != "" && server != c.server {
+			continue
+		}
+		if auth.Username == "" && auth.Password == "" {
+			continue
+		}
+		config.Auths[server] = newDockerConfigEntry(auth.Username, auth.Password, auth.Email)
+	}
+	if len(config.Auths) == 0 {
+		if c.server != "" {
+			return nil, fmt.Errorf("no credentials for %q found on the Docker configuration at %q", c.server, dir)
+		}
+		return nil, fmt.Errorf("no credentials found on the Docker configuration at %q", dir)
+	}
+	return json.Marshal(config)
+}
+
+// newDockerConfigEntry creates the registry entry, including the encoded "auth" field expected by
+// most container tools.
+func newDockerConfigEntry(username, password, email string) dockerConfigEntry {
+	return dockerConfigEntry{
+		Username: username,
+		Password: password,
+		Email:    email,
+		Auth:     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+	}
+}
@@ -0,0 +1,97 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestCreateRegistrySecret(t *testing.T) {
+	g := o.NewWithT(t)
+
+	b := &buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: metav1.NamespaceDefault},
+	}
+	clientset := fake.NewSimpleClientset()
+	shpClientset := shpfake.NewSimpleClientset(b)
+	p := params.NewParamsForTest(clientset, shpClientset, nil, metav1.NamespaceDefault, nil, nil)
+	ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+
+	run := func(c *CreateRegistryCommand) error {
+		c.cmd = &cobra.Command{}
+		c.cmd.SetContext(context.TODO())
+		if err := c.Complete(p, &ioStreams, []string{"push-secret"}); err != nil {
+			return err
+		}
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		return c.Run(p, &ioStreams)
+	}
+
+	g.Expect(run(&CreateRegistryCommand{server: "quay.io", username: "user"})).NotTo(o.Succeed())
+
+	g.Expect(run(&CreateRegistryCommand{
+		server:    "quay.io",
+		username:  "user",
+		password:  "pass",
+		buildName: "my-app",
+	})).To(o.Succeed())
+
+	secret, err := clientset.CoreV1().Secrets(metav1.NamespaceDefault).Get(context.TODO(), "push-secret", metav1.GetOptions{})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(secret.Type).To(o.Equal(corev1.SecretTypeDockerConfigJson))
+	g.Expect(secret.Annotations).To(o.HaveKeyWithValue(buildv1alpha1.AnnotationBuildRefSecret, "true"))
+
+	var config dockerConfigJSON
+	g.Expect(json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config)).To(o.Succeed())
+	g.Expect(config.Auths).To(o.HaveKeyWithValue("quay.io", dockerConfigEntry{
+		Username: "user",
+		Password: "pass",
+		Auth:     "dXNlcjpwYXNz",
+	}))
+
+	b, err = shpClientset.ShipwrightV1alpha1().Builds(metav1.NamespaceDefault).Get(context.TODO(), "my-app", metav1.GetOptions{})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(b.Spec.Output.Credentials).To(o.Equal(&corev1.LocalObjectReference{Name: "push-secret"}))
+
+	// updating the existing secret from the local docker configuration
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"auths":{
+		"quay.io":{"auth":"dXNlcjpzZWNyZXQ="},
+		"ghcr.io":{"auth":"b3RoZXI6c2VjcmV0"}
+	}}`), 0o600)).To(o.Succeed())
+
+	g.Expect(run(&CreateRegistryCommand{
+		server:           "quay.io",
+		fromDockerConfig: true,
+		dockerConfigDir:  dir,
+	})).To(o.Succeed())
+
+	secret, err = clientset.CoreV1().Secrets(metav1.NamespaceDefault).Get(context.TODO(), "push-secret", metav1.GetOptions{})
+	g.Expect(err).To(o.BeNil())
+	config = dockerConfigJSON{}
+	g.Expect(json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config)).To(o.Succeed())
+	g.Expect(config.Auths).To(o.HaveLen(1))
+	g.Expect(config.Auths["quay.io"].Password).To(o.Equal("secret"))
+
+	g.Expect(run(&CreateRegistryCommand{
+		server:           "docker.io",
+		fromDockerConfig: true,
+		dockerConfigDir:  dir,
+	})).NotTo(o.Succeed())
+}
@@ -0,0 +1,2 @@
+// Package secret contains types and functions for secret cobra sub-command
+package secret
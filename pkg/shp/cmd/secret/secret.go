@@ -0,0 +1,26 @@
+package secret
+
+import (
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// Command represents "shp secret" sub-command.
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "secret",
+		Short: "Manage Secrets used by Builds",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	command.AddCommand(
+		runner.NewRunner(p, ioStreams, createRegistryCmd()).Cmd(),
+	)
+	return command
+}
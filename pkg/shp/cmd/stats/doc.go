@@ -0,0 +1,3 @@
+// Package stats contains types and functions for the stats cobra command, statistics over the
+// historical BuildRuns for capacity planning.
+package stats
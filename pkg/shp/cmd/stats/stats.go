@@ -0,0 +1,393 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// Supported output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// formats all supported output formats.
+var formats = []string{formatTable, formatJSON, formatCSV}
+
+// StatsCommand contains data input from user for the stats command
+type StatsCommand struct {
+	cmd *cobra.Command
+
+	allNamespaces bool          // account every namespace
+	build         string        // only account the BuildRuns of this Build
+	since         time.Duration // window of completed BuildRuns accounted, zero accounts all
+	output        string        // output format
+	now           func() time.Time
+}
+
+const statsLongDesc = `
+Computes statistics over the completed BuildRuns of the namespace, or of a single Build: the amount
+of runs, the success rate, the 50th, 90th and 99th percentile of the durations, the breakdown per
+build strategy, and a histogram of the durations. For example:
+
+	$ shp stats --since=168h
+	$ shp stats --build=my-app -o json
+
+The statistics can be exported as CSV for capacity planning, one row for all the runs followed by
+a row per strategy:
+
+	$ shp stats --all-namespaces -o csv > buildruns.csv
+`
+
+// unknownStrategy scope of the BuildRuns without a strategy recorded.
+const unknownStrategy = "<unknown>"
+
+// histogramWidth maximum width of the histogram bars.
+const histogramWidth = 30
+
+// bucket upper bounds of the duration histogram, the last bucket is unbounded.
+var bucketBounds = []time.Duration{
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	30 * time.Minute,
+}
+
+// Command returns the "stats" command of Shipwright CLI.
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	cmd := runner.NewRunner(p, ioStreams, statsCmd()).Cmd()
+	cmd.Annotations = map[string]string{
+		"commandType": "main",
+	}
+	return cmd
+}
+
+func statsCmd() runner.SubCommand {
+	c := &StatsCommand{
+		cmd: &cobra.Command{
+			Use:   "stats [flags]",
+			Short: "Show statistics of the BuildRun durations",
+			Long:  statsLongDesc,
+			Args:  cobra.NoArgs,
+		},
+		now: time.Now,
+	}
+
+	f := c.cmd.Flags()
+	f.BoolVarP(&c.allNamespaces, "all-namespaces", "A", false, "Account the BuildRuns of every namespace")
+	f.StringVar(&c.build, "build", "", "Only account the BuildRuns of the Build")
+	f.DurationVar(&c.since, "since", 0, "Only account the BuildRuns completed within this window, zero accounts all")
+	f.StringVarP(&c.output, "output", "o", formatTable, fmt.Sprintf("Output format, one of %v", formats))
+
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *StatsCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *StatsCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	return nil
+}
+
+// Validate validates data input by user
+func (c *StatsCommand) Validate() error {
+	if c.since < 0 {
+		return fmt.Errorf("--since must not be negative")
+	}
+	switch c.output {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	default:
+		return fmt.Errorf("unsupported --output %q, expected one of %v", c.output, formats)
+	}
+}
+
+// Stats statistics of a set of completed BuildRuns, the durations are expressed in seconds.
+type Stats struct {
+	Scope       string  `json:"scope"`
+	Runs        int     `json:"runs"`
+	Succeeded   int     `json:"succeeded"`
+	Failed      int     `json:"failed"`
+	SuccessRate float64 `json:"successRate"`
+	P50Seconds  float64 `json:"p50Seconds"`
+	P90Seconds  float64 `json:"p90Seconds"`
+	P99Seconds  float64 `json:"p99Seconds"`
+}
+
+// Bucket amount of BuildRuns whose duration is within the bucket range.
+type Bucket struct {
+	Range string `json:"range"`
+	Runs  int    `json:"runs"`
+}
+
+// report statistics of all runs, per strategy, and the durations histogram.
+type report struct {
+	Namespace  string   `json:"namespace,omitempty"`
+	Build      string   `json:"build,omitempty"`
+	Total      Stats    `json:"total"`
+	Strategies []Stats  `json:"strategies"`
+	Histogram  []Bucket `json:"histogram"`
+}
+
+// sample outcome and duration of a completed BuildRun.
+type sample struct {
+	succeeded bool
+	duration  time.Duration
+}
+
+// Run lists the BuildRuns and renders the statistics
+func (c *StatsCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	clientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	ns := p.Namespace()
+	if c.allNamespaces {
+		ns = metav1.NamespaceAll
+	}
+	brList, err := clientset.ShipwrightV1alpha1().BuildRuns(ns).List(c.cmd.Context(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var after time.Time
+	if c.since > 0 {
+		after = c.now().Add(-c.since)
+	}
+	r := c.compute(brList.Items, after)
+	r.Namespace = ns
+
+	switch c.output {
+	case formatJSON:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(ioStreams.Out, string(data))
+		return nil
+	case formatCSV:
+		return renderCSV(ioStreams.Out, r)
+	default:
+		return c.render(ioStreams.Out, r)
+	}
+}
+
+// compute accounts the completed BuildRuns after the informed moment, a zero moment accounts all.
+func (c *StatsCommand) compute(buildRuns []buildv1alpha1.BuildRun, after time.Time) *report {
+	all := []sample{}
+	byStrategy := map[string][]sample{}
+	for i := range buildRuns {
+		br := &buildRuns[i]
+		if c.build != "" && br.Spec.BuildName() != c.build {
+			continue
+		}
+		phase := util.PhaseOf(br)
+		if phase != util.PhaseSucceeded && phase != util.PhaseFailed {
+			continue
+		}
+		completed := util.CompletionTimeOf(br)
+		if !after.IsZero() && !completed.After(after) {
+			continue
+		}
+
+		started := br.CreationTimestamp.Time
+		if br.Status.StartTime != nil {
+			started = br.Status.StartTime.Time
+		}
+		s := sample{succeeded: phase == util.PhaseSucceeded, duration: completed.Sub(started)}
+		all = append(all, s)
+		strategy := strategyOf(br)
+		byStrategy[strategy] = append(byStrategy[strategy], s)
+	}
+
+	r := &report{Build: c.build, Total: statsOf("all", all), Strategies: []Stats{}, Histogram: histogramOf(all)}
+	for strategy, samples := range byStrategy {
+		r.Strategies = append(r.Strategies, statsOf(strategy, samples))
+	}
+	sort.Slice(r.Strategies, func(i, j int) bool {
+		return r.Strategies[i].Scope < r.Strategies[j].Scope
+	})
+	return r
+}
+
+// strategyOf returns the strategy name recorded by the build controller, or embedded on the
+// BuildRun.
+func strategyOf(br *buildv1alpha1.BuildRun) string {
+	switch {
+	case br.Status.BuildSpec != nil && br.Status.BuildSpec.Strategy.Name != "":
+		return br.Status.BuildSpec.Strategy.Name
+	case br.Spec.BuildSpec != nil && br.Spec.BuildSpec.Strategy.Name != "":
+		return br.Spec.BuildSpec.Strategy.Name
+	default:
+		return unknownStrategy
+	}
+}
+
+// statsOf computes the statistics of the samples.
+func statsOf(scope string, samples []sample) Stats {
+	s := Stats{Scope: scope, Runs: len(samples)}
+	durations := make([]time.Duration, 0, len(samples))
+	for _, sample := range samples {
+		if sample.succeeded {
+			s.Succeeded++
+		} else {
+			s.Failed++
+		}
+		durations = append(durations, sample.duration)
+	}
+	if s.Runs == 0 {
+		return s
+	}
+	s.SuccessRate = float64(s.Succeeded) / float64(s.Runs)
+
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	s.P50Seconds = percentile(durations, 50).Seconds()
+	s.P90Seconds = percentile(durations, 90).Seconds()
+	s.P99Seconds = percentile(durations, 99).Seconds()
+	return s
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// histogramOf distributes the samples on the duration buckets.
+func histogramOf(samples []sample) []Bucket {
+	buckets := make([]Bucket, len(bucketBounds)+1)
+	lower := "0s"
+	for i, bound := range bucketBounds {
+		buckets[i].Range = fmt.Sprintf("%s-%s", lower, shortDuration(bound))
+		lower = shortDuration(bound)
+	}
+	buckets[len(bucketBounds)].Range = ">=" + lower
+
+	for _, s := range samples {
+		i := sort.Search(len(bucketBounds), func(i int) bool {
+			return s.duration < bucketBounds[i]
+		})
+		buckets[i].Runs++
+	}
+	return buckets
+}
+
+// shortDuration formats the duration without the zero units, i.e. "5m" instead of "5m0s".
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// seconds formats the amount of seconds as a duration rounded to the second.
+func seconds(s float64) string {
+	return (time.Duration(s * float64(time.Second))).Round(time.Second).String()
+}
+
+// render prints the statistics tables and the histogram.
+func (c *StatsCommand) render(out io.Writer, r *report) error {
+	scope := fmt.Sprintf("namespace %q", r.Namespace)
+	if c.allNamespaces {
+		scope = "all namespaces"
+	}
+	if r.Build != "" {
+		scope = fmt.Sprintf("Build %q on %s", r.Build, scope)
+	}
+	window := "all the time"
+	if c.since > 0 {
+		window = "the last " + duration.HumanDuration(c.since)
+	}
+	fmt.Fprintf(out, "BuildRun statistics of %s over %s\n\n", scope, window)
+
+	if r.Total.Runs == 0 {
+		fmt.Fprintln(out, "No completed BuildRuns found.")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(writer, "SCOPE\tRUNS\tSUCCEEDED\tFAILED\tSUCCESS RATE\tP50\tP90\tP99")
+	for _, s := range append([]Stats{r.Total}, r.Strategies...) {
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%.1f%%\t%s\t%s\t%s\n",
+			styles.Bold(s.Scope),
+			s.Runs,
+			styles.Success(strconv.Itoa(s.Succeeded)),
+			styles.Failure(strconv.Itoa(s.Failed)),
+			s.SuccessRate*100,
+			seconds(s.P50Seconds),
+			seconds(s.P90Seconds),
+			seconds(s.P99Seconds),
+		)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	most := 0
+	for _, b := range r.Histogram {
+		if b.Runs > most {
+			most = b.Runs
+		}
+	}
+	fmt.Fprintln(out, "\nDurations:")
+	writer = tabwriter.NewWriter(out, 0, 8, 2, '\t', 0)
+	for _, b := range r.Histogram {
+		bar := strings.Repeat("█", int(math.Ceil(float64(b.Runs)/float64(most)*histogramWidth)))
+		fmt.Fprintf(writer, "%s\t%d\t%s\n", b.Range, b.Runs, styles.Faint(bar))
+	}
+	return writer.Flush()
+}
+
+// renderCSV prints a header, the statistics of all runs, and a row per strategy.
+func renderCSV(out io.Writer, r *report) error {
+	w := csv.NewWriter(out)
+	records := [][]string{{"scope", "runs", "succeeded", "failed", "successRate", "p50Seconds", "p90Seconds", "p99Seconds"}}
+	for _, s := range append([]Stats{r.Total}, r.Strategies...) {
+		records = append(records, []string{
+			s.Scope,
+			strconv.Itoa(s.Runs),
+			strconv.Itoa(s.Succeeded),
+			strconv.Itoa(s.Failed),
+			strconv.FormatFloat(s.SuccessRate, 'f', 4, 64),
+			strconv.FormatFloat(s.P50Seconds, 'f', 0, 64),
+			strconv.FormatFloat(s.P90Seconds, 'f', 0, 64),
+			strconv.FormatFloat(s.P99Seconds, 'f', 0, 64),
+		})
+	}
+	return w.WriteAll(records)
+}
@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func newBuildRun(name, build, strategy string, completed time.Time, took time.Duration, status corev1.ConditionStatus) *buildv1alpha1.BuildRun {
+	started := metav1.NewTime(completed.Add(-took))
+	br := &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, CreationTimestamp: started},
+		Spec:       buildv1alpha1.BuildRunSpec{BuildRef: &buildv1alpha1.BuildRef{Name: build}},
+		Status: buildv1alpha1.BuildRunStatus{
+			Conditions: buildv1alpha1.Conditions{{Type: buildv1alpha1.Succeeded, Status: status}},
+			StartTime:  &started,
+		},
+	}
+	if status != corev1.ConditionUnknown {
+		br.Status.CompletionTime = &metav1.Time{Time: completed}
+	}
+	if strategy != "" {
+		br.Status.BuildSpec = &buildv1alpha1.BuildSpec{Strategy: buildv1alpha1.Strategy{Name: strategy}}
+	}
+	return br
+}
+
+func TestPercentile(t *testing.T) {
+	g := o.NewWithT(t)
+
+	durations := []time.Duration{}
+	for i := 1; i <= 10; i++ {
+		durations = append(durations, time.Duration(i)*time.Second)
+	}
+	g.Expect(percentile(durations, 50)).To(o.Equal(5 * time.Second))
+	g.Expect(percentile(durations, 90)).To(o.Equal(9 * time.Second))
+	g.Expect(percentile(durations, 99)).To(o.Equal(10 * time.Second))
+	g.Expect(percentile(durations[:1], 50)).To(o.Equal(time.Second))
+}
+
+func TestStats(t *testing.T) {
+	g := o.NewWithT(t)
+
+	now := time.Now()
+	shpclientset := shpfake.NewSimpleClientset(
+		newBuildRun("a-1", "app", "buildah", now.Add(-time.Hour), 30*time.Second, corev1.ConditionTrue),
+		newBuildRun("a-2", "app", "buildah", now.Add(-time.Hour), 90*time.Second, corev1.ConditionTrue),
+		newBuildRun("a-3", "app", "kaniko", now.Add(-time.Hour), 4*time.Minute, corev1.ConditionFalse),
+		newBuildRun("b-1", "other", "", now.Add(-time.Hour), 45*time.Minute, corev1.ConditionTrue),
+		newBuildRun("a-old", "app", "buildah", now.Add(-48*time.Hour), time.Minute, corev1.ConditionTrue),
+		newBuildRun("a-running", "app", "buildah", now, time.Minute, corev1.ConditionUnknown),
+	)
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, "ns", nil, nil)
+
+	run := func(args ...string) (string, error) {
+		cmd := statsCmd().(*StatsCommand)
+		cmd.now = func() time.Time { return now }
+		cmd.Cmd().SetContext(context.TODO())
+		g.Expect(cmd.Cmd().ParseFlags(args)).To(o.Succeed())
+		if err := cmd.Validate(); err != nil {
+			return "", err
+		}
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		err := cmd.Run(p, &ioStreams)
+		return out.String(), err
+	}
+
+	t.Run("json report of the namespace", func(_ *testing.T) {
+		out, err := run("--since=24h", "-o", "json")
+		g.Expect(err).To(o.BeNil())
+
+		r := report{}
+		g.Expect(json.Unmarshal([]byte(out), &r)).To(o.Succeed())
+		g.Expect(r.Total).To(o.Equal(Stats{
+			Scope:       "all",
+			Runs:        4,
+			Succeeded:   3,
+			Failed:      1,
+			SuccessRate: 0.75,
+			P50Seconds:  90,
+			P90Seconds:  2700,
+			P99Seconds:  2700,
+		}))
+		g.Expect(r.Strategies).To(o.HaveLen(3))
+		g.Expect(r.Strategies[0].Scope).To(o.Equal(unknownStrategy))
+		g.Expect(r.Strategies[1].Scope).To(o.Equal("buildah"))
+		g.Expect(r.Strategies[1].Runs).To(o.Equal(2))
+		g.Expect(r.Strategies[2].Scope).To(o.Equal("kaniko"))
+		g.Expect(r.Histogram).To(o.Equal([]Bucket{
+			{Range: "0s-1m", Runs: 1},
+			{Range: "1m-2m", Runs: 1},
+			{Range: "2m-5m", Runs: 1},
+			{Range: "5m-10m", Runs: 0},
+			{Range: "10m-30m", Runs: 0},
+			{Range: ">=30m", Runs: 1},
+		}))
+	})
+
+	t.Run("csv report of a build over all the time", func(_ *testing.T) {
+		out, err := run("--build=app", "-o", "csv")
+		g.Expect(err).To(o.BeNil())
+
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		g.Expect(lines).To(o.Equal([]string{
+			"scope,runs,succeeded,failed,successRate,p50Seconds,p90Seconds,p99Seconds",
+			"all,4,3,1,0.7500,60,240,240",
+			"buildah,3,3,0,1.0000,60,90,90",
+			"kaniko,1,0,1,0.0000,240,240,240",
+		}))
+	})
+
+	t.Run("table report", func(_ *testing.T) {
+		out, err := run("--build=app", "--since=24h")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).To(o.ContainSubstring(`BuildRun statistics of Build "app" on namespace "ns" over the last 24h`))
+		g.Expect(out).To(o.ContainSubstring("Durations:"))
+
+		var rows [][]string
+		for _, line := range strings.Split(out, "\n") {
+			if fields := strings.Fields(line); len(fields) > 0 && (fields[0] == "all" || fields[0] == "buildah") {
+				rows = append(rows, fields)
+			}
+		}
+		g.Expect(rows).To(o.Equal([][]string{
+			{"all", "3", "2", "1", "66.7%", "1m30s", "4m0s", "4m0s"},
+			{"buildah", "2", "2", "0", "100.0%", "30s", "1m30s", "1m30s"},
+		}))
+	})
+
+	t.Run("no completed runs", func(_ *testing.T) {
+		out, err := run("--build=missing")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).To(o.ContainSubstring("No completed BuildRuns found."))
+	})
+
+	t.Run("invalid output", func(_ *testing.T) {
+		_, err := run("-o", "yaml")
+		g.Expect(err).To(o.MatchError(o.ContainSubstring(`unsupported --output "yaml"`)))
+	})
+}
@@ -0,0 +1,3 @@
+// Package status contains types and functions for the status cobra command, an overview of the
+// build health per namespace.
+package status
@@ -0,0 +1,292 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+	"github.com/shipwright-io/cli/pkg/shp/util"
+)
+
+// StatusCommand contains data input from user for the status command
+type StatusCommand struct {
+	cmd *cobra.Command
+
+	allNamespaces bool          // aggregate every namespace
+	since         time.Duration // window of completed BuildRuns accounted
+	failures      int           // amount of recent failures to show
+	watch         bool          // keep refreshing the dashboard
+	interval      time.Duration // refresh interval
+	now           func() time.Time
+}
+
+const statusLongDesc = `
+Shows a dashboard of the build health per namespace: the amount of Builds, how many are invalid,
+the BuildRuns currently pending or running, the BuildRuns succeeded and failed within the --since
+window, and the most recent failures with their reasons. For example:
+
+	$ shp status --since=6h
+	$ shp status --all-namespaces --watch
+`
+
+// clearScreen ANSI sequence to move the cursor home and clear the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// maxMessageLength maximum length of the failure messages shown.
+const maxMessageLength = 60
+
+// Command returns the "status" command of Shipwright CLI.
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	cmd := runner.NewRunner(p, ioStreams, statusCmd()).Cmd()
+	cmd.Annotations = map[string]string{
+		"commandType": "main",
+	}
+	return cmd
+}
+
+func statusCmd() runner.SubCommand {
+	c := &StatusCommand{
+		cmd: &cobra.Command{
+			Use:   "status [flags]",
+			Short: "Show a dashboard of the build health",
+			Long:  statusLongDesc,
+			Args:  cobra.NoArgs,
+		},
+		now: time.Now,
+	}
+
+	f := c.cmd.Flags()
+	f.BoolVarP(&c.allNamespaces, "all-namespaces", "A", false, "Show the build health of every namespace")
+	f.DurationVar(&c.since, "since", 24*time.Hour, "Account the BuildRuns completed within this window")
+	f.IntVar(&c.failures, "failures", 5, "Amount of recent failures to show")
+	f.BoolVarP(&c.watch, "watch", "w", false, "Keep refreshing the dashboard")
+	f.DurationVar(&c.interval, "interval", 5*time.Second, "Refresh interval when watching")
+
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *StatusCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *StatusCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	return nil
+}
+
+// Validate validates data input by user
+func (c *StatusCommand) Validate() error {
+	if c.since <= 0 {
+		return fmt.Errorf("--since must be positive")
+	}
+	if c.failures < 0 {
+		return fmt.Errorf("--failures must not be negative")
+	}
+	if c.interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	return nil
+}
+
+// Run renders the dashboard, refreshing it periodically when watching
+func (c *StatusCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	if !c.watch {
+		return c.refresh(p, ioStreams.Out)
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		fmt.Fprint(ioStreams.Out, clearScreen)
+		if err := c.refresh(p, ioStreams.Out); err != nil {
+			return err
+		}
+		select {
+		case <-c.cmd.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh lists the Builds and BuildRuns and renders the dashboard.
+func (c *StatusCommand) refresh(p *params.Params, out io.Writer) error {
+	clientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+
+	ns := p.Namespace()
+	if c.allNamespaces {
+		ns = metav1.NamespaceAll
+	}
+	ctx := c.cmd.Context()
+	buildList, err := clientset.ShipwrightV1alpha1().Builds(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	brList, err := clientset.ShipwrightV1alpha1().BuildRuns(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	namespaces := summarize(buildList.Items, brList.Items, c.now().Add(-c.since))
+	if !c.allNamespaces && len(namespaces) == 0 {
+		namespaces = []*namespaceSummary{{name: ns}}
+	}
+	return c.render(out, namespaces)
+}
+
+// namespaceSummary aggregated build health of a namespace.
+type namespaceSummary struct {
+	name string
+
+	builds        int
+	invalidBuilds int
+
+	pending   int
+	running   int
+	succeeded int
+	failed    int
+
+	failures []buildv1alpha1.BuildRun // failed BuildRuns within the window, most recent first
+}
+
+// summarize aggregates the Builds and BuildRuns per namespace, sorted by name. Pending and running
+// BuildRuns are always accounted, completed BuildRuns only when completed after the informed moment.
+func summarize(builds []buildv1alpha1.Build, buildRuns []buildv1alpha1.BuildRun, after time.Time) []*namespaceSummary {
+	byName := map[string]*namespaceSummary{}
+	get := func(ns string) *namespaceSummary {
+		s, ok := byName[ns]
+		if !ok {
+			s = &namespaceSummary{name: ns}
+			byName[ns] = s
+		}
+		return s
+	}
+
+	for i := range builds {
+		s := get(builds[i].Namespace)
+		s.builds++
+		if builds[i].Status.Registered != nil && *builds[i].Status.Registered == corev1.ConditionFalse {
+			s.invalidBuilds++
+		}
+	}
+
+	for i := range buildRuns {
+		br := &buildRuns[i]
+		s := get(br.Namespace)
+		switch util.PhaseOf(br) {
+		case util.PhasePending:
+			s.pending++
+		case util.PhaseRunning:
+			s.running++
+		case util.PhaseSucceeded:
+			if util.CompletionTimeOf(br).After(after) {
+				s.succeeded++
+			}
+		case util.PhaseFailed:
+			if util.CompletionTimeOf(br).After(after) {
+				s.failed++
+				s.failures = append(s.failures, *br)
+			}
+		}
+	}
+
+	summaries := make([]*namespaceSummary, 0, len(byName))
+	for _, s := range byName {
+		sort.Slice(s.failures, func(i, j int) bool {
+			return util.CompletionTimeOf(&s.failures[i]).After(util.CompletionTimeOf(&s.failures[j]))
+		})
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].name < summaries[j].name
+	})
+	return summaries
+}
+
+// render prints the dashboard for the informed namespaces.
+func (c *StatusCommand) render(out io.Writer, namespaces []*namespaceSummary) error {
+	fmt.Fprintf(out, "Build health over the last %s (updated %s)\n\n",
+		duration.HumanDuration(c.since), c.now().Format(time.TimeOnly))
+
+	if len(namespaces) == 0 {
+		fmt.Fprintln(out, "No Builds or BuildRuns found.")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(writer, "NAMESPACE\tBUILDS\tINVALID\tPENDING\tRUNNING\tSUCCEEDED\tFAILED")
+	failures := []buildv1alpha1.BuildRun{}
+	for _, s := range namespaces {
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			styles.Bold(s.name),
+			s.builds,
+			styles.Failure(strconv.Itoa(s.invalidBuilds)),
+			styles.Faint(strconv.Itoa(s.pending)),
+			styles.Warning(strconv.Itoa(s.running)),
+			styles.Success(strconv.Itoa(s.succeeded)),
+			styles.Failure(strconv.Itoa(s.failed)),
+		)
+		failures = append(failures, s.failures...)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		return util.CompletionTimeOf(&failures[i]).After(util.CompletionTimeOf(&failures[j]))
+	})
+	if len(failures) > c.failures {
+		failures = failures[:c.failures]
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out, "\nRecent failures:")
+	writer = tabwriter.NewWriter(out, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(writer, "NAMESPACE\tNAME\tBUILD\tREASON\tMESSAGE\tAGE")
+	for i := range failures {
+		br := &failures[i]
+		reason, message := "", ""
+		if cond := br.Status.GetCondition(buildv1alpha1.Succeeded); cond != nil {
+			reason, message = cond.GetReason(), cond.GetMessage()
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			br.Namespace,
+			styles.Bold(br.Name),
+			br.Spec.BuildName(),
+			styles.Failure(reason),
+			truncate(message, maxMessageLength),
+			duration.ShortHumanDuration(c.now().Sub(util.CompletionTimeOf(br))),
+		)
+	}
+	return writer.Flush()
+}
+
+// truncate shortens the first line of the informed message to the maximum length.
+func truncate(message string, length int) string {
+	message, _, _ = strings.Cut(message, "\n")
+	if len(message) <= length {
+		return message
+	}
+	return message[:length-3] + "..."
+}
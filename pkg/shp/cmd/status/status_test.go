@@ -0,0 +1,130 @@
+package status
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func newBuildRun(ns, name string, completed time.Time, status corev1.ConditionStatus, reason string) *buildv1alpha1.BuildRun {
+	br := &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         ns,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(completed.Add(-time.Minute)),
+		},
+		Spec: buildv1alpha1.BuildRunSpec{BuildRef: &buildv1alpha1.BuildRef{Name: "my-app"}},
+	}
+	if status != "" {
+		br.Status.Conditions = buildv1alpha1.Conditions{{
+			Type:    buildv1alpha1.Succeeded,
+			Status:  status,
+			Reason:  reason,
+			Message: "message of " + name,
+		}}
+	}
+	if status != corev1.ConditionUnknown {
+		br.Status.CompletionTime = &metav1.Time{Time: completed}
+	} else {
+		br.Status.StartTime = &br.CreationTimestamp
+	}
+	return br
+}
+
+func TestStatus(t *testing.T) {
+	g := o.NewWithT(t)
+
+	now := time.Now()
+	registered := corev1.ConditionTrue
+	invalid := corev1.ConditionFalse
+	shpclientset := shpfake.NewSimpleClientset(
+		&buildv1alpha1.Build{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "my-app"},
+			Status:     buildv1alpha1.BuildStatus{Registered: &registered},
+		},
+		&buildv1alpha1.Build{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "broken"},
+			Status:     buildv1alpha1.BuildStatus{Registered: &invalid},
+		},
+		&buildv1alpha1.Build{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "other"}},
+		newBuildRun("team-a", "running", now, corev1.ConditionUnknown, "Running"),
+		newBuildRun("team-a", "pending", now, "", ""),
+		newBuildRun("team-a", "succeeded", now.Add(-time.Hour), corev1.ConditionTrue, "Succeeded"),
+		newBuildRun("team-a", "succeeded-old", now.Add(-48*time.Hour), corev1.ConditionTrue, "Succeeded"),
+		newBuildRun("team-a", "failed", now.Add(-2*time.Hour), corev1.ConditionFalse, "Failed"),
+		newBuildRun("team-b", "failed-newest", now.Add(-time.Minute), corev1.ConditionFalse, "BuildRunTimeout"),
+		newBuildRun("team-b", "failed-old", now.Add(-48*time.Hour), corev1.ConditionFalse, "Failed"),
+	)
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, nil, "team-a", nil, nil)
+
+	t.Run("summary per namespace", func(_ *testing.T) {
+		builds, err := shpclientset.ShipwrightV1alpha1().Builds("").List(context.TODO(), metav1.ListOptions{})
+		g.Expect(err).To(o.BeNil())
+		brs, err := shpclientset.ShipwrightV1alpha1().BuildRuns("").List(context.TODO(), metav1.ListOptions{})
+		g.Expect(err).To(o.BeNil())
+
+		summaries := summarize(builds.Items, brs.Items, now.Add(-24*time.Hour))
+		g.Expect(summaries).To(o.HaveLen(2))
+
+		a := summaries[0]
+		g.Expect(a.name).To(o.Equal("team-a"))
+		g.Expect(a.builds).To(o.Equal(2))
+		g.Expect(a.invalidBuilds).To(o.Equal(1))
+		g.Expect(a.pending).To(o.Equal(1))
+		g.Expect(a.running).To(o.Equal(1))
+		g.Expect(a.succeeded).To(o.Equal(1))
+		g.Expect(a.failed).To(o.Equal(1))
+
+		b := summaries[1]
+		g.Expect(b.name).To(o.Equal("team-b"))
+		g.Expect(b.failed).To(o.Equal(1))
+		g.Expect(b.failures[0].Name).To(o.Equal("failed-newest"))
+	})
+
+	t.Run("dashboard of every namespace", func(_ *testing.T) {
+		cmd := statusCmd().(*StatusCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags([]string{"--all-namespaces", "--failures=1"})).To(o.Succeed())
+		g.Expect(cmd.Validate()).To(o.Succeed())
+
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+
+		g.Expect(out.String()).To(o.ContainSubstring("team-a"))
+		g.Expect(out.String()).To(o.ContainSubstring("team-b"))
+		g.Expect(out.String()).To(o.ContainSubstring("message of failed-newest"))
+		// only the most recent failure is shown
+		g.Expect(strings.Contains(out.String(), "message of failed\t")).To(o.BeFalse())
+	})
+
+	t.Run("dashboard of the current namespace", func(_ *testing.T) {
+		cmd := statusCmd().(*StatusCommand)
+		cmd.cmd.SetContext(context.TODO())
+
+		ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+		g.Expect(cmd.Run(p, &ioStreams)).To(o.Succeed())
+
+		g.Expect(out.String()).To(o.ContainSubstring("team-a"))
+		g.Expect(out.String()).NotTo(o.ContainSubstring("team-b"))
+		g.Expect(out.String()).To(o.ContainSubstring("message of failed"))
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	g := o.NewWithT(t)
+	g.Expect(truncate("short", 10)).To(o.Equal("short"))
+	g.Expect(truncate("first line\nsecond line", 20)).To(o.Equal("first line"))
+	g.Expect(truncate("a very long message", 10)).To(o.Equal("a very ..."))
+}
@@ -1,30 +1,325 @@
 package version
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/spf13/cobra"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// version information set at build time via ldflags.
+var (
+	version   string
+	commit    string
+	buildDate string
+)
+
+const (
+	// controllerDeployment name of the Shipwright Build controller deployment.
+	controllerDeployment = "shipwright-build-controller"
+	// operatorDeployment name of the Shipwright operator deployment.
+	operatorDeployment = "shipwright-operator"
+	// versionLabel recommended label carrying the version of the deployed component.
+	versionLabel = "app.kubernetes.io/version"
 )
 
-var version string
+// testedControllerVersions the Shipwright Build minor versions the CLI is tested against.
+var testedControllerVersions = []string{"v0.12", "v0.13"}
+
+// minorVersionRegexp extracts the major and minor parts of a semantic version.
+var minorVersionRegexp = regexp.MustCompile(`^v?(\d+)\.(\d+)`)
+
+// VersionCommand contains data input from user for the version command
+type VersionCommand struct {
+	cmd *cobra.Command
+
+	clientOnly bool   // skip querying the cluster
+	output     string // output format, either empty or "json"
+}
+
+// ClientVersion describes the CLI build.
+type ClientVersion struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// ComponentVersion describes a Shipwright component found on the cluster.
+type ComponentVersion struct {
+	Version   string `json:"version"`
+	Namespace string `json:"namespace"`
+}
+
+// ServerVersion describes the Shipwright installation on the cluster.
+type ServerVersion struct {
+	Controller  *ComponentVersion `json:"controller,omitempty"`
+	Operator    *ComponentVersion `json:"operator,omitempty"`
+	APIVersions []string          `json:"apiVersions"`
+}
+
+// versionInfo the output of the command.
+type versionInfo struct {
+	Client   ClientVersion  `json:"client"`
+	Server   *ServerVersion `json:"server,omitempty"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+const versionLongDesc = `
+Prints the CLI version, git commit and build date, and queries the cluster for the Shipwright
+Build controller and operator versions, along with the Shipwright API versions served. A warning
+is shown when the CLI has not been tested against the combination found on the cluster.
+
+The controller and operator are found by their deployment names, their version is taken from the
+"app.kubernetes.io/version" label, or the image tag. For example:
+
+	$ shp version
+	$ shp version --client
+	$ shp version -o json
+`
 
 // Command returns Version subcommand of Shipwright CLI
 // for retrieving the shp version
-func Command() *cobra.Command {
-	command := &cobra.Command{
-		Use:     "version",
-		Aliases: []string{"v"},
-		Short:   "version",
-		Annotations: map[string]string{
-			"commandType": "main",
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	cmd := runner.NewRunner(p, ioStreams, versionCmd()).Cmd()
+	cmd.Annotations = map[string]string{
+		"commandType": "main",
+	}
+	return cmd
+}
+
+func versionCmd() runner.SubCommand {
+	c := &VersionCommand{
+		cmd: &cobra.Command{
+			Use:     "version [flags]",
+			Aliases: []string{"v"},
+			Short:   "Print the client and server versions",
+			Long:    versionLongDesc,
+			Args:    cobra.NoArgs,
 		},
-		Run: func(_ *cobra.Command, _ []string) {
-			if version == "" {
-				version = "development"
+	}
+	c.cmd.Flags().BoolVar(&c.clientOnly, "client", false, "print the client version only, without querying the cluster")
+	c.cmd.Flags().StringVarP(&c.output, "output", "o", "", "output format, either empty or \"json\"")
+	return c
+}
+
+// Cmd returns cobra command object
+func (c *VersionCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *VersionCommand) Complete(_ *params.Params, _ *genericclioptions.IOStreams, _ []string) error {
+	return nil
+}
+
+// Validate validates data input by user
+func (c *VersionCommand) Validate() error {
+	if c.output != "" && c.output != "json" {
+		return fmt.Errorf("unsupported --output %q, only \"json\" is supported", c.output)
+	}
+	return nil
+}
+
+// Run prints the client version, and the server version unless --client is informed. The client
+// version is printed even when the cluster can't be reached.
+func (c *VersionCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	info := versionInfo{Client: clientVersion()}
+
+	var err error
+	if !c.clientOnly {
+		var clientset kubernetes.Interface
+		if clientset, err = p.ClientSet(); err == nil {
+			info.Server, err = serverVersion(c.cmd.Context(), clientset)
+		}
+		if info.Server != nil {
+			info.Warnings = compatibilityWarnings(info.Server)
+		}
+	}
+
+	if c.output == "json" {
+		data, jsonErr := json.MarshalIndent(info, "", "  ")
+		if jsonErr != nil {
+			return jsonErr
+		}
+		fmt.Fprintln(ioStreams.Out, string(data))
+	} else {
+		printVersion(ioStreams.Out, &info)
+		for _, warning := range info.Warnings {
+			fmt.Fprintf(ioStreams.ErrOut, "Warning: %s\n", warning)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("unable to obtain the server version: %w", err)
+	}
+	return nil
+}
+
+// clientVersion describes the CLI build, falling back to the information recorded by the Go
+// toolchain when not set at build time.
+func clientVersion() ClientVersion {
+	v := ClientVersion{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && v.Commit == "":
+				v.Commit = setting.Value
+			case setting.Key == "vcs.time" && v.BuildDate == "":
+				v.BuildDate = setting.Value
 			}
+		}
+	}
+	if v.Version == "" {
+		v.Version = "development"
+	}
+	return v
+}
 
-			fmt.Printf("version: %s\n", version)
-		},
+// Current returns the version of the CLI build.
+func Current() string {
+	return clientVersion().Version
+}
+
+// serverVersion finds the Shipwright components deployed, and the Shipwright API versions served.
+// The components are optional, i.e. the user may not be allowed to list deployments, while the
+// API versions must be served.
+func serverVersion(ctx context.Context, clientset kubernetes.Interface) (*ServerVersion, error) {
+	groups, err := clientset.Discovery().ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+	server := &ServerVersion{APIVersions: []string{}}
+	for _, group := range groups.Groups {
+		if group.Name != buildv1alpha1.SchemeGroupVersion.Group {
+			continue
+		}
+		for _, v := range group.Versions {
+			server.APIVersions = append(server.APIVersions, v.GroupVersion)
+		}
+	}
+	sort.Strings(server.APIVersions)
+
+	server.Controller = findComponent(ctx, clientset, controllerDeployment)
+	server.Operator = findComponent(ctx, clientset, operatorDeployment)
+	return server, nil
+}
+
+// findComponent looks for the named deployment in every namespace, nil when not found or not
+// allowed to list the deployments.
+func findComponent(ctx context.Context, clientset kubernetes.Interface, name string) *ComponentVersion {
+	deployments, err := clientset.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: "metadata.name=" + name,
+	})
+	if err != nil {
+		return nil
+	}
+	for i := range deployments.Items {
+		if d := &deployments.Items[i]; d.GetName() == name {
+			return &ComponentVersion{Version: deploymentVersion(d), Namespace: d.GetNamespace()}
+		}
+	}
+	return nil
+}
+
+// deploymentVersion returns the version label of the deployment, falling back to the image tag of
+// its first container.
+func deploymentVersion(d *appsv1.Deployment) string {
+	if v := d.GetLabels()[versionLabel]; v != "" {
+		return v
+	}
+	if containers := d.Spec.Template.Spec.Containers; len(containers) > 0 {
+		image, _, _ := strings.Cut(containers[0].Image, "@")
+		if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+			return image[i+1:]
+		}
+	}
+	return "unknown"
+}
+
+// compatibilityWarnings describes why the combination of CLI and cluster is untested.
+func compatibilityWarnings(server *ServerVersion) []string {
+	warnings := []string{}
+	served := false
+	for _, v := range server.APIVersions {
+		served = served || v == buildv1alpha1.SchemeGroupVersion.String()
+	}
+	if !served {
+		warnings = append(warnings, fmt.Sprintf("the cluster does not serve %s, employed by this CLI",
+			buildv1alpha1.SchemeGroupVersion.String()))
+	}
+	if server.Controller == nil {
+		return warnings
+	}
+	m := minorVersionRegexp.FindStringSubmatch(server.Controller.Version)
+	if m == nil {
+		return append(warnings, fmt.Sprintf("unable to tell whether the Shipwright Build version %q is supported",
+			server.Controller.Version))
+	}
+	minor := fmt.Sprintf("v%s.%s", m[1], m[2])
+	for _, tested := range testedControllerVersions {
+		if minor == tested {
+			return warnings
+		}
+	}
+	return append(warnings, fmt.Sprintf("this CLI has not been tested against Shipwright Build %s, tested versions are %s",
+		server.Controller.Version, strings.Join(testedControllerVersions, ", ")))
+}
+
+// printVersion renders the versions as a list of attributes.
+func printVersion(out io.Writer, info *versionInfo) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "Client:")
+	fmt.Fprintf(w, "  Version:\t%s\n", info.Client.Version)
+	if info.Client.Commit != "" {
+		fmt.Fprintf(w, "  Git commit:\t%s\n", info.Client.Commit)
+	}
+	if info.Client.BuildDate != "" {
+		fmt.Fprintf(w, "  Build date:\t%s\n", info.Client.BuildDate)
+	}
+	fmt.Fprintf(w, "  Go version:\t%s\n", info.Client.GoVersion)
+	fmt.Fprintf(w, "  Platform:\t%s\n", info.Client.Platform)
+	if info.Server == nil {
+		return
+	}
+
+	component := func(c *ComponentVersion) string {
+		if c == nil {
+			return "not found"
+		}
+		return fmt.Sprintf("%s (namespace %q)", c.Version, c.Namespace)
+	}
+	apiVersions := "none"
+	if len(info.Server.APIVersions) > 0 {
+		apiVersions = strings.Join(info.Server.APIVersions, ", ")
 	}
-	return command
+	fmt.Fprintln(w, "Server:")
+	fmt.Fprintf(w, "  Build controller:\t%s\n", component(info.Server.Controller))
+	fmt.Fprintf(w, "  Operator:\t%s\n", component(info.Server.Operator))
+	fmt.Fprintf(w, "  API versions:\t%s\n", apiVersions)
 }
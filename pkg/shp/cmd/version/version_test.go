@@ -0,0 +1,106 @@
+package version
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestVersionCommand(t *testing.T) {
+	deployment := func(ns, name, image string, labels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: labels},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "c", Image: image}}},
+				},
+			},
+		}
+	}
+	newClientset := func(controllerImage string, apiVersions ...string) *fake.Clientset {
+		clientset := fake.NewSimpleClientset(
+			deployment("shipwright-build", controllerDeployment, controllerImage, nil),
+			deployment("operators", operatorDeployment, "quay.io/shipwright/operator:latest",
+				map[string]string{versionLabel: "v0.13.1"}),
+			deployment("default", "other", "registry.local/other:v1", nil),
+		)
+		for _, v := range apiVersions {
+			clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = append(
+				clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources,
+				&metav1.APIResourceList{GroupVersion: v, APIResources: []metav1.APIResource{{Name: "builds"}}},
+			)
+		}
+		return clientset
+	}
+
+	run := func(t *testing.T, clientset *fake.Clientset, args ...string) (string, string, error) {
+		g := o.NewWithT(t)
+		cmd := versionCmd().(*VersionCommand)
+		cmd.cmd.SetContext(context.TODO())
+		g.Expect(cmd.cmd.ParseFlags(args)).To(o.Succeed())
+		g.Expect(cmd.Validate()).To(o.Succeed())
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		p := params.NewParamsForTest(clientset, nil, nil, metav1.NamespaceDefault, nil, nil)
+		err := cmd.Run(p, &genericclioptions.IOStreams{Out: out, ErrOut: errOut})
+		return out.String(), errOut.String(), err
+	}
+
+	t.Run("tested combination", func(t *testing.T) {
+		g := o.NewWithT(t)
+		clientset := newClientset("ghcr.io/shipwright-io/build/controller:v0.13.0@sha256:abc", "shipwright.io/v1alpha1", "shipwright.io/v1beta1")
+		out, errOut, err := run(t, clientset)
+		g.Expect(err).To(o.BeNil())
+		g.Expect(errOut).To(o.BeEmpty())
+		g.Expect(out).To(o.ContainSubstring("Client:\n  Version:     development\n"))
+		g.Expect(out).To(o.HaveSuffix("Server:\n" +
+			"  Build controller:  v0.13.0 (namespace \"shipwright-build\")\n" +
+			"  Operator:          v0.13.1 (namespace \"operators\")\n" +
+			"  API versions:      shipwright.io/v1alpha1, shipwright.io/v1beta1\n"))
+	})
+
+	t.Run("untested combination", func(t *testing.T) {
+		g := o.NewWithT(t)
+		clientset := newClientset("registry.local:5000/controller:v0.15.2", "shipwright.io/v1beta1")
+		out, errOut, err := run(t, clientset, "-o", "json")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(errOut).To(o.BeEmpty())
+
+		info := versionInfo{}
+		g.Expect(json.Unmarshal([]byte(out), &info)).To(o.Succeed())
+		g.Expect(info.Server.Controller).To(o.Equal(&ComponentVersion{Version: "v0.15.2", Namespace: "shipwright-build"}))
+		g.Expect(info.Server.APIVersions).To(o.Equal([]string{"shipwright.io/v1beta1"}))
+		g.Expect(info.Warnings).To(o.Equal([]string{
+			"the cluster does not serve shipwright.io/v1alpha1, employed by this CLI",
+			"this CLI has not been tested against Shipwright Build v0.15.2, tested versions are v0.12, v0.13",
+		}))
+	})
+
+	t.Run("client only", func(t *testing.T) {
+		g := o.NewWithT(t)
+		clientset := newClientset("controller:v0.15.0")
+		out, _, err := run(t, clientset, "--client")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).NotTo(o.ContainSubstring("Server:"))
+		g.Expect(clientset.Actions()).To(o.BeEmpty())
+	})
+
+	t.Run("image without tag", func(t *testing.T) {
+		g := o.NewWithT(t)
+		out, errOut, err := run(t, newClientset("registry.local:5000/controller", "shipwright.io/v1alpha1"))
+		g.Expect(err).To(o.BeNil())
+		g.Expect(out).To(o.ContainSubstring("Build controller:  unknown"))
+		g.Expect(errOut).To(o.Equal("Warning: unable to tell whether the Shipwright Build version \"unknown\" is supported\n"))
+	})
+}
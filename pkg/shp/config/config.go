@@ -0,0 +1,226 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/shipwright-io/cli/pkg/shp/printer"
+)
+
+// EnvVar environment variable overriding the configuration file location.
+const EnvVar = "SHP_CONFIG"
+
+// Config persistent defaults for shp commands, explicit command-line flags take precedence.
+type Config struct {
+	Namespace      string `json:"namespace,omitempty"`
+	Output         string `json:"output,omitempty"`
+	Strategy       string `json:"strategy,omitempty"`
+	StrategyKind   string `json:"strategyKind,omitempty"`
+	RegistryPrefix string `json:"registryPrefix,omitempty"`
+	Follow         *bool  `json:"follow,omitempty"`
+	LogBackend     string `json:"logBackend,omitempty"`
+	Audit          *bool  `json:"audit,omitempty"`
+}
+
+// Key describes a configuration key, how it's stored and the command-line flag it provides the
+// default value for.
+type Key struct {
+	Name        string   // key name, as used by "shp config" subcommands
+	Flag        string   // command-line flag defaulted by the key
+	Description string   // short description of the key
+	Conflicts   []string // flags rejected along with the key flag, the default is not applied then
+	Annotation  string   // when set, the default only applies to flags carrying the annotation
+
+	get func(c *Config) string
+	set func(c *Config, value string) error
+}
+
+// Keys the configuration keys supported.
+var Keys = []Key{{
+	Name:        "namespace",
+	Flag:        "namespace",
+	Description: "namespace used when --namespace is not informed",
+	get:         func(c *Config) string { return c.Namespace },
+	set: func(c *Config, value string) error {
+		c.Namespace = value
+		return nil
+	},
+}, {
+	Name:        "output",
+	Flag:        "output",
+	Description: "output format of the list and describe commands used when --output is not informed",
+	Annotation:  printer.OutputFlagAnnotation,
+	get:         func(c *Config) string { return c.Output },
+	set: func(c *Config, value string) error {
+		if value != "" {
+			if err := (&printer.Flags{Output: value}).Validate(); err != nil {
+				return err
+			}
+		}
+		c.Output = value
+		return nil
+	},
+}, {
+	Name:        "strategy",
+	Flag:        "strategy-name",
+	Description: "build strategy name used when --strategy-name is not informed",
+	get:         func(c *Config) string { return c.Strategy },
+	set: func(c *Config, value string) error {
+		c.Strategy = value
+		return nil
+	},
+}, {
+	Name:        "strategy-kind",
+	Flag:        "strategy-kind",
+	Description: "build strategy kind used when --strategy-kind is not informed",
+	get:         func(c *Config) string { return c.StrategyKind },
+	set: func(c *Config, value string) error {
+		switch buildv1alpha1.BuildStrategyKind(value) {
+		case "", buildv1alpha1.NamespacedBuildStrategyKind, buildv1alpha1.ClusterBuildStrategyKind:
+			c.StrategyKind = value
+			return nil
+		}
+		return fmt.Errorf("invalid strategy kind %q, expected either %s or %s", value,
+			buildv1alpha1.NamespacedBuildStrategyKind, buildv1alpha1.ClusterBuildStrategyKind)
+	},
+}, {
+	Name:        "registry-prefix",
+	Flag:        "output-image",
+	Description: "registry prefix composing the output image as \"<prefix>/<build>\" when required and not informed",
+	get:         func(c *Config) string { return c.RegistryPrefix },
+	set: func(c *Config, value string) error {
+		c.RegistryPrefix = value
+		return nil
+	},
+}, {
+	Name:        "follow",
+	Flag:        "follow",
+	Description: "follow the BuildRun logs when --follow is not informed",
+	Conflicts:   []string{"wait", "quiet", "filename", "selector", "from-strategy"},
+	get:         func(c *Config) string { return formatBool(c.Follow) },
+	set: func(c *Config, value string) (err error) {
+		c.Follow, err = parseBool("follow", value)
+		return err
+	},
+}, {
+	Name:        "log-backend",
+	Flag:        "log-backend",
+	Description: "log backend URL serving the logs of BuildRuns whose pod is gone, see \"shp buildrun logs\"",
+	get:         func(c *Config) string { return c.LogBackend },
+	set: func(c *Config, value string) error {
+		c.LogBackend = value
+		return nil
+	},
+}, {
+	Name:        "audit",
+	Flag:        "audit",
+	Description: "record who triggered the BuildRuns, from which host and CLI version, when --audit is not informed",
+	get:         func(c *Config) string { return formatBool(c.Audit) },
+	set: func(c *Config, value string) (err error) {
+		c.Audit, err = parseBool("audit", value)
+		return err
+	},
+}}
+
+// formatBool renders the optional boolean, empty when not set.
+func formatBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+// parseBool parses the optional boolean value of the key, an empty value unsets it.
+func parseBool(name, value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q, expected a boolean", name, value)
+	}
+	return &b, nil
+}
+
+// LookupKey finds the configuration key by name.
+func LookupKey(name string) (*Key, error) {
+	for i := range Keys {
+		if Keys[i].Name == name {
+			return &Keys[i], nil
+		}
+	}
+	names := make([]string, 0, len(Keys))
+	for _, k := range Keys {
+		names = append(names, k.Name)
+	}
+	return nil, fmt.Errorf("unknown configuration key %q, expected one of %v", name, names)
+}
+
+// Get returns the value of the informed key, empty when not set.
+func (c *Config) Get(name string) (string, error) {
+	k, err := LookupKey(name)
+	if err != nil {
+		return "", err
+	}
+	return k.get(c), nil
+}
+
+// Set validates and stores the value of the informed key, an empty value unsets the key.
+func (c *Config) Set(name, value string) error {
+	k, err := LookupKey(name)
+	if err != nil {
+		return err
+	}
+	return k.set(c, value)
+}
+
+// Path returns the configuration file location, either informed by the SHP_CONFIG environment
+// variable or "shp/config.yaml" under the XDG configuration directory, "~/.config" by default.
+func Path() (string, error) {
+	if path := os.Getenv(EnvVar); path != "" {
+		return path, nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "shp", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "shp", "config.yaml"), nil
+}
+
+// Load reads the configuration file, a missing file results in an empty configuration.
+func Load(path string) (*Config, error) {
+	c := &Config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %q: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the configuration file, creating its directory when needed.
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
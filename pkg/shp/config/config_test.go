@@ -0,0 +1,158 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/shipwright-io/cli/pkg/shp/printer"
+)
+
+func TestConfig_LoadAndSave(t *testing.T) {
+	g := o.NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "shp", "config.yaml")
+
+	cfg, err := Load(path)
+	g.Expect(err).ToNot(o.HaveOccurred())
+	g.Expect(*cfg).To(o.Equal(Config{}))
+
+	g.Expect(cfg.Set("namespace", "builds")).To(o.Succeed())
+	g.Expect(cfg.Set("strategy-kind", "ClusterBuildStrategy")).To(o.Succeed())
+	g.Expect(cfg.Set("follow", "true")).To(o.Succeed())
+	g.Expect(cfg.Save(path)).To(o.Succeed())
+
+	cfg, err = Load(path)
+	g.Expect(err).ToNot(o.HaveOccurred())
+	g.Expect(cfg.Get("namespace")).To(o.Equal("builds"))
+	g.Expect(cfg.Get("strategy-kind")).To(o.Equal("ClusterBuildStrategy"))
+	g.Expect(cfg.Get("follow")).To(o.Equal("true"))
+	g.Expect(cfg.Get("output")).To(o.BeEmpty())
+
+	g.Expect(cfg.Set("follow", "")).To(o.Succeed())
+	g.Expect(cfg.Follow).To(o.BeNil())
+
+	g.Expect(cfg.Set("follow", "maybe")).ToNot(o.Succeed())
+	g.Expect(cfg.Set("strategy-kind", "Strategy")).ToNot(o.Succeed())
+	g.Expect(cfg.Set("output", "yaml")).To(o.Succeed())
+	g.Expect(cfg.Set("output", "xml")).To(o.MatchError(o.ContainSubstring(`unsupported --output "xml"`)))
+	g.Expect(cfg.Output).To(o.Equal("yaml"))
+	g.Expect(cfg.Set("unknown", "value")).ToNot(o.Succeed())
+	_, err = cfg.Get("unknown")
+	g.Expect(err).To(o.HaveOccurred())
+
+	g.Expect(os.WriteFile(path, []byte("colour: blue\n"), 0o600)).To(o.Succeed())
+	_, err = Load(path)
+	g.Expect(err).To(o.HaveOccurred())
+}
+
+func TestConfig_Path(t *testing.T) {
+	g := o.NewWithT(t)
+
+	t.Setenv(EnvVar, "/tmp/shp.yaml")
+	g.Expect(Path()).To(o.Equal("/tmp/shp.yaml"))
+
+	t.Setenv(EnvVar, "")
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	g.Expect(Path()).To(o.Equal(filepath.Join("/tmp/xdg", "shp", "config.yaml")))
+}
+
+func TestConfig_ApplyDefaults(t *testing.T) {
+	follow := true
+	cfg := &Config{
+		Namespace:      "builds",
+		Strategy:       "buildpacks-v3",
+		RegistryPrefix: "ghcr.io/org/",
+		Follow:         &follow,
+	}
+
+	newCmd := func() (*cobra.Command, *string, *string, *string, *bool) {
+		var namespace, strategy, image string
+		var followFlag bool
+		cmd := &cobra.Command{Use: "create"}
+		cmd.Flags().StringVar(&namespace, "namespace", "", "")
+		cmd.Flags().StringVar(&strategy, "strategy-name", "buildah", "")
+		cmd.Flags().StringVar(&image, "output-image", "", "")
+		cmd.Flags().BoolVar(&followFlag, "follow", false, "")
+		return cmd, &namespace, &strategy, &image, &followFlag
+	}
+
+	t.Run("defaults are applied to the flags not informed", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		cmd, namespace, strategy, image, followFlag := newCmd()
+		g.Expect(cmd.MarkFlagRequired("output-image")).To(o.Succeed())
+		g.Expect(cmd.Flags().Parse([]string{"--strategy-name=kaniko"})).To(o.Succeed())
+
+		g.Expect(cfg.ApplyDefaults(cmd, []string{"my-app"})).To(o.Succeed())
+		g.Expect(*namespace).To(o.Equal("builds"))
+		g.Expect(*strategy).To(o.Equal("kaniko"))
+		g.Expect(*image).To(o.Equal("ghcr.io/org/my-app"))
+		g.Expect(*followFlag).To(o.BeTrue())
+	})
+
+	t.Run("registry prefix only applies to required output image", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		cmd, _, _, image, _ := newCmd()
+		g.Expect(cfg.ApplyDefaults(cmd, []string{"my-app"})).To(o.Succeed())
+		g.Expect(*image).To(o.BeEmpty())
+	})
+
+	t.Run("commands can opt-out", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		cmd, namespace, _, _, _ := newCmd()
+		cmd.Annotations = map[string]string{SkipDefaultsAnnotation: ""}
+		g.Expect(cfg.ApplyDefaults(cmd, nil)).To(o.Succeed())
+		g.Expect(*namespace).To(o.BeEmpty())
+	})
+
+	t.Run("conflicting flags informed", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		cmd, _, _, _, followFlag := newCmd()
+		var wait bool
+		cmd.Flags().BoolVar(&wait, "wait", false, "")
+		g.Expect(cmd.Flags().Parse([]string{"--wait"})).To(o.Succeed())
+		g.Expect(cfg.ApplyDefaults(cmd, nil)).To(o.Succeed())
+		g.Expect(*followFlag).To(o.BeFalse())
+	})
+
+	t.Run("flags can opt-out", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		cmd, _, strategy, _, _ := newCmd()
+		g.Expect(cmd.Flags().SetAnnotation("strategy-name", SkipFlagDefaultAnnotation, []string{"true"})).To(o.Succeed())
+		g.Expect(cfg.ApplyDefaults(cmd, nil)).To(o.Succeed())
+		g.Expect(*strategy).To(o.Equal("buildah"))
+	})
+
+	t.Run("output only applies to the printer flags", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		cmd, _, _, _, _ := newCmd()
+		var output string
+		cmd.Flags().StringVar(&output, "output", "", "")
+		g.Expect((&Config{Output: "yaml"}).ApplyDefaults(cmd, nil)).To(o.Succeed())
+		g.Expect(output).To(o.BeEmpty())
+
+		cmd, _, _, _, _ = newCmd()
+		printerFlags := &printer.Flags{}
+		printerFlags.AddFlags(cmd.Flags())
+		g.Expect((&Config{Output: "yaml"}).ApplyDefaults(cmd, nil)).To(o.Succeed())
+		g.Expect(printerFlags.Output).To(o.Equal("yaml"))
+	})
+
+	t.Run("invalid values are reported", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		cmd := &cobra.Command{Use: "create"}
+		var count int
+		cmd.Flags().IntVar(&count, "namespace", 0, "")
+		g.Expect(cfg.ApplyDefaults(cmd, nil)).ToNot(o.Succeed())
+	})
+}
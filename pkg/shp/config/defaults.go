@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// SkipDefaultsAnnotation command annotation disabling the configuration defaults.
+const SkipDefaultsAnnotation = "shp.io/skip-config-defaults"
+
+// SkipFlagDefaultAnnotation flag annotation disabling the configuration default of the flag, for
+// flags whose meaning differs from the configuration key, like the Build flags of "shp build update".
+const SkipFlagDefaultAnnotation = "shp.io/skip-config-default"
+
+// ApplyDefaults sets the command flags not informed explicitly with the configured values, unless
+// a flag conflicting with the key is informed. The registry prefix only applies to the output image
+// of commands requiring it, composed with the first argument, the Build name.
+func (c *Config) ApplyDefaults(cmd *cobra.Command, args []string) error {
+	if _, skip := cmd.Annotations[SkipDefaultsAnnotation]; skip {
+		return nil
+	}
+
+	for _, k := range Keys {
+		value := k.get(c)
+		if value == "" {
+			continue
+		}
+		f := cmd.Flags().Lookup(k.Flag)
+		if f == nil || f.Changed || conflictInformed(cmd, k.Conflicts) {
+			continue
+		}
+		if _, skip := f.Annotations[SkipFlagDefaultAnnotation]; skip {
+			continue
+		}
+		if _, annotated := f.Annotations[k.Annotation]; k.Annotation != "" && !annotated {
+			continue
+		}
+		if k.Name == "registry-prefix" {
+			if _, required := f.Annotations[cobra.BashCompOneRequiredFlag]; !required || len(args) == 0 {
+				continue
+			}
+			value = fmt.Sprintf("%s/%s", strings.TrimSuffix(value, "/"), args[0])
+		}
+		if err := cmd.Flags().Set(k.Flag, value); err != nil {
+			return fmt.Errorf("invalid configuration %q for flag --%s: %w", k.Name, k.Flag, err)
+		}
+	}
+	return nil
+}
+
+// conflictInformed tells whether any of the conflicting flags has been informed explicitly.
+func conflictInformed(cmd *cobra.Command, conflicts []string) bool {
+	for _, name := range conflicts {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
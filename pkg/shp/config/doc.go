@@ -0,0 +1,3 @@
+// Package config contains the shp configuration file, persistent defaults applied to the
+// command-line flags not informed explicitly.
+package config
@@ -0,0 +1,132 @@
+package debug
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/test/mock"
+)
+
+func TestValidatePorts(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(ValidatePorts([]string{"5005", "8080:80", ":9090", "0:9090"})).To(o.Succeed())
+	for _, invalid := range []string{"", "debug", "8080:", "8080:0", "a:80", "70000:80", "80:70000"} {
+		g.Expect(ValidatePorts([]string{invalid})).NotTo(o.Succeed(), invalid)
+	}
+}
+
+func TestWaitForRunningPod(t *testing.T) {
+	g := o.NewWithT(t)
+
+	newPod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name, Labels: map[string]string{"app": "build"}},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	listOpts := metav1.ListOptions{LabelSelector: "app=build"}
+
+	clientset := fake.NewSimpleClientset(newPod("failed", corev1.PodFailed), newPod("running", corev1.PodRunning))
+	pod, err := WaitForRunningPod(context.TODO(), clientset, metav1.NamespaceDefault, listOpts, time.Millisecond)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(pod.GetName()).To(o.Equal("running"))
+
+	clientset = fake.NewSimpleClientset(newPod("succeeded", corev1.PodSucceeded))
+	_, err = WaitForRunningPod(context.TODO(), clientset, metav1.NamespaceDefault, listOpts, time.Millisecond)
+	g.Expect(err).To(o.MatchError("the pod is done, there is nothing to connect to"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	clientset = fake.NewSimpleClientset(newPod("pending", corev1.PodPending))
+	_, err = WaitForRunningPod(ctx, clientset, metav1.NamespaceDefault, listOpts, time.Millisecond)
+	g.Expect(err).To(o.HaveOccurred())
+}
+
+// syncBuffer a buffer safe for concurrent use.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.buf.String()
+}
+
+func TestPortForwarderStart(t *testing.T) {
+	g := o.NewWithT(t)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br-pod", Labels: map[string]string{"app": "build"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	listOpts := metav1.ListOptions{LabelSelector: "app=build"}
+
+	t.Run("forwarding until stopped", func(_ *testing.T) {
+		forwarded := make(chan string, 1)
+		p := NewPortForwarder(nil, fake.NewSimpleClientset(pod))
+		p.forward = func(ctx context.Context, _, pod string, _ []string, _, _ io.Writer) error {
+			forwarded <- pod
+			<-ctx.Done()
+			return nil
+		}
+
+		errOut := &bytes.Buffer{}
+		stop := p.Start(context.TODO(), metav1.NamespaceDefault, listOpts, []string{"5005"}, errOut)
+		g.Expect(<-forwarded).To(o.Equal("br-pod"))
+		stop()
+		g.Expect(errOut.String()).To(o.BeEmpty())
+	})
+
+	t.Run("failures are warnings", func(_ *testing.T) {
+		p := NewPortForwarder(nil, fake.NewSimpleClientset(pod))
+		p.forward = func(context.Context, string, string, []string, io.Writer, io.Writer) error {
+			return errors.New("address already in use")
+		}
+
+		errOut := &syncBuffer{}
+		stop := p.Start(context.TODO(), metav1.NamespaceDefault, listOpts, []string{"5005", "8080:80"}, errOut)
+		g.Eventually(errOut.String).Should(o.Equal(
+			"Warning: unable to forward the ports 5005, 8080:80: address already in use\n"))
+		stop()
+	})
+}
+
+func TestExecutor(t *testing.T) {
+	g := o.NewWithT(t)
+
+	f := mock.NewFakeClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	})
+	e := NewExecutor(f.RESTConfig(), f.Clientset())
+	re := mock.NewFakeRemoteExecutor(nil)
+	e.remoteExecutor = re
+
+	ioStreams, in, _, _ := genericclioptions.NewTestIOStreams()
+	in.WriteString("input")
+	err := e.Exec(metav1.NamespaceDefault, "pod", "step-build", []string{"cat"}, true, false, ioStreams)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(re.Command()).To(o.Equal([]string{"cat"}))
+	g.Expect(re.Stdin()).To(o.Equal("input"))
+}
@@ -0,0 +1,4 @@
+// Package debug holds the helpers to debug the build pods, running commands on the step containers
+// and forwarding local ports to the pod, for strategies exposing debug servers or holding steps on
+// breakpoints.
+package debug
@@ -0,0 +1,55 @@
+package debug
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/kubectl/pkg/cmd/exec"
+	"k8s.io/kubectl/pkg/util/interrupt"
+)
+
+// Executor runs commands on the containers of a running pod, the equivalent of "kubectl exec".
+type Executor struct {
+	restConfig     *rest.Config         // rest API client configuration
+	clientset      kubernetes.Interface // kubernetes client
+	remoteExecutor exec.RemoteExecutor  // overwritten during testing
+}
+
+// Exec runs the command on the pod container, wiring the standard input when stdin is set, and
+// allocating a terminal when tty is set as well, thus interactive shells work as expected.
+func (e *Executor) Exec(
+	namespace, pod, container string,
+	command []string,
+	stdin, tty bool,
+	ioStreams genericclioptions.IOStreams,
+) error {
+	opts := &exec.ExecOptions{
+		StreamOptions: exec.StreamOptions{
+			Namespace:       namespace,
+			PodName:         pod,
+			ContainerName:   container,
+			Stdin:           stdin,
+			TTY:             tty,
+			Quiet:           true,
+			InterruptParent: &interrupt.Handler{},
+			IOStreams:       ioStreams,
+		},
+		Config:    e.restConfig,
+		PodClient: e.clientset.CoreV1(),
+		Command:   command,
+		Executor:  e.remoteExecutor,
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	return opts.Run()
+}
+
+// NewExecutor instantiate Executor.
+func NewExecutor(restConfig *rest.Config, clientset kubernetes.Interface) *Executor {
+	return &Executor{
+		restConfig:     restConfig,
+		clientset:      clientset,
+		remoteExecutor: &exec.DefaultRemoteExecutor{},
+	}
+}
@@ -0,0 +1,171 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// runningPodPollInterval interval between the checks for the build pod to be running.
+const runningPodPollInterval = time.Second
+
+// ValidatePorts checks the port mappings are in the "[LOCAL:]REMOTE" format, where a local port
+// zero picks a random port.
+func ValidatePorts(ports []string) error {
+	for _, mapping := range ports {
+		local, remote, found := strings.Cut(mapping, ":")
+		if !found {
+			local, remote = remote, local
+		}
+		if local != "" {
+			if _, err := strconv.ParseUint(local, 10, 16); err != nil {
+				return fmt.Errorf("invalid local port in %q, expected [LOCAL:]REMOTE", mapping)
+			}
+		}
+		if p, err := strconv.ParseUint(remote, 10, 16); err != nil || p == 0 {
+			return fmt.Errorf("invalid remote port in %q, expected [LOCAL:]REMOTE", mapping)
+		}
+	}
+	return nil
+}
+
+// PortForwarder forwards local ports to a running pod, the equivalent of "kubectl port-forward".
+type PortForwarder struct {
+	restConfig *rest.Config         // rest API client configuration
+	clientset  kubernetes.Interface // kubernetes client
+
+	// forward forwards the ports to the pod until the context is done, overwritten during testing
+	forward func(ctx context.Context, namespace, pod string, ports []string, out, errOut io.Writer) error
+}
+
+// Forward forwards the local ports to the pod until the context is done, the addresses listened
+// are printed on out.
+func (p *PortForwarder) Forward(ctx context.Context, namespace, pod string, ports []string, out, errOut io.Writer) error {
+	return p.forward(ctx, namespace, pod, ports, out, errOut)
+}
+
+// ForwardWhenRunning waits for a pod selected by the list options to be running, and forwards the
+// local ports to it until the context is done.
+func (p *PortForwarder) ForwardWhenRunning(
+	ctx context.Context,
+	namespace string,
+	listOpts metav1.ListOptions,
+	ports []string,
+	out, errOut io.Writer,
+) error {
+	pod, err := WaitForRunningPod(ctx, p.clientset, namespace, listOpts, runningPodPollInterval)
+	if err != nil {
+		return err
+	}
+	return p.forward(ctx, namespace, pod.GetName(), ports, out, errOut)
+}
+
+// Start forwards the ports in the background, once a pod selected by the list options is running,
+// until the returned function is called. Failures are reported as warnings on errOut, the command
+// following the pod is not interrupted by them.
+func (p *PortForwarder) Start(
+	ctx context.Context,
+	namespace string,
+	listOpts metav1.ListOptions,
+	ports []string,
+	errOut io.Writer,
+) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := p.ForwardWhenRunning(ctx, namespace, listOpts, ports, errOut, errOut)
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintf(errOut, "Warning: unable to forward the ports %s: %v\n", strings.Join(ports, ", "), err)
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// portForward forwards the ports through the pod "portforward" subresource.
+func (p *PortForwarder) portForward(ctx context.Context, namespace, pod string, ports []string, out, errOut io.Writer) error {
+	transport, upgrader, err := spdy.RoundTripperFor(p.restConfig)
+	if err != nil {
+		return err
+	}
+	url := p.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(stopCh)
+		case <-done:
+		}
+	}()
+
+	fw, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, ports, stopCh, nil, out, errOut)
+	if err != nil {
+		return err
+	}
+	return fw.ForwardPorts()
+}
+
+// NewPortForwarder instantiate PortForwarder.
+func NewPortForwarder(restConfig *rest.Config, clientset kubernetes.Interface) *PortForwarder {
+	p := &PortForwarder{restConfig: restConfig, clientset: clientset}
+	p.forward = p.portForward
+	return p
+}
+
+// WaitForRunningPod polls the pods selected by the list options until one of them is running,
+// failing when they are all done, or the context is done.
+func WaitForRunningPod(
+	ctx context.Context,
+	clientset kubernetes.Interface,
+	namespace string,
+	listOpts metav1.ListOptions,
+	interval time.Duration,
+) (*corev1.Pod, error) {
+	var running *corev1.Pod
+	err := wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+		pods, err := clientset.CoreV1().Pods(namespace).List(ctx, listOpts)
+		if err != nil {
+			return false, err
+		}
+		done := len(pods.Items) > 0
+		for i := range pods.Items {
+			switch pods.Items[i].Status.Phase {
+			case corev1.PodRunning:
+				running = &pods.Items[i]
+				return true, nil
+			case corev1.PodSucceeded, corev1.PodFailed:
+			default:
+				done = false
+			}
+		}
+		if done {
+			return false, fmt.Errorf("the pod is done, there is nothing to connect to")
+		}
+		return false, nil
+	})
+	return running, err
+}
@@ -0,0 +1,172 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+)
+
+// Operations describing how a field changes.
+const (
+	Added    = "added"
+	Removed  = "removed"
+	Modified = "modified"
+)
+
+// mergeKey field identifying the elements of object lists, i.e. environment variables and
+// parameters, thus elements are compared by name instead of position.
+const mergeKey = "name"
+
+// Change difference on a single field.
+type Change struct {
+	Path      string      `json:"path"`              // field path, i.e. "spec.env[name=FOO].value"
+	Operation string      `json:"operation"`         // added, removed or modified
+	Live      interface{} `json:"live,omitempty"`    // value on the live object
+	Desired   interface{} `json:"desired,omitempty"` // value on the desired object
+}
+
+// Compare returns the changes turning the live object into the desired one, both in their
+// unstructured form, sorted by path.
+func Compare(live, desired map[string]interface{}) []Change {
+	changes := []Change{}
+	compare("", live, desired, &changes)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// compare appends the changes between both values, descending into objects and lists. Objects
+// added or removed as a whole are described field by field.
+func compare(path string, live, desired interface{}, changes *[]Change) {
+	if reflect.DeepEqual(live, desired) {
+		return
+	}
+	liveMap, liveIsMap := live.(map[string]interface{})
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	switch {
+	case liveIsMap && (desiredIsMap || desired == nil):
+		compareMaps(path, liveMap, desiredMap, changes)
+		return
+	case desiredIsMap && live == nil:
+		compareMaps(path, liveMap, desiredMap, changes)
+		return
+	case live == nil:
+		*changes = append(*changes, Change{Path: path, Operation: Added, Desired: desired})
+		return
+	case desired == nil:
+		*changes = append(*changes, Change{Path: path, Operation: Removed, Live: live})
+		return
+	}
+
+	liveList, liveIsList := live.([]interface{})
+	desiredList, desiredIsList := desired.([]interface{})
+	if liveIsList && desiredIsList {
+		compareLists(path, liveList, desiredList, changes)
+		return
+	}
+
+	*changes = append(*changes, Change{Path: path, Operation: Modified, Live: live, Desired: desired})
+}
+
+// compareMaps compares the fields of both objects, either may be nil.
+func compareMaps(path string, live, desired map[string]interface{}, changes *[]Change) {
+	keys := map[string]bool{}
+	for k := range live {
+		keys[k] = true
+	}
+	for k := range desired {
+		keys[k] = true
+	}
+	for k := range keys {
+		compare(join(path, k), live[k], desired[k], changes)
+	}
+}
+
+// compareLists compares lists of objects by their merge key, and other lists by position.
+func compareLists(path string, live, desired []interface{}, changes *[]Change) {
+	liveByKey, liveKeyed := byMergeKey(live)
+	desiredByKey, desiredKeyed := byMergeKey(desired)
+	if liveKeyed && desiredKeyed {
+		keys := map[string]bool{}
+		for k := range liveByKey {
+			keys[k] = true
+		}
+		for k := range desiredByKey {
+			keys[k] = true
+		}
+		for k := range keys {
+			compare(fmt.Sprintf("%s[%s=%s]", path, mergeKey, k), liveByKey[k], desiredByKey[k], changes)
+		}
+		return
+	}
+
+	for i := 0; i < len(live) || i < len(desired); i++ {
+		var l, d interface{}
+		if i < len(live) {
+			l = live[i]
+		}
+		if i < len(desired) {
+			d = desired[i]
+		}
+		compare(fmt.Sprintf("%s[%d]", path, i), l, d, changes)
+	}
+}
+
+// byMergeKey indexes the list elements by their merge key, false when any element is not an object
+// carrying an unique merge key.
+func byMergeKey(list []interface{}) (map[string]interface{}, bool) {
+	indexed := map[string]interface{}{}
+	for _, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		key, ok := obj[mergeKey].(string)
+		if !ok {
+			return nil, false
+		}
+		if _, duplicated := indexed[key]; duplicated {
+			return nil, false
+		}
+		indexed[key] = item
+	}
+	return indexed, true
+}
+
+// join appends the field to the path.
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// Print renders the changes, one per line, prefixed by "+" when added, "-" when removed and "~"
+// when modified.
+func Print(w io.Writer, changes []Change) {
+	for _, c := range changes {
+		switch c.Operation {
+		case Added:
+			fmt.Fprintln(w, styles.Success(fmt.Sprintf("  + %s: %s", c.Path, render(c.Desired))))
+		case Removed:
+			fmt.Fprintln(w, styles.Failure(fmt.Sprintf("  - %s: %s", c.Path, render(c.Live))))
+		default:
+			fmt.Fprintln(w, styles.Warning(fmt.Sprintf("  ~ %s: %s -> %s", c.Path, render(c.Live), render(c.Desired))))
+		}
+	}
+}
+
+// render formats the value as compact JSON, thus strings are quoted and objects fit in one line.
+func render(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return strings.TrimSpace(string(data))
+}
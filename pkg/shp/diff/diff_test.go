@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestCompare(t *testing.T) {
+	g := o.NewWithT(t)
+
+	live := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"dockerfile": "Dockerfile",
+			"source":     map[string]interface{}{"url": "https://github.com/org/app", "revision": "main"},
+			"env": []interface{}{
+				map[string]interface{}{"name": "A", "value": "1"},
+				map[string]interface{}{"name": "B", "value": "2"},
+			},
+			"args": []interface{}{"a", "b"},
+		},
+	}
+	desired := map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{"team": "platform"}},
+		"spec": map[string]interface{}{
+			"source":  map[string]interface{}{"url": "https://github.com/org/app", "revision": "v2"},
+			"timeout": "15m",
+			"env": []interface{}{
+				map[string]interface{}{"name": "B", "value": "3"},
+				map[string]interface{}{"name": "A", "value": "1"},
+				map[string]interface{}{"name": "C", "value": "4"},
+			},
+			"args": []interface{}{"a"},
+		},
+	}
+
+	changes := Compare(live, desired)
+	g.Expect(changes).To(o.Equal([]Change{
+		{Path: "metadata.labels.team", Operation: Added, Desired: "platform"},
+		{Path: "spec.args[1]", Operation: Removed, Live: "b"},
+		{Path: "spec.dockerfile", Operation: Removed, Live: "Dockerfile"},
+		{Path: "spec.env[name=B].value", Operation: Modified, Live: "2", Desired: "3"},
+		{Path: "spec.env[name=C].name", Operation: Added, Desired: "C"},
+		{Path: "spec.env[name=C].value", Operation: Added, Desired: "4"},
+		{Path: "spec.source.revision", Operation: Modified, Live: "main", Desired: "v2"},
+		{Path: "spec.timeout", Operation: Added, Desired: "15m"},
+	}))
+
+	g.Expect(Compare(live, live)).To(o.BeEmpty())
+	g.Expect(Compare(nil, map[string]interface{}{"spec": map[string]interface{}{"dockerfile": "Dockerfile"}})).To(o.Equal([]Change{
+		{Path: "spec.dockerfile", Operation: Added, Desired: "Dockerfile"},
+	}))
+
+	var out bytes.Buffer
+	Print(&out, changes[:4])
+	g.Expect(out.String()).To(o.Equal(`  + metadata.labels.team: "platform"
+  - spec.args[1]: "b"
+  - spec.dockerfile: "Dockerfile"
+  ~ spec.env[name=B].value: "2" -> "3"
+`))
+}
@@ -0,0 +1,3 @@
+// Package diff computes the field level differences between the live and the desired state of an
+// object, rendered as a structured list of changes for humans and machines.
+package diff
@@ -0,0 +1,163 @@
+package exitcode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Error formats supported by Print.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formats supported error formats.
+var Formats = []string{FormatText, FormatJSON}
+
+// Class describes an exit code, the class name is part of the structured error output.
+type Class struct {
+	Code        int    // process exit code
+	Name        string // class name
+	Description string // when the exit code is employed
+}
+
+// Classes the exit code taxonomy, the codes are stable across releases.
+var Classes = []Class{
+	{Success, "Success", "the command has completed successfully"},
+	{Failure, "Failure", "generic failure, or the BuildRun has failed"},
+	{Timeout, "Timeout", "the BuildRun, or the wait for it, has timed out"},
+	{Cancelled, "Cancelled", "the BuildRun has been cancelled"},
+	{Vulnerable, "Vulnerable", "the output image has vulnerabilities at, or above, the severity informed"},
+	{Usage, "Usage", "the command-line arguments or flags are invalid"},
+	{NotFound, "NotFound", "the resource informed, or one it depends on, does not exist"},
+	{Unauthorized, "Unauthorized", "the credentials are invalid or lack the permissions required"},
+	{Conflict, "Conflict", "the resource already exists or has been modified concurrently"},
+	{Unavailable, "Unavailable", "the cluster can't be reached or is not able to serve the request"},
+}
+
+// Details structured description of an error, rendered by Print.
+type Details struct {
+	Class       string `json:"class"`                 // exit code class name
+	Reason      string `json:"reason"`                // specific cause, i.e. the API status reason
+	Message     string `json:"message"`               // original error message
+	Remediation string `json:"remediation,omitempty"` // hint on how to solve the error
+	ExitCode    int    `json:"exitCode"`              // process exit code
+}
+
+// classOf returns the class name of the exit code.
+func classOf(code int) string {
+	for _, c := range Classes {
+		if c.Code == code {
+			return c.Name
+		}
+	}
+	return "Failure"
+}
+
+// Describe classifies the error, the exit code carried by the error prevails, otherwise the
+// Kubernetes API status and network errors are inspected.
+func Describe(err error) Details {
+	d := Details{Message: err.Error(), ExitCode: Failure}
+
+	var exitErr *Error
+	if errors.As(err, &exitErr) {
+		d.ExitCode = exitErr.Code
+	}
+
+	var status kerrors.APIStatus
+	var netErr net.Error
+	switch {
+	case errors.As(err, &status):
+		d.Reason = string(status.Status().Reason)
+		if exitErr == nil {
+			d.ExitCode, d.Remediation = classifyStatus(status.Status().Reason)
+		} else {
+			_, d.Remediation = classifyStatus(status.Status().Reason)
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		// the deadline error satisfies net.Error as well, yet it's a timeout of the command itself
+		d.Reason = "DeadlineExceeded"
+		if exitErr == nil {
+			d.ExitCode = Timeout
+		}
+		d.Remediation = "the cluster took too long to respond, try again or extend the timeout"
+	case errors.As(err, &netErr) || strings.Contains(err.Error(), "connection refused"):
+		d.Reason = "ConnectionFailed"
+		if exitErr == nil {
+			d.ExitCode = Unavailable
+		}
+		d.Remediation = "check the cluster is reachable, the cluster and context are selected by --kubeconfig and --context"
+	}
+
+	if exitErr != nil && exitErr.Reason != "" {
+		d.Reason = exitErr.Reason
+	}
+	d.Class = classOf(d.ExitCode)
+	if d.Reason == "" {
+		d.Reason = d.Class
+	}
+	if d.Remediation == "" {
+		d.Remediation = remediationOf(d.ExitCode)
+	}
+	return d
+}
+
+// classifyStatus returns the exit code and remediation hint for the Kubernetes API status reason.
+func classifyStatus(reason metav1.StatusReason) (int, string) {
+	switch reason {
+	case metav1.StatusReasonNotFound:
+		return NotFound, "verify the name and the namespace, informed by --namespace"
+	case metav1.StatusReasonUnauthorized:
+		return Unauthorized, "log in to the cluster again, the credentials may have expired"
+	case metav1.StatusReasonForbidden:
+		return Unauthorized, "ask the cluster administrator for the permissions required"
+	case metav1.StatusReasonAlreadyExists:
+		return Conflict, "use a different name, or delete the existing resource first"
+	case metav1.StatusReasonConflict:
+		return Conflict, "the resource has been modified concurrently, try again"
+	case metav1.StatusReasonInvalid, metav1.StatusReasonBadRequest:
+		return Usage, "review the values informed, the message describes the invalid fields"
+	case metav1.StatusReasonTimeout, metav1.StatusReasonServerTimeout,
+		metav1.StatusReasonTooManyRequests, metav1.StatusReasonServiceUnavailable:
+		return Unavailable, "the cluster is busy or unavailable, try again later"
+	default:
+		return Failure, ""
+	}
+}
+
+// remediationOf returns the generic remediation hint of the exit code, empty when there's none.
+func remediationOf(code int) string {
+	switch code {
+	case Usage:
+		return "see the command usage with --help"
+	case Timeout:
+		return "inspect the BuildRun, the timeout can be extended on the Build or BuildRun"
+	case Vulnerable:
+		return "inspect the vulnerabilities with \"shp buildrun vulnerabilities\""
+	default:
+		return ""
+	}
+}
+
+// Print writes the error in the informed format, either the "ERROR:" prefixed message or the
+// structured Details as JSON, text is assumed for unknown formats.
+func Print(w io.Writer, err error, format string) {
+	if format != FormatJSON {
+		fmt.Fprintf(w, "ERROR: %v\n", err)
+		return
+	}
+	data, jsonErr := json.Marshal(Describe(err))
+	if jsonErr != nil {
+		fmt.Fprintf(w, "ERROR: %v\n", err)
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
@@ -0,0 +1,121 @@
+package exitcode
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDescribe(t *testing.T) {
+	buildRuns := schema.GroupResource{Group: "shipwright.io", Resource: "buildruns"}
+	tests := []struct {
+		name     string
+		err      error
+		class    string
+		reason   string
+		exitCode int
+	}{
+		{
+			name:     "generic error",
+			err:      errors.New("boom"),
+			class:    "Failure",
+			reason:   "Failure",
+			exitCode: Failure,
+		},
+		{
+			name:     "exit code carried by the error",
+			err:      Errorf(Timeout, "timed out waiting for BuildRun %q", "br"),
+			class:    "Timeout",
+			reason:   "Timeout",
+			exitCode: Timeout,
+		},
+		{
+			name:     "specific reason carried by the error",
+			err:      Errorf(Timeout, "BuildRun %q has exceeded its timeout", "br").WithReason("BuildRunTimeout"),
+			class:    "Timeout",
+			reason:   "BuildRunTimeout",
+			exitCode: Timeout,
+		},
+		{
+			name:     "wrapped not found",
+			err:      fmt.Errorf("failed: %w", kerrors.NewNotFound(buildRuns, "br")),
+			class:    "NotFound",
+			reason:   "NotFound",
+			exitCode: NotFound,
+		},
+		{
+			name:     "forbidden",
+			err:      kerrors.NewForbidden(buildRuns, "br", errors.New("no RBAC")),
+			class:    "Unauthorized",
+			reason:   "Forbidden",
+			exitCode: Unauthorized,
+		},
+		{
+			name:     "already exists",
+			err:      kerrors.NewAlreadyExists(buildRuns, "br"),
+			class:    "Conflict",
+			reason:   "AlreadyExists",
+			exitCode: Conflict,
+		},
+		{
+			name:     "usage exit code prevails over the API status",
+			err:      Wrap(Usage, kerrors.NewBadRequest("invalid")),
+			class:    "Usage",
+			reason:   "BadRequest",
+			exitCode: Usage,
+		},
+		{
+			name:     "deadline exceeded",
+			err:      fmt.Errorf("listing pods: %w", context.DeadlineExceeded),
+			class:    "Timeout",
+			reason:   "DeadlineExceeded",
+			exitCode: Timeout,
+		},
+		{
+			name:     "connection refused",
+			err:      errors.New("dial tcp 127.0.0.1:6443: connect: connection refused"),
+			class:    "Unavailable",
+			reason:   "ConnectionFailed",
+			exitCode: Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			d := Describe(tt.err)
+			g.Expect(d.Class).To(o.Equal(tt.class))
+			g.Expect(d.Reason).To(o.Equal(tt.reason))
+			g.Expect(d.ExitCode).To(o.Equal(tt.exitCode))
+			g.Expect(d.Message).To(o.Equal(tt.err.Error()))
+			g.Expect(FromError(tt.err)).To(o.Equal(tt.exitCode))
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	g := o.NewWithT(t)
+	err := kerrors.NewNotFound(schema.GroupResource{Group: "shipwright.io", Resource: "builds"}, "app")
+
+	var text bytes.Buffer
+	Print(&text, err, FormatText)
+	g.Expect(text.String()).To(o.Equal("ERROR: builds.shipwright.io \"app\" not found\n"))
+
+	var data bytes.Buffer
+	Print(&data, err, FormatJSON)
+	g.Expect(data.String()).To(o.MatchJSON(`{
+		"class": "NotFound",
+		"reason": "NotFound",
+		"message": "builds.shipwright.io \"app\" not found",
+		"remediation": "verify the name and the namespace, informed by --namespace",
+		"exitCode": 6
+	}`))
+}
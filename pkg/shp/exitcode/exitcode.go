@@ -0,0 +1,79 @@
+// Package exitcode defines the process exit codes used by shp, and an error type carrying the exit
+// code the process should terminate with. Errors are classified by the exit code taxonomy, and can
+// be described for machine consumption along with a remediation hint.
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// Success the command has completed successfully.
+	Success = 0
+	// Failure generic failure, or the BuildRun has failed.
+	Failure = 1
+	// Timeout the BuildRun, or the wait for it, has timed out.
+	Timeout = 2
+	// Cancelled the BuildRun has been cancelled.
+	Cancelled = 3
+	// Vulnerable the output image has vulnerabilities at, or above, the severity gating the build.
+	Vulnerable = 4
+	// Usage the command-line arguments or flags are invalid.
+	Usage = 5
+	// NotFound the resource informed, or one it depends on, does not exist.
+	NotFound = 6
+	// Unauthorized the credentials are invalid or lack the permissions required.
+	Unauthorized = 7
+	// Conflict the resource already exists or has been modified concurrently.
+	Conflict = 8
+	// Unavailable the cluster can't be reached or is not able to serve the request.
+	Unavailable = 9
+)
+
+// Error wraps an error with the exit code the process should terminate with.
+type Error struct {
+	Code   int    // process exit code
+	Err    error  // original error
+	Reason string // specific cause, described instead of the exit code class when informed
+}
+
+// Error returns the original error message.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap exposes the original error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errorf creates an Error with the informed exit code and formatted message.
+func Errorf(code int, format string, a ...interface{}) *Error {
+	return &Error{Code: code, Err: fmt.Errorf(format, a...)}
+}
+
+// WithReason sets the specific cause of the error, part of the structured error output.
+func (e *Error) WithReason(reason string) *Error {
+	e.Reason = reason
+	return e
+}
+
+// Wrap creates an Error with the informed exit code, unless the error already carries one, or it's
+// nil.
+func Wrap(code int, err error) error {
+	var exitErr *Error
+	if err == nil || errors.As(err, &exitErr) {
+		return err
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// FromError returns the exit code for the informed error, Success when nil. Errors not carrying a
+// specific exit code are classified by their cause, Failure when unknown.
+func FromError(err error) int {
+	if err == nil {
+		return Success
+	}
+	return Describe(err).ExitCode
+}
@@ -0,0 +1,19 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AdditionalOutputImageFlag command-line flag.
+const AdditionalOutputImageFlag = "additional-output-image"
+
+// AdditionalOutputImageFlags registers the repeatable flag to copy the output image to other
+// registries after a successful run.
+func AdditionalOutputImageFlags(flags *pflag.FlagSet, images *[]string) {
+	flags.StringArrayVar(
+		images,
+		AdditionalOutputImageFlag,
+		[]string{},
+		"tagged image the output image is copied to after a successful run, may be informed more than once",
+	)
+}
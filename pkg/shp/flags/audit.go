@@ -0,0 +1,19 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AuditFlag command-line flag.
+const AuditFlag = "audit"
+
+// AuditFlags registers the flag recording who triggered the BuildRun, from which host and CLI
+// version, recording the value on the informed boolean pointer.
+func AuditFlags(flags *pflag.FlagSet, audit *bool) {
+	flags.BoolVar(
+		audit,
+		AuditFlag,
+		false,
+		"annotate the BuildRun with the user, host and CLI version triggering it, and record an Event on it",
+	)
+}
@@ -0,0 +1,52 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	// SelectorFlag command-line flag.
+	SelectorFlag = "selector"
+	// ConcurrencyFlag command-line flag.
+	ConcurrencyFlag = "concurrency"
+	// FailFastFlag command-line flag.
+	FailFastFlag = "fail-fast"
+)
+
+// Batch the Builds run at once, either read from manifests or selected by labels, and how many
+// of them run concurrently.
+type Batch struct {
+	Filenames   []string // manifests of the Builds to run
+	Selector    string   // label selector of the Builds to run
+	Concurrency int      // maximum amount of BuildRuns running at once
+	FailFast    bool     // stop running the Builds after the first failure
+}
+
+// BatchFlags registers the flags to run several Builds at once.
+func BatchFlags(flags *pflag.FlagSet, b *Batch) {
+	FilenamesFlags(flags, &b.Filenames)
+	flags.StringVarP(
+		&b.Selector,
+		SelectorFlag,
+		"l",
+		"",
+		"label selector of the Builds to run, instead of informing the Build name",
+	)
+	flags.IntVar(
+		&b.Concurrency,
+		ConcurrencyFlag,
+		4,
+		"maximum amount of BuildRuns running at once when running several Builds",
+	)
+	flags.BoolVar(
+		&b.FailFast,
+		FailFastFlag,
+		false,
+		"cancel the running BuildRuns and skip the pending Builds after the first failure",
+	)
+}
+
+// IsEmpty tells whether neither manifests nor a selector have been informed.
+func (b *Batch) IsEmpty() bool {
+	return len(b.Filenames) == 0 && b.Selector == ""
+}
@@ -32,6 +32,7 @@ func BuildSpecFromFlags(flags *pflag.FlagSet) *buildv1alpha1.BuildSpec {
 		Dockerfile: pointer.String(""),
 		Builder: &buildv1alpha1.Image{
 			Credentials: &corev1.LocalObjectReference{},
+			Insecure:    pointer.Bool(false),
 		},
 		Output: buildv1alpha1.Image{
 			Credentials: &corev1.LocalObjectReference{},
@@ -55,6 +56,7 @@ func BuildSpecFromFlags(flags *pflag.FlagSet) *buildv1alpha1.BuildSpec {
 	imageFlags(flags, "output", &spec.Output)
 	timeoutFlags(flags, spec.Timeout)
 	envFlags(flags, &spec.Env)
+	paramFlags(flags, &spec.ParamValues)
 	imageLabelsFlags(flags, spec.Output.Labels)
 	imageAnnotationsFlags(flags, spec.Output.Annotations)
 	buildRetentionFlags(flags, spec.Retention)
@@ -86,13 +88,16 @@ func SanitizeBuildSpec(b *buildv1alpha1.BuildSpec) {
 		if b.Builder.Credentials != nil && b.Builder.Credentials.Name == "" {
 			b.Builder.Credentials = nil
 		}
-		if b.Builder.Image == "" && b.Builder.Credentials == nil {
-			b.Builder = nil
+		if b.Builder.Insecure != nil && !*b.Builder.Insecure {
+			b.Builder.Insecure = nil
 		}
-		if len(b.Env) == 0 {
-			b.Env = nil
+		if b.Builder.Image == "" && b.Builder.Credentials == nil && b.Builder.Insecure == nil {
+			b.Builder = nil
 		}
 	}
+	if len(b.Env) == 0 {
+		b.Env = nil
+	}
 	if b.Timeout != nil && b.Timeout.Duration == 0 {
 		b.Timeout = nil
 	}
@@ -36,6 +36,7 @@ func TestBuildSpecFromFlags(t *testing.T) {
 		Builder: &buildv1alpha1.Image{
 			Credentials: &credentials,
 			Image:       "builder-image",
+			Insecure:    pointer.Bool(true),
 		},
 		Output: buildv1alpha1.Image{
 			Credentials: &credentials,
@@ -107,6 +108,9 @@ func TestBuildSpecFromFlags(t *testing.T) {
 		err = flags.Set(BuilderCredentialsSecretFlag, expected.Builder.Credentials.Name)
 		g.Expect(err).To(o.BeNil())
 
+		err = flags.Set(BuilderInsecureFlag, strconv.FormatBool(*expected.Builder.Insecure))
+		g.Expect(err).To(o.BeNil())
+
 		g.Expect(*expected.Builder).To(o.Equal(*spec.Builder), "spec.builder")
 	})
 
@@ -198,6 +202,22 @@ func TestSanitizeBuildSpec(t *testing.T) {
 		name: "should clean-up `.spec.builder.image`",
 		in:   buildv1alpha1.BuildSpec{Builder: &buildv1alpha1.Image{}},
 		out:  buildv1alpha1.BuildSpec{},
+	}, {
+		name: "should clean-up a false `.spec.builder.insecure`",
+		in: buildv1alpha1.BuildSpec{Builder: &buildv1alpha1.Image{
+			Insecure: pointer.Bool(false),
+		}},
+		out: buildv1alpha1.BuildSpec{},
+	}, {
+		name: "should not clean-up a true `.spec.builder.insecure`",
+		in: buildv1alpha1.BuildSpec{Builder: &buildv1alpha1.Image{
+			Image:    "image",
+			Insecure: pointer.Bool(true),
+		}},
+		out: buildv1alpha1.BuildSpec{Builder: &buildv1alpha1.Image{
+			Image:    "image",
+			Insecure: pointer.Bool(true),
+		}},
 	}, {
 		name: "should not clean-up complete objects",
 		in:   completeBuildSpec,
@@ -226,6 +246,26 @@ func TestSanitizeBuildSpec(t *testing.T) {
 			Retention: &buildv1alpha1.BuildRetention{},
 		},
 		out: buildv1alpha1.BuildSpec{},
+	}, {
+		name: "should clean-up empty `.spec.env` without a builder",
+		in:   buildv1alpha1.BuildSpec{Env: []corev1.EnvVar{}},
+		out:  buildv1alpha1.BuildSpec{},
+	}, {
+		name: "should not clean-up `.spec.env` referencing secrets",
+		in: buildv1alpha1.BuildSpec{Env: []corev1.EnvVar{{
+			Name: "TOKEN",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+				Key:                  "token",
+			}},
+		}}},
+		out: buildv1alpha1.BuildSpec{Env: []corev1.EnvVar{{
+			Name: "TOKEN",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+				Key:                  "token",
+			}},
+		}}},
 	}, {
 		name: "should clean-up an empty source contextDir",
 		in: buildv1alpha1.BuildSpec{
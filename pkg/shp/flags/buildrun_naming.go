@@ -0,0 +1,99 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// BuildRunNameFlag command-line flag.
+	BuildRunNameFlag = "buildrun-name"
+	// GenerateNamePrefixFlag command-line flag.
+	GenerateNamePrefixFlag = "generate-name-prefix"
+	// OnNameCollisionFlag command-line flag.
+	OnNameCollisionFlag = "on-name-collision"
+)
+
+// Name collision strategies, applicable when an explicit BuildRun name is already taken.
+const (
+	// NameCollisionFail fails the command.
+	NameCollisionFail = "fail"
+	// NameCollisionGenerate generates a unique name using the informed name as prefix.
+	NameCollisionGenerate = "generate"
+)
+
+// BuildRunNaming controls the name of the BuildRun created, by default a unique name is generated
+// out of the Build name.
+type BuildRunNaming struct {
+	Name               string // explicit BuildRun name
+	GenerateNamePrefix string // prefix for the generated BuildRun name
+	OnCollision        string // strategy when the explicit name is taken
+}
+
+// BuildRunNamingFlags registers the flags controlling the name of the BuildRun created.
+func BuildRunNamingFlags(flags *pflag.FlagSet, naming *BuildRunNaming) {
+	flags.StringVar(
+		&naming.Name,
+		BuildRunNameFlag,
+		"",
+		"explicit name of the BuildRun created, instead of generating an unique name",
+	)
+	flags.StringVar(
+		&naming.GenerateNamePrefix,
+		GenerateNamePrefixFlag,
+		"",
+		"prefix of the generated BuildRun name, defaults to the Build name followed by a dash",
+	)
+	flags.StringVar(
+		&naming.OnCollision,
+		OnNameCollisionFlag,
+		NameCollisionFail,
+		fmt.Sprintf("action when the --%s is already taken, either %q, or %q to generate an unique name using it as prefix",
+			BuildRunNameFlag, NameCollisionFail, NameCollisionGenerate),
+	)
+}
+
+// Validate checks the naming flags are consistent, and the informed name is valid.
+func (n *BuildRunNaming) Validate() error {
+	if n == nil {
+		return nil
+	}
+	if n.Name != "" && n.GenerateNamePrefix != "" {
+		return fmt.Errorf("--%s and --%s are mutually exclusive", BuildRunNameFlag, GenerateNamePrefixFlag)
+	}
+	if n.Name != "" {
+		if errs := validation.IsDNS1123Subdomain(n.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid --%s %q: %v", BuildRunNameFlag, n.Name, errs)
+		}
+	}
+	switch n.OnCollision {
+	case "", NameCollisionFail, NameCollisionGenerate:
+	default:
+		return fmt.Errorf("unsupported --%s %q, expected either %q or %q",
+			OnNameCollisionFlag, n.OnCollision, NameCollisionFail, NameCollisionGenerate)
+	}
+	return nil
+}
+
+// ObjectMeta returns the BuildRun metadata carrying either the explicit name, or the prefix to
+// generate an unique name, which defaults to the Build name followed by a dash.
+func (n *BuildRunNaming) ObjectMeta(buildName string) metav1.ObjectMeta {
+	switch {
+	case n != nil && n.Name != "":
+		return metav1.ObjectMeta{Name: n.Name}
+	case n != nil && n.GenerateNamePrefix != "":
+		return metav1.ObjectMeta{GenerateName: n.GenerateNamePrefix}
+	default:
+		return metav1.ObjectMeta{GenerateName: fmt.Sprintf("%s-", buildName)}
+	}
+}
+
+// GenerateOnCollision tells whether an unique name should be generated when the explicit name is
+// already taken.
+func (n *BuildRunNaming) GenerateOnCollision() bool {
+	return n != nil && n.Name != "" && n.OnCollision == NameCollisionGenerate
+}
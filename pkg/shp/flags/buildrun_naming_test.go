@@ -0,0 +1,40 @@
+package flags
+
+import (
+	"testing"
+
+	o "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+)
+
+func TestBuildRunNaming(t *testing.T) {
+	g := o.NewWithT(t)
+
+	parse := func(args ...string) *BuildRunNaming {
+		cmd := &cobra.Command{}
+		naming := &BuildRunNaming{}
+		BuildRunNamingFlags(cmd.Flags(), naming)
+		g.Expect(cmd.ParseFlags(args)).To(o.Succeed())
+		return naming
+	}
+
+	naming := parse()
+	g.Expect(naming.Validate()).To(o.Succeed())
+	g.Expect(naming.ObjectMeta("my-app").GenerateName).To(o.Equal("my-app-"))
+	g.Expect(naming.GenerateOnCollision()).To(o.BeFalse())
+
+	naming = parse("--generate-name-prefix", "nightly-")
+	g.Expect(naming.Validate()).To(o.Succeed())
+	g.Expect(naming.ObjectMeta("my-app").GenerateName).To(o.Equal("nightly-"))
+
+	naming = parse("--buildrun-name", "release-1", "--on-name-collision", "generate")
+	g.Expect(naming.Validate()).To(o.Succeed())
+	meta := naming.ObjectMeta("my-app")
+	g.Expect(meta.Name).To(o.Equal("release-1"))
+	g.Expect(meta.GenerateName).To(o.BeEmpty())
+	g.Expect(naming.GenerateOnCollision()).To(o.BeTrue())
+
+	g.Expect(parse("--buildrun-name", "a", "--generate-name-prefix", "b-").Validate()).NotTo(o.Succeed())
+	g.Expect(parse("--buildrun-name", "Invalid_Name").Validate()).NotTo(o.Succeed())
+	g.Expect(parse("--on-name-collision", "retry").Validate()).NotTo(o.Succeed())
+}
@@ -0,0 +1,33 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+)
+
+const (
+	// CompressFlag command-line flag.
+	CompressFlag = "compress"
+	// CompressLevelFlag command-line flag.
+	CompressLevelFlag = "compress-level"
+)
+
+// CompressionFlags registers the flags to compress the local source uploaded, either streamed to
+// the build pod or pushed as a source bundle image.
+func CompressionFlags(flags *pflag.FlagSet, compression *string, level *int) {
+	flags.StringVar(
+		compression,
+		CompressFlag,
+		"",
+		fmt.Sprintf("compression of the local source uploaded, one of %v", streamer.Compressions),
+	)
+	flags.IntVar(
+		level,
+		CompressLevelFlag,
+		0,
+		"compression level, from 1 (fastest) to 9 for gzip and 22 for zstd, zero means the default level",
+	)
+}
@@ -12,10 +12,14 @@ type CoreEnvVarArrayValue struct {
 	envs *[]corev1.EnvVar // pointer to the slice of EnvVar
 }
 
-// String prints out the string representation of the slice of EnvVar objects.
+// String prints out the string representation of the slice of EnvVar objects, the entries
+// referencing Secrets or ConfigMaps are shown by their own flags.
 func (c *CoreEnvVarArrayValue) String() string {
 	slice := []string{}
 	for _, e := range *c.envs {
+		if e.ValueFrom != nil {
+			continue
+		}
 		slice = append(slice, fmt.Sprintf("%s=%s", e.Name, e.Value))
 	}
 	csv, _ := writeAsCSV(slice)
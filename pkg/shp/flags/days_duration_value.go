@@ -0,0 +1,56 @@
+package flags
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// day duration of the "d" unit, not accepted by time.ParseDuration.
+const day = 24 * time.Hour
+
+// DaysDurationValue implements pflag.Value interface, to represent a time.Duration which also
+// accepts whole days, i.e. "7d", on top of the units accepted by time.ParseDuration.
+type DaysDurationValue struct {
+	durationPtr *time.Duration
+}
+
+// String shows the duration, in days when it's a whole amount of them.
+func (d *DaysDurationValue) String() string {
+	if d.durationPtr == nil {
+		return ""
+	}
+	if *d.durationPtr > 0 && *d.durationPtr%day == 0 {
+		return fmt.Sprintf("%dd", *d.durationPtr/day)
+	}
+	return d.durationPtr.String()
+}
+
+// Set parses the informed value either as an amount of days or as a time.Duration.
+func (d *DaysDurationValue) Set(value string) error {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return fmt.Errorf("%q is an invalid amount of days", value)
+		}
+		*d.durationPtr = time.Duration(n) * day
+		return nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d.durationPtr = duration
+	return nil
+}
+
+// Type analogous to the pflag "duration".
+func (d *DaysDurationValue) Type() string {
+	return "duration"
+}
+
+// NewDaysDurationValue creates a new instance of DaysDurationValue sharing an existing reference.
+func NewDaysDurationValue(durationPtr *time.Duration) *DaysDurationValue {
+	return &DaysDurationValue{durationPtr: durationPtr}
+}
@@ -0,0 +1,26 @@
+package flags
+
+import (
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestDaysDurationValue(t *testing.T) {
+	g := o.NewWithT(t)
+
+	var duration time.Duration
+	v := NewDaysDurationValue(&duration)
+
+	g.Expect(v.Set("7d")).To(o.Succeed())
+	g.Expect(duration).To(o.Equal(7 * 24 * time.Hour))
+	g.Expect(v.String()).To(o.Equal("7d"))
+
+	g.Expect(v.Set("36h")).To(o.Succeed())
+	g.Expect(duration).To(o.Equal(36 * time.Hour))
+	g.Expect(v.String()).To(o.Equal("36h0m0s"))
+
+	g.Expect(v.Set("1.5d")).To(o.MatchError(`"1.5d" is an invalid amount of days`))
+	g.Expect(v.Set("week")).NotTo(o.Succeed())
+}
@@ -0,0 +1,80 @@
+package flags
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DockerfileFileFlag command-line flag.
+	DockerfileFileFlag = "dockerfile-file"
+	// DockerfileVolumeFlag command-line flag.
+	DockerfileVolumeFlag = "dockerfile-volume"
+)
+
+// DockerfileKey ConfigMap key holding the Dockerfile content, also the file name on the volume.
+const DockerfileKey = "Dockerfile"
+
+// defaultDockerfileVolume strategy volume receiving the Dockerfile ConfigMap.
+const defaultDockerfileVolume = "dockerfile"
+
+// DockerfileSource local Dockerfile, stored on a ConfigMap mounted on the build strategy volume,
+// for sources which don't carry the Dockerfile.
+type DockerfileSource struct {
+	File   string // local Dockerfile, "-" reads the standard input
+	Volume string // overridable strategy volume where the ConfigMap is mounted
+}
+
+// DockerfileSourceFlags registers the flags for the local Dockerfile.
+func DockerfileSourceFlags(flags *pflag.FlagSet, d *DockerfileSource) {
+	flags.StringVar(
+		&d.File,
+		DockerfileFileFlag,
+		"",
+		"local Dockerfile stored on a ConfigMap mounted on the build strategy, \"-\" reads the standard input",
+	)
+	flags.StringVar(
+		&d.Volume,
+		DockerfileVolumeFlag,
+		defaultDockerfileVolume,
+		"overridable build strategy volume where the Dockerfile ConfigMap is mounted",
+	)
+}
+
+// Informed tells whether a local Dockerfile has been informed.
+func (d *DockerfileSource) Informed() bool {
+	return d != nil && d.File != ""
+}
+
+// Read loads the Dockerfile content, either from the file or from the informed reader when the
+// file is "-", which allows passing the Dockerfile as a heredoc.
+func (d *DockerfileSource) Read(stdin io.Reader) ([]byte, error) {
+	var content []byte
+	var err error
+	if d.File == "-" {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(d.File)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the Dockerfile: %w", err)
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf("the Dockerfile %q is empty", d.File)
+	}
+	return content, nil
+}
+
+// ConfigMap instantiates the ConfigMap holding the Dockerfile content.
+func (d *DockerfileSource) ConfigMap(name, ns string, content []byte) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Data:       map[string]string{DockerfileKey: string(content)},
+	}
+}
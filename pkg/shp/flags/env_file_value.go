@@ -0,0 +1,137 @@
+package flags
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EnvFileValue implements pflag.Value interface, in order to read dotenv-style files into the same
+// corev1.EnvVar slice used by the "--env" flag, thus duplicated keys are reported regardless of
+// where they come from.
+type EnvFileValue struct {
+	envs  *[]corev1.EnvVar // pointer to the slice of EnvVar
+	files []string         // files read so far
+}
+
+// String prints out the files informed so far.
+func (e *EnvFileValue) String() string {
+	csv, _ := writeAsCSV(e.files)
+	return fmt.Sprintf("[%s]", csv)
+}
+
+// Set reads the informed dotenv file, appending its entries to the EnvVar slice.
+func (e *EnvFileValue) Set(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	entries, err := parseEnvFile(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, entry := range entries {
+		for _, existing := range *e.envs {
+			if entry.Name == existing.Name {
+				return fmt.Errorf("%s: environment variable '%s' is already set", path, entry.Name)
+			}
+		}
+		*e.envs = append(*e.envs, entry)
+	}
+	e.files = append(e.files, path)
+	return nil
+}
+
+// Type analogous to the pflag "stringArray" type, each flag entry is a file path.
+func (e *EnvFileValue) Type() string {
+	return "stringArray"
+}
+
+// NewEnvFileValue instantiate a EnvFileValue sharing the EnvVar pointer.
+func NewEnvFileValue(envs *[]corev1.EnvVar) *EnvFileValue {
+	return &EnvFileValue{envs: envs}
+}
+
+// parseEnvFile parses dotenv-style content, supporting comments, the optional "export" prefix,
+// and single or double quoted values, where only the later interpret escape sequences.
+func parseEnvFile(r io.Reader) ([]corev1.EnvVar, error) {
+	entries := []corev1.EnvVar{}
+	seen := map[string]int{}
+
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		k, v, err := splitKeyValue(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		k = strings.TrimSpace(k)
+		if strings.ContainsAny(k, " \t") {
+			return nil, fmt.Errorf("line %d: invalid key '%s'", n, k)
+		}
+		if v, err = parseEnvFileValue(strings.TrimSpace(v)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if previous, exists := seen[k]; exists {
+			return nil, fmt.Errorf("line %d: environment variable '%s' is already set on line %d", n, k, previous)
+		}
+		seen[k] = n
+		entries = append(entries, corev1.EnvVar{Name: k, Value: v})
+	}
+	return entries, sc.Err()
+}
+
+// parseEnvFileValue unquotes the informed value, or strips the inline comment of unquoted values.
+func parseEnvFileValue(v string) (string, error) {
+	switch {
+	case strings.HasPrefix(v, `"`):
+		var b strings.Builder
+		for i := 1; i < len(v); i++ {
+			switch c := v[i]; {
+			case c == '"':
+				if rest := strings.TrimSpace(v[i+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+					return "", fmt.Errorf("unexpected content after quoted value: '%s'", rest)
+				}
+				return b.String(), nil
+			case c == '\\' && i+1 < len(v):
+				i++
+				switch v[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(v[i])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated double quoted value")
+	case strings.HasPrefix(v, "'"):
+		end := strings.Index(v[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single quoted value")
+		}
+		if rest := strings.TrimSpace(v[end+2:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected content after quoted value: '%s'", rest)
+		}
+		return v[1 : end+1], nil
+	default:
+		if i := strings.Index(v, " #"); i >= 0 {
+			v = v[:i]
+		}
+		return strings.TrimSpace(v), nil
+	}
+}
@@ -0,0 +1,99 @@
+package flags
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected []corev1.EnvVar
+		wantErr  bool
+	}{{
+		"comments and blank lines are skipped",
+		"# comment\n\nA=b\n",
+		[]corev1.EnvVar{{Name: "A", Value: "b"}},
+		false,
+	}, {
+		"export prefix and inline comments",
+		"export A=b # comment\nB = c\n",
+		[]corev1.EnvVar{{Name: "A", Value: "b"}, {Name: "B", Value: "c"}},
+		false,
+	}, {
+		"double quoted values interpret escapes",
+		`A="b # c\n\"d\""`,
+		[]corev1.EnvVar{{Name: "A", Value: "b # c\n\"d\""}},
+		false,
+	}, {
+		"single quoted values are literal",
+		`A='b\n c' # comment`,
+		[]corev1.EnvVar{{Name: "A", Value: `b\n c`}},
+		false,
+	}, {
+		"empty value",
+		"A=",
+		[]corev1.EnvVar{{Name: "A", Value: ""}},
+		false,
+	}, {
+		"error on missing equal sign",
+		"A",
+		nil,
+		true,
+	}, {
+		"error on unterminated quotes",
+		`A="b`,
+		nil,
+		true,
+	}, {
+		"error on duplicated keys",
+		"A=b\nA=c",
+		nil,
+		true,
+	}}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			entries, err := parseEnvFile(strings.NewReader(tt.content))
+			if tt.wantErr {
+				g.Expect(err).NotTo(o.BeNil())
+				return
+			}
+			g.Expect(err).To(o.BeNil())
+			g.Expect(entries).To(o.Equal(tt.expected))
+		})
+	}
+}
+
+func TestEnvFileValue(t *testing.T) {
+	g := o.NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), ".env")
+	g.Expect(os.WriteFile(path, []byte("A=b\nC=d\n"), 0o600)).To(o.Succeed())
+
+	envs := []corev1.EnvVar{}
+	g.Expect(NewCoreEnvVarArrayValue(&envs).Set("E=f")).To(o.Succeed())
+
+	e := NewEnvFileValue(&envs)
+	g.Expect(e.Set(path)).To(o.Succeed())
+	g.Expect(envs).To(o.Equal([]corev1.EnvVar{
+		{Name: "E", Value: "f"},
+		{Name: "A", Value: "b"},
+		{Name: "C", Value: "d"},
+	}))
+
+	// keys informed via "--env" and the file are conflicting
+	err := NewCoreEnvVarArrayValue(&envs).Set("A=x")
+	g.Expect(err).NotTo(o.BeNil())
+	err = e.Set(path)
+	g.Expect(err).NotTo(o.BeNil())
+
+	err = e.Set(filepath.Join(t.TempDir(), "missing"))
+	g.Expect(err).NotTo(o.BeNil())
+}
@@ -0,0 +1,98 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Kinds of objects referenced by EnvSourceValue.
+const (
+	envSourceSecret    = "secret"
+	envSourceConfigMap = "configmap"
+)
+
+// EnvSourceValue implements pflag.Value interface, in order to store corev1.EnvVar entries whose
+// value comes from a key of a Secret or ConfigMap, sharing the slice used by the "--env" flag,
+// thus duplicated keys are reported regardless of where they come from.
+type EnvSourceValue struct {
+	envs *[]corev1.EnvVar // pointer to the slice of EnvVar
+	kind string           // kind of object referenced, secret or configmap
+}
+
+// String prints out the entries referencing the object kind, as "NAME=object/key".
+func (e *EnvSourceValue) String() string {
+	slice := []string{}
+	for _, env := range *e.envs {
+		name, key, ok := e.refOf(env)
+		if ok {
+			slice = append(slice, fmt.Sprintf("%s=%s/%s", env.Name, name, key))
+		}
+	}
+	csv, _ := writeAsCSV(slice)
+	return fmt.Sprintf("[%s]", csv)
+}
+
+// refOf returns the object name and key referenced by the EnvVar, when it references the kind.
+func (e *EnvSourceValue) refOf(env corev1.EnvVar) (string, string, bool) {
+	switch {
+	case env.ValueFrom == nil:
+		return "", "", false
+	case e.kind == envSourceSecret && env.ValueFrom.SecretKeyRef != nil:
+		return env.ValueFrom.SecretKeyRef.Name, env.ValueFrom.SecretKeyRef.Key, true
+	case e.kind == envSourceConfigMap && env.ValueFrom.ConfigMapKeyRef != nil:
+		return env.ValueFrom.ConfigMapKeyRef.Name, env.ValueFrom.ConfigMapKeyRef.Key, true
+	default:
+		return "", "", false
+	}
+}
+
+// Set receives an entry in the "NAME=object/key" format, where the object is the Secret or
+// ConfigMap name.
+func (e *EnvSourceValue) Set(value string) error {
+	k, ref, err := splitKeyValue(value)
+	if err != nil {
+		return err
+	}
+	name, key, found := strings.Cut(ref, "/")
+	if !found || name == "" || key == "" {
+		return fmt.Errorf("informed value '%s' is not in NAME=%s/key format", value, e.kind)
+	}
+	for _, env := range *e.envs {
+		if k == env.Name {
+			return fmt.Errorf("environment variable '%s' is already set", k)
+		}
+	}
+
+	source := &corev1.EnvVarSource{}
+	if e.kind == envSourceSecret {
+		source.SecretKeyRef = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}
+	} else {
+		source.ConfigMapKeyRef = &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}
+	}
+	*e.envs = append(*e.envs, corev1.EnvVar{Name: k, ValueFrom: source})
+	return nil
+}
+
+// Type analogous to the pflag "stringArray" type, each flag entry is a single EnvVar.
+func (e *EnvSourceValue) Type() string {
+	return "stringArray"
+}
+
+// NewEnvSecretValue instantiate a EnvSourceValue referencing Secrets, sharing the EnvVar pointer.
+func NewEnvSecretValue(envs *[]corev1.EnvVar) *EnvSourceValue {
+	return &EnvSourceValue{envs: envs, kind: envSourceSecret}
+}
+
+// NewEnvConfigMapValue instantiate a EnvSourceValue referencing ConfigMaps, sharing the EnvVar
+// pointer.
+func NewEnvConfigMapValue(envs *[]corev1.EnvVar) *EnvSourceValue {
+	return &EnvSourceValue{envs: envs, kind: envSourceConfigMap}
+}
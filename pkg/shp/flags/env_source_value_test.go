@@ -0,0 +1,42 @@
+package flags
+
+import (
+	"testing"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestEnvSourceValue(t *testing.T) {
+	g := o.NewWithT(t)
+
+	envs := []corev1.EnvVar{}
+	literal := NewCoreEnvVarArrayValue(&envs)
+	secret := NewEnvSecretValue(&envs)
+	configMap := NewEnvConfigMapValue(&envs)
+
+	g.Expect(literal.Set("a=b")).To(o.Succeed())
+	g.Expect(secret.Set("TOKEN=credentials/token")).To(o.Succeed())
+	g.Expect(configMap.Set("LOG_LEVEL=settings/log.level")).To(o.Succeed())
+	g.Expect(envs).To(o.HaveLen(3))
+
+	g.Expect(envs[1].Value).To(o.BeEmpty())
+	g.Expect(envs[1].ValueFrom.SecretKeyRef.Name).To(o.Equal("credentials"))
+	g.Expect(envs[1].ValueFrom.SecretKeyRef.Key).To(o.Equal("token"))
+	g.Expect(envs[2].ValueFrom.ConfigMapKeyRef.Name).To(o.Equal("settings"))
+	g.Expect(envs[2].ValueFrom.ConfigMapKeyRef.Key).To(o.Equal("log.level"))
+
+	// the entries are shown by the flag that informed them
+	g.Expect(literal.String()).To(o.Equal("[a=b]"))
+	g.Expect(secret.String()).To(o.Equal("[TOKEN=credentials/token]"))
+	g.Expect(configMap.String()).To(o.Equal("[LOG_LEVEL=settings/log.level]"))
+
+	// the object name and key are required
+	g.Expect(secret.Set("TOKEN2=credentials")).To(o.MatchError("informed value 'TOKEN2=credentials' is not in NAME=secret/key format"))
+	g.Expect(configMap.Set("LEVEL=/key")).NotTo(o.Succeed())
+	g.Expect(secret.Set("token")).NotTo(o.Succeed())
+
+	// names are unique regardless of the flag
+	g.Expect(secret.Set("a=credentials/a")).To(o.MatchError("environment variable 'a' is already set"))
+	g.Expect(literal.Set("TOKEN=value")).NotTo(o.Succeed())
+}
@@ -0,0 +1,20 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// FilenameFlag command-line flag.
+const FilenameFlag = "filename"
+
+// FilenamesFlags registers the flag reading the manifests from files, directories, URLs or the
+// standard input, recording the values on the informed slice.
+func FilenamesFlags(flags *pflag.FlagSet, filenames *[]string) {
+	flags.StringArrayVarP(
+		filenames,
+		FilenameFlag,
+		"f",
+		[]string{},
+		"file, directory or URL of the manifests, use \"-\" to read from stdin, can be repeated",
+	)
+}
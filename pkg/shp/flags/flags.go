@@ -16,12 +16,24 @@ const (
 	BuildrefNameFlag = "buildref-name"
 	// BuilderImageFlag command-line flag.
 	BuilderImageFlag = "builder-image"
+	// BuilderInsecureFlag command-line flag.
+	BuilderInsecureFlag = "builder-insecure"
 	// BuilderCredentialsSecretFlag command-line flag.
 	BuilderCredentialsSecretFlag = "builder-credentials-secret"
 	// DockerfileFlag command-line flag.
 	DockerfileFlag = "dockerfile"
 	// EnvFlag command-line flag.
 	EnvFlag = "env"
+	// EnvFileFlag command-line flag.
+	EnvFileFlag = "env-file"
+	// EnvSecretFlag command-line flag.
+	EnvSecretFlag = "env-secret"
+	// EnvConfigMapFlag command-line flag.
+	EnvConfigMapFlag = "env-configmap"
+	// ParamFlag command-line flag.
+	ParamFlag = "param"
+	// ParamsFromJSONFlag command-line flag.
+	ParamsFromJSONFlag = "params-from-json"
 	// SourceURLFlag command-line flag.
 	SourceURLFlag = "source-url"
 	// SourceRevisionFlag command-line flag.
@@ -42,7 +54,7 @@ const (
 	StrategyNameFlag = "strategy-name"
 	// OutputImageFlag command-line flag.
 	OutputImageFlag = "output-image"
-	// OutputInsecure command-line flag.
+	// OutputInsecureFlag command-line flag.
 	OutputInsecureFlag = "output-insecure"
 	// OutputCredentialsSecretFlag command-line flag.
 	OutputCredentialsSecretFlag = "output-credentials-secret" // #nosec G101
@@ -126,7 +138,9 @@ func strategyFlags(flags *pflag.FlagSet, strategy *buildv1alpha1.Strategy) {
 	)
 }
 
-// imageFlags flags for Shipwright's Image definition, using a prefix to avoid duplicated flags.
+// imageFlags flags for Shipwright's Image definition, using a prefix to avoid duplicated flags. The
+// insecure flag is only registered when the image carries the Insecure attribute, since the Image
+// API has no finer grained TLS verification settings, it covers plain HTTP and self-signed registries.
 func imageFlags(flags *pflag.FlagSet, prefix string, image *buildv1alpha1.Image) {
 	flags.StringVar(
 		&image.Image,
@@ -140,12 +154,12 @@ func imageFlags(flags *pflag.FlagSet, prefix string, image *buildv1alpha1.Image)
 		"",
 		"name of the secret with builder-image pull credentials",
 	)
-	if prefix == "output" {
+	if image.Insecure != nil {
 		flags.BoolVar(
 			image.Insecure,
 			fmt.Sprintf("%s-insecure", prefix),
 			false,
-			"flag to indicate an insecure container registry",
+			fmt.Sprintf("flag to indicate an insecure %s-image container registry, either plain HTTP or with a self-signed certificate", prefix),
 		)
 	}
 }
@@ -198,18 +212,49 @@ func serviceAccountFlags(flags *pflag.FlagSet, sa *buildv1alpha1.ServiceAccount)
 		sa.Generate,
 		ServiceAccountGenerateFlag,
 		false,
-		"generate a Kubernetes service-account for the build",
+		"generate a Kubernetes service-account for the build, the leftovers are removed by \"shp cleanup serviceaccounts\"",
 	)
 }
 
-// envFlags registers flags for adding corev1.EnvVars.
+// envFlags registers flags for adding corev1.EnvVars, directly, from dotenv files, or referencing
+// Secret and ConfigMap keys.
 func envFlags(flags *pflag.FlagSet, envs *[]corev1.EnvVar) {
 	flags.VarP(
 		NewCoreEnvVarArrayValue(envs),
-		"env",
+		EnvFlag,
 		"e",
 		"specify a key-value pair for an environment variable to set for the build container",
 	)
+	flags.Var(
+		NewEnvFileValue(envs),
+		EnvFileFlag,
+		"specify a dotenv file with environment variables to set for the build container",
+	)
+	flags.Var(
+		NewEnvSecretValue(envs),
+		EnvSecretFlag,
+		"specify an environment variable for the build container from a secret key, as NAME=secret/key",
+	)
+	flags.Var(
+		NewEnvConfigMapValue(envs),
+		EnvConfigMapFlag,
+		"specify an environment variable for the build container from a configmap key, as NAME=configmap/key",
+	)
+}
+
+// paramFlags registers flags for the strategy parameter values, single values, arrays, or the API's
+// JSON encoding.
+func paramFlags(flags *pflag.FlagSet, params *[]buildv1alpha1.ParamValue) {
+	flags.Var(
+		NewParamValueArrayValue(params),
+		ParamFlag,
+		"specify a strategy parameter value, as key=value, key=[a,b,c] for arrays, or key+=value appending an array item",
+	)
+	flags.Var(
+		NewParamValuesJSONValue(params),
+		ParamsFromJSONFlag,
+		"specify strategy parameter values as the JSON list of paramValues, or @file with it, covering secret and configmap values",
+	)
 }
 
 // imageLabelsFlags registers flags for output image labels.
@@ -1,9 +1,24 @@
 package flags
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
+const (
+	// MaxLogRateFlag command-line flag.
+	MaxLogRateFlag = "max-log-rate"
+	// HeartbeatIntervalFlag command-line flag.
+	HeartbeatIntervalFlag = "heartbeat-interval"
+)
+
+// DefaultHeartbeatInterval quiet period before the step still running is reported.
+const DefaultHeartbeatInterval = time.Minute
+
 // FollowFlag register the (log) follow flag, recording the value on the informed boolean pointer.
 func FollowFlag(flags *pflag.FlagSet, follow *bool) {
 	flags.BoolVarP(
@@ -14,3 +29,55 @@ func FollowFlag(flags *pflag.FlagSet, follow *bool) {
 		"Start a build and watch its log until it completes or fails.",
 	)
 }
+
+// MaxLogRateFlags registers the flag limiting the amount of log bytes per second printed while
+// following, recording the value on the informed pointer, zero means no limit.
+func MaxLogRateFlags(flags *pflag.FlagSet, rate *int64) {
+	flags.Var(
+		&byteRateValue{ref: rate},
+		MaxLogRateFlag,
+		"maximum amount of log bytes per second printed while following, e.g. 512Ki, the lines exceeding it are skipped",
+	)
+}
+
+// HeartbeatIntervalFlags registers the flag controlling how long the output may be quiet while
+// following, before the step still running is reported, recording the value on the informed pointer.
+func HeartbeatIntervalFlags(flags *pflag.FlagSet, interval *time.Duration) {
+	flags.DurationVar(
+		interval,
+		HeartbeatIntervalFlag,
+		DefaultHeartbeatInterval,
+		"report the step still running when no logs are printed for the interval while following, only when the output is not a terminal, zero disables it",
+	)
+}
+
+// byteRateValue amount of bytes per second, informed as a Kubernetes quantity like "512Ki".
+type byteRateValue struct {
+	ref *int64
+}
+
+// String renders the amount of bytes, empty when there is no limit.
+func (b *byteRateValue) String() string {
+	if b.ref == nil || *b.ref == 0 {
+		return ""
+	}
+	return resource.NewQuantity(*b.ref, resource.BinarySI).String()
+}
+
+// Set parses the quantity, which must not be negative.
+func (b *byteRateValue) Set(s string) error {
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return fmt.Errorf("expected an amount of bytes like \"512Ki\": %w", err)
+	}
+	if q.Sign() < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	*b.ref = q.Value()
+	return nil
+}
+
+// Type analogous to the pflag "string" type, the value is a quantity.
+func (b *byteRateValue) Type() string {
+	return "quantity"
+}
@@ -0,0 +1,43 @@
+package flags
+
+import (
+	"testing"
+
+	o "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+)
+
+func TestMaxLogRateFlags(t *testing.T) {
+	g := o.NewWithT(t)
+
+	parse := func(args ...string) (int64, error) {
+		cmd := &cobra.Command{}
+		var rate int64
+		MaxLogRateFlags(cmd.Flags(), &rate)
+		err := cmd.ParseFlags(args)
+		return rate, err
+	}
+
+	rate, err := parse()
+	g.Expect(err).To(o.BeNil())
+	g.Expect(rate).To(o.BeZero())
+
+	rate, err = parse("--max-log-rate=512Ki")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(rate).To(o.Equal(int64(512 * 1024)))
+
+	rate, err = parse("--max-log-rate=1000")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(rate).To(o.Equal(int64(1000)))
+
+	_, err = parse("--max-log-rate=fast")
+	g.Expect(err).To(o.MatchError(o.ContainSubstring("expected an amount of bytes")))
+
+	_, err = parse("--max-log-rate=-1Ki")
+	g.Expect(err).To(o.MatchError(o.ContainSubstring("must not be negative")))
+
+	rate = 0
+	g.Expect((&byteRateValue{ref: &rate}).String()).To(o.Equal(""))
+	rate = 512 * 1024
+	g.Expect((&byteRateValue{ref: &rate}).String()).To(o.Equal("512Ki"))
+}
@@ -0,0 +1,19 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// OutputImageStreamFlag command-line flag.
+const OutputImageStreamFlag = "output-imagestream"
+
+// OutputImageStreamFlags registers the flag to push the output image to an OpenShift ImageStream,
+// recording the "name[:tag]" reference on the informed string pointer.
+func OutputImageStreamFlags(flags *pflag.FlagSet, imageStream *string) {
+	flags.StringVar(
+		imageStream,
+		OutputImageStreamFlag,
+		"",
+		"OpenShift ImageStream receiving the output image on the internal registry, as name[:tag]",
+	)
+}
@@ -0,0 +1,19 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// LocalPortForwardFlag command-line flag.
+const LocalPortForwardFlag = "local-port-forward"
+
+// LocalPortForwardFlags registers the flag to forward local ports to the build pod while following
+// its logs, recording the port mappings on the informed slice.
+func LocalPortForwardFlags(flags *pflag.FlagSet, ports *[]string) {
+	flags.StringArrayVar(
+		ports,
+		LocalPortForwardFlag,
+		[]string{},
+		"forward a local port to the build pod once it's running, as [LOCAL:]REMOTE, e.g. 5005:5005 to attach a debugger",
+	)
+}
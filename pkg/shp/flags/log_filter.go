@@ -0,0 +1,82 @@
+package flags
+
+import (
+	"regexp"
+
+	"github.com/spf13/pflag"
+
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+)
+
+const (
+	// GrepFlag command-line flag.
+	GrepFlag = "grep"
+	// StepFlag command-line flag.
+	StepFlag = "step"
+)
+
+// LogFilter selection of the log lines printed.
+type LogFilter struct {
+	Grep  *regexp.Regexp // only the lines matching it
+	Steps []string       // only the lines of the build strategy steps
+}
+
+// LogFilterFlags registers the flags selecting the log lines printed.
+func LogFilterFlags(flags *pflag.FlagSet, f *LogFilter) {
+	flags.Var(
+		&regexpValue{ref: &f.Grep},
+		GrepFlag,
+		"only print the log lines matching the regular expression, e.g. \"(?i)error\"",
+	)
+	flags.StringSliceVar(
+		&f.Steps,
+		StepFlag,
+		[]string{},
+		"only print the log lines of the build strategy step, e.g. \"build-and-push\", can be repeated",
+	)
+}
+
+// IsEmpty tells whether no selection has been informed.
+func (f *LogFilter) IsEmpty() bool {
+	return f.Grep == nil && len(f.Steps) == 0
+}
+
+// LineFilter returns the filter selecting the log lines, nil when all lines are printed.
+func (f *LogFilter) LineFilter() tail.LineFilter {
+	var grep, steps tail.LineFilter
+	if f.Grep != nil {
+		grep = tail.MatchFilter(f.Grep)
+	}
+	if len(f.Steps) > 0 {
+		steps = tail.StepFilter(f.Steps)
+	}
+	return tail.Filters(grep, steps)
+}
+
+// regexpValue regular expression, compiled when the flag is parsed.
+type regexpValue struct {
+	ref **regexp.Regexp
+}
+
+// String renders the regular expression, empty when not informed.
+func (r *regexpValue) String() string {
+	if r.ref == nil || *r.ref == nil {
+		return ""
+	}
+	return (*r.ref).String()
+}
+
+// Set compiles the regular expression.
+func (r *regexpValue) Set(s string) error {
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return err
+	}
+	*r.ref = re
+	return nil
+}
+
+// Type analogous to the pflag "string" type, the value is a regular expression.
+func (r *regexpValue) Type() string {
+	return "regexp"
+}
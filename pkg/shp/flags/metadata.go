@@ -0,0 +1,147 @@
+package flags
+
+import (
+	"fmt"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// LabelFlag command-line flag.
+	LabelFlag = "label"
+	// AnnotationFlag command-line flag.
+	AnnotationFlag = "annotation"
+	// OwnedByBuildFlag command-line flag.
+	OwnedByBuildFlag = "owned-by-build"
+)
+
+// reservedLabels labels managed by the build controller, which can't be informed by the user.
+var reservedLabels = []string{
+	buildv1alpha1.LabelBuild,
+	buildv1alpha1.LabelBuildGeneration,
+	buildv1alpha1.LabelBuildRun,
+}
+
+// ObjectMetadata labels, annotations and ownership informed for the object created, as opposed to
+// the output image labels and annotations.
+type ObjectMetadata struct {
+	Labels       map[string]string // object labels
+	Annotations  map[string]string // object annotations
+	PodLabels    map[string]string // object labels meant for the build pod
+	OwnedByBuild bool              // set the Build as owner of the object
+}
+
+// ObjectMetadataFlags registers the flags for the labels, annotations and ownership of the BuildRun
+// created.
+func ObjectMetadataFlags(flags *pflag.FlagSet, metadata *ObjectMetadata) {
+	if metadata.Labels == nil {
+		metadata.Labels = map[string]string{}
+	}
+	if metadata.Annotations == nil {
+		metadata.Annotations = map[string]string{}
+	}
+	if metadata.PodLabels == nil {
+		metadata.PodLabels = map[string]string{}
+	}
+	flags.Var(
+		NewMapValue(metadata.Labels),
+		LabelFlag,
+		"specify a set of key-value pairs that correspond to labels to set on the BuildRun",
+	)
+	flags.Var(
+		NewMapValue(metadata.Annotations),
+		AnnotationFlag,
+		"specify a set of key-value pairs that correspond to annotations to set on the BuildRun",
+	)
+	flags.BoolVar(
+		&metadata.OwnedByBuild,
+		OwnedByBuildFlag,
+		false,
+		"set the Build as owner of the BuildRun, thus the BuildRun is garbage collected with the Build",
+	)
+	PodLabelsFlags(flags, metadata.PodLabels, "BuildRun")
+}
+
+// Validate checks the labels and annotations are valid, and the labels managed by the build
+// controller are not informed.
+func (m *ObjectMetadata) Validate() error {
+	if m == nil {
+		return nil
+	}
+	if err := ValidateLabels(m.Labels); err != nil {
+		return err
+	}
+	if err := ValidateLabels(m.PodLabels); err != nil {
+		return err
+	}
+	for k, v := range m.PodLabels {
+		if label, ok := m.Labels[k]; ok && label != v {
+			return fmt.Errorf("label %q is informed on both --%s and --%s with different values", k, LabelFlag, PodLabelFlag)
+		}
+	}
+	for k := range m.Annotations {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation key %q: %v", k, errs)
+		}
+	}
+	return nil
+}
+
+// ValidateLabels checks the labels are valid, and the labels managed by the build controller are
+// not informed.
+func ValidateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		for _, reserved := range reservedLabels {
+			if k == reserved {
+				return fmt.Errorf("label %q is managed by the build controller", k)
+			}
+		}
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %v", k, errs)
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid label value %q: %v", v, errs)
+		}
+	}
+	return nil
+}
+
+// RequiresOwner tells whether the Build must be informed to Apply, in order to own the object.
+func (m *ObjectMetadata) RequiresOwner() bool {
+	return m != nil && m.OwnedByBuild
+}
+
+// Apply merges the labels, including the pod labels, and annotations into the informed object
+// metadata, and when requested, adds the Build as owner.
+func (m *ObjectMetadata) Apply(meta *metav1.ObjectMeta, b *buildv1alpha1.Build) {
+	if m == nil {
+		return
+	}
+	if len(m.Labels)+len(m.PodLabels) > 0 && meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	for k, v := range m.Labels {
+		meta.Labels[k] = v
+	}
+	for k, v := range m.PodLabels {
+		meta.Labels[k] = v
+	}
+	if len(m.Annotations) > 0 && meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	for k, v := range m.Annotations {
+		meta.Annotations[k] = v
+	}
+	if m.OwnedByBuild && b != nil {
+		meta.OwnerReferences = append(meta.OwnerReferences, metav1.OwnerReference{
+			APIVersion: buildv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Build",
+			Name:       b.GetName(),
+			UID:        b.GetUID(),
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package flags
+
+import (
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestObjectMetadata(t *testing.T) {
+	g := o.NewWithT(t)
+
+	parse := func(args ...string) *ObjectMetadata {
+		cmd := &cobra.Command{}
+		metadata := &ObjectMetadata{}
+		ObjectMetadataFlags(cmd.Flags(), metadata)
+		g.Expect(cmd.ParseFlags(args)).To(o.Succeed())
+		return metadata
+	}
+
+	metadata := parse("--label", "team=platform", "--annotation", "ci.example.com/job=https://ci/1", "--owned-by-build")
+	g.Expect(metadata.Validate()).To(o.Succeed())
+
+	meta := metav1.ObjectMeta{Labels: map[string]string{"existing": "label"}}
+	b := &buildv1alpha1.Build{ObjectMeta: metav1.ObjectMeta{Name: "my-app", UID: types.UID("uid")}}
+	metadata.Apply(&meta, b)
+	g.Expect(meta.Labels).To(o.Equal(map[string]string{"existing": "label", "team": "platform"}))
+	g.Expect(meta.Annotations).To(o.Equal(map[string]string{"ci.example.com/job": "https://ci/1"}))
+	g.Expect(meta.OwnerReferences).To(o.Equal([]metav1.OwnerReference{{
+		APIVersion: "shipwright.io/v1alpha1",
+		Kind:       "Build",
+		Name:       "my-app",
+		UID:        types.UID("uid"),
+	}}))
+
+	g.Expect(parse("--label", buildv1alpha1.LabelBuild+"=other").Validate()).NotTo(o.Succeed())
+	g.Expect(parse("--label", "team=not valid").Validate()).NotTo(o.Succeed())
+	g.Expect(parse("--annotation", "not valid=x").Validate()).NotTo(o.Succeed())
+
+	// the pod labels are set on the object, which the build controller propagates to the pod
+	metadata = parse("--label", "team=platform", "--pod-label", "sidecar.istio.io/inject=false")
+	g.Expect(metadata.Validate()).To(o.Succeed())
+	meta = metav1.ObjectMeta{}
+	metadata.Apply(&meta, nil)
+	g.Expect(meta.Labels).To(o.Equal(map[string]string{"team": "platform", "sidecar.istio.io/inject": "false"}))
+
+	g.Expect(parse("--label", "team=a", "--pod-label", "team=b").Validate()).
+		To(o.MatchError(`label "team" is informed on both --label and --pod-label with different values`))
+	g.Expect(parse("--pod-label", buildv1alpha1.LabelBuildRun+"=other").Validate()).NotTo(o.Succeed())
+}
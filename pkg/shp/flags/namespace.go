@@ -0,0 +1,19 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// CreateNamespaceFlag command-line flag.
+const CreateNamespaceFlag = "create-namespace"
+
+// CreateNamespaceFlags registers the flag to create the target namespace when absent, recording the
+// value on the informed boolean pointer.
+func CreateNamespaceFlags(flags *pflag.FlagSet, createNamespace *bool) {
+	flags.BoolVar(
+		createNamespace,
+		CreateNamespaceFlag,
+		false,
+		"create the target namespace, when it does not exist yet",
+	)
+}
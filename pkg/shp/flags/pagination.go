@@ -0,0 +1,37 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	// LimitFlag command-line flag.
+	LimitFlag = "limit"
+	// ContinueFlag command-line flag.
+	ContinueFlag = "continue"
+	// ChunkSizeFlag command-line flag.
+	ChunkSizeFlag = "chunk-size"
+)
+
+// PaginationFlags registers the flags controlling how lists are paginated, recording the maximum
+// amount of items, the token to continue a previous list and the page size on the informed pointers.
+func PaginationFlags(flags *pflag.FlagSet, limit *int64, continueToken *string, chunkSize *int64) {
+	flags.Int64Var(
+		limit,
+		LimitFlag,
+		*limit,
+		"maximum amount of items listed, the token to list the remaining ones is shown when there are more, zero means all",
+	)
+	flags.StringVar(
+		continueToken,
+		ContinueFlag,
+		*continueToken,
+		"continue a previous list limited by --limit, using the token it has shown",
+	)
+	flags.Int64Var(
+		chunkSize,
+		ChunkSizeFlag,
+		*chunkSize,
+		"amount of items requested per page, the rows are shown as each page arrives, zero disables pagination",
+	)
+}
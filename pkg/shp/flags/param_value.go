@@ -0,0 +1,176 @@
+package flags
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+)
+
+// ParamValueArrayValue implements pflag.Value interface, in order to store the strategy parameter
+// values, either single values or arrays, used on Shipwright's BuildSpec and BuildRunSpec.
+type ParamValueArrayValue struct {
+	params *[]buildv1alpha1.ParamValue // pointer to the slice of ParamValue
+}
+
+// String prints out the inline parameter values, as "key=value" or "key=[a,b]", the values
+// referencing Secrets or ConfigMaps are left out.
+func (p *ParamValueArrayValue) String() string {
+	slice := []string{}
+	for _, pv := range *p.params {
+		switch {
+		case pv.SingleValue != nil && pv.SingleValue.Value != nil:
+			slice = append(slice, fmt.Sprintf("%s=%s", pv.Name, *pv.SingleValue.Value))
+		case pv.SingleValue == nil:
+			values := []string{}
+			for _, v := range pv.Values {
+				if v.Value != nil {
+					values = append(values, *v.Value)
+				}
+			}
+			csv, _ := writeAsCSV(values)
+			slice = append(slice, fmt.Sprintf("%s=[%s]", pv.Name, csv))
+		}
+	}
+	csv, _ := writeAsCSV(slice)
+	return fmt.Sprintf("[%s]", csv)
+}
+
+// Set receives either "key=value" for a single value, "key=[a,b,c]" for an array, where the items
+// are comma separated and may be double quoted, or "key+=value" appending an item to the array.
+func (p *ParamValueArrayValue) Set(value string) error {
+	k, v, err := splitKeyValue(value)
+	if err != nil {
+		return err
+	}
+	if name, appending := strings.CutSuffix(k, "+"); appending {
+		if name == "" {
+			return fmt.Errorf("informed value '%s' is not in key+=value format", value)
+		}
+		return p.appendItem(name, v)
+	}
+
+	for _, pv := range *p.params {
+		if k == pv.Name {
+			return fmt.Errorf("parameter '%s' is already set", k)
+		}
+	}
+	pv := buildv1alpha1.ParamValue{Name: k}
+	if strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
+		if pv.Values, err = parseArrayItems(strings.TrimSuffix(strings.TrimPrefix(v, "["), "]")); err != nil {
+			return fmt.Errorf("parameter '%s': %w", k, err)
+		}
+	} else {
+		pv.SingleValue = &buildv1alpha1.SingleValue{Value: &v}
+	}
+	*p.params = append(*p.params, pv)
+	return nil
+}
+
+// appendItem appends the item to the array parameter, created when not set yet.
+func (p *ParamValueArrayValue) appendItem(name, item string) error {
+	for i := range *p.params {
+		pv := &(*p.params)[i]
+		if pv.Name != name {
+			continue
+		}
+		if pv.SingleValue != nil {
+			return fmt.Errorf("parameter '%s' is set to a single value, items can't be appended to it", name)
+		}
+		pv.Values = append(pv.Values, buildv1alpha1.SingleValue{Value: &item})
+		return nil
+	}
+	*p.params = append(*p.params, buildv1alpha1.ParamValue{
+		Name:   name,
+		Values: []buildv1alpha1.SingleValue{{Value: &item}},
+	})
+	return nil
+}
+
+// parseArrayItems splits the comma separated items, double quotes keep commas as part of an item.
+func parseArrayItems(items string) ([]buildv1alpha1.SingleValue, error) {
+	values := []buildv1alpha1.SingleValue{}
+	if strings.TrimSpace(items) == "" {
+		return values, nil
+	}
+	r := csv.NewReader(strings.NewReader(items))
+	r.TrimLeadingSpace = true
+	record, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid array items '%s': %w", items, err)
+	}
+	for i := range record {
+		values = append(values, buildv1alpha1.SingleValue{Value: &record[i]})
+	}
+	return values, nil
+}
+
+// Type analogous to the pflag "stringArray" type, where each flag entry will be tranlated to a
+// single parameter, therefore the comma (",") is accepted as part of single values.
+func (p *ParamValueArrayValue) Type() string {
+	return "stringArray"
+}
+
+// NewParamValueArrayValue instantiate a ParamValueArrayValue sharing the ParamValue pointer.
+func NewParamValueArrayValue(params *[]buildv1alpha1.ParamValue) *ParamValueArrayValue {
+	return &ParamValueArrayValue{params: params}
+}
+
+// ParamValuesJSONValue implements pflag.Value interface, in order to read parameter values in the
+// JSON encoding of the API's "paramValues", covering the values referencing Secret or ConfigMap
+// keys, into the same slice used by the "--param" flag.
+type ParamValuesJSONValue struct {
+	params *[]buildv1alpha1.ParamValue // pointer to the slice of ParamValue
+	inputs []string                    // inputs informed so far
+}
+
+// String prints out the inputs informed so far.
+func (p *ParamValuesJSONValue) String() string {
+	csv, _ := writeAsCSV(p.inputs)
+	return fmt.Sprintf("[%s]", csv)
+}
+
+// Set receives a JSON list of parameter values, or "@" followed by the path of a file with it.
+func (p *ParamValuesJSONValue) Set(value string) error {
+	data := []byte(value)
+	if path, found := strings.CutPrefix(value, "@"); found {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var params []buildv1alpha1.ParamValue
+	if err := decoder.Decode(&params); err != nil {
+		return fmt.Errorf("invalid parameter values JSON: %w", err)
+	}
+	for i, pv := range params {
+		if pv.Name == "" {
+			return fmt.Errorf("parameter value %d has no name", i)
+		}
+		for _, existing := range *p.params {
+			if pv.Name == existing.Name {
+				return fmt.Errorf("parameter '%s' is already set", pv.Name)
+			}
+		}
+		*p.params = append(*p.params, pv)
+	}
+	p.inputs = append(p.inputs, value)
+	return nil
+}
+
+// Type analogous to the pflag "stringArray" type, each flag entry is a JSON document or file.
+func (p *ParamValuesJSONValue) Type() string {
+	return "stringArray"
+}
+
+// NewParamValuesJSONValue instantiate a ParamValuesJSONValue sharing the ParamValue pointer.
+func NewParamValuesJSONValue(params *[]buildv1alpha1.ParamValue) *ParamValuesJSONValue {
+	return &ParamValuesJSONValue{params: params}
+}
@@ -0,0 +1,91 @@
+package flags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	o "github.com/onsi/gomega"
+)
+
+// valuesOf returns the inline values of the array parameter.
+func valuesOf(pv buildv1alpha1.ParamValue) []string {
+	values := []string{}
+	for _, v := range pv.Values {
+		values = append(values, *v.Value)
+	}
+	return values
+}
+
+func TestParamValueArrayValue(t *testing.T) {
+	g := o.NewWithT(t)
+
+	spec := &buildv1alpha1.BuildSpec{}
+	p := NewParamValueArrayValue(&spec.ParamValues)
+
+	// expect error when key-value is not split by equal sign
+	g.Expect(p.Set("a")).NotTo(o.Succeed())
+	g.Expect(p.Set("+=a")).NotTo(o.Succeed())
+
+	// setting a single value, commas are part of it
+	g.Expect(p.Set("cache=a,b")).To(o.Succeed())
+	g.Expect(spec.ParamValues).To(o.HaveLen(1))
+	g.Expect(spec.ParamValues[0].Name).To(o.Equal("cache"))
+	g.Expect(*spec.ParamValues[0].SingleValue.Value).To(o.Equal("a,b"))
+
+	// setting an array, quoted items keep the commas
+	g.Expect(p.Set(`build-args=[A=1, "B=2,3"]`)).To(o.Succeed())
+	g.Expect(spec.ParamValues).To(o.HaveLen(2))
+	g.Expect(spec.ParamValues[1].SingleValue).To(o.BeNil())
+	g.Expect(valuesOf(spec.ParamValues[1])).To(o.Equal([]string{"A=1", "B=2,3"}))
+
+	// appending items to the existing array, and to a new one
+	g.Expect(p.Set("build-args+=C=4")).To(o.Succeed())
+	g.Expect(valuesOf(spec.ParamValues[1])).To(o.Equal([]string{"A=1", "B=2,3", "C=4"}))
+	g.Expect(p.Set("tags+=v1")).To(o.Succeed())
+	g.Expect(p.Set("tags+=latest")).To(o.Succeed())
+	g.Expect(spec.ParamValues).To(o.HaveLen(3))
+	g.Expect(valuesOf(spec.ParamValues[2])).To(o.Equal([]string{"v1", "latest"}))
+
+	// setting an empty array
+	g.Expect(p.Set("empty=[]")).To(o.Succeed())
+	g.Expect(spec.ParamValues[3].Values).To(o.BeEmpty())
+
+	// repeated parameters, and appending to single values, are errors
+	g.Expect(p.Set("cache=c")).NotTo(o.Succeed())
+	g.Expect(p.Set("tags=[v2]")).NotTo(o.Succeed())
+	g.Expect(p.Set("cache+=c")).NotTo(o.Succeed())
+	g.Expect(p.Set(`broken=["a]`)).NotTo(o.Succeed())
+
+	g.Expect(p.String()).To(o.Equal(`["cache=a,b","build-args=[A=1,""B=2,3"",C=4]","tags=[v1,latest]",empty=[]]`))
+}
+
+func TestParamValuesJSONValue(t *testing.T) {
+	g := o.NewWithT(t)
+
+	spec := &buildv1alpha1.BuildSpec{}
+	g.Expect(NewParamValueArrayValue(&spec.ParamValues).Set("cache=disabled")).To(o.Succeed())
+	p := NewParamValuesJSONValue(&spec.ParamValues)
+
+	g.Expect(p.Set(`[{"name":"token","secretValue":{"name":"creds","key":"token"}},{"name":"args","values":[{"value":"a"},{"configMapValue":{"name":"cm","key":"b"}}]}]`)).
+		To(o.Succeed())
+	g.Expect(spec.ParamValues).To(o.HaveLen(3))
+	g.Expect(spec.ParamValues[1].SecretValue).To(o.Equal(&buildv1alpha1.ObjectKeyRef{Name: "creds", Key: "token"}))
+	g.Expect(spec.ParamValues[2].Values).To(o.HaveLen(2))
+	g.Expect(spec.ParamValues[2].Values[1].ConfigMapValue.Name).To(o.Equal("cm"))
+
+	// reading the values out of a file
+	file := filepath.Join(t.TempDir(), "params.json")
+	g.Expect(os.WriteFile(file, []byte(`[{"name":"dockerfile","value":"Containerfile"}]`), 0o600)).To(o.Succeed())
+	g.Expect(p.Set("@" + file)).To(o.Succeed())
+	g.Expect(*spec.ParamValues[3].SingleValue.Value).To(o.Equal("Containerfile"))
+
+	// invalid documents, nameless and repeated parameters are errors
+	g.Expect(p.Set(`{"name":"a"}`)).NotTo(o.Succeed())
+	g.Expect(p.Set(`[{"name":"a","unknown":true}]`)).NotTo(o.Succeed())
+	g.Expect(p.Set(`[{"value":"a"}]`)).NotTo(o.Succeed())
+	g.Expect(p.Set(`[{"name":"cache","value":"enabled"}]`)).NotTo(o.Succeed())
+	g.Expect(p.Set("@" + filepath.Join(t.TempDir(), "missing.json"))).NotTo(o.Succeed())
+}
@@ -0,0 +1,19 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// PinSourceImageFlag command-line flag.
+const PinSourceImageFlag = "pin-source-image"
+
+// PinSourceImageFlags registers the flag to pin the builder and source bundle images to the digests
+// their tags point to, recording the value on the informed boolean pointer.
+func PinSourceImageFlags(flags *pflag.FlagSet, pin *bool) {
+	flags.BoolVar(
+		pin,
+		PinSourceImageFlag,
+		false,
+		"resolve the builder and source bundle image tags to digests, stored on the Build for repeatable builds",
+	)
+}
@@ -0,0 +1,49 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	// PlatformsFlag command-line flag.
+	PlatformsFlag = "platforms"
+	// PlatformParamFlag command-line flag.
+	PlatformParamFlag = "platform-param"
+	// ManifestListFlag command-line flag.
+	ManifestListFlag = "manifest-list"
+)
+
+// MultiPlatform the platforms to build for, one BuildRun per platform, and whether the images
+// produced are stitched into a manifest list.
+type MultiPlatform struct {
+	Platforms    []string // platforms in the "os/arch[/variant]" format
+	Param        string   // build strategy parameter receiving the platform
+	ManifestList bool     // push a manifest list with the images produced
+}
+
+// MultiPlatformFlags registers the flags to run the Build once per platform.
+func MultiPlatformFlags(flags *pflag.FlagSet, m *MultiPlatform) {
+	flags.StringSliceVar(
+		&m.Platforms,
+		PlatformsFlag,
+		[]string{},
+		"comma separated platforms to build for, e.g. linux/amd64,linux/arm64, creating one BuildRun per platform",
+	)
+	flags.StringVar(
+		&m.Param,
+		PlatformParamFlag,
+		"platform",
+		"build strategy parameter receiving the platform of each BuildRun",
+	)
+	flags.BoolVar(
+		&m.ManifestList,
+		ManifestListFlag,
+		false,
+		"push a manifest list to the output image, stitching the images built for each platform",
+	)
+}
+
+// IsEmpty tells whether no platforms have been informed.
+func (m *MultiPlatform) IsEmpty() bool {
+	return len(m.Platforms) == 0
+}
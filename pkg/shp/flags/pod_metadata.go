@@ -0,0 +1,21 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// PodLabelFlag command-line flag.
+const PodLabelFlag = "pod-label"
+
+// PodLabelsFlags registers the flag for the labels of the build pods, recorded on the informed map
+// and set on the object created, either a Build or a BuildRun, which the build controller
+// propagates to the pods. Pod annotations, on the other hand, are only propagated from the build
+// strategy annotations.
+func PodLabelsFlags(flags *pflag.FlagSet, labels map[string]string, kind string) {
+	flags.Var(
+		NewMapValue(labels),
+		PodLabelFlag,
+		"specify a set of key-value pairs that correspond to labels propagated to the build pods via the "+kind+
+			", e.g. sidecar.istio.io/inject=false to skip the Istio sidecar injection",
+	)
+}
@@ -0,0 +1,59 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/shipwright-io/cli/pkg/shp/registry"
+)
+
+const (
+	// RegistryAuthFlag command-line flag.
+	RegistryAuthFlag = "registry-auth"
+	// RegistrySecretFlag command-line flag.
+	RegistrySecretFlag = "registry-secret" // #nosec G101
+)
+
+// RegistryAuthFlags registers the flags to select the credentials source employed by CLI-side
+// container registry operations.
+func RegistryAuthFlags(flags *pflag.FlagSet, source *string, secret *string) {
+	flags.StringVar(
+		source,
+		RegistryAuthFlag,
+		string(registry.AuthDefault),
+		fmt.Sprintf("source of the container registry credentials, one of %v", registry.AuthSources),
+	)
+	flags.StringVar(
+		secret,
+		RegistrySecretFlag,
+		"",
+		fmt.Sprintf("name of the docker-registry secret used when --%s=%s", RegistryAuthFlag, registry.AuthSecret),
+	)
+}
+
+const (
+	// PushCredentialsProviderFlag command-line flag.
+	PushCredentialsProviderFlag = "push-credentials-provider"
+	// PushSecretFlag command-line flag.
+	PushSecretFlag = "push-secret" // #nosec G101
+)
+
+// PushCredentialsFlags registers the flags to mint short-lived credentials for the output image
+// registry, refreshing the push secret before the BuildRun is created.
+func PushCredentialsFlags(flags *pflag.FlagSet, provider *string, secret *string) {
+	flags.StringVar(
+		provider,
+		PushCredentialsProviderFlag,
+		"",
+		fmt.Sprintf("mint the output registry credentials and refresh the push secret before running, %q or one of %v",
+			registry.ProviderAuto, registry.ProviderNames()),
+	)
+	flags.StringVar(
+		secret,
+		PushSecretFlag,
+		"",
+		fmt.Sprintf("name of the push secret refreshed by --%s, defaults to the Build output credentials or \"<build>-push\"",
+			PushCredentialsProviderFlag),
+	)
+}
@@ -0,0 +1,36 @@
+package flags
+
+import (
+	"fmt"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/pflag"
+)
+
+// SourceBundleDirFlag command-line flag.
+const SourceBundleDirFlag = "source-bundle-dir"
+
+// SourceBundleFlags registers the flags to pack a local directory into a source bundle image, used
+// by commands which push the bundle before running the build.
+func SourceBundleFlags(flags *pflag.FlagSet, bundle *buildv1alpha1.BundleContainer, dir *string) {
+	if bundle.Prune == nil {
+		bundle.Prune = new(buildv1alpha1.PruneOption)
+	}
+	flags.StringVar(
+		&bundle.Image,
+		SourceBundleImageFlag,
+		"",
+		"pack the local source directory and push it as the source bundle image, e.g. ghcr.io/org/app/source-bundle:latest",
+	)
+	flags.Var(
+		pruneOptionFlag{ref: bundle.Prune},
+		SourceBundlePruneFlag,
+		fmt.Sprintf("source bundle prune option, either %s, or %s", buildv1alpha1.PruneNever, buildv1alpha1.PruneAfterPull),
+	)
+	flags.StringVar(
+		dir,
+		SourceBundleDirFlag,
+		".",
+		"local source directory packed into the source bundle image",
+	)
+}
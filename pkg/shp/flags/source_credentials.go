@@ -0,0 +1,195 @@
+package flags
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SourceCredentialsFromFlag command-line flag.
+	SourceCredentialsFromFlag = "source-credentials-from" // #nosec G101
+	// SourceSSHKeyFlag command-line flag.
+	SourceSSHKeyFlag = "source-ssh-key"
+	// SourceKnownHostsFlag command-line flag.
+	SourceKnownHostsFlag = "source-known-hosts"
+	// SourceTokenFlag command-line flag.
+	SourceTokenFlag = "source-token" // #nosec G101
+	// SourceUsernameFlag command-line flag.
+	SourceUsernameFlag = "source-username"
+)
+
+// defaultSourceUsername username paired with the token, git providers accept any username along
+// with personal access tokens.
+const defaultSourceUsername = "git"
+
+// SourceCredentials git credentials informed inline, stored on a secret referenced by the Build's
+// source.
+type SourceCredentials struct {
+	From       string // file holding either a SSH private key or a token
+	SSHKey     string // SSH private key file
+	KnownHosts string // SSH known hosts file, optional
+	Token      string // token, or password, for HTTPS repositories
+	Username   string // username paired with the token
+}
+
+// SourceCredentialsFlags registers the flags to create the source credentials secret inline.
+func SourceCredentialsFlags(flags *pflag.FlagSet, c *SourceCredentials) {
+	flags.StringVar(
+		&c.From,
+		SourceCredentialsFromFlag,
+		"",
+		"file holding either a SSH private key or a token, stored on the source credentials secret",
+	)
+	flags.StringVar(
+		&c.SSHKey,
+		SourceSSHKeyFlag,
+		"",
+		"SSH private key file stored on the source credentials secret, e.g. ~/.ssh/id_ed25519",
+	)
+	flags.StringVar(
+		&c.KnownHosts,
+		SourceKnownHostsFlag,
+		"",
+		"SSH known hosts file stored along with the SSH private key, optional",
+	)
+	flags.StringVar(
+		&c.Token,
+		SourceTokenFlag,
+		"",
+		"token, or password, stored on the source credentials secret for HTTPS repositories",
+	)
+	flags.StringVar(
+		&c.Username,
+		SourceUsernameFlag,
+		defaultSourceUsername,
+		"username paired with the token on the source credentials secret",
+	)
+}
+
+// Informed tells whether the source credentials have been informed, thus a secret must be created.
+func (c *SourceCredentials) Informed() bool {
+	return c != nil && (c.From != "" || c.SSHKey != "" || c.Token != "")
+}
+
+// isSSHURL checks whether the git repository URL employs the SSH protocol.
+func isSSHURL(url string) bool {
+	return strings.HasPrefix(url, "ssh://") || strings.HasPrefix(url, "git@")
+}
+
+// Validate checks a single credentials source is informed, and it matches the repository protocol.
+func (c *SourceCredentials) Validate(sourceURL string) error {
+	if !c.Informed() {
+		if c != nil && c.KnownHosts != "" {
+			return fmt.Errorf("--%s requires --%s", SourceKnownHostsFlag, SourceSSHKeyFlag)
+		}
+		return nil
+	}
+	informed := 0
+	for _, v := range []string{c.From, c.SSHKey, c.Token} {
+		if v != "" {
+			informed++
+		}
+	}
+	if informed > 1 {
+		return fmt.Errorf("only one of --%s, --%s or --%s can be informed",
+			SourceCredentialsFromFlag, SourceSSHKeyFlag, SourceTokenFlag)
+	}
+	if sourceURL == "" {
+		return fmt.Errorf("--%s is required to use the source credentials", SourceURLFlag)
+	}
+	if c.Token != "" && isSSHURL(sourceURL) {
+		return fmt.Errorf("--%s requires a HTTPS repository URL, use --%s for %q", SourceTokenFlag, SourceSSHKeyFlag, sourceURL)
+	}
+	if c.SSHKey != "" && !isSSHURL(sourceURL) {
+		return fmt.Errorf("--%s requires a SSH repository URL, e.g. git@github.com:org/repo.git", SourceSSHKeyFlag)
+	}
+	if c.KnownHosts != "" && c.Token != "" {
+		return fmt.Errorf("--%s only applies to SSH credentials", SourceKnownHostsFlag)
+	}
+	return nil
+}
+
+// isPrivateKey checks whether the content is a PEM encoded private key, like SSH keys.
+func isPrivateKey(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return bytes.HasPrefix(data, []byte("-----BEGIN ")) && bytes.Contains(data, []byte("PRIVATE KEY-----"))
+}
+
+// Secret renders the secret carrying the credentials, either a "ssh-auth" secret for SSH keys or a
+// "basic-auth" secret for tokens, annotated to be watched by the build controller.
+func (c *SourceCredentials) Secret(name, namespace, sourceURL string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				buildv1alpha1.AnnotationBuildRefSecret: "true",
+			},
+		},
+	}
+
+	sshKey, token := []byte(nil), []byte(c.Token)
+	switch {
+	case c.SSHKey != "":
+		data, err := os.ReadFile(c.SSHKey)
+		if err != nil {
+			return nil, err
+		}
+		if !isPrivateKey(data) {
+			return nil, fmt.Errorf("file %q is not a private key", c.SSHKey)
+		}
+		sshKey = data
+	case c.From != "":
+		data, err := os.ReadFile(c.From)
+		if err != nil {
+			return nil, err
+		}
+		if isPrivateKey(data) {
+			if !isSSHURL(sourceURL) {
+				return nil, fmt.Errorf("file %q holds a SSH private key, which requires a SSH repository URL", c.From)
+			}
+			sshKey = data
+		} else {
+			if isSSHURL(sourceURL) {
+				return nil, fmt.Errorf("file %q holds a token, which requires a HTTPS repository URL", c.From)
+			}
+			token = bytes.TrimSpace(data)
+		}
+	}
+
+	if sshKey != nil {
+		secret.Type = corev1.SecretTypeSSHAuth
+		secret.Data = map[string][]byte{corev1.SSHAuthPrivateKey: sshKey}
+		if c.KnownHosts != "" {
+			data, err := os.ReadFile(c.KnownHosts)
+			if err != nil {
+				return nil, err
+			}
+			secret.Data["known_hosts"] = data
+		}
+		return secret, nil
+	}
+
+	if len(token) == 0 {
+		return nil, errors.New("the source token is empty")
+	}
+	username := c.Username
+	if username == "" {
+		username = defaultSourceUsername
+	}
+	secret.Type = corev1.SecretTypeBasicAuth
+	secret.Data = map[string][]byte{
+		corev1.BasicAuthUsernameKey: []byte(username),
+		corev1.BasicAuthPasswordKey: token,
+	}
+	return secret, nil
+}
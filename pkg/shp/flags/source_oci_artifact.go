@@ -0,0 +1,19 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// SourceOCIArtifactFlag command-line flag.
+const SourceOCIArtifactFlag = "source-oci-artifact"
+
+// SourceOCIArtifactFlags registers the flag to take the source from an existing OCI artifact,
+// instead of a git repository, recording the value on the informed pointer.
+func SourceOCIArtifactFlags(flags *pflag.FlagSet, artifact *string) {
+	flags.StringVar(
+		artifact,
+		SourceOCIArtifactFlag,
+		"",
+		"existing OCI artifact carrying the source, checked on the registry before the Build is created, e.g. ghcr.io/org/app/source:v1",
+	)
+}
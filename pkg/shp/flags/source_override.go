@@ -0,0 +1,54 @@
+package flags
+
+import (
+	"fmt"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/pflag"
+)
+
+// SourceOverride git revision and context directory replacing the Build's source when running it,
+// without modifying the Build.
+type SourceOverride struct {
+	Revision   string // git revision, e.g. branch, tag or commit SHA
+	ContextDir string // directory of the repository used as context
+}
+
+// SourceOverrideFlags registers the flags to override the Build's source when running it, using
+// the same names as the Build's source flags.
+func SourceOverrideFlags(flags *pflag.FlagSet, override *SourceOverride) {
+	flags.StringVar(
+		&override.Revision,
+		SourceRevisionFlag,
+		"",
+		"git revision to build instead of the Build's, e.g. a branch, tag or commit SHA",
+	)
+	flags.StringVar(
+		&override.ContextDir,
+		SourceContextDirFlag,
+		"",
+		"directory of the repository to use as context instead of the Build's",
+	)
+}
+
+// IsEmpty tells whether none of the overrides has been informed.
+func (s *SourceOverride) IsEmpty() bool {
+	return s.Revision == "" && s.ContextDir == ""
+}
+
+// Apply replaces the source revision and context directory informed, the revision can only be
+// overridden on git sources.
+func (s *SourceOverride) Apply(source *buildv1alpha1.Source) error {
+	if s.Revision != "" {
+		if source.URL == nil || *source.URL == "" {
+			return fmt.Errorf("--%s requires a git source, the Build has none", SourceRevisionFlag)
+		}
+		revision := s.Revision
+		source.Revision = &revision
+	}
+	if s.ContextDir != "" {
+		contextDir := s.ContextDir
+		source.ContextDir = &contextDir
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package flags
+
+import (
+	"testing"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/spf13/cobra"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestSourceOverride(t *testing.T) {
+	g := o.NewWithT(t)
+
+	parse := func(args ...string) *SourceOverride {
+		cmd := &cobra.Command{}
+		override := &SourceOverride{}
+		SourceOverrideFlags(cmd.Flags(), override)
+		g.Expect(cmd.ParseFlags(args)).To(o.Succeed())
+		return override
+	}
+
+	g.Expect(parse().IsEmpty()).To(o.BeTrue())
+
+	override := parse("--source-revision=feature", "--source-context-dir=app")
+	g.Expect(override.IsEmpty()).To(o.BeFalse())
+
+	source := buildv1alpha1.Source{
+		URL:        pointer.String("https://github.com/org/repo"),
+		Revision:   pointer.String("main"),
+		ContextDir: pointer.String("."),
+	}
+	g.Expect(override.Apply(&source)).To(o.Succeed())
+	g.Expect(*source.Revision).To(o.Equal("feature"))
+	g.Expect(*source.ContextDir).To(o.Equal("app"))
+
+	// only the informed overrides are applied
+	source = buildv1alpha1.Source{URL: pointer.String("https://github.com/org/repo"), Revision: pointer.String("main")}
+	g.Expect(parse("--source-context-dir=app").Apply(&source)).To(o.Succeed())
+	g.Expect(*source.Revision).To(o.Equal("main"))
+	g.Expect(*source.ContextDir).To(o.Equal("app"))
+
+	// the revision can't be overridden without a git source
+	source = buildv1alpha1.Source{BundleContainer: &buildv1alpha1.BundleContainer{Image: "ghcr.io/org/source"}}
+	g.Expect(parse("--source-revision=feature").Apply(&source)).To(o.MatchError(o.ContainSubstring("requires a git source")))
+	g.Expect(parse("--source-context-dir=app").Apply(&source)).To(o.Succeed())
+}
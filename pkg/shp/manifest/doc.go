@@ -0,0 +1,3 @@
+// Package manifest reads Shipwright Build and BuildRun manifests from files, directories, URLs or
+// the standard input, shared by the commands accepting the "--filename" flag.
+package manifest
@@ -0,0 +1,177 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Stdin filename reading the manifests from the standard input.
+const Stdin = "-"
+
+// Extensions file extensions of the manifests read from a directory.
+var Extensions = []string{".yaml", ".yml", ".json"}
+
+// ValidateFilenames makes sure the standard input is informed at most once.
+func ValidateFilenames(filenames []string) error {
+	stdin := 0
+	for _, filename := range filenames {
+		if filename == Stdin {
+			stdin++
+		}
+	}
+	if stdin > 1 {
+		return fmt.Errorf("the standard input can only be read once")
+	}
+	return nil
+}
+
+// ReadAll reads the objects of all informed filenames, in order.
+func ReadAll(ctx context.Context, in io.Reader, filenames []string) ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	for _, filename := range filenames {
+		found, err := Read(ctx, in, filename)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, found...)
+	}
+	return objects, nil
+}
+
+// Read reads the objects of a file, the files of a directory, an URL, or the standard input when
+// the filename is "-".
+func Read(ctx context.Context, in io.Reader, filename string) ([]*unstructured.Unstructured, error) {
+	switch {
+	case filename == Stdin:
+		return Decode(in, "stdin")
+	case strings.HasPrefix(filename, "http://") || strings.HasPrefix(filename, "https://"):
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, filename, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unable to read %q: %s", filename, resp.Status)
+		}
+		return Decode(resp.Body, filename)
+	}
+
+	stat, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+	if !stat.IsDir() {
+		return readFile(filename)
+	}
+	entries, err := os.ReadDir(filename)
+	if err != nil {
+		return nil, err
+	}
+	objects := []*unstructured.Unstructured{}
+	for _, entry := range entries {
+		if entry.IsDir() || !hasExtension(entry.Name()) {
+			continue
+		}
+		found, err := readFile(filepath.Join(filename, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, found...)
+	}
+	return objects, nil
+}
+
+// hasExtension tells whether the file is a YAML or JSON manifest.
+func hasExtension(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, e := range Extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// readFile reads the objects of a single file.
+func readFile(filename string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f, filename)
+}
+
+// Decode reads the stream of YAML (or JSON) documents, making sure all of them are Builds or
+// BuildRuns.
+func Decode(r io.Reader, source string) ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		content := map[string]interface{}{}
+		if err := decoder.Decode(&content); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("unable to decode %q: %w", source, err)
+		}
+		// skipping empty documents
+		if len(content) == 0 {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: content}
+		if obj.GetAPIVersion() != buildv1alpha1.SchemeGroupVersion.String() ||
+			(obj.GetKind() != "Build" && obj.GetKind() != "BuildRun") {
+			return nil, fmt.Errorf("unsupported object %s %q in %q, only %s Builds and BuildRuns are supported",
+				obj.GetKind(), obj.GetName(), source, buildv1alpha1.SchemeGroupVersion.String())
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// SetNamespace places the object in the namespace, unless it belongs to another namespace.
+func SetNamespace(obj *unstructured.Unstructured, namespace string) error {
+	switch obj.GetNamespace() {
+	case "":
+		obj.SetNamespace(namespace)
+	case namespace:
+	default:
+		return fmt.Errorf("%s %q belongs to namespace %q, which does not match the namespace %q",
+			obj.GetKind(), obj.GetName(), obj.GetNamespace(), namespace)
+	}
+	return nil
+}
+
+// Convert converts the object into the informed typed object, i.e. a Build, making sure the kind
+// matches.
+func Convert(obj *unstructured.Unstructured, kind string, into runtime.Object) error {
+	if obj.GetKind() != kind {
+		return fmt.Errorf("%s %q is not a %s", obj.GetKind(), displayName(obj), kind)
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, into)
+}
+
+// displayName the object name, or its generate name when the name is assigned by the API server.
+func displayName(obj *unstructured.Unstructured) string {
+	if obj.GetName() == "" {
+		return obj.GetGenerateName()
+	}
+	return obj.GetName()
+}
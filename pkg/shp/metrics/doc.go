@@ -0,0 +1,3 @@
+// Package metrics summarizes how long a BuildRun and each of its steps took, along with the
+// resources the steps were limited to, and retrieves the live resource usage of the build pod.
+package metrics
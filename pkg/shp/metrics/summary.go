@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+)
+
+// Supported output formats.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+// Formats all supported output formats.
+var Formats = []string{FormatTable, FormatJSON}
+
+// Summary durations of a BuildRun and its steps, expressed in seconds like Prometheus metrics.
+type Summary struct {
+	BuildRun     string  `json:"buildRun"`
+	Pod          string  `json:"pod,omitempty"`
+	QueueSeconds float64 `json:"queueSeconds"`
+	TotalSeconds float64 `json:"totalSeconds"`
+	Steps        []Step  `json:"steps"`
+}
+
+// Step duration, outcome and resource limits of a single build pod container.
+type Step struct {
+	Name            string            `json:"name"`
+	Init            bool              `json:"init,omitempty"`
+	DurationSeconds float64           `json:"durationSeconds"`
+	ExitCode        int32             `json:"exitCode"`
+	Reason          string            `json:"reason,omitempty"`
+	Restarts        int32             `json:"restarts,omitempty"`
+	Limits          map[string]string `json:"limits,omitempty"`
+}
+
+// NewSummary combines the BuildRun timestamps with the container timelines recorded by the tracker.
+// The queue time spans from the BuildRun creation until the pod start, and the total duration until
+// the BuildRun completion, or the last step to finish when not yet completed.
+func NewSummary(br *buildv1alpha1.BuildRun, tracker *reactor.ContainerTracker) *Summary {
+	s := &Summary{BuildRun: br.GetName(), Pod: tracker.PodName(), Steps: []Step{}}
+	created := br.GetCreationTimestamp().Time
+
+	var lastFinished, firstStarted time.Time
+	for _, c := range tracker.Containers() {
+		step := Step{
+			Name:            strings.TrimPrefix(c.Name, "step-"),
+			Init:            c.Init,
+			DurationSeconds: c.Duration().Seconds(),
+			ExitCode:        c.ExitCode,
+			Reason:          c.Reason,
+			Restarts:        c.Restarts,
+		}
+		if len(c.Limits) > 0 {
+			step.Limits = map[string]string{}
+			for name, quantity := range c.Limits {
+				step.Limits[string(name)] = quantity.String()
+			}
+		}
+		s.Steps = append(s.Steps, step)
+
+		if c.Finished.After(lastFinished) {
+			lastFinished = c.Finished
+		}
+		if !c.Started.IsZero() && (firstStarted.IsZero() || c.Started.Before(firstStarted)) {
+			firstStarted = c.Started
+		}
+	}
+
+	podStarted := tracker.PodStarted()
+	if podStarted.IsZero() {
+		podStarted = firstStarted
+	}
+	if !created.IsZero() && !podStarted.IsZero() {
+		s.QueueSeconds = podStarted.Sub(created).Seconds()
+	}
+
+	completed := lastFinished
+	if br.Status.CompletionTime != nil {
+		completed = br.Status.CompletionTime.Time
+	}
+	if !created.IsZero() && !completed.IsZero() {
+		s.TotalSeconds = completed.Sub(created).Seconds()
+	}
+	return s
+}
+
+// Print writes the summary using the informed format, defaults to table.
+func (s *Summary) Print(w io.Writer, format string) error {
+	switch format {
+	case "", FormatTable:
+		return s.printTable(w)
+	case FormatJSON:
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	default:
+		return fmt.Errorf("unsupported metrics format %q, expected one of %v", format, Formats)
+	}
+}
+
+// printTable writes the human friendly summary.
+func (s *Summary) printTable(w io.Writer) error {
+	fmt.Fprintf(w, "\nBuildRun %q metrics:\n\n", s.BuildRun)
+	writer := tabwriter.NewWriter(w, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(writer, "STEP\tDURATION\tEXIT CODE\tCPU LIMIT\tMEMORY LIMIT")
+	for _, step := range s.Steps {
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%s\t%s\n",
+			step.Name,
+			seconds(step.DurationSeconds),
+			step.ExitCode,
+			limit(step.Limits, corev1.ResourceCPU),
+			limit(step.Limits, corev1.ResourceMemory),
+		)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nQueue time: %s\n", seconds(s.QueueSeconds))
+	fmt.Fprintf(w, "Total duration: %s\n", seconds(s.TotalSeconds))
+	return nil
+}
+
+// seconds renders the amount of seconds as a rounded duration.
+func seconds(v float64) string {
+	return (time.Duration(v * float64(time.Second))).Round(time.Second).String()
+}
+
+// limit renders the resource limit, or "-" when not set.
+func limit(limits map[string]string, name corev1.ResourceName) string {
+	if v, ok := limits[string(name)]; ok {
+		return v
+	}
+	return "-"
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+)
+
+func TestSummary(t *testing.T) {
+	g := o.NewWithT(t)
+
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	br := &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-xyz", CreationTimestamp: metav1.NewTime(created)},
+		Status: buildv1alpha1.BuildRunStatus{
+			CompletionTime: &metav1.Time{Time: created.Add(time.Minute)},
+		},
+	}
+
+	tracker := reactor.NewContainerTracker()
+	g.Expect(tracker.OnEvent(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-xyz-pod"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "step-build",
+			Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}},
+		}}},
+		Status: corev1.PodStatus{
+			StartTime: &metav1.Time{Time: created.Add(5 * time.Second)},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "step-build",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					StartedAt:  metav1.NewTime(created.Add(10 * time.Second)),
+					FinishedAt: metav1.NewTime(created.Add(50 * time.Second)),
+				}},
+			}},
+		},
+	})).To(o.Succeed())
+
+	s := NewSummary(br, tracker)
+	g.Expect(s.Pod).To(o.Equal("my-app-xyz-pod"))
+	g.Expect(s.QueueSeconds).To(o.Equal(5.0))
+	g.Expect(s.TotalSeconds).To(o.Equal(60.0))
+	g.Expect(s.Steps).To(o.Equal([]Step{{
+		Name:            "build",
+		DurationSeconds: 40,
+		Limits:          map[string]string{"cpu": "500m", "memory": "1Gi"},
+	}}))
+
+	var out bytes.Buffer
+	g.Expect(s.Print(&out, FormatTable)).To(o.Succeed())
+	g.Expect(out.String()).To(o.MatchRegexp(`build\s+40s\s+0\s+500m\s+1Gi`))
+	g.Expect(out.String()).To(o.ContainSubstring("Queue time: 5s"))
+	g.Expect(out.String()).To(o.ContainSubstring("Total duration: 1m0s"))
+
+	out.Reset()
+	g.Expect(s.Print(&out, FormatJSON)).To(o.Succeed())
+	var decoded Summary
+	g.Expect(json.Unmarshal(out.Bytes(), &decoded)).To(o.Succeed())
+	g.Expect(&decoded).To(o.Equal(s))
+
+	g.Expect(s.Print(&out, "yaml")).NotTo(o.Succeed())
+}
@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// metricsAPIPath base path of the resource metrics API served by metrics-server.
+const metricsAPIPath = "/apis/metrics.k8s.io/v1beta1"
+
+// ErrNoMetrics the resource metrics API has no metrics for the pod, either because metrics-server
+// is not installed, or because it hasn't scraped the pod yet.
+var ErrNoMetrics = fmt.Errorf("no metrics available, metrics-server may not be installed or has not scraped the pod yet")
+
+// podMetrics subset of the metrics-server PodMetrics resource.
+type podMetrics struct {
+	Timestamp  metav1.Time     `json:"timestamp"`
+	Window     metav1.Duration `json:"window"`
+	Containers []struct {
+		Name  string              `json:"name"`
+		Usage corev1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+// ContainerUsage live resource usage of a build pod container, along with its limits.
+type ContainerUsage struct {
+	Name   string
+	Init   bool
+	Usage  corev1.ResourceList
+	Limits corev1.ResourceList
+}
+
+// PodUsage live resource usage of the build pod containers, sampled over the window ending at the
+// timestamp.
+type PodUsage struct {
+	Pod        string
+	Timestamp  time.Time
+	Window     time.Duration
+	Containers []ContainerUsage
+}
+
+// CoreRESTClient returns the REST client of the Kubernetes clientset, nil when the clientset does
+// not carry one, like the fake clientsets.
+func CoreRESTClient(clientset kubernetes.Interface) rest.Interface {
+	client := clientset.CoreV1().RESTClient()
+	if c, ok := client.(*rest.RESTClient); ok && c == nil {
+		return nil
+	}
+	return client
+}
+
+// FetchPodUsage retrieves the pod metrics from the resource metrics API, combined with the
+// container limits of the pod, following the order of the pod containers. Containers without
+// metrics, i.e. the init containers already terminated, are omitted.
+func FetchPodUsage(ctx context.Context, client rest.Interface, pod *corev1.Pod) (*PodUsage, error) {
+	if client == nil {
+		return nil, ErrNoMetrics
+	}
+	data, err := client.Get().
+		AbsPath(metricsAPIPath, "namespaces", pod.GetNamespace(), "pods", pod.GetName()).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, ErrNoMetrics
+		}
+		return nil, err
+	}
+	metrics := podMetrics{}
+	if err = json.Unmarshal(data, &metrics); err != nil {
+		return nil, fmt.Errorf("unable to decode the metrics of pod %q: %w", pod.GetName(), err)
+	}
+
+	usageOf := map[string]corev1.ResourceList{}
+	for _, c := range metrics.Containers {
+		usageOf[c.Name] = c.Usage
+	}
+	usage := &PodUsage{Pod: pod.GetName(), Timestamp: metrics.Timestamp.Time, Window: metrics.Window.Duration}
+	add := func(containers []corev1.Container, init bool) {
+		for _, c := range containers {
+			if u, ok := usageOf[c.Name]; ok {
+				usage.Containers = append(usage.Containers, ContainerUsage{Name: c.Name, Init: init, Usage: u, Limits: c.Resources.Limits})
+			}
+		}
+	}
+	add(pod.Spec.InitContainers, true)
+	add(pod.Spec.Containers, false)
+	return usage, nil
+}
+
+// Print writes the usage of each step, along with the share of its limits.
+func (u *PodUsage) Print(w io.Writer) error {
+	fmt.Fprintf(w, "Pod %q usage over %s, sampled at %s\n\n", u.Pod, u.Window, u.Timestamp.Format(time.TimeOnly))
+	writer := tabwriter.NewWriter(w, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(writer, "STEP\tCPU\tCPU LIMIT\tMEMORY\tMEMORY LIMIT")
+	for _, c := range u.Containers {
+		cpu, memory := c.Usage[corev1.ResourceCPU], c.Usage[corev1.ResourceMemory]
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			strings.TrimPrefix(c.Name, "step-"),
+			cpu.String(),
+			share(cpu, c.Limits, corev1.ResourceCPU),
+			memory.String(),
+			share(memory, c.Limits, corev1.ResourceMemory),
+		)
+	}
+	return writer.Flush()
+}
+
+// share renders the resource limit along with the percentage used, or "-" when not set.
+func share(used resource.Quantity, limits corev1.ResourceList, name corev1.ResourceName) string {
+	l, ok := limits[name]
+	if !ok || l.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%d%%)", l.String(), used.MilliValue()*100/l.MilliValue())
+}
@@ -0,0 +1,3 @@
+// Package openshift holds the helpers to target OpenShift specific resources, like pushing the
+// output image to an ImageStream on the cluster's internal registry.
+package openshift
@@ -0,0 +1,133 @@
+package openshift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ImageStreamResource the ImageStream resource on the image.openshift.io API group.
+var ImageStreamResource = schema.GroupVersionResource{
+	Group:    "image.openshift.io",
+	Version:  "v1",
+	Resource: "imagestreams",
+}
+
+// DefaultInternalRegistry service address of the OpenShift internal registry, employed when the
+// ImageStream does not inform its repository.
+const DefaultInternalRegistry = "image-registry.openshift-image-registry.svc:5000"
+
+// defaultTag tag employed when the ImageStream reference does not inform one.
+const defaultTag = "latest"
+
+// builderServiceAccount service account OpenShift grants push permissions on the namespace's
+// ImageStreams, its dockercfg secret carries the internal registry credentials.
+const builderServiceAccount = "builder"
+
+// ImageStreamRef reference to an ImageStream tag, on the "name[:tag]" format.
+type ImageStreamRef struct {
+	Name string
+	Tag  string
+}
+
+// ParseImageStreamRef parses the "name[:tag]" reference, the tag defaults to "latest".
+func ParseImageStreamRef(ref string) (ImageStreamRef, error) {
+	name, tag, found := strings.Cut(ref, ":")
+	if !found {
+		tag = defaultTag
+	}
+	if name == "" || tag == "" || strings.ContainsAny(name, "/@") || strings.Contains(tag, ":") {
+		return ImageStreamRef{}, fmt.Errorf("invalid ImageStream reference %q, expected on the format name[:tag]", ref)
+	}
+	return ImageStreamRef{Name: name, Tag: tag}, nil
+}
+
+// String renders the reference on the "name:tag" format.
+func (r ImageStreamRef) String() string {
+	return fmt.Sprintf("%s:%s", r.Name, r.Tag)
+}
+
+// ResolveImageStream returns the internal registry image of the ImageStream tag, creating the
+// ImageStream when it does not exist yet. Tells whether the ImageStream has been created.
+func ResolveImageStream(
+	ctx context.Context,
+	client dynamic.Interface,
+	namespace string,
+	ref ImageStreamRef,
+) (string, bool, error) {
+	imageStreams := client.Resource(ImageStreamResource).Namespace(namespace)
+	is, err := imageStreams.Get(ctx, ref.Name, metav1.GetOptions{})
+	created := false
+	if kerrors.IsNotFound(err) {
+		is = &unstructured.Unstructured{}
+		is.SetAPIVersion(ImageStreamResource.GroupVersion().String())
+		is.SetKind("ImageStream")
+		is.SetName(ref.Name)
+		is.SetNamespace(namespace)
+		if is, err = imageStreams.Create(ctx, is, metav1.CreateOptions{}); err != nil {
+			if kerrors.IsNotFound(err) {
+				// the resource itself is not served, thus not an OpenShift cluster
+				return "", false, fmt.Errorf("ImageStreams are not available on the cluster, OpenShift is required")
+			}
+			return "", false, fmt.Errorf("unable to create ImageStream %q: %w", ref.Name, err)
+		}
+		created = true
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("unable to obtain ImageStream %q: %w", ref.Name, err)
+	}
+
+	repository, _, _ := unstructured.NestedString(is.Object, "status", "dockerImageRepository")
+	if repository == "" {
+		repository = fmt.Sprintf("%s/%s/%s", DefaultInternalRegistry, namespace, ref.Name)
+	}
+	return fmt.Sprintf("%s:%s", repository, ref.Tag), created, nil
+}
+
+// BuilderPushSecret returns the name of the dockercfg secret of the "builder" service account,
+// allowed to push to the namespace's ImageStreams. An empty name is returned when the secret is
+// not found.
+func BuilderPushSecret(ctx context.Context, clientset kubernetes.Interface, namespace string) (string, error) {
+	sa, err := clientset.CoreV1().ServiceAccounts(namespace).Get(ctx, builderServiceAccount, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	prefix := builderServiceAccount + "-dockercfg-"
+	for _, ref := range sa.ImagePullSecrets {
+		if strings.HasPrefix(ref.Name, prefix) {
+			return ref.Name, nil
+		}
+	}
+	for _, ref := range sa.Secrets {
+		if strings.HasPrefix(ref.Name, prefix) {
+			return ref.Name, nil
+		}
+	}
+
+	// the secret may not be referenced by the service account yet, looking it up by annotation
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("type=%s", corev1.SecretTypeDockercfg),
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, s := range secrets.Items {
+		if s.Type == corev1.SecretTypeDockercfg &&
+			s.GetAnnotations()[corev1.ServiceAccountNameKey] == builderServiceAccount {
+			return s.GetName(), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,92 @@
+package openshift
+
+import (
+	"context"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseImageStreamRef(t *testing.T) {
+	g := o.NewWithT(t)
+
+	ref, err := ParseImageStreamRef("my-app")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(ref.String()).To(o.Equal("my-app:latest"))
+
+	ref, err = ParseImageStreamRef("my-app:v1")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(ref).To(o.Equal(ImageStreamRef{Name: "my-app", Tag: "v1"}))
+
+	for _, invalid := range []string{"", ":v1", "my-app:", "org/my-app", "my-app@sha256:abc"} {
+		_, err = ParseImageStreamRef(invalid)
+		g.Expect(err).To(o.HaveOccurred(), invalid)
+	}
+}
+
+func TestResolveImageStream(t *testing.T) {
+	g := o.NewWithT(t)
+
+	existing := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "image.openshift.io/v1",
+		"kind":       "ImageStream",
+		"metadata":   map[string]interface{}{"name": "existing", "namespace": "dev"},
+		"status": map[string]interface{}{
+			"dockerImageRepository": "image-registry.openshift-image-registry.svc:5000/dev/existing",
+		},
+	}}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{ImageStreamResource: "ImageStreamList"}, existing)
+
+	image, created, err := ResolveImageStream(context.TODO(), client, "dev", ImageStreamRef{Name: "existing", Tag: "v1"})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(created).To(o.BeFalse())
+	g.Expect(image).To(o.Equal("image-registry.openshift-image-registry.svc:5000/dev/existing:v1"))
+
+	image, created, err = ResolveImageStream(context.TODO(), client, "dev", ImageStreamRef{Name: "new", Tag: "latest"})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(created).To(o.BeTrue())
+	g.Expect(image).To(o.Equal(DefaultInternalRegistry + "/dev/new:latest"))
+
+	_, err = client.Resource(ImageStreamResource).Namespace("dev").Get(context.TODO(), "new", metav1.GetOptions{})
+	g.Expect(err).To(o.BeNil())
+}
+
+func TestBuilderPushSecret(t *testing.T) {
+	g := o.NewWithT(t)
+
+	name, err := BuilderPushSecret(context.TODO(), fake.NewSimpleClientset(), "dev")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(name).To(o.BeEmpty())
+
+	clientset := fake.NewSimpleClientset(&corev1.ServiceAccount{
+		ObjectMeta:       metav1.ObjectMeta{Name: "builder", Namespace: "dev"},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "builder-dockercfg-x7z2q"}},
+	})
+	name, err = BuilderPushSecret(context.TODO(), clientset, "dev")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(name).To(o.Equal("builder-dockercfg-x7z2q"))
+
+	clientset = fake.NewSimpleClientset(
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "dev"}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "builder-dockercfg-abcde",
+				Namespace:   "dev",
+				Annotations: map[string]string{corev1.ServiceAccountNameKey: "builder"},
+			},
+			Type: corev1.SecretTypeDockercfg,
+		},
+	)
+	name, err = BuilderPushSecret(context.TODO(), clientset, "dev")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(name).To(o.Equal("builder-dockercfg-abcde"))
+}
@@ -2,7 +2,10 @@ package params
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"math"
+	"net/http"
 	"strings"
 	"time"
 
@@ -10,17 +13,22 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/scheme"
 
 	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+	"github.com/shipwright-io/cli/pkg/shp/cache"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/follower"
+	"github.com/shipwright-io/cli/pkg/shp/exitcode"
 	"github.com/shipwright-io/cli/pkg/shp/reactor"
 
 	"github.com/spf13/pflag"
 )
 
+// hiddenKubeFlags kubeconfig overrides not exposed on the help output, the "--kubeconfig",
+// "--context", "--cluster" and "--user" flags are kept visible to target non-default clusters.
 var hiddenKubeFlags = []string{
 	"as",
 	"as-uid",
@@ -29,14 +37,11 @@ var hiddenKubeFlags = []string{
 	"certificate-authority",
 	"client-certificate",
 	"client-key",
-	"cluster",
-	"context",
 	"disable-compression",
 	"insecure-skip-tls-verify",
 	"server",
 	"tls-server-name",
 	"token",
-	"user",
 }
 
 // Params is a place for Shipwright CLI to store its runtime parameters including configured dynamic
@@ -44,11 +49,22 @@ var hiddenKubeFlags = []string{
 type Params struct {
 	clientset      kubernetes.Interface     // kubernetes api-client, global instance
 	buildClientset buildclientset.Interface // shipwright api-client, global instance
+	dynamicClient  dynamic.Interface        // dynamic api-client, global instance
 	pw             *reactor.PodWatcher      // pod-watcher global instance
 	follower       *follower.Follower       // follower global instance
+	cache          *cache.Cache             // informers cache global instance
 
 	configFlags *genericclioptions.ConfigFlags
 	namespace   string
+	noColor     bool
+	quiet       bool
+	errorFormat string
+	apiTimeout  time.Duration // timeout of each API call attempt
+	apiRetries  int           // amount of retries of throttled, or transiently failed, API calls
+	version     string        // CLI version, recorded on the exported traces
+
+	traceEndpoint string // OTLP collector endpoint receiving the command phases timing
+	traceFile     string // file the command phases timing is appended to
 
 	failPollInterval *time.Duration
 	failPollTimeout  *time.Duration
@@ -58,11 +74,70 @@ type Params struct {
 func (p *Params) AddFlags(flags *pflag.FlagSet) {
 	p.configFlags.AddFlags(flags)
 
+	// the request timeout bounds the whole command, watches and log streams included
+	if flag := flags.Lookup("request-timeout"); flag != nil {
+		flag.Usage = "maximum amount of time of the whole command, watches and log streams included, " +
+			"with a unit (e.g. 1s, 2m, 3h), zero means no limit"
+	}
 	for _, flag := range hiddenKubeFlags {
 		if err := flags.MarkHidden(flag); err != nil {
 			panic(err)
 		}
 	}
+
+	flags.BoolVar(&p.noColor, "no-color", false, "disable colored output, also disabled by the NO_COLOR environment variable")
+	flags.BoolVarP(&p.quiet, "quiet", "q", false, "only print resource names, one per line")
+	flags.StringVar(&p.errorFormat, ErrorFormatFlag, exitcode.FormatText,
+		fmt.Sprintf("format of the error printed when the command fails, one of %v", exitcode.Formats))
+	flags.DurationVar(&p.apiTimeout, APITimeoutFlag, defaultAPITimeout,
+		"maximum amount of time of each API call, streaming calls like watches and logs excluded, zero means no limit")
+	flags.IntVar(&p.apiRetries, APIRetriesFlag, defaultAPIRetries,
+		"amount of retries of API calls throttled by the API server, or failed transiently")
+	p.addTraceFlags(flags)
+}
+
+// wrapTransport applies the API calls timeout and retries on the informed configuration.
+func (p *Params) wrapTransport(config *rest.Config) {
+	timeout, retries := p.apiTimeout, p.apiRetries
+	if retries < 0 {
+		retries = 0
+	}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return newRetryTransport(rt, timeout, retries)
+	})
+}
+
+// ErrorFormatFlag command-line flag selecting the error format.
+const ErrorFormatFlag = "error-format"
+
+// ErrorFormat returns the setting from --error-format param.
+func (p *Params) ErrorFormat() string {
+	return p.errorFormat
+}
+
+// NoColor returns the setting from --no-color param
+func (p *Params) NoColor() bool {
+	return p.noColor
+}
+
+// Quiet returns the setting from --quiet param, commands print only the names of the resources
+// created or listed, one per line.
+func (p *Params) Quiet() bool {
+	return p.quiet
+}
+
+// QuietStreams returns the streams for informative messages, the standard output is discarded on
+// quiet mode while errors and warnings are still shown.
+func (p *Params) QuietStreams(ioStreams *genericclioptions.IOStreams) *genericclioptions.IOStreams {
+	if !p.quiet {
+		return ioStreams
+	}
+	return &genericclioptions.IOStreams{In: ioStreams.In, Out: io.Discard, ErrOut: ioStreams.ErrOut}
+}
+
+// SetQuiet overrides the --quiet param.
+func (p *Params) SetQuiet(quiet bool) {
+	p.quiet = quiet
 }
 
 // RESTConfig returns the rest configuration based on local flags.
@@ -77,6 +152,7 @@ func (p *Params) RESTConfig() (*rest.Config, error) {
 		return nil, err
 	}
 
+	p.wrapTransport(restConfig)
 	restConfig.APIPath = "/api"
 	restConfig.GroupVersion = &corev1.SchemeGroupVersion
 	restConfig.NegotiatedSerializer = serializer.WithoutConversionCodecFactory{
@@ -105,7 +181,7 @@ func (p *Params) ClientSet() (kubernetes.Interface, error) {
 
 // RequestTimeout returns the setting from k8s --request-timeout param
 func (p *Params) RequestTimeout() (time.Duration, error) {
-	if p.configFlags.Timeout == nil {
+	if p.configFlags == nil || p.configFlags.Timeout == nil {
 		return math.MaxInt64, nil
 	}
 	// 0 or empty also mean no timeout
@@ -116,6 +192,25 @@ func (p *Params) RequestTimeout() (time.Duration, error) {
 	return time.ParseDuration(*p.configFlags.Timeout)
 }
 
+// RequestContext derives the command context from the informed one, bound to the --request-timeout
+// deadline when informed, thus the client calls, watchers and log streams sharing it are
+// interrupted once it expires. The cancel function must be called when the command is done.
+func (p *Params) RequestContext(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	to, err := p.RequestTimeout()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid --request-timeout: %w", err)
+	}
+	if to == math.MaxInt64 {
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, cancel, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, to)
+	return ctx, cancel, nil
+}
+
 // ShipwrightClientSet returns a Shipwright Clientset
 func (p *Params) ShipwrightClientSet() (buildclientset.Interface, error) {
 	if p.buildClientset != nil {
@@ -130,6 +225,7 @@ func (p *Params) ShipwrightClientSet() (buildclientset.Interface, error) {
 	if err != nil {
 		return nil, err
 	}
+	p.wrapTransport(config)
 	p.buildClientset, err = buildclientset.NewForConfig(config)
 	if err != nil {
 		return nil, err
@@ -137,6 +233,43 @@ func (p *Params) ShipwrightClientSet() (buildclientset.Interface, error) {
 	return p.buildClientset, nil
 }
 
+// DynamicClient returns a dynamic client, employed on resources without a typed clientset.
+func (p *Params) DynamicClient() (dynamic.Interface, error) {
+	if p.dynamicClient != nil {
+		return p.dynamicClient, nil
+	}
+	clientConfig := p.configFlags.ToRawKubeConfigLoader()
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	p.wrapTransport(config)
+	p.dynamicClient, err = dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return p.dynamicClient, nil
+}
+
+// SetDynamicClient overrides the dynamic client, for testing purposes.
+func (p *Params) SetDynamicClient(client dynamic.Interface) {
+	p.dynamicClient = client
+}
+
+// Cache returns the informers cache shared across the commands, its informers run until the
+// informed context is done.
+func (p *Params) Cache(ctx context.Context) (*cache.Cache, error) {
+	if p.cache != nil {
+		return p.cache, nil
+	}
+	buildClientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return nil, err
+	}
+	p.cache = cache.NewCache(ctx, buildClientset, cache.DefaultResync)
+	return p.cache, nil
+}
+
 // Namespace returns kubernetes namespace with all the overrides
 // from command line and kubernetes config
 func (p *Params) Namespace() string {
@@ -193,6 +326,63 @@ func (p *Params) NewFollower(
 	return p.follower, nil
 }
 
+// NewDedicatedFollower instantiate a new Follower with its own PodWatcher, instead of the shared
+// one, thus several BuildRuns can be followed at once.
+func (p *Params) NewDedicatedFollower(
+	ctx context.Context,
+	br types.NamespacedName,
+	ioStreams *genericclioptions.IOStreams,
+) (*follower.Follower, error) {
+	to, err := p.RequestTimeout()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := p.ClientSet()
+	if err != nil {
+		return nil, err
+	}
+	buildClientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return nil, err
+	}
+	pw, err := reactor.NewPodWatcher(ctx, to, clientset, p.Namespace())
+	if err != nil {
+		return nil, err
+	}
+
+	f := follower.NewFollower(ctx, br, ioStreams, pw, clientset, buildClientset)
+	if p.failPollTimeout != nil {
+		f.SetFailPollTimeout(*p.failPollTimeout)
+	}
+	if p.failPollInterval != nil {
+		f.SetFailPollInterval(*p.failPollInterval)
+	}
+	return f, nil
+}
+
+// KubeconfigUser returns the kubeconfig user of the current context, or the one informed by the
+// "--user" and "--context" flags, empty when not found.
+func (p *Params) KubeconfigUser() string {
+	if p.configFlags == nil {
+		return ""
+	}
+	if p.configFlags.AuthInfoName != nil && *p.configFlags.AuthInfoName != "" {
+		return *p.configFlags.AuthInfoName
+	}
+	raw, err := p.configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return ""
+	}
+	contextName := raw.CurrentContext
+	if p.configFlags.Context != nil && *p.configFlags.Context != "" {
+		contextName = *p.configFlags.Context
+	}
+	if context, ok := raw.Contexts[contextName]; ok {
+		return context.AuthInfo
+	}
+	return ""
+}
+
 // NewParams creates a new instance of ShipwrightParams and returns it as
 // an interface value
 func NewParams() *Params {
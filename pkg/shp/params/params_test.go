@@ -1,6 +1,8 @@
 package params
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/onsi/gomega"
@@ -29,3 +31,89 @@ func TestParamsCreation(t *testing.T) {
 	})
 
 }
+
+func TestParamsKubeconfigContext(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	g.Expect(os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+users:
+- name: developer
+  user:
+    token: dev-token
+- name: admin
+  user:
+    token: prod-token
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: developer
+    namespace: dev-ns
+- name: prod
+  context:
+    cluster: prod
+    user: admin
+    namespace: prod-ns
+`), 0o600)).To(gomega.Succeed())
+
+	for _, flag := range []string{"kubeconfig", "context", "cluster", "user"} {
+		flagset := pflag.NewFlagSet("name", 0)
+		NewParams().AddFlags(flagset)
+		g.Expect(flagset.Lookup(flag).Hidden).To(gomega.BeFalse(), "flag %q must be visible", flag)
+	}
+
+	testCases := []struct {
+		args      []string
+		host      string
+		token     string
+		namespace string
+	}{
+		{[]string{}, "https://dev.example.com:6443", "dev-token", "dev-ns"},
+		{[]string{"--context=prod"}, "https://prod.example.com:6443", "prod-token", "prod-ns"},
+		{[]string{"--cluster=prod"}, "https://prod.example.com:6443", "dev-token", "dev-ns"},
+		{[]string{"--context=prod", "--user=developer"}, "https://prod.example.com:6443", "dev-token", "prod-ns"},
+	}
+	for _, tt := range testCases {
+		flagset := pflag.NewFlagSet("name", 0)
+		p := NewParams()
+		p.AddFlags(flagset)
+		g.Expect(flagset.Parse(append([]string{"--kubeconfig=" + kubeconfig}, tt.args...))).To(gomega.Succeed())
+
+		restConfig, err := p.RESTConfig()
+		g.Expect(err).To(gomega.BeNil())
+		g.Expect(restConfig.Host).To(gomega.Equal(tt.host), "args %v", tt.args)
+		g.Expect(restConfig.BearerToken).To(gomega.Equal(tt.token), "args %v", tt.args)
+		g.Expect(p.Namespace()).To(gomega.Equal(tt.namespace), "args %v", tt.args)
+	}
+}
+
+func TestParamsTrace(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	flagset := pflag.NewFlagSet("name", 0)
+	shpParams := NewParams()
+	shpParams.AddFlags(flagset)
+
+	g.Expect(shpParams.NewTracer()).To(gomega.BeNil(), "tracing must be disabled by default")
+	g.Expect(shpParams.ExportTrace(nil)).To(gomega.Succeed())
+
+	traceFile := filepath.Join(t.TempDir(), "trace.jsonl")
+	g.Expect(flagset.Set(TraceFileFlag, traceFile)).To(gomega.Succeed())
+	tracer := shpParams.NewTracer()
+	g.Expect(tracer).ToNot(gomega.BeNil())
+	g.Expect(shpParams.ExportTrace(tracer)).To(gomega.Succeed())
+
+	data, err := os.ReadFile(traceFile)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(string(data)).To(gomega.HavePrefix(`{"resourceSpans":`))
+}
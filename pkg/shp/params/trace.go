@@ -0,0 +1,65 @@
+package params
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/shipwright-io/cli/pkg/shp/trace"
+)
+
+const (
+	// TraceEndpointFlag command-line flag.
+	TraceEndpointFlag = "trace-endpoint"
+	// TraceFileFlag command-line flag.
+	TraceFileFlag = "trace-file"
+
+	// traceService service name the spans are recorded for.
+	traceService = "shp"
+	// traceExportTimeout maximum amount of time sending the spans to the collector.
+	traceExportTimeout = 10 * time.Second
+)
+
+// addTraceFlags registers the flags enabling the export of the command phases timing.
+func (p *Params) addTraceFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&p.traceEndpoint, TraceEndpointFlag, "",
+		"OpenTelemetry collector endpoint receiving the timing of the command phases, OTLP over HTTP, e.g. http://localhost:4318")
+	flags.StringVar(&p.traceFile, TraceFileFlag, "",
+		"file the timing of the command phases is appended to, one line of OTLP JSON per command")
+}
+
+// SetVersion informs the CLI version, recorded on the exported traces.
+func (p *Params) SetVersion(version string) {
+	p.version = version
+}
+
+// NewTracer returns a tracer recording the command phases when either --trace-endpoint or
+// --trace-file is informed, nil otherwise.
+func (p *Params) NewTracer() *trace.Tracer {
+	if p.traceEndpoint == "" && p.traceFile == "" {
+		return nil
+	}
+	return trace.New(traceService, p.version)
+}
+
+// ExportTrace exports the spans recorded by the tracer to the collector endpoint and the file
+// informed, the command outcome is not affected by the export, thus the errors are only reported.
+func (p *Params) ExportTrace(t *trace.Tracer) error {
+	if t == nil {
+		return nil
+	}
+	var errs []error
+	if p.traceFile != "" {
+		errs = append(errs, t.WriteFile(p.traceFile))
+	}
+	if p.traceEndpoint != "" {
+		// the command context may be expired already, i.e. by the request timeout
+		ctx, cancel := context.WithTimeout(context.Background(), traceExportTimeout)
+		defer cancel()
+		errs = append(errs, t.Send(ctx, http.DefaultClient, p.traceEndpoint))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,178 @@
+package params
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// APITimeoutFlag command-line flag.
+	APITimeoutFlag = "api-timeout"
+	// APIRetriesFlag command-line flag.
+	APIRetriesFlag = "api-retries"
+
+	// defaultAPITimeout default amount of time a single API call may take.
+	defaultAPITimeout = 30 * time.Second
+	// defaultAPIRetries default amount of retries of a failed API call.
+	defaultAPIRetries = 3
+
+	// retryBackoff initial interval between retries, doubled on every attempt.
+	retryBackoff = 250 * time.Millisecond
+	// maxRetryBackoff longest interval between retries, including the ones asked by the server.
+	maxRetryBackoff = 10 * time.Second
+)
+
+// retryTransport applies a timeout on every API call attempt, and retries the calls throttled by
+// the API server, honoring the "Retry-After" header, or failed transiently. Streaming calls, like
+// watches, logs or upgraded connections, are not subject to the timeout. Only idempotent
+// calls are retried on transient failures, while throttled calls have not been processed, thus are
+// always retried.
+type retryTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration // timeout of each attempt, zero means no timeout
+	retries int           // amount of retries after the first attempt
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// newRetryTransport wraps the informed round tripper.
+func newRetryTransport(next http.RoundTripper, timeout time.Duration, retries int) *retryTransport {
+	return &retryTransport{next: next, timeout: timeout, retries: retries, sleep: sleepContext}
+}
+
+// RoundTrip executes the request, retrying it while allowed.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req)
+
+		retry, wait := t.shouldRetry(req, resp, err)
+		if !retry || attempt >= t.retries || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if wait == 0 {
+			wait = backoff
+			backoff *= 2
+		}
+		if wait > maxRetryBackoff {
+			wait = maxRetryBackoff
+		}
+		klog.V(4).Infof("Retrying %s %s in %s (retry %d of %d): %s",
+			req.Method, req.URL.Path, wait, attempt+1, t.retries, retryReason(resp, err))
+		if err = t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// attempt executes the request once, with a timeout when it's not a streaming call. The timeout
+// is released once the response body is closed.
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 || isStreaming(req) {
+		return t.next.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// shouldRetry tells whether the attempt should be retried, and how long the server asked to wait.
+func (t *retryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) (bool, time.Duration) {
+	if req.Context().Err() != nil {
+		return false, 0
+	}
+	if err != nil {
+		return isIdempotent(req), 0
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true, retryAfter(resp)
+	case http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req), retryAfter(resp)
+	}
+	return false, 0
+}
+
+// retryReason describes why the attempt is retried.
+func retryReason(resp *http.Response, err error) string {
+	switch {
+	case err != nil:
+		return err.Error()
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "throttled by the API server"
+	default:
+		return resp.Status
+	}
+}
+
+// isStreaming checks whether the request is a long running call, like watches, logs, or upgraded
+// connections.
+func isStreaming(req *http.Request) bool {
+	return req.URL.Query().Get("watch") == "true" ||
+		strings.HasSuffix(req.URL.Path, "/log") ||
+		req.Header.Get("Upgrade") != ""
+}
+
+// isIdempotent checks whether the request can be repeated safely.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the amount of time the server asked to wait, zero when not informed.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sleepContext waits for the informed duration, or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelOnClose releases the attempt timeout once the response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and releases the timeout.
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
@@ -0,0 +1,132 @@
+package params
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"k8s.io/klog/v2"
+)
+
+func TestRetryTransport(t *testing.T) {
+	// newServer replies with the informed status codes in sequence, the last one repeated
+	newServer := func(codes ...int) (*httptest.Server, *int32) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := int(atomic.AddInt32(&calls, 1)) - 1
+			if n >= len(codes) {
+				n = len(codes) - 1
+			}
+			if r.URL.Query().Get("sleep") != "" {
+				time.Sleep(200 * time.Millisecond)
+			}
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(codes[n])
+			_, _ = w.Write(body)
+		}))
+		return server, &calls
+	}
+
+	newTransport := func(timeout time.Duration, retries int) (*retryTransport, *[]time.Duration) {
+		waits := []time.Duration{}
+		rt := newRetryTransport(http.DefaultTransport, timeout, retries)
+		rt.sleep = func(_ context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		}
+		return rt, &waits
+	}
+
+	do := func(rt http.RoundTripper, method, url, body string) (*http.Response, error) {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		return rt.RoundTrip(req)
+	}
+
+	t.Run("throttled calls are retried honoring the server", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		server, calls := newServer(http.StatusTooManyRequests, http.StatusCreated)
+		defer server.Close()
+		rt, waits := newTransport(time.Second, 3)
+
+		resp, err := do(rt, http.MethodPost, server.URL, "payload")
+		g.Expect(err).To(gomega.BeNil())
+		defer resp.Body.Close()
+		g.Expect(resp.StatusCode).To(gomega.Equal(http.StatusCreated))
+		body, _ := io.ReadAll(resp.Body)
+		g.Expect(string(body)).To(gomega.Equal("payload"))
+		g.Expect(atomic.LoadInt32(calls)).To(gomega.Equal(int32(2)))
+		g.Expect(*waits).To(gomega.Equal([]time.Duration{2 * time.Second}))
+	})
+
+	t.Run("transient failures are only retried on idempotent calls", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		server, calls := newServer(http.StatusServiceUnavailable)
+		defer server.Close()
+		rt, _ := newTransport(time.Second, 2)
+
+		resp, err := do(rt, http.MethodPost, server.URL, "")
+		g.Expect(err).To(gomega.BeNil())
+		resp.Body.Close()
+		g.Expect(atomic.LoadInt32(calls)).To(gomega.Equal(int32(1)))
+
+		resp, err = do(rt, http.MethodGet, server.URL, "")
+		g.Expect(err).To(gomega.BeNil())
+		resp.Body.Close()
+		g.Expect(resp.StatusCode).To(gomega.Equal(http.StatusServiceUnavailable))
+		g.Expect(atomic.LoadInt32(calls)).To(gomega.Equal(int32(4)))
+	})
+
+	t.Run("each attempt is subject to the timeout, but streaming calls", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		server, calls := newServer(http.StatusOK)
+		defer server.Close()
+		rt, _ := newTransport(50*time.Millisecond, 1)
+
+		_, err := do(rt, http.MethodGet, server.URL+"?sleep=true", "")
+		g.Expect(errors.Is(err, context.DeadlineExceeded)).To(gomega.BeTrue())
+		g.Expect(atomic.LoadInt32(calls)).To(gomega.Equal(int32(2)))
+
+		resp, err := do(rt, http.MethodGet, server.URL+"?sleep=true&watch=true", "")
+		g.Expect(err).To(gomega.BeNil())
+		resp.Body.Close()
+		g.Expect(resp.StatusCode).To(gomega.Equal(http.StatusOK))
+	})
+
+	t.Run("retries are logged with verbosity 4", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		var logs bytes.Buffer
+		fs := flag.NewFlagSet("klog", flag.ContinueOnError)
+		klog.InitFlags(fs)
+		g.Expect(fs.Parse([]string{"-v=4", "-logtostderr=false", "-skip_headers=true"})).To(gomega.Succeed())
+		klog.SetOutput(&logs)
+		defer func() {
+			_ = fs.Parse([]string{"-v=0", "-logtostderr=true", "-skip_headers=false"})
+			klog.SetOutput(io.Discard)
+		}()
+
+		server, _ := newServer(http.StatusTooManyRequests, http.StatusOK)
+		defer server.Close()
+		rt, _ := newTransport(time.Second, 3)
+
+		resp, err := do(rt, http.MethodGet, server.URL+"/api/v1/pods", "")
+		g.Expect(err).To(gomega.BeNil())
+		resp.Body.Close()
+		klog.Flush()
+		g.Expect(logs.String()).To(gomega.ContainSubstring(
+			"Retrying GET /api/v1/pods in 2s (retry 1 of 3): throttled by the API server"))
+	})
+}
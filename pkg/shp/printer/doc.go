@@ -0,0 +1,3 @@
+// Package printer renders the objects of list commands in the output formats informed via
+// "--output", mirroring kubectl, as an alternative to the command's own table.
+package printer
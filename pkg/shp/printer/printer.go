@@ -0,0 +1,116 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shipwright-io/build/pkg/client/clientset/versioned/scheme"
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/kubectl/pkg/cmd/get"
+)
+
+// OutputFlag command-line flag.
+const OutputFlag = "output"
+
+// OutputFlagAnnotation flag annotation marking the output flag registered by Flags, the "output"
+// configuration key only applies to it, since other commands support fewer formats.
+const OutputFlagAnnotation = "shp.io/printer-output"
+
+// Formats output formats supported, besides the command's own table.
+var Formats = []string{
+	"json",
+	"yaml",
+	"name",
+	"jsonpath=",
+	"jsonpath-file=",
+	"custom-columns=",
+	"custom-columns-file=",
+	"go-template=",
+	"go-template-file=",
+}
+
+// Flags output format of the list commands, empty means the command's own table.
+type Flags struct {
+	Output string
+}
+
+// AddFlags registers the output flag.
+func (f *Flags) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(
+		&f.Output,
+		OutputFlag,
+		"o",
+		"",
+		fmt.Sprintf("output format, one of: %s", strings.Join(Formats, "|")),
+	)
+	if err := flags.SetAnnotation(OutputFlag, OutputFlagAnnotation, []string{"true"}); err != nil {
+		panic(err)
+	}
+}
+
+// Table tells whether the command's own table is employed.
+func (f *Flags) Table() bool {
+	return f.Output == ""
+}
+
+// Validate checks the output format is supported, and its template is valid.
+func (f *Flags) Validate() error {
+	if f.Table() {
+		return nil
+	}
+	_, err := f.ToPrinter(false)
+	return err
+}
+
+// ToPrinter instantiates the printer for the informed output format, the custom columns header is
+// omitted when noHeader is set.
+func (f *Flags) ToPrinter(noHeader bool) (printers.ResourcePrinter, error) {
+	var p printers.ResourcePrinter
+	var err error
+	if strings.HasPrefix(f.Output, "custom-columns") {
+		customColumns := get.NewCustomColumnsPrintFlags()
+		customColumns.NoHeaders = noHeader
+		p, err = customColumns.ToPrinter(f.Output)
+	} else {
+		printFlags := genericclioptions.NewPrintFlags("")
+		printFlags.OutputFormat = &f.Output
+		p, err = printFlags.ToPrinter()
+	}
+	if err != nil {
+		if genericclioptions.IsNoCompatiblePrinterError(err) {
+			return nil, fmt.Errorf("unsupported --%s %q, one of: %s", OutputFlag, f.Output, strings.Join(Formats, "|"))
+		}
+		return nil, err
+	}
+	return printers.NewTypeSetter(scheme.Scheme).ToPrinter(p), nil
+}
+
+// PrintList prints the informed list, the kind of its items is set beforehand since the API server
+// omits it on list items. The name format, which doesn't support typed lists, prints each item.
+func (f *Flags) PrintList(list runtime.Object, noHeader bool, out io.Writer) error {
+	p, err := f.ToPrinter(noHeader)
+	if err != nil {
+		return err
+	}
+	err = meta.EachListItem(list, func(obj runtime.Object) error {
+		gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+		if err != nil {
+			return err
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+		if f.Output == "name" {
+			return p.PrintObj(obj, out)
+		}
+		return nil
+	})
+	if err != nil || f.Output == "name" {
+		return err
+	}
+	return p.PrintObj(list, out)
+}
@@ -0,0 +1,72 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPrintList(t *testing.T) {
+	g := o.NewWithT(t)
+
+	list := &buildv1alpha1.BuildList{Items: []buildv1alpha1.Build{{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec:       buildv1alpha1.BuildSpec{Output: buildv1alpha1.Image{Image: "ghcr.io/org/app"}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec:       buildv1alpha1.BuildSpec{Output: buildv1alpha1.Image{Image: "ghcr.io/org/api"}},
+	}}}
+
+	tests := []struct {
+		name     string
+		output   string
+		noHeader bool
+		expected string
+	}{{
+		name:     "custom columns",
+		output:   "custom-columns=NAME:.metadata.name,IMAGE:.spec.output.image",
+		expected: "NAME   IMAGE\napp    ghcr.io/org/app\napi    ghcr.io/org/api\n",
+	}, {
+		name:     "custom columns without header",
+		output:   "custom-columns=NAME:.metadata.name",
+		noHeader: true,
+		expected: "app\napi\n",
+	}, {
+		name:     "jsonpath",
+		output:   "jsonpath={.items[*].spec.output.image}",
+		expected: "ghcr.io/org/app ghcr.io/org/api",
+	}, {
+		name:     "jsonpath with the item kind",
+		output:   "jsonpath={.items[0].kind}",
+		expected: "Build",
+	}, {
+		name:     "name",
+		output:   "name",
+		expected: "build.shipwright.io/app\nbuild.shipwright.io/api\n",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Flags{Output: tt.output}
+			g.Expect(f.Validate()).To(o.Succeed())
+
+			var out bytes.Buffer
+			g.Expect(f.PrintList(list.DeepCopy(), tt.noHeader, &out)).To(o.Succeed())
+			g.Expect(out.String()).To(o.Equal(tt.expected))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect((&Flags{}).Validate()).To(o.Succeed())
+	g.Expect((&Flags{Output: "table"}).Validate()).To(o.MatchError(o.ContainSubstring(`unsupported --output "table"`)))
+	g.Expect((&Flags{Output: "custom-columns="}).Validate()).To(o.HaveOccurred())
+	g.Expect((&Flags{Output: "jsonpath={.items["}).Validate()).To(o.HaveOccurred())
+}
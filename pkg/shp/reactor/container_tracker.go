@@ -0,0 +1,134 @@
+package reactor
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ContainerTimeline the state transitions observed for a single container of the build pod.
+type ContainerTimeline struct {
+	Name     string              // container name
+	Init     bool                // init container
+	Waiting  time.Time           // first moment the container was observed waiting
+	Started  time.Time           // moment the container started running
+	Finished time.Time           // moment the container terminated
+	ExitCode int32               // exit code of the terminated container
+	Reason   string              // reason of the last termination
+	Restarts int32               // amount of container restarts
+	Limits   corev1.ResourceList // resource limits of the container
+}
+
+// Duration amount of time the container was running, zero when it did not finish.
+func (c *ContainerTimeline) Duration() time.Duration {
+	if c.Started.IsZero() || c.Finished.IsZero() {
+		return 0
+	}
+	return c.Finished.Sub(c.Started)
+}
+
+// ContainerTracker records the container state transitions of the pods informed, it is meant to
+// be registered on the PodWatcher via WithContainerTracker.
+type ContainerTracker struct {
+	lock       sync.Mutex
+	now        func() time.Time              // current time, replaceable for testing purposes
+	podName    string                        // name of the pod observed
+	podStarted time.Time                     // moment the kubelet acknowledged the pod
+	containers map[string]*ContainerTimeline // timelines by container name
+	order      []string                      // container names in the order declared on the pod
+}
+
+// OnEvent records the container states of the pod, it never returns error.
+func (c *ContainerTracker) OnEvent(pod *corev1.Pod) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.podName != pod.GetName() {
+		// a new pod resets the timelines, only the latest pod is tracked
+		c.podName = pod.GetName()
+		c.podStarted = time.Time{}
+		c.containers = map[string]*ContainerTimeline{}
+		c.order = []string{}
+	}
+	if pod.Status.StartTime != nil {
+		c.podStarted = pod.Status.StartTime.Time
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		c.timeline(container, true)
+	}
+	for _, container := range pod.Spec.Containers {
+		c.timeline(container, false)
+	}
+
+	now := c.now()
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		t, ok := c.containers[status.Name]
+		if !ok {
+			continue
+		}
+		t.Restarts = status.RestartCount
+		switch {
+		case status.State.Waiting != nil:
+			if t.Waiting.IsZero() {
+				t.Waiting = now
+			}
+		case status.State.Running != nil:
+			t.Started = status.State.Running.StartedAt.Time
+		case status.State.Terminated != nil:
+			terminated := status.State.Terminated
+			t.Started = terminated.StartedAt.Time
+			t.Finished = terminated.FinishedAt.Time
+			t.ExitCode = terminated.ExitCode
+			t.Reason = terminated.Reason
+		}
+	}
+	return nil
+}
+
+// timeline returns the container timeline, registering it when not yet known.
+func (c *ContainerTracker) timeline(container corev1.Container, init bool) *ContainerTimeline {
+	if t, ok := c.containers[container.Name]; ok {
+		return t
+	}
+	t := &ContainerTimeline{Name: container.Name, Init: init, Limits: container.Resources.Limits}
+	c.containers[container.Name] = t
+	c.order = append(c.order, container.Name)
+	return t
+}
+
+// PodName returns the name of the pod tracked.
+func (c *ContainerTracker) PodName() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.podName
+}
+
+// PodStarted returns the moment the pod has been started, zero when unknown.
+func (c *ContainerTracker) PodStarted() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.podStarted
+}
+
+// Containers returns a copy of the container timelines, in the order declared on the pod.
+func (c *ContainerTracker) Containers() []ContainerTimeline {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	timelines := make([]ContainerTimeline, 0, len(c.order))
+	for _, name := range c.order {
+		timelines = append(timelines, *c.containers[name])
+	}
+	return timelines
+}
+
+// NewContainerTracker instantiate a ContainerTracker.
+func NewContainerTracker() *ContainerTracker {
+	return &ContainerTracker{
+		now:        time.Now,
+		containers: map[string]*ContainerTimeline{},
+		order:      []string{},
+	}
+}
@@ -0,0 +1,83 @@
+package reactor
+
+import (
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ContainerTracker(t *testing.T) {
+	g := o.NewWithT(t)
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	tracker := NewContainerTracker()
+	tracker.now = func() time.Time { return now }
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "prepare"}},
+			Containers: []corev1.Container{{
+				Name: "step-build",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "prepare",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(now)}},
+			}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "step-build",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}},
+			}},
+		},
+	}
+	g.Expect(tracker.OnEvent(pod)).To(o.Succeed())
+
+	containers := tracker.Containers()
+	g.Expect(containers).To(o.HaveLen(2))
+	g.Expect(containers[0].Name).To(o.Equal("prepare"))
+	g.Expect(containers[0].Init).To(o.BeTrue())
+	g.Expect(containers[0].Started).To(o.Equal(now))
+	g.Expect(containers[0].Duration()).To(o.BeZero())
+	g.Expect(containers[1].Waiting).To(o.Equal(now))
+
+	// the init container finishes, and the build step fails after being restarted once
+	pod = pod.DeepCopy()
+	pod.Status.StartTime = &metav1.Time{Time: now}
+	pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+		StartedAt:  metav1.NewTime(now),
+		FinishedAt: metav1.NewTime(now.Add(2 * time.Second)),
+	}}
+	pod.Status.ContainerStatuses[0].RestartCount = 1
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+		StartedAt:  metav1.NewTime(now.Add(2 * time.Second)),
+		FinishedAt: metav1.NewTime(now.Add(12 * time.Second)),
+		ExitCode:   1,
+		Reason:     "Error",
+	}}
+	tracker.now = func() time.Time { return now.Add(time.Minute) }
+	g.Expect(tracker.OnEvent(pod)).To(o.Succeed())
+
+	containers = tracker.Containers()
+	g.Expect(tracker.PodStarted()).To(o.Equal(now))
+	g.Expect(containers[0].Duration()).To(o.Equal(2 * time.Second))
+	g.Expect(containers[1].Duration()).To(o.Equal(10 * time.Second))
+	g.Expect(containers[1].Waiting).To(o.Equal(now), "first waiting moment is kept")
+	g.Expect(containers[1].ExitCode).To(o.Equal(int32(1)))
+	g.Expect(containers[1].Restarts).To(o.Equal(int32(1)))
+	g.Expect(containers[1].Limits).To(o.HaveKey(corev1.ResourceCPU))
+
+	// a different pod resets the timelines
+	g.Expect(tracker.OnEvent(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other"}})).To(o.Succeed())
+	g.Expect(tracker.PodName()).To(o.Equal("other"))
+	g.Expect(tracker.Containers()).To(o.BeEmpty())
+}
@@ -0,0 +1,117 @@
+package reactor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ListNamesFn lists the names of the objects present, along with the resource version of the list
+// to watch from.
+type ListNamesFn func(ctx context.Context) ([]string, string, error)
+
+// WatchFn establishes a watch on the objects using the informed list options.
+type WatchFn func(ctx context.Context, listOpts metav1.ListOptions) (watch.Interface, error)
+
+// DeletionWatcher blocks until the informed objects are gone, which may take a while after the
+// deletion is requested, i.e. while finalizers are processed. The objects are listed, and then
+// watched from the list resource version, thus deletions in between are not missed, and listed
+// again whenever the watch is closed.
+type DeletionWatcher struct {
+	listFn  ListNamesFn
+	watchFn WatchFn
+	pending map[string]bool // objects not deleted yet
+}
+
+// WaitForDeletion blocks until all the informed objects are gone, or the context is done, in which
+// case the objects still present are reported on the error.
+func (d *DeletionWatcher) WaitForDeletion(ctx context.Context, names ...string) error {
+	for {
+		present, resourceVersion, err := d.listFn(ctx)
+		if err != nil {
+			return err
+		}
+		d.pending = map[string]bool{}
+		for _, name := range present {
+			d.pending[name] = true
+		}
+		for name := range d.pending {
+			if !contains(names, name) {
+				delete(d.pending, name)
+			}
+		}
+		if len(d.pending) == 0 {
+			return nil
+		}
+
+		w, err := d.watchFn(ctx, metav1.ListOptions{ResourceVersion: resourceVersion})
+		if err != nil {
+			return d.pendingError(err)
+		}
+		done, err := d.watch(ctx, w)
+		w.Stop()
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// watch removes the deleted objects from the pending ones, telling whether all are gone. Returns
+// false without error when the watch needs to be established again.
+func (d *DeletionWatcher) watch(ctx context.Context, w watch.Interface) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, d.pendingError(ctx.Err())
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return false, nil
+			}
+			switch event.Type {
+			case watch.Error:
+				// i.e. the resource version is too old, listing again
+				return false, nil
+			case watch.Deleted:
+				obj, err := meta.Accessor(event.Object)
+				if err != nil {
+					continue
+				}
+				delete(d.pending, obj.GetName())
+				if len(d.pending) == 0 {
+					return true, nil
+				}
+			}
+		}
+	}
+}
+
+// pendingError decorates the error with the objects still present.
+func (d *DeletionWatcher) pendingError(err error) error {
+	names := make([]string, 0, len(d.pending))
+	for name := range d.pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%w, still present: %s", err, strings.Join(names, ", "))
+}
+
+// contains tells whether the name is amongst the informed names.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDeletionWatcher instantiate a DeletionWatcher listing and watching the objects with the
+// informed functions.
+func NewDeletionWatcher(listFn ListNamesFn, watchFn WatchFn) *DeletionWatcher {
+	return &DeletionWatcher{listFn: listFn, watchFn: watchFn}
+}
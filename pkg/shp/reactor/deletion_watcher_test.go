@@ -0,0 +1,84 @@
+package reactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func Test_DeletionWatcher(t *testing.T) {
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name}}
+	}
+
+	t.Run("waits for the deletion events, listing again when the watch is closed", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		listed := 0
+		listFn := func(context.Context) ([]string, string, error) {
+			listed++
+			if listed == 1 {
+				return []string{"a", "b", "other"}, "1", nil
+			}
+			return []string{"b", "other"}, "2", nil
+		}
+		watches := []*watch.FakeWatcher{}
+		versions := []string{}
+		watchFn := func(_ context.Context, listOpts metav1.ListOptions) (watch.Interface, error) {
+			versions = append(versions, listOpts.ResourceVersion)
+			w := watch.NewFake()
+			watches = append(watches, w)
+			first := len(watches) == 1
+			go func() {
+				if first {
+					w.Delete(newPod("other"))
+					w.Stop()
+					return
+				}
+				w.Modify(newPod("b"))
+				w.Delete(newPod("b"))
+			}()
+			return w, nil
+		}
+
+		err := NewDeletionWatcher(listFn, watchFn).WaitForDeletion(context.TODO(), "a", "b")
+		g.Expect(err).To(o.BeNil())
+		g.Expect(versions).To(o.Equal([]string{"1", "2"}))
+	})
+
+	t.Run("objects already gone", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		listFn := func(context.Context) ([]string, string, error) {
+			return []string{"other"}, "1", nil
+		}
+		watchFn := func(context.Context, metav1.ListOptions) (watch.Interface, error) {
+			t.Fatal("unexpected watch")
+			return nil, nil
+		}
+		g.Expect(NewDeletionWatcher(listFn, watchFn).WaitForDeletion(context.TODO(), "a")).To(o.Succeed())
+	})
+
+	t.Run("reports the objects still present on timeout", func(t *testing.T) {
+		g := o.NewWithT(t)
+
+		listFn := func(context.Context) ([]string, string, error) {
+			return []string{"a", "b"}, "1", nil
+		}
+		watchFn := func(context.Context, metav1.ListOptions) (watch.Interface, error) {
+			return watch.NewFake(), nil
+		}
+		ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+		defer cancel()
+
+		err := NewDeletionWatcher(listFn, watchFn).WaitForDeletion(ctx, "a", "b")
+		g.Expect(err).To(o.MatchError(context.DeadlineExceeded))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("still present: a, b")))
+	})
+}
@@ -0,0 +1,170 @@
+package reactor
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EventWatcher a function orchestrator based on watching the Kubernetes Events of a namespace,
+// reacting upon the events accepted by the filter functions, in the order they are received.
+type EventWatcher struct {
+	ctx       context.Context    // global context, when done the event loop ends
+	clientCtx context.Context    // context of the client calls, cancelled on stop as well
+	cancel    context.CancelFunc // cancels the client calls in flight
+	stopCh    chan struct{}      // stops the event loop execution, closed only once
+	stopOnce  sync.Once
+	clientset kubernetes.Interface
+	ns        string
+	watcher   watch.Interface // client watch instance, or the one injected
+	injected  bool            // the watch has been injected, thus it's not re-established
+	listOpts  metav1.ListOptions
+
+	filterFn  []EventFilterFn
+	onEventFn []OnEventFn
+}
+
+// EventFilterFn a given event instance is informed and expects a boolean as return. When false is
+// returned the event is ignored.
+type EventFilterFn func(event *corev1.Event) bool
+
+// OnEventFn when an event is added or modified this method handles it, returning an error aborts
+// the event loop.
+type OnEventFn func(event *corev1.Event) error
+
+// WithFilterFn sets the filter function instance.
+func (e *EventWatcher) WithFilterFn(fn EventFilterFn) *EventWatcher {
+	e.filterFn = append(e.filterFn, fn)
+	return e
+}
+
+// WithOnEventFn sets the function executed when an event is added or modified.
+func (e *EventWatcher) WithOnEventFn(fn OnEventFn) *EventWatcher {
+	e.onEventFn = append(e.onEventFn, fn)
+	return e
+}
+
+// Connect creates the watch based on the list options provided, when the watch has been injected
+// via NewEventWatcherFromWatch only the list options are recorded.
+func (e *EventWatcher) Connect(listOpts metav1.ListOptions) error {
+	e.listOpts = listOpts
+	if e.watcher != nil {
+		return nil
+	}
+	w, err := e.clientset.CoreV1().Events(e.ns).Watch(e.clientCtx, listOpts)
+	if err != nil {
+		return err
+	}
+	e.watcher = w
+	return nil
+}
+
+// handleEvent applies the filter and user informed functions against the informed event.
+func (e *EventWatcher) handleEvent(event *corev1.Event) error {
+	for _, fn := range e.filterFn {
+		if !fn(event) {
+			return nil
+		}
+	}
+	for _, fn := range e.onEventFn {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WaitForCompletion runs the event loop until the context is done, Stop is called or a function
+// returns error. The watch is re-established from the last event seen when the API server closes
+// it, unless the watch has been injected. The watcher is stopped once the loop ends.
+func (e *EventWatcher) WaitForCompletion() error {
+	defer e.Stop()
+
+	for {
+		select {
+		case result, ok := <-e.watcher.ResultChan():
+			if !ok {
+				// stopped watches are not re-established
+				if e.injected || e.clientCtx.Err() != nil {
+					return nil
+				}
+				e.watcher = nil
+				if err := e.Connect(e.listOpts); err != nil {
+					return err
+				}
+				continue
+			}
+			if result.Type != watch.Added && result.Type != watch.Modified {
+				continue
+			}
+			event, ok := result.Object.(*corev1.Event)
+			if !ok {
+				continue
+			}
+			if rv := event.GetResourceVersion(); rv != "" {
+				e.listOpts.ResourceVersion = rv
+			}
+			if err := e.handleEvent(event); err != nil {
+				e.watcher.Stop()
+				return err
+			}
+
+		case <-e.ctx.Done():
+			e.watcher.Stop()
+			return nil
+
+		case <-e.stopCh:
+			e.watcher.Stop()
+			return nil
+		}
+	}
+}
+
+// Start is a convenience method for capturing the use of both Connect and WaitForCompletion
+func (e *EventWatcher) Start(listOpts metav1.ListOptions) error {
+	if err := e.Connect(listOpts); err != nil {
+		return err
+	}
+	return e.WaitForCompletion()
+}
+
+// Stop stops the event loop and cancels the client calls in flight. It's safe to call it more than
+// once, and concurrently with the context cancellation.
+func (e *EventWatcher) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+		e.cancel()
+	})
+}
+
+// Done returns a channel closed once the watcher is stopped, either on demand or when the event loop
+// ends.
+func (e *EventWatcher) Done() <-chan struct{} {
+	return e.stopCh
+}
+
+// NewEventWatcher instantiate EventWatcher event-loop.
+func NewEventWatcher(ctx context.Context, clientset kubernetes.Interface, ns string) *EventWatcher {
+	clientCtx, cancel := context.WithCancel(ctx)
+	return &EventWatcher{
+		ctx:       ctx,
+		clientCtx: clientCtx,
+		cancel:    cancel,
+		clientset: clientset,
+		ns:        ns,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// NewEventWatcherFromWatch instantiate EventWatcher event-loop consuming the events of the informed
+// watch, which ends the event loop when closed.
+func NewEventWatcherFromWatch(ctx context.Context, clientset kubernetes.Interface, ns string, w watch.Interface) *EventWatcher {
+	e := NewEventWatcher(ctx, clientset, ns)
+	e.watcher = w
+	e.injected = true
+	return e
+}
@@ -0,0 +1,63 @@
+package reactor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_EventWatcher(t *testing.T) {
+	g := o.NewWithT(t)
+
+	newEvent := func(name, reason string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: name},
+			Reason:         reason,
+		}
+	}
+
+	w := watch.NewFake()
+	ew := NewEventWatcherFromWatch(context.TODO(), fake.NewSimpleClientset(), metav1.NamespaceDefault, w)
+
+	reasons := []string{}
+	ew.WithFilterFn(func(event *corev1.Event) bool {
+		return event.InvolvedObject.Name != "other"
+	}).WithOnEventFn(func(event *corev1.Event) error {
+		reasons = append(reasons, event.Reason)
+		if event.Reason == "OOMKilled" {
+			return errors.New("stop")
+		}
+		return nil
+	})
+	g.Expect(ew.Connect(metav1.ListOptions{})).To(o.Succeed())
+
+	go func() {
+		w.Add(newEvent("pod", "Scheduled"))
+		w.Add(newEvent("other", "Pulled"))
+		w.Delete(newEvent("pod", "Ignored"))
+		w.Modify(newEvent("pod", "Pulling"))
+		w.Add(newEvent("pod", "OOMKilled"))
+	}()
+
+	err := ew.WaitForCompletion()
+	g.Expect(err).To(o.MatchError("stop"))
+	g.Expect(reasons).To(o.Equal([]string{"Scheduled", "Pulling", "OOMKilled"}))
+}
+
+func Test_EventWatcher_Stop(t *testing.T) {
+	g := o.NewWithT(t)
+
+	ew := NewEventWatcher(context.TODO(), fake.NewSimpleClientset(), metav1.NamespaceDefault)
+	g.Expect(ew.Connect(metav1.ListOptions{})).To(o.Succeed())
+	ew.Stop()
+	ew.Stop()
+	g.Expect(ew.WaitForCompletion()).To(o.Succeed())
+}
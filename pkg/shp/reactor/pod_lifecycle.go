@@ -0,0 +1,317 @@
+package reactor
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// PodStage lifecycle stage of a pod, derived from its status.
+type PodStage string
+
+const (
+	// PodStagePending the pod is not running yet, i.e. its containers are being created.
+	PodStagePending PodStage = "Pending"
+	// PodStageUnschedulable the pod can't be scheduled on any node.
+	PodStageUnschedulable PodStage = "Unschedulable"
+	// PodStageImagePullBackOff the image of a container can't be pulled.
+	PodStageImagePullBackOff PodStage = "ImagePullBackOff"
+	// PodStageRunning the pod is running, but not all containers are ready.
+	PodStageRunning PodStage = "Running"
+	// PodStageReady the pod is running and all containers are ready.
+	PodStageReady PodStage = "Ready"
+	// PodStageCompleted the pod has succeeded.
+	PodStageCompleted PodStage = "Completed"
+	// PodStageFailed the pod has failed.
+	PodStageFailed PodStage = "Failed"
+)
+
+// imagePullFailures waiting reasons of containers which image can't be pulled.
+var imagePullFailures = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// creatingReasons waiting reasons of containers on the regular way of starting up, less relevant
+// than any other reason.
+var creatingReasons = map[string]bool{
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+}
+
+// PendingReason explains why a pod is not running yet.
+type PendingReason struct {
+	Reason    string // short reason, i.e. "Unschedulable" or "ImagePullBackOff"
+	Message   string // human readable details, when any
+	Container string // container the reason belongs to, empty for pod wide reasons
+}
+
+// String renders the reason along with its message and container, when informed.
+func (r PendingReason) String() string {
+	s := r.Reason
+	if r.Container != "" {
+		s += " on container " + r.Container
+	}
+	if r.Message != "" {
+		s += ": " + r.Message
+	}
+	return s
+}
+
+// IsImagePullFailure tells whether the container waiting reason means the image can't be pulled.
+func IsImagePullFailure(reason string) bool {
+	return imagePullFailures[reason]
+}
+
+// PendingReasonOf extracts the reason the pod is not running yet, false when it's not pending. The
+// scheduling failures come first, then the waiting reasons of init containers and containers, the
+// failures being more relevant than the containers still being created. Running pods are pending
+// as well when the image of a container can't be pulled.
+func PendingReasonOf(pod *corev1.Pod) (PendingReason, bool) {
+	switch pod.Status.Phase {
+	case corev1.PodPending:
+	case corev1.PodRunning:
+		reason, found := waitingReasonOf(pod)
+		return reason, found && IsImagePullFailure(reason.Reason)
+	default:
+		return PendingReason{}, false
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+			return PendingReason{Reason: c.Reason, Message: c.Message}, true
+		}
+	}
+	if reason, found := waitingReasonOf(pod); found {
+		return reason, true
+	}
+	if pod.Status.Reason != "" {
+		return PendingReason{Reason: pod.Status.Reason, Message: pod.Status.Message}, true
+	}
+	return PendingReason{Reason: string(corev1.PodPending), Message: pod.Status.Message}, true
+}
+
+// waitingReasonOf returns the most relevant waiting reason of the pod containers, init containers
+// first.
+func waitingReasonOf(pod *corev1.Pod) (PendingReason, bool) {
+	var creating *PendingReason
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		waiting := status.State.Waiting
+		if waiting == nil || waiting.Reason == "" {
+			continue
+		}
+		reason := PendingReason{Reason: waiting.Reason, Message: waiting.Message, Container: status.Name}
+		if !creatingReasons[waiting.Reason] {
+			return reason, true
+		}
+		if creating == nil {
+			creating = &reason
+		}
+	}
+	if creating != nil {
+		return *creating, true
+	}
+	return PendingReason{}, false
+}
+
+// PodStageOf returns the lifecycle stage of the pod, and the reason it's not running yet when the
+// stage is pending, unschedulable or image pull back-off.
+func PodStageOf(pod *corev1.Pod) (PodStage, PendingReason) {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return PodStageCompleted, PendingReason{}
+	case corev1.PodFailed:
+		return PodStageFailed, PendingReason{}
+	}
+	if reason, pending := PendingReasonOf(pod); pending {
+		switch {
+		case reason.Reason == corev1.PodReasonUnschedulable:
+			return PodStageUnschedulable, reason
+		case IsImagePullFailure(reason.Reason):
+			return PodStageImagePullBackOff, reason
+		default:
+			return PodStagePending, reason
+		}
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			return PodStageReady, PendingReason{}
+		}
+	}
+	return PodStageRunning, PendingReason{}
+}
+
+// isPendingStage tells whether the pod is not running yet on the informed stage.
+func isPendingStage(stage PodStage) bool {
+	return stage == PodStagePending || stage == PodStageUnschedulable || stage == PodStageImagePullBackOff
+}
+
+// OnPodPendingFn when the pod is not running yet, informed of the reason. Returning an error aborts
+// the event loop.
+type OnPodPendingFn func(pod *corev1.Pod, reason PendingReason) error
+
+// OnPodTimeoutFn when the pod stays pending longer than the window configured via
+// WithPendingTimeout, informed of the latest reason and the time elapsed since the pod was first
+// seen pending. Returning an error aborts the event loop, otherwise the watcher keeps going.
+type OnPodTimeoutFn func(pod *corev1.Pod, reason PendingReason, elapsed time.Duration) error
+
+// pendingPod a pod observed pending, tracked for the pending timeout.
+type pendingPod struct {
+	pod      *corev1.Pod
+	reason   PendingReason
+	since    time.Time
+	timedOut bool
+}
+
+// WithOnPodPendingFn sets the function executed when the pod is pending, every time the reason
+// changes, including when it's unschedulable or can't pull an image.
+func (p *PodWatcher) WithOnPodPendingFn(fn OnPodPendingFn) *PodWatcher {
+	p.onPodPendingFn = append(p.onPodPendingFn, fn)
+	return p
+}
+
+// WithOnPodUnschedulableFn sets the function executed when the pod can't be scheduled.
+func (p *PodWatcher) WithOnPodUnschedulableFn(fn OnPodPendingFn) *PodWatcher {
+	p.onPodUnschedulableFn = append(p.onPodUnschedulableFn, fn)
+	return p
+}
+
+// WithOnPodImagePullBackOffFn sets the function executed when the image of a container can't be
+// pulled.
+func (p *PodWatcher) WithOnPodImagePullBackOffFn(fn OnPodPendingFn) *PodWatcher {
+	p.onPodImagePullBackOffFn = append(p.onPodImagePullBackOffFn, fn)
+	return p
+}
+
+// WithOnPodReadyFn sets the function executed when all containers of the pod are ready.
+func (p *PodWatcher) WithOnPodReadyFn(fn OnPodEventFn) *PodWatcher {
+	p.onPodReadyFn = append(p.onPodReadyFn, fn)
+	return p
+}
+
+// WithOnPodCompletedFn sets the function executed when the pod succeeds.
+func (p *PodWatcher) WithOnPodCompletedFn(fn OnPodEventFn) *PodWatcher {
+	p.onPodCompletedFn = append(p.onPodCompletedFn, fn)
+	return p
+}
+
+// WithOnPodFailedFn sets the function executed when the pod fails.
+func (p *PodWatcher) WithOnPodFailedFn(fn OnPodEventFn) *PodWatcher {
+	p.onPodFailedFn = append(p.onPodFailedFn, fn)
+	return p
+}
+
+// WithPendingTimeout sets the window a pod may stay pending before the OnPodTimeoutFn functions are
+// called, once per pod, zero disables it.
+func (p *PodWatcher) WithPendingTimeout(d time.Duration) *PodWatcher {
+	p.pendingTimeout = d
+	return p
+}
+
+// WithOnPodTimeoutFn sets the function executed when a pod stays pending longer than the pending
+// timeout.
+func (p *PodWatcher) WithOnPodTimeoutFn(fn OnPodTimeoutFn) *PodWatcher {
+	p.onPodTimeoutFn = append(p.onPodTimeoutFn, fn)
+	return p
+}
+
+// handleLifecycle calls the lifecycle functions when the stage of the pod, or its pending reason,
+// changes since the latest event.
+func (p *PodWatcher) handleLifecycle(pod *corev1.Pod) error {
+	stage, reason := PodStageOf(pod)
+	name := pod.GetName()
+
+	if isPendingStage(stage) {
+		if tracked, ok := p.pendingPods[name]; ok {
+			tracked.pod, tracked.reason = pod, reason
+		} else {
+			p.pendingPods[name] = &pendingPod{pod: pod, reason: reason, since: p.clock.Now()}
+		}
+	} else {
+		delete(p.pendingPods, name)
+	}
+
+	key := string(stage) + "/" + reason.Reason + "/" + reason.Container
+	if p.stages[name] == key {
+		return nil
+	}
+	p.stages[name] = key
+	klog.V(2).Infof("Pod %q is %s %s", name, stage, reason)
+
+	var pendingFns []OnPodPendingFn
+	var eventFns []OnPodEventFn
+	switch stage {
+	case PodStageUnschedulable:
+		pendingFns = append(append(pendingFns, p.onPodPendingFn...), p.onPodUnschedulableFn...)
+	case PodStageImagePullBackOff:
+		pendingFns = append(append(pendingFns, p.onPodPendingFn...), p.onPodImagePullBackOffFn...)
+	case PodStagePending:
+		pendingFns = p.onPodPendingFn
+	case PodStageReady:
+		eventFns = p.onPodReadyFn
+	case PodStageCompleted:
+		eventFns = p.onPodCompletedFn
+	case PodStageFailed:
+		eventFns = p.onPodFailedFn
+	}
+	for _, fn := range pendingFns {
+		if err := fn(pod, reason); err != nil {
+			return err
+		}
+	}
+	for _, fn := range eventFns {
+		if err := fn(pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forgetLifecycle stops tracking the deleted pod.
+func (p *PodWatcher) forgetLifecycle(pod *corev1.Pod) {
+	delete(p.stages, pod.GetName())
+	delete(p.pendingPods, pod.GetName())
+}
+
+// nextPendingTimeout returns the time left until the earliest pending timeout of the pods tracked,
+// false when none is due.
+func (p *PodWatcher) nextPendingTimeout() (time.Duration, bool) {
+	if p.pendingTimeout <= 0 || len(p.onPodTimeoutFn) == 0 {
+		return 0, false
+	}
+	var next time.Duration
+	found := false
+	for _, tracked := range p.pendingPods {
+		if tracked.timedOut {
+			continue
+		}
+		left := p.pendingTimeout - p.clock.Since(tracked.since)
+		if !found || left < next {
+			next, found = left, true
+		}
+	}
+	return next, found
+}
+
+// handlePendingTimeouts calls the OnPodTimeoutFn functions for the pods pending longer than the
+// pending timeout, once per pod.
+func (p *PodWatcher) handlePendingTimeouts() error {
+	for _, tracked := range p.pendingPods {
+		elapsed := p.clock.Since(tracked.since)
+		if tracked.timedOut || elapsed < p.pendingTimeout {
+			continue
+		}
+		tracked.timedOut = true
+		klog.V(2).Infof("Pod %q is pending for %s: %s", tracked.pod.GetName(), elapsed, tracked.reason)
+		for _, fn := range p.onPodTimeoutFn {
+			if err := fn(tracked.pod, tracked.reason, elapsed); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package reactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	testclock "k8s.io/utils/clock/testing"
+)
+
+// lifecyclePod pod on the informed phase, with the conditions and container statuses informed.
+func lifecyclePod(phase corev1.PodPhase, conditions []corev1.PodCondition, statuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"},
+		Status:     corev1.PodStatus{Phase: phase, Conditions: conditions, ContainerStatuses: statuses},
+	}
+}
+
+// waiting container status waiting on the informed reason.
+func waiting(name, reason, message string) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name:  name,
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message}},
+	}
+}
+
+func TestPodStageOf(t *testing.T) {
+	unschedulable := []corev1.PodCondition{{
+		Type:    corev1.PodScheduled,
+		Status:  corev1.ConditionFalse,
+		Reason:  corev1.PodReasonUnschedulable,
+		Message: "0/3 nodes are available",
+	}}
+	ready := []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+
+	tests := []struct {
+		name   string
+		pod    *corev1.Pod
+		stage  PodStage
+		reason PendingReason
+	}{{
+		name:   "pending without details",
+		pod:    lifecyclePod(corev1.PodPending, nil),
+		stage:  PodStagePending,
+		reason: PendingReason{Reason: "Pending"},
+	}, {
+		name:   "unschedulable",
+		pod:    lifecyclePod(corev1.PodPending, unschedulable),
+		stage:  PodStageUnschedulable,
+		reason: PendingReason{Reason: "Unschedulable", Message: "0/3 nodes are available"},
+	}, {
+		name: "image pull failure is more relevant than containers being created",
+		pod: lifecyclePod(corev1.PodPending, nil,
+			waiting("step-source", "ContainerCreating", ""),
+			waiting("step-build", "ImagePullBackOff", "back-off pulling image"),
+		),
+		stage:  PodStageImagePullBackOff,
+		reason: PendingReason{Reason: "ImagePullBackOff", Message: "back-off pulling image", Container: "step-build"},
+	}, {
+		name:   "containers being created",
+		pod:    lifecyclePod(corev1.PodPending, nil, waiting("step-build", "ContainerCreating", "")),
+		stage:  PodStagePending,
+		reason: PendingReason{Reason: "ContainerCreating", Container: "step-build"},
+	}, {
+		name:   "running pod failing to pull an image",
+		pod:    lifecyclePod(corev1.PodRunning, nil, waiting("step-push", "ErrImagePull", "")),
+		stage:  PodStageImagePullBackOff,
+		reason: PendingReason{Reason: "ErrImagePull", Container: "step-push"},
+	}, {
+		name:  "running",
+		pod:   lifecyclePod(corev1.PodRunning, nil),
+		stage: PodStageRunning,
+	}, {
+		name:  "ready",
+		pod:   lifecyclePod(corev1.PodRunning, ready),
+		stage: PodStageReady,
+	}, {
+		name:  "completed",
+		pod:   lifecyclePod(corev1.PodSucceeded, nil),
+		stage: PodStageCompleted,
+	}, {
+		name:  "failed",
+		pod:   lifecyclePod(corev1.PodFailed, nil),
+		stage: PodStageFailed,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			stage, reason := PodStageOf(tt.pod)
+			g.Expect(stage).To(o.Equal(tt.stage))
+			g.Expect(reason).To(o.Equal(tt.reason))
+		})
+	}
+
+	g := o.NewWithT(t)
+	g.Expect(PendingReason{Reason: "ImagePullBackOff", Message: "back-off", Container: "step-build"}.String()).
+		To(o.Equal("ImagePullBackOff on container step-build: back-off"))
+}
+
+func Test_PodWatcher_Lifecycle(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.TODO()
+
+	fakeWatch := watch.NewFake()
+	fakeClock := &timerClock{FakeClock: testclock.NewFakeClock(time.Now()), timerCh: make(chan time.Duration, 4)}
+	pw, err := NewPodWatcherFromWatch(ctx, math.MaxInt64, fake.NewSimpleClientset(), metav1.NamespaceDefault, fakeWatch, fakeClock)
+	g.Expect(err).To(o.BeNil())
+
+	hooksCh := make(chan string, 10)
+	pending := func(hook string) OnPodPendingFn {
+		return func(_ *corev1.Pod, reason PendingReason) error {
+			hooksCh <- fmt.Sprintf("%s %s", hook, reason.Reason)
+			return nil
+		}
+	}
+	event := func(hook string) OnPodEventFn {
+		return func(_ *corev1.Pod) error {
+			hooksCh <- hook
+			return nil
+		}
+	}
+	pw.WithOnPodPendingFn(pending("pending")).
+		WithOnPodUnschedulableFn(pending("unschedulable")).
+		WithOnPodImagePullBackOffFn(pending("image-pull-back-off")).
+		WithOnPodReadyFn(event("ready")).
+		WithOnPodCompletedFn(event("completed")).
+		WithOnPodFailedFn(event("failed")).
+		WithPendingTimeout(time.Minute).
+		WithOnPodTimeoutFn(func(_ *corev1.Pod, reason PendingReason, elapsed time.Duration) error {
+			hooksCh <- fmt.Sprintf("timeout %s %s", reason.Reason, elapsed)
+			return nil
+		})
+
+	g.Expect(pw.Connect(metav1.ListOptions{})).To(o.Succeed())
+	go func() {
+		_, _ = pw.WaitForCompletion()
+	}()
+	defer pw.Stop()
+	// the request timer is created first
+	g.Expect(<-fakeClock.timerCh).To(o.Equal(time.Duration(math.MaxInt64)))
+
+	fakeWatch.Add(lifecyclePod(corev1.PodPending, []corev1.PodCondition{{
+		Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable,
+	}}))
+	g.Eventually(hooksCh).Should(o.Receive(o.Equal("pending Unschedulable")))
+	g.Eventually(hooksCh).Should(o.Receive(o.Equal("unschedulable Unschedulable")))
+	g.Eventually(fakeClock.timerCh).Should(o.Receive(o.Equal(time.Minute)))
+
+	// the pending timeout counts from the first time the pod is seen pending, whatever the reason
+	fakeClock.Step(30 * time.Second)
+	fakeWatch.Modify(lifecyclePod(corev1.PodPending, nil, waiting("step-build", "ImagePullBackOff", "")))
+	g.Eventually(hooksCh).Should(o.Receive(o.Equal("pending ImagePullBackOff")))
+	g.Eventually(hooksCh).Should(o.Receive(o.Equal("image-pull-back-off ImagePullBackOff")))
+	fakeClock.Step(30 * time.Second)
+	g.Eventually(hooksCh).Should(o.Receive(o.Equal("timeout ImagePullBackOff 1m0s")))
+
+	// ready, and repeated stages don't call the hooks again
+	ready := []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	fakeWatch.Modify(lifecyclePod(corev1.PodRunning, ready))
+	g.Eventually(hooksCh).Should(o.Receive(o.Equal("ready")))
+	readyPod := lifecyclePod(corev1.PodRunning, ready)
+	readyPod.Labels = map[string]string{"changed": "true"}
+	fakeWatch.Modify(readyPod)
+	fakeWatch.Modify(lifecyclePod(corev1.PodSucceeded, nil))
+	g.Eventually(hooksCh).Should(o.Receive(o.Equal("completed")))
+	g.Consistently(hooksCh, 10*time.Millisecond).ShouldNot(o.Receive())
+}
+
+func Test_PodWatcher_LifecycleAbort(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.TODO()
+
+	fakeWatch := watch.NewFake()
+	pw, err := NewPodWatcherFromWatch(ctx, math.MaxInt64, fake.NewSimpleClientset(), metav1.NamespaceDefault, fakeWatch, testclock.NewFakeClock(time.Now()))
+	g.Expect(err).To(o.BeNil())
+
+	failedErr := errors.New("build pod has failed")
+	pw.WithOnPodFailedFn(func(_ *corev1.Pod) error {
+		return failedErr
+	})
+	g.Expect(pw.Connect(metav1.ListOptions{})).To(o.Succeed())
+	doneCh := make(chan error, 1)
+	go func() {
+		_, err := pw.WaitForCompletion()
+		doneCh <- err
+	}()
+
+	fakeWatch.Add(lifecyclePod(corev1.PodFailed, nil))
+	g.Eventually(doneCh).Should(o.Receive(o.Equal(failedErr)))
+}
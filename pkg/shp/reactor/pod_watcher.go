@@ -2,13 +2,25 @@ package reactor
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
 	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 )
 
 const (
@@ -19,27 +31,105 @@ const (
 	RequestTimeoutMessage = "request timeout has expired"
 )
 
+// TimeoutError the event loop, or the command, has stopped because either the context deadline or
+// the request timeout expired. It's a deadline exceeded error, classified by errors.Is as such.
+type TimeoutError struct {
+	Message string // either ContextTimeoutMessage or RequestTimeoutMessage
+	Err     error  // error caused by the expired calls, if any
+}
+
+// Error returns the timeout message, followed by the original error when informed.
+func (e *TimeoutError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %v", e.Message, e.Err)
+}
+
+// Unwrap exposes the error caused by the expired calls.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes the timeout a context.DeadlineExceeded error.
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
 // PodWatcher a simple function orchestrator based on watching a given pod and reacting upon the
 // state modifications, should work as a helper to build business logic based on the build POD
 // changes.
 type PodWatcher struct {
-	ctx         context.Context
+	ctx         context.Context    // global context, when done the event loop ends
+	clientCtx   context.Context    // context of the client calls, cancelled on stop as well
+	cancel      context.CancelFunc // cancels the client calls in flight
 	to          time.Duration
-	stopCh      chan bool // stops the event loop execution
-	stopLock    sync.Mutex
-	stopped     bool
-	eventTicker *time.Ticker
+	stopCh      chan struct{} // stops the event loop execution, closed only once
+	stopOnce    sync.Once
+	clock       clock.WithTicker // source of time, timers and tickers
+	eventTicker clock.Ticker
 	clientset   kubernetes.Interface
 	ns          string
-	watcher     watch.Interface // client watch instance
+	watcher     watch.Interface // client watch instance, or the one injected
+	injected    bool            // the watch has been injected, thus it's not re-established
 	listOpts    metav1.ListOptions
 
+	resourceVersion string // latest resource version seen, the watch is re-established from it
+
+	labelSelector labels.Selector // pods watched by labels, on top of the list options
+	fieldSelector fields.Selector // pods watched by fields, on top of the list options
+
+	noEventTimeout time.Duration // window without events before calling onNoEventFn
+	lastEvent      time.Time     // moment the last event was received, or the watch started
+
+	deadline          time.Time     // moment the onDeadlineFn are called, zero disables it
+	remainingInterval time.Duration // interval between the onRemainingFn calls before the deadline
+
+	states         map[string]podState    // state of each pod on its latest event, indexed by name
+	coalesceWindow time.Duration          // window gathering the modifications of a pod, zero disables it
+	pending        map[string]*corev1.Pod // latest modification of each pod within the window
+
+	stages         map[string]string      // lifecycle stage and pending reason of each pod
+	pendingPods    map[string]*pendingPod // pods not running yet, indexed by name
+	pendingTimeout time.Duration          // window a pod may stay pending, zero disables it
+
 	noPodEventsYetFn []NoPodEventsYetFn
+	onNoEventFn      []OnNoEventFn
+	onRemainingFn    []OnRemainingFn
+	onDeadlineFn     []OnDeadlineFn
 	toPodFn          []TimeoutPodFn
 	skipPodFn        []SkipPodFn
 	onPodAddedFn     []OnPodEventFn
 	onPodModifiedFn  []OnPodEventFn
 	onPodDeletedFn   []OnPodEventFn
+
+	onPodPendingFn          []OnPodPendingFn
+	onPodUnschedulableFn    []OnPodPendingFn
+	onPodImagePullBackOffFn []OnPodPendingFn
+	onPodReadyFn            []OnPodEventFn
+	onPodCompletedFn        []OnPodEventFn
+	onPodFailedFn           []OnPodEventFn
+	onPodTimeoutFn          []OnPodTimeoutFn
+}
+
+// podState identifies the state of a pod, the events carrying the same state are duplicates.
+type podState struct {
+	resourceVersion string
+	hash            uint64
+}
+
+// podStateOf hashes the parts of the pod the event handlers react upon: labels, annotations,
+// deletion timestamp and status.
+func podStateOf(pod *corev1.Pod) podState {
+	data, _ := json.Marshal(struct {
+		Labels            map[string]string
+		Annotations       map[string]string
+		DeletionTimestamp *metav1.Time
+		Status            corev1.PodStatus
+	}{pod.Labels, pod.Annotations, pod.DeletionTimestamp, pod.Status})
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	return podState{resourceVersion: pod.GetResourceVersion(), hash: h.Sum64()}
 }
 
 // SkipPodFn a given pod instance is informed and expects a boolean as return. When true is returned
@@ -56,6 +146,44 @@ type TimeoutPodFn func(msg string)
 // where a PodList is also provided in the off chance the Pod completed before the Watch was started.
 type NoPodEventsYetFn func(podList *corev1.PodList)
 
+// OnNoEventFn when no pod events are received within the window configured via WithTimeout, the
+// time elapsed since the last event (or the start of the watch) is informed. Returning an error
+// aborts the event loop, otherwise the watcher keeps waiting for another window.
+type OnNoEventFn func(elapsed time.Duration) error
+
+// OnRemainingFn periodically informed of the time left before the deadline configured via
+// WithDeadline.
+type OnRemainingFn func(remaining time.Duration)
+
+// OnDeadlineFn when the deadline configured via WithDeadline is reached. Returning an error aborts
+// the event loop, otherwise the watcher keeps going.
+type OnDeadlineFn func() error
+
+// WithLabelSelector narrows the pods watched by labels, combined with the label selector of the
+// list options informed on Connect. The events of pods not matching are skipped as well, i.e. the
+// ones of an injected watch.
+func (p *PodWatcher) WithLabelSelector(selector labels.Selector) *PodWatcher {
+	p.labelSelector = selector
+	return p
+}
+
+// WithFieldSelector narrows the pods watched by fields, e.g. "status.phase!=Succeeded", combined
+// with the field selector of the list options informed on Connect. The events of pods not matching
+// are skipped as well, i.e. the ones of an injected watch.
+func (p *PodWatcher) WithFieldSelector(selector fields.Selector) *PodWatcher {
+	p.fieldSelector = selector
+	return p
+}
+
+// selects tells whether the pod matches the label and field selectors, the pods not matching are
+// skipped.
+func (p *PodWatcher) selects(pod *corev1.Pod) bool {
+	if p.labelSelector != nil && !p.labelSelector.Matches(labels.Set(pod.GetLabels())) {
+		return false
+	}
+	return p.fieldSelector == nil || p.fieldSelector.Matches(podFields(pod))
+}
+
 // WithSkipPodFn sets the skip function instance.
 func (p *PodWatcher) WithSkipPodFn(fn SkipPodFn) *PodWatcher {
 	p.skipPodFn = append(p.skipPodFn, fn)
@@ -92,10 +220,100 @@ func (p *PodWatcher) WithNoPodEventsYetFn(fn NoPodEventsYetFn) *PodWatcher {
 	return p
 }
 
-// handleEvent applies user informed functions against informed pod and event.
+// WithTimeout sets the window of time without pod events after which the OnNoEventFn functions are
+// called, zero disables it.
+func (p *PodWatcher) WithTimeout(d time.Duration) *PodWatcher {
+	p.noEventTimeout = d
+	return p
+}
+
+// WithOnNoEventFn sets the function executed when no pod events arrive within the timeout window.
+func (p *PodWatcher) WithOnNoEventFn(fn OnNoEventFn) *PodWatcher {
+	p.onNoEventFn = append(p.onNoEventFn, fn)
+	return p
+}
+
+// WithDeadline sets the moment the OnDeadlineFn functions are called, and the interval between the
+// OnRemainingFn calls until then, a zero interval disables them.
+func (p *PodWatcher) WithDeadline(deadline time.Time, interval time.Duration) *PodWatcher {
+	p.deadline = deadline
+	p.remainingInterval = interval
+	return p
+}
+
+// WithOnRemainingFn sets the function periodically informed of the time left before the deadline.
+func (p *PodWatcher) WithOnRemainingFn(fn OnRemainingFn) *PodWatcher {
+	p.onRemainingFn = append(p.onRemainingFn, fn)
+	return p
+}
+
+// WithOnDeadlineFn sets the function executed when the deadline is reached.
+func (p *PodWatcher) WithOnDeadlineFn(fn OnDeadlineFn) *PodWatcher {
+	p.onDeadlineFn = append(p.onDeadlineFn, fn)
+	return p
+}
+
+// WithCoalesce sets the window gathering the modifications of a pod, only the latest one within
+// the window is handled once it elapses, zero disables it. Added and deleted events are handled
+// right away, after the pending modification of the same pod.
+func (p *PodWatcher) WithCoalesce(window time.Duration) *PodWatcher {
+	p.coalesceWindow = window
+	return p
+}
+
+// observe records the pod state carried by the event, telling whether it's a meaningful change. The
+// events repeating the resource version, or the state, of the latest event seen are duplicates.
+// Pods without resource version, i.e. on fake clients, are compared by state only.
+func (p *PodWatcher) observe(pod *corev1.Pod, eventType watch.EventType) bool {
+	if eventType == watch.Deleted {
+		delete(p.states, pod.GetName())
+		return true
+	}
+	state := podStateOf(pod)
+	last, seen := p.states[pod.GetName()]
+	p.states[pod.GetName()] = state
+	if !seen {
+		return true
+	}
+	if state.resourceVersion != "" && state.resourceVersion == last.resourceVersion {
+		return false
+	}
+	return state.hash != last.hash
+}
+
+// flushPending handles the pending modifications, all of them when no name is informed, in name
+// order.
+func (p *PodWatcher) flushPending(names ...string) error {
+	if len(names) == 0 {
+		for name := range p.pending {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	for _, name := range names {
+		pod, ok := p.pending[name]
+		if !ok {
+			continue
+		}
+		delete(p.pending, name)
+		if err := p.handleEvent(pod, watch.Event{Type: watch.Modified, Object: pod}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithContainerTracker registers the tracker to record the container state transitions of the pods
+// added or modified.
+func (p *PodWatcher) WithContainerTracker(t *ContainerTracker) *PodWatcher {
+	p.onPodAddedFn = append(p.onPodAddedFn, t.OnEvent)
+	p.onPodModifiedFn = append(p.onPodModifiedFn, t.OnEvent)
+	return p
+}
+
+// handleEvent applies user informed functions against informed pod and event, followed by the
+// lifecycle functions.
 func (p *PodWatcher) handleEvent(pod *corev1.Pod, event watch.Event) error {
-	//p.stopLock.Lock()
-	//defer p.stopLock.Unlock()
 	p.eventTicker.Stop()
 	switch event.Type {
 	case watch.Added:
@@ -111,20 +329,44 @@ func (p *PodWatcher) handleEvent(pod *corev1.Pod, event watch.Event) error {
 			}
 		}
 	case watch.Deleted:
+		p.forgetLifecycle(pod)
 		for _, fn := range p.onPodDeletedFn {
 			if err := fn(pod); err != nil {
 				return err
 			}
 		}
+		return nil
 	}
-	return nil
+	return p.handleLifecycle(pod)
 }
 
 // Connect is the first of two methods called by Start, and it handles the creation of the watch based on the list options provided.
 // Separating out Connect from Start helps deal with the fake k8s clients, which are used by the unit tests, and the capabilities of their Watch implementation.
+// When the watch has been injected via NewPodWatcherFromWatch, only the list options are recorded.
+// The bookmark events are requested, keeping the resource version fresh while the pods are quiet.
 func (p *PodWatcher) Connect(listOpts metav1.ListOptions) error {
+	if p.labelSelector != nil && !p.labelSelector.Empty() {
+		listOpts.LabelSelector = joinSelectors(listOpts.LabelSelector, p.labelSelector.String())
+	}
+	if p.fieldSelector != nil && !p.fieldSelector.Empty() {
+		listOpts.FieldSelector = joinSelectors(listOpts.FieldSelector, p.fieldSelector.String())
+	}
+	listOpts.AllowWatchBookmarks = true
 	p.listOpts = listOpts
-	w, err := p.clientset.CoreV1().Pods(p.ns).Watch(p.ctx, listOpts)
+	p.resourceVersion = listOpts.ResourceVersion
+	klog.V(2).Infof("Watching pods on namespace %q with selector %q and field selector %q", p.ns, listOpts.LabelSelector, listOpts.FieldSelector)
+	if p.watcher != nil {
+		return nil
+	}
+	return p.watch()
+}
+
+// watch creates the watch from the latest resource version seen, the list options are kept as
+// informed on Connect, thus listing the pods is not affected.
+func (p *PodWatcher) watch() error {
+	listOpts := p.listOpts
+	listOpts.ResourceVersion = p.resourceVersion
+	w, err := p.clientset.CoreV1().Pods(p.ns).Watch(p.clientCtx, listOpts)
 	if err != nil {
 		return err
 	}
@@ -132,23 +374,150 @@ func (p *PodWatcher) Connect(listOpts metav1.ListOptions) error {
 	return nil
 }
 
+// reconnect re-establishes the watch closed by the API server, from the latest resource version
+// seen. Returns false when the watch must not be re-established, either because it's injected or
+// the watcher is stopping.
+func (p *PodWatcher) reconnect() (bool, error) {
+	if p.injected || p.clientCtx.Err() != nil {
+		return false, nil
+	}
+	klog.V(2).Infof("Re-establishing the pod watch from resource version %q", p.resourceVersion)
+	p.watcher.Stop()
+	return true, p.watch()
+}
+
+// observeResourceVersion records the resource version carried by the event object, including the
+// bookmarks, which carry nothing else.
+func (p *PodWatcher) observeResourceVersion(obj kruntime.Object) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	if rv := accessor.GetResourceVersion(); rv != "" {
+		p.resourceVersion = rv
+	}
+}
+
 // WaitForCompletion is the second of two methods called by Start, and it runs the event loop based on the watch instantiated (by Connect) against informed pod. In case of errors
 // the loop is interrupted.  Separating out WaitForCompletion from Start helps deal with the fake k8s clients, which are used by the unit tests,
-// and the capabilities of their Watch implementation.
+// and the capabilities of their Watch implementation. The watcher is stopped once the loop ends, for whichever reason.
 func (p *PodWatcher) WaitForCompletion() (*corev1.Pod, error) {
+	defer p.Stop()
+
+	// the request timeout applies to the whole event loop, therefore the timer is created only once
+	requestTimer := p.clock.NewTimer(p.to)
+	defer requestTimer.Stop()
+
+	// the no-event window is restarted every time an event arrives, a nil channel blocks forever
+	// when the window is disabled
+	var noEventTimer clock.Timer
+	var noEventCh <-chan time.Time
+	p.lastEvent = p.clock.Now()
+	if p.noEventTimeout > 0 {
+		noEventTimer = p.clock.NewTimer(p.noEventTimeout)
+		defer noEventTimer.Stop()
+		noEventCh = noEventTimer.C()
+	}
+
+	// the deadline fires once, the remaining time is reported periodically until then
+	var deadlineCh, remainingCh <-chan time.Time
+	if !p.deadline.IsZero() {
+		deadlineTimer := p.clock.NewTimer(p.deadline.Sub(p.clock.Now()))
+		defer deadlineTimer.Stop()
+		deadlineCh = deadlineTimer.C()
+		if p.remainingInterval > 0 {
+			remainingTicker := p.clock.NewTicker(p.remainingInterval)
+			defer remainingTicker.Stop()
+			remainingCh = remainingTicker.C()
+		}
+	}
+
+	// the ticker is stopped on the first event, yet stopped tickers of fake clocks keep ticking,
+	// therefore its channel is also discarded
+	eventTickerCh := p.eventTicker.C()
+
+	// the coalesce timer is armed by the first modification gathered, and disarmed when it fires
+	var coalesceTimer clock.Timer
+	var coalesceCh <-chan time.Time
+	defer func() {
+		if coalesceTimer != nil {
+			coalesceTimer.Stop()
+		}
+	}()
+
+	// the pending timer is armed for the earliest pending timeout of the pods not running yet,
+	// after every event handled
+	var pendingTimer clock.Timer
+	var pendingCh <-chan time.Time
+	armPendingTimer := func() {
+		left, due := p.nextPendingTimeout()
+		switch {
+		case !due:
+			pendingCh = nil
+		case pendingTimer == nil:
+			pendingTimer = p.clock.NewTimer(left)
+			pendingCh = pendingTimer.C()
+		default:
+			pendingTimer.Reset(left)
+			pendingCh = pendingTimer.C()
+		}
+	}
+	defer func() {
+		if pendingTimer != nil {
+			pendingTimer.Stop()
+		}
+	}()
+
 	for {
 		select {
 		// handling the regular pod modification events, which should trigger calling event functions
 		// accordinly
-		case event := <-p.watcher.ResultChan():
+		case event, ok := <-p.watcher.ResultChan():
+			if !ok {
+				// the API server closes the watches after a while, thus long builds outlive them
+				reconnected, err := p.reconnect()
+				if err != nil {
+					return nil, err
+				}
+				if !reconnected {
+					return nil, nil
+				}
+				continue
+			}
 			if event.Object == nil {
 				continue
 			}
+			switch event.Type {
+			case watch.Bookmark:
+				p.observeResourceVersion(event.Object)
+				continue
+			case watch.Error:
+				// the resource version is too old, i.e. compacted while the watch was down, thus
+				// watching from the most recent, the pods state already seen is deduplicated
+				if err := kerrors.FromObject(event.Object); kerrors.IsResourceExpired(err) || kerrors.IsGone(err) {
+					klog.V(2).Infof("Pod watch resource version %q expired: %v", p.resourceVersion, err)
+					p.resourceVersion = ""
+					if _, err = p.reconnect(); err != nil {
+						return nil, err
+					}
+				}
+				continue
+			}
 			pod, ok := event.Object.(*corev1.Pod)
 			if !ok {
 				continue
 			}
+			p.observeResourceVersion(pod)
+			p.lastEvent = p.clock.Now()
+			eventTickerCh = nil
+			if noEventTimer != nil {
+				noEventTimer.Reset(p.noEventTimeout)
+			}
 
+			if !p.selects(pod) {
+				klog.V(4).Infof("Skipping %s event of pod %q, not matching the selectors", event.Type, pod.GetName())
+				continue
+			}
 			if len(p.skipPodFn) > 0 {
 				skip := false
 				for _, fn := range p.skipPodFn {
@@ -158,48 +527,135 @@ func (p *PodWatcher) WaitForCompletion() (*corev1.Pod, error) {
 					}
 				}
 				if skip {
+					klog.V(4).Infof("Skipping %s event of pod %q", event.Type, pod.GetName())
 					continue
 				}
 			}
+			if !p.observe(pod, event.Type) {
+				klog.V(4).Infof("Skipping %s event of pod %q, its state is unchanged", event.Type, pod.GetName())
+				continue
+			}
+			klog.V(2).Infof("Pod %q %s, phase %q", pod.GetName(), event.Type, pod.Status.Phase)
+			if p.coalesceWindow > 0 {
+				if event.Type == watch.Modified {
+					p.pending[pod.GetName()] = pod
+					if coalesceCh == nil {
+						if coalesceTimer == nil {
+							coalesceTimer = p.clock.NewTimer(p.coalesceWindow)
+						} else {
+							coalesceTimer.Reset(p.coalesceWindow)
+						}
+						coalesceCh = coalesceTimer.C()
+					}
+					continue
+				}
+				if err := p.flushPending(pod.GetName()); err != nil {
+					return pod, err
+				}
+			}
 			if err := p.handleEvent(pod, event); err != nil {
+				klog.V(2).Infof("Stopping the pod watcher on the %s event of pod %q: %v", event.Type, pod.GetName(), err)
 				return pod, err
 			}
+			armPendingTimer()
+		// the coalesce window elapsed, handling the latest modification of each pod
+		case <-coalesceCh:
+			coalesceCh = nil
+			if err := p.flushPending(); err != nil {
+				p.watcher.Stop()
+				return nil, err
+			}
+			armPendingTimer()
+
+		// a pod has been pending longer than the pending timeout, the registered functions decide
+		// whether to abort the event loop
+		case <-pendingCh:
+			pendingCh = nil
+			if err := p.handlePendingTimeouts(); err != nil {
+				klog.V(2).Infof("Stopping the pod watcher on the pending timeout: %v", err)
+				p.watcher.Stop()
+				return nil, err
+			}
+			armPendingTimer()
+
 		// watching over global context, when done is informed on the context it needs to reflect on
-		// the event loop as well.
+		// the event loop as well. An expired deadline is a timeout, a cancellation is not an error.
 		case <-p.ctx.Done():
+			klog.V(2).Infof("Stopping the pod watcher, %s", ContextTimeoutMessage)
 			p.watcher.Stop()
 			for _, fn := range p.toPodFn {
 				fn(ContextTimeoutMessage)
 			}
+			if errors.Is(p.ctx.Err(), context.DeadlineExceeded) {
+				return nil, &TimeoutError{Message: ContextTimeoutMessage}
+			}
 			return nil, nil
 
 		// handle k8s --request-timeout setting, converted to time.Duration, that is passed down to PodWatcher;
-		// if we have exceeded it, we exit
-		case <-time.After(p.to):
+		// if we have exceeded it, we exit with a timeout error
+		case <-requestTimer.C():
+			klog.V(2).Infof("Stopping the pod watcher, %s (%s)", RequestTimeoutMessage, p.to)
 			p.watcher.Stop()
 			for _, fn := range p.toPodFn {
 				fn(RequestTimeoutMessage)
 			}
-			return nil, nil
+			return nil, &TimeoutError{Message: RequestTimeoutMessage}
 
 		// deal with case where a lack of any pod event means there is some sort of issue;
 		// we let the called function decide whether to stop the watch
 		// NOTE: a k8s event watch coupled with our pod watch proved problematic with unit tests; also, with
 		// a lot of the relevant constants in github.com/k8s/k8s, which is a hassle to vendor in, prototypes
 		// felt fragile
-		case <-p.eventTicker.C:
+		case <-eventTickerCh:
 			// for the narrow edge case where the final event for the Pod occurs before the
 			// watch can be established, we list the pods and if we find any, call noPodEventsYetFn.
 			// Reminder, if we do get events, this ticker is stopped/cancelled
-			podList, _ := p.clientset.CoreV1().Pods(p.ns).List(p.ctx, p.listOpts)
+			klog.V(2).Info("No pod events yet, listing the pods")
+			podList, _ := p.clientset.CoreV1().Pods(p.ns).List(p.clientCtx, p.listOpts)
 			// no need to return the error here, calling the no pod events listener is more important and it
 			// more than likely will treat a nil/empty PodList the same regardless
 			for _, fn := range p.noPodEventsYetFn {
 				fn(podList)
 			}
 
+		// no pod events within the configured window, the registered functions decide whether to keep
+		// waiting, or to abort the event loop
+		case <-noEventCh:
+			elapsed := p.clock.Since(p.lastEvent)
+			klog.V(2).Infof("No pod events for %s", elapsed)
+			for _, fn := range p.onNoEventFn {
+				if err := fn(elapsed); err != nil {
+					klog.V(2).Infof("Stopping the pod watcher without pod events: %v", err)
+					p.watcher.Stop()
+					return nil, err
+				}
+			}
+			noEventTimer.Reset(p.noEventTimeout)
+
+		// periodically informing the time left before the deadline
+		case <-remainingCh:
+			if remaining := p.deadline.Sub(p.clock.Now()); remaining > 0 {
+				for _, fn := range p.onRemainingFn {
+					fn(remaining)
+				}
+			}
+
+		// the deadline has been reached, the registered functions decide whether to abort the event
+		// loop, otherwise the watcher keeps going
+		case <-deadlineCh:
+			klog.V(2).Infof("Deadline %s reached", p.deadline.Format(time.RFC3339))
+			remainingCh = nil
+			for _, fn := range p.onDeadlineFn {
+				if err := fn(); err != nil {
+					klog.V(2).Infof("Stopping the pod watcher on the deadline: %v", err)
+					p.watcher.Stop()
+					return nil, err
+				}
+			}
+
 		// watching over stop channel to stop the event loop on demand.
 		case <-p.stopCh:
+			klog.V(2).Info("Stopping the pod watcher on demand")
 			p.watcher.Stop()
 			return nil, nil
 		}
@@ -215,17 +671,20 @@ func (p *PodWatcher) Start(listOpts metav1.ListOptions) (*corev1.Pod, error) {
 	return p.WaitForCompletion()
 }
 
-// Stop closes the stop channel, and stops the execution loop.
+// Stop stops the event loop and cancels the client calls in flight. It's safe to call it more than
+// once, and concurrently with the context cancellation.
 func (p *PodWatcher) Stop() {
-	// employ sync because of observed 'panic: close of closed channel' when running build run log following
-	// along with canceling of builds
-	p.stopLock.Lock()
-	defer p.stopLock.Unlock()
-	p.eventTicker.Stop()
-	if !p.stopped {
+	p.stopOnce.Do(func() {
+		p.eventTicker.Stop()
 		close(p.stopCh)
-		p.stopped = true
-	}
+		p.cancel()
+	})
+}
+
+// Done returns a channel closed once the watcher is stopped, either on demand or when the event loop
+// ends, allowing to compose it with other event sources.
+func (p *PodWatcher) Done() <-chan struct{} {
+	return p.stopCh
 }
 
 // NewPodWatcher instantiate PodWatcher event-loop.
@@ -234,7 +693,56 @@ func NewPodWatcher(
 	timeout time.Duration,
 	clientset kubernetes.Interface,
 	ns string,
+) (*PodWatcher, error) {
+	return NewPodWatcherWithClock(ctx, timeout, clientset, ns, clock.RealClock{})
+}
+
+// NewPodWatcherWithClock instantiate PodWatcher event-loop using the informed clock for the request
+// timeout, the no-event window and the no pod events yet ticker, a fake clock allows driving them
+// deterministically.
+func NewPodWatcherWithClock(
+	ctx context.Context,
+	timeout time.Duration,
+	clientset kubernetes.Interface,
+	ns string,
+	clk clock.WithTicker,
 ) (*PodWatcher, error) {
 	//TODO don't think the have not received events yet ticker needs to be tunable, but leaving a TODO for now while we get feedback
-	return &PodWatcher{ctx: ctx, to: timeout, ns: ns, clientset: clientset, eventTicker: time.NewTicker(1 * time.Second), stopCh: make(chan bool), stopLock: sync.Mutex{}}, nil
+	clientCtx, cancel := context.WithCancel(ctx)
+	return &PodWatcher{
+		ctx:         ctx,
+		clientCtx:   clientCtx,
+		cancel:      cancel,
+		to:          timeout,
+		ns:          ns,
+		clientset:   clientset,
+		clock:       clk,
+		eventTicker: clk.NewTicker(1 * time.Second),
+		stopCh:      make(chan struct{}),
+		states:      map[string]podState{},
+		pending:     map[string]*corev1.Pod{},
+		stages:      map[string]string{},
+		pendingPods: map[string]*pendingPod{},
+	}, nil
+}
+
+// NewPodWatcherFromWatch instantiate PodWatcher event-loop consuming the events of the informed
+// watch, instead of watching the pods via the clientset, which is still employed to list pods when
+// no events arrive. Combined with watch.NewFake and a fake clock, the event loop is fully driven by
+// the caller.
+func NewPodWatcherFromWatch(
+	ctx context.Context,
+	timeout time.Duration,
+	clientset kubernetes.Interface,
+	ns string,
+	w watch.Interface,
+	clk clock.WithTicker,
+) (*PodWatcher, error) {
+	pw, err := NewPodWatcherWithClock(ctx, timeout, clientset, ns, clk)
+	if err != nil {
+		return nil, err
+	}
+	pw.watcher = w
+	pw.injected = true
+	return pw, nil
 }
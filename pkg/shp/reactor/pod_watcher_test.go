@@ -2,12 +2,21 @@ package reactor
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	fakekubetesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
+	testclock "k8s.io/utils/clock/testing"
 
 	o "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -29,8 +38,12 @@ func Test_PodWatcher_RequestTimeout(t *testing.T) {
 		called = true
 	})
 
-	pw.Start(metav1.ListOptions{})
+	_, err = pw.Start(metav1.ListOptions{})
 	g.Expect(called).To(o.BeTrue())
+	var timeoutErr *TimeoutError
+	g.Expect(errors.As(err, &timeoutErr)).To(o.BeTrue())
+	g.Expect(timeoutErr.Message).To(o.Equal(RequestTimeoutMessage))
+	g.Expect(errors.Is(err, context.DeadlineExceeded)).To(o.BeTrue())
 }
 
 func Test_PodWatcher_ContextTimeout(t *testing.T) {
@@ -49,8 +62,12 @@ func Test_PodWatcher_ContextTimeout(t *testing.T) {
 		called = true
 	})
 
-	pw.Start(metav1.ListOptions{})
+	_, err = pw.Start(metav1.ListOptions{})
 	g.Expect(called).To(o.BeTrue())
+	var timeoutErr *TimeoutError
+	g.Expect(errors.As(err, &timeoutErr)).To(o.BeTrue())
+	g.Expect(timeoutErr.Message).To(o.Equal(ContextTimeoutMessage))
+	g.Expect(errors.Is(err, context.DeadlineExceeded)).To(o.BeTrue())
 }
 
 func Test_PodWatcher_NotCalledYet(t *testing.T) {
@@ -77,7 +94,7 @@ func Test_PodWatcher_NotCalledYet(t *testing.T) {
 	// for errors
 	go func() {
 		_, err := pw.Start(metav1.ListOptions{})
-		<-pw.stopCh
+		<-pw.Done()
 		g.Expect(err).To(o.BeNil())
 		eventsDoneCh <- true
 	}()
@@ -131,7 +148,7 @@ func Test_PodWatcher_NotCalledYet_AllEventsBeforeWatchStart(t *testing.T) {
 	// for errors
 	go func() {
 		_, err := pw.Start(metav1.ListOptions{})
-		<-pw.stopCh
+		<-pw.Done()
 		g.Expect(err).To(o.BeNil())
 		eventsDoneCh <- true
 	}()
@@ -189,7 +206,7 @@ func Test_PodWatcherEvents(t *testing.T) {
 	// for errors
 	go func() {
 		_, err := pw.WaitForCompletion()
-		<-pw.stopCh
+		<-pw.Done()
 		g.Expect(err).To(o.BeNil())
 	}()
 
@@ -244,3 +261,419 @@ func validateEventChannelData(got, expected, verb string, ok bool, t *testing.T)
 		t.Fatalf("test channel %s value was %s instead of %s", verb, got, expected)
 	}
 }
+
+func Test_PodWatcher_OnNoEvent(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.TODO()
+
+	clientset := fake.NewSimpleClientset()
+
+	pw, err := NewPodWatcher(ctx, math.MaxInt64, clientset, metav1.NamespaceDefault)
+	g.Expect(err).To(o.BeNil())
+
+	// the first window keeps waiting, the second aborts the event loop
+	calls := 0
+	abortErr := errors.New("no build pod")
+	pw.WithTimeout(10 * time.Millisecond).WithOnNoEventFn(func(elapsed time.Duration) error {
+		calls++
+		g.Expect(elapsed >= 10*time.Millisecond).To(o.BeTrue())
+		if calls > 1 {
+			return abortErr
+		}
+		return nil
+	})
+
+	_, err = pw.Start(metav1.ListOptions{})
+	g.Expect(err).To(o.Equal(abortErr))
+	g.Expect(calls).To(o.Equal(2))
+}
+
+func Test_PodWatcher_FakeWatchAndClock(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.TODO()
+
+	fakeWatch := watch.NewFake()
+	fakeClock := testclock.NewFakeClock(time.Now())
+
+	pw, err := NewPodWatcherFromWatch(ctx, math.MaxInt64, fake.NewSimpleClientset(), metav1.NamespaceDefault, fakeWatch, fakeClock)
+	g.Expect(err).To(o.BeNil())
+
+	addedCh := make(chan string, 1)
+	noPodEventsYet := 0
+	abortErr := errors.New("no build pod")
+	var elapsed time.Duration
+	pw.WithOnPodAddedFn(func(pod *corev1.Pod) error {
+		addedCh <- pod.GetName()
+		return nil
+	}).WithNoPodEventsYetFn(func(_ *corev1.PodList) {
+		noPodEventsYet++
+	}).WithTimeout(time.Minute).WithOnNoEventFn(func(d time.Duration) error {
+		elapsed = d
+		return abortErr
+	})
+
+	g.Expect(pw.Connect(metav1.ListOptions{})).To(o.Succeed())
+	doneCh := make(chan error, 1)
+	go func() {
+		_, err := pw.WaitForCompletion()
+		doneCh <- err
+	}()
+
+	// the event is delivered by the injected watch, stopping the no pod events yet ticker
+	fakeWatch.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"}})
+	g.Expect(<-addedCh).To(o.Equal("pod"))
+
+	// nothing happens until the clock moves past the no-event window
+	g.Eventually(fakeClock.HasWaiters).Should(o.BeTrue())
+	fakeClock.Step(30 * time.Second)
+	g.Consistently(doneCh, 10*time.Millisecond).ShouldNot(o.Receive())
+
+	fakeClock.Step(30 * time.Second)
+	g.Eventually(doneCh).Should(o.Receive(o.Equal(abortErr)))
+	g.Expect(elapsed).To(o.Equal(time.Minute))
+	g.Expect(noPodEventsYet).To(o.Equal(0))
+}
+
+// tickerClock fake clock informing the interval of the tickers created, thus the test knows when the
+// event loop is ready.
+type tickerClock struct {
+	*testclock.FakeClock
+	tickerCh chan time.Duration
+}
+
+func (c *tickerClock) NewTicker(d time.Duration) clock.Ticker {
+	t := c.FakeClock.NewTicker(d)
+	c.tickerCh <- d
+	return t
+}
+
+func Test_PodWatcher_Deadline(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.TODO()
+
+	fakeClock := &tickerClock{FakeClock: testclock.NewFakeClock(time.Now()), tickerCh: make(chan time.Duration, 2)}
+	pw, err := NewPodWatcherFromWatch(ctx, math.MaxInt64, fake.NewSimpleClientset(), metav1.NamespaceDefault, watch.NewFake(), fakeClock)
+	g.Expect(err).To(o.BeNil())
+
+	remainingCh := make(chan time.Duration, 3)
+	deadlineErr := errors.New("deadline reached")
+	pw.WithDeadline(fakeClock.Now().Add(3*time.Minute), time.Minute).WithOnRemainingFn(func(remaining time.Duration) {
+		remainingCh <- remaining
+	}).WithOnDeadlineFn(func() error {
+		return deadlineErr
+	})
+
+	g.Expect(pw.Connect(metav1.ListOptions{})).To(o.Succeed())
+	doneCh := make(chan error, 1)
+	go func() {
+		_, err := pw.WaitForCompletion()
+		doneCh <- err
+	}()
+
+	// the remaining time is reported on every interval until the deadline
+	g.Expect(<-fakeClock.tickerCh).To(o.Equal(time.Second))
+	g.Eventually(fakeClock.tickerCh).Should(o.Receive(o.Equal(time.Minute)))
+	fakeClock.Step(time.Minute)
+	g.Eventually(remainingCh).Should(o.Receive(o.Equal(2 * time.Minute)))
+	fakeClock.Step(time.Minute)
+	g.Eventually(remainingCh).Should(o.Receive(o.Equal(time.Minute)))
+	g.Expect(doneCh).ToNot(o.Receive())
+
+	fakeClock.Step(time.Minute)
+	g.Eventually(doneCh).Should(o.Receive(o.Equal(deadlineErr)))
+	g.Expect(remainingCh).ToNot(o.Receive())
+}
+
+func Test_PodWatcher_Deduplication(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.TODO()
+
+	fakeWatch := watch.NewFake()
+	pw, err := NewPodWatcherFromWatch(ctx, math.MaxInt64, fake.NewSimpleClientset(), metav1.NamespaceDefault, fakeWatch, testclock.NewFakeClock(time.Now()))
+	g.Expect(err).To(o.BeNil())
+
+	eventsCh := make(chan string, 10)
+	record := func(verb string) OnPodEventFn {
+		return func(pod *corev1.Pod) error {
+			eventsCh <- fmt.Sprintf("%s %s %s", verb, pod.GetResourceVersion(), pod.Status.Phase)
+			return nil
+		}
+	}
+	pw.WithOnPodAddedFn(record("added")).WithOnPodModifiedFn(record("modified")).WithOnPodDeletedFn(record("deleted"))
+
+	g.Expect(pw.Connect(metav1.ListOptions{})).To(o.Succeed())
+	go func() {
+		_, _ = pw.WaitForCompletion()
+	}()
+	defer pw.Stop()
+
+	pod := func(resourceVersion string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod", ResourceVersion: resourceVersion},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	fakeWatch.Add(pod("1", corev1.PodPending))
+	// the same resource version, and the same state on another resource version, are duplicates
+	fakeWatch.Modify(pod("1", corev1.PodPending))
+	fakeWatch.Modify(pod("2", corev1.PodPending))
+	fakeWatch.Modify(pod("3", corev1.PodRunning))
+	fakeWatch.Modify(pod("3", corev1.PodRunning))
+	fakeWatch.Delete(pod("4", corev1.PodRunning))
+
+	events := []string{}
+	for i := 0; i < 3; i++ {
+		events = append(events, <-eventsCh)
+	}
+	g.Expect(events).To(o.Equal([]string{"added 1 Pending", "modified 3 Running", "deleted 4 Running"}))
+	g.Consistently(eventsCh, 10*time.Millisecond).ShouldNot(o.Receive())
+}
+
+// timerClock fake clock informing the duration of the timers created, thus the test knows when the
+// event loop has armed them.
+type timerClock struct {
+	*testclock.FakeClock
+	timerCh chan time.Duration
+}
+
+func (c *timerClock) NewTimer(d time.Duration) clock.Timer {
+	t := c.FakeClock.NewTimer(d)
+	c.timerCh <- d
+	return t
+}
+
+func Test_PodWatcher_Coalesce(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.TODO()
+
+	fakeWatch := watch.NewFake()
+	fakeClock := &timerClock{FakeClock: testclock.NewFakeClock(time.Now()), timerCh: make(chan time.Duration, 2)}
+	pw, err := NewPodWatcherFromWatch(ctx, math.MaxInt64, fake.NewSimpleClientset(), metav1.NamespaceDefault, fakeWatch, fakeClock)
+	g.Expect(err).To(o.BeNil())
+
+	eventsCh := make(chan string, 10)
+	record := func(verb string) OnPodEventFn {
+		return func(pod *corev1.Pod) error {
+			eventsCh <- fmt.Sprintf("%s %s", verb, pod.GetResourceVersion())
+			return nil
+		}
+	}
+	pw.WithCoalesce(time.Second).
+		WithOnPodAddedFn(record("added")).
+		WithOnPodModifiedFn(record("modified")).
+		WithOnPodDeletedFn(record("deleted"))
+
+	g.Expect(pw.Connect(metav1.ListOptions{})).To(o.Succeed())
+	go func() {
+		_, _ = pw.WaitForCompletion()
+	}()
+	defer pw.Stop()
+	// the request timer is created first
+	g.Expect(<-fakeClock.timerCh).To(o.Equal(time.Duration(math.MaxInt64)))
+
+	pod := func(resourceVersion string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod", ResourceVersion: resourceVersion},
+			Status:     corev1.PodStatus{Message: resourceVersion},
+		}
+	}
+
+	// the added event is handled right away, while the modifications wait for the window
+	fakeWatch.Add(pod("1"))
+	g.Eventually(eventsCh).Should(o.Receive(o.Equal("added 1")))
+	fakeWatch.Modify(pod("2"))
+	g.Eventually(fakeClock.timerCh).Should(o.Receive(o.Equal(time.Second)))
+	fakeWatch.Modify(pod("3"))
+	g.Consistently(eventsCh, 10*time.Millisecond).ShouldNot(o.Receive())
+
+	fakeClock.Step(time.Second)
+	g.Eventually(eventsCh).Should(o.Receive(o.Equal("modified 3")))
+
+	// the pending modification is handled before the deletion
+	fakeWatch.Modify(pod("4"))
+	fakeWatch.Delete(pod("5"))
+	g.Eventually(eventsCh).Should(o.Receive(o.Equal("modified 4")))
+	g.Eventually(eventsCh).Should(o.Receive(o.Equal("deleted 5")))
+}
+
+func Test_PodWatcher_StopAndCancel(t *testing.T) {
+	g := o.NewWithT(t)
+	goroutines := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	pw, err := NewPodWatcherFromWatch(ctx, math.MaxInt64, fake.NewSimpleClientset(), metav1.NamespaceDefault,
+		watch.NewFake(), testclock.NewFakeClock(time.Now()))
+	g.Expect(err).To(o.BeNil())
+	g.Expect(pw.Connect(metav1.ListOptions{})).To(o.Succeed())
+
+	doneCh := make(chan error, 1)
+	go func() {
+		_, err := pw.WaitForCompletion()
+		doneCh <- err
+	}()
+
+	// stopping and cancelling concurrently, more than once, must not panic
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			pw.Stop()
+		}()
+		go func() {
+			defer wg.Done()
+			cancel()
+		}()
+	}
+	wg.Wait()
+
+	g.Eventually(doneCh).Should(o.Receive(o.BeNil()))
+	g.Expect(pw.Done()).To(o.BeClosed())
+	g.Expect(pw.clientCtx.Err()).To(o.Equal(context.Canceled))
+	g.Eventually(runtime.NumGoroutine).Should(o.BeNumerically("<=", goroutines))
+}
+
+func Test_PodWatcher_DoneWhenLoopEnds(t *testing.T) {
+	g := o.NewWithT(t)
+
+	fakeWatch := watch.NewFake()
+	pw, err := NewPodWatcherFromWatch(context.TODO(), math.MaxInt64, fake.NewSimpleClientset(), metav1.NamespaceDefault,
+		fakeWatch, testclock.NewFakeClock(time.Now()))
+	g.Expect(err).To(o.BeNil())
+	pw.WithOnPodAddedFn(func(_ *corev1.Pod) error {
+		return errors.New("abort")
+	})
+	g.Expect(pw.Connect(metav1.ListOptions{})).To(o.Succeed())
+	go func() {
+		_, _ = pw.WaitForCompletion()
+	}()
+
+	g.Consistently(pw.Done(), 10*time.Millisecond).ShouldNot(o.BeClosed())
+	fakeWatch.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"}})
+	g.Eventually(pw.Done()).Should(o.BeClosed())
+	pw.Stop()
+}
+
+func Test_PodWatcher_Selectors(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.TODO()
+
+	fakeWatch := watch.NewFake()
+	pw, err := NewPodWatcherFromWatch(ctx, math.MaxInt64, fake.NewSimpleClientset(), metav1.NamespaceDefault, fakeWatch, testclock.NewFakeClock(time.Now()))
+	g.Expect(err).To(o.BeNil())
+
+	addedCh := make(chan string, 3)
+	pw.WithLabelSelector(BuildRunPodSelector("my-app-run")).
+		WithFieldSelector(fields.OneTermNotEqualSelector("status.phase", string(corev1.PodSucceeded))).
+		WithOnPodAddedFn(func(pod *corev1.Pod) error {
+			addedCh <- pod.GetName()
+			return nil
+		})
+
+	// the selectors are combined with the ones of the list options
+	g.Expect(pw.Connect(metav1.ListOptions{LabelSelector: "team=platform"})).To(o.Succeed())
+	g.Expect(pw.listOpts.LabelSelector).To(o.Equal("team=platform,buildrun.shipwright.io/name=my-app-run"))
+	g.Expect(pw.listOpts.FieldSelector).To(o.Equal("status.phase!=Succeeded"))
+
+	go func() {
+		_, _ = pw.WaitForCompletion()
+	}()
+	defer pw.Stop()
+
+	pod := func(name, buildRun string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+				Labels:    map[string]string{"buildrun.shipwright.io/name": buildRun},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	// the pods of other BuildRuns, and the ones already succeeded, are skipped
+	fakeWatch.Add(pod("other", "other-run", corev1.PodPending))
+	fakeWatch.Add(pod("done", "my-app-run", corev1.PodSucceeded))
+	fakeWatch.Add(pod("build-pod", "my-app-run", corev1.PodPending))
+	g.Eventually(addedCh).Should(o.Receive(o.Equal("build-pod")))
+	g.Consistently(addedCh, 10*time.Millisecond).ShouldNot(o.Receive())
+}
+
+func Test_BuildRunPodListOptions(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(BuildRunPodListOptions("my-app-run")).To(o.Equal(metav1.ListOptions{
+		LabelSelector: "buildrun.shipwright.io/name=my-app-run",
+	}))
+}
+
+func Test_PodWatcher_Bookmarks(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.TODO()
+
+	pod := func(resourceVersion string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod", ResourceVersion: resourceVersion},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	// each watch established is handed over to the test, along with the resource version requested
+	type established struct {
+		watch           *watch.FakeWatcher
+		resourceVersion string
+	}
+	watchesCh := make(chan established, 3)
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependWatchReactor("pods", func(action fakekubetesting.Action) (bool, watch.Interface, error) {
+		w := watch.NewFake()
+		watchesCh <- established{watch: w, resourceVersion: action.(fakekubetesting.WatchActionImpl).GetWatchRestrictions().ResourceVersion}
+		return true, w, nil
+	})
+
+	pw, err := NewPodWatcherWithClock(ctx, math.MaxInt64, clientset, metav1.NamespaceDefault, testclock.NewFakeClock(time.Now()))
+	g.Expect(err).To(o.BeNil())
+	eventsCh := make(chan string, 10)
+	pw.WithOnPodAddedFn(func(pod *corev1.Pod) error {
+		eventsCh <- "added " + string(pod.Status.Phase)
+		return nil
+	}).WithOnPodModifiedFn(func(pod *corev1.Pod) error {
+		eventsCh <- "modified " + string(pod.Status.Phase)
+		return nil
+	})
+
+	g.Expect(pw.Connect(metav1.ListOptions{ResourceVersion: "1"})).To(o.Succeed())
+	g.Expect(pw.listOpts.AllowWatchBookmarks).To(o.BeTrue())
+	doneCh := make(chan error, 1)
+	go func() {
+		_, err := pw.WaitForCompletion()
+		doneCh <- err
+	}()
+	defer pw.Stop()
+
+	first := <-watchesCh
+	g.Expect(first.resourceVersion).To(o.Equal("1"))
+	first.watch.Add(pod("10", corev1.PodPending))
+	g.Eventually(eventsCh).Should(o.Receive(o.Equal("added Pending")))
+
+	// the bookmarks only carry the resource version, the watch is re-established from it
+	first.watch.Action(watch.Bookmark, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "15"}})
+	first.watch.Stop()
+	var second established
+	g.Eventually(watchesCh).Should(o.Receive(&second))
+	g.Expect(second.resourceVersion).To(o.Equal("15"))
+	g.Expect(eventsCh).ToNot(o.Receive())
+
+	// the expired resource version is dropped, watching from the most recent instead, where the
+	// pod state already seen is not handled again
+	second.watch.Error(&kerrors.NewResourceExpired("too old resource version: 15 (20)").ErrStatus)
+	var third established
+	g.Eventually(watchesCh).Should(o.Receive(&third))
+	g.Expect(third.resourceVersion).To(o.BeEmpty())
+	third.watch.Add(pod("10", corev1.PodPending))
+	third.watch.Modify(pod("21", corev1.PodRunning))
+	g.Eventually(eventsCh).Should(o.Receive(o.Equal("modified Running")))
+
+	pw.Stop()
+	g.Eventually(doneCh).Should(o.Receive(o.BeNil()))
+}
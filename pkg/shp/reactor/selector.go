@@ -0,0 +1,50 @@
+package reactor
+
+import (
+	"strings"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// BuildRunPodSelector the canonical label selector of the build pods of the BuildRun, the build
+// controller labels them with the BuildRun name.
+func BuildRunPodSelector(buildRunName string) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{buildv1alpha1.LabelBuildRun: buildRunName})
+}
+
+// BuildRunPodListOptions the list options selecting the build pods of the BuildRun.
+func BuildRunPodListOptions(buildRunName string) metav1.ListOptions {
+	return metav1.ListOptions{LabelSelector: BuildRunPodSelector(buildRunName).String()}
+}
+
+// podFields the pod fields supported by the API server field selectors, matched against the pods
+// of the events received.
+func podFields(pod *corev1.Pod) fields.Set {
+	return fields.Set{
+		"metadata.name":            pod.GetName(),
+		"metadata.namespace":       pod.GetNamespace(),
+		"spec.nodeName":            pod.Spec.NodeName,
+		"spec.restartPolicy":       string(pod.Spec.RestartPolicy),
+		"spec.schedulerName":       pod.Spec.SchedulerName,
+		"spec.serviceAccountName":  pod.Spec.ServiceAccountName,
+		"status.phase":             string(pod.Status.Phase),
+		"status.podIP":             pod.Status.PodIP,
+		"status.nominatedNodeName": pod.Status.NominatedNodeName,
+	}
+}
+
+// joinSelectors combines the selectors requirements, either may be empty.
+func joinSelectors(selectors ...string) string {
+	parts := []string{}
+	for _, s := range selectors {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ",")
+}
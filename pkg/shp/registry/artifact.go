@@ -0,0 +1,36 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// CheckArtifact makes sure the OCI artifact exists, and the credentials are allowed to pull it,
+// with a HEAD request on the registry. Returns the artifact reference by digest.
+func CheckArtifact(ctx context.Context, artifact string, keychain authn.Keychain) (name.Digest, error) {
+	ref, err := name.ParseReference(artifact)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("invalid OCI artifact reference %q: %w", artifact, err)
+	}
+	desc, err := remote.Head(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) {
+			switch terr.StatusCode {
+			case http.StatusNotFound:
+				return name.Digest{}, fmt.Errorf("OCI artifact %q does not exist", artifact)
+			case http.StatusUnauthorized, http.StatusForbidden:
+				return name.Digest{}, fmt.Errorf("access to OCI artifact %q is denied, check the registry credentials: %w", artifact, err)
+			}
+		}
+		return name.Digest{}, fmt.Errorf("unable to check OCI artifact %q: %w", artifact, err)
+	}
+	return ref.Context().Digest(desc.Digest.String()), nil
+}
@@ -0,0 +1,49 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	o "github.com/onsi/gomega"
+)
+
+func TestCheckArtifact(t *testing.T) {
+	g := o.NewWithT(t)
+
+	const digest = "sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodHead && r.URL.Path == "/v2/org/source/manifests/v1":
+			w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+			w.Header().Set("Content-Length", "512")
+			w.Header().Set("Docker-Content-Digest", digest)
+			w.WriteHeader(http.StatusOK)
+		case strings.HasPrefix(r.URL.Path, "/v2/private/"):
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	ref, err := CheckArtifact(context.TODO(), host+"/org/source:v1", authn.DefaultKeychain)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(ref.String()).To(o.Equal(fmt.Sprintf("%s/org/source@%s", host, digest)))
+
+	_, err = CheckArtifact(context.TODO(), host+"/org/source:missing", authn.DefaultKeychain)
+	g.Expect(err).To(o.MatchError(fmt.Sprintf(`OCI artifact "%s/org/source:missing" does not exist`, host)))
+
+	_, err = CheckArtifact(context.TODO(), host+"/private/source:v1", authn.DefaultKeychain)
+	g.Expect(err).To(o.MatchError(o.HavePrefix(fmt.Sprintf(`access to OCI artifact "%s/private/source:v1" is denied`, host))))
+
+	_, err = CheckArtifact(context.TODO(), "Invalid Artifact", authn.DefaultKeychain)
+	g.Expect(err).To(o.MatchError(o.HavePrefix(`invalid OCI artifact reference "Invalid Artifact"`)))
+}
@@ -0,0 +1,192 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AuthSource names where the registry credentials are obtained from.
+type AuthSource string
+
+const (
+	// AuthDefault uses the local Docker configuration, including its credential helpers.
+	AuthDefault AuthSource = "default"
+	// AuthECR exchanges the AWS CLI credentials for an Amazon ECR token.
+	AuthECR AuthSource = "ecr"
+	// AuthGCR exchanges the gcloud CLI credentials for a Google Container/Artifact Registry token.
+	AuthGCR AuthSource = "gcr"
+	// AuthACR exchanges the Azure CLI credentials for an Azure Container Registry token.
+	AuthACR AuthSource = "acr"
+	// AuthSecret uses the docker-registry secret stored in the cluster, e.g. the Build push secret.
+	AuthSecret AuthSource = "secret"
+)
+
+// AuthSources all supported credential sources.
+var AuthSources = []AuthSource{AuthDefault, AuthECR, AuthGCR, AuthACR, AuthSecret}
+
+// ecrHostRegexp matches Amazon ECR hostnames, capturing the region.
+var ecrHostRegexp = regexp.MustCompile(`^\d+\.dkr\.ecr(-fips)?\.([a-z0-9-]+)\.amazonaws\.com(\.cn)?$`)
+
+// execFn runs a command returning its standard output, replaceable for testing purposes.
+var execFn = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	// #nosec G204 the commands are fixed cloud provider CLIs
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// AuthOptions describes how to obtain the registry credentials.
+type AuthOptions struct {
+	Source AuthSource // credentials source
+
+	Clientset  kubernetes.Interface // kubernetes api-client, used by the secret source
+	Namespace  string               // namespace of the secret
+	SecretName string               // docker-registry secret name
+}
+
+// ParseAuthSource validates the informed credential source name.
+func ParseAuthSource(s string) (AuthSource, error) {
+	if s == "" {
+		return AuthDefault, nil
+	}
+	for _, source := range AuthSources {
+		if string(source) == s {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported registry authentication %q, expected one of %v", s, AuthSources)
+}
+
+// Keychain returns the authn.Keychain for the configured credentials source.
+func Keychain(ctx context.Context, opts AuthOptions) (authn.Keychain, error) {
+	switch opts.Source {
+	case "", AuthDefault:
+		return authn.DefaultKeychain, nil
+	case AuthSecret:
+		return secretKeychain(ctx, opts)
+	default:
+		provider, err := ProviderFor(string(opts.Source), "")
+		if err != nil {
+			return nil, fmt.Errorf("unsupported registry authentication %q", opts.Source)
+		}
+		return &tokenKeychain{ctx: ctx, provider: provider}, nil
+	}
+}
+
+// tokenFn exchanges the cloud provider credentials for a registry username and token.
+type tokenFn func(ctx context.Context, registry string) (string, string, error)
+
+// tokenKeychain resolves credentials minted by a credential provider.
+type tokenKeychain struct {
+	ctx      context.Context
+	provider CredentialProvider
+}
+
+// Resolve mints the credentials for the informed registry.
+func (t *tokenKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	credentials, err := t.provider.Mint(t.ctx, resource.RegistryStr())
+	if err != nil {
+		return nil, err
+	}
+	return authn.FromConfig(authn.AuthConfig{Username: credentials.Username, Password: credentials.Password}), nil
+}
+
+// ecrToken uses "aws ecr get-login-password" on the region taken from the registry hostname.
+func ecrToken(ctx context.Context, registry string) (string, string, error) {
+	match := ecrHostRegexp.FindStringSubmatch(registry)
+	if match == nil {
+		return "", "", fmt.Errorf("registry %q is not an Amazon ECR registry", registry)
+	}
+	out, err := execFn(ctx, "aws", "ecr", "get-login-password", "--region", match[2])
+	if err != nil {
+		return "", "", err
+	}
+	return "AWS", strings.TrimSpace(string(out)), nil
+}
+
+// gcrToken uses "gcloud auth print-access-token" for the active account.
+func gcrToken(ctx context.Context, _ string) (string, string, error) {
+	out, err := execFn(ctx, "gcloud", "auth", "print-access-token")
+	if err != nil {
+		return "", "", err
+	}
+	return "oauth2accesstoken", strings.TrimSpace(string(out)), nil
+}
+
+// acrToken uses "az acr login --expose-token" on the registry name taken from the hostname.
+func acrToken(ctx context.Context, registry string) (string, string, error) {
+	name, _, found := strings.Cut(registry, ".azurecr.")
+	if !found {
+		return "", "", fmt.Errorf("registry %q is not an Azure Container Registry", registry)
+	}
+	out, err := execFn(ctx, "az", "acr", "login", "--name", name, "--expose-token", "--output", "tsv", "--query", "accessToken")
+	if err != nil {
+		return "", "", err
+	}
+	return acrTokenUsername, strings.TrimSpace(string(out)), nil
+}
+
+// acrTokenUsername the registry tokens are issued to the null GUID user, as documented by
+// "az acr login".
+const acrTokenUsername = "00000000-0000-0000-0000-000000000000"
+
+// dockerConfigJSON represents the ".dockerconfigjson" payload of docker-registry secrets.
+type dockerConfigJSON struct {
+	Auths map[string]authn.AuthConfig `json:"auths"`
+}
+
+// staticKeychain resolves credentials from a fixed set of registry entries.
+type staticKeychain struct {
+	auths map[string]authn.AuthConfig
+}
+
+// Resolve looks up the registry entry, falling back to anonymous access when not found.
+func (s *staticKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	registry := resource.RegistryStr()
+	for server, auth := range s.auths {
+		host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+		host, _, _ = strings.Cut(host, "/")
+		// Docker Hub images resolve to "index.docker.io", while secrets usually carry "docker.io"
+		if host == registry || (registry == name.DefaultRegistry && host == "docker.io") {
+			return authn.FromConfig(auth), nil
+		}
+	}
+	return authn.Anonymous, nil
+}
+
+// secretKeychain reads the docker-registry secret from the cluster.
+func secretKeychain(ctx context.Context, opts AuthOptions) (authn.Keychain, error) {
+	if opts.Clientset == nil || opts.SecretName == "" {
+		return nil, fmt.Errorf("registry authentication %q requires a secret name", AuthSecret)
+	}
+	secret, err := opts.Clientset.CoreV1().Secrets(opts.Namespace).Get(ctx, opts.SecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := secret.Data[corev1.DockerConfigJsonKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %q does not contain %q", opts.SecretName, corev1.DockerConfigJsonKey)
+	}
+	var config dockerConfigJSON
+	if err = json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("unable to parse secret %q: %w", opts.SecretName, err)
+	}
+	return &staticKeychain{auths: config.Auths}, nil
+}
@@ -0,0 +1,109 @@
+package registry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseAuthSource(t *testing.T) {
+	g := o.NewWithT(t)
+
+	source, err := ParseAuthSource("")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(source).To(o.Equal(AuthDefault))
+
+	source, err = ParseAuthSource("ecr")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(source).To(o.Equal(AuthECR))
+
+	_, err = ParseAuthSource("quay")
+	g.Expect(err).NotTo(o.BeNil())
+}
+
+func TestTokenKeychain(t *testing.T) {
+	g := o.NewWithT(t)
+
+	var executed string
+	execFn = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		executed = strings.Join(append([]string{name}, args...), " ")
+		return []byte("token\n"), nil
+	}
+
+	keychain, err := Keychain(context.TODO(), AuthOptions{Source: AuthECR})
+	g.Expect(err).To(o.BeNil())
+
+	repo, err := name.NewRepository("123456789012.dkr.ecr.eu-west-1.amazonaws.com/app")
+	g.Expect(err).To(o.BeNil())
+	auth, err := keychain.Resolve(repo)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(executed).To(o.Equal("aws ecr get-login-password --region eu-west-1"))
+
+	config, err := auth.Authorization()
+	g.Expect(err).To(o.BeNil())
+	g.Expect(config.Username).To(o.Equal("AWS"))
+	g.Expect(config.Password).To(o.Equal("token"))
+
+	repo, err = name.NewRepository("ghcr.io/shipwright-io/app")
+	g.Expect(err).To(o.BeNil())
+	_, err = keychain.Resolve(repo)
+	g.Expect(err).NotTo(o.BeNil())
+}
+
+func TestSecretKeychain(t *testing.T) {
+	g := o.NewWithT(t)
+
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "push-secret", Namespace: metav1.NamespaceDefault},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"https://registry.example.com":{"username":"user","password":"pass"}}}`),
+		},
+	})
+
+	keychain, err := Keychain(context.TODO(), AuthOptions{
+		Source:     AuthSecret,
+		Clientset:  clientset,
+		Namespace:  metav1.NamespaceDefault,
+		SecretName: "push-secret",
+	})
+	g.Expect(err).To(o.BeNil())
+
+	repo, err := name.NewRepository("registry.example.com/app")
+	g.Expect(err).To(o.BeNil())
+	auth, err := keychain.Resolve(repo)
+	g.Expect(err).To(o.BeNil())
+	config, err := auth.Authorization()
+	g.Expect(err).To(o.BeNil())
+	g.Expect(config.Username).To(o.Equal("user"))
+	g.Expect(config.Password).To(o.Equal("pass"))
+
+	repo, err = name.NewRepository("quay.io/app")
+	g.Expect(err).To(o.BeNil())
+	auth, err = keychain.Resolve(repo)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(auth).To(o.Equal(authn.Anonymous))
+
+	// the Docker Hub entry, as written by "shp secret create-registry --server=docker.io"
+	dockerHub := &staticKeychain{auths: map[string]authn.AuthConfig{"docker.io": {Username: "hub", Password: "secret"}}}
+	for _, image := range []string{"org/app", "docker.io/org/app", "index.docker.io/org/app"} {
+		repo, err = name.NewRepository(image)
+		g.Expect(err).To(o.BeNil())
+		auth, err = dockerHub.Resolve(repo)
+		g.Expect(err).To(o.BeNil())
+		config, err = auth.Authorization()
+		g.Expect(err).To(o.BeNil())
+		g.Expect(config.Username).To(o.Equal("hub"), image)
+	}
+
+	_, err = Keychain(context.TODO(), AuthOptions{Source: AuthSecret, Clientset: clientset})
+	g.Expect(err).NotTo(o.BeNil())
+}
@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// ParseCopyTarget validates the image the output is copied to, which must be tagged, digests are
+// rejected since the copy keeps the source digest.
+func ParseCopyTarget(image string) (name.Tag, error) {
+	tag, err := name.NewTag(image)
+	if err != nil {
+		return name.Tag{}, fmt.Errorf("invalid image reference %q, expected a tagged image: %w", image, err)
+	}
+	return tag, nil
+}
+
+// CopyImage copies the image, or manifest list, from the source digest to the target tag, from
+// registry to registry. The blobs already present on the target are not uploaded again, and blobs
+// on the same registry are mounted. Returns the target reference by digest.
+func CopyImage(ctx context.Context, src name.Digest, target string, keychain authn.Keychain) (name.Digest, error) {
+	tag, err := ParseCopyTarget(target)
+	if err != nil {
+		return name.Digest{}, err
+	}
+	options := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain)}
+	desc, err := remote.Get(src, options...)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("unable to obtain image %q: %w", src.String(), err)
+	}
+
+	if desc.MediaType.IsIndex() {
+		err = copyIndex(desc, tag, options)
+	} else {
+		err = copyManifest(desc, tag, options)
+	}
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("unable to copy image to %q: %w", target, err)
+	}
+	return tag.Context().Digest(desc.Digest.String()), nil
+}
+
+// copyIndex pushes the manifest list, along with the images it references.
+func copyIndex(desc *remote.Descriptor, tag name.Tag, options []remote.Option) error {
+	index, err := desc.ImageIndex()
+	if err != nil {
+		return err
+	}
+	return remote.WriteIndex(tag, index, options...)
+}
+
+// copyManifest pushes the image, along with its config and layers.
+func copyManifest(desc *remote.Descriptor, tag name.Tag, options []remote.Option) error {
+	image, err := desc.Image()
+	if err != nil {
+		return err
+	}
+	return remote.Write(tag, image, options...)
+}
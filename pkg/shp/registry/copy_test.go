@@ -0,0 +1,59 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	o "github.com/onsi/gomega"
+)
+
+func TestCopyImage(t *testing.T) {
+	g := o.NewWithT(t)
+
+	manifest, manifestDigest, config, configDigest := testImage(g, time.Now())
+	var pushed []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/v2/org/app/manifests/"+manifestDigest.String():
+			w.Header().Set("Content-Type", string(types.OCIManifestSchema1))
+			w.Header().Set("Docker-Content-Digest", manifestDigest.String())
+			_, _ = w.Write(manifest)
+		case r.URL.Path == "/v2/org/app/blobs/"+configDigest.String():
+			_, _ = w.Write(config)
+		// the blobs are already present on the mirror, only the manifest is pushed
+		case r.Method == http.MethodHead && strings.HasPrefix(r.URL.Path, "/v2/mirror/app/blobs/"):
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPut && r.URL.Path == "/v2/mirror/app/manifests/v1":
+			pushed, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	src, err := name.NewDigest(fmt.Sprintf("%s/org/app@%s", host, manifestDigest))
+	g.Expect(err).To(o.BeNil())
+
+	copied, err := CopyImage(context.TODO(), src, host+"/mirror/app:v1", authn.DefaultKeychain)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(copied.String()).To(o.Equal(fmt.Sprintf("%s/mirror/app@%s", host, manifestDigest)))
+	g.Expect(pushed).To(o.Equal(manifest))
+
+	_, err = CopyImage(context.TODO(), src, host+"/readonly/app:v1", authn.DefaultKeychain)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(fmt.Sprintf(`unable to copy image to "%s/readonly/app:v1"`, host))))
+
+	_, err = CopyImage(context.TODO(), src, "Invalid Image", authn.DefaultKeychain)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`invalid image reference "Invalid Image", expected a tagged image`)))
+}
@@ -0,0 +1,3 @@
+// Package registry holds the container registry helpers employed by the CLI-side registry
+// operations, like resolving the credentials to access a registry from different sources.
+package registry
@@ -0,0 +1,117 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// ImageDetails describes an image as stored on the registry: its manifest, config and layers.
+type ImageDetails struct {
+	Reference  string            `json:"reference"`           // image reference pinned to the digest
+	Digest     string            `json:"digest"`              // manifest digest
+	MediaType  string            `json:"mediaType"`           // manifest media type
+	Platform   string            `json:"platform,omitempty"`  // operating system and architecture
+	Platforms  []string          `json:"platforms,omitempty"` // platforms of the index, when multi-platform
+	Created    time.Time         `json:"created"`             // moment the image was created
+	Size       int64             `json:"size"`                // manifest, config and compressed layers size
+	Labels     map[string]string `json:"labels,omitempty"`
+	Entrypoint []string          `json:"entrypoint,omitempty"`
+	Cmd        []string          `json:"cmd,omitempty"`
+	Env        []string          `json:"env,omitempty"`
+	WorkingDir string            `json:"workingDir,omitempty"`
+	User       string            `json:"user,omitempty"`
+	Layers     []LayerDetails    `json:"layers"`
+}
+
+// LayerDetails describes a layer of the image.
+type LayerDetails struct {
+	Digest    string `json:"digest"`
+	MediaType string `json:"mediaType"`
+	Size      int64  `json:"size"` // compressed size
+}
+
+// InspectImage reads the image manifest and config from the registry, without pulling the layers.
+// Multi-platform images are resolved to the informed platform, linux/amd64 when nil, and their
+// platforms are listed.
+func InspectImage(ctx context.Context, image string, keychain authn.Keychain, platform *v1.Platform) (*ImageDetails, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image reference %q: %w", image, err)
+	}
+	opts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain)}
+	if platform != nil {
+		opts = append(opts, remote.WithPlatform(*platform))
+	}
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read image %q: %w", image, err)
+	}
+
+	details := &ImageDetails{}
+	if desc.MediaType.IsIndex() {
+		index, err := desc.ImageIndex()
+		if err != nil {
+			return nil, err
+		}
+		manifest, err := index.IndexManifest()
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range manifest.Manifests {
+			if m.Platform != nil {
+				details.Platforms = append(details.Platforms, m.Platform.String())
+			}
+		}
+	}
+
+	img, err := desc.Image()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read image %q: %w", image, err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	config, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	details.Reference = ref.Context().Digest(digest.String()).String()
+	details.Digest = digest.String()
+	details.MediaType = string(manifest.MediaType)
+	details.Created = config.Created.Time
+	details.Labels = config.Config.Labels
+	details.Entrypoint = config.Config.Entrypoint
+	details.Cmd = config.Config.Cmd
+	details.Env = config.Config.Env
+	details.WorkingDir = config.Config.WorkingDir
+	details.User = config.Config.User
+	if config.OS != "" {
+		details.Platform = config.Platform().String()
+	}
+	if size, err := img.Size(); err == nil {
+		details.Size = size
+	}
+	details.Size += manifest.Config.Size
+	details.Layers = []LayerDetails{}
+	for _, l := range manifest.Layers {
+		details.Size += l.Size
+		details.Layers = append(details.Layers, LayerDetails{
+			Digest:    l.Digest.String(),
+			MediaType: string(l.MediaType),
+			Size:      l.Size,
+		})
+	}
+	return details, nil
+}
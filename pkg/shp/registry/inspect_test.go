@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	o "github.com/onsi/gomega"
+)
+
+// testImage returns the manifest and config of a single layer image, along with their digests.
+func testImage(g *o.WithT, created time.Time) (manifest []byte, manifestDigest v1.Hash, config []byte, configDigest v1.Hash) {
+	config, err := json.Marshal(&v1.ConfigFile{
+		Architecture: "amd64",
+		OS:           "linux",
+		Created:      v1.Time{Time: created},
+		Config: v1.Config{
+			Labels:     map[string]string{"org.opencontainers.image.source": "https://github.com/org/app"},
+			Entrypoint: []string{"/app"},
+			User:       "1001",
+		},
+	})
+	g.Expect(err).To(o.BeNil())
+	configDigest, _, err = v1.SHA256(bytes.NewReader(config))
+	g.Expect(err).To(o.BeNil())
+
+	manifest, err = json.Marshal(&v1.Manifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		Config: v1.Descriptor{
+			MediaType: types.OCIConfigJSON,
+			Size:      int64(len(config)),
+			Digest:    configDigest,
+		},
+		Layers: []v1.Descriptor{{
+			MediaType: types.OCILayer,
+			Size:      1024,
+			Digest:    v1.Hash{Algorithm: "sha256", Hex: strings.TrimPrefix(testLayerDigest, "sha256:")},
+		}},
+	})
+	g.Expect(err).To(o.BeNil())
+	manifestDigest, _, err = v1.SHA256(bytes.NewReader(manifest))
+	g.Expect(err).To(o.BeNil())
+	return manifest, manifestDigest, config, configDigest
+}
+
+// testLayerDigest digest of the layer of the test image.
+const testLayerDigest = "sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb"
+
+func TestInspectImage(t *testing.T) {
+	g := o.NewWithT(t)
+
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	manifest, manifestDigest, config, configDigest := testImage(g, created)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/org/app/manifests/v1", "/v2/org/app/manifests/" + manifestDigest.String():
+			w.Header().Set("Content-Type", string(types.OCIManifestSchema1))
+			w.Header().Set("Docker-Content-Digest", manifestDigest.String())
+			_, _ = w.Write(manifest)
+		case "/v2/org/app/blobs/" + configDigest.String():
+			_, _ = w.Write(config)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	details, err := InspectImage(context.TODO(), host+"/org/app:v1", authn.DefaultKeychain, nil)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(details.Reference).To(o.Equal(fmt.Sprintf("%s/org/app@%s", host, manifestDigest)))
+	g.Expect(details.Digest).To(o.Equal(manifestDigest.String()))
+	g.Expect(details.MediaType).To(o.Equal(string(types.OCIManifestSchema1)))
+	g.Expect(details.Platform).To(o.Equal("linux/amd64"))
+	g.Expect(details.Platforms).To(o.BeEmpty())
+	g.Expect(details.Created).To(o.Equal(created))
+	g.Expect(details.Size).To(o.Equal(int64(len(manifest) + len(config) + 1024)))
+	g.Expect(details.Labels).To(o.HaveKeyWithValue("org.opencontainers.image.source", "https://github.com/org/app"))
+	g.Expect(details.Entrypoint).To(o.Equal([]string{"/app"}))
+	g.Expect(details.User).To(o.Equal("1001"))
+	g.Expect(details.Layers).To(o.Equal([]LayerDetails{{
+		Digest:    testLayerDigest,
+		MediaType: string(types.OCILayer),
+		Size:      1024,
+	}}))
+
+	_, err = InspectImage(context.TODO(), host+"/org/app:missing", authn.DefaultKeychain, nil)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`unable to read image`)))
+
+	_, err = InspectImage(context.TODO(), "Invalid Image", authn.DefaultKeychain, nil)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`invalid image reference "Invalid Image"`)))
+}
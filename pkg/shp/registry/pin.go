@@ -0,0 +1,29 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// PinImage resolves the image tag to the digest it currently points to, with a HEAD request on the
+// registry, returning the image reference carrying both, e.g. "ghcr.io/org/app:v1@sha256:...".
+// Images already referenced by digest are returned unchanged.
+func PinImage(ctx context.Context, image string, keychain authn.Keychain) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference %q: %w", image, err)
+	}
+	if _, ok := ref.(name.Digest); ok {
+		return image, nil
+	}
+
+	desc, err := remote.Head(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve the digest of image %q: %w", image, err)
+	}
+	return fmt.Sprintf("%s@%s", image, desc.Digest), nil
+}
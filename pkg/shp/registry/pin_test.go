@@ -0,0 +1,49 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	o "github.com/onsi/gomega"
+)
+
+func TestPinImage(t *testing.T) {
+	g := o.NewWithT(t)
+
+	const digest = "sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodHead && r.URL.Path == "/v2/org/app/manifests/v1":
+			w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+			w.Header().Set("Content-Length", "512")
+			w.Header().Set("Docker-Content-Digest", digest)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	pinned, err := PinImage(context.TODO(), host+"/org/app:v1", authn.DefaultKeychain)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(pinned).To(o.Equal(fmt.Sprintf("%s/org/app:v1@%s", host, digest)))
+
+	// already pinned, no request is made
+	pinned, err = PinImage(context.TODO(), "registry.invalid/org/app@"+digest, authn.DefaultKeychain)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(pinned).To(o.Equal("registry.invalid/org/app@" + digest))
+
+	_, err = PinImage(context.TODO(), host+"/org/app:missing", authn.DefaultKeychain)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`unable to resolve the digest of image`)))
+
+	_, err = PinImage(context.TODO(), "Invalid Image", authn.DefaultKeychain)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`invalid image reference "Invalid Image"`)))
+}
@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// PlatformImage an image built for a single platform, referenced by digest.
+type PlatformImage struct {
+	Platform v1.Platform
+	Image    name.Digest
+}
+
+// ParsePlatforms parses the platforms in the "os/arch[/variant]" format, e.g. "linux/arm64/v8",
+// rejecting duplicates.
+func ParsePlatforms(values []string) ([]v1.Platform, error) {
+	platforms := make([]v1.Platform, 0, len(values))
+	seen := map[string]bool{}
+	for _, value := range values {
+		p, err := v1.ParsePlatform(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid platform %q: %w", value, err)
+		}
+		if p.OS == "" || p.Architecture == "" {
+			return nil, fmt.Errorf("invalid platform %q, expected the \"os/arch[/variant]\" format", value)
+		}
+		if seen[p.String()] {
+			return nil, fmt.Errorf("platform %q informed more than once", value)
+		}
+		seen[p.String()] = true
+		platforms = append(platforms, *p)
+	}
+	return platforms, nil
+}
+
+// PlatformSuffix returns the platform rendered as a suffix for names and tags, e.g. "linux-arm64-v8".
+func PlatformSuffix(p v1.Platform) string {
+	return strings.ReplaceAll(p.String(), "/", "-")
+}
+
+// PlatformImageTag returns the image reference tagged for the informed platform, the platform suffix
+// is appended to the image tag, e.g. "ghcr.io/org/app:v1-linux-arm64".
+func PlatformImageTag(image string, p v1.Platform) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference %q: %w", image, err)
+	}
+	tag, ok := ref.(name.Tag)
+	if !ok {
+		return "", fmt.Errorf("image %q must be referenced by tag to be built for multiple platforms", image)
+	}
+	return fmt.Sprintf("%s:%s-%s", tag.Context().Name(), tag.TagStr(), PlatformSuffix(p)), nil
+}
+
+// PushManifestList pushes the manifest list, or image index, stitching the images built for each
+// platform, tagged as the informed image. The images must be on the same repository. Returns the
+// reference of the manifest list pushed, by digest.
+func PushManifestList(ctx context.Context, image string, images []PlatformImage, keychain authn.Keychain) (name.Digest, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("invalid image reference %q: %w", image, err)
+	}
+	options := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain)}
+
+	mediaType := types.OCIImageIndex
+	adds := make([]mutate.IndexAddendum, 0, len(images))
+	for i := range images {
+		desc, err := remote.Get(images[i].Image, options...)
+		if err != nil {
+			return name.Digest{}, fmt.Errorf("unable to obtain image %q: %w", images[i].Image, err)
+		}
+		var add mutate.Appendable
+		if desc.MediaType.IsIndex() {
+			add, err = desc.ImageIndex()
+		} else {
+			add, err = desc.Image()
+		}
+		if err != nil {
+			return name.Digest{}, err
+		}
+		// docker images are only allowed on a docker manifest list
+		if desc.MediaType == types.DockerManifestSchema2 {
+			mediaType = types.DockerManifestList
+		}
+		platform := images[i].Platform
+		adds = append(adds, mutate.IndexAddendum{
+			Add: add,
+			Descriptor: v1.Descriptor{
+				MediaType: desc.MediaType,
+				Platform:  &platform,
+			},
+		})
+	}
+
+	index := mutate.IndexMediaType(mutate.AppendManifests(empty.Index, adds...), mediaType)
+	if err = remote.WriteIndex(ref, index, options...); err != nil {
+		return name.Digest{}, fmt.Errorf("unable to push the manifest list %q: %w", image, err)
+	}
+	digest, err := index.Digest()
+	if err != nil {
+		return name.Digest{}, err
+	}
+	return ref.Context().Digest(digest.String()), nil
+}
@@ -0,0 +1,41 @@
+package registry
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	o "github.com/onsi/gomega"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	g := o.NewWithT(t)
+
+	platforms, err := ParsePlatforms([]string{"linux/amd64", "linux/arm64/v8"})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(platforms).To(o.Equal([]v1.Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm64", Variant: "v8"},
+	}))
+	g.Expect(PlatformSuffix(platforms[1])).To(o.Equal("linux-arm64-v8"))
+
+	_, err = ParsePlatforms([]string{"linux"})
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`invalid platform "linux"`)))
+	_, err = ParsePlatforms([]string{"linux/amd64", "linux/amd64"})
+	g.Expect(err).To(o.MatchError(`platform "linux/amd64" informed more than once`))
+}
+
+func TestPlatformImageTag(t *testing.T) {
+	g := o.NewWithT(t)
+	arm64 := v1.Platform{OS: "linux", Architecture: "arm64"}
+
+	image, err := PlatformImageTag("ghcr.io/org/app:v1", arm64)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(image).To(o.Equal("ghcr.io/org/app:v1-linux-arm64"))
+
+	image, err = PlatformImageTag("registry.local:5000/app", arm64)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(image).To(o.Equal("registry.local:5000/app:latest-linux-arm64"))
+
+	_, err = PlatformImageTag("ghcr.io/org/app@sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb", arm64)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring("must be referenced by tag")))
+}
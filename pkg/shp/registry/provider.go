@@ -0,0 +1,308 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// ProviderAuto selects the credential provider based on the registry hostname.
+const ProviderAuto = "auto"
+
+// Credentials short-lived registry credentials minted by a CredentialProvider.
+type Credentials struct {
+	Username  string    // registry username
+	Password  string    // registry token
+	ExpiresAt time.Time // moment the token expires, zero when unknown
+}
+
+// CredentialProvider mints short-lived registry credentials, usually by exchanging the cloud
+// provider credentials available on the local machine.
+type CredentialProvider interface {
+	// Name returns the provider name, used to select it on the command-line.
+	Name() AuthSource
+	// Matches tells whether the registry hostname belongs to the provider.
+	Matches(registry string) bool
+	// Mint issues the credentials for the informed registry hostname.
+	Mint(ctx context.Context, registry string) (*Credentials, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[AuthSource]CredentialProvider{}
+)
+
+// RegisterProvider makes the credential provider available, replacing a former provider with the
+// same name.
+func RegisterProvider(provider CredentialProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[provider.Name()] = provider
+}
+
+// ProviderNames returns the name of the registered providers, sorted.
+func ProviderNames() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateProvider makes sure the informed provider name is either "auto" or registered.
+func ValidateProvider(name string) error {
+	if name == ProviderAuto {
+		return nil
+	}
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	if _, ok := providers[AuthSource(name)]; !ok {
+		return fmt.Errorf("unsupported credential provider %q, expected %q or one of %v", name, ProviderAuto, ProviderNames())
+	}
+	return nil
+}
+
+// ProviderFor returns the named provider, or with "auto" the one matching the registry hostname.
+func ProviderFor(name, registry string) (CredentialProvider, error) {
+	if err := ValidateProvider(name); err != nil {
+		return nil, err
+	}
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	if name != ProviderAuto {
+		return providers[AuthSource(name)], nil
+	}
+	for _, name := range ProviderNames() {
+		if provider := providers[AuthSource(name)]; provider.Matches(registry) {
+			return provider, nil
+		}
+	}
+	return nil, fmt.Errorf("no credential provider matches registry %q, inform one of %v", registry, ProviderNames())
+}
+
+// DockerConfigJSON renders the ".dockerconfigjson" payload holding the credentials for the
+// registry.
+func DockerConfigJSON(registry string, credentials *Credentials) ([]byte, error) {
+	return json.Marshal(dockerConfigJSON{Auths: map[string]authn.AuthConfig{
+		registry: {Username: credentials.Username, Password: credentials.Password},
+	}})
+}
+
+// ApplySecret creates, or updates, the docker-registry secret holding the credentials for the
+// registry, annotated to be referenced by Builds. Returns whether the secret has been created.
+func ApplySecret(
+	ctx context.Context,
+	clientset kubernetes.Interface,
+	namespace, name, registry string,
+	credentials *Credentials,
+) (bool, error) {
+	data, err := DockerConfigJSON(registry, credentials)
+	if err != nil {
+		return false, err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{buildv1alpha1.AnnotationBuildRefSecret: "true"},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: data},
+	}
+
+	secrets := clientset.CoreV1().Secrets(namespace)
+	if _, err = secrets.Create(ctx, secret, metav1.CreateOptions{}); err == nil {
+		return true, nil
+	} else if !kerrors.IsAlreadyExists(err) {
+		return false, err
+	}
+	existing, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	if existing.Type != corev1.SecretTypeDockerConfigJson {
+		return false, fmt.Errorf("secret %q already exists with type %q", name, existing.Type)
+	}
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+	existing.Annotations[buildv1alpha1.AnnotationBuildRefSecret] = "true"
+	existing.Data = secret.Data
+	_, err = secrets.Update(ctx, existing, metav1.UpdateOptions{})
+	return false, err
+}
+
+// cliProvider credential provider backed by a cloud provider CLI, and optionally a fallback used
+// when the CLI is not installed, i.e. the instance metadata service.
+type cliProvider struct {
+	name     AuthSource
+	matches  func(registry string) bool
+	tokenFn  tokenFn
+	lifetime time.Duration // token lifetime documented by the cloud provider
+
+	fallback func(ctx context.Context, registry string) (*Credentials, error)
+}
+
+// Name returns the provider name.
+func (c *cliProvider) Name() AuthSource {
+	return c.name
+}
+
+// Matches tells whether the registry hostname belongs to the provider.
+func (c *cliProvider) Matches(registry string) bool {
+	return c.matches(registry)
+}
+
+// Mint issues the credentials using the CLI, falling back when it's not installed.
+func (c *cliProvider) Mint(ctx context.Context, registry string) (*Credentials, error) {
+	username, token, err := c.tokenFn(ctx, registry)
+	if err != nil {
+		if c.fallback != nil && errors.Is(err, exec.ErrNotFound) {
+			klog.V(4).Infof("%s CLI not found, falling back to the instance metadata service: %v", c.name, err)
+			return c.fallback(ctx, registry)
+		}
+		return nil, err
+	}
+	return &Credentials{Username: username, Password: token, ExpiresAt: time.Now().Add(c.lifetime)}, nil
+}
+
+// httpClient client used to reach the instance metadata services, replaceable for testing purposes.
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// gceMetadataTokenURL issues access tokens for the default service account of Google Cloud
+// instances.
+var gceMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// azureIMDSTokenURL issues Azure AD tokens for the managed identity of Azure instances.
+var azureIMDSTokenURL = "http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=https://management.azure.com/"
+
+// acrExchangeURL returns the endpoint exchanging an Azure AD token for a registry refresh token.
+var acrExchangeURL = func(registry string) string {
+	return fmt.Sprintf("https://%s/oauth2/exchange", registry)
+}
+
+// tokenResponse subset of the OAuth2 token responses returned by the metadata services.
+type tokenResponse struct {
+	AccessToken  string      `json:"access_token"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    json.Number `json:"expires_in"`
+}
+
+// doTokenRequest executes the request, decoding the OAuth2 token response.
+func doTokenRequest(req *http.Request) (*tokenResponse, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", req.Method, req.URL.Redacted(), resp.Status)
+	}
+	var token tokenResponse
+	if err = json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("unable to decode the token issued by %s: %w", req.URL.Redacted(), err)
+	}
+	return &token, nil
+}
+
+// expiresAt the moment the token expires, zero when the lifetime is not informed.
+func (t *tokenResponse) expiresAt() time.Time {
+	seconds, err := t.ExpiresIn.Int64()
+	if err != nil || seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+// gceMetadataCredentials issues an access token for the instance service account.
+func gceMetadataCredentials(ctx context.Context, _ string) (*Credentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gceMetadataTokenURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	token, err := doTokenRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return &Credentials{Username: "oauth2accesstoken", Password: token.AccessToken, ExpiresAt: token.expiresAt()}, nil
+}
+
+// azureIMDSCredentials issues an Azure AD token for the instance managed identity, exchanged for a
+// refresh token of the registry.
+func azureIMDSCredentials(ctx context.Context, registry string) (*Credentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, azureIMDSTokenURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	aad, err := doTokenRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":   {"access_token"},
+		"service":      {registry},
+		"access_token": {aad.AccessToken},
+	}
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, acrExchangeURL(registry), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	refresh, err := doTokenRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return &Credentials{Username: acrTokenUsername, Password: refresh.RefreshToken, ExpiresAt: aad.expiresAt()}, nil
+}
+
+func init() {
+	RegisterProvider(&cliProvider{
+		name:    AuthECR,
+		matches: ecrHostRegexp.MatchString,
+		tokenFn: ecrToken,
+		// the authorization token is valid for 12 hours
+		lifetime: 12 * time.Hour,
+	})
+	RegisterProvider(&cliProvider{
+		name: AuthGCR,
+		matches: func(registry string) bool {
+			return registry == "gcr.io" || strings.HasSuffix(registry, ".gcr.io") ||
+				strings.HasSuffix(registry, "-docker.pkg.dev")
+		},
+		tokenFn:  gcrToken,
+		lifetime: time.Hour,
+		fallback: gceMetadataCredentials,
+	})
+	RegisterProvider(&cliProvider{
+		name: AuthACR,
+		matches: func(registry string) bool {
+			return strings.Contains(registry, ".azurecr.")
+		},
+		tokenFn:  acrToken,
+		lifetime: 3 * time.Hour,
+		fallback: azureIMDSCredentials,
+	})
+}
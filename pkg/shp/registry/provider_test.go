@@ -0,0 +1,129 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestProviderFor(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(ProviderNames()).To(o.ContainElements("acr", "ecr", "gcr"))
+	g.Expect(ValidateProvider(ProviderAuto)).To(o.Succeed())
+	g.Expect(ValidateProvider("quay")).NotTo(o.Succeed())
+
+	for registry, expected := range map[string]AuthSource{
+		"123456789012.dkr.ecr.eu-west-1.amazonaws.com": AuthECR,
+		"gcr.io":                      AuthGCR,
+		"eu.gcr.io":                   AuthGCR,
+		"europe-west1-docker.pkg.dev": AuthGCR,
+		"myregistry.azurecr.io":       AuthACR,
+	} {
+		provider, err := ProviderFor(ProviderAuto, registry)
+		g.Expect(err).To(o.BeNil())
+		g.Expect(provider.Name()).To(o.Equal(expected), registry)
+	}
+
+	_, err := ProviderFor(ProviderAuto, "ghcr.io")
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`no credential provider matches registry "ghcr.io"`)))
+
+	provider, err := ProviderFor("ecr", "ghcr.io")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(provider.Name()).To(o.Equal(AuthECR))
+}
+
+func TestProviderMetadataFallback(t *testing.T) {
+	g := o.NewWithT(t)
+
+	execFn = func(_ context.Context, name string, _ ...string) ([]byte, error) {
+		return nil, fmt.Errorf("%s: %w", name, exec.ErrNotFound)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gce":
+			g.Expect(r.Header.Get("Metadata-Flavor")).To(o.Equal("Google"))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "gce-token", "expires_in": 3599})
+		case "/azure":
+			g.Expect(r.Header.Get("Metadata")).To(o.Equal("true"))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "aad-token", "expires_in": "3599"})
+		case "/exchange":
+			g.Expect(r.ParseForm()).To(o.Succeed())
+			g.Expect(r.PostForm.Get("access_token")).To(o.Equal("aad-token"))
+			g.Expect(r.PostForm.Get("service")).To(o.Equal("myregistry.azurecr.io"))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"refresh_token": "acr-token"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	gceMetadataTokenURL = server.URL + "/gce"
+	azureIMDSTokenURL = server.URL + "/azure"
+	acrExchangeURL = func(string) string {
+		return server.URL + "/exchange"
+	}
+
+	provider, err := ProviderFor(ProviderAuto, "gcr.io")
+	g.Expect(err).To(o.BeNil())
+	credentials, err := provider.Mint(context.TODO(), "gcr.io")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(credentials.Username).To(o.Equal("oauth2accesstoken"))
+	g.Expect(credentials.Password).To(o.Equal("gce-token"))
+	g.Expect(credentials.ExpiresAt.IsZero()).To(o.BeFalse())
+
+	provider, err = ProviderFor(ProviderAuto, "myregistry.azurecr.io")
+	g.Expect(err).To(o.BeNil())
+	credentials, err = provider.Mint(context.TODO(), "myregistry.azurecr.io")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(credentials.Username).To(o.Equal(acrTokenUsername))
+	g.Expect(credentials.Password).To(o.Equal("acr-token"))
+	g.Expect(credentials.ExpiresAt.IsZero()).To(o.BeFalse())
+
+	// the Amazon ECR provider relies on the AWS CLI only
+	provider, err = ProviderFor(ProviderAuto, "123456789012.dkr.ecr.eu-west-1.amazonaws.com")
+	g.Expect(err).To(o.BeNil())
+	_, err = provider.Mint(context.TODO(), "123456789012.dkr.ecr.eu-west-1.amazonaws.com")
+	g.Expect(err).To(o.MatchError(exec.ErrNotFound))
+}
+
+func TestApplySecret(t *testing.T) {
+	g := o.NewWithT(t)
+
+	ctx := context.TODO()
+	clientset := fake.NewSimpleClientset()
+	credentials := &Credentials{Username: "AWS", Password: "token"}
+
+	created, err := ApplySecret(ctx, clientset, metav1.NamespaceDefault, "push", "quay.io", credentials)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(created).To(o.BeTrue())
+
+	credentials.Password = "refreshed"
+	created, err = ApplySecret(ctx, clientset, metav1.NamespaceDefault, "push", "quay.io", credentials)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(created).To(o.BeFalse())
+
+	secret, err := clientset.CoreV1().Secrets(metav1.NamespaceDefault).Get(ctx, "push", metav1.GetOptions{})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(secret.Type).To(o.Equal(corev1.SecretTypeDockerConfigJson))
+	g.Expect(secret.Annotations).To(o.HaveKeyWithValue("build.shipwright.io/referenced.secret", "true"))
+	g.Expect(string(secret.Data[corev1.DockerConfigJsonKey])).To(o.Equal(
+		`{"auths":{"quay.io":{"username":"AWS","password":"refreshed","auth":"QVdTOnJlZnJlc2hlZA=="}}}`))
+
+	_, err = clientset.CoreV1().Secrets(metav1.NamespaceDefault).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "opaque", Namespace: metav1.NamespaceDefault},
+	}, metav1.CreateOptions{})
+	g.Expect(err).To(o.BeNil())
+	_, err = ApplySecret(ctx, clientset, metav1.NamespaceDefault, "opaque", "quay.io", credentials)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`secret "opaque" already exists with type ""`)))
+}
@@ -0,0 +1,4 @@
+// Package sbom retrieves the software bill of materials of BuildRun output images, either reported
+// by the build strategy as a step result, or published on the registry as an OCI referrer or a
+// cosign attachment, and converts it between the SPDX and CycloneDX JSON formats.
+package sbom
@@ -0,0 +1,185 @@
+package sbom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResultName name of the build strategy result carrying the SBOM document, or the reference of the
+// OCI artifact holding it.
+const ResultName = "sbom"
+
+// Source where the SBOM was found.
+type Source string
+
+const (
+	// SourceResult the SBOM is reported by the build strategy as a step result.
+	SourceResult Source = "result"
+	// SourceReferrer the SBOM is an OCI referrer of the output image.
+	SourceReferrer Source = "referrer"
+	// SourceAttached the SBOM is attached to the output image with "cosign attach sbom".
+	SourceAttached Source = "attached"
+)
+
+// ErrNotFound no SBOM was found for the image.
+var ErrNotFound = errors.New("no SBOM found")
+
+// SBOM document retrieved, along with where it was found.
+type SBOM struct {
+	Format   Format
+	Source   Source
+	Location string // reference of the artifact holding the document, empty for inline results
+	Data     []byte
+}
+
+// stepResult entry of the step termination message, where Tekton records the step results.
+type stepResult struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ResultValue returns the value of the SBOM result recorded on the termination message of the build
+// pod steps, empty when none reports it.
+func ResultValue(pod *corev1.Pod) string {
+	if pod == nil {
+		return ""
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated == nil || status.State.Terminated.Message == "" {
+			continue
+		}
+		var results []stepResult
+		if err := json.Unmarshal([]byte(status.State.Terminated.Message), &results); err != nil {
+			continue
+		}
+		for _, r := range results {
+			if r.Key == ResultName && r.Value != "" {
+				return r.Value
+			}
+		}
+	}
+	return ""
+}
+
+// FromResult resolves the SBOM result value, which is either the document itself or the reference
+// of the OCI artifact holding it.
+func FromResult(ctx context.Context, value string, keychain authn.Keychain) (*SBOM, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "{") {
+		format, err := Detect([]byte(value))
+		if err != nil {
+			return nil, err
+		}
+		return &SBOM{Format: format, Source: SourceResult, Data: []byte(value)}, nil
+	}
+
+	ref, err := name.ParseReference(value)
+	if err != nil {
+		return nil, fmt.Errorf("SBOM result %q is neither a document nor an image reference: %w", value, err)
+	}
+	sbom, err := fetchArtifact(ctx, ref, keychain)
+	if err != nil {
+		return nil, err
+	}
+	sbom.Source = SourceResult
+	return sbom, nil
+}
+
+// FromImage looks up the SBOM of the image among its OCI referrers first, and then on the tag
+// employed by "cosign attach sbom", returning ErrNotFound when none is published.
+func FromImage(ctx context.Context, image name.Digest, keychain authn.Keychain) (*SBOM, error) {
+	index, err := remote.Referrers(image, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the referrers of image %q: %w", image, err)
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, desc := range manifest.Manifests {
+		if _, ok := FormatOf(desc.ArtifactType); !ok {
+			continue
+		}
+		sbom, err := fetchArtifact(ctx, image.Context().Digest(desc.Digest.String()), keychain)
+		if err != nil {
+			return nil, err
+		}
+		sbom.Source = SourceReferrer
+		return sbom, nil
+	}
+
+	tag := image.Context().Tag(strings.Replace(image.DigestStr(), ":", "-", 1) + ".sbom")
+	sbom, err := fetchArtifact(ctx, tag, keychain)
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	sbom.Source = SourceAttached
+	return sbom, nil
+}
+
+// fetchArtifact reads the SBOM out of the OCI artifact, taking the layer with a SBOM media type, or
+// the single layer when its media type is not descriptive.
+func fetchArtifact(ctx context.Context, ref name.Reference, keychain authn.Keychain) (*SBOM, error) {
+	opts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain)}
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := v1.ParseManifest(bytes.NewReader(desc.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the manifest of %q: %w", ref, err)
+	}
+
+	var layer *v1.Descriptor
+	for i := range manifest.Layers {
+		if _, ok := FormatOf(string(manifest.Layers[i].MediaType)); ok {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil && len(manifest.Layers) == 1 {
+		layer = &manifest.Layers[0]
+	}
+	if layer == nil {
+		return nil, fmt.Errorf("artifact %q does not carry a SBOM layer", ref)
+	}
+
+	blob, err := remote.Layer(ref.Context().Digest(layer.Digest.String()), opts...)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := blob.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := Detect(data)
+	if err != nil {
+		return nil, fmt.Errorf("artifact %q: %w", ref, err)
+	}
+	location := ref.Context().Digest(desc.Digest.String()).String()
+	return &SBOM{Format: format, Location: location, Data: data}, nil
+}
@@ -0,0 +1,136 @@
+package sbom
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// content served by the fake registry.
+type content struct {
+	mediaType string
+	data      []byte
+}
+
+// fakeRegistry serves the manifests and blobs informed, keyed by their path under "/v2/org/app/".
+type fakeRegistry map[string]content
+
+func (f fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v2/" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	entry, ok := f[strings.TrimPrefix(r.URL.Path, "/v2/org/app/")]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", entry.mediaType)
+	w.Header().Set("Docker-Content-Digest", digestOf(entry.data))
+	_, _ = w.Write(entry.data)
+}
+
+func digestOf(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+// artifact returns the manifest of an artifact carrying the SBOM on a single layer.
+func artifact(artifactType string, layerMediaType string, sbom []byte) []byte {
+	manifest, _ := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"artifactType":  artifactType,
+		"config":        map[string]interface{}{"mediaType": "application/vnd.oci.empty.v1+json", "digest": digestOf([]byte("{}")), "size": 2},
+		"layers":        []interface{}{map[string]interface{}{"mediaType": layerMediaType, "digest": digestOf(sbom), "size": len(sbom)}},
+	})
+	return manifest
+}
+
+func TestFromImage(t *testing.T) {
+	g := o.NewWithT(t)
+
+	const (
+		imageDigest = "sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb"
+		attachedTag = "manifests/sha256-9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb.sbom"
+	)
+	cdx := []byte(`{"bomFormat": "CycloneDX", "specVersion": "1.5"}`)
+	referrer := artifact("application/vnd.cyclonedx+json", "application/vnd.cyclonedx+json", cdx)
+	index, _ := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.index.v1+json",
+		"manifests": []interface{}{
+			map[string]interface{}{"mediaType": "application/vnd.oci.image.manifest.v1+json", "artifactType": "application/vnd.dev.sigstore.bundle+json", "digest": digestOf([]byte("sig")), "size": 3},
+			map[string]interface{}{"mediaType": "application/vnd.oci.image.manifest.v1+json", "artifactType": "application/vnd.cyclonedx+json", "digest": digestOf(referrer), "size": len(referrer)},
+		},
+	})
+
+	registry := fakeRegistry{
+		"referrers/" + imageDigest:        {"application/vnd.oci.image.index.v1+json", index},
+		"manifests/" + digestOf(referrer): {"application/vnd.oci.image.manifest.v1+json", referrer},
+		"blobs/" + digestOf(cdx):          {"application/octet-stream", cdx},
+	}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	image, err := name.NewDigest(strings.TrimPrefix(server.URL, "http://") + "/org/app@" + imageDigest)
+	g.Expect(err).To(o.BeNil())
+
+	sbom, err := FromImage(context.TODO(), image, authn.DefaultKeychain)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(sbom.Source).To(o.Equal(SourceReferrer))
+	g.Expect(sbom.Format).To(o.Equal(CycloneDX))
+	g.Expect(sbom.Location).To(o.HaveSuffix("/org/app@" + digestOf(referrer)))
+	g.Expect(sbom.Data).To(o.Equal(cdx))
+
+	// without referrers, the cosign attachment tag is looked up, its media type is not descriptive
+	delete(registry, "referrers/"+imageDigest)
+	spdx := []byte(spdxDoc)
+	attached := artifact("", "text/plain", spdx)
+	registry[attachedTag] = content{"application/vnd.oci.image.manifest.v1+json", attached}
+	registry["blobs/"+digestOf(spdx)] = content{"application/octet-stream", spdx}
+
+	sbom, err = FromImage(context.TODO(), image, authn.DefaultKeychain)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(sbom.Source).To(o.Equal(SourceAttached))
+	g.Expect(sbom.Format).To(o.Equal(SPDX))
+
+	delete(registry, attachedTag)
+	_, err = FromImage(context.TODO(), image, authn.DefaultKeychain)
+	g.Expect(err).To(o.MatchError(ErrNotFound))
+}
+
+func TestFromResult(t *testing.T) {
+	g := o.NewWithT(t)
+
+	pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		Name:  "step-source-default",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "not json"}},
+	}, {
+		Name: "step-build-and-push",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Message: `[{"key":"StartedAt","value":"2024-05-02T10:00:00Z","type":3},{"key":"sbom","value":"{\"bomFormat\":\"CycloneDX\"}","type":1}]`,
+		}},
+	}}}}
+	value := ResultValue(pod)
+	g.Expect(value).To(o.Equal(`{"bomFormat":"CycloneDX"}`))
+	g.Expect(ResultValue(nil)).To(o.BeEmpty())
+
+	sbom, err := FromResult(context.TODO(), value, authn.DefaultKeychain)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(sbom.Source).To(o.Equal(SourceResult))
+	g.Expect(sbom.Format).To(o.Equal(CycloneDX))
+	g.Expect(sbom.Location).To(o.BeEmpty())
+
+	_, err = FromResult(context.TODO(), "Not A Reference", authn.DefaultKeychain)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(`SBOM result "Not A Reference" is neither a document nor an image reference`)))
+}
@@ -0,0 +1,389 @@
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Format of a SBOM document.
+type Format string
+
+const (
+	// SPDX the SPDX 2.3 JSON format.
+	SPDX Format = "spdx"
+	// CycloneDX the CycloneDX 1.5 JSON format.
+	CycloneDX Format = "cyclonedx"
+)
+
+// Formats all supported formats.
+var Formats = []Format{SPDX, CycloneDX}
+
+// mediaTypes media and artifact types the SBOMs are published with, including the ones employed by
+// "cosign attach sbom".
+var mediaTypes = map[string]Format{
+	"application/spdx+json":          SPDX,
+	"text/spdx+json":                 SPDX,
+	"application/vnd.cyclonedx+json": CycloneDX,
+}
+
+// ParseFormat validates the informed format name, case insensitive.
+func ParseFormat(s string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(string(format), s) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported SBOM format %q, expected one of %v", s, Formats)
+}
+
+// FormatOf returns the format of the informed media type, ignoring its parameters.
+func FormatOf(mediaType string) (Format, bool) {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	format, ok := mediaTypes[strings.TrimSpace(mediaType)]
+	return format, ok
+}
+
+// Detect identifies the format of the SBOM JSON document.
+func Detect(data []byte) (Format, error) {
+	probe := struct {
+		SPDXVersion string `json:"spdxVersion"`
+		BOMFormat   string `json:"bomFormat"`
+	}{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return "", fmt.Errorf("SBOM is not a JSON document: %w", err)
+	}
+	switch {
+	case strings.HasPrefix(probe.SPDXVersion, "SPDX-"):
+		return SPDX, nil
+	case probe.BOMFormat == "CycloneDX":
+		return CycloneDX, nil
+	}
+	return "", fmt.Errorf("SBOM is neither a SPDX nor a CycloneDX JSON document")
+}
+
+// Convert translates the SBOM document to the informed format, documents already in the format are
+// returned as is. The conversion keeps the document name and creation time, and the name, version,
+// package URL, type and license of the packages, other details are not carried over.
+func Convert(data []byte, to Format) ([]byte, error) {
+	from, err := Detect(data)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return data, nil
+	}
+
+	var doc *document
+	switch from {
+	case SPDX:
+		doc, err = fromSPDX(data)
+	case CycloneDX:
+		doc, err = fromCycloneDX(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the %s document: %w", from, err)
+	}
+	if doc.created == "" {
+		doc.created = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	switch to {
+	case SPDX:
+		return json.MarshalIndent(doc.toSPDX(data), "", "  ")
+	case CycloneDX:
+		return json.MarshalIndent(doc.toCycloneDX(), "", "  ")
+	}
+	return nil, fmt.Errorf("unsupported SBOM format %q, expected one of %v", to, Formats)
+}
+
+// document format neutral representation of a SBOM, holding what survives the conversion.
+type document struct {
+	name     string
+	created  string
+	subject  *component // artifact described by the document, usually the image
+	packages []component
+}
+
+// component a package listed on the SBOM.
+type component struct {
+	kind    string // CycloneDX component type
+	name    string
+	version string
+	purl    string
+	license string // SPDX license expression
+}
+
+const (
+	spdxDocumentID  = "SPDXRef-DOCUMENT"
+	spdxNoAssertion = "NOASSERTION"
+	spdxNone        = "NONE"
+)
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	DocumentDescribes []string           `json:"documentDescribes,omitempty"`
+	Packages          []spdxPackage      `json:"packages,omitempty"`
+	Relationships     []spdxRelationship `json:"relationships,omitempty"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	LicenseConcluded      string            `json:"licenseConcluded,omitempty"`
+	LicenseDeclared       string            `json:"licenseDeclared,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type cdxDocument struct {
+	BOMFormat   string         `json:"bomFormat"`
+	SpecVersion string         `json:"specVersion"`
+	Version     int            `json:"version"`
+	Metadata    *cdxMetadata   `json:"metadata,omitempty"`
+	Components  []cdxComponent `json:"components,omitempty"`
+}
+
+type cdxMetadata struct {
+	Timestamp string        `json:"timestamp,omitempty"`
+	Component *cdxComponent `json:"component,omitempty"`
+}
+
+type cdxComponent struct {
+	Type     string       `json:"type"`
+	Name     string       `json:"name"`
+	Version  string       `json:"version,omitempty"`
+	PURL     string       `json:"purl,omitempty"`
+	Licenses []cdxLicense `json:"licenses,omitempty"`
+}
+
+type cdxLicense struct {
+	License    *cdxLicenseID `json:"license,omitempty"`
+	Expression string        `json:"expression,omitempty"`
+}
+
+type cdxLicenseID struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// cdxTypes CycloneDX component types which have a SPDX primary package purpose counterpart.
+var cdxTypes = map[string]bool{
+	"application":      true,
+	"framework":        true,
+	"library":          true,
+	"container":        true,
+	"operating-system": true,
+	"device":           true,
+	"firmware":         true,
+	"file":             true,
+}
+
+// fromSPDX reads the SPDX document, the package it describes becomes the subject.
+func fromSPDX(data []byte) (*document, error) {
+	spdx := spdxDocument{}
+	if err := json.Unmarshal(data, &spdx); err != nil {
+		return nil, err
+	}
+	described := map[string]bool{}
+	for _, id := range spdx.DocumentDescribes {
+		described[id] = true
+	}
+	for _, r := range spdx.Relationships {
+		if r.SPDXElementID == spdxDocumentID && r.RelationshipType == "DESCRIBES" {
+			described[r.RelatedSPDXElement] = true
+		}
+	}
+
+	doc := &document{name: spdx.Name, created: spdx.CreationInfo.Created}
+	for _, p := range spdx.Packages {
+		c := component{kind: strings.ToLower(p.PrimaryPackagePurpose), name: p.Name, version: p.VersionInfo}
+		if !cdxTypes[c.kind] {
+			c.kind = "library"
+		}
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				c.purl = ref.ReferenceLocator
+				break
+			}
+		}
+		for _, license := range []string{p.LicenseDeclared, p.LicenseConcluded} {
+			if license != "" && license != spdxNoAssertion && license != spdxNone {
+				c.license = license
+				break
+			}
+		}
+		if doc.subject == nil && described[p.SPDXID] {
+			doc.subject = &c
+			continue
+		}
+		doc.packages = append(doc.packages, c)
+	}
+	return doc, nil
+}
+
+// fromCycloneDX reads the CycloneDX document, the metadata component becomes the subject.
+func fromCycloneDX(data []byte) (*document, error) {
+	cdx := cdxDocument{}
+	if err := json.Unmarshal(data, &cdx); err != nil {
+		return nil, err
+	}
+	toComponent := func(c cdxComponent) component {
+		var licenses []string
+		for _, l := range c.Licenses {
+			switch {
+			case l.Expression != "":
+				licenses = append(licenses, l.Expression)
+			case l.License != nil && l.License.ID != "":
+				licenses = append(licenses, l.License.ID)
+			}
+		}
+		if len(licenses) > 1 {
+			for i, l := range licenses {
+				if strings.Contains(l, " ") {
+					licenses[i] = "(" + l + ")"
+				}
+			}
+		}
+		return component{
+			kind:    c.Type,
+			name:    c.Name,
+			version: c.Version,
+			purl:    c.PURL,
+			license: strings.Join(licenses, " AND "),
+		}
+	}
+
+	doc := &document{}
+	if cdx.Metadata != nil {
+		doc.created = cdx.Metadata.Timestamp
+		if cdx.Metadata.Component != nil {
+			subject := toComponent(*cdx.Metadata.Component)
+			doc.subject = &subject
+			doc.name = subject.name
+		}
+	}
+	for _, c := range cdx.Components {
+		doc.packages = append(doc.packages, toComponent(c))
+	}
+	return doc, nil
+}
+
+// toSPDX renders the SPDX document, the source document digest makes up its unique namespace.
+func (d *document) toSPDX(source []byte) *spdxDocument {
+	name := d.name
+	if name == "" {
+		name = "sbom"
+	}
+	spdx := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            spdxDocumentID,
+		Name:              name,
+		DocumentNamespace: fmt.Sprintf("https://shipwright.io/spdxdocs/%s-%x", url.PathEscape(name), sha256.Sum256(source)),
+		CreationInfo:      spdxCreationInfo{Created: d.created, Creators: []string{"Tool: shp"}},
+	}
+
+	toPackage := func(id string, c component) spdxPackage {
+		p := spdxPackage{
+			Name:             c.name,
+			SPDXID:           id,
+			VersionInfo:      c.version,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+		}
+		if cdxTypes[c.kind] {
+			p.PrimaryPackagePurpose = strings.ToUpper(c.kind)
+		}
+		if c.license != "" {
+			p.LicenseDeclared = c.license
+		}
+		if c.purl != "" {
+			p.ExternalRefs = []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  c.purl,
+			}}
+		}
+		return p
+	}
+
+	owner := spdxDocumentID
+	if d.subject != nil {
+		owner = "SPDXRef-Subject"
+		spdx.Packages = append(spdx.Packages, toPackage(owner, *d.subject))
+		spdx.Relationships = append(spdx.Relationships, spdxRelationship{spdxDocumentID, "DESCRIBES", owner})
+	}
+	for i, c := range d.packages {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		spdx.Packages = append(spdx.Packages, toPackage(id, c))
+		relationship := "CONTAINS"
+		if d.subject == nil {
+			relationship = "DESCRIBES"
+		}
+		spdx.Relationships = append(spdx.Relationships, spdxRelationship{owner, relationship, id})
+	}
+	return spdx
+}
+
+// toCycloneDX renders the CycloneDX document.
+func (d *document) toCycloneDX() *cdxDocument {
+	toComponent := func(c component) cdxComponent {
+		kind := c.kind
+		if !cdxTypes[kind] {
+			kind = "library"
+		}
+		cdx := cdxComponent{Type: kind, Name: c.name, Version: c.version, PURL: c.purl}
+		switch {
+		case c.license == "":
+		case strings.Contains(c.license, " "):
+			cdx.Licenses = []cdxLicense{{Expression: c.license}}
+		default:
+			cdx.Licenses = []cdxLicense{{License: &cdxLicenseID{ID: c.license}}}
+		}
+		return cdx
+	}
+
+	cdx := &cdxDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata:    &cdxMetadata{Timestamp: d.created},
+	}
+	if d.subject != nil {
+		subject := toComponent(*d.subject)
+		cdx.Metadata.Component = &subject
+	}
+	for _, c := range d.packages {
+		cdx.Components = append(cdx.Components, toComponent(c))
+	}
+	return cdx
+}
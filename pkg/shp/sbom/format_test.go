@@ -0,0 +1,117 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+const spdxDoc = `{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "registry.example.com/org/app",
+  "documentNamespace": "https://example.com/app",
+  "creationInfo": {"created": "2024-05-02T10:00:00Z", "creators": ["Tool: syft"]},
+  "packages": [
+    {"name": "registry.example.com/org/app", "SPDXID": "SPDXRef-Image", "primaryPackagePurpose": "CONTAINER", "downloadLocation": "NOASSERTION"},
+    {
+      "name": "openssl", "SPDXID": "SPDXRef-openssl", "versionInfo": "3.1.4", "downloadLocation": "NOASSERTION",
+      "licenseConcluded": "NOASSERTION", "licenseDeclared": "Apache-2.0",
+      "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:apk/alpine/openssl@3.1.4"}]
+    },
+    {"name": "musl", "SPDXID": "SPDXRef-musl", "versionInfo": "1.2.4", "downloadLocation": "NOASSERTION", "licenseConcluded": "MIT OR BSD-2-Clause"}
+  ],
+  "relationships": [{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-Image"}]
+}`
+
+func TestDetect(t *testing.T) {
+	g := o.NewWithT(t)
+
+	format, err := Detect([]byte(spdxDoc))
+	g.Expect(err).To(o.BeNil())
+	g.Expect(format).To(o.Equal(SPDX))
+
+	format, err = Detect([]byte(`{"bomFormat": "CycloneDX", "specVersion": "1.4"}`))
+	g.Expect(err).To(o.BeNil())
+	g.Expect(format).To(o.Equal(CycloneDX))
+
+	_, err = Detect([]byte(`{"predicateType": "https://slsa.dev/provenance/v0.2"}`))
+	g.Expect(err).To(o.MatchError("SBOM is neither a SPDX nor a CycloneDX JSON document"))
+	_, err = Detect([]byte("SPDXVersion: SPDX-2.3"))
+	g.Expect(err).To(o.MatchError(o.ContainSubstring("SBOM is not a JSON document")))
+
+	format, ok := FormatOf("application/vnd.cyclonedx+json; version=1.5")
+	g.Expect(ok).To(o.BeTrue())
+	g.Expect(format).To(o.Equal(CycloneDX))
+	_, ok = FormatOf("application/vnd.dev.cosign.artifact.sig.v1+json")
+	g.Expect(ok).To(o.BeFalse())
+}
+
+func TestConvert(t *testing.T) {
+	g := o.NewWithT(t)
+
+	// documents in the format already are not touched
+	data, err := Convert([]byte(spdxDoc), SPDX)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(string(data)).To(o.Equal(spdxDoc))
+
+	data, err = Convert([]byte(spdxDoc), CycloneDX)
+	g.Expect(err).To(o.BeNil())
+	cdx := cdxDocument{}
+	g.Expect(json.Unmarshal(data, &cdx)).To(o.Succeed())
+	g.Expect(cdx.BOMFormat).To(o.Equal("CycloneDX"))
+	g.Expect(cdx.Metadata.Timestamp).To(o.Equal("2024-05-02T10:00:00Z"))
+	g.Expect(cdx.Metadata.Component).To(o.Equal(&cdxComponent{Type: "container", Name: "registry.example.com/org/app"}))
+	g.Expect(cdx.Components).To(o.Equal([]cdxComponent{{
+		Type:     "library",
+		Name:     "openssl",
+		Version:  "3.1.4",
+		PURL:     "pkg:apk/alpine/openssl@3.1.4",
+		Licenses: []cdxLicense{{License: &cdxLicenseID{ID: "Apache-2.0"}}},
+	}, {
+		Type:     "library",
+		Name:     "musl",
+		Version:  "1.2.4",
+		Licenses: []cdxLicense{{Expression: "MIT OR BSD-2-Clause"}},
+	}}))
+
+	// and back, the packages survive the round trip
+	data, err = Convert(data, SPDX)
+	g.Expect(err).To(o.BeNil())
+	spdx := spdxDocument{}
+	g.Expect(json.Unmarshal(data, &spdx)).To(o.Succeed())
+	g.Expect(spdx.Name).To(o.Equal("registry.example.com/org/app"))
+	g.Expect(spdx.DocumentNamespace).To(o.HavePrefix("https://shipwright.io/spdxdocs/registry.example.com%2Forg%2Fapp-"))
+	g.Expect(spdx.Packages).To(o.HaveLen(3))
+	g.Expect(spdx.Packages[0].PrimaryPackagePurpose).To(o.Equal("CONTAINER"))
+	g.Expect(spdx.Packages[1]).To(o.Equal(spdxPackage{
+		Name:                  "openssl",
+		SPDXID:                "SPDXRef-Package-1",
+		VersionInfo:           "3.1.4",
+		DownloadLocation:      "NOASSERTION",
+		PrimaryPackagePurpose: "LIBRARY",
+		LicenseConcluded:      "NOASSERTION",
+		LicenseDeclared:       "Apache-2.0",
+		ExternalRefs:          []spdxExternalRef{{"PACKAGE-MANAGER", "purl", "pkg:apk/alpine/openssl@3.1.4"}},
+	}))
+	g.Expect(spdx.Relationships).To(o.ContainElements(
+		spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Subject"},
+		spdxRelationship{"SPDXRef-Subject", "CONTAINS", "SPDXRef-Package-2"},
+	))
+
+	// CycloneDX licenses are combined on a single expression
+	data, err = Convert([]byte(`{"bomFormat": "CycloneDX", "specVersion": "1.5", "components": [
+		{"type": "library", "name": "zlib", "licenses": [{"license": {"id": "Zlib"}}, {"expression": "MIT OR Apache-2.0"}]}
+	]}`), SPDX)
+	g.Expect(err).To(o.BeNil())
+	spdx = spdxDocument{}
+	g.Expect(json.Unmarshal(data, &spdx)).To(o.Succeed())
+	g.Expect(spdx.Name).To(o.Equal("sbom"))
+	g.Expect(spdx.Packages[0].LicenseDeclared).To(o.Equal("Zlib AND (MIT OR Apache-2.0)"))
+	g.Expect(spdx.Relationships).To(o.Equal([]spdxRelationship{{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Package-1"}}))
+
+	_, err = ParseFormat("syft-json")
+	g.Expect(err).To(o.MatchError("unsupported SBOM format \"syft-json\", expected one of [spdx cyclonedx]"))
+}
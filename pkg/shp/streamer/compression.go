@@ -0,0 +1,89 @@
+package streamer
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression algorithm employed on the uploaded data.
+type Compression string
+
+const (
+	// CompressionNone the data is uploaded as-is.
+	CompressionNone Compression = ""
+	// CompressionGzip the data is compressed with gzip.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd the data is compressed with zstd.
+	CompressionZstd Compression = "zstd"
+)
+
+// Compressions the compression algorithms supported.
+var Compressions = []Compression{CompressionGzip, CompressionZstd}
+
+// levelRanges the compression levels accepted by each algorithm.
+var levelRanges = map[Compression][2]int{
+	CompressionGzip: {gzip.BestSpeed, gzip.BestCompression},
+	CompressionZstd: {1, 22},
+}
+
+// ValidateCompression checks the algorithm is supported, and the level is within its range, zero
+// means the algorithm default level.
+func ValidateCompression(c Compression, level int) error {
+	if c == CompressionNone {
+		if level != 0 {
+			return fmt.Errorf("compression level %d requires a compression algorithm", level)
+		}
+		return nil
+	}
+	levels, ok := levelRanges[c]
+	if !ok {
+		return fmt.Errorf("unsupported compression %q, supported are %v", c, Compressions)
+	}
+	if level != 0 && (level < levels[0] || level > levels[1]) {
+		return fmt.Errorf("invalid %s compression level %d, it must be between %d and %d", c, level, levels[0], levels[1])
+	}
+	return nil
+}
+
+// NewCompressor wraps the writer with the compression algorithm, closing the returned writer
+// flushes the compressed data, but does not close the informed writer.
+func NewCompressor(w io.Writer, c Compression, level int) (io.WriteCloser, error) {
+	switch c {
+	case CompressionGzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
+	case CompressionZstd:
+		opts := []zstd.EOption{}
+		if level != 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		return zstd.NewWriter(w, opts...)
+	}
+	return nopWriteCloser{Writer: w}, nil
+}
+
+// nopWriteCloser writer without compression, closing it is a no-op.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close no-op.
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// tarCmdFor returns the tar command extracting the data compressed with the algorithm.
+func tarCmdFor(c Compression) []string {
+	switch c {
+	case CompressionGzip:
+		return []string{"tar", "xzfv", "-", "-C"}
+	case CompressionZstd:
+		return []string{"tar", "--zstd", "-xfv", "-", "-C"}
+	}
+	return tarCmd
+}
@@ -0,0 +1,74 @@
+package streamer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/shipwright-io/cli/test/mock"
+)
+
+func TestValidateCompression(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(ValidateCompression(CompressionNone, 0)).To(o.Succeed())
+	g.Expect(ValidateCompression(CompressionGzip, 9)).To(o.Succeed())
+	g.Expect(ValidateCompression(CompressionZstd, 22)).To(o.Succeed())
+	g.Expect(ValidateCompression(CompressionNone, 3)).
+		To(o.MatchError("compression level 3 requires a compression algorithm"))
+	g.Expect(ValidateCompression(CompressionGzip, 10)).
+		To(o.MatchError("invalid gzip compression level 10, it must be between 1 and 9"))
+	g.Expect(ValidateCompression("lz4", 0)).
+		To(o.MatchError("unsupported compression \"lz4\", supported are [gzip zstd]"))
+}
+
+func TestStreamCompressed(t *testing.T) {
+	decompress := map[Compression]func(r io.Reader) (io.Reader, error){
+		CompressionGzip: func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		},
+		CompressionZstd: func(r io.Reader) (io.Reader, error) {
+			return zstd.NewReader(r)
+		},
+	}
+	commands := map[Compression][]string{
+		CompressionGzip: {"tar", "xzfv", "-", "-C", "/"},
+		CompressionZstd: {"tar", "--zstd", "-xfv", "-", "-C", "/"},
+	}
+
+	for _, c := range Compressions {
+		t.Run(string(c), func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			f := mock.NewFakeClientset(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"},
+			})
+			s := NewStreamer(f.RESTConfig(), f.Clientset())
+			re := mock.NewFakeRemoteExecutor(nil)
+			s.remoteExecutor = re
+			s.SetCompression(c, 1)
+
+			data := bytes.Repeat([]byte("standard input "), 1024)
+			err := s.Stream(&Target{Namespace: metav1.NamespaceDefault, Pod: "pod", Container: "container", BaseDir: "/"},
+				func(w io.Writer) error {
+					_, err := w.Write(data)
+					return err
+				}, len(data))
+			g.Expect(err).To(o.BeNil())
+			g.Expect(re.Command()).To(o.Equal(commands[c]))
+			g.Expect(len(re.Stdin())).To(o.BeNumerically("<", len(data)))
+
+			r, err := decompress[c](bytes.NewReader([]byte(re.Stdin())))
+			g.Expect(err).To(o.BeNil())
+			decompressed, err := io.ReadAll(r)
+			g.Expect(err).To(o.BeNil())
+			g.Expect(decompressed).To(o.Equal(data))
+		})
+	}
+}
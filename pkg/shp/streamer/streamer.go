@@ -1,7 +1,6 @@
 package streamer
 
 import (
-	"fmt"
 	"io"
 	"os"
 	"sync"
@@ -12,7 +11,7 @@ import (
 	"k8s.io/kubectl/pkg/cmd/exec"
 	"k8s.io/kubectl/pkg/util/interrupt"
 
-	progressbar "github.com/schollz/progressbar/v3"
+	"github.com/shipwright-io/cli/pkg/shp/util"
 )
 
 // Streamer represents the actor that streams data onto a POD, running on Kubernetes. It does so via
@@ -21,6 +20,8 @@ type Streamer struct {
 	restConfig     *rest.Config         // rest API client configuration
 	clientset      kubernetes.Interface // kubernetes client
 	remoteExecutor exec.RemoteExecutor  // overwritten during testing
+	compression    Compression          // compression of the streamed data
+	level          int                  // compression level, zero means the algorithm default
 }
 
 // WriterFn exposes the writer interface, receives the data to be streamed.
@@ -41,6 +42,25 @@ func (s *Streamer) execute(opts *exec.ExecOptions) error {
 	return opts.Run()
 }
 
+// SetCompression compresses the streamed data with the algorithm and level, the data is extracted
+// on the POD by tar, which must support the algorithm.
+func (s *Streamer) SetCompression(c Compression, level int) {
+	s.compression, s.level = c, level
+}
+
+// write executes the writerFn on the compressed writer, accounting the data written on the progress.
+func (s *Streamer) write(w io.Writer, writerFn WriterFn, progress io.Writer) error {
+	compressor, err := NewCompressor(w, s.compression, s.level)
+	if err != nil {
+		return err
+	}
+	err = writerFn(io.MultiWriter(progress, compressor))
+	if closeErr := compressor.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
 // Stream the data onto the informed target, and it uses the BaseDir as the path to store the data on
 // the running POD. The writerFn is employed to expose the writer interface to callers.
 func (s *Streamer) Stream(target *Target, writerFn WriterFn, size int) error {
@@ -54,31 +74,15 @@ func (s *Streamer) Stream(target *Target, writerFn WriterFn, size int) error {
 	errCh := make(chan error, 1)
 	defer close(errCh)
 
+	// the progress accounts the data before compression, thus it matches the informed size
+	progress := util.NewProgress(os.Stderr, "Uploading local source...", int64(size))
+
 	go func() {
 		defer writer.Close()
-		errCh <- writerFn(writer)
+		errCh <- s.write(writer, writerFn, progress)
 		wg.Done()
 	}()
 
-	progress := progressbar.NewOptions(size,
-		progressbar.OptionSetWriter(os.Stderr),
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowBytes(true),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetPredictTime(false),
-		progressbar.OptionSetDescription("Uploading local source..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]"}),
-		progressbar.OptionOnCompletion(func() {
-			fmt.Fprintln(os.Stdout)
-		}),
-	)
-	defer progress.Close()
-
 	// defines the target pod using namespace and pod name, and wires up the local stdin with the
 	// pipe reader interface, therefore all data written on the writer interface will be redirected
 	// to the pod
@@ -88,7 +92,7 @@ func (s *Streamer) Stream(target *Target, writerFn WriterFn, size int) error {
 		ContainerName: target.Container,
 		Stdin:         true,
 		IOStreams: genericclioptions.IOStreams{
-			In:     io.TeeReader(reader, progress),
+			In:     reader,
 			Out:    io.Discard,
 			ErrOut: os.Stderr,
 		},
@@ -99,12 +103,13 @@ func (s *Streamer) Stream(target *Target, writerFn WriterFn, size int) error {
 		StreamOptions: streamOpts,
 		Config:        s.restConfig,
 		PodClient:     s.clientset.CoreV1(),
-		Command:       append(tarCmd, target.BaseDir),
+		Command:       append(tarCmdFor(s.compression), target.BaseDir),
 		Executor:      s.remoteExecutor,
 	}
 	if err := s.execute(execOpts); err != nil {
 		return err
 	}
+	progress.Finish()
 
 	// blocking the execution, waiting for writerFn to return either error or nil
 	wg.Wait()
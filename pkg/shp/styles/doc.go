@@ -0,0 +1,3 @@
+// Package styles decorates the terminal output with colors, honoring the "--no-color" flag, the
+// NO_COLOR environment variable, and whether the output is a terminal at all.
+package styles
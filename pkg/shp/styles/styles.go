@@ -0,0 +1,114 @@
+package styles
+
+import (
+	"hash/fnv"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"golang.org/x/term"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NoColorEnv environment variable disabling colors when set, see https://no-color.org.
+const NoColorEnv = "NO_COLOR"
+
+// ANSI escape sequences, the colors have the same length, thus columns aligned by a tabwriter stay
+// aligned when every cell is decorated, regardless of the color.
+const (
+	reset  = "\033[0m"
+	bold   = "\033[1m"
+	gray   = "\033[90m"
+	red    = "\033[31m"
+	green  = "\033[32m"
+	yellow = "\033[33m"
+)
+
+// prefixColors palette for log prefixes, picked by the prefix name.
+var prefixColors = []string{
+	"\033[34m", // blue
+	"\033[35m", // magenta
+	"\033[36m", // cyan
+	"\033[32m", // green
+	"\033[33m", // yellow
+}
+
+// enabled controls whether colors are employed, disabled by default.
+var enabled atomic.Bool
+
+// Configure enables colors when the writer is a terminal, unless disabled by the flag or the
+// NO_COLOR environment variable.
+func Configure(w io.Writer, noColor bool) {
+	if _, set := os.LookupEnv(NoColorEnv); set || noColor {
+		enabled.Store(false)
+		return
+	}
+	f, ok := w.(*os.File)
+	enabled.Store(ok && term.IsTerminal(int(f.Fd())))
+}
+
+// SetEnabled overwrites the color detection.
+func SetEnabled(v bool) {
+	enabled.Store(v)
+}
+
+// Enabled tells whether colors are employed.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// decorate wraps the text with the informed escape sequence, when colors are enabled.
+func decorate(sequence, s string) string {
+	if !Enabled() {
+		return s
+	}
+	return sequence + s + reset
+}
+
+// Bold highlights resource names.
+func Bold(s string) string {
+	return decorate(bold, s)
+}
+
+// Success decorates text describing a successful outcome.
+func Success(s string) string {
+	return decorate(green, s)
+}
+
+// Failure decorates text describing a failed outcome.
+func Failure(s string) string {
+	return decorate(red, s)
+}
+
+// Warning decorates text describing an ongoing or pending state.
+func Warning(s string) string {
+	return decorate(yellow, s)
+}
+
+// Faint decorates text describing an unknown state.
+func Faint(s string) string {
+	return decorate(gray, s)
+}
+
+// Condition decorates text according to the status of the condition it describes, e.g. the
+// "Succeeded" condition of BuildRuns.
+func Condition(status corev1.ConditionStatus, s string) string {
+	switch status {
+	case corev1.ConditionTrue:
+		return Success(s)
+	case corev1.ConditionFalse:
+		return Failure(s)
+	case corev1.ConditionUnknown:
+		return Warning(s)
+	default:
+		return Faint(s)
+	}
+}
+
+// Prefix decorates log prefixes, the same name always gets the same color.
+func Prefix(s string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return decorate(prefixColors[h.Sum32()%uint32(len(prefixColors))], s)
+}
@@ -0,0 +1,48 @@
+package styles
+
+import (
+	"os"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestStyles(t *testing.T) {
+	g := o.NewWithT(t)
+	defer SetEnabled(false)
+
+	SetEnabled(false)
+	g.Expect(Bold("name")).To(o.Equal("name"))
+	g.Expect(Condition(corev1.ConditionTrue, "Succeeded")).To(o.Equal("Succeeded"))
+
+	SetEnabled(true)
+	g.Expect(Bold("name")).To(o.Equal("\033[1mname\033[0m"))
+	g.Expect(Condition(corev1.ConditionTrue, "Succeeded")).To(o.Equal("\033[32mSucceeded\033[0m"))
+	g.Expect(Condition(corev1.ConditionFalse, "Failed")).To(o.Equal("\033[31mFailed\033[0m"))
+	g.Expect(Condition(corev1.ConditionUnknown, "Running")).To(o.Equal("\033[33mRunning\033[0m"))
+	g.Expect(Prefix("[step-build]")).To(o.Equal(Prefix("[step-build]")))
+
+	// the colors must keep tabwriter columns aligned
+	g.Expect(len(Success("x"))).To(o.Equal(len(Failure("x"))))
+	g.Expect(len(Warning("x"))).To(o.Equal(len(Faint("x"))))
+}
+
+func TestConfigure(t *testing.T) {
+	g := o.NewWithT(t)
+	defer SetEnabled(false)
+
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	g.Expect(err).To(o.BeNil())
+	defer f.Close()
+
+	SetEnabled(true)
+	Configure(f, false)
+	g.Expect(Enabled()).To(o.BeFalse(), "regular files are not terminals")
+
+	t.Setenv(NoColorEnv, "")
+	SetEnabled(true)
+	Configure(os.Stdout, false)
+	g.Expect(Enabled()).To(o.BeFalse(), "NO_COLOR is set")
+}
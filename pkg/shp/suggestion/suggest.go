@@ -88,3 +88,15 @@ func Find(slice []string, val string) (int, bool) {
 	}
 	return -1, false
 }
+
+// SuggestionsFor returns the candidates close to the typed name, either by levenshtein distance
+// or by prefix, in the same order of the candidates.
+func SuggestionsFor(typedName string, candidates []string) []string {
+	suggestions := []string{}
+	for _, c := range candidates {
+		if suggestsByPrefixOrLd(typedName, c, 2) != "" {
+			suggestions = append(suggestions, c)
+		}
+	}
+	return suggestions
+}
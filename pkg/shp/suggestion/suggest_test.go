@@ -1,4 +1,4 @@
-package suggestion
+package suggestion_test
 
 import (
 	"fmt"
@@ -7,6 +7,7 @@ import (
 
 	"github.com/onsi/gomega"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/build"
+	"github.com/shipwright-io/cli/pkg/shp/suggestion"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
@@ -17,9 +18,19 @@ func TestSuggestion(t *testing.T) {
 	genericOpts := &genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
 	cmd := build.Command(nil, genericOpts)
 
-	err := SubcommandsRequiredWithSuggestions(cmd, []string{"cr"})
+	err := suggestion.SubcommandsRequiredWithSuggestions(cmd, []string{"cr"})
 
 	expected := fmt.Sprintf("unknown command %q for %q\n\nDid you mean this?\n\t%s\n", "cr", "build", "create")
 
 	g.Expect(err.Error()).To(gomega.Equal(expected))
 }
+
+func TestSuggestionsFor(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	candidates := []string{"buildah", "buildpacks-v3", "kaniko", "ko"}
+
+	g.Expect(suggestion.SuggestionsFor("kanico", candidates)).To(gomega.Equal([]string{"kaniko"}))
+	g.Expect(suggestion.SuggestionsFor("build", candidates)).To(gomega.Equal([]string{"buildah", "buildpacks-v3"}))
+	g.Expect(suggestion.SuggestionsFor("source-to-image", candidates)).To(gomega.BeEmpty())
+}
@@ -0,0 +1,68 @@
+package tail
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LineFilter decides whether the container log line is printed.
+type LineFilter func(container, line string) bool
+
+// MatchFilter keeps the lines matching the regular expression.
+func MatchFilter(re *regexp.Regexp) LineFilter {
+	return func(_, line string) bool {
+		return re.MatchString(line)
+	}
+}
+
+// StepFilter keeps the lines of the informed build strategy steps, named either with or without
+// the "step-" container prefix.
+func StepFilter(steps []string) LineFilter {
+	selected := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		selected[strings.TrimPrefix(step, stepPrefix)] = true
+	}
+	return func(container, _ string) bool {
+		return strings.HasPrefix(container, stepPrefix) && selected[strings.TrimPrefix(container, stepPrefix)]
+	}
+}
+
+// Filters composes the informed filters, the lines are kept when accepted by all of them. Returns
+// nil when there are no filters, thus all lines are kept.
+func Filters(filters ...LineFilter) LineFilter {
+	composed := []LineFilter{}
+	for _, f := range filters {
+		if f != nil {
+			composed = append(composed, f)
+		}
+	}
+	if len(composed) == 0 {
+		return nil
+	}
+	return func(container, line string) bool {
+		for _, f := range composed {
+			if !f(container, line) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// FilterLines returns the lines of the container logs kept by the filter, all of them when the
+// filter is nil.
+func FilterLines(filter LineFilter, container, logs string) string {
+	if filter == nil {
+		return logs
+	}
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(logs, "\n") {
+		if line == "" {
+			continue
+		}
+		if filter(container, strings.TrimSuffix(line, "\n")) {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
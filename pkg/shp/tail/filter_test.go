@@ -0,0 +1,32 @@
+package tail
+
+import (
+	"regexp"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func Test_Filters(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(Filters()).To(o.BeNil())
+	g.Expect(Filters(nil, nil)).To(o.BeNil())
+
+	filter := Filters(MatchFilter(regexp.MustCompile(`(?i)error`)), StepFilter([]string{"build-and-push", "step-push"}))
+	g.Expect(filter("step-build-and-push", "ERROR: denied")).To(o.BeTrue())
+	g.Expect(filter("step-push", "an error")).To(o.BeTrue())
+	g.Expect(filter("step-build-and-push", "pushing layers")).To(o.BeFalse())
+	g.Expect(filter("step-source-default", "error cloning")).To(o.BeFalse())
+	g.Expect(filter("prepare", "error")).To(o.BeFalse())
+}
+
+func Test_FilterLines(t *testing.T) {
+	g := o.NewWithT(t)
+
+	logs := "STEP 1/3: FROM ubi\nerror: not found\nSTEP 2/3: COPY\n"
+	g.Expect(FilterLines(nil, "step-build", logs)).To(o.Equal(logs))
+	g.Expect(FilterLines(MatchFilter(regexp.MustCompile(`^STEP`)), "step-build", logs)).
+		To(o.Equal("STEP 1/3: FROM ubi\nSTEP 2/3: COPY\n"))
+	g.Expect(FilterLines(StepFilter([]string{"push"}), "step-build", logs)).To(o.BeEmpty())
+}
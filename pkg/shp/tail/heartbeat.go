@@ -0,0 +1,119 @@
+package tail
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// maxHeartbeatCheck upper bound of the interval between the checks for quiet periods.
+const maxHeartbeatCheck = 5 * time.Second
+
+// Heartbeat writes a message when nothing else has been written on the output for the informed
+// interval, CI systems kill the jobs which stop producing output, i.e. while a step is quiet for a
+// long time. The writes are passed through, recording the moment of the last one.
+type Heartbeat struct {
+	lock sync.Mutex
+
+	out      io.Writer        // final writer
+	interval time.Duration    // quiet period before the message is written
+	message  func() string    // renders the message, called without the lock held
+	now      func() time.Time // current time, replaceable for testing purposes
+	last     time.Time        // moment of the last write
+
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	started bool
+	stopped bool
+}
+
+// NewHeartbeat instantiate a Heartbeat on the informed writer.
+func NewHeartbeat(out io.Writer, interval time.Duration, message func() string) *Heartbeat {
+	return &Heartbeat{
+		out:      out,
+		interval: interval,
+		message:  message,
+		now:      time.Now,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Write passes the bytes through, postponing the next message.
+func (h *Heartbeat) Write(p []byte) (int, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.last = h.now()
+	return h.out.Write(p)
+}
+
+// SetOutput redirects the next writes to the informed writer.
+func (h *Heartbeat) SetOutput(out io.Writer) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.out = out
+}
+
+// beat writes the message when the output has been quiet for the interval.
+func (h *Heartbeat) beat() {
+	h.lock.Lock()
+	quiet := h.now().Sub(h.last) >= h.interval
+	h.lock.Unlock()
+	if !quiet {
+		return
+	}
+	msg := h.message()
+	if msg == "" {
+		return
+	}
+	// written as any other output, thus the next message waits for another quiet period
+	_, _ = h.Write([]byte(msg))
+}
+
+// Start checks for quiet periods until stopped, the interval is counted from now.
+func (h *Heartbeat) Start() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.started || h.stopped {
+		return
+	}
+	h.started = true
+	h.last = h.now()
+	check := h.interval / 4
+	switch {
+	case check > maxHeartbeatCheck:
+		check = maxHeartbeatCheck
+	case check <= 0:
+		check = h.interval
+	}
+	go func() {
+		defer close(h.doneCh)
+		ticker := time.NewTicker(check)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-h.stopCh:
+				return
+			case <-ticker.C:
+				h.beat()
+			}
+		}
+	}()
+}
+
+// Stop stops checking for quiet periods, it's safe to call it more than once.
+func (h *Heartbeat) Stop() {
+	h.lock.Lock()
+	if h.stopped {
+		h.lock.Unlock()
+		return
+	}
+	h.stopped = true
+	started := h.started
+	h.lock.Unlock()
+
+	if started {
+		close(h.stopCh)
+		<-h.doneCh
+	}
+}
@@ -0,0 +1,45 @@
+package tail
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestHeartbeat(t *testing.T) {
+	g := o.NewWithT(t)
+
+	out := &lockedBuffer{}
+	h := NewHeartbeat(out, 200*time.Millisecond, func() string {
+		return "Still running step 2/3: build\n"
+	})
+	h.Start()
+	defer h.Stop()
+
+	// the output is not quiet, no message is written
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(h, "line %d\n", i)
+		time.Sleep(10 * time.Millisecond)
+	}
+	g.Expect(out.String()).To(o.Equal("line 0\nline 1\nline 2\nline 3\nline 4\n"))
+
+	g.Eventually(out.String).Should(o.HaveSuffix("line 4\nStill running step 2/3: build\n"))
+	g.Eventually(out.String).Should(o.HaveSuffix("Still running step 2/3: build\nStill running step 2/3: build\n"))
+
+	h.Stop()
+	fmt.Fprintln(h, "late")
+	g.Expect(out.String()).To(o.HaveSuffix("late\n"))
+}
+
+func TestHeartbeatEmptyMessage(t *testing.T) {
+	g := o.NewWithT(t)
+
+	out := &lockedBuffer{}
+	h := NewHeartbeat(out, time.Millisecond, func() string { return "" })
+	h.Start()
+	time.Sleep(20 * time.Millisecond)
+	h.Stop()
+	g.Expect(out.String()).To(o.BeEmpty())
+}
@@ -0,0 +1,62 @@
+package tail
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// PrefixWriter prefixes every line written, thus the output of concurrent sources can be told
+// apart when interleaved. Lines are written whole, partial lines are buffered until completed, and
+// the writers sharing the same lock never interleave within a line.
+type PrefixWriter struct {
+	lock   *sync.Mutex  // shared by the writers on the same final writer
+	out    io.Writer    // final writer
+	prefix []byte       // written before every line
+	buf    bytes.Buffer // partial line not written yet
+}
+
+// NewPrefixWriter instantiate a PrefixWriter on the informed writer, the lock must be shared with
+// the other writers on it.
+func NewPrefixWriter(out io.Writer, lock *sync.Mutex, prefix string) *PrefixWriter {
+	return &PrefixWriter{lock: lock, out: out, prefix: []byte(prefix)}
+}
+
+// Write writes the complete lines with the prefix, buffering the remainder.
+func (w *PrefixWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.buf.Next(i + 1)); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Unwrap returns the final writer.
+func (w *PrefixWriter) Unwrap() io.Writer {
+	return w.out
+}
+
+// Flush writes the buffered partial line, terminated.
+func (w *PrefixWriter) Flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	line := append(w.buf.Bytes(), '\n')
+	w.buf.Reset()
+	return w.writeLine(line)
+}
+
+// writeLine writes the prefix and the line at once, the lock must be held.
+func (w *PrefixWriter) writeLine(line []byte) error {
+	_, err := w.out.Write(append(append([]byte{}, w.prefix...), line...))
+	return err
+}
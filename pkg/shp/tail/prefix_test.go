@@ -0,0 +1,31 @@
+package tail
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	g := o.NewWithT(t)
+
+	var out bytes.Buffer
+	lock := &sync.Mutex{}
+	amd64 := NewPrefixWriter(&out, lock, "[linux/amd64] ")
+	arm64 := NewPrefixWriter(&out, lock, "[linux/arm64] ")
+
+	fmt.Fprint(amd64, "first ")
+	fmt.Fprintln(arm64, "one\ntwo")
+	fmt.Fprintln(amd64, "line")
+	fmt.Fprint(arm64, "partial")
+	g.Expect(amd64.Flush()).To(o.Succeed())
+	g.Expect(arm64.Flush()).To(o.Succeed())
+
+	g.Expect(out.String()).To(o.Equal("[linux/arm64] one\n" +
+		"[linux/arm64] two\n" +
+		"[linux/amd64] first line\n" +
+		"[linux/arm64] partial\n"))
+}
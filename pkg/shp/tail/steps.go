@@ -0,0 +1,72 @@
+package tail
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+)
+
+// stepPrefix the prefix of the build pod containers running the build strategy steps.
+const stepPrefix = "step-"
+
+// Step position of a build strategy step amongst the steps of the build pod.
+type Step struct {
+	Name  string // step name, the container name without prefix
+	Index int    // one-based position of the step
+	Total int    // amount of steps
+}
+
+// String renders the step position and name, like "step 2/7: build-and-push".
+func (s Step) String() string {
+	return fmt.Sprintf("step %d/%d: %s", s.Index, s.Total, s.Name)
+}
+
+// StepsOf returns the build strategy steps of the pod indexed by container name, the pod spec
+// carries the step containers in the order they are executed.
+func StepsOf(pod *corev1.Pod) map[string]Step {
+	names := []string{}
+	for _, c := range pod.Spec.Containers {
+		if strings.HasPrefix(c.Name, stepPrefix) {
+			names = append(names, c.Name)
+		}
+	}
+	steps := make(map[string]Step, len(names))
+	for i, name := range names {
+		steps[name] = Step{Name: strings.TrimPrefix(name, stepPrefix), Index: i + 1, Total: len(names)}
+	}
+	return steps
+}
+
+// stepLog tracks the log of a single step, which is introduced by a header before its first line.
+type stepLog struct {
+	step    Step
+	headed  bool      // the header has been printed
+	started time.Time // timestamp of the first line
+	running bool      // the step has been recorded as running
+}
+
+// header returns the line introducing the step log.
+func (s *stepLog) header() string {
+	return styles.Bold(s.step.String())
+}
+
+// footer returns the line concluding the step log with its duration, measured from the first line
+// when seen, since all step containers are started at once and wait for the previous steps.
+func (s *stepLog) footer(terminated *corev1.ContainerStateTerminated) string {
+	start := s.started
+	if start.IsZero() {
+		start = terminated.StartedAt.Time
+	}
+	elapsed := terminated.FinishedAt.Sub(start).Round(time.Second)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if terminated.ExitCode != 0 {
+		return styles.Failure(fmt.Sprintf("%s failed after %s (exit code %d)", s.step, elapsed, terminated.ExitCode))
+	}
+	return styles.Success(fmt.Sprintf("%s completed in %s", s.step, elapsed))
+}
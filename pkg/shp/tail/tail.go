@@ -3,24 +3,57 @@ package tail
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+)
+
+const (
+	// defaultMaxRetries amount of consecutive attempts to resume a broken log stream.
+	defaultMaxRetries = 5
+	// defaultRetryInterval initial interval between attempts, doubled on each attempt.
+	defaultRetryInterval = 1 * time.Second
+	// maxRetryInterval upper bound of the interval between attempts.
+	maxRetryInterval = 8 * time.Second
+	// defaultStartupTimeout how long to wait for a container to leave the waiting state, i.e. while
+	// its image is pulled, before streaming its logs.
+	defaultStartupTimeout = 5 * time.Minute
 )
 
 // Tail represents a "tail" command streaming log outputs to stdout interface, and errors are written
-// to stderr interface directly.
+// to stderr interface directly. The stream starts once the container leaves the waiting state, and
+// when it breaks while the container is still running, or being restarted, the stream is resumed
+// from the last line seen, up to a bounded amount of retries.
 type Tail struct {
-	ctx       context.Context      // global context
+	ctx       context.Context      // global context, cancelled on stop as well
+	cancel    context.CancelFunc   // cancels the streams and client calls in flight
 	clientset kubernetes.Interface // kubernetes client instance
-	stopCh    chan bool            // stop channel
-	stopLock  sync.Mutex
-	stopped   bool
+	stopOnce  sync.Once
+	stopped   atomic.Bool // Stop has been called
+
+	maxRetries     int           // consecutive attempts to resume the stream
+	retryInterval  time.Duration // initial interval between attempts
+	startupTimeout time.Duration // how long to wait for the container to start
+
+	steps     map[string]map[string]Step // build strategy steps, indexed by pod and container name
+	running   map[string]runningStep     // steps whose logs are streamed, indexed by pod/container
+	stepsLock sync.Mutex
+
+	filter LineFilter // selects the lines printed, nil prints all of them
+	since  time.Time  // lines logged before it are skipped, zero replays the logs from the start
 
 	stdout io.Writer
 	stderr io.Writer
@@ -36,63 +69,403 @@ func (t *Tail) SetStderr(w io.Writer) {
 	t.stderr = w
 }
 
-// Start start streaming logs for informed target.
+// SetStartupTimeout overrides how long to wait for containers to start before streaming their
+// logs, the context deadline prevails when sooner.
+func (t *Tail) SetStartupTimeout(d time.Duration) {
+	t.startupTimeout = d
+}
+
+// SetFilter selects the log lines printed, the steps without lines selected are omitted altogether.
+func (t *Tail) SetFilter(filter LineFilter) {
+	t.filter = filter
+}
+
+// SetSince skips the lines logged before the informed moment, attaching to the containers already
+// running mid-flight, zero replays the logs from the start. The containers terminated before it are
+// skipped altogether, and the ones started after it are streamed from their start.
+func (t *Tail) SetSince(since time.Time) {
+	t.since = since
+}
+
+// startOf returns the moment the container logs are streamed from, zero meaning its start, and
+// false when the container has terminated before the since moment, thus there is nothing to stream.
+// The container start time prevails, as the node clock may lag behind the local one.
+func (t *Tail) startOf(status *corev1.ContainerStatus) (time.Time, bool) {
+	if t.since.IsZero() || status == nil {
+		return time.Time{}, true
+	}
+	switch {
+	case status.State.Terminated != nil:
+		if status.State.Terminated.FinishedAt.Time.Before(t.since) {
+			return time.Time{}, false
+		}
+		if !status.State.Terminated.StartedAt.Time.Before(t.since) {
+			return time.Time{}, true
+		}
+	case status.State.Running != nil:
+		if !status.State.Running.StartedAt.Time.Before(t.since) {
+			return time.Time{}, true
+		}
+	}
+	return t.since, true
+}
+
+// SetSteps informs the build strategy steps of the pod, the step logs are introduced by a header
+// carrying the step position, and concluded by the step duration.
+func (t *Tail) SetSteps(podName string, steps map[string]Step) {
+	t.stepsLock.Lock()
+	defer t.stepsLock.Unlock()
+	if t.steps == nil {
+		t.steps = map[string]map[string]Step{}
+	}
+	t.steps[podName] = steps
+}
+
+// stepLogOf returns the step log tracker for the pod container, nil when it's not a known step.
+func (t *Tail) stepLogOf(podName, container string) *stepLog {
+	t.stepsLock.Lock()
+	defer t.stepsLock.Unlock()
+	step, ok := t.steps[podName][container]
+	if !ok {
+		return nil
+	}
+	return &stepLog{step: step}
+}
+
+// runningStep a step which has started logging, and the moment of its first line.
+type runningStep struct {
+	step    Step
+	started time.Time
+}
+
+// setRunning records the step as running once its first line is read, the step containers are
+// started all at once and wait for the previous steps, thus a started container is not enough.
+func (t *Tail) setRunning(podName, container string, sl *stepLog, started time.Time) {
+	if sl == nil || sl.running {
+		return
+	}
+	sl.running = true
+	if started.IsZero() {
+		started = time.Now()
+	}
+	t.stepsLock.Lock()
+	defer t.stepsLock.Unlock()
+	if t.running == nil {
+		t.running = map[string]runningStep{}
+	}
+	t.running[podName+"/"+container] = runningStep{step: sl.step, started: started}
+}
+
+// clearRunning records the step as no longer running.
+func (t *Tail) clearRunning(podName, container string) {
+	t.stepsLock.Lock()
+	defer t.stepsLock.Unlock()
+	delete(t.running, podName+"/"+container)
+}
+
+// RunningStep returns the most advanced step which has started logging and is not terminated yet,
+// along with the moment of its first line, the steps run one after the other.
+func (t *Tail) RunningStep() (Step, time.Time, bool) {
+	t.stepsLock.Lock()
+	defer t.stepsLock.Unlock()
+	var current *runningStep
+	for key := range t.running {
+		rs := t.running[key]
+		if current == nil || rs.step.Index > current.step.Index {
+			current = &rs
+		}
+	}
+	if current == nil {
+		return Step{}, time.Time{}, false
+	}
+	return current.step, current.started, true
+}
+
+// Start start streaming logs for informed target, until the container terminates or the Tail is
+// stopped.
 func (t *Tail) Start(ns, podName, container string) {
-	go func() {
-		podClient := t.clientset.CoreV1().Pods(ns)
-		stream, err := podClient.GetLogs(podName, &corev1.PodLogOptions{
-			Follow:    true,
-			Container: container,
-		}).Stream(t.ctx)
-		if err != nil {
+	go t.follow(ns, podName, container)
+}
+
+// follow streams the container logs, resuming the stream when it breaks before the container is
+// terminated.
+func (t *Tail) follow(ns, podName, container string) {
+	prefix := styles.Prefix(fmt.Sprintf("[%s]", strings.TrimPrefix(container, stepPrefix)))
+	sl := t.stepLogOf(podName, container)
+
+	klog.V(2).Infof("Waiting for container %q of pod %q to start", container, podName)
+	status, err := t.waitForContainer(ns, podName, container)
+	if err != nil {
+		// stopping, or cancelling the context, is not an error to report
+		switch {
+		case t.isStopped(), errors.Is(err, context.Canceled):
+		case errors.Is(err, context.DeadlineExceeded), t.expired():
+			t.reportExpired(container)
+		default:
 			fmt.Fprintln(t.stderr, err)
+		}
+		return
+	}
+	since, stream := t.startOf(status)
+	if !stream {
+		klog.V(2).Infof("Container %q of pod %q terminated before %s, skipping its logs", container, podName, t.since)
+		return
+	}
+	defer t.clearRunning(podName, container)
+
+	retries := 0
+	for {
+		before := since
+		read, err := t.stream(ns, podName, container, prefix, &since, sl)
+		if t.isStopped() {
+			return
+		}
+		// the stream has been interrupted by the deadline, there is no point in resuming it
+		if t.expired() {
+			t.reportExpired(container)
+			return
+		}
+		if since.After(before) {
+			retries = 0
+		} else if read > 0 {
+			// lines without timestamps can't be resumed without printing them again
 			return
 		}
-		defer func() {
-			if err := stream.Close(); err != nil {
-				fmt.Fprintf(t.stderr, "Failed to close stream: %v", err)
-			}
-		}()
 
-		go func() {
-			<-t.stopCh
-			if err := stream.Close(); err != nil {
-				fmt.Fprintf(t.stderr, "Failed to close stream: %v", err)
+		resume, terminated, reason := t.shouldResume(ns, podName, container, err)
+		if !resume {
+			klog.V(2).Infof("Log stream of container %q of pod %q ended: %v", container, podName, reason)
+			if reason != nil {
+				fmt.Fprintln(t.stderr, reason)
 			}
-		}()
+			// with a filter, the steps without any line selected are omitted
+			if sl != nil && terminated != nil && (sl.headed || t.filter == nil) {
+				fmt.Fprintln(t.stdout, sl.footer(terminated))
+			}
+			return
+		}
+		if retries >= t.maxRetries {
+			fmt.Fprintf(t.stderr, "Unable to resume the logs of container %q after %d attempts: %v\n", container, retries, reason)
+			return
+		}
 
-		containerName := strings.TrimPrefix(container, "step-")
-		sc := bufio.NewScanner(stream)
-		for sc.Scan() {
-			fmt.Fprintf(t.stdout, "[%s] %s\n", containerName, sc.Text())
+		interval := t.retryInterval << retries
+		if interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+		retries++
+		klog.V(2).Infof("Resuming the logs of container %q of pod %q in %s (attempt %d of %d): %v",
+			container, podName, interval, retries, t.maxRetries, reason)
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// stream prints the container logs until the stream ends, skipping the lines already seen before
+// the informed moment, which is updated as lines are printed. The step header, when tracking a
+// step, is printed before the first line. Returns the amount of lines read, printed or filtered.
+func (t *Tail) stream(ns, podName, container, prefix string, since *time.Time, sl *stepLog) (int, error) {
+	opts := &corev1.PodLogOptions{
+		Follow:     true,
+		Container:  container,
+		Timestamps: true,
+	}
+	if !since.IsZero() {
+		opts.SinceTime = &metav1.Time{Time: *since}
+	}
+	klog.V(2).Infof("Streaming the logs of container %q of pod %q", container, podName)
+	stream, err := t.clientset.CoreV1().Pods(ns).GetLogs(podName, opts).Stream(t.ctx)
+	if err != nil {
+		return 0, err
+	}
+	// the stream is bound to the context, stopping the Tail interrupts the read below
+	defer func() {
+		if err := stream.Close(); err != nil && !t.isStopped() {
+			fmt.Fprintf(t.stderr, "Failed to close stream: %v", err)
 		}
 	}()
-	go func() {
-		<-t.ctx.Done()
-		t.Stop()
-	}()
+
+	read := 0
+	sc := bufio.NewScanner(stream)
+	for sc.Scan() {
+		ts, line, ok := splitTimestamp(sc.Text())
+		if ok {
+			// the since-time has seconds precision, thus lines already printed are sent again
+			if !ts.After(*since) {
+				continue
+			}
+			*since = ts
+		}
+		read++
+		t.setRunning(podName, container, sl, ts)
+		if t.filter != nil && !t.filter(container, line) {
+			continue
+		}
+		if sl != nil && !sl.headed {
+			sl.headed, sl.started = true, ts
+			fmt.Fprintln(t.stdout, sl.header())
+		}
+		fmt.Fprintf(t.stdout, "%s %s\n", prefix, line)
+	}
+	return read, sc.Err()
 }
 
-// Stop closes stop channel to stop log streaming.
-func (t *Tail) Stop() {
-	// employ sync because of observed 'panic: close of closed channel' when running build run log following
-	// along with canceling of builds
-	t.stopLock.Lock()
-	defer t.stopLock.Unlock()
-	if !t.stopped {
-		close(t.stopCh)
-		t.stopped = true
+// backoff doubles the interval between attempts, up to its upper bound.
+func backoff(interval time.Duration) time.Duration {
+	if interval *= 2; interval > maxRetryInterval {
+		return maxRetryInterval
+	}
+	return interval
+}
+
+// waitForContainer polls the pod, with an exponential backoff, until the container leaves the
+// waiting state, returning its status, nil when not reported. Gives up when the startup timeout, or
+// the context deadline, passes.
+func (t *Tail) waitForContainer(ns, podName, container string) (*corev1.ContainerStatus, error) {
+	deadline := time.Now().Add(t.startupTimeout)
+	ctxBound := false
+	if ctxDeadline, ok := t.ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline, ctxBound = ctxDeadline, true
+	}
+
+	interval := t.retryInterval
+	for {
+		reason := ""
+		pod, err := t.clientset.CoreV1().Pods(ns).Get(t.ctx, podName, metav1.GetOptions{})
+		switch {
+		case kerrors.IsNotFound(err):
+			return nil, fmt.Errorf("pod %q is gone, stopping the logs of container %q", podName, container)
+		case err != nil:
+			reason = err.Error()
+		default:
+			status := containerStatusOf(pod, container)
+			if status == nil || status.State.Waiting == nil {
+				return status, nil
+			}
+			reason = status.State.Waiting.Reason
+		}
+		klog.V(4).Infof("Container %q of pod %q is still waiting: %s", container, podName, reason)
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 && ctxBound {
+			return nil, context.DeadlineExceeded
+		}
+		if remaining <= 0 {
+			return nil, fmt.Errorf("container %q has not started in time (%s), stopping its logs", container, reason)
+		}
+		if interval > remaining {
+			interval = remaining
+		}
+		select {
+		case <-t.ctx.Done():
+			return nil, t.ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = backoff(interval)
+	}
+}
+
+// containerStatusOf looks for the informed container status among the init and regular containers,
+// nil when it's not reported yet.
+func containerStatusOf(pod *corev1.Pod, container string) *corev1.ContainerStatus {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for i := range statuses {
+		if statuses[i].Name == container {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// shouldResume inspects the container state after the stream ended, it should be resumed while the
+// container is running or waiting to be restarted. The reason is reported when not resuming, or
+// carried over to the message of the last attempt, and the terminated state when the container
+// has finished.
+func (t *Tail) shouldResume(ns, podName, container string, streamErr error) (bool, *corev1.ContainerStateTerminated, error) {
+	pod, err := t.clientset.CoreV1().Pods(ns).Get(t.ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil, fmt.Errorf("pod %q is gone, stopping the logs of container %q", podName, container)
+		}
+		return true, nil, err
+	}
+	if pod.GetDeletionTimestamp() != nil {
+		return false, nil, fmt.Errorf("pod %q is being deleted, stopping the logs of container %q", podName, container)
+	}
+
+	status := containerStatusOf(pod, container)
+	switch {
+	case status == nil:
+		// the container state is unknown, there is nothing to wait for
+		return false, nil, streamErr
+	case status.State.Running != nil, status.State.Waiting != nil:
+		if streamErr == nil {
+			streamErr = fmt.Errorf("log stream of container %q ended unexpectedly", container)
+		}
+		return true, nil, streamErr
+	default:
+		return false, status.State.Terminated, streamErr
+	}
+}
+
+// splitTimestamp splits the RFC3339 timestamp prefix added to each line when the logs are requested
+// with timestamps.
+func splitTimestamp(s string) (time.Time, string, bool) {
+	prefix, line, found := strings.Cut(s, " ")
+	if !found {
+		prefix, line = s, ""
 	}
+	ts, err := time.Parse(time.RFC3339Nano, prefix)
+	if err != nil {
+		return time.Time{}, s, false
+	}
+	return ts, line, true
+}
+
+// expired checks whether the context deadline, i.e. the request timeout, has expired.
+func (t *Tail) expired() bool {
+	return errors.Is(t.ctx.Err(), context.DeadlineExceeded)
+}
+
+// reportExpired lets the user know the logs of the container are incomplete due to the deadline.
+func (t *Tail) reportExpired(container string) {
+	fmt.Fprintf(t.stderr, "Stopped the logs of container %q, the request timeout has expired\n", container)
+}
+
+// isStopped checks whether Stop has been called.
+func (t *Tail) isStopped() bool {
+	return t.stopped.Load()
+}
+
+// Stop stops the log streaming, cancelling the streams in flight. It's safe to call it more than
+// once, and concurrently with the context cancellation.
+func (t *Tail) Stop() {
+	t.stopOnce.Do(func() {
+		t.stopped.Store(true)
+		t.cancel()
+	})
+}
+
+// Done returns a channel closed once the Tail is stopped, either by Stop or the context
+// cancellation.
+func (t *Tail) Done() <-chan struct{} {
+	return t.ctx.Done()
 }
 
 // NewTail instantiate Tail, using by default regular stdout and stderr.
 func NewTail(ctx context.Context, clientset kubernetes.Interface) *Tail {
+	ctx, cancel := context.WithCancel(ctx)
 	return &Tail{
-		ctx:       ctx,
-		clientset: clientset,
-		stopCh:    make(chan bool, 1),
-		stopLock:  sync.Mutex{},
-		stdout:    os.Stdout,
-		stderr:    os.Stderr,
+		ctx:            ctx,
+		cancel:         cancel,
+		clientset:      clientset,
+		maxRetries:     defaultMaxRetries,
+		retryInterval:  defaultRetryInterval,
+		startupTimeout: defaultStartupTimeout,
+		stdout:         os.Stdout,
+		stderr:         os.Stderr,
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 
@@ -59,3 +61,333 @@ func Test_Tail(t *testing.T) {
 	g.Expect(err).To(o.BeNil())
 	g.Expect(stderrNumBytes).To(o.Equal(int64(0)))
 }
+
+func Test_splitTimestamp(t *testing.T) {
+	g := o.NewWithT(t)
+
+	ts, line, ok := splitTimestamp("2024-01-01T10:00:00.123456789Z hello world")
+	g.Expect(ok).To(o.BeTrue())
+	g.Expect(ts).To(o.Equal(time.Date(2024, 1, 1, 10, 0, 0, 123456789, time.UTC)))
+	g.Expect(line).To(o.Equal("hello world"))
+
+	ts, line, ok = splitTimestamp("2024-01-01T10:00:00Z")
+	g.Expect(ok).To(o.BeTrue())
+	g.Expect(ts.IsZero()).To(o.BeFalse())
+	g.Expect(line).To(o.BeEmpty())
+
+	_, line, ok = splitTimestamp("fake logs")
+	g.Expect(ok).To(o.BeFalse())
+	g.Expect(line).To(o.Equal("fake logs"))
+}
+
+func Test_Tail_shouldResume(t *testing.T) {
+	containerName := "container"
+	newPod := func(state corev1.ContainerState) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: containerName, State: state}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		wantResume bool
+		wantReason bool
+	}{
+		{
+			name:       "pod is gone",
+			wantReason: true,
+		},
+		{
+			name:       "container is running",
+			pod:        newPod(corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}),
+			wantResume: true,
+			wantReason: true,
+		},
+		{
+			name:       "container is being restarted",
+			pod:        newPod(corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}),
+			wantResume: true,
+			wantReason: true,
+		},
+		{
+			name: "container is terminated",
+			pod:  newPod(corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			clientset := fake.NewSimpleClientset()
+			if tt.pod != nil {
+				clientset = fake.NewSimpleClientset(tt.pod)
+			}
+			logTail := NewTail(context.TODO(), clientset)
+			resume, _, reason := logTail.shouldResume(metav1.NamespaceDefault, "pod", containerName, nil)
+			g.Expect(resume).To(o.Equal(tt.wantResume))
+			g.Expect(reason != nil).To(o.Equal(tt.wantReason))
+		})
+	}
+}
+
+func Test_Tail_GivesUpWithoutTimestamps(t *testing.T) {
+	g := o.NewWithT(t)
+
+	// the fake clientset serves logs without timestamps, thus even though the container is
+	// running the stream can't be resumed without repeating the lines
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "step-build",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+		},
+	}
+	logTail := NewTail(context.TODO(), fake.NewSimpleClientset(pod))
+	logTail.retryInterval = time.Millisecond
+
+	var stdout, stderr bytes.Buffer
+	logTail.SetStdout(&stdout)
+	logTail.SetStderr(&stderr)
+	logTail.follow(metav1.NamespaceDefault, "pod", "step-build")
+
+	g.Expect(stdout.String()).To(o.Equal("[build] fake logs\n"))
+	g.Expect(stderr.String()).To(o.BeEmpty())
+}
+
+func Test_StepsOf(t *testing.T) {
+	g := o.NewWithT(t)
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "prepare"}},
+		Containers: []corev1.Container{
+			{Name: "step-source-default"},
+			{Name: "sidecar-registry"},
+			{Name: "step-build-and-push"},
+		},
+	}}
+	steps := StepsOf(pod)
+	g.Expect(steps).To(o.HaveLen(2))
+	g.Expect(steps["step-build-and-push"].String()).To(o.Equal("step 2/2: build-and-push"))
+	g.Expect(steps).ToNot(o.HaveKey("sidecar-registry"))
+}
+
+func Test_stepLog_footer(t *testing.T) {
+	g := o.NewWithT(t)
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	sl := &stepLog{step: Step{Name: "build", Index: 2, Total: 3}, started: start.Add(10 * time.Second)}
+
+	g.Expect(sl.footer(&corev1.ContainerStateTerminated{
+		StartedAt:  metav1.NewTime(start),
+		FinishedAt: metav1.NewTime(start.Add(72 * time.Second)),
+	})).To(o.Equal("step 2/3: build completed in 1m2s"))
+
+	sl.started = time.Time{}
+	g.Expect(sl.footer(&corev1.ContainerStateTerminated{
+		ExitCode:   1,
+		StartedAt:  metav1.NewTime(start),
+		FinishedAt: metav1.NewTime(start.Add(3 * time.Second)),
+	})).To(o.Equal("step 2/3: build failed after 3s (exit code 1)"))
+}
+
+func Test_Tail_StepHeader(t *testing.T) {
+	g := o.NewWithT(t)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "step-source"}, {Name: "step-build"}},
+		},
+	}
+	logTail := NewTail(context.TODO(), fake.NewSimpleClientset(pod))
+	logTail.SetSteps(pod.GetName(), StepsOf(pod))
+
+	var stdout, stderr bytes.Buffer
+	logTail.SetStdout(&stdout)
+	logTail.SetStderr(&stderr)
+	logTail.follow(metav1.NamespaceDefault, "pod", "step-build")
+
+	g.Expect(stdout.String()).To(o.Equal("step 2/2: build\n[build] fake logs\n"))
+	g.Expect(stderr.String()).To(o.BeEmpty())
+
+	// the step is no longer running once its logs end
+	_, _, running := logTail.RunningStep()
+	g.Expect(running).To(o.BeFalse())
+}
+
+func Test_Tail_RunningStep(t *testing.T) {
+	g := o.NewWithT(t)
+
+	logTail := NewTail(context.TODO(), fake.NewSimpleClientset())
+	_, _, running := logTail.RunningStep()
+	g.Expect(running).To(o.BeFalse())
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	source := &stepLog{step: Step{Name: "source", Index: 1, Total: 2}}
+	build := &stepLog{step: Step{Name: "build", Index: 2, Total: 2}}
+	logTail.setRunning("pod", "step-source", source, start)
+	logTail.setRunning("pod", "step-build", build, start.Add(time.Minute))
+	// only the first line is recorded
+	logTail.setRunning("pod", "step-build", build, start.Add(2*time.Minute))
+
+	step, started, running := logTail.RunningStep()
+	g.Expect(running).To(o.BeTrue())
+	g.Expect(step.Name).To(o.Equal("build"))
+	g.Expect(started).To(o.Equal(start.Add(time.Minute)))
+
+	logTail.clearRunning("pod", "step-build")
+	step, _, running = logTail.RunningStep()
+	g.Expect(running).To(o.BeTrue())
+	g.Expect(step.Name).To(o.Equal("source"))
+}
+
+func Test_Tail_WaitsForContainer(t *testing.T) {
+	g := o.NewWithT(t)
+
+	waiting := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "step-build",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"},
+				},
+			}},
+		},
+	}
+
+	t.Run("container starts", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(waiting.DeepCopy())
+		logTail := NewTail(context.TODO(), clientset)
+		logTail.retryInterval = time.Millisecond
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			running := waiting.DeepCopy()
+			running.Status.ContainerStatuses[0].State = corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{},
+			}
+			_, _ = clientset.CoreV1().Pods(metav1.NamespaceDefault).UpdateStatus(context.TODO(), running, metav1.UpdateOptions{})
+		}()
+
+		var stdout, stderr bytes.Buffer
+		logTail.SetStdout(&stdout)
+		logTail.SetStderr(&stderr)
+		logTail.follow(metav1.NamespaceDefault, "pod", "step-build")
+
+		g.Expect(stdout.String()).To(o.Equal("[build] fake logs\n"))
+		g.Expect(stderr.String()).To(o.BeEmpty())
+	})
+
+	t.Run("startup timeout", func(t *testing.T) {
+		logTail := NewTail(context.TODO(), fake.NewSimpleClientset(waiting.DeepCopy()))
+		logTail.retryInterval = time.Millisecond
+		logTail.SetStartupTimeout(20 * time.Millisecond)
+
+		var stdout, stderr bytes.Buffer
+		logTail.SetStdout(&stdout)
+		logTail.SetStderr(&stderr)
+		logTail.follow(metav1.NamespaceDefault, "pod", "step-build")
+
+		g.Expect(stdout.String()).To(o.BeEmpty())
+		g.Expect(stderr.String()).To(o.Equal("container \"step-build\" has not started in time (ContainerCreating), stopping its logs\n"))
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Millisecond)
+		defer cancel()
+		logTail := NewTail(ctx, fake.NewSimpleClientset(waiting.DeepCopy()))
+		logTail.retryInterval = time.Millisecond
+
+		var stderr bytes.Buffer
+		logTail.SetStderr(&stderr)
+		start := time.Now()
+		logTail.follow(metav1.NamespaceDefault, "pod", "step-build")
+
+		g.Expect(time.Since(start)).To(o.BeNumerically("<", time.Second))
+		g.Expect(stderr.String()).To(o.Equal("Stopped the logs of container \"step-build\", the request timeout has expired\n"))
+	})
+}
+
+func Test_Tail_StopAndCancel(t *testing.T) {
+	g := o.NewWithT(t)
+
+	waiting := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "step-build",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+			}},
+		},
+	}
+	goroutines := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	logTail := NewTail(ctx, fake.NewSimpleClientset(waiting))
+	var stderr bytes.Buffer
+	logTail.SetStderr(&stderr)
+	logTail.Start(metav1.NamespaceDefault, "pod", "step-build")
+	logTail.Start(metav1.NamespaceDefault, "pod", "step-build")
+
+	// stopping and cancelling concurrently, more than once, must not panic
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			logTail.Stop()
+		}()
+		go func() {
+			defer wg.Done()
+			cancel()
+		}()
+	}
+	wg.Wait()
+
+	g.Eventually(logTail.Done()).Should(o.BeClosed())
+	g.Eventually(runtime.NumGoroutine).Should(o.BeNumerically("<=", goroutines))
+	g.Expect(stderr.String()).To(o.BeEmpty())
+}
+
+func Test_Tail_startOf(t *testing.T) {
+	since := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	before := metav1.NewTime(since.Add(-time.Minute))
+	after := metav1.NewTime(since.Add(time.Minute))
+
+	tests := []struct {
+		name   string
+		since  time.Time
+		state  corev1.ContainerState
+		start  time.Time
+		stream bool
+	}{
+		{name: "from the start", state: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: before}}, stream: true},
+		{name: "running before", since: since,
+			state: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: before}}, start: since, stream: true},
+		{name: "running after", since: since,
+			state: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: after}}, stream: true},
+		{name: "terminated before", since: since,
+			state: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{StartedAt: before, FinishedAt: before}}},
+		{name: "terminated mid-flight", since: since,
+			state: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{StartedAt: before, FinishedAt: after}}, start: since, stream: true},
+		{name: "terminated after", since: since,
+			state: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{StartedAt: after, FinishedAt: after}}, stream: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			logTail := NewTail(context.TODO(), fake.NewSimpleClientset())
+			logTail.SetSince(tt.since)
+			start, stream := logTail.startOf(&corev1.ContainerStatus{Name: "step-build", State: tt.state})
+			g.Expect(start).To(o.Equal(tt.start))
+			g.Expect(stream).To(o.Equal(tt.stream))
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package tail
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+)
+
+const (
+	// defaultFlushInterval interval between flushes of the buffered logs.
+	defaultFlushInterval = 100 * time.Millisecond
+	// maxBufferSize amount of buffered bytes flushed right away, regardless of the interval.
+	maxBufferSize = 64 * 1024
+	// rateWindow window in which the rate limit is accounted.
+	rateWindow = time.Second
+)
+
+// LogWriter buffers the log lines written, flushing them periodically instead of on every line,
+// thus builds printing a lot of logs don't make the terminal the bottleneck. With a maximum rate,
+// the lines exceeding the bytes per second allowed are skipped, and a notice tells how much was
+// skipped. Each line is expected on a single write.
+type LogWriter struct {
+	lock sync.Mutex
+
+	out           io.Writer        // final writer
+	buf           bytes.Buffer     // lines not flushed yet
+	maxRate       int64            // bytes per second allowed, zero means no limit
+	now           func() time.Time // current time, replaceable for testing purposes
+	flushInterval time.Duration    // interval between flushes
+
+	windowStart   time.Time // beginning of the current rate window
+	windowBytes   int64     // bytes written in the current rate window
+	windowSkipped int64     // bytes skipped in the current rate window
+
+	written int64 // total bytes written
+	skipped int64 // total bytes skipped
+
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	started bool
+	stopped bool
+}
+
+// NewLogWriter instantiate a LogWriter on the informed writer, without rate limit.
+func NewLogWriter(out io.Writer) *LogWriter {
+	return &LogWriter{
+		out:           out,
+		now:           time.Now,
+		flushInterval: defaultFlushInterval,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+}
+
+// SetMaxRate sets the maximum amount of bytes per second written, zero means no limit.
+func (w *LogWriter) SetMaxRate(bytesPerSecond int64) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.maxRate = bytesPerSecond
+}
+
+// SetOutput flushes the buffered lines and redirects the next ones to the informed writer.
+func (w *LogWriter) SetOutput(out io.Writer) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.flush()
+	w.out = out
+}
+
+// Write buffers the line, or skips it when the rate limit has been reached.
+func (w *LogWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	now := w.now()
+	if now.Sub(w.windowStart) >= rateWindow {
+		w.closeWindow()
+		w.windowStart = now
+	}
+	size := int64(len(p))
+	if w.maxRate > 0 && w.windowBytes+size > w.maxRate {
+		w.windowSkipped += size
+		w.skipped += size
+		return len(p), nil
+	}
+	w.windowBytes += size
+	w.written += size
+	w.buf.Write(p)
+	if w.stopped || w.buf.Len() >= maxBufferSize {
+		w.flush()
+	}
+	return len(p), nil
+}
+
+// closeWindow reports the lines skipped in the current rate window, and resets it.
+func (w *LogWriter) closeWindow() {
+	if w.windowSkipped > 0 {
+		fmt.Fprintln(&w.buf, styles.Warning(fmt.Sprintf("... skipped %s of logs exceeding the rate of %s/s",
+			FormatBytes(w.windowSkipped), FormatBytes(w.maxRate))))
+	}
+	w.windowBytes = 0
+	w.windowSkipped = 0
+}
+
+// flush writes the buffered lines, the lock must be held.
+func (w *LogWriter) flush() {
+	if w.buf.Len() == 0 {
+		return
+	}
+	// the logs are best effort, a broken terminal must not stop the following
+	_, _ = w.out.Write(w.buf.Bytes())
+	w.buf.Reset()
+}
+
+// Flush writes the buffered lines right away, i.e. before printing other messages on the same
+// writer.
+func (w *LogWriter) Flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.flush()
+}
+
+// Start flushes the buffered lines periodically until stopped.
+func (w *LogWriter) Start() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.started || w.stopped {
+		return
+	}
+	w.started = true
+	go func() {
+		defer close(w.doneCh)
+		ticker := time.NewTicker(w.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.Flush()
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic flush, reporting the lines skipped and flushing the buffered ones. The
+// lines written afterwards are written right away.
+func (w *LogWriter) Stop() {
+	w.lock.Lock()
+	if w.stopped {
+		w.lock.Unlock()
+		return
+	}
+	w.stopped = true
+	started := w.started
+	w.lock.Unlock()
+
+	if started {
+		close(w.stopCh)
+		<-w.doneCh
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.closeWindow()
+	w.flush()
+}
+
+// Written returns the amount of log bytes written.
+func (w *LogWriter) Written() int64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.written
+}
+
+// Skipped returns the amount of log bytes skipped by the rate limit.
+func (w *LogWriter) Skipped() int64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.skipped
+}
+
+// FormatBytes renders the amount of bytes with binary units, e.g. "1.5Mi".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	value, suffix := float64(n), ""
+	for _, s := range []string{"Ki", "Mi", "Gi", "Ti"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, s
+	}
+	return fmt.Sprintf("%.1f%s", value, suffix)
+}
@@ -0,0 +1,99 @@
+package tail
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+)
+
+// lockedBuffer buffer safe for concurrent use.
+type lockedBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func TestLogWriterBuffering(t *testing.T) {
+	g := o.NewWithT(t)
+
+	var out bytes.Buffer
+	w := NewLogWriter(&out)
+	fmt.Fprintln(w, "first")
+	fmt.Fprintln(w, "second")
+	g.Expect(out.String()).To(o.BeEmpty())
+
+	w.Flush()
+	g.Expect(out.String()).To(o.Equal("first\nsecond\n"))
+
+	// large amounts of logs are flushed right away
+	line := strings.Repeat("x", maxBufferSize)
+	fmt.Fprintln(w, line)
+	g.Expect(out.Len()).To(o.Equal(len("first\nsecond\n") + len(line) + 1))
+
+	// after stopping the lines are written right away
+	w.Stop()
+	fmt.Fprintln(w, "late")
+	g.Expect(out.String()).To(o.HaveSuffix("late\n"))
+	g.Expect(w.Written()).To(o.Equal(int64(out.Len())))
+	g.Expect(w.Skipped()).To(o.BeZero())
+}
+
+func TestLogWriterPeriodicFlush(t *testing.T) {
+	g := o.NewWithT(t)
+
+	out := &lockedBuffer{}
+	w := NewLogWriter(out)
+	w.flushInterval = 10 * time.Millisecond
+	w.Start()
+	defer w.Stop()
+
+	fmt.Fprintln(w, "line")
+	g.Eventually(out.String).Should(o.Equal("line\n"))
+}
+
+func TestLogWriterMaxRate(t *testing.T) {
+	g := o.NewWithT(t)
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	w := NewLogWriter(&out)
+	w.now = func() time.Time { return now }
+	w.SetMaxRate(10)
+
+	fmt.Fprint(w, "12345\n")   // 6 bytes
+	fmt.Fprint(w, "1234\n")    // 11 bytes, skipped
+	fmt.Fprint(w, "123\n")     // 10 bytes
+	fmt.Fprint(w, "1234567\n") // skipped
+	now = now.Add(time.Second)
+	fmt.Fprint(w, "next\n")
+	w.Stop()
+
+	g.Expect(out.String()).To(o.Equal("12345\n123\n... skipped 13B of logs exceeding the rate of 10B/s\nnext\n"))
+	g.Expect(w.Written()).To(o.Equal(int64(15)))
+	g.Expect(w.Skipped()).To(o.Equal(int64(13)))
+}
+
+func TestFormatBytes(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(FormatBytes(0)).To(o.Equal("0B"))
+	g.Expect(FormatBytes(1023)).To(o.Equal("1023B"))
+	g.Expect(FormatBytes(1536)).To(o.Equal("1.5Ki"))
+	g.Expect(FormatBytes(3 * 1024 * 1024)).To(o.Equal("3.0Mi"))
+}
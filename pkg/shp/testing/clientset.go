@@ -0,0 +1,35 @@
+package testing
+
+import (
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+	shpscheme "github.com/shipwright-io/build/pkg/client/clientset/versioned/scheme"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// NewClientsets instantiates the fake Kubernetes and Shipwright clientsets, the informed objects
+// are loaded on the clientset serving their kind, i.e. Builds on the Shipwright clientset and
+// pods on the Kubernetes one.
+func NewClientsets(objects ...runtime.Object) (*fake.Clientset, *shpfake.Clientset) {
+	kubeObjects := []runtime.Object{}
+	shpObjects := []runtime.Object{}
+	for _, obj := range objects {
+		if _, _, err := shpscheme.Scheme.ObjectKinds(obj); err == nil {
+			shpObjects = append(shpObjects, obj)
+		} else {
+			kubeObjects = append(kubeObjects, obj)
+		}
+	}
+	return fake.NewSimpleClientset(kubeObjects...), shpfake.NewSimpleClientset(shpObjects...)
+}
+
+// NewParams instantiates the command parameters targeting the informed namespace, backed by fake
+// clientsets loaded with the informed objects. The clientsets are returned to inspect the actions
+// performed and to modify the objects further.
+func NewParams(ns string, objects ...runtime.Object) (*params.Params, *fake.Clientset, *shpfake.Clientset) {
+	clientset, shpClientset := NewClientsets(objects...)
+	return params.NewParamsForTest(clientset, shpClientset, nil, ns, nil, nil), clientset, shpClientset
+}
@@ -0,0 +1,5 @@
+// Package testing offers helpers to exercise the packages of this module against fake clientsets,
+// pre-loaded with Builds, BuildRuns and their pods, and to drive the PodWatcher and Tail with
+// synthetic events. It's meant for downstream tools embedding these packages, to test their
+// integrations without a cluster.
+package testing
@@ -0,0 +1,119 @@
+package testing
+
+import (
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewBuild instantiates a Build using a ClusterBuildStrategy, with the source and output image
+// informed.
+func NewBuild(ns, name, strategy string) *buildv1alpha1.Build {
+	kind := buildv1alpha1.ClusterBuildStrategyKind
+	sourceURL := "https://github.com/shipwright-io/sample-go"
+	return &buildv1alpha1.Build{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec: buildv1alpha1.BuildSpec{
+			Source:   buildv1alpha1.Source{URL: &sourceURL},
+			Strategy: buildv1alpha1.Strategy{Name: strategy, Kind: &kind},
+			Output:   buildv1alpha1.Image{Image: "registry.local/" + ns + "/" + name},
+		},
+	}
+}
+
+// NewBuildRun instantiates a BuildRun referencing the informed Build, labeled alike the BuildRuns
+// created by the controller.
+func NewBuildRun(ns, name, buildName string) *buildv1alpha1.BuildRun {
+	return &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+			Labels:    map[string]string{buildv1alpha1.LabelBuild: buildName},
+		},
+		Spec: buildv1alpha1.BuildRunSpec{BuildRef: &buildv1alpha1.BuildRef{Name: buildName}},
+	}
+}
+
+// NewBuildRunPod instantiates the pending pod executing the BuildRun, labeled with the Build and
+// BuildRun names, with a container for each informed step name.
+func NewBuildRunPod(br *buildv1alpha1.BuildRun, name string, steps ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: br.GetNamespace(),
+			Name:      name,
+			Labels: map[string]string{
+				buildv1alpha1.LabelBuildRun: br.GetName(),
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	if br.Spec.BuildRef != nil {
+		pod.Labels[buildv1alpha1.LabelBuild] = br.Spec.BuildRef.Name
+	}
+	for _, step := range steps {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: step})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:  step,
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}},
+		})
+	}
+	return pod
+}
+
+// PodRunning returns a copy of the pod in the running phase, with all its containers running.
+func PodRunning(pod *corev1.Pod) *corev1.Pod {
+	running := pod.DeepCopy()
+	running.Status.Phase = corev1.PodRunning
+	for i := range running.Status.ContainerStatuses {
+		running.Status.ContainerStatuses[i].State = corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{StartedAt: metav1.Now()},
+		}
+	}
+	return running
+}
+
+// PodSucceeded returns a copy of the pod in the succeeded phase, with all its containers
+// terminated successfully.
+func PodSucceeded(pod *corev1.Pod) *corev1.Pod {
+	succeeded := pod.DeepCopy()
+	succeeded.Status.Phase = corev1.PodSucceeded
+	for i := range succeeded.Status.ContainerStatuses {
+		succeeded.Status.ContainerStatuses[i].State = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: "Completed", FinishedAt: metav1.Now()},
+		}
+	}
+	return succeeded
+}
+
+// PodFailed returns a copy of the pod in the failed phase, the informed container terminated with
+// the exit code and message, while the containers after it are never started.
+func PodFailed(pod *corev1.Pod, container string, exitCode int32, message string) *corev1.Pod {
+	failed := pod.DeepCopy()
+	failed.Status.Phase = corev1.PodFailed
+	failed.Status.Message = message
+	reached := false
+	for i := range failed.Status.ContainerStatuses {
+		status := &failed.Status.ContainerStatuses[i]
+		switch {
+		case status.Name == container:
+			reached = true
+			status.State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				Reason:     "Error",
+				ExitCode:   exitCode,
+				Message:    message,
+				FinishedAt: metav1.Now(),
+			}}
+		case reached:
+			status.State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				Reason:   "Error",
+				ExitCode: 1,
+			}}
+		default:
+			status.State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				Reason: "Completed",
+			}}
+		}
+	}
+	return failed
+}
@@ -0,0 +1,88 @@
+package testing_test
+
+import (
+	"context"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	shptesting "github.com/shipwright-io/cli/pkg/shp/testing"
+)
+
+func TestNewParams(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+
+	b := shptesting.NewBuild(ns, "app", "buildah")
+	br := shptesting.NewBuildRun(ns, "app-1", "app")
+	pod := shptesting.NewBuildRunPod(br, "app-1-pod", "step-build")
+
+	p, clientset, shpClientset := shptesting.NewParams(ns, b, br, pod)
+	g.Expect(p.Namespace()).To(o.Equal(ns))
+
+	_, err := shpClientset.ShipwrightV1alpha1().Builds(ns).Get(context.TODO(), "app", metav1.GetOptions{})
+	g.Expect(err).ToNot(o.HaveOccurred())
+	_, err = shpClientset.ShipwrightV1alpha1().BuildRuns(ns).Get(context.TODO(), "app-1", metav1.GetOptions{})
+	g.Expect(err).ToNot(o.HaveOccurred())
+	pods, err := clientset.CoreV1().Pods(ns).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: "build.shipwright.io/name=app,buildrun.shipwright.io/name=app-1",
+	})
+	g.Expect(err).ToNot(o.HaveOccurred())
+	g.Expect(pods.Items).To(o.HaveLen(1))
+}
+
+func TestPodWatcherAndTail(t *testing.T) {
+	g := o.NewWithT(t)
+	ns := metav1.NamespaceDefault
+	ctx := context.TODO()
+
+	br := shptesting.NewBuildRun(ns, "app-1", "app")
+	pod := shptesting.NewBuildRunPod(br, "app-1-pod", "step-build")
+	running := shptesting.PodRunning(pod)
+	_, clientset, _ := shptesting.NewParams(ns, running)
+
+	pw, err := shptesting.NewPodWatcher(ctx, clientset, ns)
+	g.Expect(err).ToNot(o.HaveOccurred())
+	tail, stdout, _ := shptesting.NewTail(ctx, clientset)
+
+	pw.WithOnPodModifiedFn(func(p *corev1.Pod) error {
+		switch p.Status.Phase {
+		case corev1.PodRunning:
+			tail.Start(ns, p.GetName(), "step-build")
+		case corev1.PodSucceeded:
+			pw.Stop()
+		}
+		return nil
+	})
+	g.Expect(pw.Connect(metav1.ListOptions{})).To(o.Succeed())
+	doneCh := make(chan error, 1)
+	go func() {
+		_, err := pw.WaitForCompletion()
+		doneCh <- err
+	}()
+
+	pw.Watch.Modify(running)
+	g.Eventually(stdout.String).Should(o.ContainSubstring("[build] fake logs"))
+
+	pw.Watch.Modify(shptesting.PodSucceeded(pod))
+	g.Eventually(doneCh).Should(o.Receive(o.BeNil()))
+	tail.Stop()
+}
+
+func TestPodFailed(t *testing.T) {
+	g := o.NewWithT(t)
+
+	br := shptesting.NewBuildRun(metav1.NamespaceDefault, "app-1", "app")
+	pod := shptesting.NewBuildRunPod(br, "app-1-pod", "step-source", "step-build", "step-push")
+	failed := shptesting.PodFailed(pod, "step-build", 2, "build failed")
+
+	g.Expect(failed.Status.Phase).To(o.Equal(corev1.PodFailed))
+	g.Expect(failed.Status.ContainerStatuses[0].State.Terminated.ExitCode).To(o.BeZero())
+	g.Expect(failed.Status.ContainerStatuses[1].State.Terminated.ExitCode).To(o.Equal(int32(2)))
+	g.Expect(failed.Status.ContainerStatuses[1].State.Terminated.Message).To(o.Equal("build failed"))
+	g.Expect(failed.Status.ContainerStatuses[2].State.Terminated.Reason).To(o.Equal("Error"))
+	g.Expect(pod.Status.Phase).To(o.Equal(corev1.PodPending))
+}
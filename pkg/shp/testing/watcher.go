@@ -0,0 +1,68 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	testclock "k8s.io/utils/clock/testing"
+
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+)
+
+// PodWatcher bundles a PodWatcher with the fake watch delivering its events and the fake clock
+// driving its timers, thus the event loop is entirely driven by the test.
+type PodWatcher struct {
+	*reactor.PodWatcher
+
+	Watch *watch.FakeWatcher   // delivers the pod events, i.e. Watch.Add(pod)
+	Clock *testclock.FakeClock // moves the no-event window and tickers forward, i.e. Clock.Step(d)
+}
+
+// NewPodWatcher instantiates a PodWatcher on the informed namespace consuming synthetic events,
+// the clientset is still employed to list the pods when no events arrive.
+func NewPodWatcher(ctx context.Context, clientset kubernetes.Interface, ns string) (*PodWatcher, error) {
+	w := watch.NewFake()
+	clk := testclock.NewFakeClock(time.Now())
+	pw, err := reactor.NewPodWatcherFromWatch(ctx, math.MaxInt64, clientset, ns, w, clk)
+	if err != nil {
+		return nil, err
+	}
+	return &PodWatcher{PodWatcher: pw, Watch: w, Clock: clk}, nil
+}
+
+// Buffer is a bytes.Buffer safe for concurrent writes and reads, collecting the output written by
+// the goroutines streaming the logs.
+type Buffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+// Write appends the informed bytes to the buffer.
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+// String returns the contents written so far.
+func (b *Buffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+// NewTail instantiates a Tail writing to buffers instead of the regular stdout and stderr, the
+// fake Kubernetes clientset serves "fake logs" as the logs of every container.
+func NewTail(ctx context.Context, clientset kubernetes.Interface) (*tail.Tail, *Buffer, *Buffer) {
+	stdout, stderr := &Buffer{}, &Buffer{}
+	t := tail.NewTail(ctx, clientset)
+	t.SetStdout(stdout)
+	t.SetStderr(stderr)
+	return t, stdout, stderr
+}
@@ -0,0 +1,5 @@
+// Package trace records the timing of the CLI phases as spans, i.e. creating the BuildRun, waiting
+// for its pod, streaming the logs and completing it, exported in the OpenTelemetry protocol (OTLP)
+// JSON encoding to a collector endpoint or a local file, measuring where the time goes in the
+// build loop.
+package trace
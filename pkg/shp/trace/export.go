@@ -0,0 +1,174 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tracesPath path of the OTLP/HTTP traces endpoint, appended to the collector endpoint.
+const tracesPath = "/v1/traces"
+
+// OTLP span kind and status codes.
+const (
+	spanKindInternal = 1
+	statusCodeOK     = 1
+	statusCodeError  = 2
+)
+
+// otlpRequest OTLP ExportTraceServiceRequest in the JSON encoding.
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// attributesOf converts the attributes to OTLP, sorted by key.
+func attributesOf(attributes map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]otlpAttribute, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, otlpAttribute{Key: k, Value: otlpValue{StringValue: attributes[k]}})
+	}
+	return result
+}
+
+// request renders the spans ended so far as an OTLP export request, in the order they started.
+func (t *Tracer) request() *otlpRequest {
+	t.lock.Lock()
+	spans := append([]*Span{}, t.spans...)
+	t.lock.Unlock()
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.lock.Lock()
+		span := otlpSpan{
+			TraceID:           t.traceID,
+			SpanID:            s.id,
+			ParentSpanID:      s.parentID,
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        attributesOf(s.attributes),
+			Status:            otlpStatus{Code: statusCodeOK},
+		}
+		if s.err != nil {
+			span.Status = otlpStatus{Code: statusCodeError, Message: s.err.Error()}
+		}
+		s.lock.Unlock()
+		otlpSpans = append(otlpSpans, span)
+	}
+
+	return &otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: attributesOf(map[string]string{
+			"service.name":    t.service,
+			"service.version": t.version,
+		})},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: t.service, Version: t.version},
+			Spans: otlpSpans,
+		}},
+	}}}
+}
+
+// WriteFile appends the spans to the informed file as a single line of OTLP JSON, the format of the
+// OpenTelemetry collector file exporter, thus a file collects the traces of several commands.
+func (t *Tracer) WriteFile(path string) error {
+	data, err := json.Marshal(t.request())
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to write the trace file: %w", err)
+	}
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("unable to write the trace file: %w", err)
+	}
+	return f.Close()
+}
+
+// Send posts the spans to the OTLP/HTTP collector endpoint, the traces path is appended when the
+// endpoint doesn't carry it already.
+func (t *Tracer) Send(ctx context.Context, client *http.Client, endpoint string) error {
+	data, err := json.Marshal(t.request())
+	if err != nil {
+		return err
+	}
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, tracesPath) {
+		url += tracesPath
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid trace endpoint %q: %w", endpoint, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send the trace to %q: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unable to send the trace to %q: %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
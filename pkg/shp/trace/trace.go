@@ -0,0 +1,111 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Tracer collects the spans ended during the command, exported all at once when it's done.
+type Tracer struct {
+	lock sync.Mutex
+
+	service string  // service name, the resource the spans belong to
+	version string  // version of the service
+	traceID string  // hex encoded identifier shared by all the spans
+	spans   []*Span // spans ended
+
+	now func() time.Time // current time, replaceable for testing purposes
+}
+
+// Span a named phase of the command, with its start and end time.
+type Span struct {
+	lock sync.Mutex
+
+	tracer     *Tracer
+	id         string            // hex encoded span identifier
+	parentID   string            // identifier of the parent span, empty for the root
+	name       string            // phase name
+	start      time.Time         // moment the phase started
+	end        time.Time         // moment the phase ended, zero while in progress
+	attributes map[string]string // details of the phase, i.e. the BuildRun name
+	err        error             // error the phase ended with, if any
+}
+
+// spanKey context key of the current span.
+type spanKey struct{}
+
+// tracerKey context key of the tracer.
+type tracerKey struct{}
+
+// New instantiates a Tracer for the informed service name and version.
+func New(service, version string) *Tracer {
+	return &Tracer{service: service, version: version, traceID: randomID(16), now: time.Now}
+}
+
+// randomID returns a random hex encoded identifier of the informed amount of bytes.
+func randomID(size int) string {
+	id := make([]byte, size)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// WithTracer returns a context carrying the tracer, the spans started from it are recorded.
+func WithTracer(ctx context.Context, t *Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// Start starts a span named after the phase, child of the span carried by the context, if any.
+// Returns nil when the context carries no tracer, the methods of a nil span do nothing, thus the
+// phases are instrumented regardless of tracing being enabled.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	t, ok := ctx.Value(tracerKey{}).(*Tracer)
+	if !ok || t == nil {
+		return ctx, nil
+	}
+	s := &Span{tracer: t, id: randomID(8), name: name, start: t.now(), attributes: map[string]string{}}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent != nil {
+		s.parentID = parent.id
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// SetAttribute records a detail of the phase.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.attributes[key] = value
+}
+
+// RecordError marks the phase as failed with the informed error, nil is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.err = err
+}
+
+// End ends the phase, it's safe to call it more than once, only the first call counts.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	if !s.end.IsZero() {
+		s.lock.Unlock()
+		return
+	}
+	s.end = s.tracer.now()
+	s.lock.Unlock()
+
+	s.tracer.lock.Lock()
+	defer s.tracer.lock.Unlock()
+	s.tracer.spans = append(s.tracer.spans, s)
+}
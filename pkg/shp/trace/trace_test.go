@@ -0,0 +1,135 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+)
+
+// newTestTracer returns a tracer whose clock advances a second every time it's read.
+func newTestTracer() *Tracer {
+	t := New("shp", "v0.0.1")
+	clock := time.Unix(1700000000, 0)
+	t.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	return t
+}
+
+func TestStart(t *testing.T) {
+	g := o.NewWithT(t)
+
+	t.Run("no tracer", func(_ *testing.T) {
+		ctx, span := Start(context.TODO(), "phase")
+		g.Expect(span).To(o.BeNil())
+		g.Expect(ctx).To(o.Equal(context.TODO()))
+
+		// the methods of a nil span do nothing
+		span.SetAttribute("key", "value")
+		span.RecordError(errors.New("failed"))
+		span.End()
+	})
+
+	t.Run("nested spans", func(_ *testing.T) {
+		tracer := newTestTracer()
+		ctx := WithTracer(context.TODO(), tracer)
+
+		ctx, root := Start(ctx, "shp build run")
+		_, child := Start(ctx, "create BuildRun")
+		child.SetAttribute("buildrun", "br-1")
+		child.RecordError(errors.New("denied"))
+		child.End()
+		child.End()
+		root.End()
+
+		req := tracer.request()
+		g.Expect(req.ResourceSpans).To(o.HaveLen(1))
+		spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+		g.Expect(spans).To(o.HaveLen(2))
+
+		g.Expect(spans[0].Name).To(o.Equal("shp build run"))
+		g.Expect(spans[0].ParentSpanID).To(o.BeEmpty())
+		g.Expect(spans[0].Status.Code).To(o.Equal(statusCodeOK))
+
+		g.Expect(spans[1].Name).To(o.Equal("create BuildRun"))
+		g.Expect(spans[1].ParentSpanID).To(o.Equal(spans[0].SpanID))
+		g.Expect(spans[1].TraceID).To(o.Equal(spans[0].TraceID))
+		g.Expect(spans[1].Status).To(o.Equal(otlpStatus{Code: statusCodeError, Message: "denied"}))
+		g.Expect(spans[1].Attributes).To(o.Equal([]otlpAttribute{
+			{Key: "buildrun", Value: otlpValue{StringValue: "br-1"}},
+		}))
+		// the second End call doesn't move the end of the span
+		g.Expect(spans[1].StartTimeUnixNano).To(o.Equal("1700000002000000000"))
+		g.Expect(spans[1].EndTimeUnixNano).To(o.Equal("1700000003000000000"))
+	})
+}
+
+func TestWriteFile(t *testing.T) {
+	g := o.NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	for _, name := range []string{"shp build run", "shp buildrun logs"} {
+		tracer := newTestTracer()
+		_, span := Start(WithTracer(context.TODO(), tracer), name)
+		span.End()
+		g.Expect(tracer.WriteFile(path)).To(o.Succeed())
+	}
+
+	data, err := os.ReadFile(path)
+	g.Expect(err).To(o.BeNil())
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	g.Expect(lines).To(o.HaveLen(2))
+
+	var req otlpRequest
+	g.Expect(json.Unmarshal([]byte(lines[1]), &req)).To(o.Succeed())
+	g.Expect(req.ResourceSpans[0].Resource.Attributes).To(o.ContainElement(
+		otlpAttribute{Key: "service.name", Value: otlpValue{StringValue: "shp"}}))
+	g.Expect(req.ResourceSpans[0].ScopeSpans[0].Spans[0].Name).To(o.Equal("shp buildrun logs"))
+}
+
+func TestSend(t *testing.T) {
+	g := o.NewWithT(t)
+
+	var received otlpRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != tracesPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tracer := newTestTracer()
+	_, span := Start(WithTracer(context.TODO(), tracer), "shp build run")
+	span.End()
+
+	g.Expect(tracer.Send(context.TODO(), server.Client(), server.URL+"/")).To(o.Succeed())
+	g.Expect(received.ResourceSpans[0].ScopeSpans[0].Spans).To(o.HaveLen(1))
+
+	g.Expect(tracer.Send(context.TODO(), server.Client(), server.URL+tracesPath)).To(o.Succeed())
+
+	err := tracer.Send(context.TODO(), server.Client(), server.URL+"/collector")
+	g.Expect(err).To(o.HaveOccurred())
+	g.Expect(err.Error()).To(o.ContainSubstring("404 Not Found"))
+}
@@ -0,0 +1,3 @@
+// Package tui renders a full-screen terminal view of a BuildRun being followed, the build steps
+// with their state on the upper pane, and the scrolling logs on the lower pane.
+package tui
@@ -0,0 +1,284 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/styles"
+)
+
+// ANSI escape sequences to control the terminal.
+const (
+	enterAltScreen = "\033[?1049h"
+	leaveAltScreen = "\033[?1049l"
+	hideCursor     = "\033[?25l"
+	showCursor     = "\033[?25h"
+	cursorHome     = "\033[H"
+	clearLine      = "\033[K"
+	clearBelow     = "\033[J"
+	resetStyle     = "\033[0m"
+)
+
+const (
+	// maxLogLines amount of log lines retained to fill the logs pane.
+	maxLogLines = 1000
+	// refreshInterval interval between redraws, the screen is not redrawn on every log line.
+	refreshInterval = 100 * time.Millisecond
+	// stepPrefix the prefix of the build pod containers running the build strategy steps.
+	stepPrefix = "step-"
+)
+
+// spinner frames animating the running steps.
+var spinner = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// SizeFn returns the terminal width and height.
+type SizeFn func() (int, int, error)
+
+// Screen full-screen view of a BuildRun being followed, the steps are taken from the container
+// tracker registered on the pod watcher, and the logs are written to the screen as an io.Writer.
+type Screen struct {
+	lock sync.Mutex
+
+	out      io.Writer                 // terminal
+	size     SizeFn                    // terminal size
+	title    string                    // headline, i.e. the BuildRun name
+	status   string                    // BuildRun status, i.e. the reason of its condition
+	tracker  *reactor.ContainerTracker // build pod container transitions
+	now      func() time.Time          // current time, replaceable for testing purposes
+	started  time.Time                 // moment the screen has been started
+	frame    int                       // spinner frame
+	lines    []string                  // log lines retained
+	partial  []byte                    // log line not yet terminated
+	stopCh   chan struct{}             // stops the refresh loop
+	doneCh   chan struct{}             // closed when the refresh loop is over
+	stopOnce sync.Once
+}
+
+// NewScreen instantiate a Screen writing on the informed terminal.
+func NewScreen(out io.Writer, size SizeFn, title string, tracker *reactor.ContainerTracker) *Screen {
+	return &Screen{
+		out:     out,
+		size:    size,
+		title:   title,
+		tracker: tracker,
+		now:     time.Now,
+		lines:   []string{},
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// Write appends the log lines to the logs pane, an unterminated line is kept until its end.
+func (s *Screen) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	data := append(s.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		s.lines = append(s.lines, strings.TrimSuffix(string(data[:i]), "\r"))
+		data = data[i+1:]
+	}
+	s.partial = append([]byte{}, data...)
+	if len(s.lines) > maxLogLines {
+		s.lines = append([]string{}, s.lines[len(s.lines)-maxLogLines:]...)
+	}
+	return len(p), nil
+}
+
+// SetStatus sets the BuildRun status shown on the headline, empty hides it.
+func (s *Screen) SetStatus(status string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status = status
+}
+
+// Start switches the terminal to the alternate screen, and redraws it periodically until stopped.
+func (s *Screen) Start() {
+	s.started = s.now()
+	fmt.Fprint(s.out, enterAltScreen+hideCursor)
+	go func() {
+		defer close(s.doneCh)
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			s.draw()
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop restores the terminal, and prints the last frame so the outcome remains visible once the
+// alternate screen is gone.
+func (s *Screen) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+		<-s.doneCh
+		fmt.Fprint(s.out, showCursor+leaveAltScreen)
+
+		width, height := s.terminalSize()
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if len(s.partial) > 0 {
+			s.lines = append(s.lines, string(s.partial))
+			s.partial = nil
+		}
+		for _, line := range s.render(width, height, false) {
+			fmt.Fprintln(s.out, line)
+		}
+	})
+}
+
+// terminalSize returns the terminal size, falling back to the classic 80x24 when unknown.
+func (s *Screen) terminalSize() (int, int) {
+	width, height, err := s.size()
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// draw redraws the whole screen, each line overwrites the previous frame.
+func (s *Screen) draw() {
+	width, height := s.terminalSize()
+	s.lock.Lock()
+	s.frame++
+	lines := s.render(width, height, true)
+	s.lock.Unlock()
+
+	var b strings.Builder
+	b.WriteString(cursorHome)
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line + clearLine)
+	}
+	b.WriteString(clearBelow)
+	fmt.Fprint(s.out, b.String())
+}
+
+// render returns the lines of the frame: the headline, a line per step, a separator and the last
+// log lines fitting the height. When filling the screen the logs pane takes the remaining height,
+// otherwise only the log lines retained are rendered.
+func (s *Screen) render(width, height int, fill bool) []string {
+	steps := s.steps()
+	// the steps pane never takes more than half of the screen
+	if limit := height / 2; len(steps) > limit {
+		steps = steps[len(steps)-limit:]
+	}
+
+	lines := []string{truncate(styles.Bold(s.headline()), width)}
+	for _, step := range steps {
+		lines = append(lines, truncate(step, width))
+	}
+	lines = append(lines, styles.Faint(strings.Repeat("─", width)))
+
+	logs := s.lines
+	if room := height - len(lines); room <= 0 {
+		logs = nil
+	} else if len(logs) > room {
+		logs = logs[len(logs)-room:]
+	}
+	for _, line := range logs {
+		lines = append(lines, truncate(line, width))
+	}
+	if fill {
+		for len(lines) < height {
+			lines = append(lines, "")
+		}
+	}
+	return lines
+}
+
+// headline describes the BuildRun followed, its status, its pod and for how long it has been followed.
+func (s *Screen) headline() string {
+	headline := s.title
+	if s.status != "" {
+		headline = fmt.Sprintf("%s · %s", headline, s.status)
+	}
+	if pod := s.tracker.PodName(); pod != "" {
+		headline = fmt.Sprintf("%s · pod %q", headline, pod)
+	}
+	return fmt.Sprintf("%s · %s", headline, s.now().Sub(s.started).Round(time.Second))
+}
+
+// steps renders a line per build pod container, with its state.
+func (s *Screen) steps() []string {
+	timelines := s.tracker.Containers()
+	if len(timelines) == 0 {
+		return []string{styles.Faint("  waiting for the build pod...")}
+	}
+	steps := make([]string, 0, len(timelines))
+	for _, c := range timelines {
+		name := strings.TrimPrefix(c.Name, stepPrefix)
+		var icon, state string
+		switch {
+		case !c.Finished.IsZero() && c.ExitCode != 0:
+			icon = styles.Failure("✖")
+			state = styles.Failure(fmt.Sprintf("failed after %s (exit code %d)", c.Duration().Round(time.Second), c.ExitCode))
+		case !c.Finished.IsZero():
+			icon = styles.Success("✔")
+			state = styles.Success(fmt.Sprintf("completed in %s", c.Duration().Round(time.Second)))
+		case !c.Started.IsZero():
+			icon = styles.Warning(spinner[s.frame%len(spinner)])
+			state = styles.Warning(fmt.Sprintf("running %s", s.now().Sub(c.Started).Round(time.Second)))
+		case !c.Waiting.IsZero():
+			icon = styles.Faint("…")
+			state = styles.Faint("waiting")
+		default:
+			icon = styles.Faint("·")
+			state = styles.Faint("pending")
+		}
+		steps = append(steps, fmt.Sprintf("  %s %-24s %s", icon, name, state))
+	}
+	return steps
+}
+
+// truncate cuts the line at the informed amount of visible characters, escape sequences do not
+// take room on the screen, and the style is reset when the line is cut in the middle of it.
+func truncate(line string, width int) string {
+	var b strings.Builder
+	visible := 0
+	styled := false
+	for i := 0; i < len(line); {
+		if line[i] == '\033' {
+			end := strings.IndexByte(line[i:], 'm')
+			if end < 0 {
+				break
+			}
+			b.WriteString(line[i : i+end+1])
+			styled = line[i:i+end+1] != resetStyle
+			i += end + 1
+			continue
+		}
+		if visible == width {
+			if styled {
+				b.WriteString(resetStyle)
+			}
+			return b.String()
+		}
+		r, size := utf8.DecodeRuneInString(line[i:])
+		if r == '\t' {
+			b.WriteString(" ")
+		} else {
+			b.WriteRune(r)
+		}
+		visible++
+		i += size
+	}
+	return b.String()
+}
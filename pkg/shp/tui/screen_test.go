@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+)
+
+func fixedSize(width, height int) SizeFn {
+	return func() (int, int, error) {
+		return width, height, nil
+	}
+}
+
+func TestScreenWrite(t *testing.T) {
+	g := o.NewWithT(t)
+
+	s := NewScreen(&bytes.Buffer{}, fixedSize(80, 24), "BuildRun", reactor.NewContainerTracker())
+	_, err := fmt.Fprint(s, "first\nsec")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(s.lines).To(o.Equal([]string{"first"}))
+
+	_, err = fmt.Fprint(s, "ond\r\nthird\n")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(s.lines).To(o.Equal([]string{"first", "second", "third"}))
+
+	for i := 0; i < maxLogLines; i++ {
+		fmt.Fprintf(s, "line %d\n", i)
+	}
+	g.Expect(s.lines).To(o.HaveLen(maxLogLines))
+	g.Expect(s.lines[maxLogLines-1]).To(o.Equal(fmt.Sprintf("line %d", maxLogLines-1)))
+}
+
+func TestScreenRender(t *testing.T) {
+	g := o.NewWithT(t)
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	tracker := reactor.NewContainerTracker()
+	g.Expect(tracker.OnEvent(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-pod"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "step-source"}, {Name: "step-build"}, {Name: "step-push"}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "step-source",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					StartedAt:  metav1.NewTime(now.Add(-time.Minute)),
+					FinishedAt: metav1.NewTime(now.Add(-50 * time.Second)),
+				}},
+			}, {
+				Name: "step-build",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{
+					StartedAt: metav1.NewTime(now.Add(-50 * time.Second)),
+				}},
+			}},
+		},
+	})).To(o.Succeed())
+
+	s := NewScreen(&bytes.Buffer{}, fixedSize(60, 10), `BuildRun "my-app"`, tracker)
+	s.now = func() time.Time { return now }
+	s.started = now.Add(-time.Minute)
+	for i := 1; i <= 5; i++ {
+		fmt.Fprintf(s, "[build] line %d\n", i)
+	}
+
+	lines := s.render(60, 10, true)
+	g.Expect(lines).To(o.HaveLen(10))
+	g.Expect(lines[0]).To(o.Equal(`BuildRun "my-app" · pod "my-app-pod" · 1m0s`))
+	g.Expect(strings.Fields(lines[1])).To(o.Equal([]string{"✔", "source", "completed", "in", "10s"}))
+	g.Expect(strings.Fields(lines[2])).To(o.Equal([]string{spinner[0], "build", "running", "50s"}))
+	g.Expect(strings.Fields(lines[3])).To(o.Equal([]string{"·", "push", "pending"}))
+	g.Expect(lines[4]).To(o.Equal(strings.Repeat("─", 60)))
+	// the logs pane shows the last lines fitting the screen
+	g.Expect(lines[5:]).To(o.Equal([]string{
+		"[build] line 1", "[build] line 2", "[build] line 3", "[build] line 4", "[build] line 5",
+	}))
+
+	lines = s.render(60, 8, true)
+	g.Expect(lines[5:]).To(o.Equal([]string{"[build] line 3", "[build] line 4", "[build] line 5"}))
+
+	// the steps pane never takes more than half of the screen
+	lines = s.render(60, 4, false)
+	g.Expect(lines).To(o.HaveLen(4))
+	g.Expect(strings.Fields(lines[1])).To(o.Equal([]string{spinner[0], "build", "running", "50s"}))
+
+	s.SetStatus("Running")
+	g.Expect(s.render(60, 10, true)[0]).To(o.Equal(`BuildRun "my-app" · Running · pod "my-app-pod" · 1m0s`))
+}
+
+func TestScreenStartStop(t *testing.T) {
+	g := o.NewWithT(t)
+
+	out := &bytes.Buffer{}
+	s := NewScreen(out, fixedSize(40, 10), `BuildRun "my-app"`, reactor.NewContainerTracker())
+	s.Start()
+	fmt.Fprint(s, "unterminated")
+	s.Stop()
+	s.Stop()
+
+	output := out.String()
+	g.Expect(output).To(o.HavePrefix(enterAltScreen + hideCursor))
+	restored := strings.Index(output, showCursor+leaveAltScreen)
+	g.Expect(restored).To(o.BeNumerically(">", 0))
+
+	// the last frame is printed once the terminal is restored
+	last := strings.Split(strings.TrimSpace(output[restored+len(showCursor+leaveAltScreen):]), "\n")
+	g.Expect(last).To(o.HaveLen(4))
+	g.Expect(last[1]).To(o.ContainSubstring("waiting for the build pod..."))
+	g.Expect(last[3]).To(o.Equal("unterminated"))
+}
+
+func TestTruncate(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(truncate("short", 10)).To(o.Equal("short"))
+	g.Expect(truncate("a longer line", 6)).To(o.Equal("a long"))
+	g.Expect(truncate("tab\there", 10)).To(o.Equal("tab here"))
+	g.Expect(truncate("\033[31mred text\033[0m", 3)).To(o.Equal("\033[31mred" + resetStyle))
+	g.Expect(truncate("\033[31mred\033[0m text", 5)).To(o.Equal("\033[31mred\033[0m t"))
+	g.Expect(truncate("ünïcödé", 3)).To(o.Equal("ünï"))
+}
@@ -0,0 +1,98 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// WaitForBuildRunDone polls the informed BuildRun until it reaches a terminal state, the build
+// controller may take a moment to update the BuildRun after the build pod is completed. A zero
+// timeout means waiting until the context is done.
+func WaitForBuildRunDone(
+	ctx context.Context,
+	client buildclientset.Interface,
+	ns, name string,
+	interval, timeout time.Duration,
+) (*buildv1alpha1.BuildRun, error) {
+	var br *buildv1alpha1.BuildRun
+	condition := func(ctx context.Context) (bool, error) {
+		var err error
+		if br, err = client.ShipwrightV1alpha1().BuildRuns(ns).Get(ctx, name, metav1.GetOptions{}); err != nil {
+			return false, err
+		}
+		return br.IsDone(), nil
+	}
+	var err error
+	if timeout <= 0 {
+		err = wait.PollUntilContextCancel(ctx, interval, true, condition)
+	} else {
+		err = wait.PollUntilContextTimeout(ctx, interval, timeout, true, condition)
+	}
+	return br, err
+}
+
+// CancelBuildRun requests the cancellation of the informed BuildRun, by patching its state, the
+// build controller stops the build pod afterwards.
+func CancelBuildRun(ctx context.Context, client buildclientset.Interface, ns, name string) error {
+	type patchStringValue struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value string `json:"value"`
+	}
+	payload := []patchStringValue{{
+		Op:    "replace",
+		Path:  "/spec/state",
+		Value: buildv1alpha1.BuildRunStateCancel,
+	}}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = client.ShipwrightV1alpha1().BuildRuns(ns).Patch(ctx, name, types.JSONPatchType, data, metav1.PatchOptions{})
+	return err
+}
+
+// BuildRunPhase describes where a BuildRun is in its lifecycle.
+type BuildRunPhase string
+
+// BuildRun phases.
+const (
+	PhasePending   BuildRunPhase = "Pending"
+	PhaseRunning   BuildRunPhase = "Running"
+	PhaseSucceeded BuildRunPhase = "Succeeded"
+	PhaseFailed    BuildRunPhase = "Failed"
+)
+
+// PhaseOf inspects the BuildRun's "Succeeded" condition and start time to tell its phase.
+func PhaseOf(br *buildv1alpha1.BuildRun) BuildRunPhase {
+	c := br.Status.GetCondition(buildv1alpha1.Succeeded)
+	switch {
+	case c == nil:
+		return PhasePending
+	case c.Status == corev1.ConditionTrue:
+		return PhaseSucceeded
+	case c.Status == corev1.ConditionFalse:
+		return PhaseFailed
+	case c.Reason == string(PhasePending) || !br.HasStarted():
+		return PhasePending
+	default:
+		return PhaseRunning
+	}
+}
+
+// CompletionTimeOf returns the BuildRun completion time, falling back to the creation time.
+func CompletionTimeOf(br *buildv1alpha1.BuildRun) time.Time {
+	if br.Status.CompletionTime != nil {
+		return br.Status.CompletionTime.Time
+	}
+	return br.CreationTimestamp.Time
+}
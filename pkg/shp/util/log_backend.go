@@ -0,0 +1,53 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// LogBackendTokenEnvVar environment variable with the bearer token sent to the log backend.
+const LogBackendTokenEnvVar = "SHP_LOG_BACKEND_TOKEN" // #nosec G101
+
+// maxBackendLogsSize upper bound of the logs read from the log backend.
+const maxBackendLogsSize = 64 * 1024 * 1024
+
+// LogBackendURL expands the "{namespace}", "{pod}" and "{buildrun}" placeholders of the log backend
+// URL template with the query escaped values.
+func LogBackendURL(template, namespace, pod, buildRun string) string {
+	return strings.NewReplacer(
+		"{namespace}", url.QueryEscape(namespace),
+		"{pod}", url.QueryEscape(pod),
+		"{buildrun}", url.QueryEscape(buildRun),
+	).Replace(template)
+}
+
+// GetBackendLogs obtains the logs served as plain text by the log backend, sending the bearer
+// token informed on the SHP_LOG_BACKEND_TOKEN environment variable.
+func GetBackendLogs(ctx context.Context, backendURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, backendURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/plain")
+	if token := os.Getenv(LogBackendTokenEnvVar); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("log backend replied %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBackendLogsSize))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
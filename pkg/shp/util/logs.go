@@ -11,9 +11,18 @@ import (
 
 // GetPodLogs returns log output of the k8s container provided by pod and name
 func GetPodLogs(ctx context.Context, client kubernetes.Interface, pod corev1.Pod, container string) (string, error) {
+	return GetPodLogsTail(ctx, client, pod, container, 0)
+}
+
+// GetPodLogsTail returns the last lines of the log output of the k8s container provided by pod and
+// name, the whole log output when lines is not positive.
+func GetPodLogsTail(ctx context.Context, client kubernetes.Interface, pod corev1.Pod, container string, lines int64) (string, error) {
 	podLogOpts := corev1.PodLogOptions{
 		Container: container,
 	}
+	if lines > 0 {
+		podLogOpts.TailLines = &lines
+	}
 	req := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &podLogOpts)
 	podLogs, err := req.Stream(ctx)
 	if err != nil {
@@ -29,3 +38,15 @@ func GetPodLogs(ctx context.Context, client kubernetes.Interface, pod corev1.Pod
 
 	return buf.String(), nil
 }
+
+// FailedContainer returns the name of the first container terminated with a non-zero exit code,
+// init containers first, or an empty string when none has failed.
+func FailedContainer(pod *corev1.Pod) string {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			return status.Name
+		}
+	}
+	return ""
+}
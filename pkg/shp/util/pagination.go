@@ -0,0 +1,59 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultChunkSize amount of items requested per page, the same as kubectl.
+const DefaultChunkSize int64 = 500
+
+// BuildRunListFn lists BuildRuns, i.e. the List method of the BuildRuns client.
+type BuildRunListFn func(ctx context.Context, opts metav1.ListOptions) (*buildv1alpha1.BuildRunList, error)
+
+// BuildRunPageFn handles a page of BuildRuns as soon as it arrives.
+type BuildRunPageFn func(page *buildv1alpha1.BuildRunList) error
+
+// ListBuildRunPages lists the BuildRuns in pages of chunkSize items, zero meaning a single request,
+// starting from the continue token informed on the options. At most limit items are listed, zero
+// meaning all of them, the pages are shrunk to stop right on the limit. Returns the token to resume
+// the listing when it stops on the limit, empty when there is nothing left.
+func ListBuildRunPages(
+	ctx context.Context,
+	list BuildRunListFn,
+	opts metav1.ListOptions,
+	limit, chunkSize int64,
+	fn BuildRunPageFn,
+) (string, error) {
+	listed := int64(0)
+	for {
+		opts.Limit = chunkSize
+		if limit > 0 && (opts.Limit <= 0 || limit-listed < opts.Limit) {
+			opts.Limit = limit - listed
+		}
+		page, err := list(ctx, opts)
+		if err != nil {
+			if kerrors.IsResourceExpired(err) && opts.Continue != "" {
+				return "", fmt.Errorf("the continue token has expired, list again without --continue: %w", err)
+			}
+			return "", err
+		}
+		if err = fn(page); err != nil {
+			return "", err
+		}
+		listed += int64(len(page.Items))
+
+		if page.Continue == "" {
+			return "", nil
+		}
+		if limit > 0 && listed >= limit {
+			return page.Continue, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
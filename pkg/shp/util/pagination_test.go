@@ -0,0 +1,110 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pagedBuildRuns list function serving the amount of BuildRuns informed, honoring the limit and
+// continue token like the API server, the requested limits are recorded.
+func pagedBuildRuns(total int, limits *[]int64) BuildRunListFn {
+	return func(_ context.Context, opts metav1.ListOptions) (*buildv1alpha1.BuildRunList, error) {
+		*limits = append(*limits, opts.Limit)
+		start := 0
+		if opts.Continue != "" {
+			var err error
+			if start, err = strconv.Atoi(opts.Continue); err != nil {
+				return nil, kerrors.NewResourceExpired("continue token is invalid")
+			}
+		}
+		end := total
+		if opts.Limit > 0 && start+int(opts.Limit) < total {
+			end = start + int(opts.Limit)
+		}
+		list := &buildv1alpha1.BuildRunList{}
+		for i := start; i < end; i++ {
+			list.Items = append(list.Items, buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("br-%d", i)}})
+		}
+		if end < total {
+			list.Continue = strconv.Itoa(end)
+		}
+		return list, nil
+	}
+}
+
+func TestListBuildRunPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int64
+		chunkSize     int64
+		continueToken string
+		pages         []int
+		limits        []int64
+		nextToken     string
+	}{{
+		name:      "all BuildRuns in chunks",
+		chunkSize: 4,
+		pages:     []int{4, 4, 2},
+		limits:    []int64{4, 4, 4},
+	}, {
+		name:   "a single request without chunks",
+		pages:  []int{10},
+		limits: []int64{0},
+	}, {
+		name:      "the last page is shrunk to stop on the limit",
+		limit:     6,
+		chunkSize: 4,
+		pages:     []int{4, 2},
+		limits:    []int64{4, 2},
+		nextToken: "6",
+	}, {
+		name:          "continuing a limited list",
+		limit:         6,
+		chunkSize:     4,
+		continueToken: "6",
+		pages:         []int{4},
+		limits:        []int64{4},
+	}, {
+		name:      "limit without chunks",
+		limit:     3,
+		pages:     []int{3},
+		limits:    []int64{3},
+		nextToken: "3",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			limits := []int64{}
+			pages := []int{}
+			token, err := ListBuildRunPages(
+				context.TODO(),
+				pagedBuildRuns(10, &limits),
+				metav1.ListOptions{Continue: tt.continueToken},
+				tt.limit,
+				tt.chunkSize,
+				func(page *buildv1alpha1.BuildRunList) error {
+					pages = append(pages, len(page.Items))
+					return nil
+				},
+			)
+			g.Expect(err).To(o.BeNil())
+			g.Expect(pages).To(o.Equal(tt.pages))
+			g.Expect(limits).To(o.Equal(tt.limits))
+			g.Expect(token).To(o.Equal(tt.nextToken))
+		})
+	}
+
+	g := o.NewWithT(t)
+	_, err := ListBuildRunPages(context.TODO(), pagedBuildRuns(10, &[]int64{}), metav1.ListOptions{Continue: "expired"}, 0, 4,
+		func(_ *buildv1alpha1.BuildRunList) error { return nil })
+	g.Expect(err).To(o.MatchError(o.HavePrefix("the continue token has expired")))
+}
@@ -0,0 +1,140 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	progressbar "github.com/schollz/progressbar/v3"
+	"golang.org/x/term"
+
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+)
+
+// progressInterval interval between the progress lines printed when the output is not a terminal.
+const progressInterval = 5 * time.Second
+
+// Progress reports the bytes transferred, the transfer rate and the estimated time left. On a
+// terminal a progress bar is rendered, otherwise a line is printed periodically, thus the output
+// remains readable on CI logs.
+type Progress struct {
+	lock sync.Mutex
+
+	out         io.Writer                // where the progress is reported
+	description string                   // what is being transferred
+	bar         *progressbar.ProgressBar // progress bar, only on a terminal
+	now         func() time.Time         // current time, replaceable for testing purposes
+
+	started  time.Time // moment the transfer started
+	reported time.Time // moment the progress was last reported
+	total    int64     // bytes to transfer, zero when unknown
+	complete int64     // bytes transferred
+	finished bool
+}
+
+// NewProgress instantiate a Progress reporting on the informed writer.
+func NewProgress(out io.Writer, description string, total int64) *Progress {
+	p := &Progress{out: out, description: description, now: time.Now, total: total}
+	p.started, p.reported = p.now(), p.now()
+	if f, ok := out.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		p.bar = progressbar.NewOptions64(total,
+			progressbar.OptionSetWriter(out),
+			progressbar.OptionEnableColorCodes(true),
+			progressbar.OptionShowBytes(true),
+			progressbar.OptionUseIECUnits(true),
+			progressbar.OptionSetWidth(15),
+			progressbar.OptionSetPredictTime(true),
+			progressbar.OptionSetDescription(description),
+			progressbar.OptionSetTheme(progressbar.Theme{
+				Saucer:        "[green]=[reset]",
+				SaucerHead:    "[green]>[reset]",
+				SaucerPadding: " ",
+				BarStart:      "[",
+				BarEnd:        "]"}),
+			progressbar.OptionOnCompletion(func() {
+				fmt.Fprintln(out)
+			}),
+		)
+	}
+	return p
+}
+
+// Write accounts the bytes as transferred, thus the Progress can be employed on a io.TeeReader.
+func (p *Progress) Write(b []byte) (int, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.complete += int64(len(b))
+	p.update()
+	return len(b), nil
+}
+
+// Set informs the bytes transferred so far, and the total, which may change during the transfer.
+func (p *Progress) Set(complete, total int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.complete, p.total = complete, total
+	p.update()
+}
+
+// update renders the progress bar, or prints a line when the interval has passed, the lock must be
+// held.
+func (p *Progress) update() {
+	if p.finished {
+		return
+	}
+	if p.bar != nil {
+		if p.total != p.bar.GetMax64() {
+			p.bar.ChangeMax64(p.total)
+		}
+		_ = p.bar.Set64(p.complete)
+		return
+	}
+	now := p.now()
+	if now.Sub(p.reported) < progressInterval {
+		return
+	}
+	p.reported = now
+	fmt.Fprintln(p.out, p.line(now))
+}
+
+// line describes the progress in a single line, with the rate and estimated time left.
+func (p *Progress) line(now time.Time) string {
+	elapsed := now.Sub(p.started)
+	rate := int64(0)
+	if elapsed > 0 {
+		rate = int64(float64(p.complete) / elapsed.Seconds())
+	}
+	if p.total <= 0 {
+		return fmt.Sprintf("%s %s, %s/s", p.description, tail.FormatBytes(p.complete), tail.FormatBytes(rate))
+	}
+	line := fmt.Sprintf("%s %s/%s (%d%%), %s/s", p.description, tail.FormatBytes(p.complete),
+		tail.FormatBytes(p.total), p.complete*100/p.total, tail.FormatBytes(rate))
+	if rate > 0 && p.complete < p.total {
+		eta := time.Duration(float64(p.total-p.complete)/float64(rate)) * time.Second
+		line = fmt.Sprintf("%s, ETA %s", line, eta.Round(time.Second))
+	}
+	return line
+}
+
+// Finish completes the progress bar, or prints the summary of the transfer.
+func (p *Progress) Finish() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.finished {
+		return
+	}
+	p.finished = true
+	if p.bar != nil {
+		_ = p.bar.Finish()
+		return
+	}
+	elapsed := p.now().Sub(p.started)
+	rate := int64(0)
+	if elapsed > 0 {
+		rate = int64(float64(p.complete) / elapsed.Seconds())
+	}
+	fmt.Fprintf(p.out, "%s done, %s in %s (%s/s)\n", p.description, tail.FormatBytes(p.complete),
+		elapsed.Round(100*time.Millisecond), tail.FormatBytes(rate))
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestProgress(t *testing.T) {
+	g := o.NewWithT(t)
+
+	out := &bytes.Buffer{}
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	p := NewProgress(out, "Uploading local source...", 4*1024*1024)
+	p.now = func() time.Time { return now }
+	p.started, p.reported = now, now
+
+	// lines are printed at most once per interval when the output is not a terminal
+	_, err := p.Write(make([]byte, 1024*1024))
+	g.Expect(err).To(o.BeNil())
+	g.Expect(out.String()).To(o.BeEmpty())
+
+	now = now.Add(progressInterval)
+	_, _ = p.Write(make([]byte, 1024*1024))
+	g.Expect(out.String()).To(o.Equal("Uploading local source... 2.0Mi/4.0Mi (50%), 409.6Ki/s, ETA 5s\n"))
+
+	now = now.Add(5 * time.Second)
+	p.Set(4*1024*1024, 4*1024*1024)
+	p.Finish()
+	p.Finish()
+	g.Expect(out.String()).To(o.HaveSuffix(
+		"Uploading local source... 4.0Mi/4.0Mi (100%), 409.6Ki/s\n" +
+			"Uploading local source... done, 4.0Mi in 10s (409.6Ki/s)\n"))
+
+	// the total may be unknown
+	out.Reset()
+	p = NewProgress(out, "Pushing...", 0)
+	p.now = func() time.Time { return now }
+	p.started, p.reported = now.Add(-progressInterval), now.Add(-progressInterval)
+	p.Set(2048, 0)
+	g.Expect(out.String()).To(o.Equal("Pushing... 2.0Ki, 409B/s\n"))
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"context"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetBuildStrategy retrieves the strategy referenced by a Build, either a namespaced BuildStrategy
+// or a ClusterBuildStrategy, the later when the kind is not informed.
+func GetBuildStrategy(
+	ctx context.Context,
+	client buildclientset.Interface,
+	ns string,
+	strategy buildv1alpha1.Strategy,
+) (buildv1alpha1.BuilderStrategy, error) {
+	if strategy.Kind != nil && *strategy.Kind == buildv1alpha1.NamespacedBuildStrategyKind {
+		return client.ShipwrightV1alpha1().BuildStrategies(ns).Get(ctx, strategy.Name, metav1.GetOptions{})
+	}
+	return client.ShipwrightV1alpha1().ClusterBuildStrategies().Get(ctx, strategy.Name, metav1.GetOptions{})
+}
+
+// ListBuildStrategyNames lists the names of the strategies of the informed kind, the namespaced
+// BuildStrategies are listed on the informed namespace.
+func ListBuildStrategyNames(
+	ctx context.Context,
+	client buildclientset.Interface,
+	ns string,
+	kind buildv1alpha1.BuildStrategyKind,
+) ([]string, error) {
+	names := []string{}
+	if kind == buildv1alpha1.NamespacedBuildStrategyKind {
+		list, err := client.ShipwrightV1alpha1().BuildStrategies(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range list.Items {
+			names = append(names, s.GetName())
+		}
+		return names, nil
+	}
+	list, err := client.ShipwrightV1alpha1().ClusterBuildStrategies().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range list.Items {
+		names = append(names, s.GetName())
+	}
+	return names, nil
+}
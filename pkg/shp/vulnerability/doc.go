@@ -0,0 +1,3 @@
+// Package vulnerability reads the vulnerability scan results reported on the BuildRun status by
+// build strategies scanning the output image, and summarizes them by severity.
+package vulnerability
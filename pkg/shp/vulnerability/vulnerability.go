@@ -0,0 +1,152 @@
+package vulnerability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	"k8s.io/client-go/rest"
+)
+
+// Severity of a vulnerability, as reported by the scanner.
+type Severity string
+
+// Severities reported by the build strategies, from the most to the least severe.
+const (
+	Critical Severity = "critical"
+	High     Severity = "high"
+	Medium   Severity = "medium"
+	Low      Severity = "low"
+	Unknown  Severity = "unknown"
+)
+
+// Severities all severities, from the most to the least severe.
+var Severities = []Severity{Critical, High, Medium, Low, Unknown}
+
+// rank returns the position of the severity, lower is more severe, unknown severities last.
+func (s Severity) rank() int {
+	for i, severity := range Severities {
+		if strings.EqualFold(string(s), string(severity)) {
+			return i
+		}
+	}
+	return len(Severities) - 1
+}
+
+// ParseSeverity finds the severity informed, case insensitive.
+func ParseSeverity(s string) (Severity, error) {
+	for _, severity := range Severities {
+		if strings.EqualFold(string(severity), s) {
+			return severity, nil
+		}
+	}
+	return "", fmt.Errorf("unknown severity %q, expected one of %v", s, Severities)
+}
+
+// Vulnerability a single vulnerability found on the output image.
+type Vulnerability struct {
+	ID       string   `json:"id,omitempty"`
+	Severity Severity `json:"severity,omitempty"`
+}
+
+// buildRunScan the part of the BuildRun carrying the scan results, which are not part of the
+// v1alpha1 types yet.
+type buildRunScan struct {
+	Status struct {
+		Output *struct {
+			Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+		} `json:"output,omitempty"`
+	} `json:"status"`
+}
+
+// FromJSON extracts the vulnerabilities out of the BuildRun JSON representation, sorted from the
+// most to the least severe.
+func FromJSON(data []byte) ([]Vulnerability, error) {
+	scan := buildRunScan{}
+	if err := json.Unmarshal(data, &scan); err != nil {
+		return nil, err
+	}
+	if scan.Status.Output == nil {
+		return nil, nil
+	}
+	vulns := scan.Status.Output.Vulnerabilities
+	sort.SliceStable(vulns, func(i, j int) bool {
+		return vulns[i].Severity.rank() < vulns[j].Severity.rank()
+	})
+	return vulns, nil
+}
+
+// RESTClient returns the REST client of the Shipwright clientset, nil when the clientset does not
+// carry one, like the fake clientsets.
+func RESTClient(clientset buildclientset.Interface) rest.Interface {
+	client := clientset.ShipwrightV1alpha1().RESTClient()
+	if c, ok := client.(*rest.RESTClient); ok && c == nil {
+		return nil
+	}
+	return client
+}
+
+// Fetch retrieves the BuildRun as raw JSON and extracts the vulnerabilities, none are returned when
+// the client is nil.
+func Fetch(ctx context.Context, client rest.Interface, namespace, name string) ([]Vulnerability, error) {
+	if client == nil {
+		return nil, nil
+	}
+	data, err := client.Get().
+		Namespace(namespace).
+		Resource("buildruns").
+		Name(name).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+	return FromJSON(data)
+}
+
+// Counts amount of vulnerabilities per severity.
+func Counts(vulns []Vulnerability) map[Severity]int {
+	counts := map[Severity]int{}
+	for _, v := range vulns {
+		counts[Severities[v.Severity.rank()]]++
+	}
+	return counts
+}
+
+// AtLeast returns the vulnerabilities as severe as the informed threshold, or more.
+func AtLeast(vulns []Vulnerability, threshold Severity) []Vulnerability {
+	found := []Vulnerability{}
+	for _, v := range vulns {
+		if v.Severity.rank() <= threshold.rank() {
+			found = append(found, v)
+		}
+	}
+	return found
+}
+
+// PrintSummary prints the amount of vulnerabilities per severity, followed by the most severe ones,
+// up to the informed amount.
+func PrintSummary(w io.Writer, vulns []Vulnerability, top int) {
+	counts := Counts(vulns)
+	parts := make([]string, 0, len(Severities))
+	for _, severity := range Severities {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+	}
+	fmt.Fprintf(w, "Vulnerabilities: %s\n", strings.Join(parts, ", "))
+
+	if top <= 0 || len(vulns) == 0 {
+		return
+	}
+	if top > len(vulns) {
+		top = len(vulns)
+	}
+	fmt.Fprintf(w, "Top vulnerabilities:\n")
+	for _, v := range vulns[:top] {
+		fmt.Fprintf(w, "  %-8s  %s\n", strings.ToUpper(string(v.Severity)), v.ID)
+	}
+}
@@ -0,0 +1,95 @@
+package vulnerability
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+)
+
+const buildRunJSON = `{
+	"metadata": {"name": "br"},
+	"status": {
+		"output": {
+			"digest": "sha256:abc",
+			"vulnerabilities": [
+				{"id": "CVE-2023-0003", "severity": "low"},
+				{"id": "CVE-2023-0001", "severity": "critical"},
+				{"id": "CVE-2023-0004", "severity": "negligible"},
+				{"id": "CVE-2023-0002", "severity": "high"}
+			]
+		}
+	}
+}`
+
+func TestFromJSON(t *testing.T) {
+	g := o.NewWithT(t)
+
+	vulns, err := FromJSON([]byte(buildRunJSON))
+	g.Expect(err).ToNot(o.HaveOccurred())
+	g.Expect(vulns).To(o.Equal([]Vulnerability{
+		{ID: "CVE-2023-0001", Severity: Critical},
+		{ID: "CVE-2023-0002", Severity: High},
+		{ID: "CVE-2023-0003", Severity: Low},
+		{ID: "CVE-2023-0004", Severity: "negligible"},
+	}))
+
+	g.Expect(Counts(vulns)).To(o.Equal(map[Severity]int{Critical: 1, High: 1, Low: 1, Unknown: 1}))
+	g.Expect(AtLeast(vulns, High)).To(o.HaveLen(2))
+	g.Expect(AtLeast(vulns, Unknown)).To(o.HaveLen(4))
+
+	vulns, err = FromJSON([]byte(`{"status": {}}`))
+	g.Expect(err).ToNot(o.HaveOccurred())
+	g.Expect(vulns).To(o.BeEmpty())
+}
+
+func TestParseSeverity(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(ParseSeverity("HIGH")).To(o.Equal(High))
+	_, err := ParseSeverity("severe")
+	g.Expect(err).To(o.HaveOccurred())
+}
+
+func TestPrintSummary(t *testing.T) {
+	g := o.NewWithT(t)
+
+	vulns, err := FromJSON([]byte(buildRunJSON))
+	g.Expect(err).ToNot(o.HaveOccurred())
+
+	out := &bytes.Buffer{}
+	PrintSummary(out, vulns, 2)
+	g.Expect(out.String()).To(o.Equal(`Vulnerabilities: 1 critical, 1 high, 0 medium, 1 low, 1 unknown
+Top vulnerabilities:
+  CRITICAL  CVE-2023-0001
+  HIGH      CVE-2023-0002
+`))
+}
+
+func TestFetch(t *testing.T) {
+	g := o.NewWithT(t)
+
+	client := &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Resp: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(buildRunJSON)),
+		},
+	}
+	vulns, err := Fetch(context.TODO(), client, "default", "br")
+	g.Expect(err).ToNot(o.HaveOccurred())
+	g.Expect(vulns).To(o.HaveLen(4))
+	g.Expect(client.Req.URL.Path).To(o.HaveSuffix("/namespaces/default/buildruns/br"))
+
+	vulns, err = Fetch(context.TODO(), nil, "default", "br")
+	g.Expect(err).ToNot(o.HaveOccurred())
+	g.Expect(vulns).To(o.BeEmpty())
+}
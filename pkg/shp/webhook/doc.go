@@ -0,0 +1,3 @@
+// Package webhook crafts and delivers the webhook requests sent by source control systems, like a
+// GitHub push, signed with the trigger secret token, in order to exercise the Build triggers.
+package webhook
@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitHub event header values, as sent by GitHub for the events supported by the Build triggers.
+const (
+	GitHubPushEvent        = "push"
+	GitHubPullRequestEvent = "pull_request"
+)
+
+// maxResponseBody amount of the response body kept to report failed deliveries.
+const maxResponseBody = 4096
+
+// GitHubEvent describes the simulated GitHub event.
+type GitHubEvent struct {
+	Name       string // event name, either push or pull_request
+	Repository string // repository URL, e.g. https://github.com/org/app
+	Branch     string // pushed branch, or the pull request base branch
+	HeadBranch string // pull request head branch
+	SHA        string // commit SHA pushed, or the pull request head commit
+}
+
+// gitHubRepository subset of the GitHub repository object.
+type gitHubRepository struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	HTMLURL       string `json:"html_url"`
+	CloneURL      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// gitHubUser subset of the GitHub user object.
+type gitHubUser struct {
+	Login string `json:"login"`
+}
+
+// RandomSHA generates a random commit SHA, for events which don't refer to an existing commit.
+func RandomSHA() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Payload renders the JSON payload of the event, carrying the fields inspected by the triggers.
+func (e *GitHubEvent) Payload() ([]byte, error) {
+	u, err := url.Parse(e.Repository)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("repository %q is not an URL", e.Repository)
+	}
+	fullName := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	htmlURL := fmt.Sprintf("%s://%s/%s", u.Scheme, u.Host, fullName)
+	repository := gitHubRepository{
+		Name:          fullName[strings.LastIndex(fullName, "/")+1:],
+		FullName:      fullName,
+		HTMLURL:       htmlURL,
+		CloneURL:      htmlURL + ".git",
+		DefaultBranch: e.Branch,
+	}
+	sender := gitHubUser{Login: "shp"}
+
+	var payload interface{}
+	switch e.Name {
+	case GitHubPushEvent:
+		payload = map[string]interface{}{
+			"ref":        "refs/heads/" + e.Branch,
+			"before":     strings.Repeat("0", 40),
+			"after":      e.SHA,
+			"repository": repository,
+			"sender":     sender,
+			"pusher":     map[string]string{"name": sender.Login},
+			"head_commit": map[string]interface{}{
+				"id":        e.SHA,
+				"message":   "Simulated push sent by shp",
+				"timestamp": time.Now().UTC().Format(time.RFC3339),
+				"url":       fmt.Sprintf("%s/commit/%s", htmlURL, e.SHA),
+			},
+		}
+	case GitHubPullRequestEvent:
+		payload = map[string]interface{}{
+			"action":     "synchronize",
+			"number":     1,
+			"repository": repository,
+			"sender":     sender,
+			"pull_request": map[string]interface{}{
+				"number":   1,
+				"state":    "open",
+				"title":    "Simulated pull request sent by shp",
+				"html_url": htmlURL + "/pull/1",
+				"head":     map[string]interface{}{"ref": e.HeadBranch, "sha": e.SHA, "repo": repository},
+				"base":     map[string]interface{}{"ref": e.Branch, "repo": repository},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported GitHub event %q, expected either %s or %s", e.Name, GitHubPushEvent, GitHubPullRequestEvent)
+	}
+	return json.MarshalIndent(payload, "", "  ")
+}
+
+// Signature returns the "X-Hub-Signature-256" header value for the payload, the HMAC SHA-256 of
+// the payload keyed by the webhook secret token.
+func Signature(payload []byte, token string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewGitHubRequest creates the webhook request delivering the payload of the event to the
+// endpoint, signed with the token when informed.
+func NewGitHubRequest(ctx context.Context, endpoint string, event string, payload []byte, token string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := RandomSHA()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GitHub-Hookshot/shp")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", delivery[:32])
+	if token != "" {
+		req.Header.Set("X-Hub-Signature-256", Signature(payload, token))
+	}
+	return req, nil
+}
+
+// Deliver sends the request, returning the response status, failing when the endpoint doesn't
+// accept the delivery.
+func Deliver(client *http.Client, req *http.Request) (string, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.Status, fmt.Errorf("webhook delivery to %q failed with %s: %s",
+			req.URL.Redacted(), resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp.Status, nil
+}
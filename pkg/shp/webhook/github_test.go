@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestGitHubEventPayload(t *testing.T) {
+	g := o.NewWithT(t)
+
+	const sha = "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678"
+	event := &GitHubEvent{Name: GitHubPushEvent, Repository: "https://github.com/org/app.git", Branch: "main", SHA: sha}
+	data, err := event.Payload()
+	g.Expect(err).To(o.BeNil())
+	push := map[string]interface{}{}
+	g.Expect(json.Unmarshal(data, &push)).To(o.Succeed())
+	g.Expect(push["ref"]).To(o.Equal("refs/heads/main"))
+	g.Expect(push["after"]).To(o.Equal(sha))
+	g.Expect(push["repository"]).To(o.HaveKeyWithValue("full_name", "org/app"))
+	g.Expect(push["repository"]).To(o.HaveKeyWithValue("html_url", "https://github.com/org/app"))
+
+	event = &GitHubEvent{Name: GitHubPullRequestEvent, Repository: "https://github.com/org/app", Branch: "main", HeadBranch: "fix", SHA: sha}
+	data, err = event.Payload()
+	g.Expect(err).To(o.BeNil())
+	pr := struct {
+		PullRequest struct {
+			Head struct{ Ref, SHA string }
+			Base struct{ Ref string }
+		} `json:"pull_request"`
+	}{}
+	g.Expect(json.Unmarshal(data, &pr)).To(o.Succeed())
+	g.Expect(pr.PullRequest.Head.Ref).To(o.Equal("fix"))
+	g.Expect(pr.PullRequest.Head.SHA).To(o.Equal(sha))
+	g.Expect(pr.PullRequest.Base.Ref).To(o.Equal("main"))
+
+	_, err = (&GitHubEvent{Name: "issues", Repository: "https://github.com/org/app"}).Payload()
+	g.Expect(err).To(o.MatchError("unsupported GitHub event \"issues\", expected either push or pull_request"))
+	_, err = (&GitHubEvent{Name: GitHubPushEvent, Repository: "org/app"}).Payload()
+	g.Expect(err).To(o.MatchError("repository \"org/app\" is not an URL"))
+}
+
+func TestSignature(t *testing.T) {
+	g := o.NewWithT(t)
+
+	// example from the GitHub documentation on validating webhook deliveries
+	g.Expect(Signature([]byte("Hello, World!"), "It's a Secret to Everybody")).
+		To(o.Equal("sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"))
+}
+
+func TestDeliver(t *testing.T) {
+	g := o.NewWithT(t)
+
+	var headers http.Header
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("invalid signature\n"))
+	}))
+	defer server.Close()
+
+	req, err := NewGitHubRequest(context.TODO(), server.URL, GitHubPushEvent, []byte("{}"), "secret")
+	g.Expect(err).To(o.BeNil())
+	answer, err := Deliver(server.Client(), req)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(answer).To(o.Equal("202 Accepted"))
+	g.Expect(headers.Get("X-GitHub-Event")).To(o.Equal("push"))
+	g.Expect(headers.Get("X-GitHub-Delivery")).To(o.HaveLen(32))
+	g.Expect(headers.Get("X-Hub-Signature-256")).To(o.Equal(Signature([]byte("{}"), "secret")))
+
+	status = http.StatusForbidden
+	req, err = NewGitHubRequest(context.TODO(), server.URL, GitHubPushEvent, []byte("{}"), "")
+	g.Expect(err).To(o.BeNil())
+	_, err = Deliver(server.Client(), req)
+	g.Expect(err).To(o.MatchError(o.HaveSuffix("failed with 403 Forbidden: invalid signature")))
+	g.Expect(headers.Get("X-Hub-Signature-256")).To(o.BeEmpty())
+}